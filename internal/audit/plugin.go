@@ -0,0 +1,390 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	"github.com/itmrchow/course-management-system/internal/logctx"
+)
+
+const (
+	pluginName = "audit"
+	beforeKey  = "audit:before"
+)
+
+// ignoredColumns 不記錄變更的欄位
+var ignoredColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Plugin gorm plugin , 於 entity 新增、更新、刪除時寫入稽核紀錄
+// 稽核紀錄與變更在同一個 transaction 寫入 , 寫入失敗時變更也會 rollback
+// 操作者與 request ID 取自 ctx (logctx.WithUserID / logctx.WithRequestID) , 須使用 db.WithContext(ctx)
+// HTTP 請求由 server.UserIDMiddleware 從 X-User-ID header 設定操作者 , 背景工作沒有操作者
+//
+// 更新與刪除前會先以相同條件查詢變更前的資料 , 變更後再依 ID 查詢變更後的資料 , 比對後只記錄有變更的欄位
+//
+// Example:
+//
+//	err := db.Use(audit.NewPlugin(&entity.Teacher{}, &entity.Course{}))
+type Plugin struct {
+	entities []interface{}
+	tables   map[string]bool
+}
+
+var _ gorm.Plugin = (*Plugin)(nil)
+
+// NewPlugin 建立稽核 plugin
+// 參數: entities - 需要記錄稽核紀錄的 entity
+func NewPlugin(entities ...interface{}) *Plugin {
+	return &Plugin{entities: entities, tables: map[string]bool{}}
+}
+
+// Name 實作 gorm.Plugin
+func (p *Plugin) Name() string {
+	return pluginName
+}
+
+// Initialize 實作 gorm.Plugin , 註冊 create / update / delete callback
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	for _, entity := range p.entities {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(entity); err != nil {
+			return fmt.Errorf("failed to parse %T: %w", entity, err)
+		}
+		p.tables[stmt.Schema.Table] = true
+	}
+
+	// 需於 transaction commit 前執行 , 稽核紀錄與變更才會在同一個 transaction
+	const commit = "gorm:commit_or_rollback_transaction"
+
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Before(commit).Register("audit:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("audit:before_update", p.beforeChange); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Before(commit).Register("audit:after_update", p.afterChange); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("audit:before_delete", p.beforeChange); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", p.afterChange)
+}
+
+// audited 是否需要記錄此 statement
+func (p *Plugin) audited(db *gorm.DB) bool {
+	return db.Error == nil &&
+		!db.DryRun &&
+		db.Statement.Schema != nil &&
+		p.tables[db.Statement.Schema.Table]
+}
+
+// afterCreate 記錄新增的資料 , 支援單筆與批次新增
+func (p *Plugin) afterCreate(db *gorm.DB) {
+	if !p.audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	var logs []*auditEntity.AuditLog
+	for _, rv := range elements(stmt.ReflectValue) {
+		changes := auditEntity.Changes{}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || ignoredColumns[field.DBName] {
+				continue
+			}
+			value, zero := field.ValueOf(stmt.Context, rv)
+			if zero {
+				continue
+			}
+			changes[field.DBName] = auditEntity.Change{New: jsonValue(value)}
+		}
+
+		logs = append(logs, newLog(stmt.Context, stmt.Schema.Table, primaryKey(stmt, rv), auditEntity.OperationCreate, changes))
+	}
+
+	p.write(db, logs)
+}
+
+// beforeChange 以 statement 的條件查詢變更前的資料
+func (p *Plugin) beforeChange(db *gorm.DB) {
+	if !p.audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	query := p.query(db)
+
+	var conditions []clause.Expression
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conditions = append(conditions, where.Exprs...)
+	}
+	// Model(&entity).Updates(...) 的主鍵條件於 gorm:update 才加入 , 需自行加上
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		if id := primaryKey(stmt, stmt.ReflectValue); id != 0 {
+			conditions = append(conditions, clause.Eq{
+				Column: clause.Column{Table: clause.CurrentTable, Name: stmt.Schema.PrioritizedPrimaryField.DBName},
+				Value:  id,
+			})
+		}
+	}
+	// 沒有條件時 gorm 會拒絕執行 (ErrMissingWhereClause) , 不需查詢整個 table
+	if len(conditions) == 0 {
+		return
+	}
+	if stmt.Unscoped {
+		query = query.Unscoped()
+	}
+
+	before, err := snapshot(query.Clauses(clause.Where{Exprs: conditions}), stmt)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("audit: failed to load %s before change: %w", stmt.Schema.Table, err))
+		return
+	}
+	db.InstanceSet(beforeKey, before)
+}
+
+// afterChange 依 ID 查詢變更後的資料 , 與變更前比對後寫入稽核紀錄
+func (p *Plugin) afterChange(db *gorm.DB) {
+	if !p.audited(db) {
+		return
+	}
+
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return
+	}
+	before := value.(map[uint]map[string]interface{})
+	if len(before) == 0 {
+		return
+	}
+
+	stmt := db.Statement
+	ids := make([]uint, 0, len(before))
+	for id := range before {
+		ids = append(ids, id)
+	}
+
+	after, err := snapshot(p.query(db).Unscoped().Where(map[string]interface{}{stmt.Schema.PrioritizedPrimaryField.DBName: ids}), stmt)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("audit: failed to load %s after change: %w", stmt.Schema.Table, err))
+		return
+	}
+
+	var logs []*auditEntity.AuditLog
+	for _, id := range ids {
+		old := before[id]
+		current, exists := after[id]
+
+		// 永久刪除 , 記錄刪除前的所有欄位
+		if !exists {
+			changes := auditEntity.Changes{}
+			for column, v := range old {
+				if !ignoredColumns[column] && v != nil {
+					changes[column] = auditEntity.Change{Old: v}
+				}
+			}
+			logs = append(logs, newLog(stmt.Context, stmt.Schema.Table, id, auditEntity.OperationPurge, changes))
+			continue
+		}
+
+		changes := diff(old, current)
+		if len(changes) == 0 {
+			continue
+		}
+		logs = append(logs, newLog(stmt.Context, stmt.Schema.Table, id, operation(changes), changes))
+	}
+
+	p.write(db, logs)
+}
+
+// query 建立與 statement 相同連線 (transaction) 的新查詢
+func (p *Plugin) query(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true, SkipDefaultTransaction: true}).
+		Model(reflect.New(db.Statement.Schema.ModelType).Interface())
+}
+
+// write 寫入稽核紀錄 , 失敗時將錯誤加入 statement , 讓 transaction rollback
+func (p *Plugin) write(db *gorm.DB, logs []*auditEntity.AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true, SkipDefaultTransaction: true}).
+		Create(&logs).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("audit: failed to write audit log: %w", err))
+	}
+}
+
+// snapshot 查詢資料 , 回傳: 主鍵與欄位值
+func snapshot(query *gorm.DB, stmt *gorm.Statement) (map[uint]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	pk := stmt.Schema.PrioritizedPrimaryField.DBName
+	result := make(map[uint]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		id, err := toUint(row[pk])
+		if err != nil {
+			return nil, err
+		}
+		for column, v := range row {
+			row[column] = jsonValue(v)
+		}
+		result[id] = row
+	}
+	return result, nil
+}
+
+// diff 比對變更前後的欄位 , 回傳: 有變更的欄位
+func diff(old, current map[string]interface{}) auditEntity.Changes {
+	changes := auditEntity.Changes{}
+	for column, v := range current {
+		if ignoredColumns[column] {
+			continue
+		}
+		if !equal(old[column], v) {
+			changes[column] = auditEntity.Change{Old: old[column], New: v}
+		}
+	}
+	return changes
+}
+
+// operation 依變更的欄位判斷操作 , deleted_at 由 NULL 變為有值為軟刪除 , 反之為還原
+func operation(changes auditEntity.Changes) auditEntity.Operation {
+	if change, ok := changes["deleted_at"]; ok {
+		switch {
+		case change.Old == nil && change.New != nil:
+			return auditEntity.OperationDelete
+		case change.Old != nil && change.New == nil:
+			return auditEntity.OperationRestore
+		}
+	}
+	return auditEntity.OperationUpdate
+}
+
+func newLog(ctx context.Context, table string, id uint, op auditEntity.Operation, changes auditEntity.Changes) *auditEntity.AuditLog {
+	log := &auditEntity.AuditLog{
+		EntityType: table,
+		EntityID:   id,
+		Operation:  op,
+		Changes:    changes,
+		RequestID:  logctx.RequestID(ctx),
+	}
+	if actorID, ok := logctx.UserID(ctx); ok {
+		log.ActorID = &actorID
+	}
+	return log
+}
+
+// elements 回傳 struct 或 slice 中的所有 struct
+func elements(rv reflect.Value) []reflect.Value {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		result := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if elem := reflect.Indirect(rv.Index(i)); elem.Kind() == reflect.Struct {
+				result = append(result, elem)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// primaryKey 取得 struct 的主鍵 , 不存在時回傳 0
+func primaryKey(stmt *gorm.Statement, rv reflect.Value) uint {
+	field := stmt.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return 0
+	}
+	value, zero := field.ValueOf(stmt.Context, rv)
+	if zero {
+		return 0
+	}
+	id, _ := toUint(value)
+	return id
+}
+
+// jsonValue 將值轉為可寫入 JSON 並可比較的值 , time.Time 轉為 UTC
+func jsonValue(v interface{}) interface{} {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		v = value
+	}
+
+	switch value := v.(type) {
+	case time.Time:
+		return value.UTC()
+	case *time.Time:
+		if value == nil {
+			return nil
+		}
+		return value.UTC()
+	case []byte:
+		return string(value)
+	}
+	return v
+}
+
+// equal 比較兩個欄位值 , 數值不區分型別
+func equal(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA && okB {
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func toUint(v interface{}) (uint, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("unsupported primary key type %T", v)
+}
@@ -0,0 +1,200 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/logctx"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+type PluginTestSuite struct {
+	suite.Suite
+	db          *gorm.DB
+	teacherRepo teacherRepository.TeacherRepository
+	courseRepo  courseRepository.CourseRepository
+	ctx         context.Context
+}
+
+// SetupTest 於每個測試案例前執行 , 建立獨立的資料庫與一筆教師資料
+func (s *PluginTestSuite) SetupTest() {
+	s.db = testutil.NewDB(s.T())
+	s.teacherRepo = teacherRepository.NewTeacherRepository(s.db)
+	s.courseRepo = courseRepository.NewCourseRepository(s.db)
+	s.ctx = logctx.WithRequestID(logctx.WithUserID(context.Background(), 99), "req-1")
+
+	_, err := s.teacherRepo.Create(s.ctx, &teacherEntity.Teacher{
+		UserID: 1, Name: "John Doe", Phone: "0911000001", Email: "john@example.com", Status: teacherEntity.TeacherStatusPending,
+	})
+	s.Require().NoError(err)
+}
+
+// TestPluginSuite 執行測試套件
+func TestPluginSuite(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}
+
+func (s *PluginTestSuite) TestCreate() {
+	logs := s.logs("teacher", 1)
+	s.Require().Len(logs, 1)
+
+	log := logs[0]
+	s.Equal(auditEntity.OperationCreate, log.Operation)
+	s.Require().NotNil(log.ActorID)
+	s.EqualValues(99, *log.ActorID)
+	s.Equal("req-1", log.RequestID)
+	s.Equal("John Doe", log.Changes["name"].New)
+	s.Nil(log.Changes["name"].Old)
+	s.NotContains(log.Changes, "id")
+	s.NotContains(log.Changes, "created_at")
+	s.NotContains(log.Changes, "deleted_at")
+}
+
+func (s *PluginTestSuite) TestCreateWithoutActor() {
+	_, err := s.teacherRepo.Create(context.Background(), &teacherEntity.Teacher{
+		UserID: 2, Name: "Jane Doe", Phone: "0911000002", Email: "jane@example.com", Status: teacherEntity.TeacherStatusPending,
+	})
+	s.Require().NoError(err)
+
+	logs := s.logs("teacher", 2)
+	s.Require().Len(logs, 1)
+	s.Nil(logs[0].ActorID)
+	s.Empty(logs[0].RequestID)
+}
+
+func (s *PluginTestSuite) TestUpdate() {
+	rowsAffected, err := s.teacherRepo.Update(s.ctx, &teacherEntity.Teacher{Model: gorm.Model{ID: 1}, Name: "John Smith", Phone: "0911000001"})
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+
+	logs := s.logs("teacher", 1)
+	s.Require().Len(logs, 2)
+
+	// 只記錄有變更的欄位 , phone 未變更
+	log := logs[1]
+	s.Equal(auditEntity.OperationUpdate, log.Operation)
+	s.Equal(auditEntity.Changes{"name": {Old: "John Doe", New: "John Smith"}}, log.Changes)
+}
+
+func (s *PluginTestSuite) TestUpdateStatus() {
+	rowsAffected, err := s.teacherRepo.UpdateStatus(s.ctx, 1, teacherEntity.TeacherStatusPending, teacherEntity.TeacherStatusApproved)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+
+	// 狀態不符時沒有更新 , 不記錄
+	rowsAffected, err = s.teacherRepo.UpdateStatus(s.ctx, 1, teacherEntity.TeacherStatusPending, teacherEntity.TeacherStatusRejected)
+	s.Require().NoError(err)
+	s.Require().Zero(rowsAffected)
+
+	logs := s.logs("teacher", 1)
+	s.Require().Len(logs, 2)
+	// 從 jsonb 讀出的數值為 float64
+	s.Equal(auditEntity.Changes{"status": {Old: float64(teacherEntity.TeacherStatusPending), New: float64(teacherEntity.TeacherStatusApproved)}}, logs[1].Changes)
+}
+
+func (s *PluginTestSuite) TestFailedUpdate() {
+	_, err := s.teacherRepo.Create(s.ctx, &teacherEntity.Teacher{
+		UserID: 2, Name: "Jane Doe", Phone: "0911000002", Email: "jane@example.com", Status: teacherEntity.TeacherStatusPending,
+	})
+	s.Require().NoError(err)
+
+	_, err = s.teacherRepo.Update(s.ctx, &teacherEntity.Teacher{Model: gorm.Model{ID: 2}, Phone: "0911000001"})
+	s.Require().ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	s.Len(s.logs("teacher", 2), 1)
+}
+
+func (s *PluginTestSuite) TestRollback() {
+	err := s.db.WithContext(s.ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := teacherRepository.NewTeacherRepository(tx).Delete(s.ctx, 1); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	s.Require().Error(err)
+
+	// 變更 rollback 時稽核紀錄也一併 rollback
+	s.Len(s.logs("teacher", 1), 1)
+}
+
+func (s *PluginTestSuite) TestDeleteRestorePurge() {
+	_, err := s.teacherRepo.Delete(s.ctx, 1)
+	s.Require().NoError(err)
+	_, err = s.teacherRepo.Restore(s.ctx, 1)
+	s.Require().NoError(err)
+	_, err = s.teacherRepo.Delete(s.ctx, 1)
+	s.Require().NoError(err)
+	_, err = s.teacherRepo.Purge(s.ctx, time.Now().Add(time.Second))
+	s.Require().NoError(err)
+
+	logs := s.logs("teacher", 1)
+	s.Require().Len(logs, 5)
+
+	var operations []auditEntity.Operation
+	for _, log := range logs {
+		operations = append(operations, log.Operation)
+	}
+	s.Equal([]auditEntity.Operation{
+		auditEntity.OperationCreate,
+		auditEntity.OperationDelete,
+		auditEntity.OperationRestore,
+		auditEntity.OperationDelete,
+		auditEntity.OperationPurge,
+	}, operations)
+
+	s.Nil(logs[1].Changes["deleted_at"].Old)
+	s.NotNil(logs[1].Changes["deleted_at"].New)
+	s.Equal("John Doe", logs[4].Changes["name"].Old)
+	s.Nil(logs[4].Changes["name"].New)
+}
+
+func (s *PluginTestSuite) TestRelatedEntities() {
+	courseID, err := s.courseRepo.Create(s.ctx, &courseEntity.Course{
+		Name:                  "Go 入門",
+		Description:           "Go 入門 description",
+		Price:                 3000,
+		MaxStudents:           20,
+		MinStudents:           5,
+		RegistrationStartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		RegistrationEndDate:   time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+		StartDate:             time.Date(2025, 7, 22, 0, 0, 0, 0, time.UTC),
+		EndDate:               time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC),
+		Status:                courseEntity.CourseStatusDraft,
+	})
+	s.Require().NoError(err)
+
+	_, err = s.courseRepo.Update(s.ctx, &courseEntity.Course{Model: gorm.Model{ID: courseID}, Price: 3500})
+	s.Require().NoError(err)
+
+	logs := s.logs("course", courseID)
+	s.Require().Len(logs, 2)
+	s.Equal(auditEntity.Changes{"price": {Old: float64(3000), New: float64(3500)}}, logs[1].Changes)
+
+	// 關聯的 entity 也會記錄
+	s.Require().NoError(s.db.WithContext(s.ctx).Create(&courseEntity.CourseTeacher{CourseID: courseID, TeacherID: 1, IsMain: true}).Error)
+
+	var count int64
+	s.Require().NoError(s.db.Model(&auditEntity.AuditLog{}).Scopes(auditEntity.AuditLogOfEntity("course_teacher", 0)).Count(&count).Error)
+	s.EqualValues(1, count)
+}
+
+// logs 依時間順序取得 entity 的稽核紀錄
+func (s *PluginTestSuite) logs(entityType string, entityID uint) []*auditEntity.AuditLog {
+	var logs []*auditEntity.AuditLog
+	s.Require().NoError(s.db.
+		Scopes(auditEntity.AuditLogOfEntity(entityType, entityID)).
+		Order("id").
+		Find(&logs).
+		Error)
+	return logs
+}
@@ -0,0 +1,150 @@
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/pdf"
+)
+
+// ErrUnsupportedLocale 沒有對應版面的語系
+var ErrUnsupportedLocale = errors.New("unsupported certificate locale")
+
+// Document 套用版面前的證書內容
+type Document struct {
+	Issuer      string    // 發證單位名稱
+	Code        string    // 驗證碼
+	StudentID   uint      // 學生 user ID
+	CourseName  string    // 課程名稱
+	TeacherName string    // 主教師姓名 , 空字串表示不顯示
+	IssuedAt    time.Time // 發放時間
+	VerifyURL   string    // 驗證網址 , 空字串表示不顯示
+}
+
+// NewDocument 由證書建立證書內容
+// 參數: issuer - 發證單位名稱, certificate - 證書, verifyURL - 驗證網址
+// 回傳: 證書內容
+func NewDocument(issuer string, certificate *entity.Certificate, verifyURL string) *Document {
+	return &Document{
+		Issuer:      issuer,
+		Code:        certificate.Code,
+		StudentID:   certificate.StudentID,
+		CourseName:  certificate.CourseName,
+		TeacherName: certificate.TeacherName,
+		IssuedAt:    certificate.IssuedAt,
+		VerifyURL:   verifyURL,
+	}
+}
+
+// layout 各語系的版面文字與日期格式
+type layout struct {
+	DateLayout string
+	Title      string
+	Certify    string // 學生前的說明文字
+	Student    string
+	Completed  string // 課程名稱前的說明文字
+	Teacher    string
+	IssuedAt   string
+	Code       string
+	VerifyAt   string
+}
+
+// layouts 支援的語系版面
+var layouts = map[string]layout{
+	notificationEntity.LocaleZhTW: {
+		DateLayout: "2006/01/02",
+		Title:      "結業證書",
+		Certify:    "茲證明",
+		Student:    "學生編號",
+		Completed:  "已完成下列課程",
+		Teacher:    "授課教師",
+		IssuedAt:   "發證日期",
+		Code:       "驗證碼",
+		VerifyAt:   "驗證網址",
+	},
+	notificationEntity.LocaleEn: {
+		DateLayout: "Jan 2, 2006",
+		Title:      "CERTIFICATE OF COMPLETION",
+		Certify:    "This is to certify that",
+		Student:    "Student ID",
+		Completed:  "has successfully completed the course",
+		Teacher:    "Instructor",
+		IssuedAt:   "Date of issue",
+		Code:       "Certificate code",
+		VerifyAt:   "Verify at",
+	},
+}
+
+// Renderer 依語系版面將證書輸出為 PDF
+// PDF 為單頁橫向 A4 , 中文使用 PDF 閱讀器內建的 MSung-Light 字型 , 不嵌入字型檔
+//
+// Example:
+//
+//	r := certificate.NewRenderer(loc)
+//	b, err := r.Render(doc, "zh-TW")
+type Renderer struct {
+	loc *time.Location
+}
+
+// NewRenderer 建立證書輸出
+// 參數: loc - 日期顯示的時區
+// 回傳: renderer
+func NewRenderer(loc *time.Location) *Renderer {
+	return &Renderer{loc: loc}
+}
+
+// Render 以語系版面輸出證書 PDF
+// 版面由上而下置中 : 標題、學生、課程名稱、授課教師 , 下方左側為發證單位與日期 , 右側為驗證碼與驗證網址
+// 參數: doc - 證書內容, locale - 語系
+// 回傳: PDF 檔案內容, 錯誤訊息 , 不支援的語系回傳 ErrUnsupportedLocale
+func (r *Renderer) Render(doc *Document, locale string) ([]byte, error) {
+	l, ok := layouts[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+
+	p := pdf.NewPage(pdf.A4Height, pdf.A4Width)
+	width, height := p.Width(), p.Height()
+	center := width / 2
+	const margin = 60.0
+
+	// 外框
+	p.Rect(24, 24, width-48, height-48, 2.5)
+	p.Rect(32, 32, width-64, height-64, 0.8)
+
+	y := height - 130
+	p.TextCenter(center, y, 32, l.Title)
+	p.Line(center-120, y-18, center+120, y-18, 1)
+
+	y -= 70
+	p.TextCenter(center, y, 14, l.Certify)
+	y -= 38
+	p.TextCenter(center, y, 22, l.Student+" "+strconv.FormatUint(uint64(doc.StudentID), 10))
+	y -= 40
+	p.TextCenter(center, y, 14, l.Completed)
+	y -= 42
+	p.TextCenter(center, y, 26, doc.CourseName)
+	if doc.TeacherName != "" {
+		y -= 34
+		p.TextCenter(center, y, 14, l.Teacher+": "+doc.TeacherName)
+	}
+
+	// 發證單位與日期
+	bottom := 90.0
+	p.Line(margin+20, bottom+34, margin+240, bottom+34, 0.8)
+	p.Text(margin+20, bottom+16, 12, doc.Issuer)
+	p.Text(margin+20, bottom, 10, l.IssuedAt+": "+doc.IssuedAt.In(r.loc).Format(l.DateLayout))
+
+	// 驗證資訊
+	right := width - margin - 20
+	p.TextRight(right, bottom+16, 10, l.Code+": "+doc.Code)
+	if doc.VerifyURL != "" {
+		p.TextRight(right, bottom, 9, l.VerifyAt+": "+doc.VerifyURL)
+	}
+
+	return p.Bytes(l.Title + " " + doc.Code), nil
+}
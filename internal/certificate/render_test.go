@@ -0,0 +1,62 @@
+package certificate
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// testDocument 課程 Go 入門 , 主教師王小明 , 台北時間 2026/01/01 發放的證書
+func testDocument() *Document {
+	return NewDocument("課程管理系統", &entity.Certificate{
+		Code:        "7K3M-Q9TX-2HNB",
+		StudentID:   10,
+		CourseName:  "Go 入門",
+		TeacherName: "王小明",
+		IssuedAt:    time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC),
+	}, "https://example.com/certificates/verify?code=7K3M-Q9TX-2HNB")
+}
+
+func TestRenderer_Render(t *testing.T) {
+	r := NewRenderer(time.FixedZone("Asia/Taipei", 8*60*60))
+
+	tests := []struct {
+		locale   string
+		contains []string
+	}{
+		{
+			locale:   notificationEntity.LocaleZhTW,
+			contains: []string{"(: 7K3M-Q9TX-2HNB) Tj", "(: 2026/01/01) Tj", "(: https://example.com/certificates/verify?code=7K3M-Q9TX-2HNB) Tj"},
+		},
+		{
+			locale:   notificationEntity.LocaleEn,
+			contains: []string{"(CERTIFICATE OF COMPLETION) Tj", "(Certificate code: 7K3M-Q9TX-2HNB) Tj", "(Date of issue: Jan 1, 2026) Tj", "(Student ID 10) Tj"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			b, err := r.Render(testDocument(), tt.locale)
+			require.NoError(t, err)
+
+			assert.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4")))
+			assert.Contains(t, string(b), "/MediaBox [0 0 842 595]")
+			// 主教師姓名以中文字型的 UCS-2 編碼輸出
+			assert.Contains(t, string(b), "<738B5C0F660E>")
+			for _, s := range tt.contains {
+				assert.Contains(t, string(b), s)
+			}
+		})
+	}
+}
+
+func TestRenderer_Unsupported(t *testing.T) {
+	_, err := NewRenderer(time.UTC).Render(testDocument(), "ja")
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+}
@@ -5,19 +5,30 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
+	gormTracing "gorm.io/plugin/opentelemetry/tracing"
 
-	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
-	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/audit"
+	"github.com/itmrchow/course-management-system/internal/metrics"
 )
 
 // NewPostgresDB 初始化 postgres db.
 func NewPostgresDB(ctx context.Context, logger *zerolog.Logger) *gorm.DB {
+	db, err := OpenPostgresDB(ctx, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to init postgres db")
+	}
+
+	return db
+}
 
+// OpenPostgresDB 依 config 連線 postgres db , 連線失敗時回傳錯誤.
+func OpenPostgresDB(ctx context.Context, logger *zerolog.Logger) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC client_encoding=UTF8",
 		viper.GetString("POSTGRES_HOST"),
 		viper.GetString("POSTGRES_USER"),
@@ -27,39 +38,104 @@ func NewPostgresDB(ctx context.Context, logger *zerolog.Logger) *gorm.DB {
 		viper.GetString("POSTGRES_SSLMODE"),
 	)
 
-	db, err := NewDB(ctx, postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		logger.Fatal().Err(err).Ctx(ctx).Msg("failed to init postgres db")
-	}
-
-	return db
+	return NewDB(ctx, postgres.Open(dsn), &gorm.Config{
+		Logger: NewGormLogger(logger, viper.GetDuration("DB_SLOW_THRESHOLD")),
+	})
 }
 
-func NewMemoryDB(ctx context.Context, logger *zerolog.Logger) *gorm.DB {
-	viper.Set("POSTGRES_HOST", "localhost")
-	viper.Set("POSTGRES_USER", "postgres")
-	viper.Set("POSTGRES_PASSWORD", "postgres")
-	viper.Set("POSTGRES_DBNAME", "postgres")
-	viper.Set("POSTGRES_PORT", "5433")
-	viper.Set("POSTGRES_SSLMODE", "disable")
+// WaitForPostgresDB 連線 postgres db , 失敗時以指數退避重試
+// 重試間隔由 DB_WAIT_INITIAL_BACKOFF 開始加倍 , 最多 DB_WAIT_MAX_BACKOFF
+// 超過 DB_WAIT_TIMEOUT 或 ctx 取消時回傳最後一次的錯誤
+func WaitForPostgresDB(ctx context.Context, logger *zerolog.Logger) (*gorm.DB, error) {
+	viper.SetDefault("DB_WAIT_TIMEOUT", time.Minute)
+	viper.SetDefault("DB_WAIT_INITIAL_BACKOFF", 500*time.Millisecond)
+	viper.SetDefault("DB_WAIT_MAX_BACKOFF", 10*time.Second)
+
+	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("DB_WAIT_TIMEOUT"))
+	defer cancel()
+
+	return Retry(ctx, logger, viper.GetDuration("DB_WAIT_INITIAL_BACKOFF"), viper.GetDuration("DB_WAIT_MAX_BACKOFF"),
+		func() (*gorm.DB, error) {
+			db, err := OpenPostgresDB(ctx, logger)
+			if err != nil {
+				return nil, err
+			}
+			if err := PingDB(ctx, logger, db); err != nil {
+				_ = CloseDB(db)
+				return nil, err
+			}
+			return db, nil
+		})
+}
 
-	return NewPostgresDB(ctx, logger)
+// Retry 重複呼叫 fn 直到成功 , 失敗時以指數退避等待
+// 參數: initial - 第一次重試的等待時間, maxBackoff - 等待時間上限
+// 回傳: fn 成功的結果 , 或 ctx 結束時最後一次的錯誤
+func Retry[T any](ctx context.Context, logger *zerolog.Logger, initial, maxBackoff time.Duration, fn func() (T, error)) (T, error) {
+	backoff := initial
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+
+		logger.Warn().Ctx(ctx).Err(err).
+			Int("attempt", attempt).
+			Dur("backoff", backoff).
+			Msg("retrying")
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
 }
 
-// NewDB 初始化 db.
+// NewDB 初始化 db , 不執行 migrate , 需要時由呼叫端呼叫 Migrate.
+// 連線池指標以資料庫名稱註冊於 metrics.Registry , 由 CloseDB 移除.
 func NewDB(ctx context.Context, dialector gorm.Dialector, opts ...gorm.Option) (*gorm.DB, error) {
 
-	opts = append(opts, &gorm.Config{
-		NamingStrategy: schema.NamingStrategy{
-			SingularTable: true,
-		},
-	})
+	// gorm.Config 的 Apply 會整個覆蓋前一個 Config , 因此命名規則設定在呼叫端傳入的 Config 上
+	// TranslateError 讓 unique 衝突回傳 gorm.ErrDuplicatedKey , 與 in-memory repository 行為一致
+	namingStrategy := schema.NamingStrategy{
+		SingularTable: true,
+	}
+	hasConfig := false
+	for _, opt := range opts {
+		if c, ok := opt.(*gorm.Config); ok {
+			if c.NamingStrategy == nil {
+				c.NamingStrategy = namingStrategy
+			}
+			c.TranslateError = true
+			hasConfig = true
+		}
+	}
+	if !hasConfig {
+		opts = append(opts, &gorm.Config{NamingStrategy: namingStrategy, TranslateError: true})
+	}
 
 	db, err := gorm.Open(dialector, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// 每一筆 SQL 產生 span , 連線池指標由 metrics 套件提供
+	if err := db.Use(gormTracing.NewPlugin(gormTracing.WithoutMetrics())); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
+	// 新增、更新、刪除時於同一個 transaction 寫入稽核紀錄
+	if err := db.Use(audit.NewPlugin(AuditedEntities()...)); err != nil {
+		return nil, fmt.Errorf("failed to register audit plugin: %w", err)
+	}
+
 	// Get generic database object sql.DB to use its functions
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -72,30 +148,24 @@ func NewDB(ctx context.Context, dialector gorm.Dialector, opts ...gorm.Option) (
 	sqlDB.SetConnMaxLifetime(time.Minute * 30)
 	sqlDB.SetConnMaxIdleTime(15 * time.Minute)
 
-	// teacher entities
-	teacherEntities := []interface{}{
-		&teacherEntity.Teacher{},
-	}
-
-	// course entities
-	courseEntities := []interface{}{
-		&courseEntity.Course{},
+	if err := metrics.RegisterDBStats(sqlDB, dbStatsName(db.Dialector)); err != nil {
+		return nil, fmt.Errorf("failed to register db stats metrics: %w", err)
 	}
 
-	entities := append(teacherEntities, courseEntities...)
+	return db, nil
+}
 
-	// Auto migrate all entities
-	err = db.AutoMigrate(
-		entities...,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to auto migrate: %w", err)
+// dbStatsName 連線池指標的 db_name label , postgres 使用 DSN 中的資料庫名稱 , 其他使用 dialector 名稱
+func dbStatsName(dialector gorm.Dialector) string {
+	if d, ok := dialector.(*postgres.Dialector); ok && d.Config != nil {
+		if cfg, err := pgx.ParseConfig(d.DSN); err == nil && cfg.Database != "" {
+			return cfg.Database
+		}
 	}
-
-	return db, nil
+	return dialector.Name()
 }
 
-// PingDB 呼叫 db.Ping() , 於初始化後呼叫.
+// PingDB 呼叫 db.PingContext() , 於初始化後呼叫.
 func PingDB(ctx context.Context, logger *zerolog.Logger, db *gorm.DB) error {
 
 	sqlDB, err := db.DB()
@@ -104,7 +174,7 @@ func PingDB(ctx context.Context, logger *zerolog.Logger, db *gorm.DB) error {
 		return err
 	}
 	logger.Info().Ctx(ctx).Msg("db initialized")
-	err = sqlDB.Ping()
+	err = sqlDB.PingContext(ctx)
 	if err != nil {
 		logger.Err(err).Ctx(ctx).Msg("failed to ping db")
 		return err
@@ -113,3 +183,17 @@ func PingDB(ctx context.Context, logger *zerolog.Logger, db *gorm.DB) error {
 	logger.Info().Ctx(ctx).Msg("db pinged")
 	return nil
 }
+
+// CloseDB 關閉 db 連線池 , 於程式結束時呼叫.
+func CloseDB(db *gorm.DB) error {
+	metrics.UnregisterDBStats(dbStatsName(db.Dialector))
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql db: %w", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close sql db: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+var _ gormLogger.Interface = (*GormLogger)(nil)
+
+// GormLogger 將 gorm 的 log 導向 zerolog
+// 優先使用 ctx 中的 logger (zerolog.Ctx) , 讓 SQL log 帶有 request_id / user_id / trace_id 等欄位
+// ctx 中沒有 logger 時使用建立時傳入的 logger
+//
+// Example:
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{Logger: config.NewGormLogger(logger, 200*time.Millisecond)})
+type GormLogger struct {
+	logger                    *zerolog.Logger
+	level                     gormLogger.LogLevel
+	slowThreshold             time.Duration
+	ignoreRecordNotFoundError bool
+}
+
+// NewGormLogger 建立 gorm logger
+// 參數: logger - 預設 logger, slowThreshold - 慢查詢門檻 , 0 表示不記錄慢查詢
+// gorm log level 依 logger level 決定 , debug 以下會記錄每一筆 SQL
+func NewGormLogger(logger *zerolog.Logger, slowThreshold time.Duration) *GormLogger {
+	level := gormLogger.Warn
+	if logger.GetLevel() <= zerolog.DebugLevel {
+		level = gormLogger.Info
+	}
+
+	return &GormLogger{
+		logger:                    logger,
+		level:                     level,
+		slowThreshold:             slowThreshold,
+		ignoreRecordNotFoundError: true,
+	}
+}
+
+// LogMode 設定 gorm log level , 回傳新的 logger 不影響原本的設定
+func (l *GormLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	newLogger := *l
+	newLogger.level = level
+	return &newLogger
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Info {
+		l.ctxLogger(ctx).Info().Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		l.ctxLogger(ctx).Warn().Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Error {
+		l.ctxLogger(ctx).Error().Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace 記錄 SQL 執行結果
+// 執行錯誤記錄為 error , 超過慢查詢門檻記錄為 warn , 其餘於 Info 模式下記錄為 debug
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	logger := l.ctxLogger(ctx)
+
+	switch {
+	case err != nil && l.level >= gormLogger.Error && !(l.ignoreRecordNotFoundError && errors.Is(err, gorm.ErrRecordNotFound)):
+		sql, rows := fc()
+		logger.Error().Err(err).
+			Str("sql", sql).
+			Int64("rows", rows).
+			Dur("elapsed", elapsed).
+			Msg("sql error")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormLogger.Warn:
+		sql, rows := fc()
+		logger.Warn().
+			Str("sql", sql).
+			Int64("rows", rows).
+			Dur("elapsed", elapsed).
+			Dur("threshold", l.slowThreshold).
+			Msg("slow sql")
+	case l.level >= gormLogger.Info:
+		sql, rows := fc()
+		logger.Debug().
+			Str("sql", sql).
+			Int64("rows", rows).
+			Dur("elapsed", elapsed).
+			Msg("sql")
+	}
+}
+
+// ctxLogger 取得 ctx 中的 logger , 不存在時使用預設 logger
+func (l *GormLogger) ctxLogger(ctx context.Context) *zerolog.Logger {
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		return logger
+	}
+	return l.logger
+}
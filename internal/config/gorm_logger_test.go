@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+)
+
+// gorm logger Trace 測試
+// Test for GormLogger.Trace
+func TestGormLoggerTrace(t *testing.T) {
+	sqlFunc := func() (string, int64) { return "SELECT 1", 1 }
+
+	tests := []struct {
+		name      string
+		level     zerolog.Level
+		begin     time.Time
+		err       error
+		wantLevel string
+		wantMsg   string
+	}{
+		{
+			name:      "error",
+			level:     zerolog.InfoLevel,
+			begin:     time.Now(),
+			err:       errors.New("boom"),
+			wantLevel: "error",
+			wantMsg:   "sql error",
+		},
+		{
+			name:  "record not found ignored",
+			level: zerolog.InfoLevel,
+			begin: time.Now(),
+			err:   gorm.ErrRecordNotFound,
+		},
+		{
+			name:      "slow sql",
+			level:     zerolog.InfoLevel,
+			begin:     time.Now().Add(-time.Second),
+			wantLevel: "warn",
+			wantMsg:   "slow sql",
+		},
+		{
+			name:  "normal sql not logged at info",
+			level: zerolog.InfoLevel,
+			begin: time.Now(),
+		},
+		{
+			name:      "normal sql logged at debug",
+			level:     zerolog.DebugLevel,
+			begin:     time.Now(),
+			wantLevel: "debug",
+			wantMsg:   "sql",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf).Level(test.level)
+			l := NewGormLogger(&logger, 100*time.Millisecond)
+
+			l.Trace(context.Background(), test.begin, sqlFunc, test.err)
+
+			if test.wantLevel == "" {
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			var got map[string]interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+			assert.Equal(t, test.wantLevel, got["level"])
+			assert.Equal(t, test.wantMsg, got["message"])
+			assert.Equal(t, "SELECT 1", got["sql"])
+		})
+	}
+}
+
+// gorm logger 使用 ctx 中的 logger 測試
+func TestGormLoggerUsesCtxLogger(t *testing.T) {
+	var defaultBuf, ctxBuf bytes.Buffer
+	defaultLogger := zerolog.New(&defaultBuf)
+	ctxLogger := zerolog.New(&ctxBuf)
+
+	l := NewGormLogger(&defaultLogger, 0).LogMode(gormLogger.Info)
+
+	ctx := logctx.WithLogger(context.Background(), &ctxLogger)
+	ctx = logctx.WithRequestID(ctx, "req-1")
+	l.Error(ctx, "failed: %s", "reason")
+
+	assert.Empty(t, defaultBuf.String())
+	assert.True(t, strings.Contains(ctxBuf.String(), `"request_id":"req-1"`))
+	assert.True(t, strings.Contains(ctxBuf.String(), "failed: reason"))
+}
+
+// logger 建立測試
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := NewLogger(&buf, "warn", "json")
+	assert.Equal(t, zerolog.WarnLevel, logger.GetLevel())
+
+	logger = NewLogger(&buf, "unknown", "json")
+	assert.Equal(t, zerolog.InfoLevel, logger.GetLevel())
+
+	buf.Reset()
+	logger = NewLogger(&buf, "info", "console")
+	logger.Info().Msg("hello")
+	assert.False(t, json.Valid(buf.Bytes()))
+	assert.Contains(t, buf.String(), "hello")
+}
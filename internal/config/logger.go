@@ -1,16 +1,47 @@
 package config
 
 import (
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 )
 
+// 支援的 log 輸出格式
+const (
+	LogFormatJSON    = "json"    // JSON 格式 , 供 log 收集系統使用
+	LogFormatConsole = "console" // 人類可讀格式 , 供本機開發使用
+)
+
+// InitLogger 初始化 logger.
+// 依 config 的 LOG_LEVEL 與 LOG_FORMAT 設定 level 與輸出格式 , 輸出至 stdout
+// 並設定為 zerolog 的預設 context logger , 讓未帶 logger 的 ctx 也能使用 zerolog.Ctx 取得
 func InitLogger() *zerolog.Logger {
-	// TODO: setting log
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logger := NewLogger(os.Stdout, viper.GetString("LOG_LEVEL"), viper.GetString("LOG_FORMAT"))
+	zerolog.DefaultContextLogger = logger
+
+	return logger
+}
+
+// NewLogger 建立 logger
+// 參數: w - 輸出目標, level - log level (trace, debug, info, warn, error), format - 輸出格式 (json, console)
+// level 無法解析時使用 info , format 無法辨識時使用 json
+func NewLogger(w io.Writer, level, format string) *zerolog.Logger {
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+
+	lvl, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(level)))
+	if err != nil || lvl == zerolog.NoLevel {
+		lvl = zerolog.InfoLevel
+	}
+
+	if strings.EqualFold(strings.TrimSpace(format), LogFormatConsole) {
+		w = zerolog.ConsoleWriter{Out: w, TimeFormat: time.RFC3339}
+	}
 
-	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger := zerolog.New(w).Level(lvl).With().Timestamp().Logger()
 
 	return &logger
 }
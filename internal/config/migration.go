@@ -0,0 +1,284 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+
+	attendanceEntity "github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	certificateEntity "github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	invoiceEntity "github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	locationEntity "github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	outboxEntity "github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	reviewEntity "github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	webhookEntity "github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+)
+
+// Entities 回傳需要 migrate 的所有 entity
+// 新增 entity 時加入對應 domain 的清單
+func Entities() []interface{} {
+	// teacher entities
+	teacherEntities := []interface{}{
+		&teacherEntity.Teacher{},
+	}
+
+	// course entities
+	courseEntities := []interface{}{
+		&courseEntity.Course{},
+		&courseEntity.CoursePattern{},
+		&courseEntity.CourseTeacher{},
+		&courseEntity.CourseSession{},
+		&courseEntity.CoursePrice{},
+		&courseEntity.CourseRefundRule{},
+	}
+
+	// location entities
+	locationEntities := []interface{}{
+		&locationEntity.Location{},
+		&locationEntity.Room{},
+	}
+
+	// enrollment entities
+	enrollmentEntities := []interface{}{
+		&enrollmentEntity.Enrollment{},
+	}
+
+	// attendance entities
+	attendanceEntities := []interface{}{
+		&attendanceEntity.Attendance{},
+	}
+
+	// certificate entities
+	certificateEntities := []interface{}{
+		&certificateEntity.Certificate{},
+	}
+
+	// review entities
+	reviewEntities := []interface{}{
+		&reviewEntity.Review{},
+		&reviewEntity.ReviewTeacherRating{},
+	}
+
+	// order entities
+	orderEntities := []interface{}{
+		&orderEntity.Order{},
+		&orderEntity.PaymentCallback{},
+		&orderEntity.Refund{},
+	}
+
+	// invoice entities
+	invoiceEntities := []interface{}{
+		&invoiceEntity.Invoice{},
+		&invoiceEntity.InvoiceLine{},
+		&invoiceEntity.InvoiceSequence{},
+	}
+
+	// promotion entities
+	promotionEntities := []interface{}{
+		&promotionEntity.Promotion{},
+		&promotionEntity.PromotionRedemption{},
+	}
+
+	// audit entities
+	auditEntities := []interface{}{
+		&auditEntity.AuditLog{},
+	}
+
+	// outbox entities
+	outboxEntities := []interface{}{
+		&outboxEntity.OutboxEvent{},
+	}
+
+	// webhook entities
+	webhookEntities := []interface{}{
+		&webhookEntity.WebhookSubscription{},
+		&webhookEntity.WebhookDelivery{},
+	}
+
+	// notification entities
+	notificationEntities := []interface{}{
+		&notificationEntity.Notification{},
+		&notificationEntity.NotificationPreference{},
+	}
+
+	entities := append(teacherEntities, courseEntities...)
+	entities = append(entities, locationEntities...)
+	entities = append(entities, enrollmentEntities...)
+	entities = append(entities, attendanceEntities...)
+	entities = append(entities, certificateEntities...)
+	entities = append(entities, reviewEntities...)
+	entities = append(entities, orderEntities...)
+	entities = append(entities, invoiceEntities...)
+	entities = append(entities, promotionEntities...)
+	entities = append(entities, auditEntities...)
+	entities = append(entities, outboxEntities...)
+	entities = append(entities, webhookEntities...)
+	return append(entities, notificationEntities...)
+}
+
+// AuditedEntities 回傳需要記錄稽核紀錄的 entity
+func AuditedEntities() []interface{} {
+	return []interface{}{
+		&teacherEntity.Teacher{},
+		&courseEntity.Course{},
+		&courseEntity.CoursePattern{},
+		&courseEntity.CourseTeacher{},
+		&locationEntity.Location{},
+		&locationEntity.Room{},
+		&enrollmentEntity.Enrollment{},
+		&certificateEntity.Certificate{},
+		&reviewEntity.Review{},
+		&orderEntity.Order{},
+		&orderEntity.Refund{},
+		&invoiceEntity.Invoice{},
+		&promotionEntity.Promotion{},
+	}
+}
+
+// legacyIndexes 已被取代的 index , migrate 時若存在則刪除
+// unique index 改為只限制未刪除的資料 (where deleted_at IS NULL) , 並使用新名稱建立
+var legacyIndexes = []struct {
+	entity interface{}
+	name   string
+}{
+	{&teacherEntity.Teacher{}, "idx_teacher_user_id"},
+	{&teacherEntity.Teacher{}, "idx_teacher_phone"},
+	{&teacherEntity.Teacher{}, "idx_teacher_email"},
+	{&courseEntity.CourseTeacher{}, "idx_course_teacher"},
+}
+
+// searchIndexes 關鍵字搜尋使用的 index , AutoMigrate 無法建立 expression / operator class index , 以 SQL 建立
+// tsvector index 的運算式修改時需更換 index 名稱 , 並將舊名稱加入 legacyIndexes
+var searchIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_teacher_search ON teacher USING gin ((" + teacherEntity.TeacherSearchVector + "))",
+	"CREATE INDEX IF NOT EXISTS idx_teacher_name_trgm ON teacher USING gin (name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_teacher_bio_trgm ON teacher USING gin (bio gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_course_search ON course USING gin ((" + courseEntity.CourseSearchVector + "))",
+	"CREATE INDEX IF NOT EXISTS idx_course_name_trgm ON course USING gin (name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_course_description_trgm ON course USING gin (description gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_course_note_trgm ON course USING gin (note gin_trgm_ops)",
+}
+
+// DataMigration 已執行的資料 migration , 每個資料 migration 只執行一次
+type DataMigration struct {
+	Name      string    `gorm:"type:varchar(100);primaryKey"` // 資料 migration 名稱
+	AppliedAt time.Time `gorm:"not null"`                     // 執行時間
+}
+
+// dataMigrations 依序執行的資料 migration , 已執行的名稱記錄於 DataMigration , 不可修改已發布的項目
+var dataMigrations = []struct {
+	name string
+	stmt string
+}{
+	// 課程價格由整數元改為以幣別的最小單位計 , TWD 的最小單位為 0.01 元
+	{"course_price_minor_units", "UPDATE course SET price = price * 100"},
+	// 訂單累計已退款金額 , 部分退款的訂單改回已付款
+	{"order_refunded_amount", `UPDATE "order" o
+		SET refunded_amount = r.total,
+			status = CASE WHEN o.status = 3 AND r.total < o.amount THEN 1 ELSE o.status END
+		FROM (SELECT order_id, SUM(amount) AS total FROM refund WHERE status = 1 AND deleted_at IS NULL GROUP BY order_id) r
+		WHERE r.order_id = o.id`},
+}
+
+// Migrate 依 Entities 執行 auto migrate , 建立搜尋使用的 index , 刪除已被取代的 index , 並執行尚未執行的資料 migration
+// 需要建立 pg_trgm extension 的權限
+func Migrate(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return fmt.Errorf("failed to create extension pg_trgm: %w", err)
+	}
+
+	if err := db.AutoMigrate(Entities()...); err != nil {
+		return fmt.Errorf("failed to auto migrate: %w", err)
+	}
+
+	for _, stmt := range searchIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	migrator := db.Migrator()
+	for _, idx := range legacyIndexes {
+		if !migrator.HasIndex(idx.entity, idx.name) {
+			continue
+		}
+		if err := migrator.DropIndex(idx.entity, idx.name); err != nil {
+			return fmt.Errorf("failed to drop index %s: %w", idx.name, err)
+		}
+	}
+
+	return migrateData(db)
+}
+
+// migrateData 依序執行尚未執行的資料 migration , 每個資料 migration 與其執行紀錄在同一個 transaction 中寫入
+func migrateData(db *gorm.DB) error {
+	if err := db.AutoMigrate(&DataMigration{}); err != nil {
+		return fmt.Errorf("failed to auto migrate data migration: %w", err)
+	}
+
+	for _, m := range dataMigrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// 鎖定執行紀錄 , 多個 instance 同時 migrate 時只執行一次
+			if err := tx.Exec("LOCK TABLE data_migration IN EXCLUSIVE MODE").Error; err != nil {
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&DataMigration{}).Where("name = ?", m.name).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(m.stmt).Error; err != nil {
+				return err
+			}
+			return tx.Create(&DataMigration{Name: m.name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run data migration %s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// PendingMigrations 檢查資料庫是否缺少 entity 對應的 table 或 column
+// 回傳: 缺少的項目 , 例如 "course" 或 "course.note" , 空切片表示 schema 已是最新
+func PendingMigrations(db *gorm.DB) ([]string, error) {
+	var pending []string
+	migrator := db.Migrator()
+
+	for _, entity := range Entities() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(entity); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", reflect.TypeOf(entity).Elem().Name(), err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(entity) {
+			pending = append(pending, table)
+			continue
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			if !migrator.HasColumn(entity, field.DBName) {
+				pending = append(pending, table+"."+field.DBName)
+			}
+		}
+	}
+
+	return pending, nil
+}
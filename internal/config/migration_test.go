@@ -0,0 +1,35 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/config"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Main(m))
+}
+
+// 資料 migration 只執行一次測試
+// 模擬尚未執行資料 migration 的資料庫 , 課程價格以整數元儲存 , migrate 後改為以最小單位計 , 再次 migrate 不會重複換算
+func TestMigrateDataOnce(t *testing.T) {
+	db := testutil.NewDB(t)
+
+	course := &courseEntity.Course{Name: "資料庫設計", Description: "desc", Price: 3000, Currency: "TWD"}
+	require.NoError(t, db.Create(course).Error)
+	require.NoError(t, db.Where("name = ?", "course_price_minor_units").Delete(&config.DataMigration{}).Error)
+
+	for range 2 {
+		require.NoError(t, config.Migrate(db))
+
+		var got courseEntity.Course
+		require.NoError(t, db.First(&got, course.ID).Error)
+		assert.EqualValues(t, 300000, got.Price)
+	}
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attendance 學生於單次上課的出席紀錄 , 每位學生在每次上課最多一筆
+// 由課程的授課教師點名 , 重新點名時更新原本的紀錄 , 上課結束超過設定的期間後鎖定 , 不可再修改
+type Attendance struct {
+	gorm.Model
+	SessionID    uint             `gorm:"not null;uniqueIndex:idx_attendance_session_student,priority:1"`                                                // 上課ID
+	StudentID    uint             `gorm:"not null;uniqueIndex:idx_attendance_session_student,priority:2;index:idx_attendance_course_student,priority:2"` // 學生 user ID
+	CourseID     uint             `gorm:"not null;index:idx_attendance_course_student,priority:1"`                                                       // 課程ID
+	EnrollmentID uint             `gorm:"not null"`                                                                                                      // 報名ID
+	Status       AttendanceStatus `gorm:"not null;default:0"`                                                                                            // 0: 出席 , 1: 缺席 , 2: 遲到 , 3: 請假
+	Note         string           `gorm:"type:varchar(255);not null;default:''"`                                                                         // 備註
+	MarkedBy     uint             `gorm:"not null"`                                                                                                      // 點名的教師ID
+	MarkedAt     time.Time        `gorm:"not null"`                                                                                                      // 點名時間
+}
+
+type AttendanceStatus uint
+
+const (
+	AttendanceStatusPresent AttendanceStatus = iota // 出席
+	AttendanceStatusAbsent                          // 缺席
+	AttendanceStatusLate                            // 遲到
+	AttendanceStatusExcused                         // 請假
+)
+
+func (s AttendanceStatus) String() string {
+	switch s {
+	case AttendanceStatusPresent:
+		return "present"
+	case AttendanceStatusAbsent:
+		return "absent"
+	case AttendanceStatusLate:
+		return "late"
+	case AttendanceStatusExcused:
+		return "excused"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAttendanceStatus 解析出席狀態名稱
+// 參數: s - 狀態名稱 , present / absent / late / excused
+// 回傳: 出席狀態, 是否為有效的狀態名稱
+func ParseAttendanceStatus(s string) (AttendanceStatus, bool) {
+	for _, status := range []AttendanceStatus{AttendanceStatusPresent, AttendanceStatusAbsent, AttendanceStatusLate, AttendanceStatusExcused} {
+		if status.String() == s {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// AttendanceCount 依學生與出席狀態統計的紀錄數量
+type AttendanceCount struct {
+	StudentID uint
+	Status    AttendanceStatus
+	Count     int64
+}
+
+// AttendanceRate 出席率統計
+// 出席與遲到視為有出席 , 請假不計入分母 , 出席率 = (出席 + 遲到) / (出席 + 遲到 + 缺席)
+type AttendanceRate struct {
+	StudentID uint // 學生 user ID , 課程整體的統計為 0
+	Present   int64
+	Late      int64
+	Absent    int64
+	Excused   int64
+}
+
+// Add 累計出席狀態的紀錄數量
+func (r *AttendanceRate) Add(status AttendanceStatus, count int64) {
+	switch status {
+	case AttendanceStatusPresent:
+		r.Present += count
+	case AttendanceStatusLate:
+		r.Late += count
+	case AttendanceStatusAbsent:
+		r.Absent += count
+	case AttendanceStatusExcused:
+		r.Excused += count
+	}
+}
+
+// AddMissing 將沒有出席紀錄的上課計為缺席
+// 參數: sessions - 應出席的上課數量 , 即已結束且未取消的上課
+func (r *AttendanceRate) AddMissing(sessions int64) {
+	if missing := sessions - r.Total(); missing > 0 {
+		r.Absent += missing
+	}
+}
+
+// Total 所有出席紀錄的數量 , 包含請假
+func (r *AttendanceRate) Total() int64 {
+	return r.Present + r.Late + r.Absent + r.Excused
+}
+
+// Rate 出席率 , 介於 0 ~ 1 , 沒有需要出席的紀錄時為 0
+func (r *AttendanceRate) Rate() float64 {
+	required := r.Present + r.Late + r.Absent
+	if required == 0 {
+		return 0
+	}
+	return float64(r.Present+r.Late) / float64(required)
+}
+
+// AttendanceOfSession 查詢上課的出席紀錄
+func AttendanceOfSession(sessionID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("session_id = ?", sessionID)
+	}
+}
+
+// AttendanceOfCourse 查詢課程的出席紀錄
+func AttendanceOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
+
+// AttendanceOfStudent 查詢學生的出席紀錄
+func AttendanceOfStudent(studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("student_id = ?", studentID)
+	}
+}
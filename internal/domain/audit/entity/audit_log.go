@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog 稽核紀錄 , 記錄 entity 的新增、更新與刪除
+// 只會新增 , 不會更新或刪除 , 因此不使用 gorm.Model
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey"`
+	ActorID    *uint     `gorm:"index"`                                                // 操作者 user ID , nil 表示系統操作 (例如背景工作)
+	EntityType string    `gorm:"type:varchar(50);not null;index:idx_audit_log_entity"` // entity 的 table 名稱 , 例如 teacher , course
+	EntityID   uint      `gorm:"not null;index:idx_audit_log_entity"`                  // entity ID
+	Operation  Operation `gorm:"type:varchar(20);not null"`                            // 操作
+	Changes    Changes   `gorm:"type:jsonb;not null"`                                  // 變更的欄位
+	RequestID  string    `gorm:"type:varchar(64)"`                                     // 觸發變更的 request ID
+	CreatedAt  time.Time `gorm:"not null;index"`                                       // 變更時間
+}
+
+// Operation 稽核紀錄的操作
+type Operation string
+
+const (
+	OperationCreate  Operation = "create"  // 新增
+	OperationUpdate  Operation = "update"  // 更新
+	OperationDelete  Operation = "delete"  // 軟刪除
+	OperationRestore Operation = "restore" // 還原軟刪除
+	OperationPurge   Operation = "purge"   // 永久刪除
+)
+
+// Change 單一欄位的變更 , 新增時 Old 為 nil , 永久刪除時 New 為 nil
+type Change struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Changes 欄位名稱 (資料庫欄位) 與變更 , 以 JSON 儲存
+type Changes map[string]Change
+
+// Value 實作 driver.Valuer
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 實作 sql.Scanner
+func (c *Changes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported changes type %T", value)
+	}
+	return json.Unmarshal(b, c)
+}
+
+// AuditLogOfEntity 查詢 entity 的稽核紀錄 , entityID 為 0 時查詢該類型的所有 entity
+func AuditLogOfEntity(entityType string, entityID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("entity_type = ?", entityType)
+		if entityID != 0 {
+			db = db.Where("entity_id = ?", entityID)
+		}
+		return db
+	}
+}
+
+// AuditLogByActor 查詢操作者的稽核紀錄
+func AuditLogByActor(actorID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("actor_id = ?", actorID)
+	}
+}
+
+// AuditLogBetween 查詢時間區間 [from, to) 的稽核紀錄 , 零值表示不限制
+func AuditLogBetween(from, to time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !from.IsZero() {
+			db = db.Where("created_at >= ?", from.UTC())
+		}
+		if !to.IsZero() {
+			db = db.Where("created_at < ?", to.UTC())
+		}
+		return db
+	}
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Certificate 課程結業證書 , 課程結束時出席率達門檻的學生各一張 , 由 CertificateService 發放
+// 課程名稱與主教師姓名為發放時的快照 , 之後課程異動不影響已發放的證書
+// 證書以 Code 公開驗證 , 撤銷後仍保留紀錄 , 驗證時顯示為已撤銷
+type Certificate struct {
+	gorm.Model
+	Code           string            `gorm:"type:varchar(20);not null;uniqueIndex:idx_certificate_code"`                              // 驗證碼 , 格式為 XXXX-XXXX-XXXX
+	CourseID       uint              `gorm:"not null;uniqueIndex:idx_certificate_course_student,where:deleted_at IS NULL,priority:1"` // 課程ID
+	StudentID      uint              `gorm:"not null;uniqueIndex:idx_certificate_course_student,where:deleted_at IS NULL,priority:2"` // 學生 user ID
+	EnrollmentID   uint              `gorm:"not null"`                                                                                // 報名ID
+	CourseName     string            `gorm:"type:varchar(100);not null"`                                                              // 課程名稱
+	TeacherName    string            `gorm:"type:varchar(100);not null;default:''"`                                                   // 主教師姓名 , 課程沒有主教師時為空字串
+	AttendanceRate float64           `gorm:"not null;default:0"`                                                                      // 發放時的出席率 , 0 至 1
+	Status         CertificateStatus `gorm:"not null;default:0"`                                                                      // 0: 有效 , 1: 已撤銷
+	IssuedAt       time.Time         `gorm:"not null"`                                                                                // 發放時間
+	RevokedAt      *time.Time        // 撤銷時間
+	RevokeReason   string            `gorm:"type:varchar(255);not null;default:''"` // 撤銷原因
+}
+
+type CertificateStatus uint
+
+const (
+	CertificateStatusValid   CertificateStatus = iota // 有效
+	CertificateStatusRevoked                          // 已撤銷
+)
+
+func (s CertificateStatus) String() string {
+	switch s {
+	case CertificateStatusValid:
+		return "valid"
+	case CertificateStatusRevoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
+}
+
+// codeAlphabet 驗證碼使用的字元 , 為 Crockford Base32 , 不含容易混淆的 I、L、O、U
+const codeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// codeLength 驗證碼的字元數 , 不含分隔的連字號 , 共 60 bits
+const codeLength = 12
+
+// NewCode 產生隨機的驗證碼
+// 回傳: 驗證碼 , 格式為 XXXX-XXXX-XXXX, 錯誤訊息
+func NewCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate certificate code: %w", err)
+	}
+	code := make([]byte, codeLength)
+	for i, v := range b {
+		// 32 整除 256 , 取餘數不會造成分布不均
+		code[i] = codeAlphabet[int(v)%len(codeAlphabet)]
+	}
+	return formatCode(string(code)), nil
+}
+
+// NormalizeCode 將使用者輸入的驗證碼轉為標準格式
+// 忽略大小寫、空白與連字號 , 並將容易混淆的 I、L 視為 1 , O 視為 0
+// 參數: s - 使用者輸入的驗證碼
+// 回傳: 標準格式的驗證碼, 是否為合法的驗證碼
+func NormalizeCode(s string) (string, bool) {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'I' || r == 'L':
+			r = '1'
+		case r == 'O':
+			r = '0'
+		case !strings.ContainsRune(codeAlphabet, r):
+			return "", false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() != codeLength {
+		return "", false
+	}
+	return formatCode(b.String()), true
+}
+
+// formatCode 每 4 個字元以連字號分隔
+func formatCode(code string) string {
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12]
+}
+
+// CertificateOfCourse 查詢課程的證書
+func CertificateOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
+
+// CertificateOfStudent 查詢學生的證書
+func CertificateOfStudent(studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("student_id = ?", studentID)
+	}
+}
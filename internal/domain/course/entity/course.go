@@ -4,31 +4,152 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/money"
 )
 
 // Course 課程
 type Course struct {
 	gorm.Model
-	Name                  string       `gorm:"type:varchar(100);not null"` // 課程名稱
-	Description           string       `gorm:"type:text;not null"`         // 課程描述
-	Price                 uint         `gorm:"not null;default:0"`         // 課程價格
-	MaxStudents           uint         `gorm:"not null;default:0"`         // 最大學生人數
-	MinStudents           uint         `gorm:"not null;default:0"`         // 最小學生人數
-	RegistrationStartDate time.Time    `gorm:"not null"`                   // 報名開始時間
-	RegistrationEndDate   time.Time    `gorm:"not null"`                   // 報名結束時間
-	StartDate             time.Time    `gorm:"not null"`                   // 上課開始時間
-	EndDate               time.Time    `gorm:"not null"`                   // 上課結束時間
-	IsOnline              bool         `gorm:"not null;default:false"`     // 是否是線上課程
-	Status                CourseStatus `gorm:"not null;default:0"`         // 0: 草稿, 1: 審核中, 2: 開放報名, 3: 已結束 , 4: 暫停報名
-	Note                  string       `gorm:"type:text"`                  // 課程備註
+	Name                  string       `gorm:"type:varchar(100);not null"`             // 課程名稱
+	Description           string       `gorm:"type:text;not null"`                     // 課程描述
+	Category              string       `gorm:"type:varchar(50);not null;default:''"`   // 課程分類 , 促銷活動可限定分類
+	Price                 uint         `gorm:"not null;default:0"`                     // 課程基本價格 , 以 Currency 的最小單位計 , 未設定價格紀錄 (CoursePrice) 時使用
+	Currency              string       `gorm:"type:varchar(3);not null;default:'TWD'"` // 課程幣別 , ISO 4217 , 價格紀錄須使用相同幣別
+	MaxStudents           uint         `gorm:"not null;default:0"`                     // 最大學生人數
+	MinStudents           uint         `gorm:"not null;default:0"`                     // 最小學生人數
+	RegistrationStartDate time.Time    `gorm:"not null"`                               // 報名開始時間
+	RegistrationEndDate   time.Time    `gorm:"not null"`                               // 報名結束時間
+	StartDate             time.Time    `gorm:"not null"`                               // 上課開始時間
+	EndDate               time.Time    `gorm:"not null"`                               // 上課結束時間
+	IsOnline              bool         `gorm:"not null;default:false"`                 // 是否是線上課程
+	Status                CourseStatus `gorm:"not null;default:0"`                     // 0: 草稿, 1: 審核中, 2: 開放報名, 3: 已結束 , 4: 暫停報名 , 5: 已取消
+	Note                  string       `gorm:"type:text"`                              // 課程備註
+	ReminderHours         uint         `gorm:"not null;default:0"`                     // 上課前幾小時發送提醒 , 0 表示使用系統預設
+	RatingSum             uint         `gorm:"not null;default:0"`                     // 審核通過的評價評分總和 , 由 ReviewService 維護
+	RatingCount           uint         `gorm:"not null;default:0"`                     // 審核通過的評價數量 , 由 ReviewService 維護
+	RatingAverage         float64      `gorm:"not null;default:0;index"`               // 平均評分 , 沒有評價時為 0 , 由 ReviewService 維護
+}
+
+// BasePrice 課程基本價格
+func (c *Course) BasePrice() money.Money {
+	return money.Money{Amount: int64(c.Price), Currency: c.Currency}
 }
 
+// CourseSearchVector 課程全文檢索的 tsvector 運算式 , 名稱權重 A , 描述權重 B , 備註權重 C
+// migration 以相同運算式建立 GIN index , 查詢時運算式需完全相同才會使用 index
+const CourseSearchVector = "setweight(to_tsvector('simple', coalesce(name, '')), 'A') || " +
+	"setweight(to_tsvector('simple', coalesce(description, '')), 'B') || " +
+	"setweight(to_tsvector('simple', coalesce(note, '')), 'C')"
+
 type CourseStatus uint
 
 const (
-	CourseStatusDraft   CourseStatus = iota // 草稿
-	CourseStatusPending                     // 審核中
-	CourseStatusOnline                      // 開放報名
-	CourseStatusEnd                         // 已結束
-	CourseStatusPause                       // 暫停報名
+	CourseStatusDraft     CourseStatus = iota // 草稿
+	CourseStatusPending                       // 審核中
+	CourseStatusOnline                        // 開放報名
+	CourseStatusEnd                           // 已結束
+	CourseStatusPause                         // 暫停報名
+	CourseStatusCancelled                     // 已取消 , 已付款的報名全額退款
 )
+
+// courseStatusTransitions 課程狀態可轉換的下一個狀態
+var courseStatusTransitions = map[CourseStatus][]CourseStatus{
+	CourseStatusDraft:   {CourseStatusPending},
+	CourseStatusPending: {CourseStatusDraft, CourseStatusOnline},
+	CourseStatusOnline:  {CourseStatusPause, CourseStatusEnd, CourseStatusCancelled},
+	CourseStatusPause:   {CourseStatusOnline, CourseStatusEnd, CourseStatusCancelled},
+}
+
+// CanTransitionTo 是否可以轉換至 next 狀態
+func (s CourseStatus) CanTransitionTo(next CourseStatus) bool {
+	for _, status := range courseStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s CourseStatus) String() string {
+	switch s {
+	case CourseStatusDraft:
+		return "draft"
+	case CourseStatusPending:
+		return "pending"
+	case CourseStatusOnline:
+		return "online"
+	case CourseStatusEnd:
+		return "end"
+	case CourseStatusPause:
+		return "pause"
+	case CourseStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// LikeCourseName 查詢課程名稱包含 name 的課程
+// 區分大小寫且無法使用 index , 關鍵字搜尋請使用 CourseRepository.Search
+func LikeCourseName(name string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("name LIKE ?", "%"+name+"%")
+	}
+}
+
+// InCourseStatus 查詢狀態
+func InCourseStatus(statuses []CourseStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
+
+// MinCourseRating 查詢平均評分至少為 rating 的課程 , 依評分排序時以 Find 的 pageInfo.Sort 指定 rating_average
+func MinCourseRating(rating float64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("rating_average >= ?", rating)
+	}
+}
+
+// IsOnlineCourse 查詢線上或實體課程
+func IsOnlineCourse(isOnline bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_online = ?", isOnline)
+	}
+}
+
+// RegistrationOpenAt 查詢於時間 t 可報名的課程 , 報名開始與結束時間皆包含在內
+// 資料庫以 UTC 儲存 , t 可為任意時區
+func RegistrationOpenAt(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("registration_start_date <= ? AND registration_end_date >= ?", t.UTC(), t.UTC())
+	}
+}
+
+// RunningBetween 查詢上課期間與 from ~ to 重疊的課程
+func RunningBetween(from, to time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("start_date <= ? AND end_date >= ?", to.UTC(), from.UTC())
+	}
+}
+
+// TaughtBy 查詢教師授課的課程 , 使用子查詢 , 記憶體 repository 不支援
+func TaughtBy(teacherID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&CourseTeacher{}).
+			Select("course_id").
+			Where("teacher_id = ?", teacherID))
+	}
+}
+
+// OnDayOfWeek 查詢於星期 day 上課的課程 , 使用子查詢 , 記憶體 repository 不支援
+func OnDayOfWeek(day time.Weekday) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&CoursePattern{}).
+			Select("course_id").
+			Where("day_of_week = ?", uint(day)))
+	}
+}
@@ -9,8 +9,9 @@ import (
 // CoursePattern 課程模式表
 type CoursePattern struct {
 	gorm.Model
-	CourseID  uint      `gorm:"not null"` // 課程ID
-	DayOfWeek uint      `gorm:"not null"` // 星期幾
-	StartTime time.Time `gorm:"not null"` // 上課開始時間
-	EndTime   time.Time `gorm:"not null"` // 上課結束時間
+	CourseID  uint      `gorm:"not null"`                 // 課程ID
+	DayOfWeek uint      `gorm:"not null"`                 // 星期幾
+	StartTime time.Time `gorm:"not null"`                 // 上課開始時間
+	EndTime   time.Time `gorm:"not null"`                 // 上課結束時間
+	RoomID    uint      `gorm:"not null;default:0;index"` // 上課教室ID , 0 表示未指定 , 線上課程不指定教室 , 由 LocationService 指定
 }
@@ -0,0 +1,95 @@
+package course
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/money"
+)
+
+// CoursePrice 課程價格紀錄 , 只新增不修改 , 保留每次調價的歷史
+// 同一級距以生效時間不晚於查詢時間中最新的一筆為準 , 早鳥價於 ValidUntil 前優先於一般價格
+type CoursePrice struct {
+	gorm.Model
+	CourseID      uint       `gorm:"not null;index:idx_course_price,priority:1"` // 課程ID
+	Tier          PriceTier  `gorm:"not null;default:0"`                         // 0: 一般價格 , 1: 早鳥價
+	Amount        uint       `gorm:"not null"`                                   // 金額 , 以 Currency 的最小單位計
+	Currency      string     `gorm:"type:varchar(3);not null"`                   // 幣別 , ISO 4217 , 與課程幣別相同
+	EffectiveFrom time.Time  `gorm:"not null;index:idx_course_price,priority:2"` // 生效時間
+	ValidUntil    *time.Time // 早鳥價截止時間 , 一般價格為 nil
+}
+
+type PriceTier uint
+
+const (
+	PriceTierRegular   PriceTier = iota // 一般價格
+	PriceTierEarlyBird                  // 早鳥價
+)
+
+func (t PriceTier) String() string {
+	switch t {
+	case PriceTierRegular:
+		return "regular"
+	case PriceTierEarlyBird:
+		return "early_bird"
+	default:
+		return "unknown"
+	}
+}
+
+// Money 價格紀錄的金額
+func (p *CoursePrice) Money() money.Money {
+	return money.Money{Amount: int64(p.Amount), Currency: p.Currency}
+}
+
+// PriceQuote 課程於某個時間的售價
+type PriceQuote struct {
+	Price      money.Money
+	Tier       PriceTier
+	PriceID    uint       // 價格紀錄ID , 0 表示使用課程的基本價格
+	ValidUntil *time.Time // 早鳥價截止時間
+}
+
+// QuotePrice 計算課程於 at 的售價
+// 各級距取生效時間不晚於 at 的最新紀錄 , 早鳥價未過截止時間時優先 , 其次為一般價格 , 都沒有時使用課程的基本價格
+// 參數: course - 課程, prices - 課程的價格紀錄, at - 查詢時間
+// 回傳: 售價
+func QuotePrice(course *Course, prices []*CoursePrice, at time.Time) PriceQuote {
+	var regular, earlyBird *CoursePrice
+	for _, price := range prices {
+		if price.CourseID != course.ID || price.EffectiveFrom.After(at) {
+			continue
+		}
+		switch price.Tier {
+		case PriceTierRegular:
+			if newerPrice(price, regular) {
+				regular = price
+			}
+		case PriceTierEarlyBird:
+			if newerPrice(price, earlyBird) {
+				earlyBird = price
+			}
+		}
+	}
+
+	switch {
+	case earlyBird != nil && earlyBird.ValidUntil != nil && at.Before(*earlyBird.ValidUntil):
+		return PriceQuote{Price: earlyBird.Money(), Tier: PriceTierEarlyBird, PriceID: earlyBird.ID, ValidUntil: earlyBird.ValidUntil}
+	case regular != nil:
+		return PriceQuote{Price: regular.Money(), Tier: PriceTierRegular, PriceID: regular.ID}
+	default:
+		return PriceQuote{Price: course.BasePrice(), Tier: PriceTierRegular}
+	}
+}
+
+// newerPrice price 是否比 current 新 , 生效時間相同時以後新增的為準
+func newerPrice(price, current *CoursePrice) bool {
+	if current == nil {
+		return true
+	}
+	if !price.EffectiveFrom.Equal(current.EffectiveFrom) {
+		return price.EffectiveFrom.After(current.EffectiveFrom)
+	}
+	return price.ID > current.ID
+}
@@ -0,0 +1,83 @@
+package course
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CourseRefundRule 課程的退款規則 , 學生於截止時間前退出課程時可退還 Percent 的已付金額
+// 截止時間為 Anchor 指定的課程時間加上 OffsetHours , 課程沒有設定規則時使用 DefaultRefundRules
+type CourseRefundRule struct {
+	gorm.Model
+	CourseID    uint         `gorm:"not null;index"`     // 課程ID
+	Anchor      RefundAnchor `gorm:"not null;default:0"` // 0: 報名結束時間 , 1: 上課開始時間
+	OffsetHours int          `gorm:"not null;default:0"` // 相對 Anchor 的小時數 , 負數表示之前
+	Percent     uint         `gorm:"not null"`           // 退款比例 , 0-100
+}
+
+type RefundAnchor uint
+
+const (
+	RefundAnchorRegistrationEnd RefundAnchor = iota // 報名結束時間
+	RefundAnchorStart                               // 上課開始時間
+)
+
+func (a RefundAnchor) String() string {
+	switch a {
+	case RefundAnchorRegistrationEnd:
+		return "registration_end"
+	case RefundAnchorStart:
+		return "start"
+	default:
+		return "unknown"
+	}
+}
+
+// Deadline 規則於課程的截止時間
+func (r *CourseRefundRule) Deadline(course *Course) time.Time {
+	anchor := course.RegistrationEndDate
+	if r.Anchor == RefundAnchorStart {
+		anchor = course.StartDate
+	}
+	return anchor.Add(time.Duration(r.OffsetHours) * time.Hour)
+}
+
+// DefaultRefundRules 預設的退款規則 , 報名結束前全額退款 , 上課開始前退款 50% , 之後不退款
+func DefaultRefundRules() []*CourseRefundRule {
+	return []*CourseRefundRule{
+		{Anchor: RefundAnchorRegistrationEnd, Percent: 100},
+		{Anchor: RefundAnchorStart, Percent: 50},
+	}
+}
+
+// RefundQuote 課程於某個時間退出的退款比例
+type RefundQuote struct {
+	Percent  uint       // 退款比例 , 0-100
+	Deadline *time.Time // 適用規則的截止時間 , 沒有適用的規則時為 nil
+}
+
+// QuoteRefund 計算於 at 退出課程的退款比例
+// 依截止時間排序 , 取第一個截止時間晚於 at 的規則 , 都已截止時不退款 , rules 為空時使用 DefaultRefundRules
+// 參數: course - 課程, rules - 課程的退款規則, at - 退出時間
+// 回傳: 退款比例
+func QuoteRefund(course *Course, rules []*CourseRefundRule, at time.Time) RefundQuote {
+	if len(rules) == 0 {
+		rules = DefaultRefundRules()
+	}
+
+	sorted := make([]*CourseRefundRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Deadline(course).Before(sorted[j].Deadline(course))
+	})
+
+	for _, rule := range sorted {
+		deadline := rule.Deadline(course)
+		if at.Before(deadline) {
+			return RefundQuote{Percent: rule.Percent, Deadline: &deadline}
+		}
+	}
+	return RefundQuote{}
+}
@@ -0,0 +1,167 @@
+package course
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CourseSession 課程的單次上課
+// 由 CoursePattern 依課程的上課期間展開 (見 DeriveSessions) , 需要時才寫入資料庫
+// 同一個上課時段在同一時間只會有一筆 , 之後修改 CoursePattern 不影響已寫入的上課
+// 改期時取消原本的上課並新增一筆 RescheduledFromID 指向原本上課的上課 , 原本的時間不會再被展開
+// 線上課程的上課由 SessionService 建立會議 , 會議資訊寫入後改期時沿用同一個會議
+type CourseSession struct {
+	gorm.Model
+	CourseID     uint          `gorm:"not null;index"`                                        // 課程ID
+	PatternID    uint          `gorm:"not null;uniqueIndex:idx_course_session_pattern_start"` // 上課時段ID
+	StartAt      time.Time     `gorm:"not null;uniqueIndex:idx_course_session_pattern_start"` // 上課開始時間
+	EndAt        time.Time     `gorm:"not null"`                                              // 上課結束時間
+	Status       SessionStatus `gorm:"not null;default:0"`                                    // 0: 預定 , 1: 已取消
+	CancelledAt  *time.Time    // 取消時間
+	CancelReason string        `gorm:"type:varchar(255)"` // 取消原因
+	RemindedAt   *time.Time    // 發送上課提醒的時間 , 未發送為 nil
+
+	RescheduledFromID uint   `gorm:"not null;default:0"`                    // 改期前的上課ID , 0 表示不是改期產生的上課
+	MeetingProvider   string `gorm:"type:varchar(20);not null;default:''"`  // 線上會議服務 , 未建立會議為空字串
+	MeetingID         string `gorm:"type:varchar(255);not null;default:''"` // 會議服務的會議ID
+	JoinURL           string `gorm:"type:varchar(500);not null;default:''"` // 學生加入會議的網址
+	HostKey           string `gorm:"type:varchar(100);not null;default:''"` // 主持人金鑰 , 只提供給授課教師
+}
+
+// HasMeeting 是否已建立線上會議
+func (s *CourseSession) HasMeeting() bool {
+	return s.MeetingID != ""
+}
+
+type SessionStatus uint
+
+const (
+	SessionStatusScheduled SessionStatus = iota // 預定
+	SessionStatusCancelled                      // 已取消
+)
+
+func (s SessionStatus) String() string {
+	switch s {
+	case SessionStatusScheduled:
+		return "scheduled"
+	case SessionStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// DeriveSessions 依上課時段展開課程於 from ~ to 的上課 , 只包含開始時間在課程上課期間內的上課
+// CoursePattern 的 DayOfWeek 與 StartTime / EndTime 的時間部分皆為 UTC , 結束時間早於開始時間表示跨日
+// 參數: course - 課程, patterns - 課程的上課時段, from / to - 上課開始時間的範圍 , 皆包含在內
+// 回傳: 尚未寫入的上課 , 依開始時間與上課時段ID排序
+func DeriveSessions(course *Course, patterns []*CoursePattern, from, to time.Time) []*CourseSession {
+	from, to = from.UTC(), to.UTC()
+	if start := course.StartDate.UTC(); start.After(from) {
+		from = start
+	}
+	if end := course.EndDate.UTC(); end.Before(to) {
+		to = end
+	}
+
+	var sessions []*CourseSession
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, pattern := range patterns {
+			if time.Weekday(pattern.DayOfWeek) != day.Weekday() {
+				continue
+			}
+
+			startAt := day.Add(timeOfDay(pattern.StartTime))
+			endAt := day.Add(timeOfDay(pattern.EndTime))
+			if !endAt.After(startAt) {
+				endAt = endAt.AddDate(0, 0, 1)
+			}
+			if startAt.Before(from) || startAt.After(to) {
+				continue
+			}
+
+			sessions = append(sessions, &CourseSession{
+				CourseID:  course.ID,
+				PatternID: pattern.ID,
+				StartAt:   startAt,
+				EndAt:     endAt,
+			})
+		}
+	}
+
+	slices.SortStableFunc(sessions, func(a, b *CourseSession) int {
+		if c := a.StartAt.Compare(b.StartAt); c != 0 {
+			return c
+		}
+		return int(a.PatternID) - int(b.PatternID)
+	})
+	return sessions
+}
+
+// Overlaps 上課時間是否與 other 重疊 , 一次上課結束時另一次上課才開始不算重疊
+func (s *CourseSession) Overlaps(other *CourseSession) bool {
+	return s.StartAt.Before(other.EndAt) && other.StartAt.Before(s.EndAt)
+}
+
+// truncateDay 取得 t (UTC) 當天的 00:00
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// timeOfDay 取得 t (UTC) 的時間部分
+func timeOfDay(t time.Time) time.Duration {
+	t = t.UTC()
+	return t.Sub(truncateDay(t))
+}
+
+// SessionOfCourse 查詢課程的上課
+func SessionOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
+
+// SessionStartBetween 查詢開始時間於 from ~ to 的上課 , 皆包含在內
+func SessionStartBetween(from, to time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("start_at >= ? AND start_at <= ?", from.UTC(), to.UTC())
+	}
+}
+
+// InSessionStatus 查詢狀態
+func InSessionStatus(statuses []SessionStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
+
+// SessionWithoutMeeting 查詢尚未建立線上會議的上課
+func SessionWithoutMeeting() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("meeting_id = ''")
+	}
+}
+
+// SessionEndAfter 查詢於 t 之後結束的上課
+func SessionEndAfter(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("end_at > ?", t.UTC())
+	}
+}
+
+// SessionEndedBy 查詢於 t 之前 (包含) 結束的上課
+func SessionEndedBy(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("end_at <= ?", t.UTC())
+	}
+}
+
+// SessionDueForReminder 查詢課程於 from 之後 , to 之前 (包含) 開始且尚未提醒的預定上課
+func SessionDueForReminder(courseID uint, from, to time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ? AND status = ? AND reminded_at IS NULL AND start_at > ? AND start_at <= ?",
+			courseID, SessionStatusScheduled, from.UTC(), to.UTC())
+	}
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Enrollment 學生報名課程
+// 同一個學生在同一門課程只會有一筆未刪除的報名 , 退出後再次報名沿用同一筆資料
+// 付費課程報名後為待付款 , 付款成功後才變更為已報名 , 待付款期間同樣佔用名額
+type Enrollment struct {
+	gorm.Model
+	CourseID    uint             `gorm:"not null;uniqueIndex:idx_enrollment_course_student_active,where:deleted_at IS NULL"`       // 課程ID
+	StudentID   uint             `gorm:"not null;uniqueIndex:idx_enrollment_course_student_active,where:deleted_at IS NULL;index"` // 學生 user ID
+	Status      EnrollmentStatus `gorm:"not null;default:0"`                                                                       // 0: 已報名 , 1: 已退出 , 2: 待付款
+	EnrolledAt  time.Time        `gorm:"not null"`                                                                                 // 報名時間 , 再次報名時更新
+	WithdrawnAt *time.Time       // 退出時間
+}
+
+type EnrollmentStatus uint
+
+const (
+	EnrollmentStatusEnrolled       EnrollmentStatus = iota // 已報名
+	EnrollmentStatusWithdrawn                              // 已退出
+	EnrollmentStatusPendingPayment                         // 待付款
+)
+
+func (s EnrollmentStatus) String() string {
+	switch s {
+	case EnrollmentStatusEnrolled:
+		return "enrolled"
+	case EnrollmentStatusWithdrawn:
+		return "withdrawn"
+	case EnrollmentStatusPendingPayment:
+		return "pending_payment"
+	default:
+		return "unknown"
+	}
+}
+
+// EnrollmentOfCourse 查詢課程的報名
+func EnrollmentOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
+
+// EnrollmentOfStudent 查詢學生的報名
+func EnrollmentOfStudent(studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("student_id = ?", studentID)
+	}
+}
+
+// InEnrollmentStatus 查詢狀態
+func InEnrollmentStatus(statuses []EnrollmentStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
@@ -0,0 +1,275 @@
+package event
+
+import "time"
+
+// Event 領域事件 , 於 entity 變更的同一個 transaction 寫入 outbox , 再由 relay 發送
+// 事件內容以 JSON 序列化 , 欄位新增須維持向下相容 , 不可更改既有欄位的意義
+type Event interface {
+	// EventType 事件類型 , 例如 course.published
+	EventType() string
+	// AggregateType 事件所屬 entity 的類型 , 例如 course
+	AggregateType() string
+	// AggregateID 事件所屬 entity 的 ID
+	AggregateID() uint
+}
+
+// 事件類型
+const (
+	TypeCourseStatusChanged = "course.status_changed"
+	TypeCoursePublished     = "course.published"
+	TypeCourseFull          = "course.full"
+	TypeCourseCancelled     = "course.cancelled"
+	TypeTeacherApproved     = "teacher.approved"
+	TypeTeacherRejected     = "teacher.rejected"
+	TypeStudentEnrolled     = "enrollment.enrolled"
+	TypeStudentWithdrawn    = "enrollment.withdrawn"
+	TypeOrderPaid           = "order.paid"
+	TypeOrderFailed         = "order.failed"
+	TypeOrderRefunded       = "order.refunded"
+	TypeInvoiceIssued       = "invoice.issued"
+	TypeCertificateIssued   = "certificate.issued"
+	TypeCertificateRevoked  = "certificate.revoked"
+	TypeReviewSubmitted     = "review.submitted"
+	TypeReviewModerated     = "review.moderated"
+)
+
+// Types 回傳所有事件類型
+func Types() []string {
+	return []string{
+		TypeCourseStatusChanged,
+		TypeCoursePublished,
+		TypeCourseFull,
+		TypeCourseCancelled,
+		TypeTeacherApproved,
+		TypeTeacherRejected,
+		TypeStudentEnrolled,
+		TypeStudentWithdrawn,
+		TypeOrderPaid,
+		TypeOrderFailed,
+		TypeOrderRefunded,
+		TypeInvoiceIssued,
+		TypeCertificateIssued,
+		TypeCertificateRevoked,
+		TypeReviewSubmitted,
+		TypeReviewModerated,
+	}
+}
+
+// CourseStatusChanged 課程狀態變更
+type CourseStatusChanged struct {
+	CourseID  uint      `json:"course_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (e CourseStatusChanged) EventType() string     { return TypeCourseStatusChanged }
+func (e CourseStatusChanged) AggregateType() string { return "course" }
+func (e CourseStatusChanged) AggregateID() uint     { return e.CourseID }
+
+// CoursePublished 課程開放報名 , 包含由暫停報名恢復
+type CoursePublished struct {
+	CourseID              uint      `json:"course_id"`
+	Name                  string    `json:"name"`
+	Price                 uint      `json:"price"`
+	Currency              string    `json:"currency"`
+	MaxStudents           uint      `json:"max_students"`
+	RegistrationStartDate time.Time `json:"registration_start_date"`
+	RegistrationEndDate   time.Time `json:"registration_end_date"`
+	PublishedAt           time.Time `json:"published_at"`
+}
+
+func (e CoursePublished) EventType() string     { return TypeCoursePublished }
+func (e CoursePublished) AggregateType() string { return "course" }
+func (e CoursePublished) AggregateID() uint     { return e.CourseID }
+
+// CourseFull 課程報名人數達到上限
+type CourseFull struct {
+	CourseID    uint      `json:"course_id"`
+	MaxStudents uint      `json:"max_students"`
+	FullAt      time.Time `json:"full_at"`
+}
+
+func (e CourseFull) EventType() string     { return TypeCourseFull }
+func (e CourseFull) AggregateType() string { return "course" }
+func (e CourseFull) AggregateID() uint     { return e.CourseID }
+
+// CourseCancelled 課程取消 , 待付款的訂單視為付款失敗 , 已報名的學生退出課程並全額退款
+type CourseCancelled struct {
+	CourseID    uint      `json:"course_id"`
+	Name        string    `json:"name"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+func (e CourseCancelled) EventType() string     { return TypeCourseCancelled }
+func (e CourseCancelled) AggregateType() string { return "course" }
+func (e CourseCancelled) AggregateID() uint     { return e.CourseID }
+
+// TeacherApproved 教師審核通過
+type TeacherApproved struct {
+	TeacherID  uint      `json:"teacher_id"`
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+func (e TeacherApproved) EventType() string     { return TypeTeacherApproved }
+func (e TeacherApproved) AggregateType() string { return "teacher" }
+func (e TeacherApproved) AggregateID() uint     { return e.TeacherID }
+
+// TeacherRejected 教師審核不通過
+type TeacherRejected struct {
+	TeacherID  uint      `json:"teacher_id"`
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+func (e TeacherRejected) EventType() string     { return TypeTeacherRejected }
+func (e TeacherRejected) AggregateType() string { return "teacher" }
+func (e TeacherRejected) AggregateID() uint     { return e.TeacherID }
+
+// StudentEnrolled 學生報名課程
+type StudentEnrolled struct {
+	EnrollmentID uint      `json:"enrollment_id"`
+	CourseID     uint      `json:"course_id"`
+	StudentID    uint      `json:"student_id"`
+	EnrolledAt   time.Time `json:"enrolled_at"`
+}
+
+func (e StudentEnrolled) EventType() string     { return TypeStudentEnrolled }
+func (e StudentEnrolled) AggregateType() string { return "enrollment" }
+func (e StudentEnrolled) AggregateID() uint     { return e.EnrollmentID }
+
+// StudentWithdrawn 學生退出課程
+type StudentWithdrawn struct {
+	EnrollmentID uint      `json:"enrollment_id"`
+	CourseID     uint      `json:"course_id"`
+	StudentID    uint      `json:"student_id"`
+	WithdrawnAt  time.Time `json:"withdrawn_at"`
+}
+
+func (e StudentWithdrawn) EventType() string     { return TypeStudentWithdrawn }
+func (e StudentWithdrawn) AggregateType() string { return "enrollment" }
+func (e StudentWithdrawn) AggregateID() uint     { return e.EnrollmentID }
+
+// OrderPaid 訂單付款成功 , 報名同時確認
+type OrderPaid struct {
+	OrderID      uint      `json:"order_id"`
+	EnrollmentID uint      `json:"enrollment_id"`
+	CourseID     uint      `json:"course_id"`
+	StudentID    uint      `json:"student_id"`
+	Amount       uint      `json:"amount"`
+	Currency     string    `json:"currency"`
+	Provider     string    `json:"provider"`
+	PaymentID    string    `json:"payment_id"`
+	PaidAt       time.Time `json:"paid_at"`
+}
+
+func (e OrderPaid) EventType() string     { return TypeOrderPaid }
+func (e OrderPaid) AggregateType() string { return "order" }
+func (e OrderPaid) AggregateID() uint     { return e.OrderID }
+
+// OrderFailed 訂單付款失敗或逾期未付款 , 報名名額同時釋出
+type OrderFailed struct {
+	OrderID      uint      `json:"order_id"`
+	EnrollmentID uint      `json:"enrollment_id"`
+	CourseID     uint      `json:"course_id"`
+	StudentID    uint      `json:"student_id"`
+	Reason       string    `json:"reason"`
+	FailedAt     time.Time `json:"failed_at"`
+}
+
+func (e OrderFailed) EventType() string     { return TypeOrderFailed }
+func (e OrderFailed) AggregateType() string { return "order" }
+func (e OrderFailed) AggregateID() uint     { return e.OrderID }
+
+// OrderRefunded 訂單已退款 , 金額為實際退款金額 , 部分退款時小於訂單金額
+type OrderRefunded struct {
+	OrderID      uint      `json:"order_id"`
+	RefundID     uint      `json:"refund_id"`
+	EnrollmentID uint      `json:"enrollment_id"`
+	CourseID     uint      `json:"course_id"`
+	StudentID    uint      `json:"student_id"`
+	Amount       uint      `json:"amount"`
+	Currency     string    `json:"currency"`
+	Reason       string    `json:"reason"`
+	RefundedAt   time.Time `json:"refunded_at"`
+}
+
+func (e OrderRefunded) EventType() string     { return TypeOrderRefunded }
+func (e OrderRefunded) AggregateType() string { return "order" }
+func (e OrderRefunded) AggregateID() uint     { return e.OrderID }
+
+// InvoiceIssued 已付款訂單開立發票
+type InvoiceIssued struct {
+	InvoiceID uint      `json:"invoice_id"`
+	Number    string    `json:"number"`
+	OrderID   uint      `json:"order_id"`
+	CourseID  uint      `json:"course_id"`
+	StudentID uint      `json:"student_id"`
+	Total     uint      `json:"total"`
+	Currency  string    `json:"currency"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
+
+func (e InvoiceIssued) EventType() string     { return TypeInvoiceIssued }
+func (e InvoiceIssued) AggregateType() string { return "invoice" }
+func (e InvoiceIssued) AggregateID() uint     { return e.InvoiceID }
+
+// CertificateIssued 課程結束後發放結業證書
+type CertificateIssued struct {
+	CertificateID uint      `json:"certificate_id"`
+	Code          string    `json:"code"`
+	CourseID      uint      `json:"course_id"`
+	StudentID     uint      `json:"student_id"`
+	IssuedAt      time.Time `json:"issued_at"`
+}
+
+func (e CertificateIssued) EventType() string     { return TypeCertificateIssued }
+func (e CertificateIssued) AggregateType() string { return "certificate" }
+func (e CertificateIssued) AggregateID() uint     { return e.CertificateID }
+
+// CertificateRevoked 結業證書撤銷 , 之後驗證時顯示為已撤銷
+type CertificateRevoked struct {
+	CertificateID uint      `json:"certificate_id"`
+	Code          string    `json:"code"`
+	CourseID      uint      `json:"course_id"`
+	StudentID     uint      `json:"student_id"`
+	Reason        string    `json:"reason"`
+	RevokedAt     time.Time `json:"revoked_at"`
+}
+
+func (e CertificateRevoked) EventType() string     { return TypeCertificateRevoked }
+func (e CertificateRevoked) AggregateType() string { return "certificate" }
+func (e CertificateRevoked) AggregateID() uint     { return e.CertificateID }
+
+// ReviewSubmitted 學生新增或修改課程評價 , 評價待審核
+type ReviewSubmitted struct {
+	ReviewID    uint      `json:"review_id"`
+	CourseID    uint      `json:"course_id"`
+	StudentID   uint      `json:"student_id"`
+	Rating      uint      `json:"rating"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+func (e ReviewSubmitted) EventType() string     { return TypeReviewSubmitted }
+func (e ReviewSubmitted) AggregateType() string { return "review" }
+func (e ReviewSubmitted) AggregateID() uint     { return e.ReviewID }
+
+// ReviewModerated 課程評價審核 , 審核通過的評價計入課程與教師的評分統計
+type ReviewModerated struct {
+	ReviewID    uint      `json:"review_id"`
+	CourseID    uint      `json:"course_id"`
+	StudentID   uint      `json:"student_id"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	ModeratorID uint      `json:"moderator_id"`
+	ModeratedAt time.Time `json:"moderated_at"`
+}
+
+func (e ReviewModerated) EventType() string     { return TypeReviewModerated }
+func (e ReviewModerated) AggregateType() string { return "review" }
+func (e ReviewModerated) AggregateID() uint     { return e.ReviewID }
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Invoice 已付款訂單的發票 , 每筆訂單最多一張 , 訂單付款後由 InvoiceService 開立
+// 發票號碼依開立年度連續編號 , 由 InvoiceSequence 於開立的 transaction 中鎖定取號 , 不會跳號
+// 金額為開立時的訂單金額 , 明細見 InvoiceLine , 開立後不再修改
+type Invoice struct {
+	gorm.Model
+	Number       string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_invoice_number"`        // 發票號碼 , 例如 INV-2025-000001
+	Year         int       `gorm:"not null;uniqueIndex:idx_invoice_year_seq,priority:1"`            // 開立年度
+	Seq          uint      `gorm:"not null;uniqueIndex:idx_invoice_year_seq,priority:2"`            // 年度內的流水號 , 從 1 開始
+	OrderID      uint      `gorm:"not null;uniqueIndex:idx_invoice_order,where:deleted_at IS NULL"` // 訂單ID
+	EnrollmentID uint      `gorm:"not null"`                                                        // 報名ID
+	CourseID     uint      `gorm:"not null;index"`                                                  // 課程ID
+	StudentID    uint      `gorm:"not null;index"`                                                  // 學生 user ID
+	Currency     string    `gorm:"type:varchar(3);not null"`                                        // 幣別 , ISO 4217
+	Subtotal     uint      `gorm:"not null"`                                                        // 課程售價合計 , 以幣別的最小單位計
+	Discount     uint      `gorm:"not null;default:0"`                                              // 折扣合計
+	Total        uint      `gorm:"not null"`                                                        // 實付金額 , 為 Subtotal 扣除 Discount
+	PaymentID    string    `gorm:"type:varchar(100);not null;default:''"`                           // 金流商的付款ID
+	PaidAt       time.Time `gorm:"not null"`                                                        // 付款時間
+	IssuedAt     time.Time `gorm:"not null"`                                                        // 開立時間
+}
+
+// FormatNumber 產生發票號碼 , 格式為 <prefix>-<年度>-<6 位數流水號>
+// 參數: prefix - 號碼前綴, year - 開立年度, seq - 年度內的流水號
+// 回傳: 發票號碼
+func FormatNumber(prefix string, year int, seq uint) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, seq)
+}
+
+// InvoiceOfYear 查詢年度開立的發票
+func InvoiceOfYear(year int) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("year = ?", year)
+	}
+}
+
+// InvoiceOfStudent 查詢學生的發票
+func InvoiceOfStudent(studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("student_id = ?", studentID)
+	}
+}
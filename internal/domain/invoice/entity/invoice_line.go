@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// InvoiceLine 發票明細 , 課程售價一筆 , 每個套用的促銷活動各一筆折扣
+// 金額皆為正數 , 折扣明細於合計時扣除
+type InvoiceLine struct {
+	gorm.Model
+	InvoiceID   uint            `gorm:"not null;index"`             // 發票ID
+	Position    uint            `gorm:"not null"`                   // 明細順序 , 從 1 開始
+	Kind        InvoiceLineKind `gorm:"not null;default:0"`         // 0: 課程 , 1: 折扣
+	Description string          `gorm:"type:varchar(255);not null"` // 課程名稱或促銷活動名稱 , 空字串表示訂單的折扣合計
+	Detail      string          `gorm:"type:varchar(100);not null"` // 課程的價格級距或促銷活動的優惠碼
+	Quantity    uint            `gorm:"not null;default:1"`         // 數量
+	UnitAmount  uint            `gorm:"not null"`                   // 單價 , 以幣別的最小單位計
+	Amount      uint            `gorm:"not null"`                   // 金額 , 為 UnitAmount 乘以 Quantity
+	ReferenceID uint            `gorm:"not null;default:0"`         // 課程價格紀錄ID或促銷活動ID , 0 表示課程的基本價格或無對應紀錄
+}
+
+type InvoiceLineKind uint
+
+const (
+	InvoiceLineKindCourse   InvoiceLineKind = iota // 課程
+	InvoiceLineKindDiscount                        // 折扣
+)
+
+func (k InvoiceLineKind) String() string {
+	switch k {
+	case InvoiceLineKindCourse:
+		return "course"
+	case InvoiceLineKindDiscount:
+		return "discount"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+// InvoiceSequence 發票年度流水號 , 每個年度一筆
+// 不使用資料庫的 sequence , sequence 於 transaction rollback 時不會退回 , 會造成跳號
+// 取號時更新並鎖定該年度的紀錄至 transaction 結束 , 開立失敗時與發票一起 rollback
+type InvoiceSequence struct {
+	Year       int       `gorm:"primaryKey;autoIncrement:false"` // 年度
+	LastNumber uint      `gorm:"not null;default:0"`             // 最後使用的流水號
+	UpdatedAt  time.Time // 最後取號時間
+}
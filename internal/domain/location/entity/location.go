@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// Location 實體課程的上課地點 , 例如分校或大樓 , 地點中有多間教室 (Room)
+type Location struct {
+	gorm.Model
+	Name    string `gorm:"type:varchar(100);not null;uniqueIndex:idx_location_name_active,where:deleted_at IS NULL"` // 地點名稱
+	Address string `gorm:"type:varchar(255);not null"`                                                               // 地址
+	Note    string `gorm:"type:text"`                                                                                // 備註 , 例如交通方式
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Room 地點中的教室 , 指定給實體課程的上課時段 (CoursePattern.RoomID)
+// 容納人數須不小於使用教室的課程的最大學生人數 , 同一間教室的上課時間不可重疊 , 由 LocationService 檢查
+type Room struct {
+	gorm.Model
+	LocationID uint       `gorm:"not null;uniqueIndex:idx_room_location_name_active,where:deleted_at IS NULL"`                  // 地點ID
+	Name       string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_room_location_name_active,where:deleted_at IS NULL"` // 教室名稱 , 同一地點中不可重複
+	Capacity   uint       `gorm:"not null;index"`                                                                               // 容納人數
+	Facilities Facilities `gorm:"type:jsonb;not null"`                                                                          // 設備 , 例如 projector
+}
+
+// Facilities 教室設備清單 , 以 JSON 陣列儲存
+type Facilities []string
+
+// Value 實作 driver.Valuer
+func (f Facilities) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(f))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 實作 sql.Scanner
+func (f *Facilities) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported facilities type %T", value)
+	}
+	return json.Unmarshal(b, f)
+}
+
+// RoomOfLocation 查詢地點的教室
+func RoomOfLocation(locationID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("location_id = ?", locationID)
+	}
+}
+
+// MinRoomCapacity 查詢容納人數不小於 capacity 的教室
+func MinRoomCapacity(capacity uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("capacity >= ?", capacity)
+	}
+}
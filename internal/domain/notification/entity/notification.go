@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification 待發送的通知 (retry queue)
+// 建立時即依收件人語系套用樣板 , 發送失敗時依 NextAttemptAt 重試
+// Key 與 Channel 唯一 , 同一個來源 (例如同一個事件) 重複建立時略過
+type Notification struct {
+	gorm.Model
+	Key           string             `gorm:"type:varchar(200);not null;uniqueIndex:idx_notification_key_channel"` // 去除重複用的識別 , 例如事件ID加樣板名稱
+	Channel       Channel            `gorm:"type:varchar(10);not null;uniqueIndex:idx_notification_key_channel"`  // 發送管道
+	UserID        uint               `gorm:"not null;index"`                                                      // 收件人 user ID
+	Address       string             `gorm:"type:varchar(255);not null"`                                          // 收件地址 , email 或電話
+	Template      string             `gorm:"type:varchar(50);not null"`                                           // 樣板名稱
+	Locale        string             `gorm:"type:varchar(10);not null"`                                           // 語系
+	Subject       string             `gorm:"type:varchar(255)"`                                                   // 主旨 , 簡訊沒有主旨
+	Body          string             `gorm:"type:text;not null"`                                                  // 內容
+	Status        NotificationStatus `gorm:"not null;default:0"`                                                  // 0: 待發送 , 1: 已發送 , 2: 失敗
+	Attempts      uint               `gorm:"not null;default:0"`                                                  // 已發送次數
+	NextAttemptAt time.Time          `gorm:"not null;index:idx_notification_pending,where:status = 0"`            // 下次發送時間
+	LastError     string             `gorm:"type:text"`                                                           // 最後一次發送失敗的原因
+	SentAt        *time.Time         // 發送成功時間
+}
+
+// Channel 通知發送管道
+type Channel string
+
+const (
+	ChannelEmail Channel = "email" // 電子郵件
+	ChannelSMS   Channel = "sms"   // 簡訊
+)
+
+// Channels 回傳所有發送管道
+func Channels() []Channel {
+	return []Channel{ChannelEmail, ChannelSMS}
+}
+
+type NotificationStatus uint
+
+const (
+	NotificationStatusPending NotificationStatus = iota // 待發送 , 包含等待重試
+	NotificationStatusSent                              // 已發送
+	NotificationStatusFailed                            // 超過重試次數
+)
+
+func (s NotificationStatus) String() string {
+	switch s {
+	case NotificationStatusPending:
+		return "pending"
+	case NotificationStatusSent:
+		return "sent"
+	case NotificationStatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// NotificationAttempt 單次發送的結果
+type NotificationAttempt struct {
+	Status        NotificationStatus // 發送後的狀態
+	Error         string             // 失敗原因
+	At            time.Time          // 發送時間
+	NextAttemptAt time.Time          // 狀態為待發送時的下次發送時間
+}
+
+// NotificationPending 查詢於時間 t 可發送的通知
+func NotificationPending(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND next_attempt_at <= ?", NotificationStatusPending, t.UTC())
+	}
+}
+
+// NotificationOfUser 查詢收件人的通知
+func NotificationOfUser(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", userID)
+	}
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// 支援的語系
+const (
+	LocaleZhTW    = "zh-TW"
+	LocaleEn      = "en"
+	DefaultLocale = LocaleZhTW
+)
+
+// Locales 回傳支援的語系
+func Locales() []string {
+	return []string{LocaleZhTW, LocaleEn}
+}
+
+// IsSupportedLocale 是否為支援的語系
+func IsSupportedLocale(locale string) bool {
+	return slices.Contains(Locales(), locale)
+}
+
+// NotificationPreference 使用者的通知偏好與聯絡方式
+// 沒有設定時使用 DefaultNotificationPreference
+// Email / Phone 用於沒有其他聯絡資料的收件人 (例如學生) , 通知時已提供地址則以提供的地址為準
+type NotificationPreference struct {
+	gorm.Model
+	UserID       uint   `gorm:"not null;uniqueIndex:idx_notification_preference_user_id_active,where:deleted_at IS NULL"` // user ID
+	Locale       string `gorm:"type:varchar(10);not null"`                                                                // 通知語系
+	EmailEnabled bool   `gorm:"not null"`                                                                                 // 是否接收 email
+	SMSEnabled   bool   `gorm:"not null"`                                                                                 // 是否接收簡訊
+	Email        string `gorm:"type:varchar(100)"`                                                                        // 接收通知的 email
+	Phone        string `gorm:"type:varchar(20)"`                                                                         // 接收簡訊的電話
+}
+
+// DefaultNotificationPreference 預設通知偏好 , 使用預設語系並接收所有管道
+func DefaultNotificationPreference(userID uint) *NotificationPreference {
+	return &NotificationPreference{UserID: userID, Locale: DefaultLocale, EmailEnabled: true, SMSEnabled: true}
+}
+
+// Enabled 是否接收管道的通知
+func (p *NotificationPreference) Enabled(channel Channel) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.EmailEnabled
+	case ChannelSMS:
+		return p.SMSEnabled
+	default:
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Order 課程訂單 , 付費課程報名時與報名資料一起建立 , 金額與幣別為建立時的課程售價扣除促銷折扣
+// 折扣後應付金額為 0 時直接建立為已付款
+// 建立付款後記錄金流商的付款ID , 依金流商通知的付款結果變更狀態 , 付款成功後才確認報名
+// 超過 ExpiresAt 仍未付款時視為付款失敗 , 並釋出報名名額
+// 退款成功時累計 RefundedAmount , 累計達到 Amount 時才變更為已退款 , 部分退款的訂單維持已付款
+type Order struct {
+	gorm.Model
+	EnrollmentID   uint        `gorm:"not null;index"`                                       // 報名ID
+	CourseID       uint        `gorm:"not null;index"`                                       // 課程ID
+	StudentID      uint        `gorm:"not null;index"`                                       // 學生 user ID
+	Amount         uint        `gorm:"not null"`                                             // 應付金額 , 以幣別的最小單位計 , 為 ListAmount 扣除 DiscountAmount
+	ListAmount     uint        `gorm:"not null;default:0"`                                   // 課程售價
+	DiscountAmount uint        `gorm:"not null;default:0"`                                   // 促銷折扣金額
+	Currency       string      `gorm:"type:varchar(3);not null"`                             // 幣別 , ISO 4217
+	PriceID        uint        `gorm:"not null;default:0"`                                   // 計價使用的課程價格紀錄ID , 0 表示使用課程的基本價格
+	Status         OrderStatus `gorm:"not null;default:0;index:idx_order_expiry,priority:1"` // 0: 待付款 , 1: 已付款 , 2: 付款失敗 , 3: 已退款
+	Provider       string      `gorm:"type:varchar(20);not null;default:''"`                 // 金流商 , 建立付款後寫入
+	PaymentID      string      `gorm:"type:varchar(100);not null;default:'';index"`          // 金流商的付款ID , 建立付款後寫入
+	CheckoutURL    string      `gorm:"type:varchar(500);not null;default:''"`                // 付款頁面網址
+	ExpiresAt      time.Time   `gorm:"not null;index:idx_order_expiry,priority:2"`           // 付款期限
+	PaidAt         *time.Time  // 付款時間
+	FailedAt       *time.Time  // 付款失敗時間
+	FailureReason  string      `gorm:"type:varchar(255);not null;default:''"` // 付款失敗原因
+	RefundedAmount uint        `gorm:"not null;default:0"`                    // 已退款金額 , 退款成功時累計
+	RefundedAt     *time.Time  // 最後一次退款成功的時間
+}
+
+type OrderStatus uint
+
+const (
+	OrderStatusPending  OrderStatus = iota // 待付款
+	OrderStatusPaid                        // 已付款
+	OrderStatusFailed                      // 付款失敗 , 包含逾期未付款
+	OrderStatusRefunded                    // 已退款
+)
+
+// orderStatusTransitions 訂單狀態可轉換的下一個狀態
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {OrderStatusPaid, OrderStatusFailed},
+	OrderStatusPaid:    {OrderStatusRefunded},
+}
+
+// CanTransitionTo 是否可以轉換至 next 狀態
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, status := range orderStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderStatusPending:
+		return "pending"
+	case OrderStatusPaid:
+		return "paid"
+	case OrderStatusFailed:
+		return "failed"
+	case OrderStatusRefunded:
+		return "refunded"
+	default:
+		return "unknown"
+	}
+}
+
+// OrderOfEnrollment 查詢報名的訂單
+func OrderOfEnrollment(enrollmentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("enrollment_id = ?", enrollmentID)
+	}
+}
+
+// InOrderStatus 查詢狀態
+func InOrderStatus(statuses []OrderStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
+
+// OrderExpired 查詢於時間 t 已超過付款期限的待付款訂單
+func OrderExpired(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND expires_at < ?", OrderStatusPending, t.UTC())
+	}
+}
+
+// OrderOfCourse 查詢課程的訂單
+func OrderOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// PaymentCallback 金流商的付款結果通知紀錄
+// Provider 與 EventID 唯一 , 金流商重送相同的通知時不會重複處理
+type PaymentCallback struct {
+	gorm.Model
+	Provider  string      `gorm:"type:varchar(20);not null;uniqueIndex:idx_payment_callback_event"`  // 金流商
+	EventID   string      `gorm:"type:varchar(100);not null;uniqueIndex:idx_payment_callback_event"` // 金流商的通知ID
+	OrderID   uint        `gorm:"not null;index"`                                                    // 訂單ID
+	PaymentID string      `gorm:"type:varchar(100);not null"`                                        // 金流商的付款ID
+	Result    OrderStatus `gorm:"not null"`                                                          // 通知的付款結果 , 已付款或付款失敗
+	Applied   bool        `gorm:"not null"`                                                          // 是否已變更訂單狀態 , 訂單狀態不允許變更 (例如已逾期) 時為 false
+	Payload   string      `gorm:"type:text;not null"`                                                // 通知的原始內容
+}
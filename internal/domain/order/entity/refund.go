@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Refund 訂單的退款紀錄 , 每筆訂單最多一筆
+// 學生退出課程時依課程的退款規則計算比例 , 課程取消時全額退款
+// 訂單已不是待付款 (例如已逾期) 或金額不符時才收到的付款 , 全額退還金流商實際收取的金額
+// 建立時為待退款 , 由 OrderService.ProcessRefunds 向金流商退款 , 失敗時依 NextAttemptAt 重試 , 超過次數上限標記為失敗
+type Refund struct {
+	gorm.Model
+	OrderID          uint         `gorm:"not null;uniqueIndex:idx_refund_order,where:deleted_at IS NULL"` // 訂單ID
+	EnrollmentID     uint         `gorm:"not null;index"`                                                 // 報名ID
+	CourseID         uint         `gorm:"not null;index"`                                                 // 課程ID
+	StudentID        uint         `gorm:"not null"`                                                       // 學生 user ID
+	Amount           uint         `gorm:"not null"`                                                       // 退款金額 , 以幣別的最小單位計
+	Currency         string       `gorm:"type:varchar(3);not null"`                                       // 幣別 , ISO 4217
+	Percent          uint         `gorm:"not null"`                                                       // 退款比例 , 建立時依退款規則計算
+	Reason           RefundReason `gorm:"not null;default:0"`                                             // 0: 學生退出 , 1: 課程取消 , 2: 付款未被接受
+	Provider         string       `gorm:"type:varchar(20);not null"`                                      // 付款的金流商
+	PaymentID        string       `gorm:"type:varchar(100);not null"`                                     // 金流商的付款ID
+	ProviderRefundID string       `gorm:"type:varchar(100);not null;default:''"`                          // 金流商的退款ID , 退款成功後寫入
+	Status           RefundStatus `gorm:"not null;default:0"`                                             // 0: 待退款 , 1: 已退款 , 2: 退款失敗
+	Attempts         uint         `gorm:"not null;default:0"`                                             // 已嘗試次數
+	NextAttemptAt    time.Time    `gorm:"not null;index:idx_refund_pending,where:status = 0"`             // 下次嘗試時間
+	LastError        string       `gorm:"type:text"`                                                      // 最後一次退款失敗的原因
+	RefundedAt       *time.Time   // 退款成功時間
+}
+
+type RefundReason uint
+
+const (
+	RefundReasonWithdrawal      RefundReason = iota // 學生退出課程
+	RefundReasonCourseCancelled                     // 課程取消
+	RefundReasonPaymentRejected                     // 付款未被接受 , 訂單已不是待付款或金額不符
+)
+
+func (r RefundReason) String() string {
+	switch r {
+	case RefundReasonWithdrawal:
+		return "withdrawal"
+	case RefundReasonCourseCancelled:
+		return "course_cancelled"
+	case RefundReasonPaymentRejected:
+		return "payment_rejected"
+	default:
+		return "unknown"
+	}
+}
+
+type RefundStatus uint
+
+const (
+	RefundStatusPending   RefundStatus = iota // 待退款 , 包含等待重試
+	RefundStatusSucceeded                     // 已退款
+	RefundStatusFailed                        // 超過重試次數 , 需人工處理
+)
+
+func (s RefundStatus) String() string {
+	switch s {
+	case RefundStatusPending:
+		return "pending"
+	case RefundStatusSucceeded:
+		return "succeeded"
+	case RefundStatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// NewRefund 建立訂單的待退款紀錄 , 金額為訂單金額的 percent% , 無條件捨去
+// 參數: order - 已付款的訂單, percent - 退款比例, reason - 退款原因, at - 建立時間
+// 回傳: 退款紀錄
+func NewRefund(order *Order, percent uint, reason RefundReason, at time.Time) *Refund {
+	return &Refund{
+		OrderID:       order.ID,
+		EnrollmentID:  order.EnrollmentID,
+		CourseID:      order.CourseID,
+		StudentID:     order.StudentID,
+		Amount:        uint(uint64(order.Amount) * uint64(percent) / 100),
+		Currency:      order.Currency,
+		Percent:       percent,
+		Reason:        reason,
+		Provider:      order.Provider,
+		PaymentID:     order.PaymentID,
+		NextAttemptAt: at,
+	}
+}
+
+// RefundQuote 報名於某個時間退出課程可退款的金額
+type RefundQuote struct {
+	EnrollmentID uint       // 報名ID
+	OrderID      uint       // 已付款的訂單ID , 0 表示沒有已付款的訂單
+	Paid         uint       // 已付金額
+	Amount       uint       // 可退款金額
+	Currency     string     // 幣別
+	Percent      uint       // 退款比例
+	Deadline     *time.Time // 目前比例的截止時間 , 已不可退款時為 nil
+}
+
+// RefundAttempt 單次退款的結果
+type RefundAttempt struct {
+	Status           RefundStatus // 嘗試後的狀態
+	ProviderRefundID string       // 金流商的退款ID
+	Error            string       // 失敗原因
+	At               time.Time    // 嘗試時間
+	NextAttemptAt    time.Time    // 狀態為待退款時的下次嘗試時間
+}
+
+// RefundPending 查詢於時間 t 可退款的紀錄
+func RefundPending(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND next_attempt_at <= ?", RefundStatusPending, t.UTC())
+	}
+}
+
+// RefundOfOrder 查詢訂單的退款紀錄
+func RefundOfOrder(orderID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("order_id = ?", orderID)
+	}
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+)
+
+// OutboxEvent 待發送的領域事件 (transactional outbox)
+// 與 entity 變更在同一個 transaction 寫入 , 由 relay 發送後標記 PublishedAt
+// 發送失敗時依 NextAttemptAt 重試 , 超過重試次數標記 DeadAt , 不再發送
+type OutboxEvent struct {
+	ID            uint       `gorm:"primarykey"`
+	EventID       string     `gorm:"type:varchar(36);not null;uniqueIndex"` // 事件唯一識別 , 接收端以此去除重複
+	EventType     string     `gorm:"type:varchar(100);not null"`            // 事件類型 , 例如 course.published
+	AggregateType string     `gorm:"type:varchar(50);not null"`             // 事件所屬 entity 類型
+	AggregateID   uint       `gorm:"not null"`                              // 事件所屬 entity ID
+	Payload       string     `gorm:"type:jsonb;not null"`                   // 事件內容 (JSON)
+	Attempts      uint       `gorm:"not null;default:0"`                    // 已發送次數
+	LastError     string     `gorm:"type:text"`                             // 最後一次發送失敗的原因
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_event_pending,where:published_at IS NULL AND dead_at IS NULL"`
+	PublishedAt   *time.Time `gorm:"index"` // 發送成功時間
+	DeadAt        *time.Time // 放棄發送時間
+	CreatedAt     time.Time  `gorm:"not null"`
+}
+
+// NewOutboxEvent 將領域事件轉為 outbox 事件 , 立即可發送
+// 參數: e - 領域事件, now - 事件發生時間
+func NewOutboxEvent(e event.Event, now time.Time) (*OutboxEvent, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", e.EventType(), err)
+	}
+
+	return &OutboxEvent{
+		EventID:       uuid.NewString(),
+		EventType:     e.EventType(),
+		AggregateType: e.AggregateType(),
+		AggregateID:   e.AggregateID(),
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// OutboxEventPending 查詢於時間 t 可發送的事件
+func OutboxEventPending(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", t.UTC())
+	}
+}
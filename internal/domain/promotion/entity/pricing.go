@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/money"
+)
+
+// Checkout 結帳內容 , 用於判斷適用的促銷活動
+type Checkout struct {
+	CourseID  uint
+	Category  string      // 課程分類
+	StudentID uint        // 學生 user ID
+	Price     money.Money // 課程售價
+	Seats     uint        // 課程報名人數 , 含本次報名
+	At        time.Time   // 結帳時間
+}
+
+// AppliedDiscount 套用的折扣
+type AppliedDiscount struct {
+	PromotionID uint
+	Name        string
+	Code        string // 優惠碼 , 自動套用的促銷為空字串
+	Amount      int64  // 折扣金額 , 以售價幣別的最小單位計
+}
+
+// Pricing 套用促銷後的價格
+type Pricing struct {
+	ListPrice money.Money       // 課程售價
+	Discounts []AppliedDiscount // 套用的折扣 , 依套用順序
+	Total     money.Money       // 應付金額
+}
+
+// Discount 折扣總額
+func (p Pricing) Discount() money.Money {
+	return money.Money{Amount: p.ListPrice.Amount - p.Total.Amount, Currency: p.ListPrice.Currency}
+}
+
+// Evaluate 計算結帳內容套用促銷後的價格
+// 自動套用的促銷只取折扣最多的一個 (相同時取 ID 較小的) , 優惠券再以折扣後的金額計算折扣 , 應付金額不低於 0
+// 不適用或沒有折扣的促銷不會出現在 Discounts , 呼叫端可據此判斷優惠券是否已套用
+// 參數: c - 結帳內容, automatic - 自動套用的促銷活動, coupon - 優惠券 , 沒有時為 nil
+// 回傳: 套用促銷後的價格
+func Evaluate(c Checkout, automatic []*Promotion, coupon *Promotion) Pricing {
+	pricing := Pricing{ListPrice: c.Price, Total: c.Price}
+
+	var best *Promotion
+	var bestAmount int64
+	for _, promotion := range automatic {
+		if promotion.IsCoupon() || !promotion.Applies(c) {
+			continue
+		}
+		amount := promotion.Discount(c.Price)
+		if amount > bestAmount || (amount == bestAmount && amount > 0 && promotion.ID < best.ID) {
+			best, bestAmount = promotion, amount
+		}
+	}
+	if best != nil {
+		pricing.apply(best, bestAmount)
+	}
+
+	if coupon != nil && coupon.Applies(c) {
+		if amount := coupon.Discount(pricing.Total); amount > 0 {
+			pricing.apply(coupon, amount)
+		}
+	}
+	return pricing
+}
+
+// apply 套用折扣
+func (p *Pricing) apply(promotion *Promotion, amount int64) {
+	p.Discounts = append(p.Discounts, AppliedDiscount{
+		PromotionID: promotion.ID,
+		Name:        promotion.Name,
+		Code:        promotion.CodeString(),
+		Amount:      amount,
+	})
+	p.Total.Amount -= amount
+}
@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/money"
+)
+
+// Promotion 促銷活動
+// 有優惠碼 (Code) 的為優惠券 , 結帳時輸入優惠碼才套用 , 沒有優惠碼的為自動套用的促銷 , 例如團報優惠
+// UsedCount 為目前有效的使用次數 , 訂單付款失敗或逾期時扣回
+type Promotion struct {
+	gorm.Model
+	Name          string       `gorm:"type:varchar(100);not null"`                                               // 活動名稱
+	Code          *string      `gorm:"type:varchar(50);uniqueIndex:idx_promotion_code,where:deleted_at IS NULL"` // 優惠碼 , 大寫 , nil 表示自動套用
+	DiscountType  DiscountType `gorm:"not null;default:0"`                                                       // 0: 百分比折扣 , 1: 固定金額折抵
+	DiscountValue uint         `gorm:"not null"`                                                                 // 百分比 (1-100) 或折抵金額 (以 Currency 的最小單位計)
+	Currency      string       `gorm:"type:varchar(3);not null;default:''"`                                      // 固定金額折抵的幣別 , 只套用於相同幣別的課程
+	CourseID      uint         `gorm:"not null;default:0;index"`                                                 // 限定課程 , 0 表示不限
+	Category      string       `gorm:"type:varchar(50);not null;default:''"`                                     // 限定課程分類 , 空字串表示不限
+	MinSeats      uint         `gorm:"not null;default:0"`                                                       // 課程報名人數 (含本次) 達此人數才套用 , 0 表示不限
+	UsageLimit    uint         `gorm:"not null;default:0"`                                                       // 總使用次數上限 , 0 表示不限
+	PerUserLimit  uint         `gorm:"not null;default:0"`                                                       // 每位學生使用次數上限 , 0 表示不限
+	UsedCount     uint         `gorm:"not null;default:0"`                                                       // 目前有效的使用次數
+	StartsAt      *time.Time   // 開始時間 , nil 表示不限
+	EndsAt        *time.Time   // 結束時間 (不含) , nil 表示不限
+	DeactivatedAt *time.Time   // 停用時間 , 停用後不再套用
+}
+
+type DiscountType uint
+
+const (
+	DiscountTypePercent DiscountType = iota // 百分比折扣
+	DiscountTypeFixed                       // 固定金額折抵
+)
+
+func (t DiscountType) String() string {
+	switch t {
+	case DiscountTypePercent:
+		return "percent"
+	case DiscountTypeFixed:
+		return "fixed"
+	default:
+		return "unknown"
+	}
+}
+
+// NormalizeCode 優惠碼不分大小寫 , 統一去除前後空白並轉為大寫
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsCoupon 是否為需要輸入優惠碼的優惠券
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != nil
+}
+
+// CodeString 優惠碼 , 自動套用的促銷為空字串
+func (p *Promotion) CodeString() string {
+	if p.Code == nil {
+		return ""
+	}
+	return *p.Code
+}
+
+// ActiveAt 促銷活動於 at 是否有效 , 未停用且在活動期間內
+func (p *Promotion) ActiveAt(at time.Time) bool {
+	return p.DeactivatedAt == nil &&
+		(p.StartsAt == nil || !at.Before(*p.StartsAt)) &&
+		(p.EndsAt == nil || at.Before(*p.EndsAt))
+}
+
+// Exhausted 是否已達總使用次數上限
+func (p *Promotion) Exhausted() bool {
+	return p.UsageLimit > 0 && p.UsedCount >= p.UsageLimit
+}
+
+// Applies 促銷活動是否適用於結帳內容 , 不檢查使用次數
+func (p *Promotion) Applies(c Checkout) bool {
+	if !p.ActiveAt(c.At) {
+		return false
+	}
+	if p.CourseID != 0 && p.CourseID != c.CourseID {
+		return false
+	}
+	if p.Category != "" && p.Category != c.Category {
+		return false
+	}
+	if p.MinSeats > 0 && c.Seats < p.MinSeats {
+		return false
+	}
+	if p.DiscountType == DiscountTypeFixed && p.Currency != c.Price.Currency {
+		return false
+	}
+	return true
+}
+
+// Discount 計算對 price 的折扣金額 , 百分比折扣無條件捨去 , 折扣不超過 price
+// 參數: price - 折扣前的金額
+// 回傳: 折扣金額 , 以 price 幣別的最小單位計
+func (p *Promotion) Discount(price money.Money) int64 {
+	if price.Amount <= 0 {
+		return 0
+	}
+	var discount int64
+	switch p.DiscountType {
+	case DiscountTypePercent:
+		discount = price.Amount * int64(p.DiscountValue) / 100
+	case DiscountTypeFixed:
+		discount = int64(p.DiscountValue)
+	}
+	return min(discount, price.Amount)
+}
+
+// PromotionActive 查詢於時間 t 有效的促銷活動
+func PromotionActive(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("deactivated_at IS NULL AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", t.UTC(), t.UTC())
+	}
+}
+
+// PromotionAutomatic 查詢自動套用 (沒有優惠碼) 的促銷活動
+func PromotionAutomatic() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("code IS NULL")
+	}
+}
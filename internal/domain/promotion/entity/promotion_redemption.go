@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromotionRedemption 促銷活動的使用紀錄 , 建立訂單時新增
+// 訂單付款失敗或逾期時標記為已釋出 , 不再計入使用次數
+type PromotionRedemption struct {
+	gorm.Model
+	PromotionID uint             `gorm:"not null;index:idx_promotion_redemption_student,priority:1"` // 促銷活動ID
+	StudentID   uint             `gorm:"not null;index:idx_promotion_redemption_student,priority:2"` // 學生 user ID
+	OrderID     uint             `gorm:"not null;index"`                                             // 訂單ID
+	Discount    uint             `gorm:"not null"`                                                   // 折扣金額 , 以 Currency 的最小單位計
+	Currency    string           `gorm:"type:varchar(3);not null"`                                   // 幣別 , 與訂單相同
+	Status      RedemptionStatus `gorm:"not null;default:0"`                                         // 0: 有效 , 1: 已釋出
+	ReleasedAt  *time.Time       // 釋出時間
+}
+
+type RedemptionStatus uint
+
+const (
+	RedemptionStatusActive   RedemptionStatus = iota // 有效
+	RedemptionStatusReleased                         // 已釋出
+)
+
+func (s RedemptionStatus) String() string {
+	switch s {
+	case RedemptionStatusActive:
+		return "active"
+	case RedemptionStatusReleased:
+		return "released"
+	default:
+		return "unknown"
+	}
+}
+
+// RedemptionOfStudent 查詢學生對促銷活動的有效使用紀錄
+func RedemptionOfStudent(promotionID, studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("promotion_id = ? AND student_id = ? AND status = ?", promotionID, studentID, RedemptionStatusActive)
+	}
+}
+
+// RedemptionOfOrder 查詢訂單的有效使用紀錄
+func RedemptionOfOrder(orderID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("order_id = ? AND status = ?", orderID, RedemptionStatusActive)
+	}
+}
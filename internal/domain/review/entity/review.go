@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MinRating uint = 1 // 評分下限
+	MaxRating uint = 5 // 評分上限
+)
+
+// ValidRating 評分是否介於 MinRating 與 MaxRating 之間
+func ValidRating(rating uint) bool {
+	return rating >= MinRating && rating <= MaxRating
+}
+
+// Review 學生對已結束課程的評價 , 每筆報名最多一則評價
+// 新增或修改後為待審核 , 審核通過後才計入課程與授課教師的評分統計 , 由 ReviewService 維護
+type Review struct {
+	gorm.Model
+	EnrollmentID   uint         `gorm:"not null;uniqueIndex:idx_review_enrollment_active,where:deleted_at IS NULL"` // 報名ID
+	CourseID       uint         `gorm:"not null;index"`                                                             // 課程ID
+	StudentID      uint         `gorm:"not null;index"`                                                             // 學生 user ID
+	Rating         uint         `gorm:"not null"`                                                                   // 課程評分 , 1 至 5
+	Comment        string       `gorm:"type:text;not null;default:''"`                                              // 評論
+	Status         ReviewStatus `gorm:"not null;default:0"`                                                         // 0: 待審核 , 1: 審核通過 , 2: 審核未通過
+	ModeratorID    uint         `gorm:"not null;default:0"`                                                         // 最後審核的管理者 user ID , 尚未審核時為 0
+	ModeratedAt    *time.Time   // 最後審核時間
+	ModerationNote string       `gorm:"type:varchar(255);not null;default:''"` // 審核備註 , 例如未通過的原因
+}
+
+type ReviewStatus uint
+
+const (
+	ReviewStatusPending  ReviewStatus = iota // 待審核
+	ReviewStatusApproved                     // 審核通過
+	ReviewStatusRejected                     // 審核未通過
+)
+
+// reviewStatusTransitions 審核可轉換的下一個狀態 , 已審核的評價可重新審核
+var reviewStatusTransitions = map[ReviewStatus][]ReviewStatus{
+	ReviewStatusPending:  {ReviewStatusApproved, ReviewStatusRejected},
+	ReviewStatusApproved: {ReviewStatusRejected},
+	ReviewStatusRejected: {ReviewStatusApproved},
+}
+
+// CanTransitionTo 是否可以轉換至 next 狀態
+func (s ReviewStatus) CanTransitionTo(next ReviewStatus) bool {
+	for _, status := range reviewStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ReviewStatus) String() string {
+	switch s {
+	case ReviewStatusPending:
+		return "pending"
+	case ReviewStatusApproved:
+		return "approved"
+	case ReviewStatusRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// ReviewOfCourse 查詢課程的評價
+func ReviewOfCourse(courseID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("course_id = ?", courseID)
+	}
+}
+
+// ReviewOfStudent 查詢學生的評價
+func ReviewOfStudent(studentID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("student_id = ?", studentID)
+	}
+}
+
+// InReviewStatus 查詢狀態
+func InReviewStatus(statuses []ReviewStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+)
+
+// ReviewTeacherRating 評價中對授課教師的評分 , 學生可只評分部分授課教師
+// 隨評價一起審核 , 評價審核通過後才計入教師的評分統計
+type ReviewTeacherRating struct {
+	gorm.Model
+	ReviewID  uint `gorm:"not null;uniqueIndex:idx_review_teacher_rating,where:deleted_at IS NULL,priority:1"`       // 評價ID
+	TeacherID uint `gorm:"not null;uniqueIndex:idx_review_teacher_rating,where:deleted_at IS NULL,priority:2;index"` // 教師ID
+	Rating    uint `gorm:"not null"`                                                                                 // 教師評分 , 1 至 5
+}
@@ -8,12 +8,16 @@ import (
 // 包含教師的基本資料與關聯帳號資訊
 type Teacher struct {
 	gorm.Model
-	UserID uint          `gorm:"not null;uniqueIndex"`                   // 關聯 user（帳號）ID
-	Name   string        `gorm:"type:varchar(100);not null"`             // 教師姓名 , 原則上跟User的Name一樣
-	Phone  string        `gorm:"type:varchar(20);not null;uniqueIndex"`  // 聯絡電話 , 原則上跟User的Phone一樣
-	Email  string        `gorm:"type:varchar(100);not null;uniqueIndex"` // 教師信箱 , 原則上跟User的Email一樣
-	Bio    string        `gorm:"type:text"`                              // 教師簡介/自我介紹
-	Status TeacherStatus `gorm:"not null"`                               // 狀態 , 0: 審核中 , 1: 審核通過 , 2: 審核失敗 , 3: 已停用
+	UserID uint          `gorm:"not null;uniqueIndex:idx_teacher_user_id_active,where:deleted_at IS NULL"`                 // 關聯 user（帳號）ID
+	Name   string        `gorm:"type:varchar(100);not null"`                                                               // 教師姓名 , 原則上跟User的Name一樣
+	Phone  string        `gorm:"type:varchar(20);not null;uniqueIndex:idx_teacher_phone_active,where:deleted_at IS NULL"`  // 聯絡電話 , 原則上跟User的Phone一樣
+	Email  string        `gorm:"type:varchar(100);not null;uniqueIndex:idx_teacher_email_active,where:deleted_at IS NULL"` // 教師信箱 , 原則上跟User的Email一樣
+	Bio    string        `gorm:"type:text"`                                                                                // 教師簡介/自我介紹
+	Status TeacherStatus `gorm:"not null"`                                                                                 // 狀態 , 0: 審核中 , 1: 審核通過 , 2: 審核失敗 , 3: 已停用
+
+	RatingSum     uint    `gorm:"not null;default:0"`       // 審核通過的評價中對教師的評分總和 , 由 ReviewService 維護
+	RatingCount   uint    `gorm:"not null;default:0"`       // 審核通過的評價中對教師的評分數量 , 由 ReviewService 維護
+	RatingAverage float64 `gorm:"not null;default:0;index"` // 平均評分 , 沒有評分時為 0 , 由 ReviewService 維護
 }
 
 type TeacherStatus uint
@@ -25,16 +29,51 @@ const (
 	TeacherStatusDisabled                      // 已停用
 )
 
+func (s TeacherStatus) String() string {
+	switch s {
+	case TeacherStatusPending:
+		return "pending"
+	case TeacherStatusApproved:
+		return "approved"
+	case TeacherStatusRejected:
+		return "rejected"
+	case TeacherStatusDisabled:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
+
+// TeacherSearchVector 教師全文檢索的 tsvector 運算式 , 姓名權重 A , 簡介權重 B
+// migration 以相同運算式建立 GIN index , 查詢時運算式需完全相同才會使用 index
+const TeacherSearchVector = "setweight(to_tsvector('simple', coalesce(name, '')), 'A') || " +
+	"setweight(to_tsvector('simple', coalesce(bio, '')), 'B')"
+
 // 查詢LikeName 查詢LikeName
+// 區分大小寫且無法使用 index , 關鍵字搜尋請使用 TeacherRepository.Search
 func LikeTeacherName(name string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where("name LIKE ?", "%"+name+"%")
 	}
 }
 
+// TeacherOfUser 查詢 user 對應的教師
+func TeacherOfUser(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", userID)
+	}
+}
+
 // 查詢狀態 查詢狀態
 func InTeacherStatus(statuses []TeacherStatus) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where("status IN (?)", statuses)
 	}
 }
+
+// MinTeacherRating 查詢平均評分至少為 rating 的教師 , 依評分排序時以 Find 的 pageInfo.Sort 指定 rating_average
+func MinTeacherRating(rating float64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("rating_average >= ?", rating)
+	}
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookDelivery 事件發送至 webhook 的紀錄
+// 每個訂閱的每個事件只會有一筆 , 記錄發送次數與最後一次的回應 , 失敗時依 NextAttemptAt 重試
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint                  `gorm:"not null;uniqueIndex:idx_webhook_delivery_subscription_event"`                  // 訂閱ID
+	EventID        string                `gorm:"type:varchar(36);not null;uniqueIndex:idx_webhook_delivery_subscription_event"` // 事件唯一識別
+	EventType      string                `gorm:"type:varchar(100);not null"`                                                    // 事件類型
+	Payload        string                `gorm:"type:jsonb;not null"`                                                           // 發送內容 (JSON)
+	Status         WebhookDeliveryStatus `gorm:"not null;default:0"`                                                            // 0: 待發送 , 1: 成功 , 2: 失敗
+	Attempts       uint                  `gorm:"not null;default:0"`                                                            // 已發送次數
+	NextAttemptAt  time.Time             `gorm:"not null;index:idx_webhook_delivery_pending,where:status = 0"`                  // 下次發送時間
+	ResponseCode   int                   `gorm:"not null;default:0"`                                                            // 最後一次的 HTTP 狀態碼 , 0 表示沒有回應
+	ResponseBody   string                `gorm:"type:text"`                                                                     // 最後一次的回應內容 , 只保留開頭
+	LastError      string                `gorm:"type:text"`                                                                     // 最後一次發送失敗的原因
+	DeliveredAt    *time.Time            // 發送成功時間
+}
+
+type WebhookDeliveryStatus uint
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = iota // 待發送 , 包含等待重試
+	WebhookDeliveryStatusSucceeded                              // 發送成功
+	WebhookDeliveryStatusFailed                                 // 超過重試次數 , 可手動重新發送
+)
+
+func (s WebhookDeliveryStatus) String() string {
+	switch s {
+	case WebhookDeliveryStatusPending:
+		return "pending"
+	case WebhookDeliveryStatusSucceeded:
+		return "succeeded"
+	case WebhookDeliveryStatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseWebhookDeliveryStatus 解析狀態名稱
+func ParseWebhookDeliveryStatus(s string) (WebhookDeliveryStatus, bool) {
+	for _, status := range []WebhookDeliveryStatus{WebhookDeliveryStatusPending, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusFailed} {
+		if status.String() == s {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// WebhookAttempt 單次發送的結果
+type WebhookAttempt struct {
+	Status        WebhookDeliveryStatus // 發送後的狀態
+	ResponseCode  int                   // HTTP 狀態碼 , 0 表示沒有回應
+	ResponseBody  string                // 回應內容
+	Error         string                // 失敗原因
+	At            time.Time             // 發送時間
+	NextAttemptAt time.Time             // 狀態為待發送時的下次發送時間
+}
+
+// WebhookDeliveryPending 查詢於時間 t 可發送的紀錄
+func WebhookDeliveryPending(t time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryStatusPending, t.UTC())
+	}
+}
+
+// WebhookDeliveryOfSubscription 查詢訂閱的發送紀錄
+func WebhookDeliveryOfSubscription(subscriptionID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("subscription_id = ?", subscriptionID)
+	}
+}
+
+// InWebhookDeliveryStatus 查詢狀態
+func InWebhookDeliveryStatus(statuses []WebhookDeliveryStatus) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN (?)", statuses)
+	}
+}
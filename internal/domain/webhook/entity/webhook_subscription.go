@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// AllEvents 訂閱所有事件類型
+const AllEvents = "*"
+
+// WebhookSubscription 外部系統訂閱的 webhook
+// 訂閱的事件發生時以 HTTP POST 發送至 URL , 並以 Secret 計算 HMAC 簽章
+// Secret 不記錄稽核紀錄 , 建立後只回傳一次
+type WebhookSubscription struct {
+	gorm.Model
+	URL         string     `gorm:"type:varchar(2048);not null"` // 接收事件的 URL
+	EventTypes  EventTypes `gorm:"type:jsonb;not null"`         // 訂閱的事件類型 , 包含 AllEvents 表示所有事件
+	Secret      string     `gorm:"type:varchar(128);not null"`  // 簽章金鑰
+	Description string     `gorm:"type:varchar(255)"`           // 說明
+}
+
+// Subscribes 是否訂閱事件類型
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType) || slices.Contains(s.EventTypes, AllEvents)
+}
+
+// EventTypes 事件類型清單 , 以 JSON 陣列儲存
+type EventTypes []string
+
+// Value 實作 driver.Valuer
+func (e EventTypes) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(e))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 實作 sql.Scanner
+func (e *EventTypes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported event types type %T", value)
+	}
+	return json.Unmarshal(b, e)
+}
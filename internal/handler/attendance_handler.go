@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	attendanceEntity "github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	attendanceService "github.com/itmrchow/course-management-system/internal/service/attendance"
+)
+
+// maxAttendanceMarks 單次點名的學生數量上限
+const maxAttendanceMarks = 500
+
+// AttendanceHandler 出席紀錄 API
+//
+// Example:
+//
+//	h := handler.NewAttendanceHandler(attendanceSvc)
+//	srv.Handle("PUT /course-sessions/{id}/attendance", h.Mark())
+//	srv.Handle("GET /courses/{id}/attendance-rate", h.CourseRate())
+type AttendanceHandler struct {
+	svc attendanceService.AttendanceService
+}
+
+// MarkAttendanceRequest 批次點名請求 , 點名的教師為 server.UserIDMiddleware 取得的操作者
+type MarkAttendanceRequest struct {
+	Records []AttendanceMarkRecord `json:"records"`
+}
+
+// AttendanceMarkRecord 單一學生的點名結果
+type AttendanceMarkRecord struct {
+	StudentID uint   `json:"student_id"`
+	Status    string `json:"status"` // present / absent / late / excused
+	Note      string `json:"note"`
+}
+
+// AttendanceResponse 出席紀錄回應
+type AttendanceResponse struct {
+	StudentID    uint      `json:"student_id"`
+	EnrollmentID uint      `json:"enrollment_id"`
+	Status       string    `json:"status"`
+	Note         string    `json:"note"`
+	MarkedBy     uint      `json:"marked_by"`
+	MarkedAt     time.Time `json:"marked_at"`
+}
+
+// SessionAttendanceResponse 上課的點名結果回應 , 尚未點名的學生沒有紀錄
+type SessionAttendanceResponse struct {
+	SessionID uint                 `json:"session_id"`
+	CourseID  uint                 `json:"course_id"`
+	StartAt   time.Time            `json:"start_at"`
+	EndAt     time.Time            `json:"end_at"`
+	LocksAt   time.Time            `json:"locks_at"`
+	Locked    bool                 `json:"locked"`
+	Data      []AttendanceResponse `json:"data"`
+}
+
+// AttendanceRateResponse 出席率回應 , rate 為 (present + late) / (present + late + absent) , 請假不計入
+type AttendanceRateResponse struct {
+	StudentID uint    `json:"student_id,omitempty"`
+	Present   int64   `json:"present"`
+	Late      int64   `json:"late"`
+	Absent    int64   `json:"absent"`
+	Excused   int64   `json:"excused"`
+	Total     int64   `json:"total"`
+	Rate      float64 `json:"rate"`
+}
+
+// CourseAttendanceRateResponse 課程的出席率回應
+type CourseAttendanceRateResponse struct {
+	CourseID uint                     `json:"course_id"`
+	Overall  AttendanceRateResponse   `json:"overall"`
+	Students []AttendanceRateResponse `json:"students"`
+}
+
+// NewAttendanceHandler 建立出席紀錄 API
+// 參數: svc - 出席紀錄業務邏輯
+func NewAttendanceHandler(svc attendanceService.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{svc: svc}
+}
+
+// ListBySession 查詢上課的點名結果
+// 參數 (path): id - 上課ID
+func (h *AttendanceHandler) ListBySession() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		result, err := h.svc.ListBySession(r.Context(), sessionID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "session not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newSessionAttendanceResponse(result))
+		}
+	})
+}
+
+// Mark 批次點名 , 學生已有紀錄時更新 , 回傳點名後的結果
+// 未登入回傳 401 , 操作者不是授課教師回傳 403 , 學生未報名回傳 422 , 上課已取消、尚未開始或已鎖定回傳 409
+// 參數 (path): id - 上課ID
+// 請求: MarkAttendanceRequest
+func (h *AttendanceHandler) Mark() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		userID, ok := requireUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req MarkAttendanceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		marks, err := req.marks()
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		result, err := h.svc.Mark(r.Context(), sessionID, userID, marks)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "session not found"})
+		case errors.Is(err, attendanceService.ErrNotCourseTeacher):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, attendanceService.ErrNotEnrolled):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, attendanceService.ErrSessionCancelled),
+			errors.Is(err, attendanceService.ErrSessionNotStarted),
+			errors.Is(err, attendanceService.ErrAttendanceLocked):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newSessionAttendanceResponse(result))
+		}
+	})
+}
+
+// CourseRate 查詢課程整體與各學生的出席率
+// 參數 (path): id - 課程ID
+func (h *AttendanceHandler) CourseRate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		result, err := h.svc.CourseRate(r.Context(), courseID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			resp := CourseAttendanceRateResponse{
+				CourseID: result.CourseID,
+				Overall:  newAttendanceRateResponse(&result.Overall),
+				Students: make([]AttendanceRateResponse, 0, len(result.Students)),
+			}
+			for i := range result.Students {
+				resp.Students = append(resp.Students, newAttendanceRateResponse(&result.Students[i]))
+			}
+			writeJSON(w, http.StatusOK, resp)
+		}
+	})
+}
+
+// StudentRate 查詢學生於課程的出席率
+// 參數 (path): id - 課程ID, student_id - 學生 user ID
+func (h *AttendanceHandler) StudentRate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, err := parsePathID(r, "student_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		rate, err := h.svc.StudentRate(r.Context(), courseID, studentID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newAttendanceRateResponse(rate))
+		}
+	})
+}
+
+// marks 驗證並轉換點名請求 , 每位學生只能出現一次
+func (req *MarkAttendanceRequest) marks() ([]attendanceService.Mark, error) {
+	if len(req.Records) == 0 || len(req.Records) > maxAttendanceMarks {
+		return nil, fmt.Errorf("records must contain 1 to %d students", maxAttendanceMarks)
+	}
+
+	marks := make([]attendanceService.Mark, 0, len(req.Records))
+	seen := make(map[uint]bool, len(req.Records))
+	for i, record := range req.Records {
+		if record.StudentID == 0 {
+			return nil, fmt.Errorf("records[%d].student_id is required", i)
+		}
+		if seen[record.StudentID] {
+			return nil, fmt.Errorf("records[%d]: duplicated student_id %d", i, record.StudentID)
+		}
+		seen[record.StudentID] = true
+		status, ok := attendanceEntity.ParseAttendanceStatus(record.Status)
+		if !ok {
+			return nil, fmt.Errorf("records[%d]: invalid status %q", i, record.Status)
+		}
+		if len([]rune(record.Note)) > 255 {
+			return nil, fmt.Errorf("records[%d].note must be at most 255 characters", i)
+		}
+		marks = append(marks, attendanceService.Mark{StudentID: record.StudentID, Status: status, Note: record.Note})
+	}
+	return marks, nil
+}
+
+func newSessionAttendanceResponse(result *attendanceService.SessionAttendance) SessionAttendanceResponse {
+	resp := SessionAttendanceResponse{
+		SessionID: result.Session.ID,
+		CourseID:  result.Session.CourseID,
+		StartAt:   result.Session.StartAt,
+		EndAt:     result.Session.EndAt,
+		LocksAt:   result.LocksAt,
+		Locked:    result.Locked,
+		Data:      make([]AttendanceResponse, 0, len(result.Attendances)),
+	}
+	for _, a := range result.Attendances {
+		resp.Data = append(resp.Data, AttendanceResponse{
+			StudentID:    a.StudentID,
+			EnrollmentID: a.EnrollmentID,
+			Status:       a.Status.String(),
+			Note:         a.Note,
+			MarkedBy:     a.MarkedBy,
+			MarkedAt:     a.MarkedAt,
+		})
+	}
+	return resp
+}
+
+func newAttendanceRateResponse(rate *attendanceEntity.AttendanceRate) AttendanceRateResponse {
+	return AttendanceRateResponse{
+		StudentID: rate.StudentID,
+		Present:   rate.Present,
+		Late:      rate.Late,
+		Absent:    rate.Absent,
+		Excused:   rate.Excused,
+		Total:     rate.Total(),
+		Rate:      rate.Rate(),
+	}
+}
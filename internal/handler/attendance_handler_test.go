@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	attendanceRepository "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/server"
+	attendanceService "github.com/itmrchow/course-management-system/internal/service/attendance"
+)
+
+type AttendanceHandlerTestSuite struct {
+	suite.Suite
+	handler http.Handler
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由
+// 課程 1 由教師 1 (user 100) 授課 , 教師 2 (user 101) 不是授課教師 , 學生 10、11 已報名 ; 上課 1 正在進行 , 上課 2 已於 10 天前結束並鎖定
+func (s *AttendanceHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusOnline})
+	s.Require().NoError(err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	s.Require().NoError(err)
+
+	teachers := teacherRepository.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 100, Name: "王老師", Phone: "0911000100", Email: "wang@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 101, Name: "李老師", Phone: "0911000101", Email: "lee@example.com", Status: teacherEntity.TeacherStatusApproved},
+	} {
+		_, err := teachers.Create(ctx, teacher)
+		s.Require().NoError(err)
+	}
+
+	enrollments := enrollmentRepository.NewEnrollmentMemoryRepository()
+	for _, studentID := range []uint{10, 11} {
+		_, err := enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: studentID, Status: enrollmentEntity.EnrollmentStatusEnrolled})
+		s.Require().NoError(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	sessions := sessionRepository.NewSessionMemoryRepository()
+	_, err = sessions.Ensure(ctx,
+		&courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)},
+		&courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: now.AddDate(0, 0, -10), EndAt: now.AddDate(0, 0, -10).Add(2 * time.Hour)},
+	)
+	s.Require().NoError(err)
+
+	h := NewAttendanceHandler(attendanceService.NewAttendanceService(attendanceRepository.NewAttendanceMemoryRepository(), sessions, courses, enrollments, teachers,
+		attendanceService.Config{LockAfter: 72 * time.Hour}))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /course-sessions/{id}/attendance", h.ListBySession())
+	mux.Handle("PUT /course-sessions/{id}/attendance", h.Mark())
+	mux.Handle("GET /courses/{id}/attendance-rate", h.CourseRate())
+	mux.Handle("GET /courses/{id}/students/{student_id}/attendance-rate", h.StudentRate())
+	s.handler = server.UserIDMiddleware()(mux)
+}
+
+// TestAttendanceHandlerSuite 執行測試套件
+func TestAttendanceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AttendanceHandlerTestSuite))
+}
+
+// serve 以 userID 為操作者發送請求 , userID 為 0 表示匿名請求
+func (s *AttendanceHandlerTestSuite) serve(method, target, body string, userID uint) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if userID != 0 {
+		req.Header.Set(server.HeaderUserID, strconv.FormatUint(uint64(userID), 10))
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *AttendanceHandlerTestSuite) TestMark() {
+	tests := []struct {
+		name       string
+		target     string
+		userID     uint
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success",
+			target: "/course-sessions/1/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 10, "status": "present"}, {"student_id": 11, "status": "late", "note": "塞車"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp SessionAttendanceResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.False(t, resp.Locked)
+				require.Len(t, resp.Data, 2)
+				assert.Equal(t, "present", resp.Data[0].Status)
+				assert.EqualValues(t, 1, resp.Data[0].MarkedBy)
+				assert.Equal(t, "late", resp.Data[1].Status)
+				assert.Equal(t, "塞車", resp.Data[1].Note)
+			},
+		},
+		{
+			name:   "invalid status",
+			target: "/course-sessions/1/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 10, "status": "sleeping"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "duplicated student",
+			target: "/course-sessions/1/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 10, "status": "present"}, {"student_id": 10, "status": "absent"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "anonymous",
+			target: "/course-sessions/1/attendance",
+			body:   `{"records": [{"student_id": 10, "status": "present"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name:   "not a course teacher",
+			target: "/course-sessions/1/attendance",
+			userID: 101,
+			body:   `{"records": [{"student_id": 10, "status": "present"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+			},
+		},
+		{
+			name:   "student not enrolled",
+			target: "/course-sessions/1/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 99, "status": "present"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:   "locked",
+			target: "/course-sessions/2/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 10, "status": "present"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name:   "session not found",
+			target: "/course-sessions/100/attendance",
+			userID: 100,
+			body:   `{"records": [{"student_id": 10, "status": "present"}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPut, tt.target, tt.body, tt.userID))
+		})
+	}
+}
+
+func (s *AttendanceHandlerTestSuite) TestListBySession() {
+	rec := s.serve(http.MethodGet, "/course-sessions/2/attendance", "", 0)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp SessionAttendanceResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.True(resp.Locked)
+	s.Empty(resp.Data)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/course-sessions/100/attendance", "", 0).Code)
+}
+
+func (s *AttendanceHandlerTestSuite) TestRate() {
+	rec := s.serve(http.MethodPut, "/course-sessions/1/attendance",
+		`{"records": [{"student_id": 10, "status": "present"}, {"student_id": 11, "status": "absent"}]}`, 100)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	rec = s.serve(http.MethodGet, "/courses/1/attendance-rate", "", 0)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var course CourseAttendanceRateResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &course))
+	s.EqualValues(2, course.Overall.Total)
+	s.InDelta(0.5, course.Overall.Rate, 0.0001)
+	s.Require().Len(course.Students, 2)
+	s.InDelta(1.0, course.Students[0].Rate, 0.0001)
+
+	rec = s.serve(http.MethodGet, "/courses/1/students/11/attendance-rate", "", 0)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var student AttendanceRateResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &student))
+	s.EqualValues(11, student.StudentID)
+	s.EqualValues(1, student.Absent)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/courses/100/attendance-rate", "", 0).Code)
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/courses/1/students/abc/attendance-rate", "", 0).Code)
+}
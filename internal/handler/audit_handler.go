@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	auditRepository "github.com/itmrchow/course-management-system/internal/repository/audit"
+)
+
+// AuditHandler 稽核紀錄查詢 API
+//
+// Example:
+//
+//	h := handler.NewAuditHandler(auditRepo)
+//	srv.Handle("GET /audit-logs", h.List())
+type AuditHandler struct {
+	repo auditRepository.AuditLogRepository
+}
+
+// AuditLogResponse 稽核紀錄回應
+type AuditLogResponse struct {
+	ID         uint                  `json:"id"`
+	ActorID    *uint                 `json:"actor_id"`
+	EntityType string                `json:"entity_type"`
+	EntityID   uint                  `json:"entity_id"`
+	Operation  auditEntity.Operation `json:"operation"`
+	Changes    auditEntity.Changes   `json:"changes"`
+	RequestID  string                `json:"request_id,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+// NewAuditHandler 建立稽核紀錄查詢 API
+// 參數: repo - 稽核紀錄 repository
+func NewAuditHandler(repo auditRepository.AuditLogRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// List 查詢稽核紀錄 , 依時間由新到舊排序
+// 參數 (query string):
+//   - entity_type , entity_id : entity 類型 (table 名稱) 與 ID , entity_id 須搭配 entity_type
+//   - actor_id : 操作者 user ID
+//   - from , to : 時間區間 [from, to) , RFC3339 格式
+//   - page , page_size , order : 分頁與排序方向 (asc / desc)
+func (h *AuditHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, conditions, err := parseAuditQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		logs, err := h.repo.Find(r.Context(), pageInfo, conditions)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]AuditLogResponse, 0, len(logs))
+		for _, log := range logs {
+			data = append(data, AuditLogResponse{
+				ID:         log.ID,
+				ActorID:    log.ActorID,
+				EntityType: log.EntityType,
+				EntityID:   log.EntityID,
+				Operation:  log.Operation,
+				Changes:    log.Changes,
+				RequestID:  log.RequestID,
+				CreatedAt:  log.CreatedAt,
+			})
+		}
+		writeJSON(w, http.StatusOK, ListResponse[AuditLogResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// parseAuditQuery 解析查詢參數為分頁資訊與查詢條件
+func parseAuditQuery(r *http.Request) (*repo.RepoPageInfo, []func(db *gorm.DB) *gorm.DB, error) {
+	pageInfo, err := parsePageInfo(r, "created_at")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := r.URL.Query()
+	var conditions []func(db *gorm.DB) *gorm.DB
+
+	entityType := query.Get("entity_type")
+	entityID, err := parseUint(r, "entity_id")
+	if err != nil {
+		return nil, nil, err
+	}
+	if entityID != 0 && entityType == "" {
+		return nil, nil, fmt.Errorf("entity_id requires entity_type")
+	}
+	if entityType != "" {
+		conditions = append(conditions, auditEntity.AuditLogOfEntity(entityType, entityID))
+	}
+
+	actorID, err := parseUint(r, "actor_id")
+	if err != nil {
+		return nil, nil, err
+	}
+	if actorID != 0 {
+		conditions = append(conditions, auditEntity.AuditLogByActor(actorID))
+	}
+
+	var from, to time.Time
+	if v := query.Get("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, nil, fmt.Errorf("invalid from %q , must be RFC3339", v)
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, nil, fmt.Errorf("invalid to %q , must be RFC3339", v)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, nil, fmt.Errorf("from must be before to")
+	}
+	if !from.IsZero() || !to.IsZero() {
+		conditions = append(conditions, auditEntity.AuditLogBetween(from, to))
+	}
+
+	return pageInfo, conditions, nil
+}
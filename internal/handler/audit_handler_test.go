@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// stubAuditLogRepo 記錄查詢參數並回傳固定結果
+type stubAuditLogRepo struct {
+	pageInfo   *repo.RepoPageInfo
+	conditions []func(db *gorm.DB) *gorm.DB
+	logs       []*auditEntity.AuditLog
+	err        error
+}
+
+func (r *stubAuditLogRepo) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*auditEntity.AuditLog, error) {
+	r.pageInfo = pageInfo
+	r.conditions = conditions
+	return r.logs, r.err
+}
+
+// Test for AuditHandler.List
+func TestAuditHandlerList(t *testing.T) {
+	actorID := uint(99)
+	createdAt := time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		stub       *stubAuditLogRepo
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo)
+	}{
+		{
+			name:  "default page info without conditions",
+			query: "",
+			stub: &stubAuditLogRepo{logs: []*auditEntity.AuditLog{{
+				ID: 1, ActorID: &actorID, EntityType: "course", EntityID: 2, Operation: auditEntity.OperationUpdate,
+				Changes: auditEntity.Changes{"price": {Old: 3000, New: 3500}}, RequestID: "req-1", CreatedAt: createdAt,
+			}}},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, &repo.RepoPageInfo{Page: 1, PageSize: defaultPageSize, Sort: "created_at", Order: "desc"}, stub.pageInfo)
+				assert.Empty(t, stub.conditions)
+				assert.JSONEq(t, `{
+					"data": [{
+						"id": 1, "actor_id": 99, "entity_type": "course", "entity_id": 2, "operation": "update",
+						"changes": {"price": {"old": 3000, "new": 3500}}, "request_id": "req-1", "created_at": "2025-07-01T08:00:00Z"
+					}],
+					"page": 1,
+					"page_size": 20
+				}`, rec.Body.String())
+			},
+		},
+		{
+			name:  "all filters",
+			query: "?entity_type=course&entity_id=2&actor_id=99&from=2025-07-01T00:00:00%2B08:00&to=2025-08-01T00:00:00Z&page=2&page_size=50&order=asc",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.JSONEq(t, `{"data": [], "page": 2, "page_size": 50}`, rec.Body.String())
+				assert.Equal(t, &repo.RepoPageInfo{Page: 2, PageSize: 50, Sort: "created_at", Order: "asc"}, stub.pageInfo)
+				assert.Equal(t,
+					`SELECT * FROM "audit_log" WHERE entity_type = 'course' AND entity_id = 2 AND actor_id = 99 AND created_at >= '2025-06-30 16:00:00' AND created_at < '2025-08-01 00:00:00'`,
+					toSQL(t, stub.conditions))
+			},
+		},
+		{
+			name:  "entity type only",
+			query: "?entity_type=teacher",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, `SELECT * FROM "audit_log" WHERE entity_type = 'teacher'`, toSQL(t, stub.conditions))
+			},
+		},
+		{
+			name:  "entity id without entity type",
+			query: "?entity_id=2",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Nil(t, stub.pageInfo)
+			},
+		},
+		{
+			name:  "invalid time",
+			query: "?from=2025-07-01",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.JSONEq(t, `{"error": "invalid from \"2025-07-01\" , must be RFC3339"}`, rec.Body.String())
+			},
+		},
+		{
+			name:  "from after to",
+			query: "?from=2025-08-01T00:00:00Z&to=2025-07-01T00:00:00Z",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:  "invalid page size",
+			query: "?page_size=1000",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:  "invalid order",
+			query: "?order=random()",
+			stub:  &stubAuditLogRepo{},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:  "repository error",
+			query: "",
+			stub:  &stubAuditLogRepo{err: errors.New("connection refused")},
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, stub *stubAuditLogRepo) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+				var resp ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.NotContains(t, resp.Error, "connection refused")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewAuditHandler(test.stub).List().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit-logs"+test.query, nil))
+			test.assertFunc(t, rec, test.stub)
+		})
+	}
+}
+
+// toSQL 以 DryRun 產生查詢條件的 SQL
+func toSQL(t *testing.T, conditions []func(db *gorm.DB) *gorm.DB) string {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		NamingStrategy:       schema.NamingStrategy{SingularTable: true},
+	})
+	require.NoError(t, err)
+
+	return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var logs []*auditEntity.AuditLog
+		return tx.Scopes(conditions...).Find(&logs)
+	})
+}
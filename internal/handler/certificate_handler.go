@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/certificate"
+	certificateEntity "github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	certificateService "github.com/itmrchow/course-management-system/internal/service/certificate"
+)
+
+// CertificateHandler 結業證書 API
+//
+// Example:
+//
+//	h := handler.NewCertificateHandler(certificateSvc)
+//	srv.Handle("GET /certificates/{id}/pdf", h.PDF())
+//	srv.Handle("GET /certificates/verify", h.Verify())
+type CertificateHandler struct {
+	svc certificateService.CertificateService
+}
+
+// RevokeCertificateRequest 撤銷證書請求
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason"` // 撤銷原因 , 必填
+}
+
+// CertificateResponse 證書回應
+type CertificateResponse struct {
+	ID             uint       `json:"id"`
+	Code           string     `json:"code"`
+	CourseID       uint       `json:"course_id"`
+	StudentID      uint       `json:"student_id"`
+	EnrollmentID   uint       `json:"enrollment_id"`
+	CourseName     string     `json:"course_name"`
+	TeacherName    string     `json:"teacher_name"`
+	AttendanceRate float64    `json:"attendance_rate"`
+	Status         string     `json:"status"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	RevokeReason   string     `json:"revoke_reason"`
+}
+
+// CertificateVerificationResponse 公開驗證的回應 , 不包含出席率與撤銷原因
+type CertificateVerificationResponse struct {
+	Code        string     `json:"code"`
+	Valid       bool       `json:"valid"` // 證書是否有效 , 已撤銷時為 false
+	Status      string     `json:"status"`
+	StudentID   uint       `json:"student_id"`
+	CourseName  string     `json:"course_name"`
+	TeacherName string     `json:"teacher_name"`
+	IssuedAt    time.Time  `json:"issued_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NewCertificateHandler 建立結業證書 API
+// 參數: svc - 結業證書業務邏輯
+func NewCertificateHandler(svc certificateService.CertificateService) *CertificateHandler {
+	return &CertificateHandler{svc: svc}
+}
+
+// List 查詢證書清單 , 依發放時間排序
+// 參數 (query): course_id - 課程ID, student_id - 學生 user ID, page, page_size, order
+func (h *CertificateHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "issued_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		courseID, err := parseUint(r, "course_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, err := parseUint(r, "student_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		certificates, err := h.svc.List(r.Context(), pageInfo, courseID, studentID)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]CertificateResponse, 0, len(certificates))
+		for _, c := range certificates {
+			data = append(data, newCertificateResponse(c))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[CertificateResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Get 查詢證書
+// 參數 (path): id - 證書ID
+func (h *CertificateHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		c, err := h.svc.Get(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "certificate not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newCertificateResponse(c))
+		}
+	})
+}
+
+// Issue 為已結束的課程發放證書 , 課程結束時會自動發放 , 用於調整門檻或補發
+// 回傳本次發放的證書 , 課程尚未結束回傳 409
+// 參數 (path): id - 課程ID
+func (h *CertificateHandler) Issue() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		issued, err := h.svc.IssueForCourse(r.Context(), courseID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case errors.Is(err, certificateService.ErrCourseNotEnded):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			data := make([]CertificateResponse, 0, len(issued))
+			for _, c := range issued {
+				data = append(data, newCertificateResponse(c))
+			}
+			writeJSON(w, http.StatusOK, ListResponse[CertificateResponse]{Data: data, Page: 1, PageSize: len(data)})
+		}
+	})
+}
+
+// Revoke 撤銷證書 , 已撤銷的證書回傳 409
+// 參數 (path): id - 證書ID
+// 請求: RevokeCertificateRequest
+func (h *CertificateHandler) Revoke() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req RevokeCertificateRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.Reason == "" {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "reason is required"})
+			return
+		}
+		if len([]rune(req.Reason)) > 255 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "reason must be at most 255 characters"})
+			return
+		}
+
+		c, err := h.svc.Revoke(r.Context(), id, req.Reason)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "certificate not found"})
+		case errors.Is(err, certificateService.ErrCertificateRevoked):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "certificate already revoked"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newCertificateResponse(c))
+		}
+	})
+}
+
+// PDF 輸出證書 PDF , 已撤銷的證書回傳 409 , 不支援的語系回傳 400
+// 參數 (path): id - 證書ID
+// 參數 (query): locale - 語系 , 預設為 zh-TW
+func (h *CertificateHandler) PDF() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		locale := r.URL.Query().Get("locale")
+		if locale == "" {
+			locale = notificationEntity.DefaultLocale
+		}
+
+		b, err := h.svc.Render(r.Context(), id, locale)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "certificate not found"})
+		case errors.Is(err, certificateService.ErrCertificateRevoked):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "certificate revoked"})
+		case errors.Is(err, certificate.ErrUnsupportedLocale):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("unsupported locale %q", locale)})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="certificate-%d.pdf"`, id))
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(b)
+		}
+	})
+}
+
+// Verify 公開驗證證書 , 已撤銷的證書同樣回傳 200 , 以 valid 表示是否有效
+// 驗證碼格式不符回傳 400 , 不存在回傳 404
+// 參數 (query): code - 驗證碼 , 不分大小寫 , 可省略連字號
+func (h *CertificateHandler) Verify() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := h.svc.Verify(r.Context(), r.URL.Query().Get("code"))
+		switch {
+		case errors.Is(err, certificateService.ErrInvalidCode):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid certificate code"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "certificate not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			resp := CertificateVerificationResponse{
+				Code:        c.Code,
+				Valid:       c.Status == certificateEntity.CertificateStatusValid,
+				Status:      c.Status.String(),
+				StudentID:   c.StudentID,
+				CourseName:  c.CourseName,
+				TeacherName: c.TeacherName,
+				IssuedAt:    c.IssuedAt,
+				RevokedAt:   c.RevokedAt,
+			}
+			writeJSON(w, http.StatusOK, resp)
+		}
+	})
+}
+
+func newCertificateResponse(c *certificateEntity.Certificate) CertificateResponse {
+	return CertificateResponse{
+		ID:             c.ID,
+		Code:           c.Code,
+		CourseID:       c.CourseID,
+		StudentID:      c.StudentID,
+		EnrollmentID:   c.EnrollmentID,
+		CourseName:     c.CourseName,
+		TeacherName:    c.TeacherName,
+		AttendanceRate: c.AttendanceRate,
+		Status:         c.Status.String(),
+		IssuedAt:       c.IssuedAt,
+		RevokedAt:      c.RevokedAt,
+		RevokeReason:   c.RevokeReason,
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itmrchow/course-management-system/internal/certificate"
+	attendanceEntity "github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	attendanceRepository "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	certificateRepository "github.com/itmrchow/course-management-system/internal/repository/certificate"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	certificateService "github.com/itmrchow/course-management-system/internal/service/certificate"
+)
+
+type CertificateHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由
+// 課程 1 已結束且只有一次上課 , 主教師為王小明 , 學生 10 出席 , 學生 11 缺席 ; 課程 2 開放報名中
+func (s *CertificateHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	teachers := teacherRepository.NewTeacherMemoryRepository()
+	_, err := teachers.Create(ctx, &teacherEntity.Teacher{UserID: 1, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved})
+	s.Require().NoError(err)
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusEnd})
+	s.Require().NoError(err)
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Status: courseEntity.CourseStatusOnline})
+	s.Require().NoError(err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	s.Require().NoError(err)
+
+	sessions := sessionRepository.NewSessionMemoryRepository()
+	start := time.Now().AddDate(0, 0, -7)
+	_, err = sessions.Ensure(ctx, &courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: start, EndAt: start.Add(2 * time.Hour)})
+	s.Require().NoError(err)
+
+	enrollments := enrollmentRepository.NewEnrollmentMemoryRepository()
+	attendances := attendanceRepository.NewAttendanceMemoryRepository()
+	for i, status := range []attendanceEntity.AttendanceStatus{attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusAbsent} {
+		studentID := uint(10 + i)
+		enrollmentID, err := enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: studentID, Status: enrollmentEntity.EnrollmentStatusEnrolled})
+		s.Require().NoError(err)
+		s.Require().NoError(attendances.Upsert(ctx, []*attendanceEntity.Attendance{
+			{SessionID: 1, StudentID: studentID, CourseID: 1, EnrollmentID: enrollmentID, Status: status, MarkedBy: 1, MarkedAt: time.Now()},
+		}))
+	}
+
+	svc := certificateService.NewCertificateService(certificateRepository.NewCertificateMemoryRepository(), courses, teachers, enrollments, attendances, sessions,
+		outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction, certificate.NewRenderer(time.UTC),
+		certificateService.Config{MinAttendanceRate: 0.8, Issuer: "課程管理系統", Location: time.UTC})
+
+	h := NewCertificateHandler(svc)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /certificates", h.List())
+	s.mux.Handle("GET /certificates/{id}", h.Get())
+	s.mux.Handle("GET /certificates/{id}/pdf", h.PDF())
+	s.mux.Handle("POST /certificates/{id}/revoke", h.Revoke())
+	s.mux.Handle("GET /certificates/verify", h.Verify())
+	s.mux.Handle("POST /courses/{id}/certificates", h.Issue())
+}
+
+// TestCertificateHandlerSuite 執行測試套件
+func TestCertificateHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CertificateHandlerTestSuite))
+}
+
+func (s *CertificateHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// issue 發放課程 1 的證書 , 只有學生 10 達到門檻
+func (s *CertificateHandlerTestSuite) issue() CertificateResponse {
+	rec := s.serve(http.MethodPost, "/courses/1/certificates", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp ListResponse[CertificateResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp.Data, 1)
+	return resp.Data[0]
+}
+
+func (s *CertificateHandlerTestSuite) TestIssue() {
+	c := s.issue()
+	s.EqualValues(10, c.StudentID)
+	s.Equal("Go 入門", c.CourseName)
+	s.Equal("王小明", c.TeacherName)
+	s.Equal("valid", c.Status)
+
+	// 重複呼叫不會重複發放
+	rec := s.serve(http.MethodPost, "/courses/1/certificates", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.JSONEq(`{"data": [], "page": 1, "page_size": 0}`, rec.Body.String())
+
+	s.Equal(http.StatusConflict, s.serve(http.MethodPost, "/courses/2/certificates", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPost, "/courses/100/certificates", "").Code)
+
+	rec = s.serve(http.MethodGet, "/certificates?course_id=1", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var list ListResponse[CertificateResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &list))
+	s.Len(list.Data, 1)
+
+	s.Equal(http.StatusOK, s.serve(http.MethodGet, "/certificates/1", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/certificates/100", "").Code)
+}
+
+func (s *CertificateHandlerTestSuite) TestVerify() {
+	c := s.issue()
+
+	tests := []struct {
+		name       string
+		code       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "valid",
+			code: strings.ToLower(c.Code),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp CertificateVerificationResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.True(t, resp.Valid)
+				assert.Equal(t, c.Code, resp.Code)
+				assert.Equal(t, "Go 入門", resp.CourseName)
+				assert.NotContains(t, rec.Body.String(), "attendance_rate")
+			},
+		},
+		{
+			name: "invalid code",
+			code: "abc",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "not found",
+			code: "0000-0000-0000",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodGet, "/certificates/verify?code="+url.QueryEscape(tt.code), ""))
+		})
+	}
+}
+
+func (s *CertificateHandlerTestSuite) TestRevoke() {
+	c := s.issue()
+
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodPost, "/certificates/1/revoke", `{"reason": "  "}`).Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPost, "/certificates/100/revoke", `{"reason": "出席紀錄有誤"}`).Code)
+
+	rec := s.serve(http.MethodPost, "/certificates/1/revoke", `{"reason": "出席紀錄有誤"}`)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp CertificateResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("revoked", resp.Status)
+	s.Equal("出席紀錄有誤", resp.RevokeReason)
+	s.NotNil(resp.RevokedAt)
+
+	s.Equal(http.StatusConflict, s.serve(http.MethodPost, "/certificates/1/revoke", `{"reason": "重複撤銷"}`).Code)
+
+	// 撤銷後驗證顯示為無效 , 不再提供 PDF
+	rec = s.serve(http.MethodGet, "/certificates/verify?code="+c.Code, "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var verification CertificateVerificationResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &verification))
+	s.False(verification.Valid)
+	s.Equal("revoked", verification.Status)
+	s.NotContains(rec.Body.String(), "出席紀錄有誤")
+
+	s.Equal(http.StatusConflict, s.serve(http.MethodGet, "/certificates/1/pdf", "").Code)
+}
+
+func (s *CertificateHandlerTestSuite) TestPDF() {
+	s.issue()
+
+	rec := s.serve(http.MethodGet, "/certificates/1/pdf?locale=en", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Equal("application/pdf", rec.Header().Get("Content-Type"))
+	s.Equal(`inline; filename="certificate-1.pdf"`, rec.Header().Get("Content-Disposition"))
+	s.True(strings.HasPrefix(rec.Body.String(), "%PDF-"))
+
+	s.Equal(http.StatusOK, s.serve(http.MethodGet, "/certificates/1/pdf", "").Code)
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/certificates/1/pdf?locale=ja", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/certificates/100/pdf", "").Code)
+}
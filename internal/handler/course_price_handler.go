@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/money"
+	courseService "github.com/itmrchow/course-management-system/internal/service/course"
+)
+
+// priceTiers 價格級距名稱
+var priceTiers = []courseEntity.PriceTier{courseEntity.PriceTierRegular, courseEntity.PriceTierEarlyBird}
+
+// CoursePriceHandler 課程價格 API
+//
+// Example:
+//
+//	h := handler.NewCoursePriceHandler(courseSvc)
+//	srv.Handle("POST /courses/{id}/prices", h.Create())
+//	srv.Handle("GET /courses/{id}/price", h.Quote())
+type CoursePriceHandler struct {
+	svc courseService.CourseService
+	now func() time.Time
+}
+
+// CreateCoursePriceRequest 新增課程價格請求
+type CreateCoursePriceRequest struct {
+	Tier          string     `json:"tier"`           // regular / early_bird
+	Amount        uint       `json:"amount"`         // 以幣別的最小單位計
+	Currency      string     `json:"currency"`       // ISO 4217 , 須與課程幣別相同
+	EffectiveFrom *time.Time `json:"effective_from"` // 生效時間 , 未設定時為現在
+	ValidUntil    *time.Time `json:"valid_until"`    // 早鳥價截止時間
+}
+
+// CoursePriceResponse 課程價格紀錄回應
+type CoursePriceResponse struct {
+	ID            uint        `json:"id"`
+	CourseID      uint        `json:"course_id"`
+	Tier          string      `json:"tier"`
+	Price         money.Money `json:"price"`
+	Display       string      `json:"display"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	ValidUntil    *time.Time  `json:"valid_until"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// CoursePriceListResponse 課程價格歷史回應 , 不分頁
+type CoursePriceListResponse struct {
+	Data []CoursePriceResponse `json:"data"`
+}
+
+// PriceQuoteResponse 課程售價回應
+type PriceQuoteResponse struct {
+	CourseID   uint        `json:"course_id"`
+	At         time.Time   `json:"at"`
+	Tier       string      `json:"tier"`
+	Price      money.Money `json:"price"`
+	Display    string      `json:"display"`
+	PriceID    uint        `json:"price_id"`
+	ValidUntil *time.Time  `json:"valid_until"`
+}
+
+// NewCoursePriceHandler 建立課程價格 API
+// 參數: svc - 課程業務邏輯
+func NewCoursePriceHandler(svc courseService.CourseService) *CoursePriceHandler {
+	return &CoursePriceHandler{svc: svc, now: time.Now}
+}
+
+// Create 新增課程價格紀錄 , 已生效的價格不可修改 , 調價時新增新的紀錄
+// 參數 (path): id - 課程ID
+// 請求: CreateCoursePriceRequest
+func (h *CoursePriceHandler) Create() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req CreateCoursePriceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		tier, ok := parseName(req.Tier, priceTiers)
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid tier %q", req.Tier)})
+			return
+		}
+
+		price := &courseEntity.CoursePrice{
+			CourseID:   courseID,
+			Tier:       tier,
+			Amount:     req.Amount,
+			Currency:   req.Currency,
+			ValidUntil: req.ValidUntil,
+		}
+		if req.EffectiveFrom != nil {
+			price.EffectiveFrom = *req.EffectiveFrom
+		}
+
+		price, err = h.svc.AddPrice(r.Context(), price)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case errors.Is(err, courseService.ErrInvalidPrice),
+			errors.Is(err, money.ErrUnsupportedCurrency),
+			errors.Is(err, money.ErrCurrencyMismatch):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newCoursePriceResponse(price))
+		}
+	})
+}
+
+// List 查詢課程的價格歷史 , 依生效時間排序
+// 參數 (path): id - 課程ID
+func (h *CoursePriceHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		prices, err := h.svc.ListPrices(r.Context(), courseID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+			return
+		case err != nil:
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]CoursePriceResponse, 0, len(prices))
+		for _, price := range prices {
+			data = append(data, newCoursePriceResponse(price))
+		}
+		writeJSON(w, http.StatusOK, CoursePriceListResponse{Data: data})
+	})
+}
+
+// Quote 查詢課程的售價
+// 參數 (path): id - 課程ID
+// 參數 (query string):
+//   - at : 查詢時間 , RFC3339 格式 , 預設為現在
+func (h *CoursePriceHandler) Quote() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		at := h.now()
+		if v := r.URL.Query().Get("at"); v != "" {
+			at, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid at %q , must be RFC3339", v)})
+				return
+			}
+		}
+
+		quote, err := h.svc.Quote(r.Context(), courseID, at)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, PriceQuoteResponse{
+				CourseID:   courseID,
+				At:         at,
+				Tier:       quote.Tier.String(),
+				Price:      quote.Price,
+				Display:    quote.Price.String(),
+				PriceID:    quote.PriceID,
+				ValidUntil: quote.ValidUntil,
+			})
+		}
+	})
+}
+
+// parseName 依名稱 (String) 解析列舉值
+func parseName[S fmt.Stringer](name string, values []S) (S, bool) {
+	for _, v := range values {
+		if v.String() == name {
+			return v, true
+		}
+	}
+	var zero S
+	return zero, false
+}
+
+func newCoursePriceResponse(p *courseEntity.CoursePrice) CoursePriceResponse {
+	return CoursePriceResponse{
+		ID:            p.ID,
+		CourseID:      p.CourseID,
+		Tier:          p.Tier.String(),
+		Price:         p.Money(),
+		Display:       p.Money().String(),
+		EffectiveFrom: p.EffectiveFrom,
+		ValidUntil:    p.ValidUntil,
+		CreatedAt:     p.CreatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	courseService "github.com/itmrchow/course-management-system/internal/service/course"
+)
+
+type CoursePriceHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並新增以 USD 計價 , 基本價格 99.00 的課程 1
+func (s *CoursePriceHandlerTestSuite) SetupTest() {
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(context.Background(), &courseEntity.Course{Name: "Go 入門", Price: 9900, Currency: "USD"})
+	s.Require().NoError(err)
+
+	h := NewCoursePriceHandler(courseService.NewCourseService(courses, outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction))
+
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /courses/{id}/prices", h.List())
+	s.mux.Handle("POST /courses/{id}/prices", h.Create())
+	s.mux.Handle("GET /courses/{id}/price", h.Quote())
+}
+
+// TestCoursePriceHandlerSuite 執行測試套件
+func TestCoursePriceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CoursePriceHandlerTestSuite))
+}
+
+func (s *CoursePriceHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *CoursePriceHandlerTestSuite) TestCreate() {
+	until := time.Now().AddDate(0, 0, 14).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "early bird",
+			target: "/courses/1/prices",
+			body:   fmt.Sprintf(`{"tier": "early_bird", "amount": 7900, "currency": "USD", "valid_until": %q}`, until),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp CoursePriceResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, "early_bird", resp.Tier)
+				assert.EqualValues(t, 7900, resp.Price.Amount)
+				assert.Equal(t, "USD 79.00", resp.Display)
+				assert.NotNil(t, resp.ValidUntil)
+			},
+		},
+		{
+			name:   "currency mismatch",
+			target: "/courses/1/prices",
+			body:   `{"tier": "regular", "amount": 300000, "currency": "TWD"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "invalid tier",
+			target: "/courses/1/prices",
+			body:   `{"tier": "vip", "amount": 100, "currency": "USD"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "backdated",
+			target: "/courses/1/prices",
+			body:   `{"tier": "regular", "amount": 100, "currency": "USD", "effective_from": "2020-01-01T00:00:00Z"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "course not found",
+			target: "/courses/100/prices",
+			body:   `{"tier": "regular", "amount": 100, "currency": "USD"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, tt.target, tt.body))
+		})
+	}
+}
+
+func (s *CoursePriceHandlerTestSuite) TestQuote() {
+	var quote PriceQuoteResponse
+	rec := s.serve(http.MethodGet, "/courses/1/price", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &quote))
+	s.Equal("USD 99.00", quote.Display)
+	s.Zero(quote.PriceID)
+
+	// 一個月後調價
+	next := time.Now().AddDate(0, 1, 0).UTC()
+	rec = s.serve(http.MethodPost, "/courses/1/prices",
+		fmt.Sprintf(`{"tier": "regular", "amount": 12900, "currency": "USD", "effective_from": %q}`, next.Format(time.RFC3339)))
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	rec = s.serve(http.MethodGet, "/courses/1/price?at="+next.Add(time.Hour).Format(time.RFC3339), "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &quote))
+	s.Equal("regular", quote.Tier)
+	s.EqualValues(12900, quote.Price.Amount)
+	s.NotZero(quote.PriceID)
+
+	var list CoursePriceListResponse
+	rec = s.serve(http.MethodGet, "/courses/1/prices", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &list))
+	s.Len(list.Data, 1)
+
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/courses/1/price?at=tomorrow", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/courses/100/price", "").Code)
+}
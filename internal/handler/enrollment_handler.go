@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	enrollmentService "github.com/itmrchow/course-management-system/internal/service/enrollment"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+// EnrollmentHandler 報名 API , 學生為 server.UserIDMiddleware 取得的操作者
+//
+// Example:
+//
+//	h := handler.NewEnrollmentHandler(enrollmentSvc)
+//	srv.Handle("POST /courses/{id}/enrollments", h.Enroll())
+//	srv.Handle("POST /enrollments/{id}/withdraw", h.Withdraw())
+type EnrollmentHandler struct {
+	svc enrollmentService.EnrollmentService
+}
+
+// EnrollRequest 報名請求
+type EnrollRequest struct {
+	CouponCode string `json:"coupon_code"` // 優惠碼 , 空字串表示不使用優惠券
+}
+
+// EnrollmentResponse 報名回應
+type EnrollmentResponse struct {
+	ID          uint       `json:"id"`
+	CourseID    uint       `json:"course_id"`
+	StudentID   uint       `json:"student_id"`
+	Status      string     `json:"status"`
+	EnrolledAt  time.Time  `json:"enrolled_at"`
+	WithdrawnAt *time.Time `json:"withdrawn_at"`
+}
+
+// NewEnrollmentHandler 建立報名 API
+// 參數: svc - 報名業務邏輯
+func NewEnrollmentHandler(svc enrollmentService.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{svc: svc}
+}
+
+// Enroll 操作者報名課程 , 付費課程報名後為待付款 , 以 GET /enrollments/{id}/orders 取得訂單
+// 未登入回傳 401 , 課程不存在或優惠碼不存在回傳 404 , 未開放報名、額滿或已報名回傳 409 , 優惠券無法使用回傳 422
+// 參數 (path): id - 課程ID
+// 請求: EnrollRequest , 可省略
+func (h *EnrollmentHandler) Enroll() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, ok := requireUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req EnrollRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+
+		enrollment, err := h.svc.Enroll(r.Context(), courseID, studentID, req.CouponCode)
+		switch {
+		case errors.Is(err, promotionService.ErrCouponNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "coupon not found"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case errors.Is(err, enrollmentService.ErrRegistrationClosed),
+			errors.Is(err, enrollmentService.ErrCourseFull),
+			errors.Is(err, enrollmentService.ErrAlreadyEnrolled),
+			errors.Is(err, enrollmentService.ErrPaymentPending),
+			errors.Is(err, enrollmentService.ErrInvalidStatus):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, promotionService.ErrCouponNotApplicable),
+			errors.Is(err, promotionService.ErrCouponExhausted),
+			errors.Is(err, promotionService.ErrPerUserLimit):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newEnrollmentResponse(enrollment))
+		}
+	})
+}
+
+// Withdraw 操作者退出課程 , 已付款時依課程的退款規則建立退款紀錄 , 完成回傳 204
+// 未登入回傳 401 , 不是自己的報名回傳 403 , 報名不存在回傳 404 , 報名不是已報名狀態回傳 409
+// 參數 (path): id - 報名ID
+func (h *EnrollmentHandler) Withdraw() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, ok := requireUserID(w, r)
+		if !ok {
+			return
+		}
+
+		err = h.svc.Withdraw(r.Context(), id, studentID)
+		switch {
+		case errors.Is(err, enrollmentService.ErrNotEnrollee):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "enrollment not found"})
+		case errors.Is(err, enrollmentService.ErrInvalidStatus):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func newEnrollmentResponse(e *enrollmentEntity.Enrollment) EnrollmentResponse {
+	return EnrollmentResponse{
+		ID:          e.ID,
+		CourseID:    e.CourseID,
+		StudentID:   e.StudentID,
+		Status:      e.Status.String(),
+		EnrolledAt:  e.EnrolledAt,
+		WithdrawnAt: e.WithdrawnAt,
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepository "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepository "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	"github.com/itmrchow/course-management-system/internal/server"
+	enrollmentService "github.com/itmrchow/course-management-system/internal/service/enrollment"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+type EnrollmentHandlerTestSuite struct {
+	suite.Suite
+	server      *httptest.Server
+	enrollments enrollmentRepository.EnrollmentRepository
+	orders      orderRepository.OrderRepository
+}
+
+// SetupTest 於每個測試案例前執行
+// 課程 1 為免費課程且上限 1 人 , 課程 2 為付費課程 , 課程 3 為草稿
+func (s *EnrollmentHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+	now := time.Now()
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", MaxStudents: 1, Status: courseEntity.CourseStatusOnline, RegistrationStartDate: now.AddDate(0, 0, -7), RegistrationEndDate: now.AddDate(0, 0, 7)},
+		{Name: "資料庫設計", Price: 3000, Currency: "TWD", Status: courseEntity.CourseStatusOnline, RegistrationStartDate: now.AddDate(0, 0, -7), RegistrationEndDate: now.AddDate(0, 0, 7)},
+		{Name: "Go 進階", Status: courseEntity.CourseStatusDraft, RegistrationStartDate: now.AddDate(0, 0, -7), RegistrationEndDate: now.AddDate(0, 0, 7)},
+	} {
+		_, err := courses.Create(ctx, course)
+		s.Require().NoError(err)
+	}
+
+	s.enrollments = enrollmentRepository.NewEnrollmentMemoryRepository()
+	s.orders = orderRepository.NewOrderMemoryRepository()
+	outbox := outboxRepository.NewOutboxMemoryRepository()
+	promotions := promotionService.NewPromotionService(promotionRepository.NewPromotionMemoryRepository(), courses, s.enrollments)
+	orders := orderService.NewOrderService(s.orders, s.enrollments, courses, promotions, outbox, repository.NoTransaction, orderService.Config{})
+	h := NewEnrollmentHandler(enrollmentService.NewEnrollmentService(s.enrollments, courses, s.orders, promotions, orders, outbox,
+		repository.NoTransaction, enrollmentService.Config{OrderTTL: 30 * time.Minute}))
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /courses/{id}/enrollments", h.Enroll())
+	mux.Handle("POST /enrollments/{id}/withdraw", h.Withdraw())
+	s.server = httptest.NewServer(server.UserIDMiddleware()(mux))
+}
+
+// TearDownTest 於每個測試案例後執行 , 關閉 server
+func (s *EnrollmentHandlerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// TestEnrollmentHandlerSuite 執行測試套件
+func TestEnrollmentHandlerSuite(t *testing.T) {
+	suite.Run(t, new(EnrollmentHandlerTestSuite))
+}
+
+// do 以 userID 為操作者發送請求 , userID 為 0 表示匿名請求
+func (s *EnrollmentHandlerTestSuite) do(target string, userID uint, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, s.server.URL+target, strings.NewReader(body))
+	s.Require().NoError(err)
+	if userID != 0 {
+		req.Header.Set(server.HeaderUserID, strconv.FormatUint(uint64(userID), 10))
+	}
+	resp, err := s.server.Client().Do(req)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func (s *EnrollmentHandlerTestSuite) TestEnroll() {
+	tests := []struct {
+		name       string
+		target     string
+		userID     uint
+		body       string
+		assertFunc func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "free course",
+			target: "/courses/1/enrollments",
+			userID: 10,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				var enrollment EnrollmentResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&enrollment))
+				assert.EqualValues(t, 1, enrollment.CourseID)
+				assert.EqualValues(t, 10, enrollment.StudentID)
+				assert.Equal(t, "enrolled", enrollment.Status)
+			},
+		},
+		{
+			name:   "paid course creates order",
+			target: "/courses/2/enrollments",
+			userID: 10,
+			body:   `{"coupon_code":""}`,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
+				var enrollment EnrollmentResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&enrollment))
+				assert.Equal(t, "pending_payment", enrollment.Status)
+
+				order, err := s.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, enrollment.ID, order.EnrollmentID)
+				assert.EqualValues(t, 3000, order.Amount)
+			},
+		},
+		{
+			name:   "anonymous",
+			target: "/courses/1/enrollments",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:   "course not found",
+			target: "/courses/100/enrollments",
+			userID: 10,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "registration closed",
+			target: "/courses/3/enrollments",
+			userID: 10,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "coupon not found",
+			target: "/courses/2/enrollments",
+			userID: 11,
+			body:   `{"coupon_code":"NOPE"}`,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "invalid body",
+			target: "/courses/1/enrollments",
+			userID: 10,
+			body:   `{`,
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.do(tt.target, tt.userID, tt.body))
+		})
+	}
+}
+
+// 重複報名與額滿
+func (s *EnrollmentHandlerTestSuite) TestEnrollCourseFull() {
+	s.Equal(http.StatusCreated, s.do("/courses/1/enrollments", 10, "").StatusCode)
+	s.Equal(http.StatusConflict, s.do("/courses/1/enrollments", 10, "").StatusCode)
+	s.Equal(http.StatusConflict, s.do("/courses/1/enrollments", 11, "").StatusCode)
+}
+
+// 退出課程 : 只能退出自己的報名 , 已退出不可再退出
+func (s *EnrollmentHandlerTestSuite) TestWithdraw() {
+	s.Require().Equal(http.StatusCreated, s.do("/courses/1/enrollments", 10, "").StatusCode)
+
+	s.Equal(http.StatusUnauthorized, s.do("/enrollments/1/withdraw", 0, "").StatusCode)
+	s.Equal(http.StatusForbidden, s.do("/enrollments/1/withdraw", 11, "").StatusCode)
+	s.Equal(http.StatusNotFound, s.do("/enrollments/100/withdraw", 10, "").StatusCode)
+	s.Equal(http.StatusNoContent, s.do("/enrollments/1/withdraw", 10, "").StatusCode)
+	s.Equal(http.StatusConflict, s.do("/enrollments/1/withdraw", 10, "").StatusCode)
+
+	enrollment, err := s.enrollments.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(enrollmentEntity.EnrollmentStatusWithdrawn, enrollment.Status)
+}
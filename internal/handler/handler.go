@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ErrorResponse 錯誤回應
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ListResponse 分頁清單回應
+type ListResponse[T any] struct {
+	Data     []T `json:"data"`
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+// parsePageInfo 解析 page / page_size / order 參數 , 排序欄位由呼叫端指定 , 避免任意欄位排序
+// 參數: r - 請求, sort - 排序欄位
+func parsePageInfo(r *http.Request, sort string) (*repo.RepoPageInfo, error) {
+	query := r.URL.Query()
+	pageInfo := &repo.RepoPageInfo{Page: 1, PageSize: defaultPageSize, Sort: sort, Order: "desc"}
+
+	if v := query.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid page %q", v)
+		}
+		pageInfo.Page = page
+	}
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return nil, fmt.Errorf("invalid page_size %q , must be between 1 and %d", v, maxPageSize)
+		}
+		pageInfo.PageSize = pageSize
+	}
+	switch v := query.Get("order"); v {
+	case "":
+	case "asc", "desc":
+		pageInfo.Order = v
+	default:
+		return nil, fmt.Errorf("invalid order %q", v)
+	}
+
+	return pageInfo, nil
+}
+
+// parseUint 解析正整數參數 , 參數不存在時回傳 0
+func parseUint(r *http.Request, name string) (uint, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 0)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return uint(n), nil
+}
+
+// parsePathID 解析路徑中的 ID 參數 , 例如 /webhooks/{id}
+func parsePathID(r *http.Request, name string) (uint, error) {
+	v := r.PathValue(name)
+	n, err := strconv.ParseUint(v, 10, 0)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return uint(n), nil
+}
+
+// requireUserID 取得 server.UserIDMiddleware 放入 request ctx 的操作者 user ID , 匿名請求回傳 401
+// 回傳: 操作者 user ID, 是否已登入 , 為 false 時已寫入回應
+func requireUserID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	userID, ok := logctx.UserID(r.Context())
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "authentication required"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// writeInternalError 記錄錯誤並回傳 500 , 不將內部錯誤回傳給呼叫端
+func writeInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	zerolog.Ctx(r.Context()).Err(err).Msg("failed to handle request")
+	writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: http.StatusText(http.StatusInternalServerError)})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
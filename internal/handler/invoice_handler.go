@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	invoiceEntity "github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	invoiceService "github.com/itmrchow/course-management-system/internal/service/invoice"
+)
+
+// InvoiceHandler 發票 API
+//
+// Example:
+//
+//	h := handler.NewInvoiceHandler(invoiceSvc)
+//	srv.Handle("GET /invoices/{id}", h.Get())
+//	srv.Handle("GET /invoices/{id}/pdf", h.Document(invoice.FormatPDF))
+type InvoiceHandler struct {
+	svc invoiceService.InvoiceService
+}
+
+// InvoiceResponse 發票回應 , 取得單張發票時包含明細
+type InvoiceResponse struct {
+	ID           uint                  `json:"id"`
+	Number       string                `json:"number"`
+	Year         int                   `json:"year"`
+	OrderID      uint                  `json:"order_id"`
+	EnrollmentID uint                  `json:"enrollment_id"`
+	CourseID     uint                  `json:"course_id"`
+	StudentID    uint                  `json:"student_id"`
+	Currency     string                `json:"currency"`
+	Subtotal     uint                  `json:"subtotal"`
+	Discount     uint                  `json:"discount"`
+	Total        uint                  `json:"total"`
+	PaymentID    string                `json:"payment_id"`
+	PaidAt       time.Time             `json:"paid_at"`
+	IssuedAt     time.Time             `json:"issued_at"`
+	Lines        []InvoiceLineResponse `json:"lines,omitempty"`
+}
+
+// InvoiceLineResponse 發票明細回應 , 金額皆為正數 , kind 為 discount 的明細於合計時扣除
+type InvoiceLineResponse struct {
+	Position    uint   `json:"position"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Detail      string `json:"detail"`
+	Quantity    uint   `json:"quantity"`
+	UnitAmount  uint   `json:"unit_amount"`
+	Amount      uint   `json:"amount"`
+	ReferenceID uint   `json:"reference_id"`
+}
+
+// NewInvoiceHandler 建立發票 API
+// 參數: svc - 發票業務邏輯
+func NewInvoiceHandler(svc invoiceService.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{svc: svc}
+}
+
+// List 查詢發票清單 , 依開立時間排序
+// 參數 (query): year - 開立年度, student_id - 學生 user ID, page, page_size, order
+func (h *InvoiceHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "issued_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		year, err := parseUint(r, "year")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, err := parseUint(r, "student_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		invoices, err := h.svc.List(r.Context(), pageInfo, int(year), studentID)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]InvoiceResponse, 0, len(invoices))
+		for _, inv := range invoices {
+			data = append(data, newInvoiceResponse(inv, nil))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[InvoiceResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Get 查詢發票與明細
+// 參數 (path): id - 發票ID
+func (h *InvoiceHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		inv, lines, err := h.svc.Get(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "invoice not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newInvoiceResponse(inv, lines))
+		}
+	})
+}
+
+// GetByOrder 查詢訂單的發票 , 尚未開立時回傳 404
+// 參數 (path): id - 訂單ID
+func (h *InvoiceHandler) GetByOrder() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orderID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		inv, err := h.svc.GetByOrder(r.Context(), orderID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "invoice not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newInvoiceResponse(inv, nil))
+		}
+	})
+}
+
+// Document 以 format 輸出發票 , 不支援的語系回傳 400
+// 參數 (path): id - 發票ID
+// 參數 (query): locale - 語系 , 預設為 zh-TW
+func (h *InvoiceHandler) Document(format invoice.Format) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		locale := r.URL.Query().Get("locale")
+		if locale == "" {
+			locale = notificationEntity.DefaultLocale
+		}
+
+		b, err := h.svc.Render(r.Context(), id, format, locale)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "invoice not found"})
+		case errors.Is(err, invoice.ErrUnsupportedLocale):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("unsupported locale %q", locale)})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.Header().Set("Content-Type", format.ContentType())
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="invoice-%d.%s"`, id, format))
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(b)
+		}
+	})
+}
+
+func newInvoiceResponse(inv *invoiceEntity.Invoice, lines []*invoiceEntity.InvoiceLine) InvoiceResponse {
+	resp := InvoiceResponse{
+		ID:           inv.ID,
+		Number:       inv.Number,
+		Year:         inv.Year,
+		OrderID:      inv.OrderID,
+		EnrollmentID: inv.EnrollmentID,
+		CourseID:     inv.CourseID,
+		StudentID:    inv.StudentID,
+		Currency:     inv.Currency,
+		Subtotal:     inv.Subtotal,
+		Discount:     inv.Discount,
+		Total:        inv.Total,
+		PaymentID:    inv.PaymentID,
+		PaidAt:       inv.PaidAt,
+		IssuedAt:     inv.IssuedAt,
+	}
+	for _, line := range lines {
+		resp.Lines = append(resp.Lines, InvoiceLineResponse{
+			Position:    line.Position,
+			Kind:        line.Kind.String(),
+			Description: line.Description,
+			Detail:      line.Detail,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+			ReferenceID: line.ReferenceID,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	invoiceRepository "github.com/itmrchow/course-management-system/internal/repository/invoice"
+	orderRepository "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepository "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	invoiceService "github.com/itmrchow/course-management-system/internal/service/invoice"
+)
+
+type InvoiceHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並為已付款的訂單 1 開立發票 1 , 訂單 2 尚未付款
+func (s *InvoiceHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Price: 3000, Currency: "TWD"})
+	s.Require().NoError(err)
+
+	paidAt := time.Now()
+	orders := orderRepository.NewOrderMemoryRepository()
+	_, err = orders.Create(ctx, &orderEntity.Order{EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, ListAmount: 3000, Currency: "TWD", Status: orderEntity.OrderStatusPaid, PaidAt: &paidAt})
+	s.Require().NoError(err)
+	_, err = orders.Create(ctx, &orderEntity.Order{EnrollmentID: 2, CourseID: 1, StudentID: 11, Amount: 3000, ListAmount: 3000, Currency: "TWD"})
+	s.Require().NoError(err)
+
+	renderer, err := invoice.NewRenderer(time.UTC)
+	s.Require().NoError(err)
+	svc := invoiceService.NewInvoiceService(invoiceRepository.NewInvoiceMemoryRepository(), orders, courses, promotionRepository.NewPromotionMemoryRepository(),
+		outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction, renderer, invoiceService.Config{Prefix: "INV", Location: time.UTC})
+	_, err = svc.Issue(ctx, 1)
+	s.Require().NoError(err)
+
+	h := NewInvoiceHandler(svc)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /invoices", h.List())
+	s.mux.Handle("GET /invoices/{id}", h.Get())
+	s.mux.Handle("GET /orders/{id}/invoice", h.GetByOrder())
+	s.mux.Handle("GET /invoices/{id}/pdf", h.Document(invoice.FormatPDF))
+	s.mux.Handle("GET /invoices/{id}/html", h.Document(invoice.FormatHTML))
+}
+
+// TestInvoiceHandlerSuite 執行測試套件
+func TestInvoiceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(InvoiceHandlerTestSuite))
+}
+
+func (s *InvoiceHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *InvoiceHandlerTestSuite) TestGet() {
+	rec := s.serve(http.MethodGet, "/invoices/1", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp InvoiceResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("INV-"+time.Now().UTC().Format("2006")+"-000001", resp.Number)
+	s.EqualValues(3000, resp.Total)
+	s.Require().Len(resp.Lines, 1)
+	s.Equal("course", resp.Lines[0].Kind)
+	s.Equal("Go 入門", resp.Lines[0].Description)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/invoices/100", "").Code)
+}
+
+func (s *InvoiceHandlerTestSuite) TestGetByOrder() {
+	rec := s.serve(http.MethodGet, "/orders/1/invoice", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp InvoiceResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.EqualValues(1, resp.ID)
+	s.Empty(resp.Lines)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/orders/2/invoice", "").Code)
+}
+
+func (s *InvoiceHandlerTestSuite) TestList() {
+	year := time.Now().UTC().Format("2006")
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "依年度與學生",
+			target: "/invoices?year=" + year + "&student_id=10",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				var resp ListResponse[InvoiceResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Len(t, resp.Data, 1)
+			},
+		},
+		{
+			name:   "其他學生",
+			target: "/invoices?student_id=11",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				var resp ListResponse[InvoiceResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Empty(t, resp.Data)
+			},
+		},
+		{
+			name:   "invalid year",
+			target: "/invoices?year=abc",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodGet, tt.target, ""))
+		})
+	}
+}
+
+func (s *InvoiceHandlerTestSuite) TestDocument() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "pdf , 預設語系",
+			target: "/invoices/1/pdf",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+				assert.Equal(t, `inline; filename="invoice-1.pdf"`, rec.Header().Get("Content-Disposition"))
+				assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-"))
+			},
+		},
+		{
+			name:   "html , 英文",
+			target: "/invoices/1/html?locale=en",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Contains(t, rec.Body.String(), "Invoice No.")
+			},
+		},
+		{
+			name:   "unsupported locale",
+			target: "/invoices/1/html?locale=ja",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "not found",
+			target: "/invoices/100/pdf",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodGet, tt.target, ""))
+		})
+	}
+}
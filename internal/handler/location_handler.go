@@ -0,0 +1,503 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	locationEntity "github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	locationService "github.com/itmrchow/course-management-system/internal/service/location"
+)
+
+// defaultConflictRange 未指定 to 時查詢教室重疊的範圍
+const defaultConflictRange = 90 * 24 * time.Hour
+
+// maxFacilities 教室設備的最大數量
+const maxFacilities = 20
+
+// LocationHandler 上課地點與教室 API
+//
+// Example:
+//
+//	h := handler.NewLocationHandler(locationSvc)
+//	srv.Handle("POST /locations/{id}/rooms", h.CreateRoom())
+//	srv.Handle("PUT /courses/{id}/patterns/{pattern_id}/room", h.AssignRoom())
+type LocationHandler struct {
+	svc locationService.LocationService
+	now func() time.Time
+}
+
+// LocationRequest 新增或更新地點請求
+type LocationRequest struct {
+	Name    string `json:"name"`    // 地點名稱 , 必填
+	Address string `json:"address"` // 地址 , 必填
+	Note    string `json:"note"`
+}
+
+// RoomRequest 新增或更新教室請求
+type RoomRequest struct {
+	Name       string   `json:"name"`     // 教室名稱 , 必填 , 同一地點中不可重複
+	Capacity   uint     `json:"capacity"` // 容納人數 , 必填
+	Facilities []string `json:"facilities"`
+}
+
+// AssignRoomRequest 指定上課時段教室請求
+type AssignRoomRequest struct {
+	RoomID uint `json:"room_id"` // 教室ID , 0 表示取消指定
+}
+
+// LocationResponse 地點回應
+type LocationResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoomResponse 教室回應
+type RoomResponse struct {
+	ID         uint      `json:"id"`
+	LocationID uint      `json:"location_id"`
+	Name       string    `json:"name"`
+	Capacity   uint      `json:"capacity"`
+	Facilities []string  `json:"facilities"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PatternResponse 上課時段回應 , DayOfWeek 與時間皆為 UTC
+type PatternResponse struct {
+	ID        uint   `json:"id"`
+	CourseID  uint   `json:"course_id"`
+	DayOfWeek uint   `json:"day_of_week"` // 0: 星期日 ~ 6: 星期六
+	StartTime string `json:"start_time"`  // HH:MM
+	EndTime   string `json:"end_time"`    // HH:MM , 早於開始時間表示跨日
+	RoomID    uint   `json:"room_id"`
+}
+
+// RoomConflictResponse 教室時間重疊的兩次上課
+type RoomConflictResponse struct {
+	RoomID uint            `json:"room_id"`
+	First  SessionResponse `json:"first"`
+	Second SessionResponse `json:"second"`
+}
+
+// RoomConflictListResponse 教室時間重疊清單回應 , 以時間範圍查詢 , 不分頁
+type RoomConflictListResponse struct {
+	Data []RoomConflictResponse `json:"data"`
+}
+
+// NewLocationHandler 建立上課地點與教室 API
+// 參數: svc - 上課地點業務邏輯
+func NewLocationHandler(svc locationService.LocationService) *LocationHandler {
+	return &LocationHandler{svc: svc, now: time.Now}
+}
+
+// CreateLocation 新增地點 , 名稱重複回傳 409
+// 請求: LocationRequest
+func (h *LocationHandler) CreateLocation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		location, ok := decodeLocationRequest(w, r)
+		if !ok {
+			return
+		}
+
+		location, err := h.svc.CreateLocation(r.Context(), location)
+		switch {
+		case errors.Is(err, locationService.ErrDuplicateName):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "location name already exists"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newLocationResponse(location))
+		}
+	})
+}
+
+// UpdateLocation 更新地點 , 名稱重複回傳 409
+// 參數 (path): id - 地點ID
+// 請求: LocationRequest
+func (h *LocationHandler) UpdateLocation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		location, ok := decodeLocationRequest(w, r)
+		if !ok {
+			return
+		}
+		location.ID = id
+
+		location, err = h.svc.UpdateLocation(r.Context(), location)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "location not found"})
+		case errors.Is(err, locationService.ErrDuplicateName):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "location name already exists"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newLocationResponse(location))
+		}
+	})
+}
+
+// GetLocation 查詢地點
+// 參數 (path): id - 地點ID
+func (h *LocationHandler) GetLocation() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		location, err := h.svc.GetLocation(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "location not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newLocationResponse(location))
+		}
+	})
+}
+
+// ListLocations 查詢地點清單 , 依建立時間排序
+// 參數 (query): page, page_size, order
+func (h *LocationHandler) ListLocations() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		locations, err := h.svc.ListLocations(r.Context(), pageInfo)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]LocationResponse, 0, len(locations))
+		for _, location := range locations {
+			data = append(data, newLocationResponse(location))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[LocationResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// CreateRoom 新增地點的教室 , 同一地點中名稱重複回傳 409
+// 參數 (path): id - 地點ID
+// 請求: RoomRequest
+func (h *LocationHandler) CreateRoom() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locationID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		room, ok := decodeRoomRequest(w, r)
+		if !ok {
+			return
+		}
+		room.LocationID = locationID
+
+		room, err = h.svc.CreateRoom(r.Context(), room)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "location not found"})
+		case errors.Is(err, locationService.ErrDuplicateName):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "room name already exists in location"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newRoomResponse(room))
+		}
+	})
+}
+
+// UpdateRoom 更新教室 , 同一地點中名稱重複回傳 409 , 容納人數小於使用教室的課程的最大學生人數回傳 422
+// 參數 (path): id - 教室ID
+// 請求: RoomRequest
+func (h *LocationHandler) UpdateRoom() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		room, ok := decodeRoomRequest(w, r)
+		if !ok {
+			return
+		}
+		room.ID = id
+
+		room, err = h.svc.UpdateRoom(r.Context(), room)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "room not found"})
+		case errors.Is(err, locationService.ErrDuplicateName):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "room name already exists in location"})
+		case errors.Is(err, locationService.ErrRoomTooSmall):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newRoomResponse(room))
+		}
+	})
+}
+
+// GetRoom 查詢教室
+// 參數 (path): id - 教室ID
+func (h *LocationHandler) GetRoom() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		room, err := h.svc.GetRoom(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "room not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newRoomResponse(room))
+		}
+	})
+}
+
+// ListRooms 查詢教室清單 , 依建立時間排序
+// 參數 (query): location_id - 地點ID, min_capacity - 最小容納人數, page, page_size, order
+func (h *LocationHandler) ListRooms() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		locationID, err := parseUint(r, "location_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		minCapacity, err := parseUint(r, "min_capacity")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		rooms, err := h.svc.ListRooms(r.Context(), pageInfo, locationID, minCapacity)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]RoomResponse, 0, len(rooms))
+		for _, room := range rooms {
+			data = append(data, newRoomResponse(room))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[RoomResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Conflicts 查詢教室時間重疊的上課 , 依開始時間排序
+// 參數 (path): id - 教室ID
+// 參數 (query string):
+//   - from , to : 上課開始時間範圍 [from, to] , RFC3339 格式 , 預設為現在起 90 天 , 不可超過一年
+func (h *LocationHandler) Conflicts() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		from, to, err := parseTimeRange(r, h.now(), defaultConflictRange)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		conflicts, err := h.svc.Conflicts(r.Context(), id, from, to)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "room not found"})
+			return
+		case errors.Is(err, locationService.ErrInvalidRange):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		case err != nil:
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]RoomConflictResponse, 0, len(conflicts))
+		for _, c := range conflicts {
+			data = append(data, RoomConflictResponse{
+				RoomID: c.RoomID,
+				First:  newSessionResponse(c.First),
+				Second: newSessionResponse(c.Second),
+			})
+		}
+		writeJSON(w, http.StatusOK, RoomConflictListResponse{Data: data})
+	})
+}
+
+// AssignRoom 指定課程上課時段的教室 , room_id 為 0 表示取消指定
+// 線上課程或容納人數不足回傳 422 , 與教室其他上課時間重疊回傳 409
+// 參數 (path): id - 課程ID, pattern_id - 上課時段ID
+// 請求: AssignRoomRequest
+func (h *LocationHandler) AssignRoom() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		patternID, err := parsePathID(r, "pattern_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req AssignRoomRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+
+		pattern, err := h.svc.AssignRoom(r.Context(), courseID, patternID, req.RoomID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course, pattern or room not found"})
+		case errors.Is(err, locationService.ErrOnlineCourse),
+			errors.Is(err, locationService.ErrRoomTooSmall):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, locationService.ErrRoomConflict):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, PatternResponse{
+				ID:        pattern.ID,
+				CourseID:  pattern.CourseID,
+				DayOfWeek: pattern.DayOfWeek,
+				StartTime: pattern.StartTime.UTC().Format("15:04"),
+				EndTime:   pattern.EndTime.UTC().Format("15:04"),
+				RoomID:    pattern.RoomID,
+			})
+		}
+	})
+}
+
+// decodeLocationRequest 解析並驗證地點請求 , 驗證失敗時寫入 400 回應並回傳 false
+func decodeLocationRequest(w http.ResponseWriter, r *http.Request) (*locationEntity.Location, bool) {
+	var req LocationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return nil, false
+	}
+
+	location := &locationEntity.Location{
+		Name:    strings.TrimSpace(req.Name),
+		Address: strings.TrimSpace(req.Address),
+		Note:    strings.TrimSpace(req.Note),
+	}
+	switch {
+	case location.Name == "":
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "name is required"})
+	case len([]rune(location.Name)) > 100:
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "name must be at most 100 characters"})
+	case location.Address == "":
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "address is required"})
+	case len([]rune(location.Address)) > 255:
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "address must be at most 255 characters"})
+	default:
+		return location, true
+	}
+	return nil, false
+}
+
+// decodeRoomRequest 解析並驗證教室請求 , 驗證失敗時寫入 400 回應並回傳 false
+func decodeRoomRequest(w http.ResponseWriter, r *http.Request) (*locationEntity.Room, bool) {
+	var req RoomRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return nil, false
+	}
+
+	room := &locationEntity.Room{
+		Name:       strings.TrimSpace(req.Name),
+		Capacity:   req.Capacity,
+		Facilities: make(locationEntity.Facilities, 0, len(req.Facilities)),
+	}
+	switch {
+	case room.Name == "":
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "name is required"})
+		return nil, false
+	case len([]rune(room.Name)) > 50:
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "name must be at most 50 characters"})
+		return nil, false
+	case room.Capacity == 0:
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "capacity is required"})
+		return nil, false
+	case len(req.Facilities) > maxFacilities:
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("at most %d facilities", maxFacilities)})
+		return nil, false
+	}
+
+	seen := make(map[string]bool, len(req.Facilities))
+	for _, facility := range req.Facilities {
+		facility = strings.TrimSpace(facility)
+		if facility == "" || len([]rune(facility)) > 50 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid facility %q", facility)})
+			return nil, false
+		}
+		if !seen[facility] {
+			seen[facility] = true
+			room.Facilities = append(room.Facilities, facility)
+		}
+	}
+	return room, true
+}
+
+func newLocationResponse(l *locationEntity.Location) LocationResponse {
+	return LocationResponse{
+		ID:        l.ID,
+		Name:      l.Name,
+		Address:   l.Address,
+		Note:      l.Note,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+func newRoomResponse(room *locationEntity.Room) RoomResponse {
+	facilities := []string(room.Facilities)
+	if facilities == nil {
+		facilities = []string{}
+	}
+	return RoomResponse{
+		ID:         room.ID,
+		LocationID: room.LocationID,
+		Name:       room.Name,
+		Capacity:   room.Capacity,
+		Facilities: facilities,
+		CreatedAt:  room.CreatedAt,
+		UpdatedAt:  room.UpdatedAt,
+	}
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	locationRepository "github.com/itmrchow/course-management-system/internal/repository/location"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	locationService "github.com/itmrchow/course-management-system/internal/service/location"
+)
+
+type LocationHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由
+// 課程 1 與 2 於 2025-07-21 ~ 08-03 上課 , 最大學生人數 20 , 上課時段 1 與 2 皆為每週一 11:00 ~ 13:00
+// 課程 3 為線上課程 , 上課時段 3
+func (s *LocationHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", MaxStudents: 20},
+		{Name: "Go 進階", MaxStudents: 20},
+		{Name: "線上課程", MaxStudents: 20, IsOnline: true},
+	} {
+		course.Status = courseEntity.CourseStatusOnline
+		course.StartDate = time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC)
+		course.EndDate = time.Date(2025, 8, 3, 23, 59, 59, 0, time.UTC)
+		_, err := courses.Create(ctx, course)
+		s.Require().NoError(err)
+	}
+	for courseID := uint(1); courseID <= 3; courseID++ {
+		_, err := courses.CreatePattern(ctx, &courseEntity.CoursePattern{
+			CourseID:  courseID,
+			DayOfWeek: uint(time.Monday),
+			StartTime: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC),
+		})
+		s.Require().NoError(err)
+	}
+
+	svc := locationService.NewLocationService(locationRepository.NewLocationMemoryRepository(), courses,
+		sessionRepository.NewSessionMemoryRepository(), repository.NoTransaction)
+
+	h := NewLocationHandler(svc)
+	h.now = func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) }
+	s.mux = http.NewServeMux()
+	s.mux.Handle("POST /locations", h.CreateLocation())
+	s.mux.Handle("GET /locations", h.ListLocations())
+	s.mux.Handle("GET /locations/{id}", h.GetLocation())
+	s.mux.Handle("PUT /locations/{id}", h.UpdateLocation())
+	s.mux.Handle("POST /locations/{id}/rooms", h.CreateRoom())
+	s.mux.Handle("GET /rooms", h.ListRooms())
+	s.mux.Handle("GET /rooms/{id}", h.GetRoom())
+	s.mux.Handle("PUT /rooms/{id}", h.UpdateRoom())
+	s.mux.Handle("GET /rooms/{id}/conflicts", h.Conflicts())
+	s.mux.Handle("PUT /courses/{id}/patterns/{pattern_id}/room", h.AssignRoom())
+}
+
+// TestLocationHandlerSuite 執行測試套件
+func TestLocationHandlerSuite(t *testing.T) {
+	suite.Run(t, new(LocationHandlerTestSuite))
+}
+
+func (s *LocationHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// createRoom 建立地點與容納人數為 capacity 的教室 , 回傳教室ID
+func (s *LocationHandlerTestSuite) createRoom(capacity string) uint {
+	rec := s.serve(http.MethodPost, "/locations", `{"name": "台北分校", "address": "台北市中正區"}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	rec = s.serve(http.MethodPost, "/locations/1/rooms", `{"name": "301", "capacity": `+capacity+`}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	var resp RoomResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.ID
+}
+
+func (s *LocationHandlerTestSuite) TestCreateLocation() {
+	tests := []struct {
+		name       string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "新增地點",
+			body: `{"name": " 台北分校 ", "address": "台北市中正區", "note": "近捷運站"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp LocationResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, "台北分校", resp.Name)
+				assert.Equal(t, "台北市中正區", resp.Address)
+				assert.Equal(t, "近捷運站", resp.Note)
+			},
+		},
+		{
+			name: "缺少名稱",
+			body: `{"address": "台北市中正區"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "缺少地址",
+			body: `{"name": "台北分校"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, "/locations", tt.body))
+		})
+	}
+}
+
+func (s *LocationHandlerTestSuite) TestDuplicateLocation() {
+	rec := s.serve(http.MethodPost, "/locations", `{"name": "台北分校", "address": "台北市中正區"}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	rec = s.serve(http.MethodPost, "/locations", `{"name": "台北分校", "address": "台北市大安區"}`)
+	s.Equal(http.StatusConflict, rec.Code)
+}
+
+func (s *LocationHandlerTestSuite) TestCreateRoom() {
+	rec := s.serve(http.MethodPost, "/locations", `{"name": "台北分校", "address": "台北市中正區"}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "新增教室",
+			target: "/locations/1/rooms",
+			body:   `{"name": "301", "capacity": 30, "facilities": ["projector", " whiteboard ", "projector"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp RoomResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.EqualValues(t, 1, resp.LocationID)
+				assert.EqualValues(t, 30, resp.Capacity)
+				assert.Equal(t, []string{"projector", "whiteboard"}, resp.Facilities)
+			},
+		},
+		{
+			name:   "缺少容納人數",
+			target: "/locations/1/rooms",
+			body:   `{"name": "302"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "空白設備",
+			target: "/locations/1/rooms",
+			body:   `{"name": "302", "capacity": 10, "facilities": [" "]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "地點不存在",
+			target: "/locations/99/rooms",
+			body:   `{"name": "302", "capacity": 10}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, tt.target, tt.body))
+		})
+	}
+}
+
+func (s *LocationHandlerTestSuite) TestListRooms() {
+	s.createRoom("30")
+	rec := s.serve(http.MethodPost, "/locations/1/rooms", `{"name": "302", "capacity": 10}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+
+	rec = s.serve(http.MethodGet, "/rooms?location_id=1&min_capacity=20", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp ListResponse[RoomResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp.Data, 1)
+	s.Equal("301", resp.Data[0].Name)
+	s.Equal([]string{}, resp.Data[0].Facilities)
+}
+
+func (s *LocationHandlerTestSuite) TestAssignRoom() {
+	roomID := s.createRoom("30")
+	smallRoom := s.serve(http.MethodPost, "/locations/1/rooms", `{"name": "302", "capacity": 10}`)
+	s.Require().Equal(http.StatusCreated, smallRoom.Code)
+	var small RoomResponse
+	s.Require().NoError(json.Unmarshal(smallRoom.Body.Bytes(), &small))
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "指定教室",
+			target: "/courses/1/patterns/1/room",
+			body:   `{"room_id": ` + fmt.Sprint(roomID) + `}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp PatternResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, roomID, resp.RoomID)
+				assert.Equal(t, "11:00", resp.StartTime)
+			},
+		},
+		{
+			name:   "教室時間重疊",
+			target: "/courses/2/patterns/2/room",
+			body:   `{"room_id": ` + fmt.Sprint(roomID) + `}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name:   "容納人數不足",
+			target: "/courses/2/patterns/2/room",
+			body:   `{"room_id": ` + fmt.Sprint(small.ID) + `}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:   "線上課程",
+			target: "/courses/3/patterns/3/room",
+			body:   `{"room_id": ` + fmt.Sprint(roomID) + `}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:   "上課時段不屬於課程",
+			target: "/courses/1/patterns/2/room",
+			body:   `{"room_id": ` + fmt.Sprint(roomID) + `}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+		{
+			name:   "取消指定教室",
+			target: "/courses/1/patterns/1/room",
+			body:   `{"room_id": 0}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp PatternResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Zero(t, resp.RoomID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPut, tt.target, tt.body))
+		})
+	}
+}
+
+func (s *LocationHandlerTestSuite) TestUpdateRoomCapacity() {
+	roomID := s.createRoom("30")
+	rec := s.serve(http.MethodPut, "/courses/1/patterns/1/room", `{"room_id": `+fmt.Sprint(roomID)+`}`)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	rec = s.serve(http.MethodPut, "/rooms/"+fmt.Sprint(roomID), `{"name": "301", "capacity": 10}`)
+	s.Equal(http.StatusUnprocessableEntity, rec.Code)
+
+	rec = s.serve(http.MethodPut, "/rooms/"+fmt.Sprint(roomID), `{"name": "301", "capacity": 20}`)
+	s.Equal(http.StatusOK, rec.Code)
+}
+
+func (s *LocationHandlerTestSuite) TestConflicts() {
+	roomID := s.createRoom("30")
+	rec := s.serve(http.MethodPut, "/courses/1/patterns/1/room", `{"room_id": `+fmt.Sprint(roomID)+`}`)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	rec = s.serve(http.MethodGet, "/rooms/"+fmt.Sprint(roomID)+"/conflicts", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp RoomConflictListResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Empty(resp.Data)
+
+	rec = s.serve(http.MethodGet, "/rooms/"+fmt.Sprint(roomID)+"/conflicts?from=2025-08-01T00:00:00Z&to=2025-07-01T00:00:00Z", "")
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	rec = s.serve(http.MethodGet, "/rooms/99/conflicts", "")
+	s.Equal(http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	notificationService "github.com/itmrchow/course-management-system/internal/service/notification"
+)
+
+// NotificationHandler 通知偏好 API
+//
+// Example:
+//
+//	h := handler.NewNotificationHandler(notificationSvc)
+//	srv.Handle("GET /users/{id}/notification-preferences", h.GetPreference())
+type NotificationHandler struct {
+	svc notificationService.NotificationService
+}
+
+// NotificationPreferenceRequest 更新通知偏好請求 , 所有欄位都會更新
+type NotificationPreferenceRequest struct {
+	Locale       string `json:"locale"`
+	EmailEnabled bool   `json:"email_enabled"`
+	SMSEnabled   bool   `json:"sms_enabled"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+}
+
+// NotificationPreferenceResponse 通知偏好回應
+type NotificationPreferenceResponse struct {
+	UserID       uint   `json:"user_id"`
+	Locale       string `json:"locale"`
+	EmailEnabled bool   `json:"email_enabled"`
+	SMSEnabled   bool   `json:"sms_enabled"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+}
+
+// NewNotificationHandler 建立通知偏好 API
+// 參數: svc - 通知業務邏輯
+func NewNotificationHandler(svc notificationService.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+// GetPreference 查詢使用者的通知偏好 , 沒有設定時回傳預設值
+// 參數 (path): id - user ID
+func (h *NotificationHandler) GetPreference() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		preference, err := h.svc.GetPreference(r.Context(), userID)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newNotificationPreferenceResponse(preference))
+	})
+}
+
+// PutPreference 更新使用者的通知偏好
+// 參數 (path): id - user ID
+// 請求: NotificationPreferenceRequest , locale 須為支援的語系
+func (h *NotificationHandler) PutPreference() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req NotificationPreferenceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+
+		preference := &notificationEntity.NotificationPreference{
+			UserID:       userID,
+			Locale:       req.Locale,
+			EmailEnabled: req.EmailEnabled,
+			SMSEnabled:   req.SMSEnabled,
+			Email:        req.Email,
+			Phone:        req.Phone,
+		}
+		err = h.svc.SavePreference(r.Context(), preference)
+		switch {
+		case errors.Is(err, notificationService.ErrUnsupportedLocale):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newNotificationPreferenceResponse(preference))
+		}
+	})
+}
+
+func newNotificationPreferenceResponse(p *notificationEntity.NotificationPreference) NotificationPreferenceResponse {
+	return NotificationPreferenceResponse{
+		UserID:       p.UserID,
+		Locale:       p.Locale,
+		EmailEnabled: p.EmailEnabled,
+		SMSEnabled:   p.SMSEnabled,
+		Email:        p.Email,
+		Phone:        p.Phone,
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	notificationRepository "github.com/itmrchow/course-management-system/internal/repository/notification"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	notificationService "github.com/itmrchow/course-management-system/internal/service/notification"
+)
+
+type NotificationHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由
+func (s *NotificationHandlerTestSuite) SetupTest() {
+	renderer, err := notification.NewRenderer(time.UTC)
+	s.Require().NoError(err)
+	svc := notificationService.NewNotificationService(notificationRepository.NewNotificationMemoryRepository(), teacherRepository.NewTeacherMemoryRepository(), renderer)
+
+	h := NewNotificationHandler(svc)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /users/{id}/notification-preferences", h.GetPreference())
+	s.mux.Handle("PUT /users/{id}/notification-preferences", h.PutPreference())
+}
+
+// TestNotificationHandlerSuite 執行測試套件
+func TestNotificationHandlerSuite(t *testing.T) {
+	suite.Run(t, new(NotificationHandlerTestSuite))
+}
+
+func (s *NotificationHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *NotificationHandlerTestSuite) decode(rec *httptest.ResponseRecorder) NotificationPreferenceResponse {
+	var resp NotificationPreferenceResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func (s *NotificationHandlerTestSuite) TestGetPreference_Default() {
+	rec := s.serve(http.MethodGet, "/users/7/notification-preferences", "")
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(NotificationPreferenceResponse{UserID: 7, Locale: notificationEntity.DefaultLocale, EmailEnabled: true, SMSEnabled: true}, s.decode(rec))
+}
+
+func (s *NotificationHandlerTestSuite) TestPutPreference() {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success",
+			target: "/users/7/notification-preferences",
+			body:   `{"locale": "en", "email_enabled": true, "sms_enabled": false, "email": "amy@example.com"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				rec = s.serve(http.MethodGet, "/users/7/notification-preferences", "")
+				assert.Equal(t, NotificationPreferenceResponse{UserID: 7, Locale: "en", EmailEnabled: true, Email: "amy@example.com"}, s.decode(rec))
+			},
+		},
+		{
+			name:   "unsupported locale",
+			target: "/users/7/notification-preferences",
+			body:   `{"locale": "ja", "email_enabled": true}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "invalid json",
+			target: "/users/7/notification-preferences",
+			body:   `{"locale":`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "invalid user id",
+			target: "/users/abc/notification-preferences",
+			body:   `{"locale": "en"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPut, tt.target, tt.body))
+		})
+	}
+}
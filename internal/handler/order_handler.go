@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/payment"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+)
+
+// OrderHandler 訂單與付款 API
+//
+// Example:
+//
+//	h := handler.NewOrderHandler(orderSvc)
+//	srv.Handle("POST /orders/{id}/pay", h.Pay())
+//	srv.Handle("POST /payments/{provider}/callback", h.Callback())
+type OrderHandler struct {
+	svc orderService.OrderService
+}
+
+// OrderResponse 訂單回應
+type OrderResponse struct {
+	ID             uint       `json:"id"`
+	EnrollmentID   uint       `json:"enrollment_id"`
+	CourseID       uint       `json:"course_id"`
+	StudentID      uint       `json:"student_id"`
+	Amount         uint       `json:"amount"`
+	ListAmount     uint       `json:"list_amount"`
+	DiscountAmount uint       `json:"discount_amount"`
+	Currency       string     `json:"currency"`
+	PriceID        uint       `json:"price_id"`
+	Status         string     `json:"status"`
+	Provider       string     `json:"provider"`
+	PaymentID      string     `json:"payment_id"`
+	CheckoutURL    string     `json:"checkout_url"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	PaidAt         *time.Time `json:"paid_at"`
+	FailedAt       *time.Time `json:"failed_at"`
+	FailureReason  string     `json:"failure_reason"`
+	RefundedAmount uint       `json:"refunded_amount"`
+	RefundedAt     *time.Time `json:"refunded_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// OrderListResponse 訂單清單回應 , 一個報名的訂單數量有限 , 不分頁
+type OrderListResponse struct {
+	Data []OrderResponse `json:"data"`
+}
+
+// RefundResponse 退款紀錄回應
+type RefundResponse struct {
+	ID               uint       `json:"id"`
+	OrderID          uint       `json:"order_id"`
+	EnrollmentID     uint       `json:"enrollment_id"`
+	Amount           uint       `json:"amount"`
+	Currency         string     `json:"currency"`
+	Percent          uint       `json:"percent"`
+	Reason           string     `json:"reason"`
+	Status           string     `json:"status"`
+	ProviderRefundID string     `json:"provider_refund_id"`
+	Attempts         uint       `json:"attempts"`
+	LastError        string     `json:"last_error"`
+	RefundedAt       *time.Time `json:"refunded_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// RefundListResponse 退款紀錄清單回應 , 不分頁
+type RefundListResponse struct {
+	Data []RefundResponse `json:"data"`
+}
+
+// RefundQuoteResponse 退出課程可退款金額回應
+type RefundQuoteResponse struct {
+	EnrollmentID uint       `json:"enrollment_id"`
+	OrderID      uint       `json:"order_id"`
+	Paid         uint       `json:"paid"`
+	Amount       uint       `json:"amount"`
+	Currency     string     `json:"currency"`
+	Percent      uint       `json:"percent"`
+	Deadline     *time.Time `json:"deadline"`
+}
+
+// NewOrderHandler 建立訂單與付款 API
+// 參數: svc - 訂單業務邏輯
+func NewOrderHandler(svc orderService.OrderService) *OrderHandler {
+	return &OrderHandler{svc: svc}
+}
+
+// Get 查詢訂單
+// 參數 (path): id - 訂單ID
+func (h *OrderHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		order, err := h.svc.GetByID(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "order not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newOrderResponse(order))
+		}
+	})
+}
+
+// ListByEnrollment 查詢報名的訂單 , 新的在前
+// 參數 (path): id - 報名ID
+func (h *OrderHandler) ListByEnrollment() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enrollmentID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		orders, err := h.svc.ListByEnrollment(r.Context(), enrollmentID)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]OrderResponse, 0, len(orders))
+		for _, order := range orders {
+			data = append(data, newOrderResponse(order))
+		}
+		writeJSON(w, http.StatusOK, OrderListResponse{Data: data})
+	})
+}
+
+// Pay 建立付款 , 回傳含付款頁面網址 (checkout_url) 的訂單 , 已建立付款時回傳原本的付款頁面
+// 訂單不是待付款或已逾期時回傳 409 , 金流商建立付款失敗時回傳 502
+// 參數 (path): id - 訂單ID
+func (h *OrderHandler) Pay() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		order, err := h.svc.Pay(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "order not found"})
+		case errors.Is(err, orderService.ErrInvalidStatus), errors.Is(err, orderService.ErrOrderExpired):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, orderService.ErrPaymentProvider):
+			writeJSON(w, http.StatusBadGateway, ErrorResponse{Error: "payment provider unavailable"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newOrderResponse(order))
+		}
+	})
+}
+
+// Callback 接收金流商的付款結果通知 , 處理完成 (包含重複的通知) 回傳 204
+// 簽章不符回傳 400 , 金流商或付款不存在回傳 404 , 其他錯誤回傳 500 讓金流商重送
+// 參數 (path): provider - 金流商名稱
+func (h *OrderHandler) Callback() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+
+		err = h.svc.HandleCallback(r.Context(), r.PathValue("provider"), r.Header, body)
+		switch {
+		case errors.Is(err, orderService.ErrUnknownProvider):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "unknown payment provider"})
+		case errors.Is(err, payment.ErrInvalidCallback):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid payment callback"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "payment not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// QuoteRefund 查詢報名於現在退出課程可退款的金額 , 沒有已付款的訂單時 order_id 為 0
+// 報名不是已報名狀態時回傳 409
+// 參數 (path): id - 報名ID
+func (h *OrderHandler) QuoteRefund() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enrollmentID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		quote, err := h.svc.QuoteRefund(r.Context(), enrollmentID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "enrollment not found"})
+		case errors.Is(err, orderService.ErrNotRefundable):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, RefundQuoteResponse{
+				EnrollmentID: quote.EnrollmentID,
+				OrderID:      quote.OrderID,
+				Paid:         quote.Paid,
+				Amount:       quote.Amount,
+				Currency:     quote.Currency,
+				Percent:      quote.Percent,
+				Deadline:     quote.Deadline,
+			})
+		}
+	})
+}
+
+// ListRefunds 查詢訂單的退款紀錄
+// 參數 (path): id - 訂單ID
+func (h *OrderHandler) ListRefunds() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orderID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		refunds, err := h.svc.ListRefunds(r.Context(), orderID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "order not found"})
+			return
+		case err != nil:
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]RefundResponse, 0, len(refunds))
+		for _, refund := range refunds {
+			data = append(data, RefundResponse{
+				ID:               refund.ID,
+				OrderID:          refund.OrderID,
+				EnrollmentID:     refund.EnrollmentID,
+				Amount:           refund.Amount,
+				Currency:         refund.Currency,
+				Percent:          refund.Percent,
+				Reason:           refund.Reason.String(),
+				Status:           refund.Status.String(),
+				ProviderRefundID: refund.ProviderRefundID,
+				Attempts:         refund.Attempts,
+				LastError:        refund.LastError,
+				RefundedAt:       refund.RefundedAt,
+				CreatedAt:        refund.CreatedAt,
+			})
+		}
+		writeJSON(w, http.StatusOK, RefundListResponse{Data: data})
+	})
+}
+
+func newOrderResponse(o *orderEntity.Order) OrderResponse {
+	return OrderResponse{
+		ID:             o.ID,
+		EnrollmentID:   o.EnrollmentID,
+		CourseID:       o.CourseID,
+		StudentID:      o.StudentID,
+		Amount:         o.Amount,
+		ListAmount:     o.ListAmount,
+		DiscountAmount: o.DiscountAmount,
+		Currency:       o.Currency,
+		PriceID:        o.PriceID,
+		Status:         o.Status.String(),
+		Provider:       o.Provider,
+		PaymentID:      o.PaymentID,
+		CheckoutURL:    o.CheckoutURL,
+		ExpiresAt:      o.ExpiresAt,
+		PaidAt:         o.PaidAt,
+		FailedAt:       o.FailedAt,
+		FailureReason:  o.FailureReason,
+		RefundedAmount: o.RefundedAmount,
+		RefundedAt:     o.RefundedAt,
+		CreatedAt:      o.CreatedAt,
+	}
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/payment"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepository "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepository "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+type OrderHandlerTestSuite struct {
+	suite.Suite
+	server      *httptest.Server
+	enrollments enrollmentRepository.EnrollmentRepository
+	orders      orderRepository.OrderRepository
+}
+
+// SetupTest 於每個測試案例前執行
+// 以模擬金流商啟動 server , 並新增待付款的訂單 1 (報名 1) 與已付款的訂單 2 (報名 2 , 已報名)
+// 課程報名於 7 天後結束 , 使用預設退款規則
+func (s *OrderHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+	now := time.Now()
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Price: 3000, Status: courseEntity.CourseStatusOnline,
+		RegistrationEndDate: now.AddDate(0, 0, 7), StartDate: now.AddDate(0, 0, 14)})
+	s.Require().NoError(err)
+
+	s.enrollments = enrollmentRepository.NewEnrollmentMemoryRepository()
+	orders := orderRepository.NewOrderMemoryRepository()
+	for _, studentID := range []uint{10, 11} {
+		id, err := s.enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: studentID, Status: enrollmentEntity.EnrollmentStatusPendingPayment, EnrolledAt: now})
+		s.Require().NoError(err)
+		_, err = orders.Create(ctx, &orderEntity.Order{EnrollmentID: id, CourseID: 1, StudentID: studentID, Amount: 3000, Currency: "TWD", ExpiresAt: now.Add(30 * time.Minute)})
+		s.Require().NoError(err)
+	}
+	_, err = orders.UpdateStatus(ctx, 2, orderEntity.OrderStatusPending, orderEntity.OrderStatusPaid, now, "")
+	s.Require().NoError(err)
+	_, err = s.enrollments.UpdateStatus(ctx, 2, enrollmentEntity.EnrollmentStatusPendingPayment, enrollmentEntity.EnrollmentStatusEnrolled)
+	s.Require().NoError(err)
+	s.orders = orders
+
+	mux := http.NewServeMux()
+	s.server = httptest.NewServer(mux)
+
+	gateway := payment.NewFakeGateway(payment.FakeConfig{BaseURL: s.server.URL, Secret: "whsec_test"}, s.server.Client())
+	promotions := promotionService.NewPromotionService(promotionRepository.NewPromotionMemoryRepository(), courses, s.enrollments)
+	h := NewOrderHandler(orderService.NewOrderService(orders, s.enrollments, courses, promotions, outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction, orderService.Config{}, gateway))
+	mux.Handle("GET /orders/{id}", h.Get())
+	mux.Handle("GET /enrollments/{id}/orders", h.ListByEnrollment())
+	mux.Handle("POST /orders/{id}/pay", h.Pay())
+	mux.Handle("GET /orders/{id}/refunds", h.ListRefunds())
+	mux.Handle("GET /enrollments/{id}/refund-quote", h.QuoteRefund())
+	mux.Handle("POST /payments/{provider}/callback", h.Callback())
+	mux.Handle("POST /fake-payments/{id}", gateway.Checkout())
+}
+
+// TearDownTest 於每個測試案例後執行 , 關閉 server
+func (s *OrderHandlerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// TestOrderHandlerSuite 執行測試套件
+func TestOrderHandlerSuite(t *testing.T) {
+	suite.Run(t, new(OrderHandlerTestSuite))
+}
+
+func (s *OrderHandlerTestSuite) do(method, target string) *http.Response {
+	req, err := http.NewRequest(method, target, nil)
+	s.Require().NoError(err)
+	resp, err := s.server.Client().Do(req)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func (s *OrderHandlerTestSuite) decodeOrder(resp *http.Response) OrderResponse {
+	var order OrderResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&order))
+	return order
+}
+
+func (s *OrderHandlerTestSuite) TestPay() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "success",
+			target: "/orders/1/pay",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				order := s.decodeOrder(resp)
+				assert.Equal(t, "pending", order.Status)
+				assert.Equal(t, payment.FakeProvider, order.Provider)
+				assert.Equal(t, s.server.URL+"/fake-payments/"+order.PaymentID, order.CheckoutURL)
+			},
+		},
+		{
+			name:   "already paid",
+			target: "/orders/2/pay",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "not found",
+			target: "/orders/100/pay",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "invalid id",
+			target: "/orders/abc/pay",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.do(http.MethodPost, s.server.URL+tt.target))
+		})
+	}
+}
+
+// 付款成功 : 建立付款 -> 模擬付款頁面 -> 金流商通知 -> 訂單已付款且報名確認
+func (s *OrderHandlerTestSuite) TestCheckoutPaid() {
+	order := s.decodeOrder(s.do(http.MethodPost, s.server.URL+"/orders/1/pay"))
+
+	resp := s.do(http.MethodPost, order.CheckoutURL+"?result=paid")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	order = s.decodeOrder(s.do(http.MethodGet, s.server.URL+"/orders/1"))
+	s.Equal("paid", order.Status)
+	s.NotNil(order.PaidAt)
+
+	enrollment, err := s.enrollments.GetByID(context.Background(), order.EnrollmentID)
+	s.Require().NoError(err)
+	s.Equal(enrollmentEntity.EnrollmentStatusEnrolled, enrollment.Status)
+}
+
+// 付款失敗 : 訂單付款失敗且釋出報名名額
+func (s *OrderHandlerTestSuite) TestCheckoutFailed() {
+	order := s.decodeOrder(s.do(http.MethodPost, s.server.URL+"/orders/1/pay"))
+
+	resp := s.do(http.MethodPost, order.CheckoutURL+"?result=failed")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var list OrderListResponse
+	s.Require().NoError(json.NewDecoder(s.do(http.MethodGet, s.server.URL+"/enrollments/1/orders").Body).Decode(&list))
+	s.Require().Len(list.Data, 1)
+	s.Equal("failed", list.Data[0].Status)
+	s.NotEmpty(list.Data[0].FailureReason)
+
+	enrollment, err := s.enrollments.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(enrollmentEntity.EnrollmentStatusWithdrawn, enrollment.Status)
+}
+
+func (s *OrderHandlerTestSuite) TestCallback_Invalid() {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "unknown provider", target: "/payments/unknown/callback", wantStatus: http.StatusNotFound},
+		{name: "missing signature", target: "/payments/fake/callback", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.wantStatus, s.do(http.MethodPost, s.server.URL+tt.target).StatusCode)
+		})
+	}
+}
+
+func (s *OrderHandlerTestSuite) TestQuoteRefund() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "full refund before registration ends",
+			target: "/enrollments/2/refund-quote",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var quote RefundQuoteResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&quote))
+				assert.EqualValues(t, 2, quote.OrderID)
+				assert.EqualValues(t, 3000, quote.Paid)
+				assert.EqualValues(t, 3000, quote.Amount)
+				assert.EqualValues(t, 100, quote.Percent)
+				assert.NotNil(t, quote.Deadline)
+			},
+		},
+		{
+			name:   "pending payment",
+			target: "/enrollments/1/refund-quote",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "not found",
+			target: "/enrollments/100/refund-quote",
+			assertFunc: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.do(http.MethodGet, s.server.URL+tt.target))
+		})
+	}
+}
+
+func (s *OrderHandlerTestSuite) TestListRefunds() {
+	order, err := s.orders.GetByID(context.Background(), 2)
+	s.Require().NoError(err)
+	_, err = s.orders.CreateRefund(context.Background(), orderEntity.NewRefund(order, 50, orderEntity.RefundReasonWithdrawal, time.Now()))
+	s.Require().NoError(err)
+
+	resp := s.do(http.MethodGet, s.server.URL+"/orders/2/refunds")
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var list RefundListResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&list))
+	s.Require().Len(list.Data, 1)
+	s.EqualValues(1500, list.Data[0].Amount)
+	s.Equal("withdrawal", list.Data[0].Reason)
+	s.Equal("pending", list.Data[0].Status)
+
+	s.Equal(http.StatusNotFound, s.do(http.MethodGet, s.server.URL+"/orders/100/refunds").StatusCode)
+}
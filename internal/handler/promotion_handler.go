@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/money"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+// discountTypes 折扣類型名稱
+var discountTypes = []promotionEntity.DiscountType{promotionEntity.DiscountTypePercent, promotionEntity.DiscountTypeFixed}
+
+// PromotionHandler 促銷活動 API
+//
+// Example:
+//
+//	h := handler.NewPromotionHandler(promotionSvc)
+//	srv.Handle("POST /promotions", h.Create())
+//	srv.Handle("GET /courses/{id}/checkout-preview", h.Preview())
+type PromotionHandler struct {
+	svc promotionService.PromotionService
+}
+
+// CreatePromotionRequest 新增促銷活動請求
+type CreatePromotionRequest struct {
+	Name          string     `json:"name"`
+	Code          string     `json:"code"`           // 優惠碼 , 空字串表示自動套用
+	DiscountType  string     `json:"discount_type"`  // percent / fixed
+	DiscountValue uint       `json:"discount_value"` // 百分比 (1-100) 或折抵金額 (以幣別的最小單位計)
+	Currency      string     `json:"currency"`       // 固定金額折抵的幣別
+	CourseID      uint       `json:"course_id"`      // 限定課程 , 0 表示不限
+	Category      string     `json:"category"`       // 限定課程分類
+	MinSeats      uint       `json:"min_seats"`      // 課程報名人數 (含本次) 達此人數才套用
+	UsageLimit    uint       `json:"usage_limit"`    // 總使用次數上限 , 0 表示不限
+	PerUserLimit  uint       `json:"per_user_limit"` // 每位學生使用次數上限 , 0 表示不限
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+}
+
+// PromotionResponse 促銷活動回應
+type PromotionResponse struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Code          string     `json:"code"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue uint       `json:"discount_value"`
+	Currency      string     `json:"currency"`
+	CourseID      uint       `json:"course_id"`
+	Category      string     `json:"category"`
+	MinSeats      uint       `json:"min_seats"`
+	UsageLimit    uint       `json:"usage_limit"`
+	PerUserLimit  uint       `json:"per_user_limit"`
+	UsedCount     uint       `json:"used_count"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// AppliedDiscountResponse 套用的折扣
+type AppliedDiscountResponse struct {
+	PromotionID uint        `json:"promotion_id"`
+	Name        string      `json:"name"`
+	Code        string      `json:"code"`
+	Amount      money.Money `json:"amount"`
+}
+
+// CheckoutPreviewResponse 結帳預覽回應
+type CheckoutPreviewResponse struct {
+	CourseID  uint                      `json:"course_id"`
+	ListPrice money.Money               `json:"list_price"`
+	Discounts []AppliedDiscountResponse `json:"discounts"`
+	Discount  money.Money               `json:"discount"`
+	Total     money.Money               `json:"total"`
+	Display   string                    `json:"display"`
+}
+
+// NewPromotionHandler 建立促銷活動 API
+// 參數: svc - 促銷活動業務邏輯
+func NewPromotionHandler(svc promotionService.PromotionService) *PromotionHandler {
+	return &PromotionHandler{svc: svc}
+}
+
+// Create 新增促銷活動 , 優惠碼已存在時回傳 409
+// 請求: CreatePromotionRequest
+func (h *PromotionHandler) Create() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreatePromotionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		discountType, ok := parseName(req.DiscountType, discountTypes)
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid discount_type %q", req.DiscountType)})
+			return
+		}
+
+		promotion := &promotionEntity.Promotion{
+			Name:          req.Name,
+			DiscountType:  discountType,
+			DiscountValue: req.DiscountValue,
+			Currency:      req.Currency,
+			CourseID:      req.CourseID,
+			Category:      req.Category,
+			MinSeats:      req.MinSeats,
+			UsageLimit:    req.UsageLimit,
+			PerUserLimit:  req.PerUserLimit,
+			StartsAt:      req.StartsAt,
+			EndsAt:        req.EndsAt,
+		}
+		if code := strings.TrimSpace(req.Code); code != "" {
+			promotion.Code = &code
+		}
+
+		promotion, err := h.svc.Create(r.Context(), promotion)
+		switch {
+		case errors.Is(err, promotionService.ErrInvalidPromotion), errors.Is(err, money.ErrUnsupportedCurrency):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, promotionService.ErrDuplicateCode):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "coupon code already exists"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newPromotionResponse(promotion))
+		}
+	})
+}
+
+// List 查詢促銷活動 , 依建立時間排序
+// 參數 (query string): page / page_size / order
+func (h *PromotionHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		promotions, err := h.svc.List(r.Context(), pageInfo)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]PromotionResponse, 0, len(promotions))
+		for _, promotion := range promotions {
+			data = append(data, newPromotionResponse(promotion))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[PromotionResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Get 查詢促銷活動
+// 參數 (path): id - 促銷活動ID
+func (h *PromotionHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		promotion, err := h.svc.GetByID(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "promotion not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newPromotionResponse(promotion))
+		}
+	})
+}
+
+// Deactivate 停用促銷活動 , 成功 (包含已停用) 回傳 204
+// 參數 (path): id - 促銷活動ID
+func (h *PromotionHandler) Deactivate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		err = h.svc.Deactivate(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "promotion not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// Preview 預覽學生報名課程的應付金額 , 不記錄使用次數
+// 優惠碼不存在回傳 404 , 無法使用 (不適用或已達使用次數上限) 回傳 422
+// 參數 (path): id - 課程ID
+// 參數 (query string):
+//   - student_id : 學生 user ID , 用於檢查每位學生的使用次數上限
+//   - code : 優惠碼
+func (h *PromotionHandler) Preview() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, err := parseUint(r, "student_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		pricing, err := h.svc.Preview(r.Context(), courseID, studentID, r.URL.Query().Get("code"))
+		switch {
+		case errors.Is(err, promotionService.ErrCouponNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "coupon not found"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case errors.Is(err, promotionService.ErrCouponNotApplicable),
+			errors.Is(err, promotionService.ErrCouponExhausted),
+			errors.Is(err, promotionService.ErrPerUserLimit):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newCheckoutPreviewResponse(courseID, pricing))
+		}
+	})
+}
+
+func newPromotionResponse(p *promotionEntity.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:            p.ID,
+		Name:          p.Name,
+		Code:          p.CodeString(),
+		DiscountType:  p.DiscountType.String(),
+		DiscountValue: p.DiscountValue,
+		Currency:      p.Currency,
+		CourseID:      p.CourseID,
+		Category:      p.Category,
+		MinSeats:      p.MinSeats,
+		UsageLimit:    p.UsageLimit,
+		PerUserLimit:  p.PerUserLimit,
+		UsedCount:     p.UsedCount,
+		StartsAt:      p.StartsAt,
+		EndsAt:        p.EndsAt,
+		DeactivatedAt: p.DeactivatedAt,
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+func newCheckoutPreviewResponse(courseID uint, p promotionEntity.Pricing) CheckoutPreviewResponse {
+	discounts := make([]AppliedDiscountResponse, 0, len(p.Discounts))
+	for _, d := range p.Discounts {
+		discounts = append(discounts, AppliedDiscountResponse{
+			PromotionID: d.PromotionID,
+			Name:        d.Name,
+			Code:        d.Code,
+			Amount:      money.Money{Amount: d.Amount, Currency: p.ListPrice.Currency},
+		})
+	}
+	return CheckoutPreviewResponse{
+		CourseID:  courseID,
+		ListPrice: p.ListPrice,
+		Discounts: discounts,
+		Discount:  p.Discount(),
+		Total:     p.Total,
+		Display:   p.Total.String(),
+	}
+}
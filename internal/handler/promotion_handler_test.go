@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	promotionRepository "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+type PromotionHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由 , 新增售價 TWD 3000 的課程 1 與優惠券 SAVE10 (促銷活動 1)
+func (s *PromotionHandlerTestSuite) SetupTest() {
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(context.Background(), &courseEntity.Course{Name: "Go 入門", Category: "programming", Price: 3000, Currency: "TWD", Status: courseEntity.CourseStatusOnline})
+	s.Require().NoError(err)
+
+	h := NewPromotionHandler(promotionService.NewPromotionService(promotionRepository.NewPromotionMemoryRepository(), courses, enrollmentRepository.NewEnrollmentMemoryRepository()))
+
+	s.mux = http.NewServeMux()
+	s.mux.Handle("POST /promotions", h.Create())
+	s.mux.Handle("GET /promotions", h.List())
+	s.mux.Handle("GET /promotions/{id}", h.Get())
+	s.mux.Handle("POST /promotions/{id}/deactivate", h.Deactivate())
+	s.mux.Handle("GET /courses/{id}/checkout-preview", h.Preview())
+
+	rec := s.serve(http.MethodPost, "/promotions", `{"name": "九折", "code": "save10", "discount_type": "percent", "discount_value": 10}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+}
+
+// TestPromotionHandlerSuite 執行測試套件
+func TestPromotionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(PromotionHandlerTestSuite))
+}
+
+func (s *PromotionHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *PromotionHandlerTestSuite) TestCreate() {
+	tests := []struct {
+		name       string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "automatic fixed",
+			body: `{"name": "三人團報折 500", "discount_type": "fixed", "discount_value": 500, "currency": "TWD", "min_seats": 3}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp PromotionResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Empty(t, resp.Code)
+				assert.Equal(t, "fixed", resp.DiscountType)
+				assert.EqualValues(t, 3, resp.MinSeats)
+			},
+		},
+		{
+			name: "duplicate code",
+			body: `{"name": "重複", "code": "SAVE10", "discount_type": "percent", "discount_value": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name: "invalid discount type",
+			body: `{"name": "錯誤", "discount_type": "free", "discount_value": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "invalid percent",
+			body: `{"name": "錯誤", "discount_type": "percent", "discount_value": 0}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, "/promotions", tt.body))
+		})
+	}
+}
+
+func (s *PromotionHandlerTestSuite) TestPreview() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "with coupon",
+			target: "/courses/1/checkout-preview?student_id=10&code=save10",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp CheckoutPreviewResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.EqualValues(t, 3000, resp.ListPrice.Amount)
+				assert.EqualValues(t, 300, resp.Discount.Amount)
+				assert.EqualValues(t, 2700, resp.Total.Amount)
+				assert.Equal(t, "TWD 27.00", resp.Display)
+				if assert.Len(t, resp.Discounts, 1) {
+					assert.Equal(t, "SAVE10", resp.Discounts[0].Code)
+				}
+			},
+		},
+		{
+			name:   "without coupon",
+			target: "/courses/1/checkout-preview",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp CheckoutPreviewResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Empty(t, resp.Discounts)
+				assert.EqualValues(t, 3000, resp.Total.Amount)
+			},
+		},
+		{
+			name:   "coupon not found",
+			target: "/courses/1/checkout-preview?code=UNKNOWN",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+				assert.Contains(t, rec.Body.String(), "coupon not found")
+			},
+		},
+		{
+			name:   "course not found",
+			target: "/courses/100/checkout-preview",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+				assert.Contains(t, rec.Body.String(), "course not found")
+			},
+		},
+		{
+			name:   "invalid student id",
+			target: "/courses/1/checkout-preview?student_id=abc",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodGet, tt.target, ""))
+		})
+	}
+}
+
+// 停用後不可再使用優惠券
+func (s *PromotionHandlerTestSuite) TestDeactivate() {
+	s.Equal(http.StatusNoContent, s.serve(http.MethodPost, "/promotions/1/deactivate", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPost, "/promotions/100/deactivate", "").Code)
+
+	rec := s.serve(http.MethodGet, "/promotions/1", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp PromotionResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.NotNil(resp.DeactivatedAt)
+
+	s.Equal(http.StatusUnprocessableEntity, s.serve(http.MethodGet, "/courses/1/checkout-preview?code=SAVE10", "").Code)
+}
+
+func (s *PromotionHandlerTestSuite) TestList() {
+	rec := s.serve(http.MethodGet, "/promotions?page_size=10", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp ListResponse[PromotionResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Len(resp.Data, 1)
+	s.Equal(10, resp.PageSize)
+}
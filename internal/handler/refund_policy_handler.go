@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	courseService "github.com/itmrchow/course-management-system/internal/service/course"
+)
+
+// refundAnchors 退款規則基準時間名稱
+var refundAnchors = []courseEntity.RefundAnchor{courseEntity.RefundAnchorRegistrationEnd, courseEntity.RefundAnchorStart}
+
+// RefundPolicyHandler 課程退款規則 API
+//
+// Example:
+//
+//	h := handler.NewRefundPolicyHandler(courseSvc)
+//	srv.Handle("GET /courses/{id}/refund-policy", h.Get())
+//	srv.Handle("PUT /courses/{id}/refund-policy", h.Put())
+type RefundPolicyHandler struct {
+	svc courseService.CourseService
+}
+
+// RefundRuleRequest 退款規則
+type RefundRuleRequest struct {
+	Anchor      string `json:"anchor"`       // registration_end / start
+	OffsetHours int    `json:"offset_hours"` // 相對基準時間的小時數 , 負數表示之前
+	Percent     uint   `json:"percent"`      // 退款比例 , 0-100
+}
+
+// PutRefundPolicyRequest 設定課程退款規則請求 , rules 為空時恢復預設規則
+type PutRefundPolicyRequest struct {
+	Rules []RefundRuleRequest `json:"rules"`
+}
+
+// RefundPolicyResponse 課程退款規則回應
+type RefundPolicyResponse struct {
+	CourseID uint                `json:"course_id"`
+	Default  bool                `json:"default"` // 課程沒有設定規則 , 使用預設規則
+	Rules    []RefundRuleRequest `json:"rules"`
+}
+
+// NewRefundPolicyHandler 建立課程退款規則 API
+// 參數: svc - 課程業務邏輯
+func NewRefundPolicyHandler(svc courseService.CourseService) *RefundPolicyHandler {
+	return &RefundPolicyHandler{svc: svc}
+}
+
+// Get 查詢課程的退款規則 , 沒有設定時回傳預設規則
+// 參數 (path): id - 課程ID
+func (h *RefundPolicyHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		rules, err := h.svc.ListRefundRules(r.Context(), courseID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newRefundPolicyResponse(courseID, rules))
+		}
+	})
+}
+
+// Put 以請求的規則取代課程的退款規則 , 只影響之後的退款
+// 參數 (path): id - 課程ID
+// 請求: PutRefundPolicyRequest
+func (h *RefundPolicyHandler) Put() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req PutRefundPolicyRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		rules := make([]*courseEntity.CourseRefundRule, 0, len(req.Rules))
+		for _, rule := range req.Rules {
+			anchor, ok := parseName(rule.Anchor, refundAnchors)
+			if !ok {
+				writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid anchor %q", rule.Anchor)})
+				return
+			}
+			rules = append(rules, &courseEntity.CourseRefundRule{Anchor: anchor, OffsetHours: rule.OffsetHours, Percent: rule.Percent})
+		}
+
+		rules, err = h.svc.SetRefundRules(r.Context(), courseID, rules)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case errors.Is(err, courseService.ErrInvalidRefundRule):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newRefundPolicyResponse(courseID, rules))
+		}
+	})
+}
+
+func newRefundPolicyResponse(courseID uint, rules []*courseEntity.CourseRefundRule) RefundPolicyResponse {
+	resp := RefundPolicyResponse{CourseID: courseID}
+	if len(rules) == 0 {
+		resp.Default = true
+		rules = courseEntity.DefaultRefundRules()
+	}
+	resp.Rules = make([]RefundRuleRequest, 0, len(rules))
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, RefundRuleRequest{
+			Anchor:      rule.Anchor.String(),
+			OffsetHours: rule.OffsetHours,
+			Percent:     rule.Percent,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	courseService "github.com/itmrchow/course-management-system/internal/service/course"
+)
+
+type RefundPolicyHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並新增沒有退款規則的課程 1
+func (s *RefundPolicyHandlerTestSuite) SetupTest() {
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(context.Background(), &courseEntity.Course{Name: "Go 入門", Price: 3000, Currency: "TWD"})
+	s.Require().NoError(err)
+
+	h := NewRefundPolicyHandler(courseService.NewCourseService(courses, outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction))
+
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /courses/{id}/refund-policy", h.Get())
+	s.mux.Handle("PUT /courses/{id}/refund-policy", h.Put())
+}
+
+// TestRefundPolicyHandlerSuite 執行測試套件
+func TestRefundPolicyHandlerSuite(t *testing.T) {
+	suite.Run(t, new(RefundPolicyHandlerTestSuite))
+}
+
+func (s *RefundPolicyHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *RefundPolicyHandlerTestSuite) TestGet() {
+	rec := s.serve(http.MethodGet, "/courses/1/refund-policy", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp RefundPolicyResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.True(resp.Default)
+	s.Require().Len(resp.Rules, 2)
+	s.Equal("registration_end", resp.Rules[0].Anchor)
+	s.EqualValues(100, resp.Rules[0].Percent)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/courses/100/refund-policy", "").Code)
+}
+
+func (s *RefundPolicyHandlerTestSuite) TestPut() {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success",
+			target: "/courses/1/refund-policy",
+			body:   `{"rules": [{"anchor": "start", "offset_hours": -48, "percent": 80}, {"anchor": "start", "percent": 20}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp RefundPolicyResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.False(t, resp.Default)
+				require.Len(t, resp.Rules, 2)
+				assert.Equal(t, -48, resp.Rules[0].OffsetHours)
+				assert.EqualValues(t, 80, resp.Rules[0].Percent)
+			},
+		},
+		{
+			name:   "invalid anchor",
+			target: "/courses/1/refund-policy",
+			body:   `{"rules": [{"anchor": "end", "percent": 50}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "invalid percent",
+			target: "/courses/1/refund-policy",
+			body:   `{"rules": [{"anchor": "start", "percent": 120}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "course not found",
+			target: "/courses/100/refund-policy",
+			body:   `{"rules": []}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPut, tt.target, tt.body))
+		})
+	}
+}
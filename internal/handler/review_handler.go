@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	reviewEntity "github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	reviewService "github.com/itmrchow/course-management-system/internal/service/review"
+)
+
+// maxReviewComment 評論的最大字數
+const maxReviewComment = 2000
+
+// reviewStatuses 評價狀態名稱
+var reviewStatuses = []reviewEntity.ReviewStatus{
+	reviewEntity.ReviewStatusPending,
+	reviewEntity.ReviewStatusApproved,
+	reviewEntity.ReviewStatusRejected,
+}
+
+// ReviewHandler 課程評價 API
+//
+// Example:
+//
+//	h := handler.NewReviewHandler(reviewSvc)
+//	srv.Handle("POST /enrollments/{id}/review", h.Submit())
+//	srv.Handle("GET /courses/{id}/reviews", h.ListByCourse())
+type ReviewHandler struct {
+	svc reviewService.ReviewService
+}
+
+// TeacherRatingRequest 對授課教師的評分
+type TeacherRatingRequest struct {
+	TeacherID uint `json:"teacher_id"`
+	Rating    uint `json:"rating"` // 1 至 5
+}
+
+// SubmitReviewRequest 新增或修改評價請求
+type SubmitReviewRequest struct {
+	StudentID      uint                   `json:"student_id"` // 評價的學生 user ID
+	Rating         uint                   `json:"rating"`     // 課程評分 , 1 至 5
+	Comment        string                 `json:"comment"`
+	TeacherRatings []TeacherRatingRequest `json:"teacher_ratings"` // 可只評分部分授課教師
+}
+
+// ModerateReviewRequest 審核評價請求
+type ModerateReviewRequest struct {
+	ModeratorID uint   `json:"moderator_id"` // 審核的管理者 user ID
+	Status      string `json:"status"`       // approved 或 rejected
+	Note        string `json:"note"`
+}
+
+// TeacherRatingResponse 對授課教師的評分回應
+type TeacherRatingResponse struct {
+	TeacherID uint `json:"teacher_id"`
+	Rating    uint `json:"rating"`
+}
+
+// ReviewResponse 評價回應
+type ReviewResponse struct {
+	ID             uint                    `json:"id"`
+	EnrollmentID   uint                    `json:"enrollment_id"`
+	CourseID       uint                    `json:"course_id"`
+	StudentID      uint                    `json:"student_id"`
+	Rating         uint                    `json:"rating"`
+	Comment        string                  `json:"comment"`
+	Status         string                  `json:"status"`
+	ModeratorID    uint                    `json:"moderator_id"`
+	ModeratedAt    *time.Time              `json:"moderated_at"`
+	ModerationNote string                  `json:"moderation_note"`
+	TeacherRatings []TeacherRatingResponse `json:"teacher_ratings,omitempty"` // 清單查詢時不包含
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// CourseReviewsResponse 課程評分統計與審核通過的評價
+type CourseReviewsResponse struct {
+	CourseID      uint             `json:"course_id"`
+	RatingAverage float64          `json:"rating_average"`
+	RatingCount   uint             `json:"rating_count"`
+	Data          []ReviewResponse `json:"data"`
+	Page          int              `json:"page"`
+	PageSize      int              `json:"page_size"`
+}
+
+// NewReviewHandler 建立課程評價 API
+// 參數: svc - 課程評價業務邏輯
+func NewReviewHandler(svc reviewService.ReviewService) *ReviewHandler {
+	return &ReviewHandler{svc: svc}
+}
+
+// Submit 學生評價已結束的課程 , 每筆報名一則評價 , 評價為待審核
+// 非報名的學生回傳 403 , 已退出、課程尚未結束或已評價回傳 409 , 評分的教師非授課教師回傳 422
+// 參數 (path): id - 報名ID
+// 請求: SubmitReviewRequest
+func (h *ReviewHandler) Submit() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enrollmentID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		req, submission, ok := decodeSubmitReviewRequest(w, r)
+		if !ok {
+			return
+		}
+
+		review, ratings, err := h.svc.Submit(r.Context(), enrollmentID, req.StudentID, submission)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "enrollment not found"})
+		case err != nil:
+			writeReviewError(w, r, err)
+		default:
+			writeJSON(w, http.StatusCreated, newReviewResponse(review, ratings))
+		}
+	})
+}
+
+// Update 學生修改評價 , 修改後改為待審核
+// 非評價的學生回傳 403 , 評分的教師非授課教師回傳 422
+// 參數 (path): id - 評價ID
+// 請求: SubmitReviewRequest
+func (h *ReviewHandler) Update() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		req, submission, ok := decodeSubmitReviewRequest(w, r)
+		if !ok {
+			return
+		}
+
+		review, ratings, err := h.svc.Update(r.Context(), id, req.StudentID, submission)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "review not found"})
+		case err != nil:
+			writeReviewError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newReviewResponse(review, ratings))
+		}
+	})
+}
+
+// Moderate 審核評價 , 已審核的評價可重新審核 , 狀態無法轉換時回傳 409
+// 參數 (path): id - 評價ID
+// 請求: ModerateReviewRequest
+func (h *ReviewHandler) Moderate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req ModerateReviewRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		if req.ModeratorID == 0 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "moderator_id is required"})
+			return
+		}
+		to, ok := parseName(req.Status, reviewStatuses)
+		if !ok || to == reviewEntity.ReviewStatusPending {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid status %q , must be approved or rejected", req.Status)})
+			return
+		}
+		req.Note = strings.TrimSpace(req.Note)
+		if len([]rune(req.Note)) > 255 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "note must be at most 255 characters"})
+			return
+		}
+
+		review, err := h.svc.Moderate(r.Context(), id, req.ModeratorID, to, req.Note)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "review not found"})
+		case errors.Is(err, reviewService.ErrInvalidTransition):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newReviewResponse(review, nil))
+		}
+	})
+}
+
+// Get 查詢評價與教師評分
+// 參數 (path): id - 評價ID
+func (h *ReviewHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		review, ratings, err := h.svc.Get(r.Context(), id)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "review not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newReviewResponse(review, ratings))
+		}
+	})
+}
+
+// List 查詢評價清單 , 用於審核與查詢學生的評價 , 依建立時間排序
+// 參數 (query): course_id - 課程ID, student_id - 學生 user ID, status - 評價狀態 , 例如 pending, page, page_size, order
+func (h *ReviewHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		courseID, err := parseUint(r, "course_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		studentID, err := parseUint(r, "student_id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		var statuses []reviewEntity.ReviewStatus
+		status, ok, err := parseStatus(r, reviewStatuses)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if ok {
+			statuses = append(statuses, status)
+		}
+
+		reviews, err := h.svc.List(r.Context(), pageInfo, courseID, studentID, statuses)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]ReviewResponse, 0, len(reviews))
+		for _, review := range reviews {
+			data = append(data, newReviewResponse(review, nil))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[ReviewResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// ListByCourse 查詢課程的平均評分與審核通過的評價 , 依建立時間排序
+// 參數 (path): id - 課程ID
+// 參數 (query): page, page_size, order
+func (h *ReviewHandler) ListByCourse() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		result, err := h.svc.ListByCourse(r.Context(), courseID, pageInfo)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			resp := CourseReviewsResponse{
+				CourseID:      result.Course.ID,
+				RatingAverage: result.Course.RatingAverage,
+				RatingCount:   result.Course.RatingCount,
+				Data:          make([]ReviewResponse, 0, len(result.Reviews)),
+				Page:          pageInfo.Page,
+				PageSize:      pageInfo.PageSize,
+			}
+			for _, review := range result.Reviews {
+				resp.Data = append(resp.Data, newReviewResponse(review, nil))
+			}
+			writeJSON(w, http.StatusOK, resp)
+		}
+	})
+}
+
+// decodeSubmitReviewRequest 解析並驗證新增或修改評價請求 , 驗證失敗時寫入 400 回應並回傳 false
+// 評分範圍由 ReviewService 檢查
+func decodeSubmitReviewRequest(w http.ResponseWriter, r *http.Request) (SubmitReviewRequest, reviewService.Submission, bool) {
+	var req SubmitReviewRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return req, reviewService.Submission{}, false
+	}
+	if req.StudentID == 0 {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "student_id is required"})
+		return req, reviewService.Submission{}, false
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if len([]rune(req.Comment)) > maxReviewComment {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("comment must be at most %d characters", maxReviewComment)})
+		return req, reviewService.Submission{}, false
+	}
+
+	submission := reviewService.Submission{
+		Rating:         req.Rating,
+		Comment:        req.Comment,
+		TeacherRatings: make(map[uint]uint, len(req.TeacherRatings)),
+	}
+	for _, tr := range req.TeacherRatings {
+		if tr.TeacherID == 0 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "teacher_id is required"})
+			return req, reviewService.Submission{}, false
+		}
+		if _, ok := submission.TeacherRatings[tr.TeacherID]; ok {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("duplicate teacher_id %d", tr.TeacherID)})
+			return req, reviewService.Submission{}, false
+		}
+		submission.TeacherRatings[tr.TeacherID] = tr.Rating
+	}
+	return req, submission, true
+}
+
+// writeReviewError 將新增或修改評價的業務錯誤轉換為回應
+func writeReviewError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, reviewService.ErrNotReviewer):
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, reviewService.ErrNotCompleted),
+		errors.Is(err, reviewService.ErrAlreadyReviewed):
+		writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, reviewService.ErrInvalidRating),
+		errors.Is(err, reviewService.ErrNotCourseTeacher):
+		writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+	default:
+		writeInternalError(w, r, err)
+	}
+}
+
+func newReviewResponse(review *reviewEntity.Review, ratings []*reviewEntity.ReviewTeacherRating) ReviewResponse {
+	resp := ReviewResponse{
+		ID:             review.ID,
+		EnrollmentID:   review.EnrollmentID,
+		CourseID:       review.CourseID,
+		StudentID:      review.StudentID,
+		Rating:         review.Rating,
+		Comment:        review.Comment,
+		Status:         review.Status.String(),
+		ModeratorID:    review.ModeratorID,
+		ModeratedAt:    review.ModeratedAt,
+		ModerationNote: review.ModerationNote,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+	for _, rating := range ratings {
+		resp.TeacherRatings = append(resp.TeacherRatings, TeacherRatingResponse{TeacherID: rating.TeacherID, Rating: rating.Rating})
+	}
+	return resp
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	reviewRepository "github.com/itmrchow/course-management-system/internal/repository/review"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	reviewService "github.com/itmrchow/course-management-system/internal/service/review"
+)
+
+type ReviewHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由
+// 課程 1 已結束 , 授課教師為教師 1 , 報名 1 與 2 為學生 10 與 11 ; 課程 2 開放報名中 , 報名 3 為學生 10
+func (s *ReviewHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	teachers := teacherRepository.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 1, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 2, Name: "陳助教", Phone: "0912345679", Email: "chen@example.com", Status: teacherEntity.TeacherStatusApproved},
+	} {
+		_, err := teachers.Create(ctx, teacher)
+		s.Require().NoError(err)
+	}
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusEnd})
+	s.Require().NoError(err)
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Status: courseEntity.CourseStatusOnline})
+	s.Require().NoError(err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	s.Require().NoError(err)
+
+	enrollments := enrollmentRepository.NewEnrollmentMemoryRepository()
+	for _, e := range []*enrollmentEntity.Enrollment{
+		{CourseID: 1, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 11, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 2, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+	} {
+		_, err := enrollments.Create(ctx, e)
+		s.Require().NoError(err)
+	}
+
+	svc := reviewService.NewReviewService(reviewRepository.NewReviewMemoryRepository(), courses, teachers, enrollments,
+		outboxRepository.NewOutboxMemoryRepository(), repository.NoTransaction)
+
+	h := NewReviewHandler(svc)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("POST /enrollments/{id}/review", h.Submit())
+	s.mux.Handle("GET /reviews", h.List())
+	s.mux.Handle("GET /reviews/{id}", h.Get())
+	s.mux.Handle("PUT /reviews/{id}", h.Update())
+	s.mux.Handle("POST /reviews/{id}/moderate", h.Moderate())
+	s.mux.Handle("GET /courses/{id}/reviews", h.ListByCourse())
+}
+
+// TestReviewHandlerSuite 執行測試套件
+func TestReviewHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ReviewHandlerTestSuite))
+}
+
+func (s *ReviewHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *ReviewHandlerTestSuite) TestSubmit() {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "評價課程與教師",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 10, "rating": 5, "comment": " 很實用 ", "teacher_ratings": [{"teacher_id": 1, "rating": 4}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp ReviewResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.EqualValues(t, 1, resp.CourseID)
+				assert.EqualValues(t, 5, resp.Rating)
+				assert.Equal(t, "很實用", resp.Comment)
+				assert.Equal(t, "pending", resp.Status)
+				assert.Equal(t, []TeacherRatingResponse{{TeacherID: 1, Rating: 4}}, resp.TeacherRatings)
+			},
+		},
+		{
+			name:   "非報名的學生",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 11, "rating": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+			},
+		},
+		{
+			name:   "課程尚未結束",
+			target: "/enrollments/3/review",
+			body:   `{"student_id": 10, "rating": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name:   "評分超出範圍",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 10, "rating": 6}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:   "評分非授課教師",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 10, "rating": 5, "teacher_ratings": [{"teacher_id": 2, "rating": 4}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:   "重複評分同一位教師",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 10, "rating": 5, "teacher_ratings": [{"teacher_id": 1, "rating": 4}, {"teacher_id": 1, "rating": 3}]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "評論過長",
+			target: "/enrollments/1/review",
+			body:   `{"student_id": 10, "rating": 5, "comment": "` + strings.Repeat("好", maxReviewComment+1) + `"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "缺少學生",
+			target: "/enrollments/1/review",
+			body:   `{"rating": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "報名不存在",
+			target: "/enrollments/100/review",
+			body:   `{"student_id": 10, "rating": 5}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, tt.target, tt.body))
+		})
+	}
+}
+
+func (s *ReviewHandlerTestSuite) TestModerate() {
+	rec := s.serve(http.MethodPost, "/enrollments/1/review", `{"student_id": 10, "rating": 4, "teacher_ratings": [{"teacher_id": 1, "rating": 5}]}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	s.Equal(http.StatusConflict, s.serve(http.MethodPost, "/enrollments/1/review", `{"student_id": 10, "rating": 4}`).Code)
+	rec = s.serve(http.MethodPost, "/enrollments/2/review", `{"student_id": 11, "rating": 2}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	var second ReviewResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &second))
+	secondPath := fmt.Sprintf("/reviews/%d/moderate", second.ID)
+
+	// 待審核的評價不公開
+	rec = s.serve(http.MethodGet, "/courses/1/reviews", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.JSONEq(`{"course_id": 1, "rating_average": 0, "rating_count": 0, "data": [], "page": 1, "page_size": 20}`, rec.Body.String())
+
+	rec = s.serve(http.MethodGet, "/reviews?course_id=1&status=pending", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var list ListResponse[ReviewResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &list))
+	s.Len(list.Data, 2)
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/reviews?status=deleted", "").Code)
+
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodPost, "/reviews/1/moderate", `{"moderator_id": 99, "status": "pending"}`).Code)
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodPost, "/reviews/1/moderate", `{"status": "approved"}`).Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPost, "/reviews/100/moderate", `{"moderator_id": 99, "status": "approved"}`).Code)
+	for _, target := range []string{"/reviews/1/moderate", secondPath} {
+		rec = s.serve(http.MethodPost, target, `{"moderator_id": 99, "status": "approved"}`)
+		s.Require().Equal(http.StatusOK, rec.Code)
+	}
+	s.Equal(http.StatusConflict, s.serve(http.MethodPost, "/reviews/1/moderate", `{"moderator_id": 99, "status": "approved"}`).Code)
+
+	rec = s.serve(http.MethodGet, "/courses/1/reviews?order=asc", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var course CourseReviewsResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &course))
+	s.InDelta(3.0, course.RatingAverage, 1e-9)
+	s.EqualValues(2, course.RatingCount)
+	s.Require().Len(course.Data, 2)
+	s.EqualValues(10, course.Data[0].StudentID)
+
+	// 未通過的評價從評分統計中移除
+	rec = s.serve(http.MethodPost, secondPath, `{"moderator_id": 99, "status": "rejected", "note": "離題"}`)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var rejected ReviewResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &rejected))
+	s.Equal("rejected", rejected.Status)
+	s.Equal("離題", rejected.ModerationNote)
+	s.EqualValues(99, rejected.ModeratorID)
+
+	rec = s.serve(http.MethodGet, "/courses/1/reviews", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &course))
+	s.InDelta(4.0, course.RatingAverage, 1e-9)
+	s.EqualValues(1, course.RatingCount)
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/courses/100/reviews", "").Code)
+}
+
+func (s *ReviewHandlerTestSuite) TestUpdate() {
+	rec := s.serve(http.MethodPost, "/enrollments/1/review", `{"student_id": 10, "rating": 4, "teacher_ratings": [{"teacher_id": 1, "rating": 5}]}`)
+	s.Require().Equal(http.StatusCreated, rec.Code)
+	s.Require().Equal(http.StatusOK, s.serve(http.MethodPost, "/reviews/1/moderate", `{"moderator_id": 99, "status": "approved"}`).Code)
+
+	s.Equal(http.StatusForbidden, s.serve(http.MethodPut, "/reviews/1", `{"student_id": 11, "rating": 1}`).Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPut, "/reviews/100", `{"student_id": 10, "rating": 1}`).Code)
+
+	rec = s.serve(http.MethodPut, "/reviews/1", `{"student_id": 10, "rating": 2, "comment": "後半段太快"}`)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp ReviewResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("pending", resp.Status)
+	s.EqualValues(2, resp.Rating)
+	s.Empty(resp.TeacherRatings)
+
+	rec = s.serve(http.MethodGet, "/reviews/1", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("後半段太快", resp.Comment)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/reviews/100", "").Code)
+
+	// 修改後重新審核前不計入評分統計
+	rec = s.serve(http.MethodGet, "/courses/1/reviews", "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var course CourseReviewsResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &course))
+	s.EqualValues(0, course.RatingCount)
+	s.Empty(course.Data)
+}
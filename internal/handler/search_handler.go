@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/search"
+)
+
+// SearchHandler 課程與教師關鍵字搜尋 API
+//
+// Example:
+//
+//	h := handler.NewSearchHandler(teacherRepo, courseRepo)
+//	srv.Handle("GET /search/teachers", h.Teachers())
+//	srv.Handle("GET /search/courses", h.Courses())
+type SearchHandler struct {
+	teacherRepo teacherRepository.TeacherRepository
+	courseRepo  courseRepository.CourseRepository
+}
+
+// TeacherSearchResponse 教師搜尋結果
+// Highlights 為比對到的欄位摘要 , 以 <mark> 標示比對到的文字 , 其餘內容已做 HTML 跳脫
+type TeacherSearchResponse struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// CourseSearchResponse 課程搜尋結果
+// Highlights 為比對到的欄位摘要 , 以 <mark> 標示比對到的文字 , 其餘內容已做 HTML 跳脫
+type CourseSearchResponse struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	Price      uint              `json:"price"`
+	Currency   string            `json:"currency"`
+	IsOnline   bool              `json:"is_online"`
+	StartDate  time.Time         `json:"start_date"`
+	EndDate    time.Time         `json:"end_date"`
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// NewSearchHandler 建立搜尋 API
+// 參數: teacherRepo - 教師 repository, courseRepo - 課程 repository
+func NewSearchHandler(teacherRepo teacherRepository.TeacherRepository, courseRepo courseRepository.CourseRepository) *SearchHandler {
+	return &SearchHandler{teacherRepo: teacherRepo, courseRepo: courseRepo}
+}
+
+// Teachers 搜尋教師姓名與簡介 , 依相關度排序
+// 參數 (query string):
+//   - q : 關鍵字 , 必填
+//   - status : 教師狀態 , 例如 approved
+//   - page , page_size : 分頁
+func (h *SearchHandler) Teachers() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, pageInfo, err := parseSearchQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var conditions []func(db *gorm.DB) *gorm.DB
+		status, ok, err := parseStatus(r, []teacherEntity.TeacherStatus{
+			teacherEntity.TeacherStatusPending,
+			teacherEntity.TeacherStatusApproved,
+			teacherEntity.TeacherStatusRejected,
+			teacherEntity.TeacherStatusDisabled,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if ok {
+			conditions = append(conditions, teacherEntity.InTeacherStatus([]teacherEntity.TeacherStatus{status}))
+		}
+
+		hits, err := h.teacherRepo.Search(r.Context(), query, pageInfo, conditions)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]TeacherSearchResponse, 0, len(hits))
+		for _, hit := range hits {
+			data = append(data, TeacherSearchResponse{
+				ID:     hit.Item.ID,
+				Name:   hit.Item.Name,
+				Status: hit.Item.Status.String(),
+				Rank:   hit.Rank,
+				Highlights: highlights(query, map[string]string{
+					"name": hit.Item.Name,
+					"bio":  hit.Item.Bio,
+				}),
+			})
+		}
+		writeJSON(w, http.StatusOK, ListResponse[TeacherSearchResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Courses 搜尋課程名稱、描述與備註 , 依相關度排序
+// 參數 (query string):
+//   - q : 關鍵字 , 必填
+//   - status : 課程狀態 , 例如 online
+//   - page , page_size : 分頁
+func (h *SearchHandler) Courses() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, pageInfo, err := parseSearchQuery(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var conditions []func(db *gorm.DB) *gorm.DB
+		status, ok, err := parseStatus(r, []courseEntity.CourseStatus{
+			courseEntity.CourseStatusDraft,
+			courseEntity.CourseStatusPending,
+			courseEntity.CourseStatusOnline,
+			courseEntity.CourseStatusEnd,
+			courseEntity.CourseStatusPause,
+			courseEntity.CourseStatusCancelled,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if ok {
+			conditions = append(conditions, courseEntity.InCourseStatus([]courseEntity.CourseStatus{status}))
+		}
+
+		hits, err := h.courseRepo.Search(r.Context(), query, pageInfo, conditions)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]CourseSearchResponse, 0, len(hits))
+		for _, hit := range hits {
+			data = append(data, CourseSearchResponse{
+				ID:        hit.Item.ID,
+				Name:      hit.Item.Name,
+				Status:    hit.Item.Status.String(),
+				Price:     hit.Item.Price,
+				Currency:  hit.Item.Currency,
+				IsOnline:  hit.Item.IsOnline,
+				StartDate: hit.Item.StartDate,
+				EndDate:   hit.Item.EndDate,
+				Rank:      hit.Rank,
+				Highlights: highlights(query, map[string]string{
+					"name":        hit.Item.Name,
+					"description": hit.Item.Description,
+					"note":        hit.Item.Note,
+				}),
+			})
+		}
+		writeJSON(w, http.StatusOK, ListResponse[CourseSearchResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// parseSearchQuery 解析關鍵字與分頁參數 , 搜尋結果固定依相關度排序
+func parseSearchQuery(r *http.Request) (search.Query, *repo.RepoPageInfo, error) {
+	pageInfo, err := parsePageInfo(r, "")
+	if err != nil {
+		return search.Query{}, nil, err
+	}
+
+	query, err := search.Parse(r.URL.Query().Get("q"))
+	if err != nil {
+		return search.Query{}, nil, fmt.Errorf("invalid q: %w", err)
+	}
+	return query, pageInfo, nil
+}
+
+// parseStatus 依狀態名稱 (String) 解析 status 參數
+// 回傳: 狀態, 是否有指定狀態, 錯誤訊息
+func parseStatus[S fmt.Stringer](r *http.Request, statuses []S) (S, bool, error) {
+	v := r.URL.Query().Get("status")
+	if v == "" {
+		var zero S
+		return zero, false, nil
+	}
+	status, ok := parseName(v, statuses)
+	if !ok {
+		return status, false, fmt.Errorf("invalid status %q", v)
+	}
+	return status, true, nil
+}
+
+// highlights 取得各欄位比對到的摘要 , 沒有比對到的欄位不列入
+func highlights(query search.Query, fields map[string]string) map[string]string {
+	result := make(map[string]string)
+	for name, text := range fields {
+		if snippet := query.Snippet(text, search.SnippetLength); snippet != "" {
+			result[name] = snippet
+		}
+	}
+	return result
+}
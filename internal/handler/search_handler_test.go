@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+type SearchHandlerTestSuite struct {
+	suite.Suite
+	mux *http.ServeMux
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並新增測試資料
+func (s *SearchHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+
+	teachers := teacherRepository.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 1, Name: "王小明", Phone: "0911000001", Email: "ming@example.com", Bio: "十年 Go 後端開發經驗", Status: teacherEntity.TeacherStatusApproved}, // id 1
+		{UserID: 2, Name: "Go Lin", Phone: "0911000002", Email: "lin@example.com", Status: teacherEntity.TeacherStatusPending},                     // id 2
+	} {
+		_, err := teachers.Create(ctx, teacher)
+		s.Require().NoError(err)
+	}
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", Description: "從 <Hello World> 開始學 Go", StartDate: start, EndDate: start.AddDate(0, 1, 0), Status: courseEntity.CourseStatusOnline}, // id 1
+		{Name: "資料庫設計", Description: "以 Go 實作 repository", StartDate: start, EndDate: start.AddDate(0, 1, 0), Status: courseEntity.CourseStatusDraft},      // id 2
+	} {
+		_, err := courses.Create(ctx, course)
+		s.Require().NoError(err)
+	}
+
+	h := NewSearchHandler(teachers, courses)
+	s.mux = http.NewServeMux()
+	s.mux.Handle("GET /search/teachers", h.Teachers())
+	s.mux.Handle("GET /search/courses", h.Courses())
+}
+
+// TestSearchHandlerSuite 執行測試套件
+func TestSearchHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SearchHandlerTestSuite))
+}
+
+func (s *SearchHandlerTestSuite) serve(target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func (s *SearchHandlerTestSuite) TestTeachers() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ranked with highlights",
+			target: "/search/teachers?q=go",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp ListResponse[TeacherSearchResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 2)
+				assert.EqualValues(t, 2, resp.Data[0].ID)
+				assert.Equal(t, map[string]string{"name": "<mark>Go</mark> Lin"}, resp.Data[0].Highlights)
+				assert.EqualValues(t, 1, resp.Data[1].ID)
+				assert.Equal(t, map[string]string{"bio": "十年 <mark>Go</mark> 後端開發經驗"}, resp.Data[1].Highlights)
+				assert.Equal(t, 1, resp.Page)
+				assert.Equal(t, defaultPageSize, resp.PageSize)
+			},
+		},
+		{
+			name:   "status",
+			target: "/search/teachers?q=go&status=approved",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp ListResponse[TeacherSearchResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 1)
+				assert.Equal(t, "approved", resp.Data[0].Status)
+			},
+		},
+		{
+			name:   "invalid status",
+			target: "/search/teachers?q=go&status=unknown",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "missing q",
+			target: "/search/teachers?q=%20",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(tt.target))
+		})
+	}
+}
+
+func (s *SearchHandlerTestSuite) TestCourses() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "escaped highlights",
+			target: "/search/courses?q=hello",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp ListResponse[CourseSearchResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 1)
+				assert.Equal(t, "從 &lt;<mark>Hello</mark> World&gt; 開始學 Go", resp.Data[0].Highlights["description"])
+				assert.NotContains(t, resp.Data[0].Highlights, "name")
+			},
+		},
+		{
+			name:   "chinese",
+			target: "/search/courses?q=資料庫",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp ListResponse[CourseSearchResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 1)
+				assert.EqualValues(t, 2, resp.Data[0].ID)
+				assert.Equal(t, "<mark>資料庫</mark>設計", resp.Data[0].Highlights["name"])
+			},
+		},
+		{
+			name:   "status and pagination",
+			target: "/search/courses?q=go&status=online&page=1&page_size=1",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp ListResponse[CourseSearchResponse]
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 1)
+				assert.EqualValues(t, 1, resp.Data[0].ID)
+				assert.Equal(t, "online", resp.Data[0].Status)
+				assert.Equal(t, 1, resp.PageSize)
+			},
+		},
+		{
+			name:   "invalid page_size",
+			target: "/search/courses?q=go&page_size=1000",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(tt.target))
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	sessionService "github.com/itmrchow/course-management-system/internal/service/session"
+)
+
+// defaultSessionRange 未指定 to 時查詢的上課範圍
+const defaultSessionRange = 30 * 24 * time.Hour
+
+// SessionHandler 課程上課 API
+//
+// Example:
+//
+//	h := handler.NewSessionHandler(sessionSvc)
+//	srv.Handle("GET /courses/{id}/sessions", h.ListByCourse())
+//	srv.Handle("POST /course-sessions/{id}/reschedule", h.Reschedule())
+type SessionHandler struct {
+	svc sessionService.SessionService
+	now func() time.Time
+}
+
+// CancelSessionRequest 取消上課請求
+type CancelSessionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RescheduleSessionRequest 改期上課請求
+type RescheduleSessionRequest struct {
+	StartAt time.Time `json:"start_at"` // 改期後的開始時間 , RFC3339 , 必填
+	EndAt   time.Time `json:"end_at"`   // 改期後的結束時間 , RFC3339 , 必填
+}
+
+// SessionResponse 上課回應 , 不包含主持人金鑰
+type SessionResponse struct {
+	ID                uint       `json:"id"`
+	CourseID          uint       `json:"course_id"`
+	PatternID         uint       `json:"pattern_id"`
+	StartAt           time.Time  `json:"start_at"`
+	EndAt             time.Time  `json:"end_at"`
+	Status            string     `json:"status"`
+	CancelledAt       *time.Time `json:"cancelled_at"`
+	CancelReason      string     `json:"cancel_reason"`
+	RemindedAt        *time.Time `json:"reminded_at"`
+	RescheduledFromID uint       `json:"rescheduled_from_id"` // 改期前的上課ID , 0 表示不是改期產生的上課
+	MeetingProvider   string     `json:"meeting_provider"`    // 線上會議服務 , 未建立會議為空字串
+	JoinURL           string     `json:"join_url"`            // 加入會議網址
+}
+
+// SessionMeetingResponse 上課的線上會議回應 , 只提供給授課教師
+type SessionMeetingResponse struct {
+	SessionID uint   `json:"session_id"`
+	Provider  string `json:"provider"`
+	MeetingID string `json:"meeting_id"`
+	JoinURL   string `json:"join_url"`
+	HostKey   string `json:"host_key"` // 主持人金鑰 , 會議服務不提供時為空字串
+}
+
+// SessionListResponse 上課清單回應 , 以時間範圍查詢 , 不分頁
+type SessionListResponse struct {
+	Data []SessionResponse `json:"data"`
+}
+
+// NewSessionHandler 建立課程上課 API
+// 參數: svc - 上課業務邏輯
+func NewSessionHandler(svc sessionService.SessionService) *SessionHandler {
+	return &SessionHandler{svc: svc, now: time.Now}
+}
+
+// ListByCourse 查詢課程的上課 , 依開始時間排序
+// 參數 (path): id - 課程ID
+// 參數 (query string):
+//   - from , to : 上課開始時間範圍 [from, to] , RFC3339 格式 , 預設為現在起 30 天 , 不可超過一年
+func (h *SessionHandler) ListByCourse() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		courseID, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		from, to, err := h.parseRange(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		sessions, err := h.svc.ListByCourse(r.Context(), courseID, from, to)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "course not found"})
+			return
+		case errors.Is(err, sessionService.ErrInvalidRange):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		case err != nil:
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]SessionResponse, 0, len(sessions))
+		for _, session := range sessions {
+			data = append(data, newSessionResponse(session))
+		}
+		writeJSON(w, http.StatusOK, SessionListResponse{Data: data})
+	})
+}
+
+// Cancel 取消上課 , 已取消的上課回傳 409
+// 參數 (path): id - 上課ID
+// 請求: CancelSessionRequest
+func (h *SessionHandler) Cancel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req CancelSessionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		if len([]rune(req.Reason)) > 255 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "reason must be at most 255 characters"})
+			return
+		}
+
+		err = h.svc.Cancel(r.Context(), id, req.Reason)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "session not found"})
+		case errors.Is(err, sessionService.ErrAlreadyCancelled):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "session already cancelled"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// Reschedule 改期上課 , 回傳改期後的上課
+// 已取消或改期的時間已有上課回傳 409 , 上課已開始或改期的時間已過回傳 422
+// 參數 (path): id - 上課ID
+// 請求: RescheduleSessionRequest
+func (h *SessionHandler) Reschedule() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		var req RescheduleSessionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		if req.StartAt.IsZero() || req.EndAt.IsZero() {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "start_at and end_at are required"})
+			return
+		}
+		if !req.EndAt.After(req.StartAt) {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "end_at must be after start_at"})
+			return
+		}
+
+		session, err := h.svc.Reschedule(r.Context(), id, req.StartAt, req.EndAt)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "session not found"})
+		case errors.Is(err, sessionService.ErrAlreadyCancelled):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "session already cancelled"})
+		case errors.Is(err, sessionService.ErrSlotTaken):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, sessionService.ErrSessionStarted),
+			errors.Is(err, sessionService.ErrInvalidSchedule):
+			writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, newSessionResponse(session))
+		}
+	})
+}
+
+// Meeting 授課教師查詢上課的線上會議 , 包含主持人金鑰 , 教師為 server.UserIDMiddleware 取得的操作者
+// 未登入回傳 401 , 不是授課教師回傳 403 , 上課不存在或尚未建立會議回傳 404
+// 參數 (path): id - 上課ID
+func (h *SessionHandler) Meeting() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		userID, ok := requireUserID(w, r)
+		if !ok {
+			return
+		}
+
+		session, err := h.svc.GetMeeting(r.Context(), id, userID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "session not found"})
+		case errors.Is(err, sessionService.ErrNoMeeting):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "meeting not found"})
+		case errors.Is(err, sessionService.ErrNotCourseTeacher):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: "not a teacher of the course"})
+		case err != nil:
+			writeInternalError(w, r, err)
+		default:
+			writeJSON(w, http.StatusOK, SessionMeetingResponse{
+				SessionID: session.ID,
+				Provider:  session.MeetingProvider,
+				MeetingID: session.MeetingID,
+				JoinURL:   session.JoinURL,
+				HostKey:   session.HostKey,
+			})
+		}
+	})
+}
+
+// parseRange 解析上課開始時間範圍 , 預設為現在起 defaultSessionRange
+func (h *SessionHandler) parseRange(r *http.Request) (time.Time, time.Time, error) {
+	return parseTimeRange(r, h.now(), defaultSessionRange)
+}
+
+// parseTimeRange 解析 from / to 參數 , RFC3339 格式 , 預設為 from 起 defaultRange
+// 參數: r - 請求, from - 未指定 from 時的開始時間, defaultRange - 未指定 to 時的範圍
+func parseTimeRange(r *http.Request, from time.Time, defaultRange time.Duration) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	if v := query.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from %q , must be RFC3339", v)
+		}
+		from = t
+	}
+
+	to := from.Add(defaultRange)
+	if v := query.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to %q , must be RFC3339", v)
+		}
+		to = t
+	}
+	return from, to, nil
+}
+
+func newSessionResponse(s *courseEntity.CourseSession) SessionResponse {
+	return SessionResponse{
+		ID:           s.ID,
+		CourseID:     s.CourseID,
+		PatternID:    s.PatternID,
+		StartAt:      s.StartAt,
+		EndAt:        s.EndAt,
+		Status:       s.Status.String(),
+		CancelledAt:  s.CancelledAt,
+		CancelReason: s.CancelReason,
+		RemindedAt:   s.RemindedAt,
+
+		RescheduledFromID: s.RescheduledFromID,
+		MeetingProvider:   s.MeetingProvider,
+		JoinURL:           s.JoinURL,
+	}
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/meeting"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/server"
+	sessionService "github.com/itmrchow/course-management-system/internal/service/session"
+)
+
+type SessionHandlerTestSuite struct {
+	suite.Suite
+	handler  http.Handler
+	now      time.Time
+	upcoming time.Time // 課程 2 的開始日期
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並新增每週一 11:00 上課的課程 1
+// 課程 2 為線上課程 , 於明天起兩週內每週同一天 11:00 上課 , 授課教師為教師 1 (user 100) ; 改期以實際時間判斷是否已過 , 因此使用未來的日期
+func (s *SessionHandlerTestSuite) SetupTest() {
+	ctx := context.Background()
+	s.now = time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC) // 星期一
+	s.upcoming = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	courses := courseRepository.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", StartDate: s.now, EndDate: s.now.AddDate(0, 2, 0)})
+	s.Require().NoError(err)
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "線上 Go 入門", IsOnline: true, StartDate: s.upcoming, EndDate: s.upcoming.AddDate(0, 0, 14)})
+	s.Require().NoError(err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 2, TeacherID: 1, IsMain: true})
+	s.Require().NoError(err)
+	for _, pattern := range []*courseEntity.CoursePattern{
+		{CourseID: 1, DayOfWeek: uint(time.Monday)},
+		{CourseID: 2, DayOfWeek: uint(s.upcoming.Weekday())},
+	} {
+		pattern.StartTime = time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)
+		pattern.EndTime = time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+		_, err = courses.CreatePattern(ctx, pattern)
+		s.Require().NoError(err)
+	}
+
+	teachers := teacherRepository.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 100, Name: "王老師", Phone: "0911000100", Email: "wang@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 101, Name: "李老師", Phone: "0911000101", Email: "lee@example.com", Status: teacherEntity.TeacherStatusApproved},
+	} {
+		_, err = teachers.Create(ctx, teacher)
+		s.Require().NoError(err)
+	}
+
+	svc := sessionService.NewSessionService(courses, sessionRepository.NewSessionMemoryRepository(), teachers, repository.NoTransaction,
+		sessionService.Config{MeetingLead: 7 * 24 * time.Hour, BatchSize: 100}, meeting.NewFakeMeeting(meeting.FakeConfig{BaseURL: "http://localhost:8080"}))
+	h := NewSessionHandler(svc)
+	h.now = func() time.Time { return s.now }
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /courses/{id}/sessions", h.ListByCourse())
+	mux.Handle("POST /course-sessions/{id}/cancel", h.Cancel())
+	mux.Handle("POST /course-sessions/{id}/reschedule", h.Reschedule())
+	mux.Handle("GET /course-sessions/{id}/meeting", h.Meeting())
+	s.handler = server.UserIDMiddleware()(mux)
+}
+
+// TestSessionHandlerSuite 執行測試套件
+func TestSessionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SessionHandlerTestSuite))
+}
+
+func (s *SessionHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	return s.serveAs(method, target, body, 0)
+}
+
+// serveAs 以 userID 為操作者發送請求 , userID 為 0 表示匿名請求
+func (s *SessionHandlerTestSuite) serveAs(method, target, body string, userID uint) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if userID != 0 {
+		req.Header.Set(server.HeaderUserID, strconv.FormatUint(uint64(userID), 10))
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *SessionHandlerTestSuite) TestListByCourse() {
+	tests := []struct {
+		name       string
+		target     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "default range",
+			target: "/courses/1/sessions",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp SessionListResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				require.Len(t, resp.Data, 5)
+				assert.Equal(t, "scheduled", resp.Data[0].Status)
+				assert.True(t, resp.Data[0].StartAt.Equal(time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)))
+			},
+		},
+		{
+			name:   "range",
+			target: "/courses/1/sessions?from=2025-07-22T00:00:00Z&to=2025-08-04T11:00:00Z",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp SessionListResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Len(t, resp.Data, 2)
+			},
+		},
+		{
+			name:   "invalid from",
+			target: "/courses/1/sessions?from=2025-07-22",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "range too large",
+			target: "/courses/1/sessions?from=2025-01-01T00:00:00Z&to=2027-01-01T00:00:00Z",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:   "course not found",
+			target: "/courses/100/sessions",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodGet, tt.target, ""))
+		})
+	}
+}
+
+func (s *SessionHandlerTestSuite) TestCancel() {
+	s.Require().Equal(http.StatusOK, s.serve(http.MethodGet, "/courses/1/sessions", "").Code)
+
+	rec := s.serve(http.MethodPost, "/course-sessions/2/cancel", `{"reason": "颱風停課"}`)
+	s.Equal(http.StatusNoContent, rec.Code)
+
+	rec = s.serve(http.MethodPost, "/course-sessions/2/cancel", `{"reason": "颱風停課"}`)
+	s.Equal(http.StatusConflict, rec.Code)
+
+	rec = s.serve(http.MethodPost, "/course-sessions/100/cancel", `{}`)
+	s.Equal(http.StatusNotFound, rec.Code)
+
+	rec = s.serve(http.MethodPost, "/course-sessions/1/cancel", `{"reason":`)
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	var resp SessionListResponse
+	s.Require().NoError(json.Unmarshal(s.serve(http.MethodGet, "/courses/1/sessions", "").Body.Bytes(), &resp))
+	s.Equal("cancelled", resp.Data[1].Status)
+	s.Equal("颱風停課", resp.Data[1].CancelReason)
+}
+
+// listUpcoming 查詢課程 2 的上課 , 並建立線上會議
+func (s *SessionHandlerTestSuite) listUpcoming() []SessionResponse {
+	rec := s.serve(http.MethodGet, "/courses/2/sessions?from="+s.upcoming.Format(time.RFC3339)+"&to="+s.upcoming.AddDate(0, 0, 14).Format(time.RFC3339), "")
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var resp SessionListResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp.Data, 2)
+	return resp.Data
+}
+
+func (s *SessionHandlerTestSuite) TestReschedule() {
+	sessions := s.listUpcoming()
+	s.Equal("fake", sessions[0].MeetingProvider)
+	s.NotEmpty(sessions[0].JoinURL)
+	s.Require().Equal(http.StatusNoContent, s.serve(http.MethodPost, "/course-sessions/"+fmt.Sprint(sessions[1].ID)+"/cancel", `{}`).Code)
+
+	schedule := func(startAt time.Time) string {
+		return fmt.Sprintf(`{"start_at": %q, "end_at": %q}`, startAt.Format(time.RFC3339), startAt.Add(2*time.Hour).Format(time.RFC3339))
+	}
+	nextDay := sessions[0].StartAt.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name       string
+		id         uint
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "改期並沿用會議",
+			id:   sessions[0].ID,
+			body: schedule(nextDay),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var resp SessionResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, sessions[0].ID, resp.RescheduledFromID)
+				assert.True(t, resp.StartAt.Equal(nextDay))
+				assert.Equal(t, "scheduled", resp.Status)
+				assert.Equal(t, sessions[0].JoinURL, resp.JoinURL)
+			},
+		},
+		{
+			name: "已改期的上課",
+			id:   sessions[0].ID,
+			body: schedule(nextDay.AddDate(0, 0, 1)),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name: "已取消的上課",
+			id:   sessions[1].ID,
+			body: schedule(nextDay),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+			},
+		},
+		{
+			name: "改至過去的時間",
+			id:   sessions[1].ID,
+			body: schedule(s.now),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name: "結束時間早於開始時間",
+			id:   sessions[1].ID,
+			body: fmt.Sprintf(`{"start_at": %q, "end_at": %q}`, nextDay.Format(time.RFC3339), nextDay.Add(-time.Hour).Format(time.RFC3339)),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "上課不存在",
+			id:   100,
+			body: schedule(nextDay),
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			tt.assertFunc(s.T(), s.serve(http.MethodPost, "/course-sessions/"+fmt.Sprint(tt.id)+"/reschedule", tt.body))
+		})
+	}
+}
+
+func (s *SessionHandlerTestSuite) TestMeeting() {
+	sessions := s.listUpcoming()
+	target := "/course-sessions/" + fmt.Sprint(sessions[0].ID) + "/meeting"
+
+	rec := s.serveAs(http.MethodGet, target, "", 100)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	var resp SessionMeetingResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal(sessions[0].JoinURL, resp.JoinURL)
+	s.Len(resp.HostKey, 6)
+
+	s.Equal(http.StatusForbidden, s.serveAs(http.MethodGet, target, "", 101).Code)
+	s.Equal(http.StatusForbidden, s.serveAs(http.MethodGet, target, "", 200).Code)
+	// teacher_id 不再作為身分依據
+	s.Equal(http.StatusUnauthorized, s.serve(http.MethodGet, target+"?teacher_id=1", "").Code)
+	s.Equal(http.StatusNotFound, s.serveAs(http.MethodGet, "/course-sessions/100/meeting", "", 100).Code)
+}
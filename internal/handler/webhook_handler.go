@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	webhookEntity "github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+	"github.com/itmrchow/course-management-system/internal/webhook"
+)
+
+// maxRequestBody 請求內容長度上限
+const maxRequestBody = 1 << 20
+
+// WebhookHandler webhook 訂閱管理與發送紀錄 API
+//
+// Example:
+//
+//	h := handler.NewWebhookHandler(webhookRepo, webhook.NewGuard(false, net.DefaultResolver))
+//	srv.Handle("POST /webhooks", h.Create())
+type WebhookHandler struct {
+	repo  webhookRepository.WebhookRepository
+	guard *webhook.Guard
+	now   func() time.Time
+}
+
+// CreateWebhookRequest 建立訂閱請求
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+}
+
+// WebhookSubscriptionResponse 訂閱回應 , Secret 只在建立時回傳
+type WebhookSubscriptionResponse struct {
+	ID          uint      `json:"id"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"event_types"`
+	Description string    `json:"description"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryResponse 發送紀錄回應 , 不回傳訂閱端的回應內容 , 避免以 webhook 讀取其他服務的回應
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	SubscriptionID uint       `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       uint       `json:"attempts"`
+	ResponseCode   int        `json:"response_code"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewWebhookHandler 建立 webhook API
+// 參數: repo - webhook repository, guard - 檢查訂閱的目的地
+func NewWebhookHandler(repo webhookRepository.WebhookRepository, guard *webhook.Guard) *WebhookHandler {
+	return &WebhookHandler{repo: repo, guard: guard, now: time.Now}
+}
+
+// Create 建立訂閱 , 由系統產生簽章金鑰並只在回應中回傳一次
+// 請求: CreateWebhookRequest , url 須為 http 或 https 且不可為內部位址 , event_types 為事件類型或 * (所有事件)
+func (h *WebhookHandler) Create() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateWebhookRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+		if err := validateWebhookRequest(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err := h.guard.CheckURL(r.Context(), req.URL); err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		subscription := &webhookEntity.WebhookSubscription{
+			URL:         req.URL,
+			EventTypes:  req.EventTypes,
+			Secret:      secret,
+			Description: req.Description,
+		}
+		if _, err := h.repo.CreateSubscription(r.Context(), subscription); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		resp := newWebhookSubscriptionResponse(subscription)
+		resp.Secret = subscription.Secret
+		writeJSON(w, http.StatusCreated, resp)
+	})
+}
+
+// List 查詢訂閱 , 依建立時間由新到舊排序
+// 參數 (query string): page , page_size , order
+func (h *WebhookHandler) List() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		subscriptions, err := h.repo.FindSubscriptions(r.Context(), pageInfo, nil)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]WebhookSubscriptionResponse, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			data = append(data, newWebhookSubscriptionResponse(subscription))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[WebhookSubscriptionResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Get 查詢單一訂閱
+// 參數 (path): id - 訂閱ID
+func (h *WebhookHandler) Get() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		subscription, err := h.repo.GetSubscription(r.Context(), id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "webhook not found"})
+			return
+		}
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newWebhookSubscriptionResponse(subscription))
+	})
+}
+
+// Delete 刪除訂閱 , 待發送的紀錄不會再發送
+// 參數 (path): id - 訂閱ID
+func (h *WebhookHandler) Delete() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		rowsAffected, err := h.repo.DeleteSubscription(r.Context(), id)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		if rowsAffected == 0 {
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "webhook not found"})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// ListDeliveries 查詢訂閱的發送紀錄 , 依建立時間由新到舊排序
+// 參數 (path): id - 訂閱ID
+// 參數 (query string):
+//   - status : pending , succeeded 或 failed
+//   - page , page_size , order : 分頁與排序方向 (asc / desc)
+func (h *WebhookHandler) ListDeliveries() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		pageInfo, err := parsePageInfo(r, "created_at")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		conditions := []func(db *gorm.DB) *gorm.DB{webhookEntity.WebhookDeliveryOfSubscription(id)}
+		if v := r.URL.Query().Get("status"); v != "" {
+			status, ok := webhookEntity.ParseWebhookDeliveryStatus(v)
+			if !ok {
+				writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid status %q", v)})
+				return
+			}
+			conditions = append(conditions, webhookEntity.InWebhookDeliveryStatus([]webhookEntity.WebhookDeliveryStatus{status}))
+		}
+
+		deliveries, err := h.repo.FindDeliveries(r.Context(), pageInfo, conditions)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		data := make([]WebhookDeliveryResponse, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			data = append(data, newWebhookDeliveryResponse(delivery))
+		}
+		writeJSON(w, http.StatusOK, ListResponse[WebhookDeliveryResponse]{Data: data, Page: pageInfo.Page, PageSize: pageInfo.PageSize})
+	})
+}
+
+// Redeliver 手動重新發送已完成 (成功或失敗) 的紀錄 , 由 deliverer 於下次執行時發送
+// 參數 (path): id - 發送紀錄ID
+func (h *WebhookHandler) Redeliver() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := parsePathID(r, "id")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		delivery, err := h.repo.GetDelivery(r.Context(), id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "webhook delivery not found"})
+			return
+		}
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		rowsAffected, err := h.repo.Redeliver(r.Context(), id, h.now())
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		if rowsAffected == 0 {
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "webhook delivery is pending"})
+			return
+		}
+
+		delivery, err = h.repo.GetDelivery(r.Context(), id)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, newWebhookDeliveryResponse(delivery))
+	})
+}
+
+// validateWebhookRequest 檢查 URL 與事件類型 , 並移除重複的事件類型
+func validateWebhookRequest(req *CreateWebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q , must be an absolute http or https url", req.URL)
+	}
+	if len(req.URL) > 2048 {
+		return fmt.Errorf("url must not exceed 2048 characters")
+	}
+	if len(req.Description) > 255 {
+		return fmt.Errorf("description must not exceed 255 characters")
+	}
+
+	if len(req.EventTypes) == 0 {
+		return fmt.Errorf("event_types is required")
+	}
+	types := event.Types()
+	eventTypes := make([]string, 0, len(req.EventTypes))
+	for _, t := range req.EventTypes {
+		if t != webhookEntity.AllEvents && !slices.Contains(types, t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+	req.EventTypes = eventTypes
+	return nil
+}
+
+func newWebhookSubscriptionResponse(subscription *webhookEntity.WebhookSubscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		ID:          subscription.ID,
+		URL:         subscription.URL,
+		EventTypes:  subscription.EventTypes,
+		Description: subscription.Description,
+		CreatedAt:   subscription.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery *webhookEntity.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status.String(),
+		Attempts:       delivery.Attempts,
+		ResponseCode:   delivery.ResponseCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == webhookEntity.WebhookDeliveryStatusPending {
+		next := delivery.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
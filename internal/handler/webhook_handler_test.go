@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	webhookEntity "github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+	"github.com/itmrchow/course-management-system/internal/webhook"
+)
+
+// staticResolver 以固定的對應解析主機名稱
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+type WebhookHandlerTestSuite struct {
+	suite.Suite
+	repo webhookRepository.WebhookRepository
+	mux  *http.ServeMux
+	now  time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 註冊路由並新增一個訂閱與兩筆發送紀錄
+func (s *WebhookHandlerTestSuite) SetupTest() {
+	s.repo = webhookRepository.NewWebhookMemoryRepository()
+	s.now = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	h := NewWebhookHandler(s.repo, webhook.NewGuard(false, staticResolver{
+		"partner.example.com":  {netip.MustParseAddr("93.184.216.34")},
+		"internal.example.com": {netip.MustParseAddr("10.0.0.5")},
+	}))
+	h.now = func() time.Time { return s.now }
+
+	s.mux = http.NewServeMux()
+	s.mux.Handle("POST /webhooks", h.Create())
+	s.mux.Handle("GET /webhooks", h.List())
+	s.mux.Handle("GET /webhooks/{id}", h.Get())
+	s.mux.Handle("DELETE /webhooks/{id}", h.Delete())
+	s.mux.Handle("GET /webhooks/{id}/deliveries", h.ListDeliveries())
+	s.mux.Handle("POST /webhook-deliveries/{id}/redeliver", h.Redeliver())
+
+	_, err := s.repo.CreateSubscription(context.Background(), &webhookEntity.WebhookSubscription{
+		URL: "https://partner.example.com/hook", EventTypes: webhookEntity.EventTypes{"course.published"}, Secret: "secret",
+	})
+	s.Require().NoError(err)
+
+	_, err = s.repo.AddDeliveries(context.Background(),
+		&webhookEntity.WebhookDelivery{SubscriptionID: 1, EventID: "event-1", EventType: "course.published", Payload: `{}`, NextAttemptAt: s.now},
+		&webhookEntity.WebhookDelivery{SubscriptionID: 1, EventID: "event-2", EventType: "course.published", Payload: `{}`, NextAttemptAt: s.now},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(s.repo.RecordAttempt(context.Background(), 2, &webhookEntity.WebhookAttempt{
+		Status: webhookEntity.WebhookDeliveryStatusFailed, ResponseCode: 500, ResponseBody: "internal secret", Error: "webhook responded with status 500", At: s.now,
+	}))
+}
+
+// TestWebhookHandlerSuite 執行測試套件
+func TestWebhookHandlerSuite(t *testing.T) {
+	suite.Run(t, new(WebhookHandlerTestSuite))
+}
+
+func (s *WebhookHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (s *WebhookHandlerTestSuite) TestCreate() {
+	tests := []struct {
+		name       string
+		body       string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: `{"url": "https://partner.example.com/courses", "event_types": ["course.published", "course.full", "course.full"], "description": "partner"}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				var resp WebhookSubscriptionResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.EqualValues(t, 2, resp.ID)
+				assert.Equal(t, []string{"course.published", "course.full"}, resp.EventTypes)
+				assert.True(t, strings.HasPrefix(resp.Secret, "whsec_"))
+
+				subscription, err := s.repo.GetSubscription(context.Background(), resp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, resp.Secret, subscription.Secret)
+			},
+		},
+		{
+			name: "all events",
+			body: `{"url": "http://partner.example.com:9000/hook", "event_types": ["*"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			},
+		},
+		{
+			name: "loopback",
+			body: `{"url": "http://127.0.0.1:9000/hook", "event_types": ["*"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Contains(t, rec.Body.String(), webhook.ErrForbiddenDestination.Error())
+			},
+		},
+		{
+			name: "cloud metadata",
+			body: `{"url": "http://169.254.169.254/latest/meta-data/", "event_types": ["*"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Contains(t, rec.Body.String(), webhook.ErrForbiddenDestination.Error())
+			},
+		},
+		{
+			name: "host resolves to private address",
+			body: `{"url": "https://internal.example.com/hook", "event_types": ["*"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Contains(t, rec.Body.String(), webhook.ErrForbiddenDestination.Error())
+			},
+		},
+		{
+			name: "invalid json",
+			body: `{"url":`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "relative url",
+			body: `{"url": "/hook", "event_types": ["course.published"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "unsupported scheme",
+			body: `{"url": "ftp://partner.example.com/hook", "event_types": ["course.published"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "no event types",
+			body: `{"url": "https://partner.example.com/hook", "event_types": []}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.JSONEq(t, `{"error": "event_types is required"}`, rec.Body.String())
+			},
+		},
+		{
+			name: "unknown event type",
+			body: `{"url": "https://partner.example.com/hook", "event_types": ["course.deleted"]}`,
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.JSONEq(t, `{"error": "unknown event type \"course.deleted\""}`, rec.Body.String())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			test.assertFunc(s.T(), s.serve(http.MethodPost, "/webhooks", test.body))
+		})
+	}
+}
+
+func (s *WebhookHandlerTestSuite) TestListAndGetHideSecret() {
+	rec := s.serve(http.MethodGet, "/webhooks", "")
+	s.Equal(http.StatusOK, rec.Code)
+	s.NotContains(rec.Body.String(), "secret")
+	s.Contains(rec.Body.String(), `"url":"https://partner.example.com/hook"`)
+
+	rec = s.serve(http.MethodGet, "/webhooks/1", "")
+	s.Equal(http.StatusOK, rec.Code)
+	s.NotContains(rec.Body.String(), "secret")
+
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/webhooks/100", "").Code)
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/webhooks/abc", "").Code)
+}
+
+func (s *WebhookHandlerTestSuite) TestDelete() {
+	s.Equal(http.StatusNoContent, s.serve(http.MethodDelete, "/webhooks/1", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodDelete, "/webhooks/1", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodGet, "/webhooks/1", "").Code)
+}
+
+func (s *WebhookHandlerTestSuite) TestListDeliveries() {
+	rec := s.serve(http.MethodGet, "/webhooks/1/deliveries?order=asc", "")
+	s.Equal(http.StatusOK, rec.Code)
+	// 不回傳訂閱端的回應內容
+	s.NotContains(rec.Body.String(), "internal secret")
+
+	var resp ListResponse[WebhookDeliveryResponse]
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp.Data, 2)
+	s.Equal("pending", resp.Data[0].Status)
+	s.NotNil(resp.Data[0].NextAttemptAt)
+	s.Equal("failed", resp.Data[1].Status)
+	s.Equal(500, resp.Data[1].ResponseCode)
+	s.Nil(resp.Data[1].NextAttemptAt)
+
+	rec = s.serve(http.MethodGet, "/webhooks/1/deliveries?status=failed", "")
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp.Data, 1)
+	s.Equal("event-2", resp.Data[0].EventID)
+
+	s.Equal(http.StatusBadRequest, s.serve(http.MethodGet, "/webhooks/1/deliveries?status=unknown", "").Code)
+}
+
+func (s *WebhookHandlerTestSuite) TestRedeliver() {
+	rec := s.serve(http.MethodPost, "/webhook-deliveries/2/redeliver", "")
+	s.Equal(http.StatusAccepted, rec.Code)
+
+	var resp WebhookDeliveryResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("pending", resp.Status)
+	s.Zero(resp.Attempts)
+	s.Require().NotNil(resp.NextAttemptAt)
+	s.True(resp.NextAttemptAt.Equal(s.now))
+
+	s.Equal(http.StatusConflict, s.serve(http.MethodPost, "/webhook-deliveries/2/redeliver", "").Code)
+	s.Equal(http.StatusNotFound, s.serve(http.MethodPost, "/webhook-deliveries/100/redeliver", "").Code)
+}
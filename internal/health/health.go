@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/config"
+)
+
+// CheckFunc readiness 檢查 , 回傳 nil 表示正常
+type CheckFunc func(ctx context.Context) error
+
+// 檢查結果狀態
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Response /healthz 與 /readyz 的回應內容
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"` // 各檢查項目結果 , 失敗時為錯誤訊息
+}
+
+// Health 管理 liveness 與 readiness 檢查
+//
+// Example:
+//
+//	h := health.New(2 * time.Second)
+//	h.Register("db", health.DBCheck(db))
+//	srv.Handle("GET /healthz", h.LivenessHandler())
+//	srv.Handle("GET /readyz", h.ReadinessHandler())
+type Health struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// New 建立 Health
+// 參數: timeout - 每次 readiness 檢查的逾時時間
+func New(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+		checks:  map[string]CheckFunc{},
+	}
+}
+
+// Register 註冊 readiness 檢查項目 , 同名時覆蓋
+func (h *Health) Register(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check
+}
+
+// Check 同時執行所有檢查項目 , 每個項目共用 timeout
+// 回傳: 各項目結果 , 與是否全部通過
+func (h *Health) Check(ctx context.Context) (map[string]string, bool) {
+	h.mu.RLock()
+	checks := make(map[string]CheckFunc, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]string, len(checks))
+		ok      = true
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := StatusOK
+			if err := runCheck(ctx, check); err != nil {
+				result = err.Error()
+				zerolog.Ctx(ctx).Warn().Err(err).Str("check", name).Msg("readiness check failed")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+			if result != StatusOK {
+				ok = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, ok
+}
+
+// LivenessHandler /healthz , 程序可回應即為存活 , 不檢查外部相依
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Response{Status: StatusOK})
+	})
+}
+
+// ReadinessHandler /readyz , 所有檢查項目通過回傳 200 , 否則回傳 503
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, ok := h.Check(r.Context())
+		if !ok {
+			writeJSON(w, http.StatusServiceUnavailable, Response{Status: StatusFail, Checks: results})
+			return
+		}
+		writeJSON(w, http.StatusOK, Response{Status: StatusOK, Checks: results})
+	})
+}
+
+// DBCheck 檢查資料庫連線
+func DBCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get sql db: %w", err)
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to ping db: %w", err)
+		}
+		return nil
+	}
+}
+
+// MigrationCheck 檢查資料庫 schema 是否缺少 entity 對應的 table 或 column
+func MigrationCheck(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		pending, err := config.PendingMigrations(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+		}
+		return nil
+	}
+}
+
+// runCheck 執行檢查 , 檢查未於 ctx 結束前回傳時視為逾時
+func runCheck(ctx context.Context, check CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveness 測試 , 不受檢查項目影響
+// Test for Health.LivenessHandler
+func TestLiveness(t *testing.T) {
+	h := New(time.Second)
+	h.Register("db", func(ctx context.Context) error { return errors.New("down") })
+
+	rec := httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+// readiness 測試
+// Test for Health.ReadinessHandler
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     map[string]CheckFunc
+		assertFunc func(t *testing.T, code int, resp Response)
+	}{
+		{
+			name: "all ok",
+			checks: map[string]CheckFunc{
+				"db":        func(ctx context.Context) error { return nil },
+				"migration": func(ctx context.Context) error { return nil },
+			},
+			assertFunc: func(t *testing.T, code int, resp Response) {
+				assert.Equal(t, http.StatusOK, code)
+				assert.Equal(t, StatusOK, resp.Status)
+				assert.Equal(t, map[string]string{"db": StatusOK, "migration": StatusOK}, resp.Checks)
+			},
+		},
+		{
+			name: "one failed",
+			checks: map[string]CheckFunc{
+				"db":        func(ctx context.Context) error { return nil },
+				"migration": func(ctx context.Context) error { return errors.New("pending migrations: course") },
+			},
+			assertFunc: func(t *testing.T, code int, resp Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, code)
+				assert.Equal(t, StatusFail, resp.Status)
+				assert.Equal(t, StatusOK, resp.Checks["db"])
+				assert.Equal(t, "pending migrations: course", resp.Checks["migration"])
+			},
+		},
+		{
+			name: "timeout",
+			checks: map[string]CheckFunc{
+				// 不理會 ctx 的檢查也會在逾時後回傳
+				"db": func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				},
+			},
+			assertFunc: func(t *testing.T, code int, resp Response) {
+				assert.Equal(t, http.StatusServiceUnavailable, code)
+				assert.Contains(t, resp.Checks["db"], "timed out")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := New(50 * time.Millisecond)
+			for name, check := range test.checks {
+				h.Register(name, check)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			var resp Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			test.assertFunc(t, rec.Code, resp)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package invoice
+
+import (
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	"github.com/itmrchow/course-management-system/internal/money"
+)
+
+// Issuer 開立發票的單位 , 顯示於發票抬頭
+type Issuer struct {
+	Name    string // 名稱
+	TaxID   string // 統一編號
+	Address string // 地址
+	Email   string // 聯絡 email
+}
+
+// Document 套用版面前的發票內容
+// 金額皆為 money.Money , 折扣明細的金額為負數
+type Document struct {
+	Issuer    Issuer
+	Number    string    // 發票號碼
+	IssuedAt  time.Time // 開立時間
+	PaidAt    time.Time // 付款時間
+	OrderID   uint      // 訂單ID
+	PaymentID string    // 金流商的付款ID
+	StudentID uint      // 學生 user ID
+	Lines     []Line
+	Subtotal  money.Money // 課程售價合計
+	Discount  money.Money // 折扣合計 , 為負數
+	Total     money.Money // 實付金額
+}
+
+// Line 發票明細
+type Line struct {
+	Kind        entity.InvoiceLineKind
+	Description string      // 課程名稱或促銷活動名稱 , 折扣明細為空字串時表示訂單的折扣合計
+	Detail      string      // 課程的價格級距 (regular / early_bird) 或促銷活動的優惠碼 , 空字串表示自動套用的促銷
+	Quantity    uint        // 數量
+	UnitAmount  money.Money // 單價
+	Amount      money.Money // 金額
+}
+
+// NewDocument 由發票與明細建立發票內容
+// 參數: issuer - 開立單位, invoice - 發票, lines - 發票明細
+// 回傳: 發票內容
+func NewDocument(issuer Issuer, invoice *entity.Invoice, lines []*entity.InvoiceLine) *Document {
+	amount := func(v uint) money.Money { return money.Money{Amount: int64(v), Currency: invoice.Currency} }
+
+	doc := &Document{
+		Issuer:    issuer,
+		Number:    invoice.Number,
+		IssuedAt:  invoice.IssuedAt,
+		PaidAt:    invoice.PaidAt,
+		OrderID:   invoice.OrderID,
+		PaymentID: invoice.PaymentID,
+		StudentID: invoice.StudentID,
+		Lines:     make([]Line, 0, len(lines)),
+		Subtotal:  amount(invoice.Subtotal),
+		Discount:  money.Money{Amount: -int64(invoice.Discount), Currency: invoice.Currency},
+		Total:     amount(invoice.Total),
+	}
+	for _, l := range lines {
+		line := Line{
+			Kind:        l.Kind,
+			Description: l.Description,
+			Detail:      l.Detail,
+			Quantity:    l.Quantity,
+			UnitAmount:  amount(l.UnitAmount),
+			Amount:      amount(l.Amount),
+		}
+		if l.Kind == entity.InvoiceLineKindDiscount {
+			line.UnitAmount.Amount = -line.UnitAmount.Amount
+			line.Amount.Amount = -line.Amount.Amount
+		}
+		doc.Lines = append(doc.Lines, line)
+	}
+	return doc
+}
@@ -0,0 +1,95 @@
+package invoice
+
+import (
+	"github.com/itmrchow/course-management-system/internal/pdf"
+)
+
+// 版面邊界與表格列高 , 單位為 point (1/72 inch)
+const (
+	pageMargin = 50.0
+	rowHeight  = 18.0
+)
+
+// renderPDF 以單頁 A4 輸出發票 , 版面與 HTML 相同 : 抬頭、開立單位與買受人、明細表格、合計
+func renderPDF(v view) []byte {
+	p := pdf.NewPage(pdf.A4Width, pdf.A4Height)
+	right := pdf.A4Width - pageMargin
+	y := pdf.A4Height - pageMargin - 20
+
+	// 抬頭
+	p.Text(pageMargin, y, 22, v.L.Title)
+	p.TextRight(right, y, 10, v.L.Number+": "+v.Number)
+	y -= 16
+	p.TextRight(right, y, 10, v.L.IssuedAt+": "+v.IssuedAt)
+	y -= 14
+	p.TextRight(right, y, 10, v.L.PaidAt+": "+v.PaidAt)
+
+	// 開立單位與買受人
+	y -= 36
+	top := y
+	p.Text(pageMargin, y, 9, v.L.Issuer)
+	for _, s := range []string{v.Issuer.Name, prefixed(v.L.TaxID, v.Issuer.TaxID), v.Issuer.Address, v.Issuer.Email} {
+		if s == "" {
+			continue
+		}
+		y -= 14
+		p.Text(pageMargin, y, 10, s)
+	}
+	billedY := top
+	p.Text(pdf.A4Width/2, billedY, 9, v.L.BilledTo)
+	for _, s := range []string{prefixed(v.L.Student, v.Student), prefixed(v.L.Order, v.Order), prefixed(v.L.Payment, v.Payment)} {
+		if s == "" {
+			continue
+		}
+		billedY -= 14
+		p.Text(pdf.A4Width/2, billedY, 10, s)
+	}
+	y = min(y, billedY)
+
+	// 明細表格
+	colDetail := pageMargin + 200
+	colQuantity := right - 190
+	colUnit := right - 95
+	y -= 40
+	p.Text(pageMargin, y, 10, v.L.Item)
+	p.Text(colDetail, y, 10, v.L.Detail)
+	p.TextRight(colQuantity, y, 10, v.L.Quantity)
+	p.TextRight(colUnit, y, 10, v.L.UnitPrice)
+	p.TextRight(right, y, 10, v.L.Amount)
+	p.Line(pageMargin, y-6, right, y-6, 0.8)
+	for _, r := range v.Rows {
+		y -= rowHeight + 4
+		p.Text(pageMargin, y, 10, r.Description)
+		p.Text(colDetail, y, 10, r.Detail)
+		p.TextRight(colQuantity, y, 10, r.Quantity)
+		p.TextRight(colUnit, y, 10, r.UnitAmount)
+		p.TextRight(right, y, 10, r.Amount)
+	}
+	p.Line(pageMargin, y-8, right, y-8, 0.5)
+
+	// 合計
+	y -= 8
+	if v.HasDiscount {
+		y -= rowHeight
+		p.TextRight(colUnit, y, 10, v.L.Subtotal)
+		p.TextRight(right, y, 10, v.Subtotal)
+		y -= rowHeight
+		p.TextRight(colUnit, y, 10, v.L.Discount)
+		p.TextRight(right, y, 10, v.Discount)
+	}
+	y -= rowHeight + 4
+	p.Line(colQuantity, y+14, right, y+14, 1.2)
+	p.TextRight(colUnit, y, 12, v.L.Total)
+	p.TextRight(right, y, 12, v.Total)
+
+	p.Text(pageMargin, pageMargin, 8, v.L.Footer)
+	return p.Bytes(v.L.Title + " " + v.Number)
+}
+
+// prefixed 以 "標籤: 值" 顯示 , 值為空字串時回傳空字串
+func prefixed(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + value
+}
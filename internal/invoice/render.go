@@ -0,0 +1,243 @@
+package invoice
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+var (
+	// ErrUnsupportedFormat 不支援的輸出格式
+	ErrUnsupportedFormat = errors.New("unsupported invoice format")
+	// ErrUnsupportedLocale 沒有對應版面的語系
+	ErrUnsupportedLocale = errors.New("unsupported invoice locale")
+)
+
+// Format 發票的輸出格式
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatPDF  Format = "pdf"
+)
+
+// ContentType 輸出格式的 MIME type
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+// layout 各語系的版面文字與日期格式
+type layout struct {
+	Lang       string // HTML lang 屬性
+	DateLayout string
+	Title      string
+	Number     string
+	IssuedAt   string
+	PaidAt     string
+	Issuer     string
+	TaxID      string
+	BilledTo   string
+	Student    string
+	Order      string
+	Payment    string
+	Item       string
+	Detail     string
+	Quantity   string
+	UnitPrice  string
+	Amount     string
+	Subtotal   string
+	Discount   string
+	Total      string
+	Automatic  string            // 自動套用的促銷沒有優惠碼時顯示的說明
+	Tiers      map[string]string // 課程價格級距的名稱
+	Footer     string
+}
+
+// layouts 支援的語系版面
+var layouts = map[string]layout{
+	notificationEntity.LocaleZhTW: {
+		Lang:       "zh-Hant-TW",
+		DateLayout: "2006/01/02",
+		Title:      "發票",
+		Number:     "發票號碼",
+		IssuedAt:   "開立日期",
+		PaidAt:     "付款日期",
+		Issuer:     "開立單位",
+		TaxID:      "統一編號",
+		BilledTo:   "買受人",
+		Student:    "學生編號",
+		Order:      "訂單編號",
+		Payment:    "付款編號",
+		Item:       "品項",
+		Detail:     "說明",
+		Quantity:   "數量",
+		UnitPrice:  "單價",
+		Amount:     "金額",
+		Subtotal:   "小計",
+		Discount:   "折扣",
+		Total:      "總計",
+		Automatic:  "自動折扣",
+		Tiers:      map[string]string{"regular": "一般價格", "early_bird": "早鳥價"},
+		Footer:     "本發票由系統自動產生，如有疑問請聯絡開立單位。",
+	},
+	notificationEntity.LocaleEn: {
+		Lang:       "en",
+		DateLayout: "Jan 2, 2006",
+		Title:      "INVOICE",
+		Number:     "Invoice No.",
+		IssuedAt:   "Issue date",
+		PaidAt:     "Payment date",
+		Issuer:     "Issued by",
+		TaxID:      "Tax ID",
+		BilledTo:   "Billed to",
+		Student:    "Student ID",
+		Order:      "Order ID",
+		Payment:    "Payment ID",
+		Item:       "Item",
+		Detail:     "Detail",
+		Quantity:   "Qty",
+		UnitPrice:  "Unit price",
+		Amount:     "Amount",
+		Subtotal:   "Subtotal",
+		Discount:   "Discount",
+		Total:      "Total",
+		Automatic:  "Automatic discount",
+		Tiers:      map[string]string{"regular": "Regular price", "early_bird": "Early bird"},
+		Footer:     "This invoice was generated electronically. Please contact the issuer for any questions.",
+	},
+}
+
+// view 套用版面後的發票內容 , 所有欄位皆已格式化為字串 , HTML 與 PDF 共用
+type view struct {
+	L           layout
+	Issuer      Issuer
+	Number      string
+	IssuedAt    string
+	PaidAt      string
+	Student     string
+	Order       string
+	Payment     string
+	Rows        []row
+	Subtotal    string
+	Discount    string
+	Total       string
+	HasDiscount bool // 是否有折扣 , 沒有折扣時不顯示小計與折扣
+}
+
+type row struct {
+	Description string
+	Detail      string
+	Quantity    string
+	UnitAmount  string
+	Amount      string
+}
+
+// Renderer 依語系版面將發票輸出為 HTML 或 PDF
+// HTML 使用 html/template , PDF 為單頁 A4 , 中文使用 PDF 閱讀器內建的 MSung-Light 字型 , 不嵌入字型檔
+//
+// Example:
+//
+//	r, err := invoice.NewRenderer(loc)
+//	b, err := r.Render(doc, invoice.FormatPDF, "zh-TW")
+type Renderer struct {
+	html *template.Template
+	loc  *time.Location
+}
+
+// NewRenderer 載入內建的 HTML 樣板
+// 參數: loc - 日期顯示的時區
+// 回傳: renderer, 錯誤訊息
+func NewRenderer(loc *time.Location) (*Renderer, error) {
+	tmpl, err := template.New("invoice.html.tmpl").
+		Option("missingkey=error").
+		ParseFS(templateFS, "templates/invoice.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invoice template: %w", err)
+	}
+	return &Renderer{html: tmpl, loc: loc}, nil
+}
+
+// Render 以語系版面輸出發票
+// 參數: doc - 發票內容, format - 輸出格式, locale - 語系
+// 回傳: 檔案內容, 錯誤訊息 , 不支援的格式或語系回傳 ErrUnsupportedFormat / ErrUnsupportedLocale
+func (r *Renderer) Render(doc *Document, format Format, locale string) ([]byte, error) {
+	l, ok := layouts[locale]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+	v := r.view(doc, l)
+
+	switch format {
+	case FormatHTML:
+		var buf bytes.Buffer
+		if err := r.html.Execute(&buf, v); err != nil {
+			return nil, fmt.Errorf("failed to render invoice %s: %w", doc.Number, err)
+		}
+		return buf.Bytes(), nil
+	case FormatPDF:
+		return renderPDF(v), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// view 將發票內容依版面格式化
+func (r *Renderer) view(doc *Document, l layout) view {
+	v := view{
+		L:           l,
+		Issuer:      doc.Issuer,
+		Number:      doc.Number,
+		IssuedAt:    doc.IssuedAt.In(r.loc).Format(l.DateLayout),
+		PaidAt:      doc.PaidAt.In(r.loc).Format(l.DateLayout),
+		Student:     strconv.FormatUint(uint64(doc.StudentID), 10),
+		Order:       strconv.FormatUint(uint64(doc.OrderID), 10),
+		Payment:     doc.PaymentID,
+		Rows:        make([]row, 0, len(doc.Lines)),
+		Subtotal:    doc.Subtotal.String(),
+		Discount:    doc.Discount.String(),
+		Total:       doc.Total.String(),
+		HasDiscount: !doc.Discount.IsZero(),
+	}
+
+	for _, line := range doc.Lines {
+		description, detail := line.Description, line.Detail
+		switch line.Kind {
+		case entity.InvoiceLineKindCourse:
+			if name, ok := l.Tiers[detail]; ok {
+				detail = name
+			}
+		case entity.InvoiceLineKindDiscount:
+			// 沒有名稱的折扣明細為訂單的折扣合計 , 沒有優惠碼的為自動套用的促銷
+			if description == "" {
+				description = l.Discount
+			} else if detail == "" {
+				detail = l.Automatic
+			}
+		}
+		v.Rows = append(v.Rows, row{
+			Description: description,
+			Detail:      detail,
+			Quantity:    strconv.FormatUint(uint64(line.Quantity), 10),
+			UnitAmount:  line.UnitAmount.String(),
+			Amount:      line.Amount.String(),
+		})
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package invoice
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// testDocument 課程 3000 元 , 早鳥價 , 使用優惠券折扣 300 元的發票
+func testDocument() *Document {
+	at := time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC) // 台北時間 2026/01/01
+	return NewDocument(
+		Issuer{Name: "課程管理股份有限公司", TaxID: "12345678", Address: "台北市信義區信義路五段 7 號", Email: "billing@example.com"},
+		&entity.Invoice{Number: "INV-2026-000001", OrderID: 1, StudentID: 10, PaymentID: "fake_1", Currency: "TWD", Subtotal: 300000, Discount: 30000, Total: 270000, PaidAt: at, IssuedAt: at},
+		[]*entity.InvoiceLine{
+			{Position: 1, Kind: entity.InvoiceLineKindCourse, Description: "資料庫設計", Detail: "early_bird", Quantity: 1, UnitAmount: 300000, Amount: 300000},
+			{Position: 2, Kind: entity.InvoiceLineKindDiscount, Description: "暑期九折", Detail: "SUMMER10", Quantity: 1, UnitAmount: 30000, Amount: 30000},
+		},
+	)
+}
+
+func TestNewDocument(t *testing.T) {
+	doc := testDocument()
+
+	assert.Equal(t, "TWD 3,000.00", doc.Subtotal.String())
+	assert.Equal(t, "TWD -300.00", doc.Discount.String())
+	assert.Equal(t, "TWD 2,700.00", doc.Total.String())
+	require.Len(t, doc.Lines, 2)
+	assert.EqualValues(t, -30000, doc.Lines[1].Amount.Amount)
+}
+
+func TestRenderer_HTML(t *testing.T) {
+	r, err := NewRenderer(time.FixedZone("Asia/Taipei", 8*60*60))
+	require.NoError(t, err)
+
+	tests := []struct {
+		locale   string
+		contains []string
+	}{
+		{
+			locale:   notificationEntity.LocaleZhTW,
+			contains: []string{`lang="zh-Hant-TW"`, "發票號碼", "INV-2026-000001", "2026/01/01", "早鳥價", "SUMMER10", "TWD -300.00", "TWD 2,700.00", "統一編號: 12345678"},
+		},
+		{
+			locale:   notificationEntity.LocaleEn,
+			contains: []string{`lang="en"`, "Invoice No.", "Jan 1, 2026", "Early bird", "資料庫設計", "TWD 2,700.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			b, err := r.Render(testDocument(), FormatHTML, tt.locale)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, string(b), s)
+			}
+		})
+	}
+}
+
+func TestRenderer_PDF(t *testing.T) {
+	r, err := NewRenderer(time.UTC)
+	require.NoError(t, err)
+
+	for _, locale := range notificationEntity.Locales() {
+		t.Run(locale, func(t *testing.T) {
+			b, err := r.Render(testDocument(), FormatPDF, locale)
+			require.NoError(t, err)
+
+			assert.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4")))
+			assert.True(t, bytes.HasSuffix(b, []byte("%%EOF\n")))
+			assert.Contains(t, string(b), "INV-2026-000001) Tj")
+			// 課程名稱以中文字型的 UCS-2 編碼輸出
+			assert.Contains(t, string(b), "<8CC765995EAB8A2D8A08>")
+		})
+	}
+}
+
+func TestRenderer_Unsupported(t *testing.T) {
+	r, err := NewRenderer(time.UTC)
+	require.NoError(t, err)
+
+	_, err = r.Render(testDocument(), FormatHTML, "ja")
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+
+	_, err = r.Render(testDocument(), Format("docx"), notificationEntity.LocaleEn)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
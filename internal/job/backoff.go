@@ -0,0 +1,17 @@
+package job
+
+import "time"
+
+// Backoff 第 attempts 次失敗後的重試等待時間 , 由 initial 開始每次加倍 , 不超過 maxBackoff
+// 供以資料表排程重試的背景工作 (outbox relay、webhook、通知、退款) 計算下次執行時間
+//
+// Example:
+//
+//	next := now.Add(job.Backoff(cfg.InitialBackoff, cfg.MaxBackoff, delivery.Attempts))
+func Backoff(initial, maxBackoff time.Duration, attempts uint) time.Duration {
+	backoff := initial
+	for i := uint(0); i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
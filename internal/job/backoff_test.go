@@ -0,0 +1,27 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 重試等待時間測試
+// Test for Backoff
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts uint
+		want     time.Duration
+	}{
+		{attempts: 0, want: 5 * time.Second},
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 4, want: time.Minute},
+		{attempts: 100, want: time.Minute},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, Backoff(5*time.Second, time.Minute, test.attempts), "attempts %d", test.attempts)
+	}
+}
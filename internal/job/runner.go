@@ -0,0 +1,214 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+// Func 背景工作
+type Func func(ctx context.Context) error
+
+// periodicJob 週期性工作
+type periodicJob struct {
+	name     string
+	interval time.Duration
+	fn       Func
+}
+
+// ErrNotRunning Runner 尚未啟動或已停止
+var ErrNotRunning = errors.New("job runner is not running")
+
+// stuckFactor 週期性工作超過幾倍間隔沒有完成即視為卡住
+const stuckFactor = 3
+
+// Runner 背景工作執行器
+// 支援週期性工作 (Every) 與由請求觸發的一次性工作 (Go)
+// 每次執行都會建立 span , 並於 Stop 時取消所有工作
+//
+// Example:
+//
+//	runner := job.NewRunner(logger)
+//	runner.Every("purge", time.Hour, purgeFunc)
+//	runner.Start(ctx)
+//	defer runner.Stop(ctx)
+type Runner struct {
+	logger   *zerolog.Logger
+	periodic []periodicJob
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+
+	// lastDone 週期性工作最後一次完成的時間 , 供 Check 判斷工作是否卡住
+	lastDone map[string]time.Time
+}
+
+// NewRunner 建立背景工作執行器
+// 參數: logger - 預設 logger , 工作的 ctx 沒有 logger 時使用
+func NewRunner(logger *zerolog.Logger) *Runner {
+	ctx, cancel := context.WithCancel(logctx.WithLogger(context.Background(), logger))
+
+	return &Runner{
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		lastDone: map[string]time.Time{},
+	}
+}
+
+// Every 註冊週期性工作 , 須於 Start 之前呼叫
+// 每次執行都是新的 trace
+func (r *Runner) Every(name string, interval time.Duration, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.periodic = append(r.periodic, periodicJob{name: name, interval: interval, fn: fn})
+}
+
+// Start 啟動所有週期性工作
+func (r *Runner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return fmt.Errorf("job runner already started")
+	}
+	r.started = true
+
+	now := time.Now()
+	for _, job := range r.periodic {
+		r.lastDone[job.name] = now
+		r.wg.Add(1)
+		go r.loop(job)
+	}
+
+	r.logger.Info().Ctx(ctx).Int("jobs", len(r.periodic)).Msg("job runner started")
+	return nil
+}
+
+// Go 於背景執行一次性工作
+// 承接呼叫端 ctx 的 logger 與 trace context , 工作的 span 為呼叫端 span 的子 span
+// 不受呼叫端 ctx 取消影響 (例如 HTTP 請求已結束) , 但會於 Stop 時取消
+func (r *Runner) Go(ctx context.Context, name string, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		zerolog.Ctx(ctx).Warn().Str("job", name).Msg("job runner stopped, job dropped")
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(r.ctx, cancel)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer stop()
+		defer cancel()
+
+		r.run(jobCtx, name, fn)
+	}()
+}
+
+// Stop 取消所有工作 , 等待執行中的工作結束或 ctx 逾時
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		r.logger.Info().Ctx(ctx).Msg("job runner stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for jobs to stop: %w", ctx.Err())
+	}
+}
+
+// Check 檢查 Runner 是否正常運作 , 供 readiness 使用
+// 尚未啟動、已停止 , 或有週期性工作超過 3 倍間隔沒有完成時回傳錯誤
+func (r *Runner) Check(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started || r.stopped {
+		return ErrNotRunning
+	}
+
+	for _, job := range r.periodic {
+		if since := time.Since(r.lastDone[job.name]); since > stuckFactor*job.interval {
+			return fmt.Errorf("job %s has not completed for %s", job.name, since.Round(time.Second))
+		}
+	}
+
+	return nil
+}
+
+// loop 依間隔執行週期性工作 , 直到 Runner 停止
+func (r *Runner) loop(job periodicJob) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.run(r.ctx, job.name, job.fn, trace.WithNewRoot())
+
+			r.mu.Lock()
+			r.lastDone[job.name] = time.Now()
+			r.mu.Unlock()
+		}
+	}
+}
+
+// run 執行一次工作 , 建立 span 並記錄錯誤 , panic 時不影響其他工作
+func (r *Runner) run(ctx context.Context, name string, fn Func, opts ...trace.SpanStartOption) {
+	opts = append(opts, trace.WithAttributes(attribute.String("job.name", name)))
+	ctx, span := tracing.Start(ctx, "job "+name, opts...)
+
+	var err error
+	defer tracing.End(span, &err)
+
+	begin := time.Now()
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("job %s panic: %v", name, rec)
+		}
+
+		logger := zerolog.Ctx(ctx)
+		if err != nil {
+			logger.Err(err).Str("job", name).Dur("elapsed", time.Since(begin)).Msg("job failed")
+			return
+		}
+		logger.Debug().Str("job", name).Dur("elapsed", time.Since(begin)).Msg("job done")
+	}()
+
+	err = fn(ctx)
+}
@@ -0,0 +1,151 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+// RunnerTestSuite 用於 Runner 的測試
+type RunnerTestSuite struct {
+	suite.Suite
+	recorder *tracetest.SpanRecorder
+	runner   *Runner
+}
+
+// SetupTest 於每個測試案例前執行 , 設定記錄 span 的 tracer provider
+func (s *RunnerTestSuite) SetupTest() {
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+	s.runner = NewRunner(&logger)
+}
+
+// TestRunnerSuite 執行測試套件
+func TestRunnerSuite(t *testing.T) {
+	suite.Run(t, new(RunnerTestSuite))
+}
+
+// 一次性工作承接呼叫端 trace context 測試
+// Test for Runner.Go
+func (s *RunnerTestSuite) TestGoPropagatesTrace() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, parent := tracing.Start(ctx, "request")
+
+	done := make(chan trace.SpanContext, 1)
+	s.runner.Go(ctx, "notify", func(ctx context.Context) error {
+		<-time.After(10 * time.Millisecond)
+		// 呼叫端取消不影響背景工作
+		s.NoError(ctx.Err())
+		done <- trace.SpanContextFromContext(ctx)
+		return nil
+	})
+
+	// 模擬請求結束
+	parent.End()
+	cancel()
+
+	jobSpan := <-done
+	s.Require().NoError(s.runner.Stop(context.Background()))
+
+	s.Equal(parent.SpanContext().TraceID(), jobSpan.TraceID())
+	s.NotEqual(parent.SpanContext().SpanID(), jobSpan.SpanID())
+
+	var names []string
+	for _, span := range s.recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	s.Contains(names, "job notify")
+}
+
+// Stop 取消執行中的工作測試
+// Test for Runner.Stop
+func (s *RunnerTestSuite) TestStopCancelsJobs() {
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	s.runner.Go(context.Background(), "long", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	})
+	<-started
+
+	s.Require().NoError(s.runner.Stop(context.Background()))
+	s.True(cancelled.Load())
+
+	// 停止後的工作不會執行
+	var ran atomic.Bool
+	s.runner.Go(context.Background(), "late", func(ctx context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+	s.False(ran.Load())
+}
+
+// 週期性工作測試 , 錯誤與 panic 不影響後續執行
+// Test for Runner.Every
+func (s *RunnerTestSuite) TestEvery() {
+	var count atomic.Int32
+	s.runner.Every("tick", 5*time.Millisecond, func(ctx context.Context) error {
+		switch count.Add(1) {
+		case 1:
+			return errors.New("boom")
+		case 2:
+			panic("boom")
+		}
+		return nil
+	})
+	s.Require().NoError(s.runner.Start(context.Background()))
+	s.Error(s.runner.Start(context.Background()), "start twice should fail")
+
+	s.Eventually(func() bool { return count.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	s.Require().NoError(s.runner.Stop(context.Background()))
+
+	spans := s.recorder.Ended()
+	s.Require().NotEmpty(spans)
+	// 週期性工作每次執行都是新的 trace
+	if len(spans) >= 2 {
+		assert.NotEqual(s.T(), spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	}
+}
+
+// Runner 健康檢查測試
+// Test for Runner.Check
+func (s *RunnerTestSuite) TestCheck() {
+	s.ErrorIs(s.runner.Check(context.Background()), ErrNotRunning, "not started")
+
+	block := make(chan struct{})
+	s.runner.Every("stuck", 5*time.Millisecond, func(ctx context.Context) error {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	s.Require().NoError(s.runner.Start(context.Background()))
+	s.NoError(s.runner.Check(context.Background()))
+
+	// 工作卡住超過 3 倍間隔
+	s.Eventually(func() bool {
+		return s.runner.Check(context.Background()) != nil
+	}, time.Second, 5*time.Millisecond)
+
+	close(block)
+	s.Require().NoError(s.runner.Stop(context.Background()))
+	s.ErrorIs(s.runner.Check(context.Background()), ErrNotRunning, "stopped")
+}
@@ -0,0 +1,132 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Component 由 Manager 管理啟動與停止的元件
+// Start 與 Stop 皆可為 nil , 例如啟動前已建立的資源只需要註冊 Stop
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager 依註冊順序啟動元件 , 並以相反順序停止
+//
+// Example:
+//
+//	m := lifecycle.NewManager(logger, 30*time.Second)
+//	m.Add(lifecycle.Component{Name: "db", Stop: closeDB})
+//	m.Add(lifecycle.Component{Name: "http_server", Start: srv.Start, Stop: srv.Shutdown})
+//	if err := m.Run(ctx); err != nil {
+//		logger.Err(err).Msg("shutdown with errors")
+//	}
+type Manager struct {
+	logger          *zerolog.Logger
+	shutdownTimeout time.Duration
+	components      []Component
+}
+
+// NewManager 建立 Manager
+// 參數: logger - logger, shutdownTimeout - 停止所有元件的時間上限
+func NewManager(logger *zerolog.Logger, shutdownTimeout time.Duration) *Manager {
+	return &Manager{
+		logger:          logger,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+// Add 註冊元件 , 啟動順序與註冊順序相同
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// Run 依序啟動所有元件 , 等待 ctx 結束 (例如收到 SIGINT/SIGTERM) 後以相反順序停止
+// 任一元件啟動失敗時 , 停止已啟動的元件並回傳啟動錯誤
+// 停止階段共用 shutdownTimeout , 逾時後仍會繼續呼叫剩餘元件的 Stop , 讓元件自行處理已逾時的 ctx
+// 回傳: 啟動與停止過程的所有錯誤
+func (m *Manager) Run(ctx context.Context) error {
+	started, startErr := m.start(ctx)
+	if startErr == nil {
+		m.logger.Info().Msg("all components started")
+		<-ctx.Done()
+		m.logger.Info().Msg("shutting down")
+	}
+
+	stopErr := m.stop(context.WithoutCancel(ctx), started)
+	if stopErr == nil {
+		m.logger.Info().Msg("all components stopped")
+	}
+
+	return errors.Join(startErr, stopErr)
+}
+
+// Close 於 Run 之前發生錯誤時呼叫 , 以相反順序停止已註冊且不需要啟動 (Start 為 nil) 的元件 , 例如已開啟的 db 連線
+// 需要啟動的元件尚未啟動 , 不呼叫其 Stop
+// 回傳: 停止過程的所有錯誤
+func (m *Manager) Close(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(m.components) - 1; i >= 0; i-- {
+		component := m.components[i]
+		if component.Start != nil || component.Stop == nil {
+			continue
+		}
+		if err := component.Stop(ctx); err != nil {
+			m.logger.Err(err).Str("component", component.Name).Msg("failed to stop component")
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// start 依序啟動元件 , 回傳: 已成功啟動的元件數量 , 啟動錯誤
+func (m *Manager) start(ctx context.Context) (int, error) {
+	for i, component := range m.components {
+		if component.Start == nil {
+			continue
+		}
+
+		m.logger.Info().Str("component", component.Name).Msg("starting component")
+		if err := component.Start(ctx); err != nil {
+			return i, fmt.Errorf("failed to start %s: %w", component.Name, err)
+		}
+	}
+
+	return len(m.components), nil
+}
+
+// stop 以相反順序停止前 count 個元件
+func (m *Manager) stop(ctx context.Context, count int) error {
+	ctx, cancel := context.WithTimeout(ctx, m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := count - 1; i >= 0; i-- {
+		component := m.components[i]
+		if component.Stop == nil {
+			continue
+		}
+
+		begin := time.Now()
+		if err := component.Stop(ctx); err != nil {
+			m.logger.Err(err).Str("component", component.Name).Msg("failed to stop component")
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+			continue
+		}
+		m.logger.Info().
+			Str("component", component.Name).
+			Dur("elapsed", time.Since(begin)).
+			Msg("component stopped")
+	}
+
+	return errors.Join(errs...)
+}
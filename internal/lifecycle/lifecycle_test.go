@@ -0,0 +1,157 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder 記錄元件啟動與停止順序
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, startErr, stopErr error) Component {
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+// 元件啟動與停止順序測試
+// Test for Manager.Run
+func TestRun(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		components func(r *recorder) []Component
+		assertFunc func(t *testing.T, events []string, err error)
+	}{
+		{
+			name: "start in order and stop in reverse",
+			components: func(r *recorder) []Component {
+				return []Component{
+					r.component("db", nil, nil),
+					r.component("job_runner", nil, nil),
+					r.component("http_server", nil, nil),
+				}
+			},
+			assertFunc: func(t *testing.T, events []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{
+					"start db", "start job_runner", "start http_server",
+					"stop http_server", "stop job_runner", "stop db",
+				}, events)
+			},
+		},
+		{
+			name: "start failed stops started components",
+			components: func(r *recorder) []Component {
+				return []Component{
+					r.component("db", nil, nil),
+					r.component("job_runner", errBoom, nil),
+					r.component("http_server", nil, nil),
+				}
+			},
+			assertFunc: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, errBoom)
+				assert.ErrorContains(t, err, "failed to start job_runner")
+				assert.Equal(t, []string{"start db", "start job_runner", "stop db"}, events)
+			},
+		},
+		{
+			name: "stop errors reported and remaining components still stopped",
+			components: func(r *recorder) []Component {
+				return []Component{
+					{Name: "tracing", Stop: func(ctx context.Context) error {
+						r.events = append(r.events, "stop tracing")
+						return nil
+					}},
+					r.component("db", nil, errBoom),
+					r.component("http_server", nil, nil),
+				}
+			},
+			assertFunc: func(t *testing.T, events []string, err error) {
+				assert.ErrorIs(t, err, errBoom)
+				assert.ErrorContains(t, err, "failed to stop db")
+				assert.Equal(t, []string{"start db", "start http_server", "stop http_server", "stop db", "stop tracing"}, events)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			r := &recorder{}
+			m := NewManager(&logger, time.Second)
+			for _, component := range test.components(r) {
+				m.Add(component)
+			}
+
+			// ctx 已取消 , 模擬啟動後立即收到 signal
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := m.Run(ctx)
+			test.assertFunc(t, r.events, err)
+		})
+	}
+}
+
+// 停止逾時測試
+func TestRunShutdownTimeout(t *testing.T) {
+	logger := zerolog.Nop()
+	m := NewManager(&logger, 20*time.Millisecond)
+
+	m.Add(Component{
+		Name: "slow",
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	begin := time.Now()
+	err := m.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(begin), time.Second)
+}
+
+// Run 之前發生錯誤時只停止不需要啟動的元件
+// Test for Manager.Close
+func TestClose(t *testing.T) {
+	logger := zerolog.Nop()
+	m := NewManager(&logger, time.Second)
+	r := &recorder{}
+
+	errBoom := errors.New("boom")
+	db := r.component("db", nil, errBoom)
+	db.Start = nil
+	tracing := r.component("tracing", nil, nil)
+	tracing.Start = nil
+	m.Add(tracing)
+	m.Add(db)
+	m.Add(r.component("http_server", nil, nil))
+
+	err := m.Close(context.Background())
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []string{"stop db", "stop tracing"}, r.events)
+}
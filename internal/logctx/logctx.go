@@ -0,0 +1,75 @@
+package logctx
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	userIDKey
+	traceIDKey
+)
+
+// log 欄位名稱
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldTraceID   = "trace_id"
+)
+
+// WithLogger 將 logger 放入 ctx , 之後可用 zerolog.Ctx(ctx) 取得
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return logger.WithContext(ctx)
+}
+
+// WithRequestID 將 request ID 放入 ctx , 並加入 ctx logger 的欄位
+//
+// Example:
+//
+//	ctx = logctx.WithRequestID(ctx, "req-1")
+//	zerolog.Ctx(ctx).Info().Msg("hello") // {"request_id":"req-1",...}
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey, requestID)
+	return withField(ctx, FieldRequestID, requestID)
+}
+
+// WithUserID 將操作者 user ID 放入 ctx , 並加入 ctx logger 的欄位
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return withField(ctx, FieldUserID, strconv.FormatUint(uint64(userID), 10))
+}
+
+// WithTraceID 將 trace ID 放入 ctx , 並加入 ctx logger 的欄位
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	ctx = context.WithValue(ctx, traceIDKey, traceID)
+	return withField(ctx, FieldTraceID, traceID)
+}
+
+// RequestID 取得 ctx 中的 request ID , 不存在時回傳空字串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// UserID 取得 ctx 中的 user ID , 不存在時回傳 false
+func UserID(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(userIDKey).(uint)
+	return id, ok
+}
+
+// TraceID 取得 ctx 中的 trace ID , 不存在時回傳空字串
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey).(string)
+	return id
+}
+
+// withField 以 ctx 中的 logger 為基礎加上欄位 , 再放回 ctx
+func withField(ctx context.Context, key, value string) context.Context {
+	logger := zerolog.Ctx(ctx).With().Str(key, value).Logger()
+	return logger.WithContext(ctx)
+}
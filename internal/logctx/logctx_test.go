@@ -0,0 +1,52 @@
+package logctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ctx logger 帶入 request_id / user_id / trace_id 欄位測試
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	ctx := WithLogger(context.Background(), &logger)
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithUserID(ctx, 42)
+	ctx = WithTraceID(ctx, "trace-1")
+
+	zerolog.Ctx(ctx).Info().Msg("hello")
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "req-1", got[FieldRequestID])
+	assert.Equal(t, "42", got[FieldUserID])
+	assert.Equal(t, "trace-1", got[FieldTraceID])
+
+	assert.Equal(t, "req-1", RequestID(ctx))
+	assert.Equal(t, "trace-1", TraceID(ctx))
+	userID, ok := UserID(ctx)
+	assert.True(t, ok)
+	assert.EqualValues(t, 42, userID)
+}
+
+// ctx 中沒有值的情況測試
+func TestEmptyContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Empty(t, RequestID(ctx))
+	assert.Empty(t, TraceID(ctx))
+	_, ok := UserID(ctx)
+	assert.False(t, ok)
+
+	// 沒有 logger 時不應 panic
+	assert.NotPanics(t, func() {
+		zerolog.Ctx(WithRequestID(ctx, "req-1")).Info().Msg("hello")
+	})
+}
@@ -0,0 +1,131 @@
+package meeting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshMargin access token 到期前多久重新取得
+const tokenRefreshMargin = time.Minute
+
+// APIError 會議服務回應非 2xx
+type APIError struct {
+	Provider   string // 會議服務名稱
+	StatusCode int    // HTTP 狀態碼
+	Body       string // 回應內容 , 最多 1KB
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s responded with status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// tokenResponse OAuth token endpoint 的回應
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"` // 秒
+}
+
+// tokenSource 快取 OAuth access token , 到期前 tokenRefreshMargin 重新取得
+type tokenSource struct {
+	provider string
+	client   *http.Client
+	url      string     // token endpoint
+	form     url.Values // grant_type 與 credential
+	user     string     // 以 Basic auth 傳送的 client id , 空字串表示 credential 放在 form
+	password string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+// Token 取得 access token
+func (t *tokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.token != "" && now.Before(t.expiresAt.Add(-tokenRefreshMargin)) {
+		return t.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, strings.NewReader(t.form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if t.user != "" {
+		req.SetBasicAuth(t.user, t.password)
+	}
+
+	var resp tokenResponse
+	if err := send(t.client, t.provider, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("%s token endpoint returned empty access token", t.provider)
+	}
+
+	t.token = resp.AccessToken
+	t.expiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return t.token, nil
+}
+
+// call 以 access token 呼叫會議服務的 JSON API
+// 參數: in - 請求內容 , nil 表示沒有內容, out - 解析回應的目標 , nil 表示不解析
+func call(ctx context.Context, client *http.Client, tokens *tokenSource, method, url string, in, out any) error {
+	token, err := tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return send(client, tokens.provider, req, out)
+}
+
+// send 送出請求 , 回應非 2xx 時回傳 *APIError
+func send(client *http.Client, provider string, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return &APIError{Provider: provider, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package meeting
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ProviderFromViper 依 MEETING_PROVIDER 建立會議服務
+// 回傳: 會議服務, 錯誤訊息 , 不支援的會議服務或缺少 credential 時回傳錯誤
+func ProviderFromViper() (Provider, error) {
+	viper.SetDefault("MEETING_PROVIDER", FakeProvider)
+	viper.SetDefault("MEETING_TIMEOUT", 10*time.Second)
+
+	client := &http.Client{Timeout: viper.GetDuration("MEETING_TIMEOUT")}
+	switch provider := viper.GetString("MEETING_PROVIDER"); provider {
+	case FakeProvider:
+		return NewFakeMeeting(FakeConfigFromViper()), nil
+	case ZoomProvider:
+		cfg := ZoomConfigFromViper()
+		if cfg.AccountID == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+			return nil, errors.New("ZOOM_ACCOUNT_ID, ZOOM_CLIENT_ID and ZOOM_CLIENT_SECRET are required")
+		}
+		return NewZoomMeeting(cfg, client), nil
+	case MeetProvider:
+		cfg := MeetConfigFromViper()
+		if cfg.ClientID == "" || cfg.ClientSecret == "" || cfg.RefreshToken == "" {
+			return nil, errors.New("MEET_CLIENT_ID, MEET_CLIENT_SECRET and MEET_REFRESH_TOKEN are required")
+		}
+		return NewMeetMeeting(cfg, client), nil
+	default:
+		return nil, fmt.Errorf("unsupported meeting provider %q", provider)
+	}
+}
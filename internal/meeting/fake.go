@@ -0,0 +1,119 @@
+package meeting
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// FakeProvider 本機模擬會議服務的名稱
+const FakeProvider = "fake"
+
+// FakeConfig 本機模擬會議服務設定
+type FakeConfig struct {
+	BaseURL string // 產生加入網址使用的網址
+}
+
+// FakeConfigFromViper 從 viper 讀取本機模擬會議服務設定
+func FakeConfigFromViper() FakeConfig {
+	viper.SetDefault("MEETING_FAKE_BASE_URL", "http://localhost:8080")
+
+	return FakeConfig{
+		BaseURL: viper.GetString("MEETING_FAKE_BASE_URL"),
+	}
+}
+
+// FakeMeeting 本機模擬會議服務 , 供開發與測試使用
+// 加入網址為 {BaseURL}/fake-meetings/{id} , 不會實際開啟會議 , 會議只存在記憶體
+//
+// Example:
+//
+//	provider := meeting.NewFakeMeeting(meeting.FakeConfigFromViper())
+//	srv.Handle("GET /fake-meetings/{id}", provider.Join())
+type FakeMeeting struct {
+	cfg      FakeConfig
+	mu       sync.Mutex
+	meetings map[string]Request
+}
+
+var _ Provider = (*FakeMeeting)(nil)
+
+// NewFakeMeeting 建立本機模擬會議服務
+// 參數: cfg - 設定
+func NewFakeMeeting(cfg FakeConfig) *FakeMeeting {
+	return &FakeMeeting{cfg: cfg, meetings: make(map[string]Request)}
+}
+
+// Name 會議服務名稱
+func (f *FakeMeeting) Name() string {
+	return FakeProvider
+}
+
+// CreateMeeting 建立會議 , 會議ID為 fake_ 加上隨機值 , 主持人金鑰為 6 位數字
+func (f *FakeMeeting) CreateMeeting(ctx context.Context, req Request) (*Meeting, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate host key: %w", err)
+	}
+	id := "fake_" + uuid.NewString()
+
+	f.mu.Lock()
+	f.meetings[id] = req
+	f.mu.Unlock()
+
+	return &Meeting{
+		ID:      id,
+		JoinURL: f.cfg.BaseURL + "/fake-meetings/" + id,
+		HostKey: fmt.Sprintf("%06d", n.Int64()),
+	}, nil
+}
+
+// UpdateMeeting 更新會議 , 重新啟動後不知道之前建立的會議 , 視為成功
+func (f *FakeMeeting) UpdateMeeting(ctx context.Context, id string, req Request) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.meetings[id] = req
+	return nil
+}
+
+// CancelMeeting 取消會議
+func (f *FakeMeeting) CancelMeeting(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.meetings, id)
+	return nil
+}
+
+// Join 模擬加入會議的頁面 , 回傳會議的主題與時間 , 會議不存在或已取消時回傳 404
+func (f *FakeMeeting) Join() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		req, ok := f.Get(id)
+		if !ok {
+			http.Error(w, "meeting not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"meeting_id": id, "topic": req.Topic, "start_at": req.StartAt, "end_at": req.EndAt})
+	})
+}
+
+// Get 查詢會議內容 , 供測試確認會議的主題與時間
+// 回傳: 會議內容, 會議是否存在
+func (f *FakeMeeting) Get(id string) (Request, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	req, ok := f.meetings[id]
+	return req, ok
+}
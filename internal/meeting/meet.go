@@ -0,0 +1,169 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// MeetProvider Google Meet 會議服務的名稱
+const MeetProvider = "meet"
+
+// MeetConfig Google Meet 設定 , 以 Google Calendar API 建立附有 Meet 連結的行事曆活動
+// 使用 OAuth client 與行事曆擁有者授權的 refresh token (scope: calendar.events)
+type MeetConfig struct {
+	ClientID     string // OAuth client ID
+	ClientSecret string // OAuth client secret
+	RefreshToken string // 行事曆擁有者的 refresh token
+	CalendarID   string // 建立活動的行事曆 , 預設 primary
+	APIURL       string // Calendar API 網址
+	TokenURL     string // OAuth token 網址
+}
+
+// MeetConfigFromViper 從 viper 讀取 Google Meet 設定
+func MeetConfigFromViper() MeetConfig {
+	viper.SetDefault("MEET_CALENDAR_ID", "primary")
+	viper.SetDefault("MEET_API_URL", "https://www.googleapis.com/calendar/v3")
+	viper.SetDefault("MEET_TOKEN_URL", "https://oauth2.googleapis.com/token")
+
+	return MeetConfig{
+		ClientID:     viper.GetString("MEET_CLIENT_ID"),
+		ClientSecret: viper.GetString("MEET_CLIENT_SECRET"),
+		RefreshToken: viper.GetString("MEET_REFRESH_TOKEN"),
+		CalendarID:   viper.GetString("MEET_CALENDAR_ID"),
+		APIURL:       viper.GetString("MEET_API_URL"),
+		TokenURL:     viper.GetString("MEET_TOKEN_URL"),
+	}
+}
+
+// meetTime Calendar API 的活動時間
+type meetTime struct {
+	DateTime string `json:"dateTime"` // RFC3339
+}
+
+// meetEvent Calendar API 建立與更新活動的內容
+type meetEvent struct {
+	Summary        string          `json:"summary"`
+	Start          meetTime        `json:"start"`
+	End            meetTime        `json:"end"`
+	ConferenceData *meetConference `json:"conferenceData,omitempty"`
+}
+
+// meetConference 建立活動時要求建立 Meet 會議
+type meetConference struct {
+	CreateRequest struct {
+		RequestID             string `json:"requestId"`
+		ConferenceSolutionKey struct {
+			Type string `json:"type"`
+		} `json:"conferenceSolutionKey"`
+	} `json:"createRequest"`
+}
+
+// meetCreated Calendar API 建立活動的回應
+type meetCreated struct {
+	ID          string `json:"id"`
+	HangoutLink string `json:"hangoutLink"`
+}
+
+// MeetMeeting Google Meet 會議服務
+// 會議ID為行事曆活動ID , 行事曆擁有者即為主持人 , Meet 沒有主持人金鑰 , HostKey 為空字串
+// 建立、更新與取消活動皆不寄送行事曆邀請
+//
+// Example:
+//
+//	provider := meeting.NewMeetMeeting(meeting.MeetConfigFromViper(), &http.Client{Timeout: 10 * time.Second})
+type MeetMeeting struct {
+	cfg    MeetConfig
+	client *http.Client
+	tokens *tokenSource
+}
+
+var _ Provider = (*MeetMeeting)(nil)
+
+// NewMeetMeeting 建立 Google Meet 會議服務
+// 參數: cfg - 設定, client - HTTP client , 須設定 Timeout
+func NewMeetMeeting(cfg MeetConfig, client *http.Client) *MeetMeeting {
+	return &MeetMeeting{
+		cfg:    cfg,
+		client: client,
+		tokens: &tokenSource{
+			provider: MeetProvider,
+			client:   client,
+			url:      cfg.TokenURL,
+			form: url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {cfg.ClientID},
+				"client_secret": {cfg.ClientSecret},
+				"refresh_token": {cfg.RefreshToken},
+			},
+			now: time.Now,
+		},
+	}
+}
+
+// Name 會議服務名稱
+func (m *MeetMeeting) Name() string {
+	return MeetProvider
+}
+
+// CreateMeeting 建立附有 Meet 連結的行事曆活動
+func (m *MeetMeeting) CreateMeeting(ctx context.Context, req Request) (*Meeting, error) {
+	body := newMeetEvent(req)
+	body.ConferenceData = &meetConference{}
+	body.ConferenceData.CreateRequest.RequestID = uuid.NewString()
+	body.ConferenceData.CreateRequest.ConferenceSolutionKey.Type = "hangoutsMeet"
+
+	var created meetCreated
+	if err := call(ctx, m.client, m.tokens, http.MethodPost, m.eventsURL("")+"?conferenceDataVersion=1&sendUpdates=none", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create meet event: %w", err)
+	}
+	if created.ID == "" || created.HangoutLink == "" {
+		return nil, errors.New("google calendar returned event without id or hangoutLink")
+	}
+	return &Meeting{ID: created.ID, JoinURL: created.HangoutLink}, nil
+}
+
+// UpdateMeeting 更新活動的主題與時間 , Meet 連結不變
+func (m *MeetMeeting) UpdateMeeting(ctx context.Context, id string, req Request) error {
+	if err := call(ctx, m.client, m.tokens, http.MethodPatch, m.eventsURL(id)+"?sendUpdates=none", newMeetEvent(req), nil); err != nil {
+		return fmt.Errorf("failed to update meet event %s: %w", id, err)
+	}
+	return nil
+}
+
+// CancelMeeting 刪除活動 , 活動不存在 (404) 或已刪除 (410) 時視為成功
+func (m *MeetMeeting) CancelMeeting(ctx context.Context, id string) error {
+	err := call(ctx, m.client, m.tokens, http.MethodDelete, m.eventsURL(id)+"?sendUpdates=none", nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete meet event %s: %w", id, err)
+	}
+	return nil
+}
+
+// eventsURL 行事曆活動的網址 , id 為空字串時為活動清單
+func (m *MeetMeeting) eventsURL(id string) string {
+	u := m.cfg.APIURL + "/calendars/" + url.PathEscape(m.cfg.CalendarID) + "/events"
+	if id != "" {
+		u += "/" + url.PathEscape(id)
+	}
+	return u
+}
+
+// newMeetEvent 轉換為 Calendar API 的活動內容
+func newMeetEvent(req Request) meetEvent {
+	return meetEvent{
+		Summary: req.Topic,
+		Start:   meetTime{DateTime: req.StartAt.UTC().Format(time.RFC3339)},
+		End:     meetTime{DateTime: req.EndAt.UTC().Format(time.RFC3339)},
+	}
+}
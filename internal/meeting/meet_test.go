@@ -0,0 +1,83 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetMeeting(t *testing.T) {
+	var (
+		tokenCalls int
+		now        = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+		assert.Equal(t, "refresh", r.Form.Get("refresh_token"))
+		_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: "token", ExpiresIn: 3600})
+	})
+	mux.HandleFunc("POST /calendar/v3/calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "1", r.URL.Query().Get("conferenceDataVersion"))
+
+		var body meetEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Go 入門", body.Summary)
+		assert.Equal(t, "2025-07-21T11:00:00Z", body.Start.DateTime)
+		assert.Equal(t, "2025-07-21T12:30:00Z", body.End.DateTime)
+		require.NotNil(t, body.ConferenceData)
+		assert.Equal(t, "hangoutsMeet", body.ConferenceData.CreateRequest.ConferenceSolutionKey.Type)
+		assert.NotEmpty(t, body.ConferenceData.CreateRequest.RequestID)
+		_ = json.NewEncoder(w).Encode(meetCreated{ID: "evt1", HangoutLink: "https://meet.google.com/abc-defg-hij"})
+	})
+	mux.HandleFunc("PATCH /calendar/v3/calendars/primary/events/evt1", func(w http.ResponseWriter, r *http.Request) {
+		var body meetEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2025-07-22T11:00:00Z", body.Start.DateTime)
+		assert.Nil(t, body.ConferenceData)
+		_ = json.NewEncoder(w).Encode(meetCreated{ID: "evt1"})
+	})
+	mux.HandleFunc("DELETE /calendar/v3/calendars/primary/events/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "evt1" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := NewMeetMeeting(MeetConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RefreshToken: "refresh",
+		CalendarID:   "primary",
+		APIURL:       srv.URL + "/calendar/v3",
+		TokenURL:     srv.URL + "/token",
+	}, srv.Client())
+	m.tokens.now = func() time.Time { return now }
+	ctx := context.Background()
+	startAt := time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)
+
+	created, err := m.CreateMeeting(ctx, Request{Topic: "Go 入門", StartAt: startAt, EndAt: startAt.Add(90 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, &Meeting{ID: "evt1", JoinURL: "https://meet.google.com/abc-defg-hij"}, created)
+
+	// access token 到期前一分鐘內重新取得
+	now = now.Add(59 * time.Minute)
+	startAt = startAt.AddDate(0, 0, 1)
+	require.NoError(t, m.UpdateMeeting(ctx, created.ID, Request{Topic: "Go 入門", StartAt: startAt, EndAt: startAt.Add(90 * time.Minute)}))
+	assert.Equal(t, 2, tokenCalls)
+
+	require.NoError(t, m.CancelMeeting(ctx, created.ID))
+	require.NoError(t, m.CancelMeeting(ctx, "deleted"))
+}
@@ -0,0 +1,48 @@
+package meeting
+
+import (
+	"context"
+	"time"
+)
+
+// Provider 線上會議服務介面 , 為線上課程的每次上課建立、更新與取消會議
+// 會議建立後以 Meeting.ID 操作 , 取消已不存在的會議視為成功
+//
+// Example:
+//
+//	m, err := provider.CreateMeeting(ctx, meeting.Request{Topic: "Go 入門", StartAt: startAt, EndAt: endAt})
+//	// 將 m.JoinURL 與 m.HostKey 存於上課
+//	err = provider.UpdateMeeting(ctx, m.ID, meeting.Request{Topic: "Go 入門", StartAt: newStartAt, EndAt: newEndAt})
+type Provider interface {
+	// Name 會議服務名稱 , 記錄於上課的 MeetingProvider
+	Name() string
+
+	// CreateMeeting 建立會議
+	// 參數: ctx - context, req - 會議內容
+	// 回傳: 會議, 錯誤訊息
+	CreateMeeting(ctx context.Context, req Request) (*Meeting, error)
+
+	// UpdateMeeting 更新會議的主題與時間 , 加入網址與主持人金鑰不變
+	// 參數: ctx - context, id - 會議服務的會議ID, req - 會議內容
+	// 回傳: 錯誤訊息
+	UpdateMeeting(ctx context.Context, id string, req Request) error
+
+	// CancelMeeting 取消會議
+	// 參數: ctx - context, id - 會議服務的會議ID
+	// 回傳: 錯誤訊息
+	CancelMeeting(ctx context.Context, id string) error
+}
+
+// Request 建立或更新會議的內容
+type Request struct {
+	Topic   string    // 會議主題 , 例如課程名稱
+	StartAt time.Time // 開始時間
+	EndAt   time.Time // 結束時間
+}
+
+// Meeting 會議服務建立的會議
+type Meeting struct {
+	ID      string // 會議服務的會議ID
+	JoinURL string // 學生加入會議的網址
+	HostKey string // 主持人金鑰 , 授課教師取得主持權限使用 , 會議服務不提供時為空字串
+}
@@ -0,0 +1,143 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ZoomProvider Zoom 會議服務的名稱
+const ZoomProvider = "zoom"
+
+// ZoomConfig Zoom 設定 , 使用 Server-to-Server OAuth app
+type ZoomConfig struct {
+	AccountID    string // OAuth app 的 account ID
+	ClientID     string // OAuth app 的 client ID
+	ClientSecret string // OAuth app 的 client secret
+	UserID       string // 建立會議的 Zoom 使用者 , 預設 me
+	HostKey      string // 會議主持人 (UserID) 的 host key , Zoom 的 host key 屬於使用者 , API 不提供
+	APIURL       string // API 網址
+	TokenURL     string // OAuth token 網址
+}
+
+// ZoomConfigFromViper 從 viper 讀取 Zoom 設定
+func ZoomConfigFromViper() ZoomConfig {
+	viper.SetDefault("ZOOM_USER_ID", "me")
+	viper.SetDefault("ZOOM_API_URL", "https://api.zoom.us/v2")
+	viper.SetDefault("ZOOM_TOKEN_URL", "https://zoom.us/oauth/token")
+
+	return ZoomConfig{
+		AccountID:    viper.GetString("ZOOM_ACCOUNT_ID"),
+		ClientID:     viper.GetString("ZOOM_CLIENT_ID"),
+		ClientSecret: viper.GetString("ZOOM_CLIENT_SECRET"),
+		UserID:       viper.GetString("ZOOM_USER_ID"),
+		HostKey:      viper.GetString("ZOOM_HOST_KEY"),
+		APIURL:       viper.GetString("ZOOM_API_URL"),
+		TokenURL:     viper.GetString("ZOOM_TOKEN_URL"),
+	}
+}
+
+// zoomMeeting Zoom 建立與更新會議的內容
+type zoomMeeting struct {
+	Topic     string `json:"topic"`
+	Type      int    `json:"type,omitempty"` // 2: 預定的會議
+	StartTime string `json:"start_time"`     // UTC , yyyy-MM-ddTHH:mm:ssZ
+	Duration  int    `json:"duration"`       // 分鐘
+	Timezone  string `json:"timezone"`
+}
+
+// zoomCreated Zoom 建立會議的回應
+type zoomCreated struct {
+	ID      int64  `json:"id"`
+	JoinURL string `json:"join_url"`
+}
+
+// ZoomMeeting Zoom 會議服務
+// 會議建立於 ZoomConfig.UserID , 主持人金鑰為該使用者的 host key
+//
+// Example:
+//
+//	provider := meeting.NewZoomMeeting(meeting.ZoomConfigFromViper(), &http.Client{Timeout: 10 * time.Second})
+type ZoomMeeting struct {
+	cfg    ZoomConfig
+	client *http.Client
+	tokens *tokenSource
+}
+
+var _ Provider = (*ZoomMeeting)(nil)
+
+// NewZoomMeeting 建立 Zoom 會議服務
+// 參數: cfg - 設定, client - HTTP client , 須設定 Timeout
+func NewZoomMeeting(cfg ZoomConfig, client *http.Client) *ZoomMeeting {
+	return &ZoomMeeting{
+		cfg:    cfg,
+		client: client,
+		tokens: &tokenSource{
+			provider: ZoomProvider,
+			client:   client,
+			url:      cfg.TokenURL,
+			form:     url.Values{"grant_type": {"account_credentials"}, "account_id": {cfg.AccountID}},
+			user:     cfg.ClientID,
+			password: cfg.ClientSecret,
+			now:      time.Now,
+		},
+	}
+}
+
+// Name 會議服務名稱
+func (z *ZoomMeeting) Name() string {
+	return ZoomProvider
+}
+
+// CreateMeeting 建立預定的會議
+func (z *ZoomMeeting) CreateMeeting(ctx context.Context, req Request) (*Meeting, error) {
+	body := newZoomMeeting(req)
+	body.Type = 2
+
+	var created zoomCreated
+	if err := call(ctx, z.client, z.tokens, http.MethodPost, z.cfg.APIURL+"/users/"+url.PathEscape(z.cfg.UserID)+"/meetings", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create zoom meeting: %w", err)
+	}
+	if created.ID == 0 || created.JoinURL == "" {
+		return nil, errors.New("zoom returned meeting without id or join_url")
+	}
+	return &Meeting{ID: strconv.FormatInt(created.ID, 10), JoinURL: created.JoinURL, HostKey: z.cfg.HostKey}, nil
+}
+
+// UpdateMeeting 更新會議的主題與時間
+func (z *ZoomMeeting) UpdateMeeting(ctx context.Context, id string, req Request) error {
+	if err := call(ctx, z.client, z.tokens, http.MethodPatch, z.cfg.APIURL+"/meetings/"+url.PathEscape(id), newZoomMeeting(req), nil); err != nil {
+		return fmt.Errorf("failed to update zoom meeting %s: %w", id, err)
+	}
+	return nil
+}
+
+// CancelMeeting 刪除會議 , 會議不存在 (404) 時視為成功
+func (z *ZoomMeeting) CancelMeeting(ctx context.Context, id string) error {
+	err := call(ctx, z.client, z.tokens, http.MethodDelete, z.cfg.APIURL+"/meetings/"+url.PathEscape(id), nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete zoom meeting %s: %w", id, err)
+	}
+	return nil
+}
+
+// newZoomMeeting 轉換為 Zoom 的會議內容 , 時長以分鐘無條件進位
+func newZoomMeeting(req Request) zoomMeeting {
+	return zoomMeeting{
+		Topic:     req.Topic,
+		StartTime: req.StartAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Duration:  int(math.Ceil(req.EndAt.Sub(req.StartAt).Minutes())),
+		Timezone:  "UTC",
+	}
+}
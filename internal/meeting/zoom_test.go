@@ -0,0 +1,98 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newZoomServer 模擬 Zoom 的 OAuth 與會議 API , 會議 404 不存在
+// 回傳: 伺服器, 取得 token 的次數
+func newZoomServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+
+	var tokenCalls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenCalls++
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", user)
+		assert.Equal(t, "secret", password)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "account_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "account", r.Form.Get("account_id"))
+		_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: "token", ExpiresIn: 3600})
+	})
+	mux.HandleFunc("POST /v2/users/me/meetings", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var body zoomMeeting
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Go 入門", body.Topic)
+		assert.Equal(t, 2, body.Type)
+		assert.Equal(t, "2025-07-21T11:00:00Z", body.StartTime)
+		assert.Equal(t, 90, body.Duration)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(zoomCreated{ID: 85746065432, JoinURL: "https://zoom.us/j/85746065432"})
+	})
+	mux.HandleFunc("PATCH /v2/meetings/85746065432", func(w http.ResponseWriter, r *http.Request) {
+		var body zoomMeeting
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2025-07-22T11:00:00Z", body.StartTime)
+		assert.Zero(t, body.Type)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /v2/meetings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "85746065432" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":3001,"message":"Meeting does not exist"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PATCH /v2/meetings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &tokenCalls
+}
+
+func TestZoomMeeting(t *testing.T) {
+	srv, tokenCalls := newZoomServer(t)
+	z := NewZoomMeeting(ZoomConfig{
+		AccountID:    "account",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		UserID:       "me",
+		HostKey:      "123456",
+		APIURL:       srv.URL + "/v2",
+		TokenURL:     srv.URL + "/oauth/token",
+	}, srv.Client())
+	ctx := context.Background()
+	startAt := time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)
+
+	m, err := z.CreateMeeting(ctx, Request{Topic: "Go 入門", StartAt: startAt, EndAt: startAt.Add(90 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, &Meeting{ID: "85746065432", JoinURL: "https://zoom.us/j/85746065432", HostKey: "123456"}, m)
+
+	startAt = startAt.AddDate(0, 0, 1)
+	require.NoError(t, z.UpdateMeeting(ctx, m.ID, Request{Topic: "Go 入門", StartAt: startAt, EndAt: startAt.Add(90 * time.Minute)}))
+	require.NoError(t, z.CancelMeeting(ctx, m.ID))
+	assert.Equal(t, 1, *tokenCalls, "access token 快取至到期前")
+
+	// 已刪除的會議視為取消成功 , 更新則回傳錯誤
+	require.NoError(t, z.CancelMeeting(ctx, "1"))
+	err = z.UpdateMeeting(ctx, "1", Request{Topic: "Go 入門", StartAt: startAt, EndAt: startAt.Add(time.Hour)})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
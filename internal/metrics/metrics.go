@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cms"
+
+// Registry 系統使用的 prometheus registry
+// 不使用 prometheus.DefaultRegisterer , 避免測試與第三方套件互相影響
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration HTTP 請求耗時 , 依 method / route / status 分類
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// RepoQueryDuration repository 查詢耗時 , 依 repository / method 分類
+	RepoQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "query_duration_seconds",
+		Help:      "Repository method latency in seconds.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method"})
+
+	// EnrollmentsTotal 報名數量 , 於報名確認時累計 (免費課程報名成功或付費課程付款完成)
+	EnrollmentsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "enrollments_total",
+		Help:      "Number of course enrollments.",
+	})
+
+	// CourseStatusTransitionsTotal 課程狀態轉換數量 , 依轉換前後狀態分類
+	CourseStatusTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "course_status_transitions_total",
+		Help:      "Number of course status transitions.",
+	}, []string{"from", "to"})
+
+	// TeacherApprovalsTotal 教師審核數量 , 依審核結果分類
+	TeacherApprovalsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "business",
+		Name:      "teacher_approvals_total",
+		Help:      "Number of teacher review decisions.",
+	}, []string{"result"})
+
+	// OutboxEventsTotal outbox 事件發送數量 , 依結果分類 (published / failed / dead)
+	OutboxEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "events_total",
+		Help:      "Number of outbox event delivery attempts by result.",
+	}, []string{"result"})
+
+	// WebhookDeliveriesTotal webhook 發送次數 , 依結果分類 (succeeded / retry / failed)
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Number of webhook delivery attempts by result.",
+	}, []string{"result"})
+
+	// NotificationsTotal 通知發送次數 , 依管道與結果分類 (sent / retry / failed)
+	NotificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notification",
+		Name:      "sends_total",
+		Help:      "Number of notification send attempts by channel and result.",
+	}, []string{"channel", "result"})
+
+	// PaymentCallbacksTotal 金流商付款通知次數 , 依金流商與結果分類 (applied / refunded / ignored / duplicate / invalid)
+	PaymentCallbacksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "payment",
+		Name:      "callbacks_total",
+		Help:      "Number of payment provider callbacks by provider and result.",
+	}, []string{"provider", "result"})
+
+	// PromotionRedemptionsTotal 促銷活動使用次數 , 依類型 (coupon / automatic) 與結果 (redeemed / released) 分類
+	PromotionRedemptionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "promotion",
+		Name:      "redemptions_total",
+		Help:      "Number of promotion redemptions by kind and result.",
+	}, []string{"kind", "result"})
+
+	// RefundsTotal 退款次數 , 依原因 (withdrawal / course_cancelled) 與結果 (requested / succeeded / retry / failed) 分類
+	RefundsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "payment",
+		Name:      "refunds_total",
+		Help:      "Number of refunds by reason and result.",
+	}, []string{"reason", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		RepoQueryDuration,
+		EnrollmentsTotal,
+		CourseStatusTransitionsTotal,
+		TeacherApprovalsTotal,
+		OutboxEventsTotal,
+		WebhookDeliveriesTotal,
+		NotificationsTotal,
+		PaymentCallbacksTotal,
+		PromotionRedemptionsTotal,
+		RefundsTotal,
+	)
+}
+
+// Handler 回傳 /metrics 使用的 http.Handler
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDBStats 註冊 sql.DBStats 的 collector , 已註冊相同 dbName 的 collector 時取代之
+// 參數: db - 連線池 , dbName - 資料庫名稱 , 作為 db_name label
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	collector := collectors.NewDBStatsCollector(db, dbName)
+	Registry.Unregister(collector)
+	return Registry.Register(collector)
+}
+
+// UnregisterDBStats 移除 RegisterDBStats 註冊的 collector , 關閉連線池時呼叫
+// 參數: dbName - 資料庫名稱
+func UnregisterDBStats(dbName string) {
+	// Unregister 只比對 collector 的 Desc , 不會讀取連線池
+	Registry.Unregister(collectors.NewDBStatsCollector(nil, dbName))
+}
+
+// ObserveRepoQuery 記錄 repository 方法耗時 , 搭配 defer 使用
+//
+// Example:
+//
+//	defer metrics.ObserveRepoQuery("teacher", "GetByID", time.Now())
+func ObserveRepoQuery(repository, method string, begin time.Time) {
+	RepoQueryDuration.WithLabelValues(repository, method).Observe(time.Since(begin).Seconds())
+}
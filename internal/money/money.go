@@ -0,0 +1,128 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedCurrency 不支援的幣別
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrCurrencyMismatch 不同幣別的金額不可運算或比較
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// exponents 支援的幣別 (ISO 4217) 與最小單位的小數位數 , 例如 USD 的最小單位為 cent , 小數位數為 2
+var exponents = map[string]int{
+	"TWD": 2,
+	"USD": 2,
+	"EUR": 2,
+	"HKD": 2,
+	"CNY": 2,
+	"JPY": 0,
+}
+
+// Money 金額 , Amount 以幣別的最小單位計 , 避免浮點數誤差
+//
+// Example:
+//
+//	price, err := money.New(150000, "TWD") // TWD 1,500.00
+//	total, err := price.Add(fee)
+type Money struct {
+	Amount   int64  `json:"amount"`   // 金額 , 以幣別的最小單位計
+	Currency string `json:"currency"` // 幣別 , ISO 4217
+}
+
+// New 建立金額
+// 參數: amount - 以幣別的最小單位計的金額, currency - 幣別 , ISO 4217
+// 回傳: 金額, 錯誤訊息 , 不支援的幣別回傳 ErrUnsupportedCurrency
+func New(amount int64, currency string) (Money, error) {
+	if !IsSupported(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// IsSupported 是否為支援的幣別 , 幣別代碼須為大寫
+func IsSupported(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent 幣別最小單位的小數位數 , 不支援的幣別回傳 0
+func Exponent(currency string) int {
+	return exponents[currency]
+}
+
+// IsZero 金額是否為 0
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative 金額是否小於 0
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add 相加 , 幣別不同時回傳 ErrCurrencyMismatch
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub 相減 , 幣別不同時回傳 ErrCurrencyMismatch
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp 比較金額 , m 小於、等於、大於 other 時分別回傳 -1 , 0 , 1 , 幣別不同時回傳 ErrCurrencyMismatch
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w: %s <> %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// String 以幣別代碼與千分位格式化金額 , 例如 TWD 1,500.00 , JPY 3,000
+func (m Money) String() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	exp := Exponent(m.Currency)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-exp], digits[len(digits)-exp:]
+
+	var b strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if exp > 0 {
+		b.WriteByte('.')
+		b.WriteString(fraction)
+	}
+	return m.Currency + " " + sign + b.String()
+}
@@ -0,0 +1,62 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	m, err := New(150000, "TWD")
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 150000, Currency: "TWD"}, m)
+
+	_, err = New(100, "twd")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	_, err = New(100, "")
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := Money{Amount: 1000, Currency: "USD"}
+	b := Money{Amount: 250, Currency: "USD"}
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1250, sum.Amount)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.EqualValues(t, -750, diff.Amount)
+	assert.True(t, diff.IsNegative())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = a.Add(Money{Amount: 1, Currency: "TWD"})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = a.Cmp(Money{Amount: 1, Currency: "TWD"})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: Money{Amount: 150000, Currency: "TWD"}, want: "TWD 1,500.00"},
+		{money: Money{Amount: 123456789, Currency: "USD"}, want: "USD 1,234,567.89"},
+		{money: Money{Amount: 5, Currency: "USD"}, want: "USD 0.05"},
+		{money: Money{Amount: -1999, Currency: "EUR"}, want: "EUR -19.99"},
+		{money: Money{Amount: 3000, Currency: "JPY"}, want: "JPY 3,000"},
+		{money: Money{Amount: 0, Currency: "TWD"}, want: "TWD 0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.String())
+		})
+	}
+}
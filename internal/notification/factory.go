@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// 發送管道的 driver
+const (
+	DriverSMTP = "smtp" // email 以 SMTP 發送
+	DriverHTTP = "http" // 簡訊以服務商的 HTTP API 發送
+	DriverLog  = "log"  // 寫入 stdout , 不實際發送
+	DriverFile = "file" // 寫入 NOTIFICATION_FILE , 不實際發送
+)
+
+// NotifiersFromViper 依 NOTIFICATION_EMAIL_DRIVER / NOTIFICATION_SMS_DRIVER 建立各管道的 notifier
+// driver 空白表示不啟用該管道 , 該管道的通知會被標記為失敗
+// 回傳: notifiers, 關閉開啟的檔案的函式, 錯誤訊息
+func NotifiersFromViper() ([]Notifier, func() error, error) {
+	viper.SetDefault("NOTIFICATION_EMAIL_DRIVER", DriverLog)
+	viper.SetDefault("NOTIFICATION_SMS_DRIVER", DriverLog)
+	viper.SetDefault("NOTIFICATION_FILE", "notifications.log")
+	viper.SetDefault("SMS_TIMEOUT", 10*time.Second)
+
+	var (
+		notifiers []Notifier
+		file      *os.File
+	)
+	closeFile := func() error {
+		if file == nil {
+			return nil
+		}
+		return file.Close()
+	}
+	openFile := func() (io.Writer, error) {
+		if file != nil {
+			return file, nil
+		}
+		f, err := os.OpenFile(viper.GetString("NOTIFICATION_FILE"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open notification file: %w", err)
+		}
+		file = f
+		return file, nil
+	}
+
+	for _, channel := range entity.Channels() {
+		driver := viper.GetString("NOTIFICATION_" + strings.ToUpper(string(channel)) + "_DRIVER")
+
+		var notifier Notifier
+		switch {
+		case driver == "":
+			continue
+		case driver == DriverLog:
+			notifier = NewWriterNotifier(channel, os.Stdout)
+		case driver == DriverFile:
+			w, err := openFile()
+			if err != nil {
+				return nil, nil, errors.Join(err, closeFile())
+			}
+			notifier = NewWriterNotifier(channel, w)
+		case driver == DriverSMTP && channel == entity.ChannelEmail:
+			notifier = NewSMTPNotifier(SMTPConfigFromViper())
+		case driver == DriverHTTP && channel == entity.ChannelSMS:
+			notifier = NewSMSNotifier(SMSConfigFromViper(), &http.Client{Timeout: viper.GetDuration("SMS_TIMEOUT")})
+		default:
+			return nil, nil, errors.Join(fmt.Errorf("unsupported %s notification driver %q", channel, driver), closeFile())
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, closeFile, nil
+}
@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// Message 發送給 Notifier 的通知內容
+type Message struct {
+	To      string // 收件地址 , email 或電話
+	Subject string // 主旨 , 簡訊沒有主旨
+	Body    string // 內容
+}
+
+// Notifier 通知的發送管道
+// Send 回傳錯誤時 Sender 會於稍後重新發送 , 因此 Send 須可重複執行
+type Notifier interface {
+	// Channel 發送管道
+	Channel() entity.Channel
+	// Send 發送通知
+	Send(ctx context.Context, msg Message) error
+}
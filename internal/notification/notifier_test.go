@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+func TestBuildMail(t *testing.T) {
+	from := &mail.Address{Name: "課程管理系統", Address: "noreply@example.com"}
+	to := &mail.Address{Address: "amy@example.com"}
+	body := strings.Repeat("您好，這是一封測試信。", 10)
+
+	raw := buildMail(from, to, Message{To: to.Address, Subject: "測試主旨", Body: body}, time.Now())
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "測試主旨", subject)
+	assert.Equal(t, "<amy@example.com>", msg.Header.Get("To"))
+	assert.Equal(t, "text/plain; charset=UTF-8", msg.Header.Get("Content-Type"))
+
+	var encoded bytes.Buffer
+	_, err = encoded.ReadFrom(msg.Body)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(encoded.String()), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded.String(), "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestSMSNotifier_Send(t *testing.T) {
+	var got smsRequest
+	var auth string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	n := NewSMSNotifier(SMSConfig{URL: server.URL, APIKey: "key", Sender: "CMS"}, server.Client())
+	assert.Equal(t, entity.ChannelSMS, n.Channel())
+
+	require.NoError(t, n.Send(context.Background(), Message{To: "0912345678", Body: "hello"}))
+	assert.Equal(t, "Bearer key", auth)
+	assert.Equal(t, smsRequest{From: "CMS", To: "0912345678", Text: "hello"}, got)
+
+	status = http.StatusTooManyRequests
+	assert.ErrorContains(t, n.Send(context.Background(), Message{To: "0912345678", Body: "hello"}), "429")
+}
+
+func TestWriterNotifier_Send(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewWriterNotifier(entity.ChannelEmail, &buf)
+
+	require.NoError(t, n.Send(context.Background(), Message{To: "a@example.com", Subject: "s1", Body: "b1"}))
+	require.NoError(t, n.Send(context.Background(), Message{To: "b@example.com", Subject: "s2", Body: "b2"}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var written WrittenMessage
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &written))
+	assert.Equal(t, entity.ChannelEmail, written.Channel)
+	assert.Equal(t, "b@example.com", written.To)
+	assert.Equal(t, "s2", written.Subject)
+	assert.Equal(t, "b2", written.Body)
+}
@@ -0,0 +1,155 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/job"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	notificationRepository "github.com/itmrchow/course-management-system/internal/repository/notification"
+)
+
+// Config sender 設定
+type Config struct {
+	BatchSize      int           // 每次取得的通知數量
+	MaxAttempts    uint          // 發送次數上限 , 超過後標記為失敗
+	InitialBackoff time.Duration // 第一次重試等待時間 , 之後每次加倍
+	MaxBackoff     time.Duration // 重試等待時間上限
+	SendTimeout    time.Duration // 單次發送的時間上限
+}
+
+// ConfigFromViper 從 viper 讀取 sender 設定
+func ConfigFromViper() Config {
+	viper.SetDefault("NOTIFICATION_BATCH_SIZE", 50)
+	viper.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 6)
+	viper.SetDefault("NOTIFICATION_INITIAL_BACKOFF", time.Minute)
+	viper.SetDefault("NOTIFICATION_MAX_BACKOFF", time.Hour)
+	viper.SetDefault("NOTIFICATION_SEND_TIMEOUT", 30*time.Second)
+
+	return Config{
+		BatchSize:      viper.GetInt("NOTIFICATION_BATCH_SIZE"),
+		MaxAttempts:    viper.GetUint("NOTIFICATION_MAX_ATTEMPTS"),
+		InitialBackoff: viper.GetDuration("NOTIFICATION_INITIAL_BACKOFF"),
+		MaxBackoff:     viper.GetDuration("NOTIFICATION_MAX_BACKOFF"),
+		SendTimeout:    viper.GetDuration("NOTIFICATION_SEND_TIMEOUT"),
+	}
+}
+
+// Sender 發送通知佇列中待發送的通知
+// 每一批通知在同一個 transaction 中取得並鎖定 , 多個 sender 同時執行不會重複發送同一則通知
+// 發送失敗時依指數退避重試 , 超過 MaxAttempts 或沒有對應管道的 Notifier 時標記為失敗
+//
+// Example:
+//
+//	sender := notification.NewSender(transactor, notificationRepo, notification.ConfigFromViper(), emailNotifier, smsNotifier)
+//	runner.Every("notification_sender", 10*time.Second, sender.RunOnce)
+type Sender struct {
+	transactor repo.Transactor
+	repo       notificationRepository.NotificationRepository
+	notifiers  map[entity.Channel]Notifier
+	cfg        Config
+	now        func() time.Time
+}
+
+// NewSender 建立 sender
+// 參數: transactor - transaction, repo - notification repository, cfg - 設定, notifiers - 各管道的 notifier
+func NewSender(transactor repo.Transactor, repo notificationRepository.NotificationRepository, cfg Config, notifiers ...Notifier) *Sender {
+	s := &Sender{
+		transactor: transactor,
+		repo:       repo,
+		notifiers:  make(map[entity.Channel]Notifier, len(notifiers)),
+		cfg:        cfg,
+		now:        time.Now,
+	}
+	for _, n := range notifiers {
+		s.notifiers[n.Channel()] = n
+	}
+	return s
+}
+
+// RunOnce 發送所有可發送的通知 , 直到沒有可發送的通知或 ctx 結束 , 可作為 job.Func
+// 個別通知發送失敗不回傳錯誤 , 只記錄於通知 , 回傳的錯誤為存取資料庫失敗
+func (s *Sender) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		count, err := s.runBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if count < s.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// runBatch 於 transaction 中取得並發送一批通知 , 回傳: 取得的通知數量
+func (s *Sender) runBatch(ctx context.Context) (int, error) {
+	var count int
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		notifications, err := s.repo.ListPending(ctx, s.now(), s.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list pending notifications: %w", err)
+		}
+		count = len(notifications)
+
+		for _, n := range notifications {
+			if err := s.send(ctx, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// send 發送單一通知並記錄結果
+func (s *Sender) send(ctx context.Context, n *entity.Notification) error {
+	logger := zerolog.Ctx(ctx).With().
+		Uint("notification_id", n.ID).
+		Str("channel", string(n.Channel)).
+		Str("template", n.Template).
+		Uint("attempts", n.Attempts+1).
+		Logger()
+
+	attempt := &entity.NotificationAttempt{At: s.now()}
+	notifier, ok := s.notifiers[n.Channel]
+	if !ok {
+		attempt.Error = fmt.Sprintf("no notifier for channel %s", n.Channel)
+	} else {
+		sendCtx, cancel := context.WithTimeout(ctx, s.cfg.SendTimeout)
+		err := notifier.Send(sendCtx, Message{To: n.Address, Subject: n.Subject, Body: n.Body})
+		cancel()
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	}
+
+	switch {
+	case attempt.Error == "":
+		attempt.Status = entity.NotificationStatusSent
+		metrics.NotificationsTotal.WithLabelValues(string(n.Channel), "sent").Inc()
+		logger.Debug().Msg("notification sent")
+
+	case !ok || n.Attempts+1 >= s.cfg.MaxAttempts:
+		attempt.Status = entity.NotificationStatusFailed
+		metrics.NotificationsTotal.WithLabelValues(string(n.Channel), "failed").Inc()
+		logger.Error().Str("error", attempt.Error).Msg("notification failed")
+
+	default:
+		attempt.Status = entity.NotificationStatusPending
+		attempt.NextAttemptAt = attempt.At.Add(job.Backoff(s.cfg.InitialBackoff, s.cfg.MaxBackoff, n.Attempts))
+		metrics.NotificationsTotal.WithLabelValues(string(n.Channel), "retry").Inc()
+		logger.Warn().Str("error", attempt.Error).Time("next_attempt_at", attempt.NextAttemptAt).Msg("failed to send notification")
+	}
+
+	if err := s.repo.RecordAttempt(ctx, n.ID, attempt); err != nil {
+		return fmt.Errorf("failed to record notification %d attempt: %w", n.ID, err)
+	}
+	return nil
+}
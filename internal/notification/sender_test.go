@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	notificationRepository "github.com/itmrchow/course-management-system/internal/repository/notification"
+)
+
+// fakeNotifier 記錄發送內容 , err 不為 nil 時發送失敗
+type fakeNotifier struct {
+	mu      sync.Mutex
+	channel entity.Channel
+	err     error
+	sent    []Message
+}
+
+func (n *fakeNotifier) Channel() entity.Channel { return n.channel }
+
+func (n *fakeNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+type SenderTestSuite struct {
+	suite.Suite
+	repo   notificationRepository.NotificationRepository
+	email  *fakeNotifier
+	sender *Sender
+	now    time.Time
+}
+
+func (s *SenderTestSuite) SetupTest() {
+	s.repo = notificationRepository.NewNotificationMemoryRepository()
+	s.now = time.Now().Truncate(time.Second)
+	s.email = &fakeNotifier{channel: entity.ChannelEmail}
+	s.sender = NewSender(repository.NoTransaction, s.repo, Config{
+		BatchSize:      2,
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     90 * time.Second,
+		SendTimeout:    time.Second,
+	}, s.email)
+	s.sender.now = func() time.Time { return s.now }
+}
+
+func TestSender(t *testing.T) {
+	suite.Run(t, new(SenderTestSuite))
+}
+
+// enqueue 新增一則於 s.now 可發送的通知
+func (s *SenderTestSuite) enqueue(key string, channel entity.Channel) {
+	_, err := s.repo.Enqueue(context.Background(), &entity.Notification{
+		Key: key, Channel: channel, UserID: 1, Address: "a@example.com", Template: TemplateTeacherApproved,
+		Locale: entity.LocaleEn, Subject: "subject " + key, Body: "body " + key, NextAttemptAt: s.now,
+	})
+	s.Require().NoError(err)
+}
+
+func (s *SenderTestSuite) Test_RunOnce_SendsAllBatches() {
+	for _, key := range []string{"a", "b", "c"} {
+		s.enqueue(key, entity.ChannelEmail)
+	}
+
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+
+	s.Len(s.email.sent, 3)
+	s.Equal(Message{To: "a@example.com", Subject: "subject a", Body: "body a"}, s.email.sent[0])
+	for id := uint(1); id <= 3; id++ {
+		n, err := s.repo.GetByID(context.Background(), id)
+		s.Require().NoError(err)
+		s.Equal(entity.NotificationStatusSent, n.Status)
+		s.Equal(uint(1), n.Attempts)
+		s.NotNil(n.SentAt)
+	}
+}
+
+func (s *SenderTestSuite) Test_RunOnce_RetryThenFail() {
+	s.enqueue("a", entity.ChannelEmail)
+	s.email.err = errors.New("smtp unavailable")
+
+	// 第一次失敗 , 一分鐘後重試
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+	n, err := s.repo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(entity.NotificationStatusPending, n.Status)
+	s.Equal("smtp unavailable", n.LastError)
+	s.WithinDuration(s.now.Add(time.Minute), n.NextAttemptAt, time.Second)
+
+	// 尚未到重試時間 , 不發送
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+	n, err = s.repo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(uint(1), n.Attempts)
+
+	// 第二次失敗 , 等待時間不超過 MaxBackoff
+	s.now = s.now.Add(time.Minute)
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+	n, err = s.repo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.WithinDuration(s.now.Add(90*time.Second), n.NextAttemptAt, time.Second)
+
+	// 第三次失敗 , 超過次數上限
+	s.now = s.now.Add(90 * time.Second)
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+	n, err = s.repo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(entity.NotificationStatusFailed, n.Status)
+	s.Equal(uint(3), n.Attempts)
+}
+
+func (s *SenderTestSuite) Test_RunOnce_NoNotifier() {
+	s.enqueue("a", entity.ChannelSMS)
+
+	s.Require().NoError(s.sender.RunOnce(context.Background()))
+
+	n, err := s.repo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal(entity.NotificationStatusFailed, n.Status)
+	s.Contains(n.LastError, "no notifier")
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// SMSConfig 簡訊服務商設定
+type SMSConfig struct {
+	URL    string // 服務商的發送 API
+	APIKey string // API key , 以 Authorization: Bearer 傳送
+	Sender string // 發送者名稱或號碼
+}
+
+// SMSConfigFromViper 從 viper 讀取簡訊服務商設定
+func SMSConfigFromViper() SMSConfig {
+	return SMSConfig{
+		URL:    viper.GetString("SMS_API_URL"),
+		APIKey: viper.GetString("SMS_API_KEY"),
+		Sender: viper.GetString("SMS_SENDER"),
+	}
+}
+
+// smsRequest 發送簡訊的請求內容
+type smsRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+// SMSNotifier 以 HTTP API 發送簡訊
+// 請求為 JSON {"from","to","text"} , 不同服務商的格式差異由 gateway 或 proxy 轉換
+type SMSNotifier struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+var _ Notifier = (*SMSNotifier)(nil)
+
+// NewSMSNotifier 建立簡訊 notifier
+// 參數: cfg - 簡訊服務商設定, client - HTTP client , 須設定 Timeout
+func NewSMSNotifier(cfg SMSConfig, client *http.Client) *SMSNotifier {
+	return &SMSNotifier{cfg: cfg, client: client}
+}
+
+// Channel 實作 Notifier
+func (n *SMSNotifier) Channel() entity.Channel {
+	return entity.ChannelSMS
+}
+
+// Send 實作 Notifier , 回應 2xx 視為成功
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(smsRequest{From: n.cfg.Sender, To: msg.To, Text: msg.Body})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sms: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.cfg.APIKey)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post sms: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms provider responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
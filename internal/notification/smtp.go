@@ -0,0 +1,140 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// SMTPConfig SMTP 設定
+type SMTPConfig struct {
+	Host     string // SMTP server
+	Port     int    // SMTP port
+	Username string // 帳號 , 空白表示不驗證
+	Password string // 密碼
+	From     string // 寄件人 , 例如 "課程管理系統 <noreply@example.com>"
+}
+
+// SMTPConfigFromViper 從 viper 讀取 SMTP 設定
+func SMTPConfigFromViper() SMTPConfig {
+	viper.SetDefault("SMTP_PORT", 587)
+
+	return SMTPConfig{
+		Host:     viper.GetString("SMTP_HOST"),
+		Port:     viper.GetInt("SMTP_PORT"),
+		Username: viper.GetString("SMTP_USERNAME"),
+		Password: viper.GetString("SMTP_PASSWORD"),
+		From:     viper.GetString("SMTP_FROM"),
+	}
+}
+
+// SMTPNotifier 以 SMTP 發送 email
+// server 支援 STARTTLS 時會先升級為 TLS 再驗證
+type SMTPNotifier struct {
+	cfg SMTPConfig
+	now func() time.Time
+}
+
+var _ Notifier = (*SMTPNotifier)(nil)
+
+// NewSMTPNotifier 建立 SMTP notifier
+// 參數: cfg - SMTP 設定
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg, now: time.Now}
+}
+
+// Channel 實作 Notifier
+func (n *SMTPNotifier) Channel() entity.Channel {
+	return entity.ChannelEmail
+}
+
+// Send 實作 Notifier
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address %q: %w", n.cfg.From, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid to address %q: %w", msg.To, err)
+	}
+
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to dial smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("failed to set recipient: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start data: %w", err)
+	}
+	if _, err := w.Write(buildMail(from, to, msg, n.now())); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMail 建立 RFC 5322 格式的郵件 , 主旨以 RFC 2047 編碼 , 內容為 base64 編碼的 UTF-8 純文字
+func buildMail(from, to *mail.Address, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
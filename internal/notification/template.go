@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// 樣板名稱
+// 樣板檔案位於 templates/<locale>/<name>.<channel>.tmpl
+// email 樣板須定義 subject 與 body , 簡訊樣板只需定義 body
+const (
+	TemplateTeacherApproved = "teacher_approved" // 教師審核通過 , 資料: Name, ApprovedAt
+	TemplateTeacherRejected = "teacher_rejected" // 教師審核不通過 , 資料: Name, RejectedAt
+	TemplateCourseReminder  = "course_reminder"  // 上課提醒 , 資料: Name, CourseName, StartAt, EndAt, JoinURL
+	TemplateCourseCancelled = "course_cancelled" // 課程取消 , 資料: Name, CourseName, Reason
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// datetimeLayouts 各語系的日期時間格式
+var datetimeLayouts = map[string]string{
+	entity.LocaleZhTW: "2006/01/02 15:04",
+	entity.LocaleEn:   "Jan 2, 2006 3:04 PM",
+}
+
+// Rendered 套用樣板後的內容
+type Rendered struct {
+	Subject string
+	Body    string
+}
+
+// Renderer 依語系與管道套用通知樣板
+// 樣板使用 text/template , 資料缺少欄位時回傳錯誤 , 時間以 datetime 函式依語系格式化
+type Renderer struct {
+	templates map[string]*template.Template // key: locale/name.channel
+}
+
+// NewRenderer 載入內建的樣板
+// 參數: loc - datetime 函式使用的時區
+// 回傳: renderer, 錯誤訊息
+func NewRenderer(loc *time.Location) (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]*template.Template)}
+
+	err := fs.WalkDir(templateFS, "templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		locale := path.Base(path.Dir(p))
+		layout, ok := datetimeLayouts[locale]
+		if !ok {
+			return fmt.Errorf("unsupported template locale %q", locale)
+		}
+
+		b, err := templateFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(path.Base(p)).
+			Option("missingkey=error").
+			Funcs(template.FuncMap{
+				"datetime": func(t time.Time) string { return t.In(loc).Format(layout) },
+			}).
+			Parse(string(b))
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", p, err)
+		}
+
+		r.templates[locale+"/"+strings.TrimSuffix(path.Base(p), ".tmpl")] = tmpl
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Has 是否有樣板於管道的內容 , 以預設語系判斷
+func (r *Renderer) Has(name string, channel entity.Channel) bool {
+	_, ok := r.templates[entity.DefaultLocale+"/"+name+"."+string(channel)]
+	return ok
+}
+
+// Render 套用樣板 , 語系沒有對應的樣板時使用預設語系
+// 參數: name - 樣板名稱, locale - 語系, channel - 發送管道, data - 樣板資料
+// 回傳: 內容, 實際使用的語系, 錯誤訊息
+func (r *Renderer) Render(name, locale string, channel entity.Channel, data map[string]any) (*Rendered, string, error) {
+	tmpl, ok := r.templates[locale+"/"+name+"."+string(channel)]
+	if !ok {
+		locale = entity.DefaultLocale
+		if tmpl, ok = r.templates[locale+"/"+name+"."+string(channel)]; !ok {
+			return nil, "", fmt.Errorf("template %s for channel %s not found", name, channel)
+		}
+	}
+
+	var rendered Rendered
+	if tmpl.Lookup("subject") != nil {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+			return nil, "", fmt.Errorf("failed to render %s subject: %w", name, err)
+		}
+		rendered.Subject = strings.TrimSpace(buf.String())
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return nil, "", fmt.Errorf("failed to render %s body: %w", name, err)
+	}
+	rendered.Body = strings.TrimSpace(buf.String())
+
+	return &rendered, locale, nil
+}
@@ -0,0 +1,136 @@
+package notification
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// templateData 各樣板的測試資料
+func templateData() map[string]map[string]any {
+	at := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	return map[string]map[string]any{
+		TemplateTeacherApproved: {"Name": "王小明", "ApprovedAt": at},
+		TemplateTeacherRejected: {"Name": "王小明", "RejectedAt": at},
+		TemplateCourseReminder:  {"Name": "王小明", "CourseName": "Go 入門", "StartAt": at, "EndAt": at.Add(2 * time.Hour), "JoinURL": ""},
+		TemplateCourseCancelled: {"Name": "王小明", "CourseName": "Go 入門", "Reason": "講師請假"},
+	}
+}
+
+func TestRenderer_AllTemplates(t *testing.T) {
+	r, err := NewRenderer(time.UTC)
+	require.NoError(t, err)
+
+	for name, data := range templateData() {
+		for _, locale := range entity.Locales() {
+			for _, channel := range entity.Channels() {
+				t.Run(locale+"/"+name+"."+string(channel), func(t *testing.T) {
+					rendered, usedLocale, err := r.Render(name, locale, channel, data)
+					require.NoError(t, err)
+					assert.Equal(t, locale, usedLocale)
+					assert.NotEmpty(t, rendered.Body)
+					assert.NotContains(t, rendered.Body, "<no value>")
+					if channel == entity.ChannelEmail {
+						assert.NotEmpty(t, rendered.Subject)
+					} else {
+						assert.Empty(t, rendered.Subject)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	r, err := NewRenderer(time.FixedZone("Asia/Taipei", 8*60*60))
+	require.NoError(t, err)
+	data := templateData()
+
+	type args struct {
+		name    string
+		locale  string
+		channel entity.Channel
+		data    map[string]any
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, rendered *Rendered, locale string, err error)
+	}{
+		{
+			name: "zh-TW datetime in configured location",
+			args: args{TemplateCourseReminder, entity.LocaleZhTW, entity.ChannelSMS, data[TemplateCourseReminder]},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				require.NoError(t, err)
+				assert.Contains(t, rendered.Body, "2025/03/01 17:30")
+				assert.Contains(t, rendered.Body, "Go 入門")
+			},
+		},
+		{
+			name: "en datetime",
+			args: args{TemplateCourseReminder, entity.LocaleEn, entity.ChannelEmail, data[TemplateCourseReminder]},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "[Course Management] Reminder: Go 入門", rendered.Subject)
+				assert.Contains(t, rendered.Body, "Mar 1, 2025 5:30 PM")
+			},
+		},
+		{
+			name: "join url of online course",
+			args: args{TemplateCourseReminder, entity.LocaleZhTW, entity.ChannelEmail, withJoinURL(data[TemplateCourseReminder], "https://zoom.us/j/1")},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				require.NoError(t, err)
+				assert.Contains(t, rendered.Body, "線上課程連結：https://zoom.us/j/1")
+			},
+		},
+		{
+			name: "unsupported locale falls back to default",
+			args: args{TemplateTeacherApproved, "ja", entity.ChannelEmail, data[TemplateTeacherApproved]},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, entity.DefaultLocale, locale)
+				assert.Contains(t, rendered.Subject, "審核通過")
+			},
+		},
+		{
+			name: "empty reason omitted",
+			args: args{TemplateCourseCancelled, entity.LocaleEn, entity.ChannelEmail, map[string]any{"Name": "Amy", "CourseName": "Go", "Reason": ""}},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				require.NoError(t, err)
+				assert.NotContains(t, rendered.Body, "Reason:")
+			},
+		},
+		{
+			name: "missing data",
+			args: args{TemplateTeacherApproved, entity.LocaleEn, entity.ChannelEmail, map[string]any{"Name": "Amy"}},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "unknown template",
+			args: args{"unknown", entity.LocaleEn, entity.ChannelEmail, nil},
+			assertFunc: func(t *testing.T, rendered *Rendered, locale string, err error) {
+				assert.ErrorContains(t, err, "not found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, locale, err := r.Render(tt.args.name, tt.args.locale, tt.args.channel, tt.args.data)
+			tt.assertFunc(t, rendered, locale, err)
+		})
+	}
+}
+
+// withJoinURL 複製上課提醒的測試資料並設定會議網址
+func withJoinURL(data map[string]any, joinURL string) map[string]any {
+	copied := maps.Clone(data)
+	copied["JoinURL"] = joinURL
+	return copied
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+)
+
+// WrittenMessage WriterNotifier 寫出的內容 , 每則通知一行 JSON
+type WrittenMessage struct {
+	Channel entity.Channel `json:"channel"`
+	To      string         `json:"to"`
+	Subject string         `json:"subject,omitempty"`
+	Body    string         `json:"body"`
+	SentAt  time.Time      `json:"sent_at"`
+}
+
+// WriterNotifier 將通知寫入 io.Writer (檔案或 stdout) , 不會實際發送
+// 作為本機開發與測試時 SMTP / 簡訊服務商的替代
+//
+// Example:
+//
+//	f, err := os.OpenFile("notifications.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+//	notifier := notification.NewWriterNotifier(entity.ChannelEmail, f)
+type WriterNotifier struct {
+	mu      sync.Mutex
+	channel entity.Channel
+	w       io.Writer
+	now     func() time.Time
+}
+
+var _ Notifier = (*WriterNotifier)(nil)
+
+// NewWriterNotifier 建立寫入 io.Writer 的 notifier
+// 參數: channel - 發送管道, w - 寫入目的地
+func NewWriterNotifier(channel entity.Channel, w io.Writer) *WriterNotifier {
+	return &WriterNotifier{channel: channel, w: w, now: time.Now}
+}
+
+// Channel 實作 Notifier
+func (n *WriterNotifier) Channel() entity.Channel {
+	return n.channel
+}
+
+// Send 實作 Notifier
+func (n *WriterNotifier) Send(ctx context.Context, msg Message) error {
+	b, err := json.Marshal(WrittenMessage{
+		Channel: n.channel,
+		To:      msg.To,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+		SentAt:  n.now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, err := n.w.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
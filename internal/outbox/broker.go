@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// NATSPublisher NATS 連線需要的方法 , *nats.Conn 即符合此介面
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// KafkaProducer Kafka producer 需要的方法
+// 以 kafka client (例如 segmentio/kafka-go 的 Writer) 包裝後傳入 , 避免 outbox 相依特定 client
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte, headers map[string]string) error
+}
+
+// NATSSink 將事件發送至 NATS , subject 為 prefix.事件類型 , 例如 cms.course.published
+type NATSSink struct {
+	conn   NATSPublisher
+	prefix string
+}
+
+var _ Sink = (*NATSSink)(nil)
+
+// NewNATSSink 建立 NATS sink
+// 參數: conn - NATS 連線, prefix - subject 前綴
+func NewNATSSink(conn NATSPublisher, prefix string) *NATSSink {
+	return &NATSSink{conn: conn, prefix: prefix}
+}
+
+// Name 實作 Sink
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+// Publish 實作 Sink
+func (s *NATSSink) Publish(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return s.conn.Publish(s.prefix+"."+msg.Type, data)
+}
+
+// KafkaSink 將事件發送至 Kafka topic
+// key 為 aggregate_type:aggregate_id , 同一個 entity 的事件會進入同一個 partition , 維持順序
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+var _ Sink = (*KafkaSink)(nil)
+
+// NewKafkaSink 建立 Kafka sink
+// 參數: producer - Kafka producer, topic - 事件 topic
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+// Name 實作 Sink
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+// Publish 實作 Sink
+func (s *KafkaSink) Publish(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	key := fmt.Sprintf("%s:%d", msg.AggregateType, msg.AggregateID)
+	headers := map[string]string{HeaderEventID: msg.ID, HeaderEventType: msg.Type}
+	return s.producer.Produce(ctx, s.topic, []byte(key), data, headers)
+}
+
+// BrokerMessage MemoryBroker 收到的訊息
+type BrokerMessage struct {
+	Topic   string // NATS subject 或 Kafka topic
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// MemoryBroker 記憶體中的 message broker , 同時實作 NATSPublisher 與 KafkaProducer
+// 作為本機開發與測試時 NATS / Kafka 的替代
+type MemoryBroker struct {
+	mu       sync.Mutex
+	messages []BrokerMessage
+	err      error
+}
+
+var (
+	_ NATSPublisher = (*MemoryBroker)(nil)
+	_ KafkaProducer = (*MemoryBroker)(nil)
+)
+
+// NewMemoryBroker 建立記憶體 message broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish 實作 NATSPublisher
+func (b *MemoryBroker) Publish(subject string, data []byte) error {
+	return b.add(BrokerMessage{Topic: subject, Value: data})
+}
+
+// Produce 實作 KafkaProducer
+func (b *MemoryBroker) Produce(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	return b.add(BrokerMessage{Topic: topic, Key: key, Value: value, Headers: headers})
+}
+
+// Fail 設定之後的發送回傳 err , nil 表示恢復正常 , 用於模擬 broker 無法連線
+func (b *MemoryBroker) Fail(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.err = err
+}
+
+// Messages 回傳已收到的訊息
+func (b *MemoryBroker) Messages() []BrokerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]BrokerMessage{}, b.messages...)
+}
+
+func (b *MemoryBroker) add(msg BrokerMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+	b.messages = append(b.messages, msg)
+	return nil
+}
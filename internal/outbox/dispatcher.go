@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AllEvents 訂閱所有事件類型
+const AllEvents = "*"
+
+// Handler 處理事件 , 回傳錯誤時事件會被重新發送
+type Handler func(ctx context.Context, msg Message) error
+
+// Dispatcher 將事件交給同一個程序內訂閱的 Handler (in-process sink)
+//
+// Example:
+//
+//	dispatcher := outbox.NewDispatcher()
+//	dispatcher.Subscribe(event.TypeTeacherApproved, notifyTeacher)
+//	relay := outbox.NewRelay(transactor, outboxRepo, cfg, dispatcher)
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+var _ Sink = (*Dispatcher)(nil)
+
+// NewDispatcher 建立 in-process sink
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: map[string][]Handler{}}
+}
+
+// Subscribe 訂閱事件
+// 參數: eventType - 事件類型 , AllEvents 表示所有事件, handler - 處理函式
+func (d *Dispatcher) Subscribe(eventType string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Name 實作 Sink
+func (d *Dispatcher) Name() string {
+	return "in_process"
+}
+
+// Publish 實作 Sink , 依序執行訂閱的 Handler
+// 任一 Handler 失敗或 panic 時仍會執行其他 Handler , 重新發送時所有 Handler 都會再執行一次
+// Handler 在 relay 的 transaction 外執行 , 需要多個寫入同時成功時由 Handler 自行使用 transaction
+func (d *Dispatcher) Publish(ctx context.Context, msg Message) error {
+	d.mu.RLock()
+	handlers := append(append([]Handler{}, d.handlers[msg.Type]...), d.handlers[AllEvents]...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for i, handler := range handlers {
+		if err := safeHandle(ctx, handler, msg); err != nil {
+			errs = append(errs, fmt.Errorf("handler %d for %s: %w", i, msg.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// safeHandle 執行 Handler , 將 panic 轉為錯誤
+func safeHandle(ctx context.Context, handler Handler, msg Message) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return handler(ctx, msg)
+}
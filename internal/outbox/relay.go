@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	"github.com/itmrchow/course-management-system/internal/job"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+)
+
+// Config relay 設定
+type Config struct {
+	BatchSize      int           // 每次取得的事件數量
+	MaxAttempts    uint          // 發送次數上限 , 超過後放棄發送
+	InitialBackoff time.Duration // 第一次重試等待時間 , 之後每次加倍
+	MaxBackoff     time.Duration // 重試等待時間上限
+	PublishTimeout time.Duration // 單一事件發送至所有 sink 的時間上限
+}
+
+// ConfigFromViper 從 viper 讀取 relay 設定
+func ConfigFromViper() Config {
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_INITIAL_BACKOFF", 5*time.Second)
+	viper.SetDefault("OUTBOX_MAX_BACKOFF", time.Hour)
+	viper.SetDefault("OUTBOX_PUBLISH_TIMEOUT", 10*time.Second)
+
+	return Config{
+		BatchSize:      viper.GetInt("OUTBOX_BATCH_SIZE"),
+		MaxAttempts:    viper.GetUint("OUTBOX_MAX_ATTEMPTS"),
+		InitialBackoff: viper.GetDuration("OUTBOX_INITIAL_BACKOFF"),
+		MaxBackoff:     viper.GetDuration("OUTBOX_MAX_BACKOFF"),
+		PublishTimeout: viper.GetDuration("OUTBOX_PUBLISH_TIMEOUT"),
+	}
+}
+
+// Relay 將 outbox 中的事件發送至所有 sink (at-least-once)
+// 每一批事件在 transaction 中取得並以 Claim 延後下次發送時間 , commit 後在 transaction 外發送 , 再個別記錄結果
+// 發送期間不持有鎖定 , 多個 relay 同時執行不會重複發送同一批事件 , relay 中斷時事件於 claim 到期後重新發送
+// 任一 sink 失敗或 panic 時整個事件稍後重新發送 , 已成功的 sink 會再收到一次 , 接收端須以 Message.ID 去除重複
+// 失敗的事件不會阻擋其他事件 , 因此不同 entity 的事件不保證順序
+//
+// Example:
+//
+//	relay := outbox.NewRelay(transactor, outboxRepo, outbox.ConfigFromViper(), dispatcher)
+//	runner.Every("outbox_relay", time.Second, relay.RunOnce)
+type Relay struct {
+	transactor repo.Transactor
+	repo       outboxRepository.OutboxRepository
+	sinks      []Sink
+	cfg        Config
+	now        func() time.Time
+}
+
+// NewRelay 建立 relay
+// 參數: transactor - transaction, repo - outbox repository, cfg - 設定, sinks - 事件發送目的地
+func NewRelay(transactor repo.Transactor, repo outboxRepository.OutboxRepository, cfg Config, sinks ...Sink) *Relay {
+	return &Relay{
+		transactor: transactor,
+		repo:       repo,
+		sinks:      sinks,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// RunOnce 發送所有可發送的事件 , 直到沒有可發送的事件或 ctx 結束 , 可作為 job.Func
+// 個別事件發送失敗不回傳錯誤 , 只記錄 log 並安排重試 , 回傳的錯誤為存取 outbox 失敗
+func (r *Relay) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		count, err := r.runBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if count < r.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// runBatch 取得並發送一批事件 , 回傳: 取得的事件數量
+func (r *Relay) runBatch(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		if err := r.deliver(ctx, e); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+// claim 於 transaction 中取得一批可發送的事件 , 並延後至整批發送時間上限之後才可再取得
+func (r *Relay) claim(ctx context.Context) ([]*entity.OutboxEvent, error) {
+	now := r.now()
+	var events []*entity.OutboxEvent
+	err := r.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		events, err = r.repo.ListPending(ctx, now, r.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list pending outbox events: %w", err)
+		}
+
+		ids := make([]uint, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		until := now.Add(time.Duration(len(events)) * r.cfg.PublishTimeout)
+		if err := r.repo.Claim(ctx, ids, until); err != nil {
+			return fmt.Errorf("failed to claim outbox events: %w", err)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// deliver 發送單一事件至所有 sink 並記錄結果
+func (r *Relay) deliver(ctx context.Context, e *entity.OutboxEvent) error {
+	logger := zerolog.Ctx(ctx).With().
+		Str("event_id", e.EventID).
+		Str("event_type", e.EventType).
+		Uint("attempts", e.Attempts+1).
+		Logger()
+
+	publishErr := r.publish(ctx, NewMessage(e))
+	now := r.now()
+
+	switch {
+	case publishErr == nil:
+		metrics.OutboxEventsTotal.WithLabelValues("published").Inc()
+		if err := r.repo.MarkPublished(ctx, e.ID, now); err != nil {
+			return fmt.Errorf("failed to mark outbox event %d published: %w", e.ID, err)
+		}
+		logger.Debug().Msg("outbox event published")
+
+	case e.Attempts+1 >= r.cfg.MaxAttempts:
+		metrics.OutboxEventsTotal.WithLabelValues("dead").Inc()
+		if err := r.repo.MarkDead(ctx, e.ID, publishErr.Error(), now); err != nil {
+			return fmt.Errorf("failed to mark outbox event %d dead: %w", e.ID, err)
+		}
+		logger.Error().Err(publishErr).Msg("outbox event dropped after max attempts")
+
+	default:
+		metrics.OutboxEventsTotal.WithLabelValues("failed").Inc()
+		next := now.Add(job.Backoff(r.cfg.InitialBackoff, r.cfg.MaxBackoff, e.Attempts))
+		if err := r.repo.MarkFailed(ctx, e.ID, publishErr.Error(), next); err != nil {
+			return fmt.Errorf("failed to mark outbox event %d failed: %w", e.ID, err)
+		}
+		logger.Warn().Err(publishErr).Time("next_attempt_at", next).Msg("failed to publish outbox event")
+	}
+	return nil
+}
+
+// publish 發送事件至所有 sink , 任一 sink 失敗或 panic 時回傳錯誤
+func (r *Relay) publish(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := safePublish(ctx, sink, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// safePublish 呼叫 sink.Publish , 將 panic 轉為錯誤 , 避免單一事件中斷整批發送
+func safePublish(ctx context.Context, sink Sink, msg Message) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return sink.Publish(ctx, msg)
+}
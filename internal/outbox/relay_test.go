@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+)
+
+type RelayTestSuite struct {
+	suite.Suite
+	repo       outboxRepository.OutboxRepository
+	dispatcher *Dispatcher
+	broker     *MemoryBroker
+	relay      *Relay
+	now        time.Time
+	received   []Message
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 relay 並新增三個事件
+func (s *RelayTestSuite) SetupTest() {
+	s.repo = outboxRepository.NewOutboxMemoryRepository()
+	s.dispatcher = NewDispatcher()
+	s.broker = NewMemoryBroker()
+	s.received = nil
+	s.now = time.Now().Add(time.Second)
+
+	s.dispatcher.Subscribe(AllEvents, func(ctx context.Context, msg Message) error {
+		s.received = append(s.received, msg)
+		return nil
+	})
+
+	s.relay = NewRelay(repository.NoTransaction, s.repo, Config{
+		BatchSize:      2,
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+		PublishTimeout: time.Second,
+	}, s.dispatcher, NewKafkaSink(s.broker, "cms.events"))
+	s.relay.now = func() time.Time { return s.now }
+
+	s.Require().NoError(s.repo.Add(context.Background(),
+		event.TeacherApproved{TeacherID: 1},
+		event.CourseStatusChanged{CourseID: 1, From: "draft", To: "pending"},
+		event.StudentEnrolled{EnrollmentID: 1, CourseID: 1, StudentID: 10},
+	))
+}
+
+// TestRelaySuite 執行測試套件
+func TestRelaySuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
+
+func (s *RelayTestSuite) TestRunOncePublishesAllBatches() {
+	before := testutil.ToFloat64(metrics.OutboxEventsTotal.WithLabelValues("published"))
+
+	s.NoError(s.relay.RunOnce(context.Background()))
+
+	s.Len(s.received, 3)
+	s.Equal(event.TypeTeacherApproved, s.received[0].Type)
+	s.Len(s.broker.Messages(), 3)
+	s.Equal(3.0, testutil.ToFloat64(metrics.OutboxEventsTotal.WithLabelValues("published"))-before)
+
+	pending, err := s.repo.ListPending(context.Background(), s.now.Add(time.Hour), 10)
+	s.NoError(err)
+	s.Empty(pending)
+
+	// 已發送的事件不會重複發送
+	s.NoError(s.relay.RunOnce(context.Background()))
+	s.Len(s.received, 3)
+}
+
+func (s *RelayTestSuite) TestRunOnceRetriesWithBackoff() {
+	s.broker.Fail(errors.New("broker unavailable"))
+
+	s.NoError(s.relay.RunOnce(context.Background()))
+
+	pending, err := s.repo.ListPending(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Empty(pending, "failed events wait for backoff")
+
+	pending, err = s.repo.ListPending(context.Background(), s.now.Add(time.Second), 10)
+	s.NoError(err)
+	s.Require().Len(pending, 3)
+	s.EqualValues(1, pending[0].Attempts)
+	s.Contains(pending[0].LastError, "kafka: broker unavailable")
+
+	// 第二次失敗等待時間加倍
+	s.now = s.now.Add(time.Second)
+	s.NoError(s.relay.RunOnce(context.Background()))
+	pending, err = s.repo.ListPending(context.Background(), s.now.Add(time.Second), 10)
+	s.NoError(err)
+	s.Empty(pending)
+	pending, err = s.repo.ListPending(context.Background(), s.now.Add(2*time.Second), 10)
+	s.NoError(err)
+	s.Len(pending, 3)
+
+	// 恢復後發送成功 , in-process handler 會再收到一次
+	s.broker.Fail(nil)
+	s.now = s.now.Add(2 * time.Second)
+	s.NoError(s.relay.RunOnce(context.Background()))
+	s.Len(s.broker.Messages(), 3)
+	s.Len(s.received, 9)
+}
+
+func (s *RelayTestSuite) TestRunOnceDropsAfterMaxAttempts() {
+	s.broker.Fail(errors.New("broker unavailable"))
+	before := testutil.ToFloat64(metrics.OutboxEventsTotal.WithLabelValues("dead"))
+
+	for range 3 {
+		s.NoError(s.relay.RunOnce(context.Background()))
+		s.now = s.now.Add(time.Hour)
+	}
+
+	pending, err := s.repo.ListPending(context.Background(), s.now.Add(time.Hour), 10)
+	s.NoError(err)
+	s.Empty(pending)
+	s.Equal(3.0, testutil.ToFloat64(metrics.OutboxEventsTotal.WithLabelValues("dead"))-before)
+}
+
+func (s *RelayTestSuite) TestRunOnceRecoversPanic() {
+	s.dispatcher.Subscribe(event.TypeTeacherApproved, func(ctx context.Context, msg Message) error {
+		panic("boom")
+	})
+
+	s.NoError(s.relay.RunOnce(context.Background()))
+
+	// panic 的事件記錄為發送失敗 , 不影響其他事件與其他 handler
+	s.Len(s.received, 3)
+	s.Len(s.broker.Messages(), 3)
+	pending, err := s.repo.ListPending(context.Background(), s.now.Add(time.Second), 10)
+	s.NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(event.TypeTeacherApproved, pending[0].EventType)
+	s.EqualValues(1, pending[0].Attempts)
+	s.Contains(pending[0].LastError, "panic: boom")
+}
+
+func (s *RelayTestSuite) TestRunOnceClaimsBatch() {
+	// 發送期間同一批事件不會被其他 relay 取得
+	var claimed []uint
+	s.dispatcher.Subscribe(event.TypeTeacherApproved, func(ctx context.Context, msg Message) error {
+		pending, err := s.repo.ListPending(ctx, s.now, 10)
+		for _, e := range pending {
+			claimed = append(claimed, e.ID)
+		}
+		return err
+	})
+
+	s.NoError(s.relay.RunOnce(context.Background()))
+	s.Equal([]uint{3}, claimed)
+}
+
+func (s *RelayTestSuite) TestRunOnceCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s.ErrorIs(s.relay.RunOnce(ctx), context.Canceled)
+	s.Empty(s.received)
+}
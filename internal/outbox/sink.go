@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+)
+
+// Message 發送給 Sink 的事件
+// 同一個事件可能被發送多次 (at-least-once) , 接收端須以 ID 去除重複
+type Message struct {
+	ID            string          `json:"id"`             // 事件唯一識別
+	Type          string          `json:"type"`           // 事件類型 , 例如 course.published
+	AggregateType string          `json:"aggregate_type"` // 事件所屬 entity 類型
+	AggregateID   uint            `json:"aggregate_id"`   // 事件所屬 entity ID
+	OccurredAt    time.Time       `json:"occurred_at"`    // 事件發生時間
+	Payload       json.RawMessage `json:"payload"`        // 事件內容 , 對應 domain/event 的事件結構
+}
+
+// NewMessage 將 outbox 事件轉為 Message
+func NewMessage(e *entity.OutboxEvent) Message {
+	return Message{
+		ID:            e.EventID,
+		Type:          e.EventType,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		OccurredAt:    e.CreatedAt.UTC(),
+		Payload:       json.RawMessage(e.Payload),
+	}
+}
+
+// Sink 事件的發送目的地
+// Publish 回傳錯誤時 relay 會於稍後重新發送 , 因此 Publish 須可重複執行
+type Sink interface {
+	// Name 名稱 , 用於 log
+	Name() string
+	// Publish 發送事件
+	Publish(ctx context.Context, msg Message) error
+}
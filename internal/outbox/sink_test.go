@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() Message {
+	return Message{
+		ID:            "5f0c6a3e-3f7e-4c8e-9a43-1f0f2a4c9b10",
+		Type:          "course.published",
+		AggregateType: "course",
+		AggregateID:   1,
+		OccurredAt:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		Payload:       json.RawMessage(`{"course_id":1}`),
+	}
+}
+
+// 依事件類型分派給 Handler 測試
+// Test for Dispatcher.Publish
+func TestDispatcherPublish(t *testing.T) {
+	dispatcher := NewDispatcher()
+
+	var calls []string
+	dispatcher.Subscribe("course.published", func(ctx context.Context, msg Message) error {
+		calls = append(calls, "published")
+		return errors.New("handler failed")
+	})
+	dispatcher.Subscribe("teacher.approved", func(ctx context.Context, msg Message) error {
+		calls = append(calls, "approved")
+		return nil
+	})
+	dispatcher.Subscribe(AllEvents, func(ctx context.Context, msg Message) error {
+		calls = append(calls, "all")
+		return nil
+	})
+
+	err := dispatcher.Publish(context.Background(), testMessage())
+	assert.ErrorContains(t, err, "handler failed")
+	assert.Equal(t, []string{"published", "all"}, calls, "handler failure does not skip others")
+}
+
+// 發送 webhook 測試
+// Test for WebhookSink.Publish
+func TestWebhookSinkPublish(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		assertFunc func(t *testing.T, err error)
+	}{
+		{
+			name:   "success",
+			status: http.StatusAccepted,
+			assertFunc: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "non 2xx",
+			status: http.StatusInternalServerError,
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "status 500")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var received Message
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, testMessage().ID, r.Header.Get(HeaderEventID))
+				assert.Equal(t, "course.published", r.Header.Get(HeaderEventType))
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(body, &received))
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookSink(server.URL, server.Client()).Publish(context.Background(), testMessage())
+			test.assertFunc(t, err)
+			assert.Equal(t, testMessage().ID, received.ID)
+			assert.JSONEq(t, `{"course_id":1}`, string(received.Payload))
+		})
+	}
+}
+
+// 發送至 NATS 與 Kafka 測試
+// Test for NATSSink.Publish, KafkaSink.Publish
+func TestBrokerSinkPublish(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	require.NoError(t, NewNATSSink(broker, "cms").Publish(context.Background(), testMessage()))
+	require.NoError(t, NewKafkaSink(broker, "cms.events").Publish(context.Background(), testMessage()))
+
+	messages := broker.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "cms.course.published", messages[0].Topic)
+	assert.Equal(t, "cms.events", messages[1].Topic)
+	assert.Equal(t, "course:1", string(messages[1].Key))
+	assert.Equal(t, testMessage().ID, messages[1].Headers[HeaderEventID])
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(messages[1].Value, &msg))
+	assert.Equal(t, testMessage().Type, msg.Type)
+
+	broker.Fail(errors.New("connection refused"))
+	assert.Error(t, NewNATSSink(broker, "cms").Publish(context.Background(), testMessage()))
+	assert.Len(t, broker.Messages(), 2)
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// 發送 webhook 時的 header
+const (
+	HeaderEventID   = "X-Event-ID"
+	HeaderEventType = "X-Event-Type"
+)
+
+// WebhookSink 以 HTTP POST 將事件 (Message JSON) 發送至固定 URL
+// 回應 2xx 視為成功 , 其他狀態碼或連線失敗時重新發送
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+var _ Sink = (*WebhookSink)(nil)
+
+// NewWebhookSink 建立 webhook sink
+// 參數: url - 接收事件的 URL, client - HTTP client , 須設定 Timeout
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, client: client}
+}
+
+// Name 實作 Sink
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Publish 實作 Sink
+func (s *WebhookSink) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, msg.ID)
+	req.Header.Set(HeaderEventType, msg.Type)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
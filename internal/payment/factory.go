@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// GatewayFromViper 依 PAYMENT_PROVIDER 建立金流商
+// 回傳: 金流商, 錯誤訊息 , 不支援的金流商回傳錯誤
+func GatewayFromViper() (Gateway, error) {
+	viper.SetDefault("PAYMENT_PROVIDER", FakeProvider)
+	viper.SetDefault("PAYMENT_TIMEOUT", 10*time.Second)
+
+	client := &http.Client{Timeout: viper.GetDuration("PAYMENT_TIMEOUT")}
+	switch provider := viper.GetString("PAYMENT_PROVIDER"); provider {
+	case FakeProvider:
+		return NewFakeGateway(FakeConfigFromViper(), client), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment provider %q", provider)
+	}
+}
@@ -0,0 +1,206 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/webhook"
+)
+
+// FakeProvider 本機模擬金流商的名稱
+const FakeProvider = "fake"
+
+// 模擬金流商通知的 header , 簽章方式同 webhook.Sign
+const (
+	HeaderFakeTimestamp = "X-Fake-Payment-Timestamp"
+	HeaderFakeSignature = "X-Fake-Payment-Signature"
+)
+
+// fakeSignatureTolerance 通知簽章時間的容許差距
+const fakeSignatureTolerance = 5 * time.Minute
+
+// FakeConfig 本機模擬金流商設定
+type FakeConfig struct {
+	BaseURL string // 本服務的網址 , 用於產生付款頁面與 callback 網址
+	Secret  string // 通知簽章金鑰
+}
+
+// FakeConfigFromViper 從 viper 讀取本機模擬金流商設定
+func FakeConfigFromViper() FakeConfig {
+	viper.SetDefault("PAYMENT_FAKE_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("PAYMENT_FAKE_SECRET", "whsec_fake")
+
+	return FakeConfig{
+		BaseURL: viper.GetString("PAYMENT_FAKE_BASE_URL"),
+		Secret:  viper.GetString("PAYMENT_FAKE_SECRET"),
+	}
+}
+
+// FakeGateway 本機模擬金流商 , 供開發與測試使用
+// 付款頁面為 POST {BaseURL}/fake-payments/{id}?result=paid|failed , 呼叫後以簽章的通知 POST 至 {BaseURL}/payments/fake/callback
+// 建立的付款只存在記憶體 , 重新啟動後無法再付款 , 退款直接成功
+//
+// Example:
+//
+//	gateway := payment.NewFakeGateway(payment.FakeConfigFromViper(), &http.Client{Timeout: 10 * time.Second})
+//	srv.Handle("POST /fake-payments/{id}", gateway.Checkout())
+type FakeGateway struct {
+	cfg      FakeConfig
+	client   *http.Client
+	mu       sync.Mutex
+	payments map[string]Request
+	refunds  map[uint]string // 退款紀錄ID 對應的退款ID
+	now      func() time.Time
+}
+
+var _ Gateway = (*FakeGateway)(nil)
+
+// NewFakeGateway 建立本機模擬金流商
+// 參數: cfg - 設定, client - 發送通知使用的 http client
+func NewFakeGateway(cfg FakeConfig, client *http.Client) *FakeGateway {
+	return &FakeGateway{
+		cfg:      cfg,
+		client:   client,
+		payments: make(map[string]Request),
+		refunds:  make(map[uint]string),
+		now:      time.Now,
+	}
+}
+
+// Name 金流商名稱
+func (g *FakeGateway) Name() string {
+	return FakeProvider
+}
+
+// CreatePayment 建立付款 , 付款ID為 fake_ 加上隨機值
+func (g *FakeGateway) CreatePayment(ctx context.Context, req Request) (*Payment, error) {
+	id := "fake_" + uuid.NewString()
+
+	g.mu.Lock()
+	g.payments[id] = req
+	g.mu.Unlock()
+
+	return &Payment{ID: id, CheckoutURL: g.cfg.BaseURL + "/fake-payments/" + id}, nil
+}
+
+// Refund 退款 , 退款ID為 fake_refund_ 加上隨機值 , 相同 RefundID 回傳原本的退款ID
+// 重新啟動後不知道付款金額 , 只檢查本次啟動後建立的付款
+func (g *FakeGateway) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.refunds[req.RefundID]; ok {
+		return &RefundResult{ID: id}, nil
+	}
+	if p, ok := g.payments[req.PaymentID]; ok && (req.Amount > p.Amount || req.Currency != p.Currency) {
+		return nil, fmt.Errorf("refund %d %s exceeds payment %s %d %s", req.Amount, req.Currency, req.PaymentID, p.Amount, p.Currency)
+	}
+
+	id := "fake_refund_" + uuid.NewString()
+	g.refunds[req.RefundID] = id
+	return &RefundResult{ID: id}, nil
+}
+
+// fakeCallback 模擬金流商的通知內容
+type fakeCallback struct {
+	EventID   string `json:"event_id"`
+	PaymentID string `json:"payment_id"`
+	Status    Status `json:"status"`
+	Amount    uint   `json:"amount"`
+	Currency  string `json:"currency"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ParseCallback 驗證簽章並解析通知
+func (g *FakeGateway) ParseCallback(header http.Header, body []byte) (*Callback, error) {
+	if err := webhook.Verify(g.cfg.Secret, header.Get(HeaderFakeSignature), header.Get(HeaderFakeTimestamp), body, g.now(), fakeSignatureTolerance); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCallback, err)
+	}
+
+	var cb fakeCallback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCallback, err)
+	}
+	if cb.EventID == "" || cb.PaymentID == "" || (cb.Status != StatusPaid && cb.Status != StatusFailed) {
+		return nil, fmt.Errorf("%w: missing event_id, payment_id or status", ErrInvalidCallback)
+	}
+
+	return &Callback{
+		EventID:   cb.EventID,
+		PaymentID: cb.PaymentID,
+		Status:    cb.Status,
+		Amount:    cb.Amount,
+		Currency:  cb.Currency,
+		Reason:    cb.Reason,
+	}, nil
+}
+
+// Checkout 模擬付款頁面 , 依 result 參數 (paid / failed , 預設 paid) 發送付款結果通知
+// 回傳 callback 的 HTTP 狀態碼 , callback 失敗時回傳 502
+func (g *FakeGateway) Checkout() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		g.mu.Lock()
+		req, ok := g.payments[id]
+		g.mu.Unlock()
+		if !ok {
+			http.Error(w, "payment not found", http.StatusNotFound)
+			return
+		}
+
+		cb := fakeCallback{EventID: uuid.NewString(), PaymentID: id, Status: StatusPaid, Amount: req.Amount, Currency: req.Currency}
+		switch result := r.URL.Query().Get("result"); result {
+		case "", string(StatusPaid):
+		case string(StatusFailed):
+			cb.Status = StatusFailed
+			cb.Reason = "declined by fake provider"
+		default:
+			http.Error(w, fmt.Sprintf("invalid result %q", result), http.StatusBadRequest)
+			return
+		}
+
+		status, err := g.sendCallback(r.Context(), cb)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"payment_id": id, "status": cb.Status, "callback_status": status})
+	})
+}
+
+// sendCallback 以簽章的通知 POST 至 callback 網址
+func (g *FakeGateway) sendCallback(ctx context.Context, cb fakeCallback) (int, error) {
+	body, err := json.Marshal(cb)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal callback: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.BaseURL+"/payments/"+FakeProvider+"/callback", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create callback request: %w", err)
+	}
+	ts := g.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderFakeTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderFakeSignature, webhook.Sign(g.cfg.Secret, ts, body))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send callback: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("callback responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,121 @@
+package payment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 模擬付款頁面發送簽章的付款通知 , 並可由 ParseCallback 驗證
+func TestFakeGateway_Checkout(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		assertFunc func(t *testing.T, rec *httptest.ResponseRecorder, cb *Callback, err error)
+	}{
+		{
+			name:   "paid",
+			result: "paid",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, cb *Callback, err error) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NoError(t, err)
+				assert.Equal(t, StatusPaid, cb.Status)
+				assert.EqualValues(t, 3000, cb.Amount)
+				assert.Equal(t, "TWD", cb.Currency)
+				assert.NotEmpty(t, cb.EventID)
+			},
+		},
+		{
+			name:   "failed",
+			result: "failed",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, cb *Callback, err error) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NoError(t, err)
+				assert.Equal(t, StatusFailed, cb.Status)
+				assert.NotEmpty(t, cb.Reason)
+			},
+		},
+		{
+			name:   "invalid result",
+			result: "unknown",
+			assertFunc: func(t *testing.T, rec *httptest.ResponseRecorder, cb *Callback, err error) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Nil(t, cb)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				header http.Header
+				body   []byte
+			)
+			callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/payments/fake/callback", r.URL.Path)
+				header = r.Header.Clone()
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer callbackServer.Close()
+
+			gateway := NewFakeGateway(FakeConfig{BaseURL: callbackServer.URL, Secret: "whsec_test"}, callbackServer.Client())
+			p, err := gateway.CreatePayment(context.Background(), Request{OrderID: 1, Amount: 3000, Currency: "TWD"})
+			require.NoError(t, err)
+
+			mux := http.NewServeMux()
+			mux.Handle("POST /fake-payments/{id}", gateway.Checkout())
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fake-payments/"+p.ID+"?result="+tt.result, nil))
+
+			var cb *Callback
+			if body != nil {
+				cb, err = gateway.ParseCallback(header, body)
+				if err == nil {
+					assert.Equal(t, p.ID, cb.PaymentID)
+				}
+			}
+			tt.assertFunc(t, rec, cb, err)
+		})
+	}
+}
+
+func TestFakeGateway_ParseCallback_Invalid(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Secret: "whsec_test"}, http.DefaultClient)
+
+	_, err := gateway.ParseCallback(http.Header{}, []byte(`{"event_id":"evt_1"}`))
+	assert.ErrorIs(t, err, ErrInvalidCallback)
+}
+
+func TestFakeGateway_Checkout_NotFound(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Secret: "whsec_test"}, http.DefaultClient)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /fake-payments/{id}", gateway.Checkout())
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fake-payments/fake_unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestFakeGateway_Refund(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Secret: "whsec_test"}, http.DefaultClient)
+	p, err := gateway.CreatePayment(context.Background(), Request{OrderID: 1, Amount: 3000, Currency: "TWD"})
+	require.NoError(t, err)
+
+	_, err = gateway.Refund(context.Background(), RefundRequest{RefundID: 1, PaymentID: p.ID, Amount: 3001, Currency: "TWD"})
+	assert.Error(t, err, "refund exceeds payment")
+
+	refund, err := gateway.Refund(context.Background(), RefundRequest{RefundID: 1, PaymentID: p.ID, Amount: 1500, Currency: "TWD"})
+	require.NoError(t, err)
+	assert.Contains(t, refund.ID, "fake_refund_")
+
+	// 相同退款紀錄重送時回傳原本的退款
+	again, err := gateway.Refund(context.Background(), RefundRequest{RefundID: 1, PaymentID: p.ID, Amount: 1500, Currency: "TWD"})
+	require.NoError(t, err)
+	assert.Equal(t, refund.ID, again.ID)
+}
@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrInvalidCallback 付款通知格式錯誤或簽章不符
+var ErrInvalidCallback = errors.New("invalid payment callback")
+
+// Gateway 金流商介面 , 建立付款、解析金流商的付款結果通知與退款
+// 付款結果以非同步通知 (callback) 回傳 , 同一個通知可能重送 , 呼叫端需以 Callback.EventID 去除重複
+//
+// Example:
+//
+//	payment, err := gateway.CreatePayment(ctx, payment.Request{OrderID: 1, Amount: 3000, Currency: "TWD"})
+//	// 導向 payment.CheckoutURL , 付款完成後金流商通知 callback
+//	callback, err := gateway.ParseCallback(r.Header, body)
+type Gateway interface {
+	// Name 金流商名稱 , 對應 callback 路徑與訂單的 Provider
+	Name() string
+
+	// CreatePayment 建立付款
+	// 參數: ctx - context, req - 付款內容
+	// 回傳: 付款, 錯誤訊息
+	CreatePayment(ctx context.Context, req Request) (*Payment, error)
+
+	// ParseCallback 驗證並解析付款結果通知
+	// 參數: header - 通知的 header, body - 通知內容
+	// 回傳: 付款結果, 錯誤訊息 , 格式錯誤或簽章不符時回傳 ErrInvalidCallback
+	ParseCallback(header http.Header, body []byte) (*Callback, error)
+
+	// Refund 退還已付款的金額 , 同一個 RefundID 重送時金流商不會重複退款
+	// 參數: ctx - context, req - 退款內容
+	// 回傳: 退款結果, 錯誤訊息
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+}
+
+// Request 建立付款的內容
+type Request struct {
+	OrderID     uint   // 訂單ID
+	Amount      uint   // 金額 , 以幣別的最小單位計
+	Currency    string // 幣別 , ISO 4217
+	Description string // 付款說明 , 例如課程名稱
+}
+
+// Payment 金流商建立的付款
+type Payment struct {
+	ID          string // 金流商的付款ID
+	CheckoutURL string // 付款頁面網址
+}
+
+// Status 付款結果
+type Status string
+
+const (
+	StatusPaid   Status = "paid"   // 付款成功
+	StatusFailed Status = "failed" // 付款失敗
+)
+
+// Callback 付款結果通知
+type Callback struct {
+	EventID   string // 通知ID , 重送時不變
+	PaymentID string // 金流商的付款ID
+	Status    Status // 付款結果
+	Amount    uint   // 實際付款金額
+	Currency  string // 幣別
+	Reason    string // 付款失敗原因
+}
+
+// RefundRequest 退款的內容
+type RefundRequest struct {
+	RefundID  uint   // 退款紀錄ID , 作為金流商的冪等鍵
+	PaymentID string // 金流商的付款ID
+	Amount    uint   // 退款金額 , 以幣別的最小單位計 , 不超過付款金額
+	Currency  string // 幣別 , ISO 4217
+}
+
+// RefundResult 金流商的退款結果
+type RefundResult struct {
+	ID string // 金流商的退款ID
+}
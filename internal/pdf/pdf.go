@@ -0,0 +1,178 @@
+// Package pdf 輸出單頁 PDF 文件 , 供發票、證書等需要下載的文件使用
+// 只支援文字與線條 , ASCII 使用 Helvetica , 其他字元使用 PDF 閱讀器內建的 MSung-Light 字型 , 不嵌入字型檔
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 尺寸 , 單位為 point (1/72 inch)
+const (
+	A4Width  = 595.0
+	A4Height = 842.0
+)
+
+// cjkFontName Adobe-CNS1 繁體中文字型 , 由 PDF 閱讀器提供 , 不需嵌入
+const cjkFontName = "MSung-Light"
+
+// helveticaWidths Helvetica 字元 32 (空白) 至 126 (~) 的寬度 , 單位為字型大小的 1/1000 , 用於計算對齊
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// Page 單頁 PDF 的內容串流 , 座標原點為頁面左下角
+// 文字依字元切換字型 , ASCII 使用 Helvetica (F1) , 其他字元使用 MSung-Light (F2) , 以 UCS-2 編碼
+//
+// Example:
+//
+//	p := pdf.NewPage(pdf.A4Width, pdf.A4Height)
+//	p.Text(50, 800, 22, "發票")
+//	b := p.Bytes("發票 INV-2025-000001")
+type Page struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+// NewPage 建立空白頁面
+// 參數: width - 頁面寬度, height - 頁面高度 , 單位為 point , 橫向頁面將寬高對調
+// 回傳: 頁面
+func NewPage(width, height float64) *Page {
+	return &Page{width: width, height: height}
+}
+
+// Width 頁面寬度
+func (p *Page) Width() float64 { return p.width }
+
+// Height 頁面高度
+func (p *Page) Height() float64 { return p.height }
+
+// Text 於 (x, y) 輸出文字 , y 為由頁面下緣起算的基準線位置
+func (p *Page) Text(x, y, size float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT %.2f %.2f Td ", x, y)
+	for _, run := range splitRuns(s) {
+		if run.ascii {
+			fmt.Fprintf(&p.content, "/F1 %.1f Tf (%s) Tj ", size, escapeLiteral(run.text))
+		} else {
+			fmt.Fprintf(&p.content, "/F2 %.1f Tf <%s> Tj ", size, ucs2Hex(run.text))
+		}
+	}
+	p.content.WriteString("ET\n")
+}
+
+// TextRight 輸出文字 , 文字右緣對齊 x
+func (p *Page) TextRight(x, y, size float64, s string) {
+	p.Text(x-TextWidth(s, size), y, size, s)
+}
+
+// TextCenter 輸出文字 , 文字中心對齊 x
+func (p *Page) TextCenter(x, y, size float64, s string) {
+	p.Text(x-TextWidth(s, size)/2, y, size, s)
+}
+
+// Line 由 (x1, y1) 至 (x2, y2) 畫線
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Rect 畫出左下角為 (x, y) 的矩形外框
+func (p *Page) Rect(x, y, width, height, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n", lineWidth, x, y, width, height)
+}
+
+// Bytes 組成完整的 PDF 檔案 , 包含字型、頁面與文件資訊 , 物件位置寫入 xref
+// 參數: title - 文件標題 , 顯示於 PDF 閱讀器的視窗標題
+// 回傳: PDF 檔案內容
+func (p *Page) Bytes(title string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>", p.width, p.height),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /" + cjkFontName + " /Encoding /UniCNS-UCS2-H /DescendantFonts [7 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /" + cjkFontName + " /CIDSystemInfo << /Registry (Adobe) /Ordering (CNS1) /Supplement 0 >> /FontDescriptor 8 0 R /DW 1000 >>",
+		"<< /Type /FontDescriptor /FontName /" + cjkFontName + " /Flags 6 /FontBBox [-160 -249 1015 1071] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+		"<< /Title <FEFF" + ucs2Hex(title) + "> /Producer (course-management-system) >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return buf.Bytes()
+}
+
+// TextWidth 文字於字型大小 size 時的寬度 , 非 ASCII 字元以全形寬度計算
+func TextWidth(s string, size float64) float64 {
+	var width int
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			width += helveticaWidths[r-32]
+		} else {
+			width += 1000
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// textRun 使用同一個字型的連續字元
+type textRun struct {
+	text  string
+	ascii bool
+}
+
+// splitRuns 將文字依 ASCII 與非 ASCII 字元切分
+func splitRuns(s string) []textRun {
+	var runs []textRun
+	var current strings.Builder
+	ascii := true
+	for i, r := range s {
+		isASCII := r >= 32 && r < 127
+		if i > 0 && isASCII != ascii {
+			runs = append(runs, textRun{text: current.String(), ascii: ascii})
+			current.Reset()
+		}
+		ascii = isASCII
+		current.WriteRune(r)
+	}
+	return append(runs, textRun{text: current.String(), ascii: ascii})
+}
+
+// escapeLiteral 跳脫 PDF literal string 的特殊字元
+func escapeLiteral(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// ucs2Hex 將文字編碼為 UCS-2 big endian 的十六進位字串 , BMP 以外的字元以問號取代
+func ucs2Hex(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage_Bytes(t *testing.T) {
+	p := NewPage(A4Height, A4Width)
+	p.Text(50, 500, 12, "Go 入門 (2025)")
+	p.TextCenter(A4Height/2, 300, 20, "結業證書")
+	p.Line(50, 100, 200, 100, 1)
+	p.Rect(20, 20, A4Height-40, A4Width-40, 2)
+	b := p.Bytes("結業證書")
+
+	assert.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(b, []byte("%%EOF\n")))
+	assert.Contains(t, string(b), "/MediaBox [0 0 842 595]")
+	// 括號須跳脫 , 中文以 UCS-2 編碼輸出
+	assert.Contains(t, string(b), `/F1 12.0 Tf ( \(2025\)) Tj`)
+	assert.Contains(t, string(b), "/F2 12.0 Tf <51659580> Tj")
+
+	// xref 記錄的位置須指向對應的物件
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(b)
+	require.NotNil(t, m)
+	xref, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(b[xref:], []byte("xref\n")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(b, -1)
+	require.Len(t, offsets, 9)
+	for i, o := range offsets {
+		offset, err := strconv.Atoi(string(o[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(b[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+func TestSplitRuns(t *testing.T) {
+	runs := splitRuns("Go 入門 (2025)")
+	assert.Equal(t, []textRun{{text: "Go ", ascii: true}, {text: "入門", ascii: false}, {text: " (2025)", ascii: true}}, runs)
+	assert.InDelta(t, 2*10+TextWidth("Go ", 10), TextWidth("Go 入門", 10), 0.001)
+	assert.Equal(t, "516595800030003F", ucs2Hex("入門0\U0001F600"))
+}
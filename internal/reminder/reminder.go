@@ -0,0 +1,214 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	notificationService "github.com/itmrchow/course-management-system/internal/service/notification"
+)
+
+// Config reminder 設定
+type Config struct {
+	BatchSize   int           // 每次取得的課程數量
+	DefaultLead time.Duration // 課程未設定 ReminderHours 時 , 上課前多久發送提醒
+	MaxLead     time.Duration // 上課前發送提醒的時間上限 , 課程設定超過時以此為準
+}
+
+// ConfigFromViper 從 viper 讀取 reminder 設定
+func ConfigFromViper() Config {
+	viper.SetDefault("REMINDER_BATCH_SIZE", 100)
+	viper.SetDefault("REMINDER_DEFAULT_LEAD", 24*time.Hour)
+	viper.SetDefault("REMINDER_MAX_LEAD", 7*24*time.Hour)
+
+	return Config{
+		BatchSize:   viper.GetInt("REMINDER_BATCH_SIZE"),
+		DefaultLead: viper.GetDuration("REMINDER_DEFAULT_LEAD"),
+		MaxLead:     viper.GetDuration("REMINDER_MAX_LEAD"),
+	}
+}
+
+// Reminder 於上課前發送上課提醒給已報名的學生與授課教師
+// 上課由課程的 CoursePattern 展開 , 於提醒時間內才寫入 , 已取消的上課與已退出的學生不會收到提醒
+// 同一門課程的通知建立與上課標記已提醒在同一個 transaction 中完成 , 重新啟動或多個 reminder 同時執行不會重複提醒
+//
+// Example:
+//
+//	reminder := reminder.NewReminder(transactor, courseRepo, sessionRepo, enrollmentRepo, teacherRepo, notificationSvc, reminder.ConfigFromViper())
+//	runner.Every("session_reminder", time.Minute, reminder.RunOnce)
+type Reminder struct {
+	transactor      repo.Transactor
+	courseRepo      courseRepository.CourseRepository
+	sessionRepo     sessionRepository.SessionRepository
+	enrollmentRepo  enrollmentRepository.EnrollmentRepository
+	teacherRepo     teacherRepository.TeacherRepository
+	notificationSvc notificationService.NotificationService
+	cfg             Config
+	now             func() time.Time
+}
+
+// NewReminder 建立 reminder
+// 參數: transactor - transaction, courseRepo - 課程, sessionRepo - 上課, enrollmentRepo - 報名, teacherRepo - 教師 , 查詢教師的聯絡方式, notificationSvc - 通知, cfg - 設定
+func NewReminder(
+	transactor repo.Transactor,
+	courseRepo courseRepository.CourseRepository,
+	sessionRepo sessionRepository.SessionRepository,
+	enrollmentRepo enrollmentRepository.EnrollmentRepository,
+	teacherRepo teacherRepository.TeacherRepository,
+	notificationSvc notificationService.NotificationService,
+	cfg Config,
+) *Reminder {
+	return &Reminder{
+		transactor:      transactor,
+		courseRepo:      courseRepo,
+		sessionRepo:     sessionRepo,
+		enrollmentRepo:  enrollmentRepo,
+		teacherRepo:     teacherRepo,
+		notificationSvc: notificationSvc,
+		cfg:             cfg,
+		now:             time.Now,
+	}
+}
+
+// RunOnce 發送所有到達提醒時間的上課提醒 , 可作為 job.Func
+// 只處理開放報名或暫停報名的課程 , 單一課程失敗時仍會處理其他課程 , 回傳所有失敗的錯誤
+func (r *Reminder) RunOnce(ctx context.Context) error {
+	now := r.now()
+	conditions := []func(db *gorm.DB) *gorm.DB{
+		courseEntity.InCourseStatus([]courseEntity.CourseStatus{courseEntity.CourseStatusOnline, courseEntity.CourseStatusPause}),
+		courseEntity.RunningBetween(now, now.Add(r.cfg.MaxLead)),
+	}
+
+	var errs []error
+	for page := 1; ctx.Err() == nil; page++ {
+		courses, err := r.courseRepo.Find(ctx, &repo.RepoPageInfo{Page: page, PageSize: r.cfg.BatchSize, Sort: "id", Order: "asc"}, conditions)
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("failed to find courses: %w", err))...)
+		}
+
+		for _, course := range courses {
+			if err := r.remindCourse(ctx, course, now); err != nil {
+				errs = append(errs, fmt.Errorf("course %d: %w", course.ID, err))
+			}
+		}
+		if len(courses) < r.cfg.BatchSize {
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(append(errs, ctx.Err())...)
+}
+
+// remindCourse 於 transaction 中寫入課程提醒時間內的上課 , 並提醒尚未提醒的上課
+func (r *Reminder) remindCourse(ctx context.Context, course *courseEntity.Course, now time.Time) error {
+	to := now.Add(r.lead(course))
+
+	return r.transactor.Transaction(ctx, func(ctx context.Context) error {
+		patterns, err := r.courseRepo.ListPatterns(ctx, course.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list patterns: %w", err)
+		}
+		if _, err := r.sessionRepo.Ensure(ctx, courseEntity.DeriveSessions(course, patterns, now, to)...); err != nil {
+			return fmt.Errorf("failed to ensure sessions: %w", err)
+		}
+
+		sessions, err := r.sessionRepo.ListDueForReminder(ctx, course.ID, now, to)
+		if err != nil {
+			return fmt.Errorf("failed to list sessions due for reminder: %w", err)
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+
+		recipients, err := r.recipients(ctx, course.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, session := range sessions {
+			for _, recipient := range recipients {
+				if _, err := r.notificationSvc.Notify(ctx, &notificationService.NotifyRequest{
+					Key:       fmt.Sprintf("%s:%d:%d", notification.TemplateCourseReminder, session.ID, recipient.UserID),
+					Recipient: recipient,
+					Template:  notification.TemplateCourseReminder,
+					Data: map[string]any{
+						"CourseName": course.Name,
+						"StartAt":    session.StartAt,
+						"EndAt":      session.EndAt,
+						"JoinURL":    session.JoinURL, // 線上課程的會議網址 , 尚未建立會議時為空字串
+					},
+				}); err != nil {
+					return fmt.Errorf("failed to notify user %d of session %d: %w", recipient.UserID, session.ID, err)
+				}
+			}
+
+			if _, err := r.sessionRepo.MarkReminded(ctx, session.ID, now); err != nil {
+				return fmt.Errorf("failed to mark session %d reminded: %w", session.ID, err)
+			}
+			zerolog.Ctx(ctx).Info().
+				Uint("course_id", course.ID).
+				Uint("session_id", session.ID).
+				Int("recipients", len(recipients)).
+				Msg("session reminder sent")
+		}
+		return nil
+	})
+}
+
+// lead 課程於上課前多久發送提醒
+func (r *Reminder) lead(course *courseEntity.Course) time.Duration {
+	if course.ReminderHours == 0 {
+		return min(r.cfg.DefaultLead, r.cfg.MaxLead)
+	}
+	return min(time.Duration(course.ReminderHours)*time.Hour, r.cfg.MaxLead)
+}
+
+// recipients 課程的授課教師與已報名的學生
+// 學生沒有聯絡資料 , 由通知偏好提供地址 ; 教師已刪除時略過
+func (r *Reminder) recipients(ctx context.Context, courseID uint) ([]notificationService.Recipient, error) {
+	var recipients []notificationService.Recipient
+
+	courseTeachers, err := r.courseRepo.ListTeachers(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list course teachers: %w", err)
+	}
+	for _, courseTeacher := range courseTeachers {
+		teacher, err := r.teacherRepo.GetByID(ctx, courseTeacher.TeacherID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teacher %d: %w", courseTeacher.TeacherID, err)
+		}
+		recipients = append(recipients, notificationService.Recipient{
+			UserID: teacher.UserID,
+			Name:   teacher.Name,
+			Email:  teacher.Email,
+			Phone:  teacher.Phone,
+		})
+	}
+
+	enrollments, err := r.enrollmentRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"}, []func(db *gorm.DB) *gorm.DB{
+		enrollmentEntity.EnrollmentOfCourse(courseID),
+		enrollmentEntity.InEnrollmentStatus([]enrollmentEntity.EnrollmentStatus{enrollmentEntity.EnrollmentStatusEnrolled}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enrollments: %w", err)
+	}
+	for _, enrollment := range enrollments {
+		recipients = append(recipients, notificationService.Recipient{UserID: enrollment.StudentID})
+	}
+	return recipients, nil
+}
@@ -0,0 +1,193 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	notificationEntity "github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	notificationRepository "github.com/itmrchow/course-management-system/internal/repository/notification"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	notificationService "github.com/itmrchow/course-management-system/internal/service/notification"
+)
+
+type ReminderTestSuite struct {
+	suite.Suite
+	courseRepo       courseRepository.CourseRepository
+	sessionRepo      sessionRepository.SessionRepository
+	notificationRepo notificationRepository.NotificationRepository
+	reminder         *Reminder
+	now              time.Time
+}
+
+// SetupTest 於每個測試案例前執行
+// 課程 1 每週一、三 11:00 上課 , 使用預設提醒時間 (24 小時) , 教師 1 (user 10) 授課
+// 學生 20 已報名且設定 email , 學生 21 已退出 , 學生 22 已報名但沒有聯絡方式
+// 課程 2 每週一、三 11:00 上課 , 上課前 72 小時提醒 , 課程 3 為草稿
+func (s *ReminderTestSuite) SetupTest() {
+	ctx := context.Background()
+	s.now = time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC) // 星期一
+
+	s.courseRepo = courseRepository.NewCourseMemoryRepository()
+	s.sessionRepo = sessionRepository.NewSessionMemoryRepository()
+	s.notificationRepo = notificationRepository.NewNotificationMemoryRepository()
+	teacherRepo := teacherRepository.NewTeacherMemoryRepository()
+	enrollmentRepo := enrollmentRepository.NewEnrollmentMemoryRepository()
+
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", Status: courseEntity.CourseStatusOnline, StartDate: s.now, EndDate: s.now.AddDate(0, 1, 0)},
+		{Name: "Go 進階", Status: courseEntity.CourseStatusPause, StartDate: s.now, EndDate: s.now.AddDate(0, 1, 0), ReminderHours: 72},
+		{Name: "Python 入門", Status: courseEntity.CourseStatusDraft, StartDate: s.now, EndDate: s.now.AddDate(0, 1, 0)},
+	} {
+		_, err := s.courseRepo.Create(ctx, course)
+		s.Require().NoError(err)
+
+		for _, day := range []time.Weekday{time.Monday, time.Wednesday} {
+			_, err := s.courseRepo.CreatePattern(ctx, &courseEntity.CoursePattern{
+				CourseID:  course.ID,
+				DayOfWeek: uint(day),
+				StartTime: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC),
+			})
+			s.Require().NoError(err)
+		}
+	}
+
+	_, err := teacherRepo.Create(ctx, &teacherEntity.Teacher{UserID: 10, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved})
+	s.Require().NoError(err)
+	_, err = s.courseRepo.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	s.Require().NoError(err)
+
+	for _, enrollment := range []*enrollmentEntity.Enrollment{
+		{CourseID: 1, StudentID: 20, Status: enrollmentEntity.EnrollmentStatusEnrolled, EnrolledAt: s.now},
+		{CourseID: 1, StudentID: 21, Status: enrollmentEntity.EnrollmentStatusWithdrawn, EnrolledAt: s.now},
+		{CourseID: 1, StudentID: 22, Status: enrollmentEntity.EnrollmentStatusEnrolled, EnrolledAt: s.now},
+		{CourseID: 2, StudentID: 20, Status: enrollmentEntity.EnrollmentStatusEnrolled, EnrolledAt: s.now},
+		{CourseID: 3, StudentID: 20, Status: enrollmentEntity.EnrollmentStatusEnrolled, EnrolledAt: s.now},
+	} {
+		_, err := enrollmentRepo.Create(ctx, enrollment)
+		s.Require().NoError(err)
+	}
+
+	renderer, err := notification.NewRenderer(time.UTC)
+	s.Require().NoError(err)
+	notificationSvc := notificationService.NewNotificationService(s.notificationRepo, teacherRepo, renderer)
+	for _, userID := range []uint{20, 21} {
+		s.Require().NoError(notificationSvc.SavePreference(ctx, &notificationEntity.NotificationPreference{
+			UserID: userID, Locale: notificationEntity.LocaleEn, EmailEnabled: true, Email: "student@example.com",
+		}))
+	}
+
+	s.reminder = NewReminder(repository.NoTransaction, s.courseRepo, s.sessionRepo, enrollmentRepo, teacherRepo, notificationSvc, Config{
+		BatchSize:   1,
+		DefaultLead: 24 * time.Hour,
+		MaxLead:     7 * 24 * time.Hour,
+	})
+	s.reminder.now = func() time.Time { return s.now }
+}
+
+func TestReminder(t *testing.T) {
+	suite.Run(t, new(ReminderTestSuite))
+}
+
+// notifications 取得所有通知
+func (s *ReminderTestSuite) notifications() []*notificationEntity.Notification {
+	notifications, err := s.notificationRepo.Find(context.Background(), &repository.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"}, nil)
+	s.Require().NoError(err)
+	return notifications
+}
+
+// sessions 取得課程的上課
+func (s *ReminderTestSuite) sessions(courseID uint) []*courseEntity.CourseSession {
+	sessions, err := s.sessionRepo.Find(context.Background(), &repository.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{courseEntity.SessionOfCourse(courseID)})
+	s.Require().NoError(err)
+	return sessions
+}
+
+func (s *ReminderTestSuite) Test_RunOnce_RemindsWithinLead() {
+	s.Require().NoError(s.reminder.RunOnce(context.Background()))
+
+	// 課程 1 只有今天的上課在 24 小時內 , 課程 2 週一與週三的上課都在 72 小時內 , 課程 3 為草稿
+	course1 := s.sessions(1)
+	s.Require().Len(course1, 1)
+	s.True(course1[0].StartAt.Equal(time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)))
+	s.True(course1[0].EndAt.Equal(time.Date(2025, 7, 21, 13, 0, 0, 0, time.UTC)))
+	s.NotNil(course1[0].RemindedAt)
+	s.Len(s.sessions(2), 2)
+	s.Empty(s.sessions(3))
+
+	// 課程 1 (上課 1): 教師 email + 簡訊 , 學生 20 email ; 學生 21 已退出 , 學生 22 沒有聯絡方式
+	// 課程 2 (上課 2, 3): 學生 20 各一則 email
+	var addresses []string
+	for _, n := range s.notifications() {
+		s.Equal(notification.TemplateCourseReminder, n.Template)
+		if strings.HasPrefix(n.Key, "course_reminder:1:") {
+			addresses = append(addresses, n.Address)
+		}
+	}
+	s.ElementsMatch([]string{"ming@example.com", "0912345678", "student@example.com"}, addresses)
+	s.Len(s.notifications(), 5)
+}
+
+func (s *ReminderTestSuite) Test_RunOnce_NoDuplicates() {
+	s.Require().NoError(s.reminder.RunOnce(context.Background()))
+	count := len(s.notifications())
+
+	// 重新執行 (例如重新啟動) 不會重複提醒
+	s.now = s.now.Add(time.Hour)
+	s.Require().NoError(s.reminder.RunOnce(context.Background()))
+	s.Len(s.notifications(), count)
+
+	// 週三的上課進入提醒時間後才提醒
+	s.now = time.Date(2025, 7, 22, 12, 0, 0, 0, time.UTC)
+	s.Require().NoError(s.reminder.RunOnce(context.Background()))
+	s.Len(s.notifications(), count+3)
+	s.Len(s.sessions(1), 2)
+}
+
+func (s *ReminderTestSuite) Test_RunOnce_SkipsCancelledSessions() {
+	ctx := context.Background()
+	_, err := s.sessionRepo.Ensure(ctx, courseEntity.DeriveSessions(&courseEntity.Course{
+		Model: gorm.Model{ID: 1}, StartDate: s.now, EndDate: s.now.AddDate(0, 1, 0),
+	}, []*courseEntity.CoursePattern{{Model: gorm.Model{ID: 1}, DayOfWeek: uint(time.Monday), StartTime: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)}},
+		s.now, s.now.Add(24*time.Hour))...)
+	s.Require().NoError(err)
+	_, err = s.sessionRepo.Cancel(ctx, 1, "颱風停課", s.now)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.reminder.RunOnce(ctx))
+
+	course1 := s.sessions(1)
+	s.Require().Len(course1, 1)
+	s.Equal(courseEntity.SessionStatusCancelled, course1[0].Status)
+	s.Nil(course1[0].RemindedAt)
+	for _, n := range s.notifications() {
+		s.NotContains(n.Key, "course_reminder:1:")
+	}
+}
+
+func (s *ReminderTestSuite) Test_RunOnce_SkipsStartedSessions() {
+	s.now = time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)
+
+	s.Require().NoError(s.reminder.RunOnce(context.Background()))
+
+	for _, session := range s.sessions(1) {
+		s.Nil(session.RemindedAt)
+	}
+	for _, n := range s.notifications() {
+		s.NotContains(n.Key, "course_reminder:1:")
+	}
+}
@@ -0,0 +1,113 @@
+package attendance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// AttendanceRepoContractSuite AttendanceRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type AttendanceRepoContractSuite struct {
+	suite.Suite
+	newRepo        func(t *testing.T) AttendanceRepository
+	attendanceRepo AttendanceRepository
+	now            time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增課程 1 上課 1 的出席紀錄 (學生 10 出席 , 學生 11 缺席)
+func (s *AttendanceRepoContractSuite) SetupTest() {
+	s.attendanceRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	s.Require().NoError(s.attendanceRepo.Upsert(context.Background(), []*entity.Attendance{
+		s.newAttendance(1, 10, entity.AttendanceStatusPresent),
+		s.newAttendance(1, 11, entity.AttendanceStatusAbsent),
+	}))
+}
+
+// TestAttendanceRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestAttendanceRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &AttendanceRepoContractSuite{newRepo: func(t *testing.T) AttendanceRepository {
+			return NewAttendanceRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &AttendanceRepoContractSuite{newRepo: func(t *testing.T) AttendanceRepository {
+			return NewAttendanceMemoryRepository()
+		}})
+	})
+}
+
+func (s *AttendanceRepoContractSuite) newAttendance(sessionID, studentID uint, status entity.AttendanceStatus) *entity.Attendance {
+	return &entity.Attendance{
+		SessionID:    sessionID,
+		StudentID:    studentID,
+		CourseID:     1,
+		EnrollmentID: studentID,
+		Status:       status,
+		MarkedBy:     1,
+		MarkedAt:     s.now,
+	}
+}
+
+// 重新點名時更新既有的紀錄 , 不會新增
+func (s *AttendanceRepoContractSuite) TestUpsert() {
+	ctx := context.Background()
+
+	update := s.newAttendance(1, 11, entity.AttendanceStatusExcused)
+	update.Note = "病假"
+	update.MarkedBy = 2
+	update.MarkedAt = s.now.Add(time.Hour)
+	s.Require().NoError(s.attendanceRepo.Upsert(ctx, []*entity.Attendance{update, s.newAttendance(1, 12, entity.AttendanceStatusLate)}))
+
+	attendances, err := s.attendanceRepo.ListBySession(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(attendances, 3)
+	s.Equal([]uint{10, 11, 12}, []uint{attendances[0].StudentID, attendances[1].StudentID, attendances[2].StudentID})
+	s.Equal(entity.AttendanceStatusExcused, attendances[1].Status)
+	s.Equal("病假", attendances[1].Note)
+	s.EqualValues(2, attendances[1].MarkedBy)
+	s.True(attendances[1].MarkedAt.Equal(s.now.Add(time.Hour)))
+	s.Equal(entity.AttendanceStatusLate, attendances[2].Status)
+
+	s.Require().NoError(s.attendanceRepo.Upsert(ctx, nil))
+}
+
+func (s *AttendanceRepoContractSuite) TestListBySession() {
+	attendances, err := s.attendanceRepo.ListBySession(context.Background(), 2)
+	s.Require().NoError(err)
+	s.Empty(attendances)
+}
+
+func (s *AttendanceRepoContractSuite) TestCount() {
+	ctx := context.Background()
+	s.Require().NoError(s.attendanceRepo.Upsert(ctx, []*entity.Attendance{
+		s.newAttendance(2, 10, entity.AttendanceStatusPresent),
+		s.newAttendance(2, 11, entity.AttendanceStatusAbsent),
+		s.newAttendance(3, 10, entity.AttendanceStatusLate),
+	}))
+
+	counts, err := s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{entity.AttendanceOfCourse(1)})
+	s.Require().NoError(err)
+	s.Equal([]*entity.AttendanceCount{
+		{StudentID: 10, Status: entity.AttendanceStatusPresent, Count: 2},
+		{StudentID: 10, Status: entity.AttendanceStatusLate, Count: 1},
+		{StudentID: 11, Status: entity.AttendanceStatusAbsent, Count: 2},
+	}, counts)
+
+	counts, err = s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{entity.AttendanceOfCourse(1), entity.AttendanceOfStudent(11)})
+	s.Require().NoError(err)
+	s.Equal([]*entity.AttendanceCount{{StudentID: 11, Status: entity.AttendanceStatusAbsent, Count: 2}}, counts)
+
+	counts, err = s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{entity.AttendanceOfCourse(2)})
+	s.Require().NoError(err)
+	s.Empty(counts)
+}
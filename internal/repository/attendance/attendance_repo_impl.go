@@ -0,0 +1,82 @@
+package attendance
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ AttendanceRepository = (*AttendanceRepositoryImpl)(nil)
+
+// AttendanceRepositoryImpl 實作 AttendanceRepository 介面
+//
+// Example:
+//
+//	repo := attendance.NewAttendanceRepository(db)
+//	attendances, err := repo.ListBySession(ctx, 1)
+type AttendanceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttendanceRepository 建立出席紀錄資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 出席紀錄資料庫操作實例
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &AttendanceRepositoryImpl{db: db}
+}
+
+// Upsert 新增或更新出席紀錄 , 以 ON CONFLICT (session_id, student_id) DO UPDATE 更新既有的紀錄
+func (r *AttendanceRepositoryImpl) Upsert(ctx context.Context, attendances []*entity.Attendance) error {
+	defer metrics.ObserveRepoQuery("attendance", "Upsert", time.Now())
+
+	if len(attendances) == 0 {
+		return nil
+	}
+
+	return repo.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "session_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "note", "marked_by", "marked_at", "updated_at"}),
+		}).
+		Create(&attendances).
+		Error
+}
+
+// ListBySession 查詢上課的出席紀錄
+func (r *AttendanceRepositoryImpl) ListBySession(ctx context.Context, sessionID uint) ([]*entity.Attendance, error) {
+	defer metrics.ObserveRepoQuery("attendance", "ListBySession", time.Now())
+
+	var attendances []*entity.Attendance
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.AttendanceOfSession(sessionID)).
+		Order("student_id").
+		Find(&attendances).
+		Error; err != nil {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+// Count 依學生與出席狀態統計出席紀錄 , 以 GROUP BY 於資料庫計算
+func (r *AttendanceRepositoryImpl) Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.AttendanceCount, error) {
+	defer metrics.ObserveRepoQuery("attendance", "Count", time.Now())
+
+	var counts []*entity.AttendanceCount
+	if err := repo.Conn(ctx, r.db).
+		Model(&entity.Attendance{}).
+		Scopes(conditions...).
+		Select("student_id, status, COUNT(*) AS count").
+		Group("student_id, status").
+		Order("student_id, status").
+		Scan(&counts).
+		Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -0,0 +1,33 @@
+package attendance
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+)
+
+// AttendanceRepository 定義出席紀錄的資料存取介面
+//
+// Example:
+//
+//	var repo AttendanceRepository
+//	err := repo.Upsert(ctx, attendances)
+//	counts, err := repo.Count(ctx, []func(db *gorm.DB) *gorm.DB{entity.AttendanceOfCourse(1)})
+type AttendanceRepository interface {
+	// Upsert 新增或更新出席紀錄 , 學生於該次上課已有紀錄時更新狀態、備註、點名教師與點名時間
+	// 參數: ctx - context, attendances - 出席紀錄 , 新增後寫入ID
+	// 回傳: 錯誤訊息
+	Upsert(ctx context.Context, attendances []*entity.Attendance) error
+
+	// ListBySession 查詢上課的出席紀錄
+	// 參數: ctx - context, sessionID - 上課ID
+	// 回傳: 出席紀錄 , 依學生ID排序, 錯誤訊息
+	ListBySession(ctx context.Context, sessionID uint) ([]*entity.Attendance, error)
+
+	// Count 依學生與出席狀態統計出席紀錄
+	// 參數: ctx - context, conditions - 查詢條件
+	// 回傳: 各學生各狀態的紀錄數量 , 依學生ID與狀態排序, 錯誤訊息
+	Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.AttendanceCount, error)
+}
@@ -0,0 +1,111 @@
+package attendance
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ AttendanceRepository = (*AttendanceMemoryRepository)(nil)
+
+// AttendanceMemoryRepository 以記憶體實作 AttendanceRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫
+//
+// Example:
+//
+//	repo := attendance.NewAttendanceMemoryRepository()
+//	err := repo.Upsert(ctx, attendances)
+type AttendanceMemoryRepository struct {
+	attendances *memory.Table[entity.Attendance]
+
+	mu sync.Mutex // 確保 Upsert 的查詢與新增不會交錯
+}
+
+// NewAttendanceMemoryRepository 建立記憶體出席紀錄資料操作實例
+// 回傳: 出席紀錄資料操作實例
+func NewAttendanceMemoryRepository() AttendanceRepository {
+	return &AttendanceMemoryRepository{attendances: memory.NewTable[entity.Attendance]()}
+}
+
+// Upsert 新增或更新出席紀錄
+func (r *AttendanceMemoryRepository) Upsert(ctx context.Context, attendances []*entity.Attendance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, attendance := range attendances {
+		existing, err := r.attendances.Where([]func(db *gorm.DB) *gorm.DB{
+			func(db *gorm.DB) *gorm.DB {
+				return db.Where("session_id = ? AND student_id = ?", attendance.SessionID, attendance.StudentID)
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			if err := r.attendances.Create(attendance); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attendance.ID = existing[0].ID
+		if _, err := r.attendances.Update(attendance.ID,
+			func(row *entity.Attendance) bool { return true },
+			func(row *entity.Attendance) {
+				row.Status = attendance.Status
+				row.Note = attendance.Note
+				row.MarkedBy = attendance.MarkedBy
+				row.MarkedAt = attendance.MarkedAt
+			},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBySession 查詢上課的出席紀錄 , 依學生ID排序
+func (r *AttendanceMemoryRepository) ListBySession(ctx context.Context, sessionID uint) ([]*entity.Attendance, error) {
+	attendances, err := r.attendances.Where([]func(db *gorm.DB) *gorm.DB{entity.AttendanceOfSession(sessionID)})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(attendances, func(i, j int) bool { return attendances[i].StudentID < attendances[j].StudentID })
+	return attendances, nil
+}
+
+// Count 依學生與出席狀態統計出席紀錄
+func (r *AttendanceMemoryRepository) Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.AttendanceCount, error) {
+	attendances, err := r.attendances.Where(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		studentID uint
+		status    entity.AttendanceStatus
+	}
+	counts := make(map[key]*entity.AttendanceCount)
+	var result []*entity.AttendanceCount
+	for _, attendance := range attendances {
+		k := key{attendance.StudentID, attendance.Status}
+		if c, ok := counts[k]; ok {
+			c.Count++
+			continue
+		}
+		counts[k] = &entity.AttendanceCount{StudentID: attendance.StudentID, Status: attendance.Status, Count: 1}
+		result = append(result, counts[k])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].StudentID != result[j].StudentID {
+			return result[i].StudentID < result[j].StudentID
+		}
+		return result[i].Status < result[j].Status
+	})
+	return result, nil
+}
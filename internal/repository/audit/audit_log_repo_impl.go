@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ AuditLogRepository = (*AuditLogRepositoryImpl)(nil)
+
+// AuditLogRepositoryImpl 實作 AuditLogRepository 介面
+//
+// Example:
+//
+//	repo := audit.NewAuditLogRepository(db)
+//	logs, err := repo.Find(ctx, pageInfo, nil)
+type AuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 建立稽核紀錄資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 稽核紀錄資料庫操作實例
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &AuditLogRepositoryImpl{db: db}
+}
+
+// Find 查詢稽核紀錄
+// 使用 gorm.Find 查詢稽核紀錄 , 相同排序值時依 id 排序 , 確保分頁結果穩定
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回稽核紀錄
+func (r *AuditLogRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*auditEntity.AuditLog, error) {
+	defer metrics.ObserveRepoQuery("audit_log", "Find", time.Now())
+
+	var logs []*auditEntity.AuditLog
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Order(fmt.Sprintf("id %s", pageInfo.Order)).
+		Find(&logs).
+		Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+type AuditLogRepoTestSuite struct {
+	suite.Suite
+	auditLogRepo AuditLogRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立獨立的資料庫並新增測試資料
+func (s *AuditLogRepoTestSuite) SetupTest() {
+	db := testutil.NewDB(s.T())
+	s.auditLogRepo = NewAuditLogRepository(db)
+
+	actorID := uint(99)
+	base := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	logs := []*auditEntity.AuditLog{
+		{EntityType: "course", EntityID: 1, Operation: auditEntity.OperationCreate, CreatedAt: base},                                   // id 1
+		{EntityType: "course", EntityID: 1, Operation: auditEntity.OperationUpdate, ActorID: &actorID, CreatedAt: base.Add(time.Hour)}, // id 2
+		{EntityType: "course", EntityID: 2, Operation: auditEntity.OperationCreate, ActorID: &actorID, CreatedAt: base.Add(time.Hour)}, // id 3
+		{EntityType: "teacher", EntityID: 1, Operation: auditEntity.OperationDelete, CreatedAt: base.Add(2 * time.Hour)},               // id 4
+	}
+	for _, log := range logs {
+		log.Changes = auditEntity.Changes{"name": {Old: "old", New: "new"}}
+	}
+	s.Require().NoError(db.Create(&logs).Error)
+}
+
+// TestAuditLogRepoTestSuite 執行測試套件
+func TestAuditLogRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditLogRepoTestSuite))
+}
+
+func (s *AuditLogRepoTestSuite) TestFind() {
+	from := time.Date(2025, 7, 1, 1, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, logs []*auditEntity.AuditLog, err error)
+	}{
+		{
+			name:     "newest first with id tie-break",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "created_at", Order: "desc"},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{4, 3, 2, 1}, auditLogIDs(logs))
+				assert.Equal(t, auditEntity.Changes{"name": {Old: "old", New: "new"}}, logs[0].Changes)
+			},
+		},
+		{
+			name:     "of entity",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "created_at", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				auditEntity.AuditLogOfEntity("course", 1),
+			},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2}, auditLogIDs(logs))
+			},
+		},
+		{
+			name:     "of entity type",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "created_at", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				auditEntity.AuditLogOfEntity("course", 0),
+			},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2, 3}, auditLogIDs(logs))
+			},
+		},
+		{
+			name:     "by actor",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "created_at", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				auditEntity.AuditLogByActor(99),
+			},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 3}, auditLogIDs(logs))
+				assert.EqualValues(t, 99, *logs[0].ActorID)
+			},
+		},
+		{
+			name:     "between, to is exclusive",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "created_at", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				auditEntity.AuditLogBetween(from, from.Add(time.Hour)),
+			},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 3}, auditLogIDs(logs))
+			},
+		},
+		{
+			name:     "pagination",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 3, Sort: "created_at", Order: "desc"},
+			assertFunc: func(t *testing.T, logs []*auditEntity.AuditLog, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, auditLogIDs(logs))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			logs, err := s.auditLogRepo.Find(context.Background(), test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), logs, err)
+		})
+	}
+}
+
+func auditLogIDs(logs []*auditEntity.AuditLog) []uint {
+	ids := make([]uint, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.ID)
+	}
+	return ids
+}
@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	auditEntity "github.com/itmrchow/course-management-system/internal/domain/audit/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// AuditLogRepository 定義稽核紀錄存取的介面
+// 稽核紀錄由 audit plugin 於 entity 變更時寫入 , 此介面只提供查詢
+//
+// Example:
+//
+//	var repo AuditLogRepository
+//	logs, err := repo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{auditEntity.AuditLogOfEntity("course", 1)})
+type AuditLogRepository interface {
+	// Find 取得稽核紀錄清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 稽核紀錄切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*auditEntity.AuditLog, error)
+}
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"os"
+	"testing"
+
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// TestMain 初始化 repository 測試環境
+// repo 測試呼叫 testutil.Main , 測試結束後停止 embedded postgres
+func TestMain(m *testing.M) {
+	code := testutil.Main(m)
+
+	os.Exit(code)
+}
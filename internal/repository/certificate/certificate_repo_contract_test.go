@@ -0,0 +1,124 @@
+package certificate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// CertificateRepoContractSuite CertificateRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type CertificateRepoContractSuite struct {
+	suite.Suite
+	newRepo         func(t *testing.T) CertificateRepository
+	certificateRepo CertificateRepository
+	now             time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並發放課程 1 學生 10 的證書 AAAA-BBBB-CCCC
+func (s *CertificateRepoContractSuite) SetupTest() {
+	s.certificateRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.certificateRepo.Create(context.Background(), s.newCertificate("AAAA-BBBB-CCCC", 1, 10))
+	s.Require().NoError(err)
+}
+
+// TestCertificateRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestCertificateRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &CertificateRepoContractSuite{newRepo: func(t *testing.T) CertificateRepository {
+			return NewCertificateRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &CertificateRepoContractSuite{newRepo: func(t *testing.T) CertificateRepository {
+			return NewCertificateMemoryRepository()
+		}})
+	})
+}
+
+func (s *CertificateRepoContractSuite) newCertificate(code string, courseID, studentID uint) *entity.Certificate {
+	return &entity.Certificate{
+		Code:           code,
+		CourseID:       courseID,
+		StudentID:      studentID,
+		EnrollmentID:   studentID,
+		CourseName:     "資料庫設計",
+		TeacherName:    "王小明",
+		AttendanceRate: 0.9,
+		IssuedAt:       s.now,
+	}
+}
+
+func (s *CertificateRepoContractSuite) TestCreate() {
+	ctx := context.Background()
+
+	certificate, err := s.certificateRepo.GetByCode(ctx, "AAAA-BBBB-CCCC")
+	s.Require().NoError(err)
+	s.EqualValues(10, certificate.StudentID)
+	s.Equal("王小明", certificate.TeacherName)
+	s.Equal(entity.CertificateStatusValid, certificate.Status)
+
+	_, err = s.certificateRepo.GetByCode(ctx, "AAAA-BBBB-DDDD")
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	// 學生已有該課程的證書
+	_, err = s.certificateRepo.Create(ctx, s.newCertificate("AAAA-BBBB-DDDD", 1, 10))
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	// 驗證碼重複
+	_, err = s.certificateRepo.Create(ctx, s.newCertificate("AAAA-BBBB-CCCC", 1, 11))
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+}
+
+func (s *CertificateRepoContractSuite) TestFind() {
+	ctx := context.Background()
+	_, err := s.certificateRepo.Create(ctx, s.newCertificate("AAAA-BBBB-DDDD", 1, 11))
+	s.Require().NoError(err)
+	_, err = s.certificateRepo.Create(ctx, s.newCertificate("AAAA-BBBB-EEEE", 2, 10))
+	s.Require().NoError(err)
+
+	pageInfo := &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}
+	certificates, err := s.certificateRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{entity.CertificateOfCourse(1)})
+	s.Require().NoError(err)
+	s.Require().Len(certificates, 2)
+	s.EqualValues(11, certificates[1].StudentID)
+
+	certificates, err = s.certificateRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{entity.CertificateOfStudent(10)})
+	s.Require().NoError(err)
+	s.Require().Len(certificates, 2)
+	s.EqualValues(2, certificates[1].CourseID)
+}
+
+func (s *CertificateRepoContractSuite) TestRevoke() {
+	ctx := context.Background()
+
+	affected, err := s.certificateRepo.Revoke(ctx, 1, "出席紀錄有誤", s.now.Add(time.Hour))
+	s.Require().NoError(err)
+	s.EqualValues(1, affected)
+
+	certificate, err := s.certificateRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal(entity.CertificateStatusRevoked, certificate.Status)
+	s.Equal("出席紀錄有誤", certificate.RevokeReason)
+	s.Require().NotNil(certificate.RevokedAt)
+	s.True(certificate.RevokedAt.Equal(s.now.Add(time.Hour)))
+
+	// 已撤銷
+	affected, err = s.certificateRepo.Revoke(ctx, 1, "重複撤銷", s.now)
+	s.Require().NoError(err)
+	s.Zero(affected)
+
+	// 不存在
+	affected, err = s.certificateRepo.Revoke(ctx, 100, "不存在", s.now)
+	s.Require().NoError(err)
+	s.Zero(affected)
+}
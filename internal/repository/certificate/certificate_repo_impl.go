@@ -0,0 +1,98 @@
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ CertificateRepository = (*CertificateRepositoryImpl)(nil)
+
+// CertificateRepositoryImpl 實作 CertificateRepository 介面
+//
+// Example:
+//
+//	repo := certificate.NewCertificateRepository(db)
+//	certificate, err := repo.GetByCode(ctx, "7K3M-Q9TX-2HNB")
+type CertificateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCertificateRepository 建立結業證書資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 結業證書資料庫操作實例
+func NewCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &CertificateRepositoryImpl{db: db}
+}
+
+// Create 新增證書
+func (r *CertificateRepositoryImpl) Create(ctx context.Context, certificate *entity.Certificate) (uint, error) {
+	defer metrics.ObserveRepoQuery("certificate", "Create", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(certificate).Error; err != nil {
+		return 0, err
+	}
+	return certificate.ID, nil
+}
+
+// GetByID 依證書ID查詢證書
+func (r *CertificateRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Certificate, error) {
+	defer metrics.ObserveRepoQuery("certificate", "GetByID", time.Now())
+
+	var certificate entity.Certificate
+	if err := repo.Conn(ctx, r.db).First(&certificate, id).Error; err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// GetByCode 依驗證碼查詢證書
+func (r *CertificateRepositoryImpl) GetByCode(ctx context.Context, code string) (*entity.Certificate, error) {
+	defer metrics.ObserveRepoQuery("certificate", "GetByCode", time.Now())
+
+	var certificate entity.Certificate
+	if err := repo.Conn(ctx, r.db).Where("code = ?", code).First(&certificate).Error; err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// Find 查詢證書
+func (r *CertificateRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Certificate, error) {
+	defer metrics.ObserveRepoQuery("certificate", "Find", time.Now())
+
+	var certificates []*entity.Certificate
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&certificates).
+		Error; err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+// Revoke 撤銷證書
+func (r *CertificateRepositoryImpl) Revoke(ctx context.Context, id uint, reason string, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("certificate", "Revoke", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Certificate{}).
+		Where("id = ? AND status = ?", id, entity.CertificateStatusValid).
+		Updates(map[string]interface{}{
+			"status":        entity.CertificateStatusRevoked,
+			"revoked_at":    at.UTC(),
+			"revoke_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
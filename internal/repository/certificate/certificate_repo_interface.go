@@ -0,0 +1,45 @@
+package certificate
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// CertificateRepository 定義結業證書的資料存取介面
+//
+// Example:
+//
+//	var repo CertificateRepository
+//	id, err := repo.Create(ctx, certificate)
+//	certificate, err := repo.GetByCode(ctx, "7K3M-Q9TX-2HNB")
+type CertificateRepository interface {
+	// Create 新增證書
+	// 參數: ctx - context, certificate - 證書
+	// 回傳: 新增後的證書ID, 錯誤訊息 , 學生已有該課程的證書或驗證碼重複時回傳 gorm.ErrDuplicatedKey
+	Create(ctx context.Context, certificate *entity.Certificate) (uint, error)
+
+	// GetByID 依證書ID查詢證書
+	// 參數: ctx - context, id - 證書ID
+	// 回傳: 證書, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Certificate, error)
+
+	// GetByCode 依驗證碼查詢證書
+	// 參數: ctx - context, code - 標準格式的驗證碼
+	// 回傳: 證書, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByCode(ctx context.Context, code string) (*entity.Certificate, error)
+
+	// Find 取得證書清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 證書切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Certificate, error)
+
+	// Revoke 撤銷證書 , 只有狀態為有效時才會更新
+	// 參數: ctx - context, id - 證書ID, reason - 撤銷原因, at - 撤銷時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示證書不存在或已撤銷
+	Revoke(ctx context.Context, id uint, reason string, at time.Time) (int64, error)
+}
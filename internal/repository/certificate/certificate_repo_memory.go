@@ -0,0 +1,78 @@
+package certificate
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ CertificateRepository = (*CertificateMemoryRepository)(nil)
+
+// CertificateMemoryRepository 以記憶體實作 CertificateRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援 transaction
+//
+// Example:
+//
+//	repo := certificate.NewCertificateMemoryRepository()
+//	id, err := repo.Create(ctx, certificate)
+type CertificateMemoryRepository struct {
+	table *memory.Table[entity.Certificate]
+}
+
+// NewCertificateMemoryRepository 建立記憶體結業證書資料操作實例
+// 回傳: 結業證書資料操作實例
+func NewCertificateMemoryRepository() CertificateRepository {
+	return &CertificateMemoryRepository{table: memory.NewTable[entity.Certificate]()}
+}
+
+// Create 新增證書 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *CertificateMemoryRepository) Create(ctx context.Context, certificate *entity.Certificate) (uint, error) {
+	if err := r.table.Create(certificate); err != nil {
+		return 0, err
+	}
+	return certificate.ID, nil
+}
+
+// GetByID 依證書ID查詢證書
+func (r *CertificateMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Certificate, error) {
+	return r.table.Get(id)
+}
+
+// GetByCode 依驗證碼查詢證書
+func (r *CertificateMemoryRepository) GetByCode(ctx context.Context, code string) (*entity.Certificate, error) {
+	certificates, err := r.table.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("code = ?", code) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return certificates[0], nil
+}
+
+// Find 查詢證書
+func (r *CertificateMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Certificate, error) {
+	return r.table.Find(pageInfo, conditions)
+}
+
+// Revoke 撤銷證書
+func (r *CertificateMemoryRepository) Revoke(ctx context.Context, id uint, reason string, at time.Time) (int64, error) {
+	return r.table.Update(id,
+		func(certificate *entity.Certificate) bool {
+			return certificate.Status == entity.CertificateStatusValid
+		},
+		func(certificate *entity.Certificate) {
+			at := at.UTC()
+			certificate.Status = entity.CertificateStatusRevoked
+			certificate.RevokedAt = &at
+			certificate.RevokeReason = reason
+		},
+	)
+}
@@ -0,0 +1,607 @@
+package course
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// CourseRepoContractSuite CourseRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type CourseRepoContractSuite struct {
+	suite.Suite
+	newRepo    func(t *testing.T) CourseRepository
+	courseRepo CourseRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增測試資料
+func (s *CourseRepoContractSuite) SetupTest() {
+	s.courseRepo = s.newRepo(s.T())
+
+	for _, course := range []*courseEntity.Course{
+		newContractCourse("Go 入門", 3000, courseEntity.CourseStatusOnline, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),     // id 1
+		newContractCourse("Go 進階", 5000, courseEntity.CourseStatusDraft, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)),      // id 2
+		newContractCourse("Python 入門", 3000, courseEntity.CourseStatusOnline, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)), // id 3
+		newContractCourse("已刪除課程", 1000, courseEntity.CourseStatusOnline, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),     // id 4 , 已刪除
+	} {
+		_, err := s.courseRepo.Create(context.Background(), course)
+		s.Require().NoError(err)
+	}
+
+	rowsAffected, err := s.courseRepo.Delete(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+}
+
+// TestCourseRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestCourseRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &CourseRepoContractSuite{newRepo: func(t *testing.T) CourseRepository {
+			return NewCourseRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &CourseRepoContractSuite{newRepo: func(t *testing.T) CourseRepository {
+			return NewCourseMemoryRepository()
+		}})
+	})
+}
+
+func (s *CourseRepoContractSuite) TestGetByID() {
+	tests := []struct {
+		name       string
+		id         uint
+		assertFunc func(t *testing.T, course *courseEntity.Course, err error)
+	}{
+		{
+			name: "found",
+			id:   2,
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Go 進階", course.Name)
+				assert.EqualValues(t, 5000, course.Price)
+				assert.True(t, course.RegistrationStartDate.Equal(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)))
+			},
+		},
+		{
+			name: "not found",
+			id:   100,
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, course)
+			},
+		},
+		{
+			name: "deleted",
+			id:   4,
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, course)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			course, err := s.courseRepo.GetByID(context.Background(), test.id)
+			test.assertFunc(s.T(), course, err)
+		})
+	}
+}
+
+func (s *CourseRepoContractSuite) TestLockByID() {
+	course, err := s.courseRepo.LockByID(context.Background(), 2)
+	s.NoError(err)
+	s.Equal("Go 進階", course.Name)
+
+	_, err = s.courseRepo.LockByID(context.Background(), 4)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *CourseRepoContractSuite) TestUpdate() {
+	tests := []struct {
+		name       string
+		course     *courseEntity.Course
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name:   "not found",
+			course: &courseEntity.Course{Model: gorm.Model{ID: 100}, Name: "Not Found"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name:   "deleted",
+			course: &courseEntity.Course{Model: gorm.Model{ID: 4}, Name: "Deleted"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name:   "success",
+			course: &courseEntity.Course{Model: gorm.Model{ID: 1}, Name: "Go 入門 (2025)", Price: 3500},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.courseRepo.Update(context.Background(), test.course)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+
+	// 零值欄位不會被更新
+	course, err := s.courseRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal("Go 入門 (2025)", course.Name)
+	s.EqualValues(3500, course.Price)
+	s.EqualValues(20, course.MaxStudents)
+	s.Equal(courseEntity.CourseStatusOnline, course.Status)
+}
+
+func (s *CourseRepoContractSuite) TestUpdateStatus() {
+	rowsAffected, err := s.courseRepo.UpdateStatus(context.Background(), 2, courseEntity.CourseStatusPending, courseEntity.CourseStatusOnline)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.courseRepo.UpdateStatus(context.Background(), 2, courseEntity.CourseStatusDraft, courseEntity.CourseStatusPending)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	course, err := s.courseRepo.GetByID(context.Background(), 2)
+	s.NoError(err)
+	s.Equal(courseEntity.CourseStatusPending, course.Status)
+}
+
+func (s *CourseRepoContractSuite) TestAddRating() {
+	ctx := context.Background()
+
+	// 課程 3 兩則評價 5 與 4 , 課程 1 一則評價 2 , 課程 2 的評價加入後又移除
+	for _, rating := range []struct {
+		id         uint
+		sum, count int
+	}{
+		{3, 5, 1}, {3, 4, 1}, {1, 2, 1}, {2, 3, 1}, {2, -3, -1},
+	} {
+		rowsAffected, err := s.courseRepo.AddRating(ctx, rating.id, rating.sum, rating.count)
+		s.Require().NoError(err)
+		s.Require().EqualValues(1, rowsAffected)
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, 3)
+	s.Require().NoError(err)
+	s.EqualValues(9, course.RatingSum)
+	s.EqualValues(2, course.RatingCount)
+	s.InDelta(4.5, course.RatingAverage, 0.0001)
+
+	course, err = s.courseRepo.GetByID(ctx, 2)
+	s.Require().NoError(err)
+	s.Zero(course.RatingCount)
+	s.Zero(course.RatingAverage)
+
+	// 依平均評分排序
+	courses, err := s.courseRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "rating_average", Order: "desc"}, nil)
+	s.Require().NoError(err)
+	s.Equal([]uint{3, 1, 2}, courseIDs(courses))
+
+	courses, err = s.courseRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "rating_average", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{courseEntity.MinCourseRating(4)})
+	s.Require().NoError(err)
+	s.Equal([]uint{3}, courseIDs(courses))
+
+	// 已刪除的課程
+	rowsAffected, err := s.courseRepo.AddRating(ctx, 4, 5, 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+}
+
+func (s *CourseRepoContractSuite) TestDelete() {
+	rowsAffected, err := s.courseRepo.Delete(context.Background(), 1)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	rowsAffected, err = s.courseRepo.Delete(context.Background(), 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	_, err = s.courseRepo.GetByID(context.Background(), 1)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *CourseRepoContractSuite) TestRestore() {
+	// 未刪除與不存在的課程不受影響
+	rowsAffected, err := s.courseRepo.Restore(context.Background(), 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.courseRepo.Restore(context.Background(), 100)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.courseRepo.Restore(context.Background(), 4)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	course, err := s.courseRepo.GetByID(context.Background(), 4)
+	s.NoError(err)
+	s.Equal("已刪除課程", course.Name)
+	s.False(course.DeletedAt.Valid)
+}
+
+func (s *CourseRepoContractSuite) TestListDeleted() {
+	_, err := s.courseRepo.Delete(context.Background(), 2)
+	s.Require().NoError(err)
+
+	courses, err := s.courseRepo.ListDeleted(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"})
+	s.NoError(err)
+	s.Equal([]uint{2, 4}, courseIDs(courses))
+}
+
+func (s *CourseRepoContractSuite) TestPurge() {
+	count, err := s.courseRepo.Purge(context.Background(), time.Now().Add(-time.Hour))
+	s.NoError(err)
+	s.Zero(count)
+
+	count, err = s.courseRepo.Purge(context.Background(), time.Now().Add(time.Second))
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	courses, err := s.courseRepo.ListDeleted(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"})
+	s.NoError(err)
+	s.Empty(courses)
+}
+
+func (s *CourseRepoContractSuite) TestFind() {
+	tests := []struct {
+		name       string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, courses []*courseEntity.Course, err error)
+	}{
+		{
+			name:     "excludes deleted",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 2, 1}, courseIDs(courses))
+			},
+		},
+		{
+			name:     "sort by price",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "price", Order: "desc"},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(2), courses[0].ID)
+				assert.Len(t, courses, 3)
+			},
+		},
+		{
+			name:     "pagination",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 2, Sort: "id", Order: "asc"},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, courseIDs(courses))
+			},
+		},
+		{
+			name:     "status and registration window",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB {
+					return db.Where("status = ? AND registration_start_date <= ?", courseEntity.CourseStatusOnline, time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC))
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, courseIDs(courses))
+			},
+		},
+		{
+			name:     "running between",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				courseEntity.RunningBetween(time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC)),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2}, courseIDs(courses))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			courses, err := s.courseRepo.Find(context.Background(), test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), courses, err)
+		})
+	}
+}
+
+func (s *CourseRepoContractSuite) TestPatterns() {
+	ctx := context.Background()
+	for _, pattern := range []*courseEntity.CoursePattern{
+		{CourseID: 1, DayOfWeek: 1, StartTime: time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 7, 21, 13, 0, 0, 0, time.UTC)},
+		{CourseID: 2, DayOfWeek: 2, StartTime: time.Date(2025, 7, 22, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 7, 22, 13, 0, 0, 0, time.UTC)},
+		{CourseID: 1, DayOfWeek: 3, StartTime: time.Date(2025, 7, 23, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 7, 23, 13, 0, 0, 0, time.UTC)},
+	} {
+		id, err := s.courseRepo.CreatePattern(ctx, pattern)
+		s.Require().NoError(err)
+		s.NotZero(id)
+	}
+
+	patterns, err := s.courseRepo.ListPatterns(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(patterns, 2)
+	s.EqualValues(1, patterns[0].DayOfWeek)
+	s.EqualValues(3, patterns[1].DayOfWeek)
+	s.True(patterns[0].StartTime.Equal(time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)))
+
+	patterns, err = s.courseRepo.ListPatterns(ctx, 3)
+	s.Require().NoError(err)
+	s.Empty(patterns)
+
+	// 指定教室
+	for _, id := range []uint{1, 2} {
+		rowsAffected, err := s.courseRepo.UpdatePatternRoom(ctx, id, 7)
+		s.Require().NoError(err)
+		s.EqualValues(1, rowsAffected)
+	}
+	rowsAffected, err := s.courseRepo.UpdatePatternRoom(ctx, 100, 7)
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+
+	patterns, err = s.courseRepo.ListPatternsByRoom(ctx, 7)
+	s.Require().NoError(err)
+	s.Require().Len(patterns, 2)
+	s.EqualValues(1, patterns[0].ID)
+	s.EqualValues(7, patterns[0].RoomID)
+	s.EqualValues(2, patterns[1].CourseID)
+
+	// 已刪除課程的上課時段不列入
+	_, err = s.courseRepo.Delete(ctx, 2)
+	s.Require().NoError(err)
+	patterns, err = s.courseRepo.ListPatternsByRoom(ctx, 7)
+	s.Require().NoError(err)
+	s.Require().Len(patterns, 1)
+	s.EqualValues(1, patterns[0].CourseID)
+
+	// 取消指定
+	_, err = s.courseRepo.UpdatePatternRoom(ctx, 1, 0)
+	s.Require().NoError(err)
+	patterns, err = s.courseRepo.ListPatternsByRoom(ctx, 7)
+	s.Require().NoError(err)
+	s.Empty(patterns)
+}
+
+func (s *CourseRepoContractSuite) TestTeachers() {
+	ctx := context.Background()
+	_, err := s.courseRepo.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 10, IsMain: true})
+	s.Require().NoError(err)
+	_, err = s.courseRepo.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 11})
+	s.Require().NoError(err)
+
+	_, err = s.courseRepo.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 10})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	teachers, err := s.courseRepo.ListTeachers(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(teachers, 2)
+	s.EqualValues(10, teachers[0].TeacherID)
+	s.True(teachers[0].IsMain)
+	s.EqualValues(11, teachers[1].TeacherID)
+}
+
+// newContractCourse 建立測試用課程 , 報名期間兩週 , 開課期間一個月
+func (s *CourseRepoContractSuite) TestPrices() {
+	ctx := context.Background()
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	earlyBirdUntil := from.AddDate(0, 0, 14)
+	for _, price := range []*courseEntity.CoursePrice{
+		{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 300000, Currency: "TWD", EffectiveFrom: from.AddDate(0, 1, 0)},
+		{CourseID: 1, Tier: courseEntity.PriceTierEarlyBird, Amount: 250000, Currency: "TWD", EffectiveFrom: from, ValidUntil: &earlyBirdUntil},
+		{CourseID: 2, Tier: courseEntity.PriceTierRegular, Amount: 9900, Currency: "USD", EffectiveFrom: from},
+		{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 280000, Currency: "TWD", EffectiveFrom: from},
+	} {
+		id, err := s.courseRepo.CreatePrice(ctx, price)
+		s.Require().NoError(err)
+		s.NotZero(id)
+	}
+
+	prices, err := s.courseRepo.ListPrices(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(prices, 3)
+	s.Equal(courseEntity.PriceTierEarlyBird, prices[0].Tier)
+	s.True(prices[0].ValidUntil.Equal(earlyBirdUntil))
+	s.EqualValues(280000, prices[1].Amount)
+	s.EqualValues(300000, prices[2].Amount)
+	s.Equal("TWD", prices[2].Currency)
+
+	prices, err = s.courseRepo.ListPrices(ctx, 3)
+	s.Require().NoError(err)
+	s.Empty(prices)
+}
+
+func (s *CourseRepoContractSuite) TestReplaceRefundRules() {
+	ctx := context.Background()
+	s.Require().NoError(s.courseRepo.ReplaceRefundRules(ctx, 1, []*courseEntity.CourseRefundRule{
+		{Anchor: courseEntity.RefundAnchorRegistrationEnd, Percent: 100},
+		{Anchor: courseEntity.RefundAnchorStart, OffsetHours: -24, Percent: 30},
+	}))
+	s.Require().NoError(s.courseRepo.ReplaceRefundRules(ctx, 2, []*courseEntity.CourseRefundRule{
+		{Anchor: courseEntity.RefundAnchorStart, Percent: 80},
+	}))
+
+	rules, err := s.courseRepo.ListRefundRules(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(rules, 2)
+	s.EqualValues(1, rules[0].CourseID)
+	s.EqualValues(100, rules[0].Percent)
+	s.Equal(courseEntity.RefundAnchorStart, rules[1].Anchor)
+	s.Equal(-24, rules[1].OffsetHours)
+
+	// 取代後只剩新的規則 , 其他課程不受影響
+	s.Require().NoError(s.courseRepo.ReplaceRefundRules(ctx, 1, []*courseEntity.CourseRefundRule{
+		{Anchor: courseEntity.RefundAnchorStart, Percent: 60},
+	}))
+	rules, err = s.courseRepo.ListRefundRules(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Len(rules, 1)
+	s.EqualValues(60, rules[0].Percent)
+
+	s.Require().NoError(s.courseRepo.ReplaceRefundRules(ctx, 1, nil))
+	rules, err = s.courseRepo.ListRefundRules(ctx, 1)
+	s.Require().NoError(err)
+	s.Empty(rules)
+
+	rules, err = s.courseRepo.ListRefundRules(ctx, 2)
+	s.Require().NoError(err)
+	s.Len(rules, 1)
+}
+
+func (s *CourseRepoContractSuite) TestSearch() {
+	_, err := s.courseRepo.Update(context.Background(), &courseEntity.Course{Model: gorm.Model{ID: 2}, Note: "適合 Python 工程師"})
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name       string
+		query      string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error)
+	}{
+		{
+			name:     "name",
+			query:    "go",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 1}, courseHitIDs(hits))
+			},
+		},
+		{
+			// 中文相似度依資料庫 locale 而定 , 只比對結果不比對順序
+			name:     "chinese , excludes deleted",
+			query:    "入門",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, []uint{1, 3}, courseHitIDs(hits))
+			},
+		},
+		{
+			name:     "name ranks above note",
+			query:    "python",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 2}, courseHitIDs(hits))
+				assert.Greater(t, hits[0].Rank, hits[1].Rank)
+			},
+		},
+		{
+			name:     "terms across fields",
+			query:    "description python",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 2}, courseHitIDs(hits))
+			},
+		},
+		{
+			name:     "typo",
+			query:    "pythn",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, courseHitIDs(hits))
+			},
+		},
+		{
+			name:     "pagination",
+			query:    "go",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 1},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, courseHitIDs(hits))
+			},
+		},
+		{
+			name:     "conditions",
+			query:    "go",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Where("status = ?", courseEntity.CourseStatusOnline) },
+			},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, courseHitIDs(hits))
+			},
+		},
+		{
+			name:     "no match",
+			query:    "已刪除",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[courseEntity.Course], err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, hits)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			query, err := search.Parse(test.query)
+			s.Require().NoError(err)
+
+			hits, err := s.courseRepo.Search(context.Background(), query, test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), hits, err)
+		})
+	}
+}
+
+func courseHitIDs(hits []*search.Hit[courseEntity.Course]) []uint {
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Item.ID)
+	}
+	return ids
+}
+
+func newContractCourse(name string, price uint, status courseEntity.CourseStatus, registrationStart time.Time) *courseEntity.Course {
+	return &courseEntity.Course{
+		Name:                  name,
+		Description:           name + " description",
+		Price:                 price,
+		MaxStudents:           20,
+		MinStudents:           5,
+		RegistrationStartDate: registrationStart,
+		RegistrationEndDate:   registrationStart.AddDate(0, 0, 14),
+		StartDate:             registrationStart.AddDate(0, 0, 21),
+		EndDate:               registrationStart.AddDate(0, 1, 21),
+		Status:                status,
+	}
+}
+
+func courseIDs(courses []*courseEntity.Course) []uint {
+	ids := make([]uint, 0, len(courses))
+	for _, course := range courses {
+		ids = append(ids, course.ID)
+	}
+	return ids
+}
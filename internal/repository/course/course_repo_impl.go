@@ -2,23 +2,64 @@ package course
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
 	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
 )
 
+var _ CourseRepository = (*CourseRepositoryImpl)(nil)
+
+// coursePurgeReferences 以 course_id 參照課程的 table , 包含已軟刪除的紀錄 , 有紀錄的課程不永久刪除
+var coursePurgeReferences = []string{"enrollment", `"order"`, "refund", "invoice", "attendance", "certificate", "review", "promotion"}
+
+const (
+	// courseSearchMatch 搜尋條件 , 參數: @tsquery , @text , @pattern
+	courseSearchMatch = "(" + courseEntity.CourseSearchVector + " @@ to_tsquery('simple', @tsquery)" +
+		" OR name ILIKE @pattern OR description ILIKE @pattern OR note ILIKE @pattern OR name % @text)"
+	// courseSearchRank 相關度 , 全文檢索分數 + 名稱相似度 , 名稱包含完整關鍵字時加 1
+	courseSearchRank = "ts_rank(" + courseEntity.CourseSearchVector + ", to_tsquery('simple', @tsquery))" +
+		" + similarity(name, @text) + CASE WHEN name ILIKE @pattern THEN 1 ELSE 0 END"
+)
+
+// courseHit 搜尋查詢的結果列 , 課程資料加上相關度
+type courseHit struct {
+	courseEntity.Course
+	SearchRank float64
+}
+
+// CourseRepositoryImpl 實作 CourseRepository 介面
+// 負責課程資料的存取操作
+//
+// Example:
+//
+//	repo := course.NewCourseRepository(db)
+//	course, err := repo.GetByID(ctx, 1)
 type CourseRepositoryImpl struct {
 	db *gorm.DB
 }
 
+// NewCourseRepository 建立課程資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 課程資料庫操作實例
 func NewCourseRepository(db *gorm.DB) CourseRepository {
 	return &CourseRepositoryImpl{db: db}
 }
 
+// Create 建立課程資料
+// 使用 gorm.Create 建立課程資料
+// 如果建立失敗，返回錯誤
+// 如果建立成功，返回課程ID
 func (r *CourseRepositoryImpl) Create(ctx context.Context, course *courseEntity.Course) (uint, error) {
-	result := r.db.WithContext(ctx).Create(course)
+	defer metrics.ObserveRepoQuery("course", "Create", time.Now())
+
+	result := repo.Conn(ctx, r.db).Create(course)
 	if result.Error != nil {
 		return 0, result.Error
 	}
@@ -26,18 +67,359 @@ func (r *CourseRepositoryImpl) Create(ctx context.Context, course *courseEntity.
 	return course.ID, result.Error
 }
 
+// GetByID 依課程ID查詢課程資料
+// 使用 gorm.First 查詢課程資料
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回課程資料
 func (r *CourseRepositoryImpl) GetByID(ctx context.Context, id uint) (*courseEntity.Course, error) {
-	return nil, nil
+	defer metrics.ObserveRepoQuery("course", "GetByID", time.Now())
+
+	var course courseEntity.Course
+	if err := repo.Conn(ctx, r.db).First(&course, id).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// LockByID 依課程ID查詢課程資料並鎖定
+// 使用 SELECT ... FOR UPDATE , 其他 transaction 鎖定同一門課程時會等待
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回課程資料
+func (r *CourseRepositoryImpl) LockByID(ctx context.Context, id uint) (*courseEntity.Course, error) {
+	defer metrics.ObserveRepoQuery("course", "LockByID", time.Now())
+
+	var course courseEntity.Course
+	if err := repo.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		First(&course, id).
+		Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
 }
 
+// Update 更新課程資料
+// 使用 gorm.Model.Updates 更新課程資料 , 零值欄位不會更新
+// 如果更新失敗，返回錯誤
+// 如果更新成功，返回更新後的課程資料數量
 func (r *CourseRepositoryImpl) Update(ctx context.Context, course *courseEntity.Course) (int64, error) {
-	return 0, nil
+	defer metrics.ObserveRepoQuery("course", "Update", time.Now())
+
+	result := repo.Conn(ctx, r.db).Model(course).Updates(course)
+	return result.RowsAffected, result.Error
+}
+
+// UpdateStatus 更新課程狀態
+// 以 id 與目前狀態 from 為條件更新 , 避免併發操作覆蓋彼此的結果
+// 如果更新失敗，返回錯誤
+// 如果更新成功，返回更新的課程資料數量 , 0 表示課程不存在或狀態已被變更
+func (r *CourseRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to courseEntity.CourseStatus) (int64, error) {
+	defer metrics.ObserveRepoQuery("course", "UpdateStatus", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.Course{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return result.RowsAffected, result.Error
 }
 
+// AddRating 調整課程的評分統計
+// 以 SET rating_sum = rating_sum + ? 累加 , 平均評分以同一筆 UPDATE 中的舊值計算
+func (r *CourseRepositoryImpl) AddRating(ctx context.Context, id uint, sum, count int) (int64, error) {
+	defer metrics.ObserveRepoQuery("course", "AddRating", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.Course{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"rating_sum":     gorm.Expr("rating_sum + ?", sum),
+			"rating_count":   gorm.Expr("rating_count + ?", count),
+			"rating_average": gorm.Expr("CASE WHEN rating_count + ? > 0 THEN (rating_sum + ?)::float8 / (rating_count + ?) ELSE 0 END", count, sum, count),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Delete 刪除課程資料
+// 使用 gorm.Delete 軟刪除課程資料
+// 如果刪除失敗，返回錯誤
+// 如果刪除成功，返回刪除的課程資料數量
 func (r *CourseRepositoryImpl) Delete(ctx context.Context, id uint) (int64, error) {
-	return 0, nil
+	defer metrics.ObserveRepoQuery("course", "Delete", time.Now())
+
+	result := repo.Conn(ctx, r.db).Delete(&courseEntity.Course{}, id)
+	return result.RowsAffected, result.Error
+}
+
+// Restore 還原已刪除的課程資料
+// 以 Unscoped 將 deleted_at 設為 NULL
+// 如果還原失敗，返回錯誤
+// 如果還原成功，返回還原的課程資料數量 , 0 表示課程不存在或未被刪除
+func (r *CourseRepositoryImpl) Restore(ctx context.Context, id uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("course", "Restore", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Unscoped().
+		Model(&courseEntity.Course{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected, result.Error
+}
+
+// ListDeleted 查詢已刪除的課程資料
+// 使用 gorm.Find 查詢已刪除的課程資料
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回課程資料
+func (r *CourseRepositoryImpl) ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*courseEntity.Course, error) {
+	defer metrics.ObserveRepoQuery("course", "ListDeleted", time.Now())
+
+	var courses []*courseEntity.Course
+	if err := repo.Conn(ctx, r.db).
+		Scopes(repo.Paginate(pageInfo), repo.OnlyDeleted).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&courses).
+		Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// Purge 永久刪除課程資料
+// 只刪除刪除時間早於 olderThan 的課程資料 , 並於同一個 transaction 刪除課程的上課時段、授課教師、上課與價格紀錄
+// 課程仍有報名、訂單、發票、點名、證書、評價或促銷紀錄時保留 , 避免這些紀錄參照不存在的課程
+// 如果刪除失敗，返回錯誤
+// 如果刪除成功，返回刪除的課程資料數量
+func (r *CourseRepositoryImpl) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("course", "Purge", time.Now())
+
+	var count int64
+	err := repo.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().
+			Model(&courseEntity.Course{}).
+			Where("deleted_at < ?", olderThan)
+		for _, table := range coursePurgeReferences {
+			query = query.Where("NOT EXISTS (SELECT 1 FROM " + table + " WHERE " + table + ".course_id = course.id)")
+		}
+		var ids []uint
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to list purged courses: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&courseEntity.CoursePattern{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course patterns: %w", err)
+		}
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&courseEntity.CourseTeacher{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course teachers: %w", err)
+		}
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&courseEntity.CourseSession{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course sessions: %w", err)
+		}
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&courseEntity.CoursePrice{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course prices: %w", err)
+		}
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&courseEntity.CourseRefundRule{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course refund rules: %w", err)
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&courseEntity.Course{})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
+// Find 查詢課程資料
+// 使用 gorm.Find 查詢課程資料
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回課程資料
 func (r *CourseRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.Course, error) {
-	return nil, nil
+	defer metrics.ObserveRepoQuery("course", "Find", time.Now())
+
+	var courses []*courseEntity.Course
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&courses).
+		Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// Search 依關鍵字搜尋課程
+// 以 pg_trgm 與 tsvector 的 GIN index 比對 , 依相關度排序後分頁
+func (r *CourseRepositoryImpl) Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[courseEntity.Course], error) {
+	defer metrics.ObserveRepoQuery("course", "Search", time.Now())
+
+	args := map[string]interface{}{"tsquery": query.TSQuery(), "text": query.Text(), "pattern": query.Pattern()}
+
+	var rows []*courseHit
+	if err := repo.Conn(ctx, r.db).
+		Model(&courseEntity.Course{}).
+		Select("*, ("+courseSearchRank+") AS search_rank", args).
+		Where(courseSearchMatch, args).
+		Scopes(conditions...).
+		Scopes(repo.Paginate(pageInfo)).
+		Order("search_rank DESC, id DESC").
+		Find(&rows).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to search courses: %w", err)
+	}
+
+	hits := make([]*search.Hit[courseEntity.Course], 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, &search.Hit[courseEntity.Course]{Item: &row.Course, Rank: row.SearchRank})
+	}
+	return hits, nil
+}
+
+// CreatePattern 新增課程的上課時段
+func (r *CourseRepositoryImpl) CreatePattern(ctx context.Context, pattern *courseEntity.CoursePattern) (uint, error) {
+	defer metrics.ObserveRepoQuery("course", "CreatePattern", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(pattern).Error; err != nil {
+		return 0, err
+	}
+	return pattern.ID, nil
+}
+
+// ListPatterns 查詢課程的上課時段 , 依 id 排序
+func (r *CourseRepositoryImpl) ListPatterns(ctx context.Context, courseID uint) ([]*courseEntity.CoursePattern, error) {
+	defer metrics.ObserveRepoQuery("course", "ListPatterns", time.Now())
+
+	var patterns []*courseEntity.CoursePattern
+	if err := repo.Conn(ctx, r.db).
+		Where("course_id = ?", courseID).
+		Order("id").
+		Find(&patterns).
+		Error; err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// ListPatternsByRoom 查詢指定教室的上課時段 , 依 id 排序 , 以 join 排除已刪除的課程
+func (r *CourseRepositoryImpl) ListPatternsByRoom(ctx context.Context, roomID uint) ([]*courseEntity.CoursePattern, error) {
+	defer metrics.ObserveRepoQuery("course", "ListPatternsByRoom", time.Now())
+
+	var patterns []*courseEntity.CoursePattern
+	if err := repo.Conn(ctx, r.db).
+		Joins("JOIN courses ON courses.id = course_patterns.course_id AND courses.deleted_at IS NULL").
+		Where("course_patterns.room_id = ?", roomID).
+		Order("course_patterns.id").
+		Find(&patterns).
+		Error; err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// UpdatePatternRoom 指定上課時段的教室
+func (r *CourseRepositoryImpl) UpdatePatternRoom(ctx context.Context, id, roomID uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("course", "UpdatePatternRoom", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.CoursePattern{}).
+		Where("id = ?", id).
+		Update("room_id", roomID)
+	return result.RowsAffected, result.Error
+}
+
+// AddTeacher 新增課程的授課教師
+func (r *CourseRepositoryImpl) AddTeacher(ctx context.Context, courseTeacher *courseEntity.CourseTeacher) (uint, error) {
+	defer metrics.ObserveRepoQuery("course", "AddTeacher", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(courseTeacher).Error; err != nil {
+		return 0, err
+	}
+	return courseTeacher.ID, nil
+}
+
+// ListTeachers 查詢課程的授課教師 , 依 id 排序
+func (r *CourseRepositoryImpl) ListTeachers(ctx context.Context, courseID uint) ([]*courseEntity.CourseTeacher, error) {
+	defer metrics.ObserveRepoQuery("course", "ListTeachers", time.Now())
+
+	var teachers []*courseEntity.CourseTeacher
+	if err := repo.Conn(ctx, r.db).
+		Where("course_id = ?", courseID).
+		Order("id").
+		Find(&teachers).
+		Error; err != nil {
+		return nil, err
+	}
+	return teachers, nil
+}
+
+// CreatePrice 新增課程的價格紀錄
+func (r *CourseRepositoryImpl) CreatePrice(ctx context.Context, price *courseEntity.CoursePrice) (uint, error) {
+	defer metrics.ObserveRepoQuery("course", "CreatePrice", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(price).Error; err != nil {
+		return 0, err
+	}
+	return price.ID, nil
+}
+
+// ListPrices 查詢課程的所有價格紀錄 , 依生效時間與 id 排序
+func (r *CourseRepositoryImpl) ListPrices(ctx context.Context, courseID uint) ([]*courseEntity.CoursePrice, error) {
+	defer metrics.ObserveRepoQuery("course", "ListPrices", time.Now())
+
+	var prices []*courseEntity.CoursePrice
+	if err := repo.Conn(ctx, r.db).
+		Where("course_id = ?", courseID).
+		Order("effective_from, id").
+		Find(&prices).
+		Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// ListRefundRules 查詢課程的退款規則 , 依 id 排序
+func (r *CourseRepositoryImpl) ListRefundRules(ctx context.Context, courseID uint) ([]*courseEntity.CourseRefundRule, error) {
+	defer metrics.ObserveRepoQuery("course", "ListRefundRules", time.Now())
+
+	var rules []*courseEntity.CourseRefundRule
+	if err := repo.Conn(ctx, r.db).
+		Where("course_id = ?", courseID).
+		Order("id").
+		Find(&rules).
+		Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReplaceRefundRules 以 rules 取代課程的退款規則
+// 舊規則直接刪除 , 退款紀錄已保存計算時的比例 , 不需要保留舊規則
+func (r *CourseRepositoryImpl) ReplaceRefundRules(ctx context.Context, courseID uint, rules []*courseEntity.CourseRefundRule) error {
+	defer metrics.ObserveRepoQuery("course", "ReplaceRefundRules", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+	if err := db.Unscoped().Where("course_id = ?", courseID).Delete(&courseEntity.CourseRefundRule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete refund rules of course %d: %w", courseID, err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	for _, rule := range rules {
+		rule.CourseID = courseID
+	}
+	if err := db.Create(&rules).Error; err != nil {
+		return fmt.Errorf("failed to create refund rules of course %d: %w", courseID, err)
+	}
+	return nil
 }
@@ -2,11 +2,13 @@ package course
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
 	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
 	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
 )
 
 // CourseRepository 定義課程資料存取的介面
@@ -27,18 +29,105 @@ type CourseRepository interface {
 	// 回傳: 課程實體, 錯誤訊息
 	GetByID(ctx context.Context, id uint) (*courseEntity.Course, error)
 
+	// LockByID 依課程ID查詢課程資料並鎖定 (SELECT ... FOR UPDATE) , 直到 transaction 結束
+	// 須於 repository.Transactor 的 transaction 中呼叫 , 用於報名人數等需要序列化的檢查
+	// 參數: ctx - context, id - 課程ID
+	// 回傳: 課程實體, 錯誤訊息
+	LockByID(ctx context.Context, id uint) (*courseEntity.Course, error)
+
 	// Update 更新課程資料
 	// 參數: ctx - context, course - 課程實體
 	// 回傳: 影響數量, 錯誤訊息
 	Update(ctx context.Context, course *courseEntity.Course) (int64, error)
 
-	// Delete 刪除課程資料
+	// UpdateStatus 更新課程狀態 , 只有目前狀態為 from 時才會更新
+	// 參數: ctx - context, id - 課程ID, from - 目前狀態, to - 新狀態
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示課程不存在或狀態已被變更
+	UpdateStatus(ctx context.Context, id uint, from, to courseEntity.CourseStatus) (int64, error)
+
+	// AddRating 調整課程的評分統計並重新計算平均評分 , 以資料庫現有值累加 , 併發呼叫不會覆蓋彼此的結果
+	// 參數: ctx - context, id - 課程ID, sum - 評分總和的增減, count - 評價數量的增減
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示課程不存在
+	AddRating(ctx context.Context, id uint, sum, count int) (int64, error)
+
+	// Delete 軟刪除課程資料 , 可透過 Restore 還原
 	// 參數: ctx - context, id - 課程ID
 	// 回傳: 影響數量, 錯誤訊息
 	Delete(ctx context.Context, id uint) (int64, error)
 
+	// Restore 還原已刪除的課程資料
+	// 參數: ctx - context, id - 課程ID
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示課程不存在或未被刪除
+	Restore(ctx context.Context, id uint) (int64, error)
+
+	// ListDeleted 取得已刪除的課程清單（可加分頁）
+	// 參數: ctx - context, pageInfo - 分頁資訊
+	// 回傳: 課程實體切片, 錯誤訊息
+	ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*courseEntity.Course, error)
+
+	// Purge 永久刪除刪除時間早於 olderThan 的課程資料 , 仍有報名、訂單等紀錄參照的課程保留不刪除
+	// 參數: ctx - context, olderThan - 刪除時間上限
+	// 回傳: 刪除數量, 錯誤訊息
+	Purge(ctx context.Context, olderThan time.Time) (int64, error)
+
 	// Find 取得課程清單（可加分頁、條件查詢）
 	// 參數: ctx - context
 	// 回傳: 課程實體切片, 錯誤訊息
 	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.Course, error)
+
+	// Search 依關鍵字搜尋課程名稱、描述與備註 , 依相關度由高到低排序 , 相關度相同時依 ID 由新到舊
+	// 符合任一條件即列入結果: 全文檢索前綴比對 , 任一欄位包含完整關鍵字 (不分大小寫) , 名稱 trigram 相似 (容許錯字)
+	// 參數: ctx - context, query - 查詢, pageInfo - 分頁資訊 (不使用排序欄位), conditions - 額外查詢條件
+	// 回傳: 搜尋結果, 錯誤訊息
+	Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[courseEntity.Course], error)
+
+	// CreatePattern 新增課程的上課時段
+	// 參數: ctx - context, pattern - 上課時段 , DayOfWeek 與時間皆為 UTC
+	// 回傳: 新增後的上課時段ID, 錯誤訊息
+	CreatePattern(ctx context.Context, pattern *courseEntity.CoursePattern) (uint, error)
+
+	// ListPatterns 查詢課程的上課時段
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 上課時段 , 依 id 排序, 錯誤訊息
+	ListPatterns(ctx context.Context, courseID uint) ([]*courseEntity.CoursePattern, error)
+
+	// ListPatternsByRoom 查詢指定教室的上課時段 , 不包含已刪除課程的上課時段
+	// 參數: ctx - context, roomID - 教室ID
+	// 回傳: 上課時段 , 依 id 排序, 錯誤訊息
+	ListPatternsByRoom(ctx context.Context, roomID uint) ([]*courseEntity.CoursePattern, error)
+
+	// UpdatePatternRoom 指定上課時段的教室
+	// 參數: ctx - context, id - 上課時段ID, roomID - 教室ID , 0 表示取消指定
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示上課時段不存在
+	UpdatePatternRoom(ctx context.Context, id, roomID uint) (int64, error)
+
+	// AddTeacher 新增課程的授課教師
+	// 參數: ctx - context, courseTeacher - 授課教師
+	// 回傳: 新增後的ID, 錯誤訊息 , 教師已在授課教師中時回傳 gorm.ErrDuplicatedKey
+	AddTeacher(ctx context.Context, courseTeacher *courseEntity.CourseTeacher) (uint, error)
+
+	// ListTeachers 查詢課程的授課教師
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 授課教師 , 依 id 排序, 錯誤訊息
+	ListTeachers(ctx context.Context, courseID uint) ([]*courseEntity.CourseTeacher, error)
+
+	// CreatePrice 新增課程的價格紀錄 , 價格紀錄只新增不修改
+	// 參數: ctx - context, price - 價格紀錄
+	// 回傳: 新增後的價格紀錄ID, 錯誤訊息
+	CreatePrice(ctx context.Context, price *courseEntity.CoursePrice) (uint, error)
+
+	// ListPrices 查詢課程的所有價格紀錄
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 價格紀錄 , 依生效時間與 id 排序, 錯誤訊息
+	ListPrices(ctx context.Context, courseID uint) ([]*courseEntity.CoursePrice, error)
+
+	// ListRefundRules 查詢課程的退款規則
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 退款規則 , 依 id 排序, 錯誤訊息
+	ListRefundRules(ctx context.Context, courseID uint) ([]*courseEntity.CourseRefundRule, error)
+
+	// ReplaceRefundRules 以 rules 取代課程的退款規則 , 須於 transaction 中呼叫
+	// 參數: ctx - context, courseID - 課程ID, rules - 新的退款規則 , 空切片表示使用預設規則
+	// 回傳: 錯誤訊息
+	ReplaceRefundRules(ctx context.Context, courseID uint, rules []*courseEntity.CourseRefundRule) error
 }
@@ -0,0 +1,254 @@
+package course
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+	"github.com/itmrchow/course-management-system/internal/search"
+)
+
+var _ CourseRepository = (*CourseMemoryRepository)(nil)
+
+// CourseMemoryRepository 以記憶體實作 CourseRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫
+// 與 CourseRepositoryImpl 相同 , 支援軟刪除、分頁與 gorm scope 查詢條件
+//
+// Example:
+//
+//	repo := course.NewCourseMemoryRepository()
+//	id, err := repo.Create(ctx, &courseEntity.Course{Name: "Go 入門"})
+type CourseMemoryRepository struct {
+	table    *memory.Table[courseEntity.Course]
+	patterns *memory.Table[courseEntity.CoursePattern]
+	teachers *memory.Table[courseEntity.CourseTeacher]
+	prices   *memory.Table[courseEntity.CoursePrice]
+	refunds  *memory.Table[courseEntity.CourseRefundRule]
+}
+
+// NewCourseMemoryRepository 建立記憶體課程資料操作實例
+// 回傳: 課程資料操作實例
+func NewCourseMemoryRepository() CourseRepository {
+	return &CourseMemoryRepository{
+		table:    memory.NewTable[courseEntity.Course](),
+		patterns: memory.NewTable[courseEntity.CoursePattern](),
+		teachers: memory.NewTable[courseEntity.CourseTeacher](),
+		prices:   memory.NewTable[courseEntity.CoursePrice](),
+		refunds:  memory.NewTable[courseEntity.CourseRefundRule](),
+	}
+}
+
+// Create 建立課程資料
+func (r *CourseMemoryRepository) Create(ctx context.Context, course *courseEntity.Course) (uint, error) {
+	if err := r.table.Create(course); err != nil {
+		return 0, err
+	}
+	return course.ID, nil
+}
+
+// GetByID 依課程ID查詢課程資料
+// 不存在或已刪除時回傳 gorm.ErrRecordNotFound
+func (r *CourseMemoryRepository) GetByID(ctx context.Context, id uint) (*courseEntity.Course, error) {
+	return r.table.Get(id)
+}
+
+// LockByID 依課程ID查詢課程資料 , 記憶體實作不支援 transaction , 與 GetByID 相同
+func (r *CourseMemoryRepository) LockByID(ctx context.Context, id uint) (*courseEntity.Course, error) {
+	return r.table.Get(id)
+}
+
+// Update 更新課程資料 , 零值欄位不會更新
+func (r *CourseMemoryRepository) Update(ctx context.Context, course *courseEntity.Course) (int64, error) {
+	return r.table.Updates(course)
+}
+
+// UpdateStatus 更新課程狀態 , 只有目前狀態為 from 時才會更新
+func (r *CourseMemoryRepository) UpdateStatus(ctx context.Context, id uint, from, to courseEntity.CourseStatus) (int64, error) {
+	return r.table.Update(id,
+		func(course *courseEntity.Course) bool { return course.Status == from },
+		func(course *courseEntity.Course) { course.Status = to },
+	)
+}
+
+// AddRating 調整課程的評分統計
+func (r *CourseMemoryRepository) AddRating(ctx context.Context, id uint, sum, count int) (int64, error) {
+	return r.table.Update(id, nil, func(course *courseEntity.Course) {
+		course.RatingSum = uint(int(course.RatingSum) + sum)
+		course.RatingCount = uint(int(course.RatingCount) + count)
+		course.RatingAverage = 0
+		if course.RatingCount > 0 {
+			course.RatingAverage = float64(course.RatingSum) / float64(course.RatingCount)
+		}
+	})
+}
+
+// Delete 軟刪除課程資料
+func (r *CourseMemoryRepository) Delete(ctx context.Context, id uint) (int64, error) {
+	return r.table.Delete(id)
+}
+
+// Restore 還原已刪除的課程資料
+func (r *CourseMemoryRepository) Restore(ctx context.Context, id uint) (int64, error) {
+	return r.table.Restore(id)
+}
+
+// ListDeleted 查詢已刪除的課程資料
+func (r *CourseMemoryRepository) ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*courseEntity.Course, error) {
+	return r.table.Find(pageInfo, []func(db *gorm.DB) *gorm.DB{repo.OnlyDeleted})
+}
+
+// Purge 永久刪除刪除時間早於 olderThan 的課程資料
+// 記憶體實作不保存報名、訂單等紀錄 , 不檢查課程是否仍被參照
+func (r *CourseMemoryRepository) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	return r.table.Purge(olderThan), nil
+}
+
+// Find 查詢課程資料 , 條件與 CourseRepositoryImpl 相同以 gorm scope 表示
+func (r *CourseMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.Course, error) {
+	return r.table.Find(pageInfo, conditions)
+}
+
+// Search 依關鍵字搜尋課程
+// 比對條件同 CourseRepositoryImpl , 相關度以 search.Query.TermRank 近似 ts_rank
+func (r *CourseMemoryRepository) Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[courseEntity.Course], error) {
+	courses, err := r.table.Where(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []*search.Hit[courseEntity.Course]
+	for _, course := range courses {
+		similarity := search.Similarity(course.Name, query.Text())
+		if !query.MatchTerms(course.Name, course.Description, course.Note) &&
+			!query.In(course.Name) && !query.In(course.Description) && !query.In(course.Note) &&
+			similarity < search.SimilarityThreshold {
+			continue
+		}
+
+		rank := query.TermRank(
+			search.Field{Text: course.Name, Weight: 1},
+			search.Field{Text: course.Description, Weight: 0.4},
+			search.Field{Text: course.Note, Weight: 0.2},
+		) + similarity
+		if query.In(course.Name) {
+			rank++
+		}
+		hits = append(hits, &search.Hit[courseEntity.Course]{Item: course, Rank: rank})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Item.ID > hits[j].Item.ID
+	})
+	return memory.Paginate(hits, pageInfo), nil
+}
+
+// CreatePattern 新增課程的上課時段
+func (r *CourseMemoryRepository) CreatePattern(ctx context.Context, pattern *courseEntity.CoursePattern) (uint, error) {
+	if err := r.patterns.Create(pattern); err != nil {
+		return 0, err
+	}
+	return pattern.ID, nil
+}
+
+// ListPatterns 查詢課程的上課時段 , 依 id 排序
+func (r *CourseMemoryRepository) ListPatterns(ctx context.Context, courseID uint) ([]*courseEntity.CoursePattern, error) {
+	return r.patterns.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("course_id = ?", courseID) },
+	})
+}
+
+// ListPatternsByRoom 查詢指定教室的上課時段 , 依 id 排序 , 不包含已刪除課程的上課時段
+func (r *CourseMemoryRepository) ListPatternsByRoom(ctx context.Context, roomID uint) ([]*courseEntity.CoursePattern, error) {
+	patterns, err := r.patterns.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("room_id = ?", roomID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*courseEntity.CoursePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := r.table.Get(pattern.CourseID); err == nil {
+			result = append(result, pattern)
+		}
+	}
+	return result, nil
+}
+
+// UpdatePatternRoom 指定上課時段的教室
+func (r *CourseMemoryRepository) UpdatePatternRoom(ctx context.Context, id, roomID uint) (int64, error) {
+	return r.patterns.Update(id, nil, func(pattern *courseEntity.CoursePattern) { pattern.RoomID = roomID })
+}
+
+// AddTeacher 新增課程的授課教師
+func (r *CourseMemoryRepository) AddTeacher(ctx context.Context, courseTeacher *courseEntity.CourseTeacher) (uint, error) {
+	if err := r.teachers.Create(courseTeacher); err != nil {
+		return 0, err
+	}
+	return courseTeacher.ID, nil
+}
+
+// ListTeachers 查詢課程的授課教師 , 依 id 排序
+func (r *CourseMemoryRepository) ListTeachers(ctx context.Context, courseID uint) ([]*courseEntity.CourseTeacher, error) {
+	return r.teachers.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("course_id = ?", courseID) },
+	})
+}
+
+// CreatePrice 新增課程的價格紀錄
+func (r *CourseMemoryRepository) CreatePrice(ctx context.Context, price *courseEntity.CoursePrice) (uint, error) {
+	if err := r.prices.Create(price); err != nil {
+		return 0, err
+	}
+	return price.ID, nil
+}
+
+// ListPrices 查詢課程的所有價格紀錄 , 依生效時間與 id 排序
+func (r *CourseMemoryRepository) ListPrices(ctx context.Context, courseID uint) ([]*courseEntity.CoursePrice, error) {
+	prices, err := r.prices.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("course_id = ?", courseID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].EffectiveFrom.Before(prices[j].EffectiveFrom)
+	})
+	return prices, nil
+}
+
+// ListRefundRules 查詢課程的退款規則 , 依 id 排序
+func (r *CourseMemoryRepository) ListRefundRules(ctx context.Context, courseID uint) ([]*courseEntity.CourseRefundRule, error) {
+	return r.refunds.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("course_id = ?", courseID) },
+	})
+}
+
+// ReplaceRefundRules 以 rules 取代課程的退款規則
+func (r *CourseMemoryRepository) ReplaceRefundRules(ctx context.Context, courseID uint, rules []*courseEntity.CourseRefundRule) error {
+	existing, err := r.ListRefundRules(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range existing {
+		if _, err := r.refunds.Delete(rule.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		rule.CourseID = courseID
+		if err := r.refunds.Create(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,17 +2,18 @@ package course
 
 import (
 	"context"
-	"os"
 	"testing"
 	"time"
 
-	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
-	"github.com/itmrchow/course-management-system/internal/config"
 	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
 )
 
 type CourseRepoTestSuite struct {
@@ -21,26 +22,14 @@ type CourseRepoTestSuite struct {
 	db         *gorm.DB
 }
 
+// SetupTest 於每個測試案例前執行 , 每個測試案例使用獨立的資料庫 , 並載入 fixture
+// fixture 包含課程、上課時段與授課教師 , 所有時間皆為 UTC
 func (s *CourseRepoTestSuite) SetupTest() {
-	// logger
-	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
-
 	// db
-	db := config.NewMemoryDB(context.Background(), &logger)
-
-	// sqlDB, err := db.DB()
-	// s.Require().NoError(err)
+	db := testutil.NewDB(s.T())
 
 	// fixture
-	// dir, _ := os.Getwd()
-	// fixtures, err := testfixtures.New(
-	// 	testfixtures.Database(sqlDB), // 傳入 *sql.DB
-	// 	testfixtures.Dialect("postgres"),
-	// 	testfixtures.Directory(filepath.Join(dir, "../../repository/teacher/testdata")),
-	// 	testfixtures.DangerousSkipTestDatabaseCheck(),
-	// )
-	// s.Require().NoError(err)
-	// s.Require().NoError(fixtures.Load())
+	testutil.LoadFixtures(s.T(), db, "testdata")
 
 	s.db = db
 	s.courseRepo = NewCourseRepository(db)
@@ -96,3 +85,637 @@ func (s *CourseRepoTestSuite) TestCreate() {
 		})
 	}
 }
+
+// 依課程ID查詢課程資料測試
+// Test for CourseRepositoryImpl.GetByID
+func (s *CourseRepoTestSuite) TestGetByID() {
+	type args struct {
+		ctx context.Context
+		id  uint
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, course *courseEntity.Course, err error)
+	}{
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Go 入門", course.Name)
+				assert.Equal(t, "從零開始學習 Go 語言", course.Description)
+				assert.EqualValues(t, 3000, course.Price)
+				assert.EqualValues(t, 30, course.MaxStudents)
+				assert.EqualValues(t, 5, course.MinStudents)
+				assert.True(t, course.IsOnline)
+				assert.Equal(t, courseEntity.CourseStatusOnline, course.Status)
+				assert.True(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC).Equal(course.RegistrationStartDate))
+				assert.True(t, time.Date(2025, 7, 14, 23, 59, 59, 0, time.UTC).Equal(course.RegistrationEndDate))
+			},
+		},
+		{
+			name: "not found",
+			args: args{
+				ctx: context.Background(),
+				id:  100,
+			},
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, course)
+			},
+		},
+		{
+			name: "deleted",
+			args: args{
+				ctx: context.Background(),
+				id:  8,
+			},
+			assertFunc: func(t *testing.T, course *courseEntity.Course, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, course)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			course, err := s.courseRepo.GetByID(test.args.ctx, test.args.id)
+			test.assertFunc(s.T(), course, err)
+		})
+	}
+}
+
+// 更新課程資料測試
+// Test for CourseRepositoryImpl.Update
+func (s *CourseRepoTestSuite) TestUpdate() {
+	type args struct {
+		ctx    context.Context
+		course *courseEntity.Course
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "not found",
+			args: args{
+				ctx:    context.Background(),
+				course: &courseEntity.Course{Model: gorm.Model{ID: 100}, Name: "Not Found"},
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "deleted",
+			args: args{
+				ctx:    context.Background(),
+				course: &courseEntity.Course{Model: gorm.Model{ID: 8}, Name: "Deleted"},
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				course: &courseEntity.Course{
+					Model:               gorm.Model{ID: 2},
+					Name:                "Go 進階 (第二梯)",
+					Price:               5500,
+					MaxStudents:         25,
+					RegistrationEndDate: time.Date(2025, 8, 17, 23, 59, 59, 0, time.UTC),
+					Note:                "延長報名",
+				},
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+
+				// 取得更新後的資料做驗證 , 零值欄位不會被更新
+				updated, getErr := s.courseRepo.GetByID(context.Background(), 2)
+				assert.NoError(t, getErr)
+				assert.Equal(t, "Go 進階 (第二梯)", updated.Name)
+				assert.EqualValues(t, 5500, updated.Price)
+				assert.EqualValues(t, 25, updated.MaxStudents)
+				assert.EqualValues(t, 5, updated.MinStudents)
+				assert.Equal(t, "延長報名", updated.Note)
+				assert.Equal(t, "Go 併發與效能調校", updated.Description)
+				assert.True(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC).Equal(updated.RegistrationStartDate))
+				assert.True(t, time.Date(2025, 8, 17, 23, 59, 59, 0, time.UTC).Equal(updated.RegistrationEndDate))
+				assert.True(t, updated.IsOnline)
+				assert.True(t, updated.UpdatedAt.After(time.Date(2025, 6, 1, 0, 0, 1, 0, time.UTC)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.courseRepo.Update(test.args.ctx, test.args.course)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+}
+
+// 更新課程狀態測試
+// Test for CourseRepositoryImpl.UpdateStatus
+func (s *CourseRepoTestSuite) TestUpdateStatus() {
+	type args struct {
+		ctx      context.Context
+		id       uint
+		from, to courseEntity.CourseStatus
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "status changed",
+			args: args{
+				ctx:  context.Background(),
+				id:   4,
+				from: courseEntity.CourseStatusDraft,
+				to:   courseEntity.CourseStatusPending,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "deleted",
+			args: args{
+				ctx:  context.Background(),
+				id:   8,
+				from: courseEntity.CourseStatusOnline,
+				to:   courseEntity.CourseStatusPause,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx:  context.Background(),
+				id:   4,
+				from: courseEntity.CourseStatusPending,
+				to:   courseEntity.CourseStatusOnline,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+
+				updated, getErr := s.courseRepo.GetByID(context.Background(), 4)
+				assert.NoError(t, getErr)
+				assert.Equal(t, courseEntity.CourseStatusOnline, updated.Status)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.courseRepo.UpdateStatus(test.args.ctx, test.args.id, test.args.from, test.args.to)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+}
+
+// 刪除課程資料測試
+// Test for CourseRepositoryImpl.Delete
+func (s *CourseRepoTestSuite) TestDelete() {
+	type args struct {
+		ctx context.Context
+		id  uint
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "not found",
+			args: args{
+				ctx: context.Background(),
+				id:  100,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "already deleted",
+			args: args{
+				ctx: context.Background(),
+				id:  8,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 0, rowsAffected)
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+
+				// 軟刪除 , 一般查詢取不到 , Unscoped 仍保留資料
+				_, getErr := s.courseRepo.GetByID(context.Background(), 1)
+				assert.ErrorIs(t, getErr, gorm.ErrRecordNotFound)
+
+				var deleted courseEntity.Course
+				assert.NoError(t, s.db.Unscoped().First(&deleted, 1).Error)
+				assert.True(t, deleted.DeletedAt.Valid)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.courseRepo.Delete(test.args.ctx, test.args.id)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+}
+
+// 取得課程清單測試
+// Test for CourseRepositoryImpl.Find
+func (s *CourseRepoTestSuite) TestList() {
+	type args struct {
+		ctx        context.Context
+		pageInfo   *repository.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+	}
+
+	pageInfo := func(page, pageSize int, sort, order string) *repository.RepoPageInfo {
+		return &repository.RepoPageInfo{Page: page, PageSize: pageSize, Sort: sort, Order: order}
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, courses []*courseEntity.Course, err error)
+	}{
+		{
+			name: "success_excludes_deleted",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "desc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{7, 6, 5, 4, 3, 2, 1}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_page_size",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(2, 3, "id", "desc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{4, 3, 2}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_last_page",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(3, 3, "id", "desc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_page_out_of_range",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(4, 3, "id", "desc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, courses)
+			},
+		},
+		{
+			name: "success_sort_by_price",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 3, "price", "desc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 4}, courseIDs(courses)[:2])
+				assert.EqualValues(t, 3000, courses[2].Price)
+			},
+		},
+		{
+			name: "success_sort_by_registration_start_date",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 2, "registration_start_date", "asc"),
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				// 課程 3 的報名開始時間為 2025-06-30 16:00 UTC , 早於課程 1 的 2025-07-01 00:00 UTC
+				assert.Equal(t, []uint{5, 3}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_like_name",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.LikeCourseName("入門"),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 3}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_in_status",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.InCourseStatus([]courseEntity.CourseStatus{courseEntity.CourseStatusDraft, courseEntity.CourseStatusPending}),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 4}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_online_and_status",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.IsOnlineCourse(true),
+					courseEntity.InCourseStatus([]courseEntity.CourseStatus{courseEntity.CourseStatusOnline}),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_taught_by",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.TaughtBy(6),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				// 教師 6 與課程 4 的關聯已刪除
+				assert.Equal(t, []uint{1, 2, 3}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_on_day_of_week",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.OnDayOfWeek(time.Monday),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 4}, courseIDs(courses))
+			},
+		},
+		{
+			name: "success_deleted_pattern",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					courseEntity.OnDayOfWeek(time.Friday),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, courses)
+			},
+		},
+		{
+			name: "success_unscoped",
+			args: args{
+				ctx:      context.Background(),
+				pageInfo: pageInfo(1, 10, "id", "asc"),
+				conditions: []func(db *gorm.DB) *gorm.DB{
+					func(db *gorm.DB) *gorm.DB { return db.Unscoped() },
+					courseEntity.LikeCourseName("Go"),
+				},
+			},
+			assertFunc: func(t *testing.T, courses []*courseEntity.Course, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2, 8}, courseIDs(courses))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			courses, err := s.courseRepo.Find(test.args.ctx, test.args.pageInfo, test.args.conditions)
+			test.assertFunc(s.T(), courses, err)
+		})
+	}
+}
+
+// 報名期間查詢測試 , 報名期間以 UTC 儲存 , 邊界時間皆包含在內
+// Test for courseEntity.RegistrationOpenAt
+func (s *CourseRepoTestSuite) TestRegistrationOpenAt() {
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected []uint
+	}{
+		{
+			name:     "before any window",
+			at:       time.Date(2025, 6, 30, 15, 59, 59, 0, time.UTC),
+			expected: []uint{},
+		},
+		{
+			name:     "start boundary in UTC+8 is previous day in UTC",
+			at:       time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC),
+			expected: []uint{3},
+		},
+		{
+			name:     "same instant in UTC+8",
+			at:       time.Date(2025, 7, 1, 0, 0, 0, 0, taipei),
+			expected: []uint{3},
+		},
+		{
+			name:     "start boundary in UTC",
+			at:       time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			expected: []uint{1, 3, 6},
+		},
+		{
+			name:     "end boundary in UTC",
+			at:       time.Date(2025, 7, 14, 23, 59, 59, 0, time.UTC),
+			expected: []uint{1, 3, 6},
+		},
+		{
+			name:     "after end boundary in UTC",
+			at:       time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC),
+			expected: []uint{3, 6},
+		},
+		{
+			name:     "end boundary in UTC+8",
+			at:       time.Date(2025, 7, 15, 23, 59, 59, 0, taipei),
+			expected: []uint{3, 6},
+		},
+		{
+			name:     "after end boundary in UTC+8",
+			at:       time.Date(2025, 7, 16, 0, 0, 0, 0, taipei),
+			expected: []uint{6},
+		},
+		{
+			name:     "before new year in UTC",
+			at:       time.Date(2025, 12, 31, 15, 59, 59, 0, time.UTC),
+			expected: []uint{},
+		},
+		{
+			name:     "new year in UTC+8 is still previous year in UTC",
+			at:       time.Date(2026, 1, 1, 0, 0, 0, 0, taipei),
+			expected: []uint{7},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			courses, err := s.courseRepo.Find(context.Background(),
+				&repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+				[]func(db *gorm.DB) *gorm.DB{courseEntity.RegistrationOpenAt(test.at)},
+			)
+			s.NoError(err)
+			s.Equal(test.expected, courseIDs(courses))
+		})
+	}
+}
+
+// 非 UTC 時區的時間寫入後 , 讀回的時間點與期間長度不變
+// Test for CourseRepositoryImpl.Create / GetByID
+func (s *CourseRepoTestSuite) TestCreateWithTimeZone() {
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+
+	course := &courseEntity.Course{
+		Name:                  "跨日課程",
+		Description:           "報名期間跨越 UTC 日期",
+		Price:                 1000,
+		MaxStudents:           10,
+		MinStudents:           1,
+		RegistrationStartDate: time.Date(2025, 10, 1, 0, 0, 0, 0, taipei),
+		RegistrationEndDate:   time.Date(2025, 10, 31, 23, 59, 59, 0, taipei),
+		StartDate:             time.Date(2025, 11, 3, 7, 30, 0, 0, taipei),
+		EndDate:               time.Date(2025, 12, 29, 9, 30, 0, 0, taipei),
+		Status:                courseEntity.CourseStatusDraft,
+	}
+
+	courseID, err := s.courseRepo.Create(context.Background(), course)
+	s.Require().NoError(err)
+
+	created, err := s.courseRepo.GetByID(context.Background(), courseID)
+	s.Require().NoError(err)
+	s.True(course.RegistrationStartDate.Equal(created.RegistrationStartDate))
+	s.True(course.RegistrationEndDate.Equal(created.RegistrationEndDate))
+	s.True(course.StartDate.Equal(created.StartDate))
+	s.True(course.EndDate.Equal(created.EndDate))
+	s.Equal("2025-09-30T16:00:00Z", created.RegistrationStartDate.UTC().Format(time.RFC3339))
+	s.Equal("2025-10-31T15:59:59Z", created.RegistrationEndDate.UTC().Format(time.RFC3339))
+	s.Equal(course.RegistrationEndDate.Sub(course.RegistrationStartDate), created.RegistrationEndDate.Sub(created.RegistrationStartDate))
+}
+
+// 永久刪除課程測試 , 課程的上課時段與授課教師一併刪除
+// Test for CourseRepositoryImpl.Purge
+func (s *CourseRepoTestSuite) TestPurge() {
+	// 課程 8 於 2025-06-02 刪除
+	count, err := s.courseRepo.Purge(context.Background(), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.Zero(count)
+
+	count, err = s.courseRepo.Purge(context.Background(), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	var remaining int64
+	s.NoError(s.db.Unscoped().Model(&courseEntity.Course{}).Where("id = ?", 8).Count(&remaining).Error)
+	s.Zero(remaining)
+	s.NoError(s.db.Unscoped().Model(&courseEntity.CoursePattern{}).Where("course_id = ?", 8).Count(&remaining).Error)
+	s.Zero(remaining)
+	s.NoError(s.db.Unscoped().Model(&courseEntity.CourseTeacher{}).Where("course_id = ?", 8).Count(&remaining).Error)
+	s.Zero(remaining)
+
+	// 其他課程的上課時段不受影響 , 包含已刪除的時段
+	s.NoError(s.db.Unscoped().Model(&courseEntity.CoursePattern{}).Where("course_id = ?", 1).Count(&remaining).Error)
+	s.EqualValues(3, remaining)
+}
+
+// 永久刪除課程測試 , 課程仍有報名或訂單紀錄時保留 , 包含已軟刪除的紀錄
+// Test for CourseRepositoryImpl.Purge
+func (s *CourseRepoTestSuite) TestPurgeKeepsReferenced() {
+	tests := []struct {
+		name   string
+		record interface{}
+	}{
+		{
+			name:   "enrollment",
+			record: &enrollmentEntity.Enrollment{CourseID: 8, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusWithdrawn, EnrolledAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "order",
+			record: &orderEntity.Order{EnrollmentID: 1, CourseID: 8, StudentID: 10, Amount: 1000, Currency: "TWD", Status: orderEntity.OrderStatusRefunded, ExpiresAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Valid: true}}},
+		},
+	}
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.SetupTest()
+			s.Require().NoError(s.db.Create(test.record).Error)
+
+			count, err := s.courseRepo.Purge(context.Background(), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+			s.NoError(err)
+			s.Zero(count)
+
+			var remaining int64
+			s.NoError(s.db.Unscoped().Model(&courseEntity.Course{}).Where("id = ?", 8).Count(&remaining).Error)
+			s.EqualValues(1, remaining)
+			s.NoError(s.db.Unscoped().Model(&courseEntity.CourseTeacher{}).Where("course_id = ?", 8).Count(&remaining).Error)
+			s.EqualValues(1, remaining)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package enrollment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// EnrollmentRepoContractSuite EnrollmentRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type EnrollmentRepoContractSuite struct {
+	suite.Suite
+	newRepo        func(t *testing.T) EnrollmentRepository
+	enrollmentRepo EnrollmentRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增測試資料
+func (s *EnrollmentRepoContractSuite) SetupTest() {
+	s.enrollmentRepo = s.newRepo(s.T())
+
+	enrolledAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	for _, enrollment := range []*entity.Enrollment{
+		{CourseID: 1, StudentID: 10, EnrolledAt: enrolledAt}, // id 1
+		{CourseID: 1, StudentID: 11, EnrolledAt: enrolledAt}, // id 2
+		{CourseID: 2, StudentID: 10, EnrolledAt: enrolledAt}, // id 3
+	} {
+		_, err := s.enrollmentRepo.Create(context.Background(), enrollment)
+		s.Require().NoError(err)
+	}
+}
+
+// TestEnrollmentRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestEnrollmentRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &EnrollmentRepoContractSuite{newRepo: func(t *testing.T) EnrollmentRepository {
+			return NewEnrollmentRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &EnrollmentRepoContractSuite{newRepo: func(t *testing.T) EnrollmentRepository {
+			return NewEnrollmentMemoryRepository()
+		}})
+	})
+}
+
+func (s *EnrollmentRepoContractSuite) TestCreate() {
+	tests := []struct {
+		name       string
+		enrollment *entity.Enrollment
+		assertFunc func(t *testing.T, id uint, err error)
+	}{
+		{
+			name:       "duplicate course and student",
+			enrollment: &entity.Enrollment{CourseID: 1, StudentID: 10, EnrolledAt: time.Now()},
+			assertFunc: func(t *testing.T, id uint, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, id)
+			},
+		},
+		{
+			name:       "success",
+			enrollment: &entity.Enrollment{CourseID: 2, StudentID: 11, EnrolledAt: time.Now()},
+			assertFunc: func(t *testing.T, id uint, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, id)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			id, err := s.enrollmentRepo.Create(context.Background(), test.enrollment)
+			test.assertFunc(s.T(), id, err)
+		})
+	}
+}
+
+func (s *EnrollmentRepoContractSuite) TestGetByCourseAndStudent() {
+	enrollment, err := s.enrollmentRepo.GetByCourseAndStudent(context.Background(), 1, 11)
+	s.NoError(err)
+	s.EqualValues(2, enrollment.ID)
+
+	_, err = s.enrollmentRepo.GetByCourseAndStudent(context.Background(), 2, 11)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *EnrollmentRepoContractSuite) TestUpdateStatus() {
+	rowsAffected, err := s.enrollmentRepo.UpdateStatus(context.Background(), 1, entity.EnrollmentStatusWithdrawn, entity.EnrollmentStatusEnrolled)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.enrollmentRepo.UpdateStatus(context.Background(), 1, entity.EnrollmentStatusEnrolled, entity.EnrollmentStatusWithdrawn)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	enrollment, err := s.enrollmentRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.EnrollmentStatusWithdrawn, enrollment.Status)
+	s.NotNil(enrollment.WithdrawnAt)
+
+	// 再次報名時清除退出時間
+	rowsAffected, err = s.enrollmentRepo.UpdateStatus(context.Background(), 1, entity.EnrollmentStatusWithdrawn, entity.EnrollmentStatusEnrolled)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	enrollment, err = s.enrollmentRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.EnrollmentStatusEnrolled, enrollment.Status)
+	s.Nil(enrollment.WithdrawnAt)
+}
+
+func (s *EnrollmentRepoContractSuite) TestCount() {
+	_, err := s.enrollmentRepo.UpdateStatus(context.Background(), 2, entity.EnrollmentStatusEnrolled, entity.EnrollmentStatusWithdrawn)
+	s.Require().NoError(err)
+
+	count, err := s.enrollmentRepo.Count(context.Background(), []func(db *gorm.DB) *gorm.DB{
+		entity.EnrollmentOfCourse(1),
+		entity.InEnrollmentStatus([]entity.EnrollmentStatus{entity.EnrollmentStatusEnrolled}),
+	})
+	s.NoError(err)
+	s.EqualValues(1, count)
+}
+
+func (s *EnrollmentRepoContractSuite) TestFind() {
+	enrollments, err := s.enrollmentRepo.Find(context.Background(),
+		&repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.EnrollmentOfStudent(10)},
+	)
+	s.NoError(err)
+	s.Require().Len(enrollments, 2)
+	s.EqualValues(3, enrollments[0].ID)
+	s.EqualValues(1, enrollments[1].ID)
+}
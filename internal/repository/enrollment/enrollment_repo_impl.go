@@ -0,0 +1,130 @@
+package enrollment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ EnrollmentRepository = (*EnrollmentRepositoryImpl)(nil)
+
+// EnrollmentRepositoryImpl 實作 EnrollmentRepository 介面
+//
+// Example:
+//
+//	repo := enrollment.NewEnrollmentRepository(db)
+//	enrollment, err := repo.GetByID(ctx, 1)
+type EnrollmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewEnrollmentRepository 建立報名資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 報名資料庫操作實例
+func NewEnrollmentRepository(db *gorm.DB) EnrollmentRepository {
+	return &EnrollmentRepositoryImpl{db: db}
+}
+
+// Create 建立報名資料
+func (r *EnrollmentRepositoryImpl) Create(ctx context.Context, enrollment *entity.Enrollment) (uint, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "Create", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(enrollment).Error; err != nil {
+		return 0, err
+	}
+	return enrollment.ID, nil
+}
+
+// GetByID 依報名ID查詢報名資料
+func (r *EnrollmentRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Enrollment, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "GetByID", time.Now())
+
+	var enrollment entity.Enrollment
+	if err := repo.Conn(ctx, r.db).First(&enrollment, id).Error; err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// GetByCourseAndStudent 查詢學生在課程的報名資料
+func (r *EnrollmentRepositoryImpl) GetByCourseAndStudent(ctx context.Context, courseID, studentID uint) (*entity.Enrollment, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "GetByCourseAndStudent", time.Now())
+
+	var enrollment entity.Enrollment
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.EnrollmentOfCourse(courseID), entity.EnrollmentOfStudent(studentID)).
+		First(&enrollment).
+		Error; err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// Update 更新報名資料 , 零值欄位不會更新
+func (r *EnrollmentRepositoryImpl) Update(ctx context.Context, enrollment *entity.Enrollment) (int64, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "Update", time.Now())
+
+	result := repo.Conn(ctx, r.db).Model(enrollment).Updates(enrollment)
+	return result.RowsAffected, result.Error
+}
+
+// UpdateStatus 更新報名狀態 , 以目前狀態為條件避免併發覆蓋
+// 變更為已退出時記錄退出時間 , 變更為已報名時更新報名時間並清除退出時間
+func (r *EnrollmentRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to entity.EnrollmentStatus) (int64, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "UpdateStatus", time.Now())
+
+	values := map[string]interface{}{"status": to}
+	if to == entity.EnrollmentStatusWithdrawn {
+		values["withdrawn_at"] = time.Now()
+	}
+	if to == entity.EnrollmentStatusEnrolled || to == entity.EnrollmentStatusPendingPayment {
+		values["enrolled_at"] = time.Now()
+		values["withdrawn_at"] = nil
+	}
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Enrollment{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(values)
+	return result.RowsAffected, result.Error
+}
+
+// Count 計算符合條件的報名數量
+func (r *EnrollmentRepositoryImpl) Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) (int64, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "Count", time.Now())
+
+	var count int64
+	if err := repo.Conn(ctx, r.db).
+		Model(&entity.Enrollment{}).
+		Scopes(conditions...).
+		Count(&count).
+		Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Find 查詢報名資料
+func (r *EnrollmentRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Enrollment, error) {
+	defer metrics.ObserveRepoQuery("enrollment", "Find", time.Now())
+
+	var enrollments []*entity.Enrollment
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&enrollments).
+		Error; err != nil {
+		return nil, err
+	}
+	return enrollments, nil
+}
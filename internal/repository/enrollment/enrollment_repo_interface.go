@@ -0,0 +1,55 @@
+package enrollment
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// EnrollmentRepository 定義報名資料存取的介面
+// 負責報名的新增、查詢與狀態變更
+//
+// Example:
+//
+//	var repo EnrollmentRepository
+//	enrollment, err := repo.GetByID(ctx, 1)
+type EnrollmentRepository interface {
+	// Create 新增報名資料
+	// 參數: ctx - context, enrollment - 報名實體
+	// 回傳: 新增後的報名ID, 錯誤訊息 , 同一個學生重複報名同一門課程時回傳 gorm.ErrDuplicatedKey
+	Create(ctx context.Context, enrollment *entity.Enrollment) (uint, error)
+
+	// GetByID 依報名ID查詢報名資料
+	// 參數: ctx - context, id - 報名ID
+	// 回傳: 報名實體, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Enrollment, error)
+
+	// GetByCourseAndStudent 查詢學生在課程的報名資料 , 包含已退出
+	// 參數: ctx - context, courseID - 課程ID, studentID - 學生 user ID
+	// 回傳: 報名實體, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByCourseAndStudent(ctx context.Context, courseID, studentID uint) (*entity.Enrollment, error)
+
+	// Update 更新報名資料
+	// 參數: ctx - context, enrollment - 報名實體
+	// 回傳: 影響數量, 錯誤訊息
+	Update(ctx context.Context, enrollment *entity.Enrollment) (int64, error)
+
+	// UpdateStatus 更新報名狀態 , 只有目前狀態為 from 時才會更新
+	// 變更為已退出時記錄退出時間 , 變更為已報名或待付款時更新報名時間並清除退出時間
+	// 參數: ctx - context, id - 報名ID, from - 目前狀態, to - 新狀態
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示報名不存在或狀態已被變更
+	UpdateStatus(ctx context.Context, id uint, from, to entity.EnrollmentStatus) (int64, error)
+
+	// Count 計算符合條件的報名數量
+	// 參數: ctx - context, conditions - 查詢條件
+	// 回傳: 數量, 錯誤訊息
+	Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) (int64, error)
+
+	// Find 取得報名清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 報名實體切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Enrollment, error)
+}
@@ -0,0 +1,97 @@
+package enrollment
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ EnrollmentRepository = (*EnrollmentMemoryRepository)(nil)
+
+// EnrollmentMemoryRepository 以記憶體實作 EnrollmentRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫
+//
+// Example:
+//
+//	repo := enrollment.NewEnrollmentMemoryRepository()
+//	id, err := repo.Create(ctx, &entity.Enrollment{CourseID: 1, StudentID: 1})
+type EnrollmentMemoryRepository struct {
+	table *memory.Table[entity.Enrollment]
+}
+
+// NewEnrollmentMemoryRepository 建立記憶體報名資料操作實例
+// 回傳: 報名資料操作實例
+func NewEnrollmentMemoryRepository() EnrollmentRepository {
+	return &EnrollmentMemoryRepository{table: memory.NewTable[entity.Enrollment]()}
+}
+
+// Create 建立報名資料
+// 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *EnrollmentMemoryRepository) Create(ctx context.Context, enrollment *entity.Enrollment) (uint, error) {
+	if err := r.table.Create(enrollment); err != nil {
+		return 0, err
+	}
+	return enrollment.ID, nil
+}
+
+// GetByID 依報名ID查詢報名資料
+func (r *EnrollmentMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Enrollment, error) {
+	return r.table.Get(id)
+}
+
+// GetByCourseAndStudent 查詢學生在課程的報名資料
+func (r *EnrollmentMemoryRepository) GetByCourseAndStudent(ctx context.Context, courseID, studentID uint) (*entity.Enrollment, error) {
+	enrollments, err := r.table.Where([]func(db *gorm.DB) *gorm.DB{
+		entity.EnrollmentOfCourse(courseID),
+		entity.EnrollmentOfStudent(studentID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(enrollments) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return enrollments[0], nil
+}
+
+// Update 更新報名資料 , 零值欄位不會更新
+func (r *EnrollmentMemoryRepository) Update(ctx context.Context, enrollment *entity.Enrollment) (int64, error) {
+	return r.table.Updates(enrollment)
+}
+
+// UpdateStatus 更新報名狀態 , 只有目前狀態為 from 時才會更新
+func (r *EnrollmentMemoryRepository) UpdateStatus(ctx context.Context, id uint, from, to entity.EnrollmentStatus) (int64, error) {
+	return r.table.Update(id,
+		func(enrollment *entity.Enrollment) bool { return enrollment.Status == from },
+		func(enrollment *entity.Enrollment) {
+			enrollment.Status = to
+			if to == entity.EnrollmentStatusWithdrawn {
+				now := time.Now()
+				enrollment.WithdrawnAt = &now
+			}
+			if to == entity.EnrollmentStatusEnrolled || to == entity.EnrollmentStatusPendingPayment {
+				enrollment.EnrolledAt = time.Now()
+				enrollment.WithdrawnAt = nil
+			}
+		},
+	)
+}
+
+// Count 計算符合條件的報名數量
+func (r *EnrollmentMemoryRepository) Count(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) (int64, error) {
+	enrollments, err := r.table.Where(conditions)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(enrollments)), nil
+}
+
+// Find 查詢報名資料 , 條件與 EnrollmentRepositoryImpl 相同以 gorm scope 表示
+func (r *EnrollmentMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Enrollment, error) {
+	return r.table.Find(pageInfo, conditions)
+}
@@ -0,0 +1,129 @@
+package invoice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// InvoiceRepoContractSuite InvoiceRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type InvoiceRepoContractSuite struct {
+	suite.Suite
+	newRepo     func(t *testing.T) InvoiceRepository
+	invoiceRepo InvoiceRepository
+	now         time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並開立訂單 1 的發票 INV-2025-000001 (課程與一筆折扣)
+func (s *InvoiceRepoContractSuite) SetupTest() {
+	s.invoiceRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.invoiceRepo.Create(context.Background(), s.newInvoice(1, 1, 100), []*entity.InvoiceLine{
+		{Position: 2, Kind: entity.InvoiceLineKindDiscount, Description: "暑期九折", Detail: "SUMMER10", Quantity: 1, UnitAmount: 300, Amount: 300},
+		{Position: 1, Kind: entity.InvoiceLineKindCourse, Description: "資料庫設計", Detail: "regular", Quantity: 1, UnitAmount: 3000, Amount: 3000},
+	})
+	s.Require().NoError(err)
+}
+
+// TestInvoiceRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestInvoiceRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &InvoiceRepoContractSuite{newRepo: func(t *testing.T) InvoiceRepository {
+			return NewInvoiceRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &InvoiceRepoContractSuite{newRepo: func(t *testing.T) InvoiceRepository {
+			return NewInvoiceMemoryRepository()
+		}})
+	})
+}
+
+func (s *InvoiceRepoContractSuite) newInvoice(seq, orderID, studentID uint) *entity.Invoice {
+	return &entity.Invoice{
+		Number:       entity.FormatNumber("INV", 2025, seq),
+		Year:         2025,
+		Seq:          seq,
+		OrderID:      orderID,
+		EnrollmentID: orderID,
+		CourseID:     1,
+		StudentID:    studentID,
+		Currency:     "TWD",
+		Subtotal:     3000,
+		Discount:     300,
+		Total:        2700,
+		PaidAt:       s.now,
+		IssuedAt:     s.now,
+	}
+}
+
+// 各年度分別從 1 開始連續編號
+func (s *InvoiceRepoContractSuite) TestNextNumber() {
+	ctx := context.Background()
+
+	for _, want := range []uint{1, 2, 3} {
+		seq, err := s.invoiceRepo.NextNumber(ctx, 2025)
+		s.Require().NoError(err)
+		s.Equal(want, seq)
+	}
+
+	seq, err := s.invoiceRepo.NextNumber(ctx, 2026)
+	s.Require().NoError(err)
+	s.EqualValues(1, seq)
+}
+
+func (s *InvoiceRepoContractSuite) TestCreate() {
+	ctx := context.Background()
+
+	invoice, err := s.invoiceRepo.GetByOrder(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal("INV-2025-000001", invoice.Number)
+	s.EqualValues(2700, invoice.Total)
+
+	lines, err := s.invoiceRepo.ListLines(ctx, invoice.ID)
+	s.Require().NoError(err)
+	s.Require().Len(lines, 2)
+	s.Equal(entity.InvoiceLineKindCourse, lines[0].Kind)
+	s.Equal("SUMMER10", lines[1].Detail)
+
+	_, err = s.invoiceRepo.GetByOrder(ctx, 2)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	// 訂單已開立發票
+	_, err = s.invoiceRepo.Create(ctx, s.newInvoice(2, 1, 100), nil)
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	// 發票號碼重複
+	_, err = s.invoiceRepo.Create(ctx, s.newInvoice(1, 2, 100), nil)
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+}
+
+func (s *InvoiceRepoContractSuite) TestFind() {
+	ctx := context.Background()
+	_, err := s.invoiceRepo.Create(ctx, s.newInvoice(2, 2, 101), nil)
+	s.Require().NoError(err)
+
+	pageInfo := &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"}
+	invoices, err := s.invoiceRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{entity.InvoiceOfYear(2025)})
+	s.Require().NoError(err)
+	s.Require().Len(invoices, 2)
+	s.Equal("INV-2025-000002", invoices[0].Number)
+
+	invoices, err = s.invoiceRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{entity.InvoiceOfStudent(100)})
+	s.Require().NoError(err)
+	s.Require().Len(invoices, 1)
+	s.EqualValues(1, invoices[0].OrderID)
+
+	invoices, err = s.invoiceRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{entity.InvoiceOfYear(2024)})
+	s.NoError(err)
+	s.Empty(invoices)
+}
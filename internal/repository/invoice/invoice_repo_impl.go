@@ -0,0 +1,135 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ InvoiceRepository = (*InvoiceRepositoryImpl)(nil)
+
+// InvoiceRepositoryImpl 實作 InvoiceRepository 介面
+//
+// Example:
+//
+//	repo := invoice.NewInvoiceRepository(db)
+//	invoice, err := repo.GetByOrder(ctx, 1)
+type InvoiceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository 建立發票資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 發票資料庫操作實例
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &InvoiceRepositoryImpl{db: db}
+}
+
+// NextNumber 取得年度的下一個流水號
+// 年度第一次取號時新增紀錄 , 併發新增時以 ON CONFLICT DO NOTHING 略過
+// 之後以 UPDATE ... RETURNING 加一 , UPDATE 鎖定的紀錄於 transaction 結束前不會被其他 transaction 更新
+func (r *InvoiceRepositoryImpl) NextNumber(ctx context.Context, year int) (uint, error) {
+	defer metrics.ObserveRepoQuery("invoice", "NextNumber", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.InvoiceSequence{Year: year}).
+		Error; err != nil {
+		return 0, err
+	}
+
+	var seq entity.InvoiceSequence
+	result := db.Model(&seq).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "last_number"}}}).
+		Where("year = ?", year).
+		Update("last_number", gorm.Expr("last_number + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("invoice sequence of year %d: %w", year, gorm.ErrRecordNotFound)
+	}
+	return seq.LastNumber, nil
+}
+
+// Create 新增發票與明細
+func (r *InvoiceRepositoryImpl) Create(ctx context.Context, invoice *entity.Invoice, lines []*entity.InvoiceLine) (uint, error) {
+	defer metrics.ObserveRepoQuery("invoice", "Create", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+	if err := db.Create(invoice).Error; err != nil {
+		return 0, err
+	}
+	for _, line := range lines {
+		line.InvoiceID = invoice.ID
+	}
+	if len(lines) > 0 {
+		if err := db.Create(&lines).Error; err != nil {
+			return 0, err
+		}
+	}
+	return invoice.ID, nil
+}
+
+// GetByID 依發票ID查詢發票
+func (r *InvoiceRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Invoice, error) {
+	defer metrics.ObserveRepoQuery("invoice", "GetByID", time.Now())
+
+	var invoice entity.Invoice
+	if err := repo.Conn(ctx, r.db).First(&invoice, id).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// GetByOrder 查詢訂單的發票
+func (r *InvoiceRepositoryImpl) GetByOrder(ctx context.Context, orderID uint) (*entity.Invoice, error) {
+	defer metrics.ObserveRepoQuery("invoice", "GetByOrder", time.Now())
+
+	var invoice entity.Invoice
+	if err := repo.Conn(ctx, r.db).Where("order_id = ?", orderID).First(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// Find 查詢發票
+func (r *InvoiceRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Invoice, error) {
+	defer metrics.ObserveRepoQuery("invoice", "Find", time.Now())
+
+	var invoices []*entity.Invoice
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&invoices).
+		Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// ListLines 查詢發票的明細 , 依 Position 排序
+func (r *InvoiceRepositoryImpl) ListLines(ctx context.Context, invoiceID uint) ([]*entity.InvoiceLine, error) {
+	defer metrics.ObserveRepoQuery("invoice", "ListLines", time.Now())
+
+	var lines []*entity.InvoiceLine
+	if err := repo.Conn(ctx, r.db).
+		Where("invoice_id = ?", invoiceID).
+		Order("position").
+		Find(&lines).
+		Error; err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
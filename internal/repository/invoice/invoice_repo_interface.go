@@ -0,0 +1,50 @@
+package invoice
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// InvoiceRepository 定義發票、發票明細與年度流水號的資料存取介面
+//
+// Example:
+//
+//	var repo InvoiceRepository
+//	seq, err := repo.NextNumber(ctx, 2025)
+//	_, err = repo.Create(ctx, invoice, lines)
+type InvoiceRepository interface {
+	// NextNumber 取得年度的下一個流水號 , 須於開立發票的 transaction 中呼叫
+	// 會鎖定該年度的流水號至 transaction 結束 , 併發開立時依序取號 , rollback 時流水號一併退回
+	// 參數: ctx - context, year - 開立年度
+	// 回傳: 流水號 , 從 1 開始, 錯誤訊息
+	NextNumber(ctx context.Context, year int) (uint, error)
+
+	// Create 新增發票與明細
+	// 參數: ctx - context, invoice - 發票, lines - 發票明細 , InvoiceID 於新增時寫入
+	// 回傳: 新增後的發票ID, 錯誤訊息 , 訂單已開立發票時回傳 gorm.ErrDuplicatedKey
+	Create(ctx context.Context, invoice *entity.Invoice, lines []*entity.InvoiceLine) (uint, error)
+
+	// GetByID 依發票ID查詢發票
+	// 參數: ctx - context, id - 發票ID
+	// 回傳: 發票, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Invoice, error)
+
+	// GetByOrder 查詢訂單的發票
+	// 參數: ctx - context, orderID - 訂單ID
+	// 回傳: 發票, 錯誤訊息 , 尚未開立時回傳 gorm.ErrRecordNotFound
+	GetByOrder(ctx context.Context, orderID uint) (*entity.Invoice, error)
+
+	// Find 取得發票清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 發票切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Invoice, error)
+
+	// ListLines 查詢發票的明細
+	// 參數: ctx - context, invoiceID - 發票ID
+	// 回傳: 發票明細 , 依 Position 排序, 錯誤訊息
+	ListLines(ctx context.Context, invoiceID uint) ([]*entity.InvoiceLine, error)
+}
@@ -0,0 +1,99 @@
+package invoice
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ InvoiceRepository = (*InvoiceMemoryRepository)(nil)
+
+// InvoiceMemoryRepository 以記憶體實作 InvoiceRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援 transaction , 取得的流水號不會退回
+//
+// Example:
+//
+//	repo := invoice.NewInvoiceMemoryRepository()
+//	seq, err := repo.NextNumber(ctx, 2025)
+type InvoiceMemoryRepository struct {
+	invoices *memory.Table[entity.Invoice]
+	lines    *memory.Table[entity.InvoiceLine]
+
+	mu        sync.Mutex
+	sequences map[int]uint
+}
+
+// NewInvoiceMemoryRepository 建立記憶體發票資料操作實例
+// 回傳: 發票資料操作實例
+func NewInvoiceMemoryRepository() InvoiceRepository {
+	return &InvoiceMemoryRepository{
+		invoices:  memory.NewTable[entity.Invoice](),
+		lines:     memory.NewTable[entity.InvoiceLine](),
+		sequences: make(map[int]uint),
+	}
+}
+
+// NextNumber 取得年度的下一個流水號
+func (r *InvoiceMemoryRepository) NextNumber(ctx context.Context, year int) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sequences[year]++
+	return r.sequences[year], nil
+}
+
+// Create 新增發票與明細 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *InvoiceMemoryRepository) Create(ctx context.Context, invoice *entity.Invoice, lines []*entity.InvoiceLine) (uint, error) {
+	if err := r.invoices.Create(invoice); err != nil {
+		return 0, err
+	}
+	for _, line := range lines {
+		line.InvoiceID = invoice.ID
+		if err := r.lines.Create(line); err != nil {
+			return 0, err
+		}
+	}
+	return invoice.ID, nil
+}
+
+// GetByID 依發票ID查詢發票
+func (r *InvoiceMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Invoice, error) {
+	return r.invoices.Get(id)
+}
+
+// GetByOrder 查詢訂單的發票
+func (r *InvoiceMemoryRepository) GetByOrder(ctx context.Context, orderID uint) (*entity.Invoice, error) {
+	invoices, err := r.invoices.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("order_id = ?", orderID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return invoices[0], nil
+}
+
+// Find 查詢發票
+func (r *InvoiceMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Invoice, error) {
+	return r.invoices.Find(pageInfo, conditions)
+}
+
+// ListLines 查詢發票的明細 , 依 Position 排序
+func (r *InvoiceMemoryRepository) ListLines(ctx context.Context, invoiceID uint) ([]*entity.InvoiceLine, error) {
+	lines, err := r.lines.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("invoice_id = ?", invoiceID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Position < lines[j].Position })
+	return lines, nil
+}
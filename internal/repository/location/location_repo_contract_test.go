@@ -0,0 +1,135 @@
+package location
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// LocationRepoContractSuite LocationRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type LocationRepoContractSuite struct {
+	suite.Suite
+	newRepo      func(t *testing.T) LocationRepository
+	locationRepo LocationRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增地點 1 台北分校與教室 301 (30 人) 、 302 (12 人)
+func (s *LocationRepoContractSuite) SetupTest() {
+	ctx := context.Background()
+	s.locationRepo = s.newRepo(s.T())
+
+	_, err := s.locationRepo.CreateLocation(ctx, &entity.Location{Name: "台北分校", Address: "台北市中正區重慶南路一段 1 號"})
+	s.Require().NoError(err)
+	for _, room := range []*entity.Room{
+		{LocationID: 1, Name: "301", Capacity: 30, Facilities: entity.Facilities{"projector", "whiteboard"}},
+		{LocationID: 1, Name: "302", Capacity: 12},
+	} {
+		_, err := s.locationRepo.CreateRoom(ctx, room)
+		s.Require().NoError(err)
+	}
+}
+
+// TestLocationRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestLocationRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &LocationRepoContractSuite{newRepo: func(t *testing.T) LocationRepository {
+			return NewLocationRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &LocationRepoContractSuite{newRepo: func(t *testing.T) LocationRepository {
+			return NewLocationMemoryRepository()
+		}})
+	})
+}
+
+func (s *LocationRepoContractSuite) TestLocation() {
+	ctx := context.Background()
+
+	location, err := s.locationRepo.GetLocation(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal("台北分校", location.Name)
+
+	_, err = s.locationRepo.GetLocation(ctx, 100)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	// 名稱重複
+	_, err = s.locationRepo.CreateLocation(ctx, &entity.Location{Name: "台北分校", Address: "台北市大安區"})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	id, err := s.locationRepo.CreateLocation(ctx, &entity.Location{Name: "台中分校", Address: "台中市西區", Note: "近捷運站"})
+	s.Require().NoError(err)
+
+	// 清空備註
+	rowsAffected, err := s.locationRepo.UpdateLocation(ctx, &entity.Location{Model: gorm.Model{ID: id}, Name: "台中教室", Address: "台中市北區"})
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+	location, err = s.locationRepo.GetLocation(ctx, id)
+	s.Require().NoError(err)
+	s.Equal("台中教室", location.Name)
+	s.Equal("台中市北區", location.Address)
+	s.Empty(location.Note)
+
+	_, err = s.locationRepo.UpdateLocation(ctx, &entity.Location{Model: gorm.Model{ID: id}, Name: "台北分校", Address: "台中市北區"})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	locations, err := s.locationRepo.FindLocations(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "name", Order: "asc"}, nil)
+	s.Require().NoError(err)
+	s.Require().Len(locations, 2)
+	s.Equal("台中教室", locations[0].Name)
+}
+
+func (s *LocationRepoContractSuite) TestRoom() {
+	ctx := context.Background()
+
+	room, err := s.locationRepo.GetRoom(ctx, 1)
+	s.Require().NoError(err)
+	s.EqualValues(30, room.Capacity)
+	s.Equal(entity.Facilities{"projector", "whiteboard"}, room.Facilities)
+
+	room, err = s.locationRepo.LockRoom(ctx, 2)
+	s.Require().NoError(err)
+	s.Empty(room.Facilities)
+
+	_, err = s.locationRepo.GetRoom(ctx, 100)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	// 同一地點中名稱重複 , 其他地點可使用相同名稱
+	_, err = s.locationRepo.CreateRoom(ctx, &entity.Room{LocationID: 1, Name: "301", Capacity: 20})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+	_, err = s.locationRepo.CreateRoom(ctx, &entity.Room{LocationID: 2, Name: "301", Capacity: 20})
+	s.Require().NoError(err)
+
+	rowsAffected, err := s.locationRepo.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "301A", Capacity: 40, Facilities: entity.Facilities{}})
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+	room, err = s.locationRepo.GetRoom(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal("301A", room.Name)
+	s.EqualValues(40, room.Capacity)
+	s.Empty(room.Facilities)
+	s.EqualValues(1, room.LocationID)
+
+	_, err = s.locationRepo.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "302", Capacity: 40})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	rooms, err := s.locationRepo.FindRooms(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "capacity", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.RoomOfLocation(1), entity.MinRoomCapacity(12)})
+	s.Require().NoError(err)
+	s.Require().Len(rooms, 2)
+	s.Equal("302", rooms[0].Name)
+	s.Equal("301A", rooms[1].Name)
+
+	rooms, err = s.locationRepo.FindRooms(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.MinRoomCapacity(25)})
+	s.Require().NoError(err)
+	s.Require().Len(rooms, 1)
+	s.EqualValues(1, rooms[0].ID)
+}
@@ -0,0 +1,149 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ LocationRepository = (*LocationRepositoryImpl)(nil)
+
+// LocationRepositoryImpl 實作 LocationRepository 介面
+//
+// Example:
+//
+//	repo := location.NewLocationRepository(db)
+//	room, err := repo.GetRoom(ctx, 1)
+type LocationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLocationRepository 建立上課地點資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 上課地點資料庫操作實例
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &LocationRepositoryImpl{db: db}
+}
+
+// CreateLocation 新增地點
+func (r *LocationRepositoryImpl) CreateLocation(ctx context.Context, location *entity.Location) (uint, error) {
+	defer metrics.ObserveRepoQuery("location", "CreateLocation", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(location).Error; err != nil {
+		return 0, err
+	}
+	return location.ID, nil
+}
+
+// GetLocation 依地點ID查詢地點
+func (r *LocationRepositoryImpl) GetLocation(ctx context.Context, id uint) (*entity.Location, error) {
+	defer metrics.ObserveRepoQuery("location", "GetLocation", time.Now())
+
+	var location entity.Location
+	if err := repo.Conn(ctx, r.db).First(&location, id).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// UpdateLocation 更新地點 , 以 Select 指定欄位 , 清空備註時同樣會更新
+func (r *LocationRepositoryImpl) UpdateLocation(ctx context.Context, location *entity.Location) (int64, error) {
+	defer metrics.ObserveRepoQuery("location", "UpdateLocation", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(location).
+		Select("name", "address", "note").
+		Updates(location)
+	return result.RowsAffected, result.Error
+}
+
+// FindLocations 查詢地點
+func (r *LocationRepositoryImpl) FindLocations(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Location, error) {
+	defer metrics.ObserveRepoQuery("location", "FindLocations", time.Now())
+
+	var locations []*entity.Location
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&locations).
+		Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// CreateRoom 新增教室
+func (r *LocationRepositoryImpl) CreateRoom(ctx context.Context, room *entity.Room) (uint, error) {
+	defer metrics.ObserveRepoQuery("location", "CreateRoom", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(room).Error; err != nil {
+		return 0, err
+	}
+	return room.ID, nil
+}
+
+// GetRoom 依教室ID查詢教室
+func (r *LocationRepositoryImpl) GetRoom(ctx context.Context, id uint) (*entity.Room, error) {
+	defer metrics.ObserveRepoQuery("location", "GetRoom", time.Now())
+
+	var room entity.Room
+	if err := repo.Conn(ctx, r.db).First(&room, id).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// LockRoom 依教室ID查詢教室並鎖定
+func (r *LocationRepositoryImpl) LockRoom(ctx context.Context, id uint) (*entity.Room, error) {
+	defer metrics.ObserveRepoQuery("location", "LockRoom", time.Now())
+
+	var room entity.Room
+	if err := repo.Conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		First(&room, id).
+		Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// UpdateRoom 更新教室 , 以 Select 指定欄位 , 清空設備時同樣會更新
+func (r *LocationRepositoryImpl) UpdateRoom(ctx context.Context, room *entity.Room) (int64, error) {
+	defer metrics.ObserveRepoQuery("location", "UpdateRoom", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(room).
+		Select("name", "capacity", "facilities").
+		Updates(room)
+	return result.RowsAffected, result.Error
+}
+
+// FindRooms 查詢教室
+func (r *LocationRepositoryImpl) FindRooms(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Room, error) {
+	defer metrics.ObserveRepoQuery("location", "FindRooms", time.Now())
+
+	var rooms []*entity.Room
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&rooms).
+		Error; err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
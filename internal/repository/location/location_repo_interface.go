@@ -0,0 +1,65 @@
+package location
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// LocationRepository 定義上課地點與教室的資料存取介面
+//
+// Example:
+//
+//	var repo LocationRepository
+//	locationID, err := repo.CreateLocation(ctx, &entity.Location{Name: "台北分校", Address: "台北市中正區"})
+//	roomID, err := repo.CreateRoom(ctx, &entity.Room{LocationID: locationID, Name: "301", Capacity: 30})
+type LocationRepository interface {
+	// CreateLocation 新增地點
+	// 參數: ctx - context, location - 地點
+	// 回傳: 新增後的地點ID, 錯誤訊息 , 名稱重複時回傳 gorm.ErrDuplicatedKey
+	CreateLocation(ctx context.Context, location *entity.Location) (uint, error)
+
+	// GetLocation 依地點ID查詢地點
+	// 參數: ctx - context, id - 地點ID
+	// 回傳: 地點, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetLocation(ctx context.Context, id uint) (*entity.Location, error)
+
+	// UpdateLocation 更新地點的名稱、地址與備註 , 零值同樣會更新
+	// 參數: ctx - context, location - 地點
+	// 回傳: 影響數量, 錯誤訊息 , 名稱重複時回傳 gorm.ErrDuplicatedKey
+	UpdateLocation(ctx context.Context, location *entity.Location) (int64, error)
+
+	// FindLocations 取得地點清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 地點切片, 錯誤訊息
+	FindLocations(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Location, error)
+
+	// CreateRoom 新增教室
+	// 參數: ctx - context, room - 教室
+	// 回傳: 新增後的教室ID, 錯誤訊息 , 同一地點中名稱重複時回傳 gorm.ErrDuplicatedKey
+	CreateRoom(ctx context.Context, room *entity.Room) (uint, error)
+
+	// GetRoom 依教室ID查詢教室
+	// 參數: ctx - context, id - 教室ID
+	// 回傳: 教室, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetRoom(ctx context.Context, id uint) (*entity.Room, error)
+
+	// LockRoom 依教室ID查詢教室並鎖定 (SELECT ... FOR UPDATE) , 直到 transaction 結束
+	// 須於 repository.Transactor 的 transaction 中呼叫 , 用於序列化同一間教室的指定與容納人數變更
+	// 參數: ctx - context, id - 教室ID
+	// 回傳: 教室, 錯誤訊息
+	LockRoom(ctx context.Context, id uint) (*entity.Room, error)
+
+	// UpdateRoom 更新教室的名稱、容納人數與設備 , 零值同樣會更新
+	// 參數: ctx - context, room - 教室
+	// 回傳: 影響數量, 錯誤訊息 , 同一地點中名稱重複時回傳 gorm.ErrDuplicatedKey
+	UpdateRoom(ctx context.Context, room *entity.Room) (int64, error)
+
+	// FindRooms 取得教室清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 教室切片, 錯誤訊息
+	FindRooms(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Room, error)
+}
@@ -0,0 +1,93 @@
+package location
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ LocationRepository = (*LocationMemoryRepository)(nil)
+
+// LocationMemoryRepository 以記憶體實作 LocationRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援 transaction , LockRoom 不會鎖定
+//
+// Example:
+//
+//	repo := location.NewLocationMemoryRepository()
+//	id, err := repo.CreateRoom(ctx, room)
+type LocationMemoryRepository struct {
+	locations *memory.Table[entity.Location]
+	rooms     *memory.Table[entity.Room]
+}
+
+// NewLocationMemoryRepository 建立記憶體上課地點資料操作實例
+// 回傳: 上課地點資料操作實例
+func NewLocationMemoryRepository() LocationRepository {
+	return &LocationMemoryRepository{
+		locations: memory.NewTable[entity.Location](),
+		rooms:     memory.NewTable[entity.Room](),
+	}
+}
+
+// CreateLocation 新增地點 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *LocationMemoryRepository) CreateLocation(ctx context.Context, location *entity.Location) (uint, error) {
+	if err := r.locations.Create(location); err != nil {
+		return 0, err
+	}
+	return location.ID, nil
+}
+
+// GetLocation 依地點ID查詢地點
+func (r *LocationMemoryRepository) GetLocation(ctx context.Context, id uint) (*entity.Location, error) {
+	return r.locations.Get(id)
+}
+
+// UpdateLocation 更新地點的名稱、地址與備註
+func (r *LocationMemoryRepository) UpdateLocation(ctx context.Context, location *entity.Location) (int64, error) {
+	return r.locations.Update(location.ID, nil, func(row *entity.Location) {
+		row.Name = location.Name
+		row.Address = location.Address
+		row.Note = location.Note
+	})
+}
+
+// FindLocations 查詢地點
+func (r *LocationMemoryRepository) FindLocations(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Location, error) {
+	return r.locations.Find(pageInfo, conditions)
+}
+
+// CreateRoom 新增教室 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *LocationMemoryRepository) CreateRoom(ctx context.Context, room *entity.Room) (uint, error) {
+	if err := r.rooms.Create(room); err != nil {
+		return 0, err
+	}
+	return room.ID, nil
+}
+
+// GetRoom 依教室ID查詢教室
+func (r *LocationMemoryRepository) GetRoom(ctx context.Context, id uint) (*entity.Room, error) {
+	return r.rooms.Get(id)
+}
+
+// LockRoom 依教室ID查詢教室 , 記憶體實作不會鎖定
+func (r *LocationMemoryRepository) LockRoom(ctx context.Context, id uint) (*entity.Room, error) {
+	return r.rooms.Get(id)
+}
+
+// UpdateRoom 更新教室的名稱、容納人數與設備
+func (r *LocationMemoryRepository) UpdateRoom(ctx context.Context, room *entity.Room) (int64, error) {
+	return r.rooms.Update(room.ID, nil, func(row *entity.Room) {
+		row.Name = room.Name
+		row.Capacity = room.Capacity
+		row.Facilities = room.Facilities
+	})
+}
+
+// FindRooms 查詢教室
+func (r *LocationMemoryRepository) FindRooms(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Room, error) {
+	return r.rooms.Find(pageInfo, conditions)
+}
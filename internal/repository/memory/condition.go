@@ -0,0 +1,767 @@
+package memory
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// predicate 判斷一筆資料是否符合條件
+type predicate func(row reflect.Value) (bool, error)
+
+// operand 取得運算元的值 , 欄位或參數
+type operand func(row reflect.Value) (interface{}, error)
+
+// buildPredicate 將 gorm WHERE 子句的 expression 轉為 predicate
+// 支援 gorm 內建的 clause (Eq, IN, AndConditions ...) 與常見的 SQL 字串條件
+// 例如 "name LIKE ?" , "status IN (?)" , "email = ? AND deleted_at IS NULL"
+func buildPredicate(sch *schema.Schema, expr clause.Expression) (predicate, error) {
+	switch e := expr.(type) {
+	case clause.Expr:
+		return parseExpr(sch, e.SQL, e.Vars)
+	case clause.NamedExpr:
+		return nil, fmt.Errorf("memory: named expression is not supported: %s", e.SQL)
+	case clause.Eq:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c == 0 }, true)
+	case clause.Neq:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c != 0 }, false)
+	case clause.Gt:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c > 0 }, false)
+	case clause.Gte:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c >= 0 }, false)
+	case clause.Lt:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c < 0 }, false)
+	case clause.Lte:
+		return compareClause(sch, e.Column, e.Value, func(c int) bool { return c <= 0 }, false)
+	case clause.Like:
+		col, err := columnOperand(sch, e.Column)
+		if err != nil {
+			return nil, err
+		}
+		return likePredicate(col, constOperand(e.Value), false, false), nil
+	case clause.IN:
+		col, err := columnOperand(sch, e.Column)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]operand, 0, len(e.Values))
+		for _, v := range e.Values {
+			values = append(values, constOperand(v))
+		}
+		return inPredicate(col, values, false), nil
+	case clause.AndConditions:
+		return combine(sch, e.Exprs, true)
+	case clause.OrConditions:
+		return combine(sch, e.Exprs, false)
+	case clause.NotConditions:
+		p, err := combine(sch, e.Exprs, true)
+		if err != nil {
+			return nil, err
+		}
+		return not(p), nil
+	case clause.Where:
+		return combine(sch, e.Exprs, true)
+	default:
+		return nil, fmt.Errorf("memory: unsupported condition %T", expr)
+	}
+}
+
+// combine 將多個 expression 以 AND 或 OR 組合
+func combine(sch *schema.Schema, exprs []clause.Expression, and bool) (predicate, error) {
+	preds := make([]predicate, 0, len(exprs))
+	for _, expr := range exprs {
+		p, err := buildPredicate(sch, expr)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, p)
+	}
+
+	return func(row reflect.Value) (bool, error) {
+		for _, p := range preds {
+			ok, err := p(row)
+			if err != nil {
+				return false, err
+			}
+			if and && !ok {
+				return false, nil
+			}
+			if !and && ok {
+				return true, nil
+			}
+		}
+		return and || len(preds) == 0, nil
+	}, nil
+}
+
+func compareClause(sch *schema.Schema, column interface{}, value interface{}, match func(int) bool, nullEq bool) (predicate, error) {
+	col, err := columnOperand(sch, column)
+	if err != nil {
+		return nil, err
+	}
+
+	// clause.Eq{Value: nil} 代表 IS NULL , clause.Eq 的 slice 值代表 IN
+	if value == nil || isNilValue(value) {
+		return isNullPredicate(col, !nullEq), nil
+	}
+	if values, ok := sliceValues(value); ok && nullEq {
+		ops := make([]operand, 0, len(values))
+		for _, v := range values {
+			ops = append(ops, constOperand(v))
+		}
+		return inPredicate(col, ops, false), nil
+	}
+
+	return comparePredicate(col, constOperand(value), match), nil
+}
+
+func not(p predicate) predicate {
+	return func(row reflect.Value) (bool, error) {
+		ok, err := p(row)
+		return !ok && err == nil, err
+	}
+}
+
+func comparePredicate(left, right operand, match func(int) bool) predicate {
+	return func(row reflect.Value) (bool, error) {
+		l, err := left(row)
+		if err != nil {
+			return false, err
+		}
+		r, err := right(row)
+		if err != nil {
+			return false, err
+		}
+		// SQL 中與 NULL 比較的結果不為 true
+		if l == nil || r == nil {
+			return false, nil
+		}
+		c, err := compare(l, r)
+		if err != nil {
+			return false, err
+		}
+		return match(c), nil
+	}
+}
+
+func isNullPredicate(col operand, negate bool) predicate {
+	return func(row reflect.Value) (bool, error) {
+		v, err := col(row)
+		if err != nil {
+			return false, err
+		}
+		return (v == nil) != negate, nil
+	}
+}
+
+func likePredicate(left, pattern operand, caseInsensitive, negate bool) predicate {
+	return func(row reflect.Value) (bool, error) {
+		l, err := left(row)
+		if err != nil {
+			return false, err
+		}
+		p, err := pattern(row)
+		if err != nil {
+			return false, err
+		}
+		if l == nil || p == nil {
+			return false, nil
+		}
+		ls, lok := l.(string)
+		ps, pok := p.(string)
+		if !lok || !pok {
+			return false, fmt.Errorf("memory: LIKE requires string operands, got %T and %T", l, p)
+		}
+		re, err := likeRegexp(ps, caseInsensitive)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(ls) != negate, nil
+	}
+}
+
+func inPredicate(left operand, values []operand, negate bool) predicate {
+	return func(row reflect.Value) (bool, error) {
+		l, err := left(row)
+		if err != nil {
+			return false, err
+		}
+		if l == nil {
+			return false, nil
+		}
+
+		var candidates []interface{}
+		for _, value := range values {
+			v, err := value(row)
+			if err != nil {
+				return false, err
+			}
+			// IN (?) 傳入 slice 時展開
+			if items, ok := sliceValues(v); ok {
+				for _, item := range items {
+					candidates = append(candidates, normalize(item))
+				}
+				continue
+			}
+			candidates = append(candidates, v)
+		}
+
+		for _, candidate := range candidates {
+			if candidate == nil {
+				continue
+			}
+			if c, err := compare(l, candidate); err == nil && c == 0 {
+				return !negate, nil
+			}
+		}
+		return negate, nil
+	}
+}
+
+// columnOperand 取得欄位值的 operand , column 可為 clause.Column 或欄位名稱字串
+func columnOperand(sch *schema.Schema, column interface{}) (operand, error) {
+	var name string
+	switch c := column.(type) {
+	case clause.Column:
+		name = c.Name
+	case string:
+		name = c
+	default:
+		return nil, fmt.Errorf("memory: unsupported column %T", column)
+	}
+
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	name = strings.Trim(name, "\"`")
+
+	field := sch.LookUpField(name)
+	if field == nil {
+		return nil, fmt.Errorf("memory: unknown column %s.%s", sch.Table, name)
+	}
+
+	return func(row reflect.Value) (interface{}, error) {
+		return normalize(field.ReflectValueOf(context.Background(), row).Interface()), nil
+	}, nil
+}
+
+func constOperand(v interface{}) operand {
+	if _, ok := sliceValues(v); !ok {
+		v = normalize(v)
+	}
+	return func(reflect.Value) (interface{}, error) {
+		return v, nil
+	}
+}
+
+// normalize 將值轉為可比較的型別: float64 , string , bool , time.Time 或 nil
+// 實作 driver.Valuer 的型別 (例如 gorm.DeletedAt) 先轉為 driver 值
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil
+		}
+		value, err := valuer.Value()
+		if err != nil || value == nil {
+			return nil
+		}
+		v = value
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if t, ok := rv.Interface().(time.Time); ok {
+		return t
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return rv.Interface()
+}
+
+func isNilValue(v interface{}) bool {
+	return normalize(v) == nil
+}
+
+// sliceValues 回傳 slice 的元素 , []byte 不視為 slice
+func sliceValues(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+// compare 比較兩個 normalize 後的值 , 回傳 -1 , 0 , 1
+func compare(a, b interface{}) (int, error) {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, fmt.Errorf("memory: cannot compare %T with %T", a, b)
+		}
+		switch {
+		case av < bv:
+			return -1, nil
+		case av > bv:
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, fmt.Errorf("memory: cannot compare %T with %T", a, b)
+		}
+		return strings.Compare(av, bv), nil
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, fmt.Errorf("memory: cannot compare %T with %T", a, b)
+		}
+		switch {
+		case av == bv:
+			return 0, nil
+		case !av:
+			return -1, nil
+		}
+		return 1, nil
+	case time.Time:
+		bv, ok := b.(time.Time)
+		if !ok {
+			return 0, fmt.Errorf("memory: cannot compare %T with %T", a, b)
+		}
+		return av.Compare(bv), nil
+	}
+	return 0, fmt.Errorf("memory: unsupported value type %T", a)
+}
+
+// likeRegexp 將 SQL LIKE pattern 轉為正規表示式 , % 為任意字元 , _ 為單一字元
+func likeRegexp(pattern string, caseInsensitive bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if caseInsensitive {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString("(?s:.*)")
+		case r == '_':
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// ---- SQL 字串條件解析 ----
+
+// exprParser 解析 clause.Expr 的 SQL 字串條件
+// 語法: expr := and (OR and)* ; and := unary (AND unary)* ; unary := NOT unary | '(' expr ')' | comparison
+type exprParser struct {
+	sch    *schema.Schema
+	sql    string
+	tokens []string
+	pos    int
+	vars   []interface{}
+	varIdx int
+}
+
+func parseExpr(sch *schema.Schema, sql string, vars []interface{}) (predicate, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{sch: sch, sql: sql, tokens: tokens, vars: vars}
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, p.errorf("unexpected token %q", p.tokens[p.pos])
+	}
+	return pred, nil
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("memory: unsupported condition %q: %s", p.sql, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) peekKeyword(keyword string) bool {
+	return strings.EqualFold(p.peek(), keyword)
+}
+
+func (p *exprParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *exprParser) expect(token string) error {
+	if !strings.EqualFold(p.peek(), token) {
+		return p.errorf("expected %q", token)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *exprParser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	preds := []predicate{left}
+	for p.peekKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, right)
+	}
+	if len(preds) == 1 {
+		return left, nil
+	}
+	return anyOf(preds), nil
+}
+
+func (p *exprParser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	preds := []predicate{left}
+	for p.peekKeyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, right)
+	}
+	if len(preds) == 1 {
+		return left, nil
+	}
+	return allOf(preds), nil
+}
+
+func (p *exprParser) parseUnary() (predicate, error) {
+	if p.peekKeyword("NOT") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not(inner), nil
+	}
+	if p.peek() == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (predicate, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	negate := false
+	if p.peekKeyword("NOT") {
+		p.next()
+		negate = true
+	}
+
+	op := strings.ToUpper(p.next())
+	switch op {
+	case "=", "<>", "!=", ">", ">=", "<", "<=":
+		if negate {
+			return nil, p.errorf("unexpected NOT before %s", op)
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return comparePredicate(left, right, compareMatcher(op)), nil
+	case "LIKE", "ILIKE":
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return likePredicate(left, right, op == "ILIKE", negate), nil
+	case "IN":
+		var values []operand
+		if p.peek() == "(" {
+			p.next()
+			for {
+				value, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+				if p.peek() != "," {
+					break
+				}
+				p.next()
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		} else {
+			value, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return inPredicate(left, values, negate), nil
+	case "IS":
+		isNot := false
+		if p.peekKeyword("NOT") {
+			p.next()
+			isNot = true
+		}
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return isNullPredicate(left, isNot), nil
+	case "BETWEEN":
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		between := allOf([]predicate{
+			comparePredicate(left, low, compareMatcher(">=")),
+			comparePredicate(left, high, compareMatcher("<=")),
+		})
+		if negate {
+			return not(between), nil
+		}
+		return between, nil
+	}
+
+	return nil, p.errorf("unsupported operator %q", op)
+}
+
+func (p *exprParser) parseOperand() (operand, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, p.errorf("unexpected end of condition")
+	case token == "?":
+		if p.varIdx >= len(p.vars) {
+			return nil, p.errorf("not enough vars")
+		}
+		v := p.vars[p.varIdx]
+		p.varIdx++
+		return constOperand(v), nil
+	case strings.HasPrefix(token, "'"):
+		return constOperand(strings.ReplaceAll(strings.Trim(token, "'"), "''", "'")), nil
+	case strings.EqualFold(token, "TRUE"):
+		return constOperand(true), nil
+	case strings.EqualFold(token, "FALSE"):
+		return constOperand(false), nil
+	case strings.EqualFold(token, "NULL"):
+		return constOperand(nil), nil
+	case strings.EqualFold(token, "LOWER"), strings.EqualFold(token, "UPPER"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		convert := strings.ToLower
+		if strings.EqualFold(token, "UPPER") {
+			convert = strings.ToUpper
+		}
+		return func(row reflect.Value) (interface{}, error) {
+			v, err := inner(row)
+			if s, ok := v.(string); ok {
+				return convert(s), err
+			}
+			return v, err
+		}, nil
+	}
+
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		return constOperand(n), nil
+	}
+	if isIdentifier(token) {
+		return columnOperand(p.sch, token)
+	}
+	return nil, p.errorf("unexpected token %q", token)
+}
+
+func compareMatcher(op string) func(int) bool {
+	switch op {
+	case "=":
+		return func(c int) bool { return c == 0 }
+	case "<>", "!=":
+		return func(c int) bool { return c != 0 }
+	case ">":
+		return func(c int) bool { return c > 0 }
+	case ">=":
+		return func(c int) bool { return c >= 0 }
+	case "<":
+		return func(c int) bool { return c < 0 }
+	default:
+		return func(c int) bool { return c <= 0 }
+	}
+}
+
+func allOf(preds []predicate) predicate {
+	return func(row reflect.Value) (bool, error) {
+		for _, p := range preds {
+			ok, err := p(row)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+func anyOf(preds []predicate) predicate {
+	return func(row reflect.Value) (bool, error) {
+		for _, p := range preds {
+			ok, err := p(row)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func isIdentifier(token string) bool {
+	for _, r := range token {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '"' || r == '`') {
+			return false
+		}
+	}
+	return token != ""
+}
+
+// tokenize 將 SQL 條件切為 token , 字串常數以單引號包住並保留引號
+func tokenize(sql string) ([]string, error) {
+	var tokens []string
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '?':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '\'':
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\'' {
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("memory: unterminated string in %q", sql)
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		case strings.ContainsRune("=<>!", r):
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune("=<>", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("()',?=<>!", runes[j]) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("memory: unexpected character %q in %q", r, sql)
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		}
+	}
+	return tokens, nil
+}
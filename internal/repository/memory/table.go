@@ -0,0 +1,416 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// uniqueIndex entity 上的 unique index
+type uniqueIndex struct {
+	name    string
+	columns []*schema.Field
+	partial bool // 只檢查未刪除的資料 , 對應 where:deleted_at IS NULL 的 partial index
+}
+
+// Table 泛型 in-memory table , 模擬 gorm + postgres 對 gorm.Model entity 的行為
+// 提供給 repository 的 in-memory 實作使用 , 支援:
+//   - 自動遞增 ID 與 CreatedAt / UpdatedAt
+//   - entity tag 上的 unique index , 違反時回傳 gorm.ErrDuplicatedKey
+//   - 軟刪除 (DeletedAt) , 還原與永久刪除
+//   - 以 gorm scope 表示的查詢條件 , 透過 DryRun 取得 WHERE 子句後於記憶體中比對
+//   - 排序與分頁
+//
+// Example:
+//
+//	table := memory.NewTable[entity.Teacher]()
+//	err := table.Create(&entity.Teacher{Name: "John"})
+type Table[T any] struct {
+	mu      sync.RWMutex
+	schema  *schema.Schema
+	dryRun  *gorm.DB
+	rows    map[uint]*T
+	nextID  uint
+	indexes []uniqueIndex
+	now     func() time.Time
+}
+
+// NewTable 建立 in-memory table
+// T 須嵌入 gorm.Model , schema 解析失敗時 panic
+func NewTable[T any]() *Table[T] {
+	dryRun, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		NamingStrategy:       schema.NamingStrategy{SingularTable: true},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("memory: failed to open dry run db: %v", err))
+	}
+
+	stmt := &gorm.Statement{DB: dryRun}
+	if err := stmt.Parse(new(T)); err != nil {
+		panic(fmt.Sprintf("memory: failed to parse schema: %v", err))
+	}
+
+	t := &Table[T]{
+		schema: stmt.Schema,
+		dryRun: dryRun,
+		rows:   map[uint]*T{},
+		nextID: 1,
+		now:    time.Now,
+	}
+
+	for _, idx := range stmt.Schema.ParseIndexes() {
+		if idx.Class != "UNIQUE" {
+			continue
+		}
+		ui := uniqueIndex{
+			name:    idx.Name,
+			partial: strings.Contains(strings.ToLower(idx.Where), "deleted_at is null"),
+		}
+		for _, opt := range idx.Fields {
+			ui.columns = append(ui.columns, opt.Field)
+		}
+		t.indexes = append(t.indexes, ui)
+	}
+
+	return t
+}
+
+// Create 新增資料 , ID 為 0 時自動產生
+func (t *Table[T]) Create(row *T) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rv := reflect.ValueOf(row).Elem()
+	id := t.id(rv)
+	if id == 0 {
+		id = t.nextID
+		t.set(rv, "ID", id)
+	} else if _, ok := t.rows[id]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if id >= t.nextID {
+		t.nextID = id + 1
+	}
+
+	now := t.now()
+	if t.get(rv, "CreatedAt").(time.Time).IsZero() {
+		t.set(rv, "CreatedAt", now)
+	}
+	if t.get(rv, "UpdatedAt").(time.Time).IsZero() {
+		t.set(rv, "UpdatedAt", now)
+	}
+
+	stored := *row
+	if err := t.checkUnique(&stored); err != nil {
+		// 與 postgres 相同 , 失敗時不保留產生的 ID
+		t.set(rv, "ID", uint(0))
+		return err
+	}
+
+	t.rows[id] = &stored
+	return nil
+}
+
+// Get 依 ID 取得未刪除的資料 , 不存在時回傳 gorm.ErrRecordNotFound
+func (t *Table[T]) Get(id uint) (*T, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	row, ok := t.rows[id]
+	if !ok || t.deleted(row) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	c := *row
+	return &c, nil
+}
+
+// Updates 以 row 的非零值欄位更新資料 , 行為同 gorm 的 Model(row).Updates(row)
+// 回傳: 影響數量 , 資料不存在或已刪除時為 0
+func (t *Table[T]) Updates(row *T) (int64, error) {
+	src := reflect.ValueOf(row).Elem()
+
+	return t.Update(t.id(src), nil, func(dst *T) {
+		dv := reflect.ValueOf(dst).Elem()
+		for _, field := range t.schema.Fields {
+			if field.PrimaryKey || field.Name == "CreatedAt" || field.Name == "DeletedAt" || field.DBName == "" {
+				continue
+			}
+			value, zero := field.ValueOf(context.Background(), src)
+			if zero {
+				continue
+			}
+			_ = field.Set(context.Background(), dv, value)
+		}
+	})
+}
+
+// Update 更新單筆未刪除的資料
+// 參數: id - 資料ID, match - 額外條件 , nil 表示不檢查 , apply - 更新內容
+// 回傳: 影響數量 , 不存在、已刪除或不符合條件時為 0
+func (t *Table[T]) Update(id uint, match func(row *T) bool, apply func(row *T)) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok || t.deleted(row) || (match != nil && !match(row)) {
+		return 0, nil
+	}
+
+	updated := *row
+	apply(&updated)
+	t.set(reflect.ValueOf(&updated).Elem(), "UpdatedAt", t.now())
+
+	if err := t.checkUnique(&updated); err != nil {
+		return 0, err
+	}
+
+	t.rows[id] = &updated
+	return 1, nil
+}
+
+// Delete 軟刪除資料 , 回傳: 影響數量 , 不存在或已刪除時為 0
+func (t *Table[T]) Delete(id uint) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok || t.deleted(row) {
+		return 0, nil
+	}
+
+	deleted := *row
+	t.set(reflect.ValueOf(&deleted).Elem(), "DeletedAt", gorm.DeletedAt{Time: t.now(), Valid: true})
+	t.rows[id] = &deleted
+	return 1, nil
+}
+
+// Restore 還原已軟刪除的資料
+// 還原後違反 unique index 時回傳 gorm.ErrDuplicatedKey
+// 回傳: 影響數量 , 不存在或未刪除時為 0
+func (t *Table[T]) Restore(id uint) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok || !t.deleted(row) {
+		return 0, nil
+	}
+
+	restored := *row
+	rv := reflect.ValueOf(&restored).Elem()
+	t.set(rv, "DeletedAt", gorm.DeletedAt{})
+	t.set(rv, "UpdatedAt", t.now())
+
+	if err := t.checkUnique(&restored); err != nil {
+		return 0, err
+	}
+
+	t.rows[id] = &restored
+	return 1, nil
+}
+
+// Purge 永久刪除刪除時間早於 olderThan 的資料
+// 回傳: 刪除數量
+func (t *Table[T]) Purge(olderThan time.Time) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var count int64
+	for id, row := range t.rows {
+		deletedAt := t.get(reflect.ValueOf(row).Elem(), "DeletedAt").(gorm.DeletedAt)
+		if deletedAt.Valid && deletedAt.Time.Before(olderThan) {
+			delete(t.rows, id)
+			count++
+		}
+	}
+	return count
+}
+
+// Find 依條件查詢資料 , 並依 pageInfo 排序與分頁
+// 條件與 gorm 實作相同 , 以 scope 表示 , 未使用 Unscoped 時不包含已刪除的資料
+func (t *Table[T]) Find(pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*T, error) {
+	rows, err := t.Where(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.sort(rows, pageInfo.Sort, pageInfo.Order); err != nil {
+		return nil, err
+	}
+
+	return Paginate(rows, pageInfo), nil
+}
+
+// Where 依條件查詢所有符合的資料 , 依 ID 排序
+func (t *Table[T]) Where(conditions []func(db *gorm.DB) *gorm.DB) ([]*T, error) {
+	pred, err := t.predicate(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var result []*T
+	for _, row := range t.rows {
+		ok, err := pred(reflect.ValueOf(row).Elem())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			c := *row
+			result = append(result, &c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return t.id(reflect.ValueOf(result[i]).Elem()) < t.id(reflect.ValueOf(result[j]).Elem())
+	})
+	return result, nil
+}
+
+// predicate 以 DryRun 執行查詢 , 將產生的 WHERE 子句轉為 predicate
+func (t *Table[T]) predicate(conditions []func(db *gorm.DB) *gorm.DB) (predicate, error) {
+	var dest []T
+	stmt := t.dryRun.Session(&gorm.Session{DryRun: true, NewDB: true}).
+		Model(new(T)).
+		Scopes(conditions...).
+		Find(&dest).
+		Statement
+	if stmt.Error != nil {
+		return nil, stmt.Error
+	}
+
+	where, ok := stmt.Clauses["WHERE"]
+	if !ok || where.Expression == nil {
+		return func(reflect.Value) (bool, error) { return true, nil }, nil
+	}
+
+	return buildPredicate(t.schema, where.Expression)
+}
+
+// checkUnique 檢查 row 是否違反 unique index , 需持有鎖
+func (t *Table[T]) checkUnique(row *T) error {
+	rv := reflect.ValueOf(row).Elem()
+	id := t.id(rv)
+	rowDeleted := t.deleted(row)
+
+	for _, idx := range t.indexes {
+		if idx.partial && rowDeleted {
+			continue
+		}
+		for otherID, other := range t.rows {
+			if otherID == id || (idx.partial && t.deleted(other)) {
+				continue
+			}
+			ov := reflect.ValueOf(other).Elem()
+			same := true
+			for _, field := range idx.columns {
+				a := normalize(field.ReflectValueOf(context.Background(), rv).Interface())
+				b := normalize(field.ReflectValueOf(context.Background(), ov).Interface())
+				// NULL 不違反 unique
+				if a == nil || b == nil {
+					same = false
+					break
+				}
+				if c, err := compare(a, b); err != nil || c != 0 {
+					same = false
+					break
+				}
+			}
+			if same {
+				return fmt.Errorf("%w: %s", gorm.ErrDuplicatedKey, idx.name)
+			}
+		}
+	}
+	return nil
+}
+
+// sort 依欄位排序 , 值相同時依 ID 遞增
+func (t *Table[T]) sort(rows []*T, column, order string) error {
+	if column == "" {
+		return nil
+	}
+	field := t.schema.LookUpField(column)
+	if field == nil {
+		return fmt.Errorf("memory: unknown sort column %s.%s", t.schema.Table, column)
+	}
+	desc := strings.EqualFold(order, "desc")
+
+	var sortErr error
+	sort.SliceStable(rows, func(i, j int) bool {
+		a := normalize(field.ReflectValueOf(context.Background(), reflect.ValueOf(rows[i]).Elem()).Interface())
+		b := normalize(field.ReflectValueOf(context.Background(), reflect.ValueOf(rows[j]).Elem()).Interface())
+		// postgres 預設 NULL 視為最大值
+		switch {
+		case a == nil && b == nil:
+			return false
+		case a == nil:
+			return desc
+		case b == nil:
+			return !desc
+		}
+		c, err := compare(a, b)
+		if err != nil {
+			sortErr = err
+			return false
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	return sortErr
+}
+
+func (t *Table[T]) id(rv reflect.Value) uint {
+	return t.get(rv, "ID").(uint)
+}
+
+func (t *Table[T]) deleted(row *T) bool {
+	return t.get(reflect.ValueOf(row).Elem(), "DeletedAt").(gorm.DeletedAt).Valid
+}
+
+func (t *Table[T]) get(rv reflect.Value, name string) interface{} {
+	return t.schema.LookUpField(name).ReflectValueOf(context.Background(), rv).Interface()
+}
+
+func (t *Table[T]) set(rv reflect.Value, name string, value interface{}) {
+	_ = t.schema.LookUpField(name).Set(context.Background(), rv, value)
+}
+
+// Paginate 依 pageInfo 取出分頁資料 , 規則同 repository.Paginate
+// 供需要自行排序的 in-memory 查詢使用 , 例如依相關度排序的搜尋
+func Paginate[T any](rows []*T, pageInfo *repo.RepoPageInfo) []*T {
+	page := pageInfo.Page
+	if page <= 0 {
+		page = 1
+	}
+	// 同 gorm 的 Limit , 負數表示不限制 , 0 表示不取資料
+	if pageInfo.PageSize < 0 {
+		return rows
+	}
+
+	offset := (page - 1) * pageInfo.PageSize
+	if offset >= len(rows) {
+		return []*T{}
+	}
+	end := offset + pageInfo.PageSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[offset:end]
+}
@@ -0,0 +1,333 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// item 測試用 entity
+type item struct {
+	gorm.Model
+	Code  string `gorm:"type:varchar(20);not null;uniqueIndex"`
+	Slug  string `gorm:"type:varchar(20);not null;uniqueIndex:idx_item_slug_active,where:deleted_at IS NULL"`
+	Name  string `gorm:"type:varchar(100);not null"`
+	Score int    `gorm:"not null;default:0"`
+	Note  *string
+}
+
+type TableTestSuite struct {
+	suite.Suite
+	table *Table[item]
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 table 並新增測試資料
+func (s *TableTestSuite) SetupTest() {
+	s.table = NewTable[item]()
+
+	note := "note"
+	for _, row := range []*item{
+		{Code: "A01", Slug: "apple", Name: "Apple", Score: 30, Note: &note}, // id 1
+		{Code: "B01", Slug: "banana", Name: "banana", Score: 10},            // id 2
+		{Code: "C01", Slug: "cherry", Name: "Cherry", Score: 20},            // id 3
+		{Code: "D01", Slug: "durian", Name: "Durian", Score: 20},            // id 4 , 已刪除
+	} {
+		s.Require().NoError(s.table.Create(row))
+	}
+	_, err := s.table.Delete(4)
+	s.Require().NoError(err)
+}
+
+// TestTableSuite 執行測試套件
+func TestTableSuite(t *testing.T) {
+	suite.Run(t, new(TableTestSuite))
+}
+
+func (s *TableTestSuite) TestCreate() {
+	tests := []struct {
+		name       string
+		row        *item
+		assertFunc func(t *testing.T, row *item, err error)
+	}{
+		{
+			name: "duplicate code",
+			row:  &item{Code: "A01", Name: "Avocado"},
+			assertFunc: func(t *testing.T, row *item, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, row.ID)
+			},
+		},
+		{
+			name: "duplicate code of deleted row",
+			row:  &item{Code: "D01", Name: "Dragon fruit"},
+			assertFunc: func(t *testing.T, row *item, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+			},
+		},
+		{
+			name: "duplicate slug",
+			row:  &item{Code: "A02", Slug: "apple", Name: "Apple 2"},
+			assertFunc: func(t *testing.T, row *item, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+			},
+		},
+		{
+			name: "slug of deleted row, partial index",
+			row:  &item{Code: "D02", Slug: "durian", Name: "Durian 2"},
+			assertFunc: func(t *testing.T, row *item, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, row.ID)
+			},
+		},
+		{
+			name: "success",
+			row:  &item{Code: "E01", Slug: "elderberry", Name: "Elderberry"},
+			assertFunc: func(t *testing.T, row *item, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, row.ID)
+				assert.False(t, row.CreatedAt.IsZero())
+				assert.False(t, row.UpdatedAt.IsZero())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			err := s.table.Create(test.row)
+			test.assertFunc(s.T(), test.row, err)
+		})
+	}
+}
+
+func (s *TableTestSuite) TestGet() {
+	row, err := s.table.Get(1)
+	s.NoError(err)
+	s.Equal("Apple", row.Name)
+
+	// 回傳的是複本 , 修改不影響 table
+	row.Name = "changed"
+	row, err = s.table.Get(1)
+	s.NoError(err)
+	s.Equal("Apple", row.Name)
+
+	_, err = s.table.Get(4)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	_, err = s.table.Get(100)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *TableTestSuite) TestUpdates() {
+	tests := []struct {
+		name       string
+		row        *item
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "not found",
+			row:  &item{Model: gorm.Model{ID: 100}, Name: "Not Found"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "deleted",
+			row:  &item{Model: gorm.Model{ID: 4}, Name: "Deleted"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "duplicate code",
+			row:  &item{Model: gorm.Model{ID: 1}, Code: "B01"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "success, zero value fields are ignored",
+			row:  &item{Model: gorm.Model{ID: 2}, Name: "Banana"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.table.Updates(test.row)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+
+	row, err := s.table.Get(2)
+	s.NoError(err)
+	s.Equal("Banana", row.Name)
+	s.Equal("B01", row.Code)
+	s.Equal(10, row.Score)
+}
+
+func (s *TableTestSuite) TestRestore() {
+	// 未刪除與不存在的資料不影響
+	rowsAffected, err := s.table.Restore(1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.table.Restore(100)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	// 已有未刪除的資料使用相同 slug 時無法還原
+	s.Require().NoError(s.table.Create(&item{Code: "D02", Slug: "durian", Name: "Durian 2"}))
+	rowsAffected, err = s.table.Restore(4)
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+	s.Zero(rowsAffected)
+
+	_, err = s.table.Delete(5)
+	s.Require().NoError(err)
+	rowsAffected, err = s.table.Restore(4)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	row, err := s.table.Get(4)
+	s.NoError(err)
+	s.False(row.DeletedAt.Valid)
+}
+
+func (s *TableTestSuite) TestPurge() {
+	s.Zero(s.table.Purge(time.Now().Add(-time.Hour)))
+	s.EqualValues(1, s.table.Purge(time.Now().Add(time.Second)))
+
+	rows, err := s.table.Where([]func(db *gorm.DB) *gorm.DB{repo.OnlyDeleted})
+	s.NoError(err)
+	s.Empty(rows)
+
+	// 永久刪除後 code 可以重複使用
+	s.NoError(s.table.Create(&item{Code: "D01", Slug: "durian", Name: "Durian"}))
+}
+
+func (s *TableTestSuite) TestFind() {
+	tests := []struct {
+		name       string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, rows []*item, err error)
+	}{
+		{
+			name:     "sort desc with id tie-break",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "score", Order: "desc"},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 3, 2}, ids(rows))
+			},
+		},
+		{
+			name:     "pagination",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 2, Sort: "id", Order: "asc"},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, ids(rows))
+			},
+		},
+		{
+			name:     "page out of range",
+			pageInfo: &repo.RepoPageInfo{Page: 5, PageSize: 2, Sort: "id", Order: "asc"},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, rows)
+			},
+		},
+		{
+			name:     "like is case sensitive",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Where("name LIKE ?", "%an%") },
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2}, ids(rows))
+			},
+		},
+		{
+			name:     "ilike, in and or",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB {
+					return db.Where("(name ILIKE ? OR score IN (?)) AND code <> ?", "c%", []int{10, 30}, "A01")
+				},
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 3}, ids(rows))
+			},
+		},
+		{
+			name:     "struct condition and not",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Where(&item{Score: 20}) },
+				func(db *gorm.DB) *gorm.DB { return db.Not("code = ?", "A01") },
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, ids(rows))
+			},
+		},
+		{
+			name:     "is null and between",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Where("note IS NULL AND score BETWEEN ? AND ?", 15, 30) },
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, ids(rows))
+			},
+		},
+		{
+			name:     "unscoped includes deleted",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Unscoped().Where("score = ?", 20) },
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 4}, ids(rows))
+			},
+		},
+		{
+			name:     "unknown column",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Where("unknown = ?", 1) },
+			},
+			assertFunc: func(t *testing.T, rows []*item, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rows, err := s.table.Find(test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), rows, err)
+		})
+	}
+}
+
+func ids(rows []*item) []uint {
+	result := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.ID)
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// NotificationRepoContractSuite NotificationRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type NotificationRepoContractSuite struct {
+	suite.Suite
+	newRepo          func(t *testing.T) NotificationRepository
+	notificationRepo NotificationRepository
+	now              time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增通知
+func (s *NotificationRepoContractSuite) SetupTest() {
+	s.notificationRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	count, err := s.notificationRepo.Enqueue(context.Background(),
+		newContractNotification("event-1", entity.ChannelEmail, 1, s.now),                // id 1
+		newContractNotification("event-1", entity.ChannelSMS, 1, s.now),                  // id 2
+		newContractNotification("event-2", entity.ChannelEmail, 2, s.now.Add(time.Hour)), // id 3 , 尚未到發送時間
+	)
+	s.Require().NoError(err)
+	s.Require().EqualValues(3, count)
+}
+
+// TestNotificationRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestNotificationRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &NotificationRepoContractSuite{newRepo: func(t *testing.T) NotificationRepository {
+			return NewNotificationRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &NotificationRepoContractSuite{newRepo: func(t *testing.T) NotificationRepository {
+			return NewNotificationMemoryRepository()
+		}})
+	})
+}
+
+func (s *NotificationRepoContractSuite) TestEnqueueIgnoresDuplicates() {
+	count, err := s.notificationRepo.Enqueue(context.Background(),
+		newContractNotification("event-1", entity.ChannelEmail, 1, s.now),
+		newContractNotification("event-2", entity.ChannelSMS, 2, s.now),
+	)
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	notifications, err := s.notificationRepo.Find(context.Background(),
+		&repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.NotificationOfUser(2)},
+	)
+	s.NoError(err)
+	s.Require().Len(notifications, 2)
+	s.Equal(entity.ChannelSMS, notifications[1].Channel)
+}
+
+func (s *NotificationRepoContractSuite) TestListPending() {
+	notifications, err := s.notificationRepo.ListPending(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Require().Len(notifications, 2)
+	s.EqualValues(1, notifications[0].ID)
+	s.EqualValues(2, notifications[1].ID)
+
+	notifications, err = s.notificationRepo.ListPending(context.Background(), s.now.Add(time.Hour), 1)
+	s.NoError(err)
+	s.Len(notifications, 1)
+}
+
+func (s *NotificationRepoContractSuite) TestRecordAttempt() {
+	s.NoError(s.notificationRepo.RecordAttempt(context.Background(), 1, &entity.NotificationAttempt{
+		Status:        entity.NotificationStatusPending,
+		Error:         "connection refused",
+		At:            s.now,
+		NextAttemptAt: s.now.Add(time.Minute),
+	}))
+	s.NoError(s.notificationRepo.RecordAttempt(context.Background(), 2, &entity.NotificationAttempt{
+		Status: entity.NotificationStatusSent,
+		At:     s.now,
+	}))
+
+	notification, err := s.notificationRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.NotificationStatusPending, notification.Status)
+	s.EqualValues(1, notification.Attempts)
+	s.Equal("connection refused", notification.LastError)
+	s.True(notification.NextAttemptAt.Equal(s.now.Add(time.Minute)))
+
+	notification, err = s.notificationRepo.GetByID(context.Background(), 2)
+	s.NoError(err)
+	s.Equal(entity.NotificationStatusSent, notification.Status)
+	s.Require().NotNil(notification.SentAt)
+	s.True(notification.SentAt.Equal(s.now))
+
+	notifications, err := s.notificationRepo.ListPending(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Empty(notifications)
+}
+
+func (s *NotificationRepoContractSuite) TestPreference() {
+	_, err := s.notificationRepo.GetPreference(context.Background(), 1)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	s.NoError(s.notificationRepo.SavePreference(context.Background(), &entity.NotificationPreference{
+		UserID: 1, Locale: entity.LocaleEn, EmailEnabled: true, SMSEnabled: true, Email: "user@example.com", Phone: "0912345678",
+	}))
+
+	// 關閉管道 (零值) 也會更新
+	preference := &entity.NotificationPreference{UserID: 1, Locale: entity.LocaleZhTW, EmailEnabled: true, Email: "new@example.com"}
+	s.NoError(s.notificationRepo.SavePreference(context.Background(), preference))
+	s.NotZero(preference.ID)
+
+	saved, err := s.notificationRepo.GetPreference(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(preference.ID, saved.ID)
+	s.Equal(entity.LocaleZhTW, saved.Locale)
+	s.True(saved.EmailEnabled)
+	s.False(saved.SMSEnabled)
+	s.Equal("new@example.com", saved.Email)
+	s.Empty(saved.Phone)
+}
+
+func newContractNotification(key string, channel entity.Channel, userID uint, nextAttemptAt time.Time) *entity.Notification {
+	address := "user@example.com"
+	if channel == entity.ChannelSMS {
+		address = "0912345678"
+	}
+	return &entity.Notification{
+		Key:           key,
+		Channel:       channel,
+		UserID:        userID,
+		Address:       address,
+		Template:      "teacher_approved",
+		Locale:        entity.LocaleZhTW,
+		Body:          "body",
+		NextAttemptAt: nextAttemptAt,
+	}
+}
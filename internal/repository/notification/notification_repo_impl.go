@@ -0,0 +1,158 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ NotificationRepository = (*NotificationRepositoryImpl)(nil)
+
+// NotificationRepositoryImpl 實作 NotificationRepository 介面
+//
+// Example:
+//
+//	repo := notification.NewNotificationRepository(db)
+//	preference, err := repo.GetPreference(ctx, 1)
+type NotificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 建立通知資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 通知資料庫操作實例
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &NotificationRepositoryImpl{db: db}
+}
+
+// Enqueue 新增待發送的通知 , 以 ON CONFLICT DO NOTHING 略過重複的通知
+func (r *NotificationRepositoryImpl) Enqueue(ctx context.Context, notifications ...*entity.Notification) (int64, error) {
+	defer metrics.ObserveRepoQuery("notification", "Enqueue", time.Now())
+
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	result := repo.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&notifications)
+	return result.RowsAffected, result.Error
+}
+
+// GetByID 依通知ID查詢通知
+func (r *NotificationRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Notification, error) {
+	defer metrics.ObserveRepoQuery("notification", "GetByID", time.Now())
+
+	var notification entity.Notification
+	if err := repo.Conn(ctx, r.db).First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// Find 查詢通知
+func (r *NotificationRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Notification, error) {
+	defer metrics.ObserveRepoQuery("notification", "Find", time.Now())
+
+	var notifications []*entity.Notification
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Order("id").
+		Find(&notifications).
+		Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// ListPending 查詢可發送的通知
+// 使用 FOR UPDATE SKIP LOCKED , 其他 transaction 已鎖定的通知會被略過
+func (r *NotificationRepositoryImpl) ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.Notification, error) {
+	defer metrics.ObserveRepoQuery("notification", "ListPending", time.Now())
+
+	var notifications []*entity.Notification
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.NotificationPending(now)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("id").
+		Limit(limit).
+		Find(&notifications).
+		Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// RecordAttempt 記錄一次發送的結果
+func (r *NotificationRepositoryImpl) RecordAttempt(ctx context.Context, id uint, attempt *entity.NotificationAttempt) error {
+	defer metrics.ObserveRepoQuery("notification", "RecordAttempt", time.Now())
+
+	values := map[string]interface{}{
+		"status":     attempt.Status,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": attempt.Error,
+	}
+	switch attempt.Status {
+	case entity.NotificationStatusPending:
+		values["next_attempt_at"] = attempt.NextAttemptAt
+	case entity.NotificationStatusSent:
+		values["sent_at"] = attempt.At
+	}
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.Notification{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error
+}
+
+// GetPreference 查詢使用者的通知偏好
+func (r *NotificationRepositoryImpl) GetPreference(ctx context.Context, userID uint) (*entity.NotificationPreference, error) {
+	defer metrics.ObserveRepoQuery("notification", "GetPreference", time.Now())
+
+	var preference entity.NotificationPreference
+	if err := repo.Conn(ctx, r.db).Where("user_id = ?", userID).First(&preference).Error; err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreference 新增或更新使用者的通知偏好
+// 已有設定時以 map 更新 , 關閉管道 (false) 也會寫入
+func (r *NotificationRepositoryImpl) SavePreference(ctx context.Context, preference *entity.NotificationPreference) error {
+	defer metrics.ObserveRepoQuery("notification", "SavePreference", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+
+	var existing entity.NotificationPreference
+	err := db.Where("user_id = ?", preference.UserID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return db.Create(preference).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	preference.ID = existing.ID
+	preference.CreatedAt = existing.CreatedAt
+	return db.Model(&existing).Updates(map[string]interface{}{
+		"locale":        preference.Locale,
+		"email_enabled": preference.EmailEnabled,
+		"sms_enabled":   preference.SMSEnabled,
+		"email":         preference.Email,
+		"phone":         preference.Phone,
+	}).Error
+}
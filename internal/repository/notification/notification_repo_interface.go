@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// NotificationRepository 定義通知佇列與通知偏好的資料存取介面
+//
+// Example:
+//
+//	var repo NotificationRepository
+//	notifications, err := repo.ListPending(ctx, time.Now(), 100)
+type NotificationRepository interface {
+	// Enqueue 新增待發送的通知 , 已有相同 Key 與 Channel 的通知時略過
+	// 參數: ctx - context, notifications - 通知
+	// 回傳: 實際新增的數量, 錯誤訊息
+	Enqueue(ctx context.Context, notifications ...*entity.Notification) (int64, error)
+
+	// GetByID 依通知ID查詢通知
+	// 參數: ctx - context, id - 通知ID
+	// 回傳: 通知, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Notification, error)
+
+	// Find 取得通知清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 通知切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Notification, error)
+
+	// ListPending 查詢於 now 可發送的通知並鎖定 , 其他 transaction 已鎖定的通知會被略過
+	// 須於 transaction 中呼叫 , 鎖定至 transaction 結束
+	// 參數: ctx - context, now - 目前時間, limit - 數量上限
+	// 回傳: 通知 , 依 id 排序, 錯誤訊息
+	ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.Notification, error)
+
+	// RecordAttempt 記錄一次發送的結果並增加發送次數
+	// 參數: ctx - context, id - 通知ID, attempt - 發送結果
+	// 回傳: 錯誤訊息
+	RecordAttempt(ctx context.Context, id uint, attempt *entity.NotificationAttempt) error
+
+	// GetPreference 查詢使用者的通知偏好
+	// 參數: ctx - context, userID - user ID
+	// 回傳: 通知偏好, 錯誤訊息 , 沒有設定時回傳 gorm.ErrRecordNotFound
+	GetPreference(ctx context.Context, userID uint) (*entity.NotificationPreference, error)
+
+	// SavePreference 新增或更新使用者的通知偏好 , 所有欄位 (包含零值) 都會更新
+	// 參數: ctx - context, preference - 通知偏好
+	// 回傳: 錯誤訊息
+	SavePreference(ctx context.Context, preference *entity.NotificationPreference) error
+}
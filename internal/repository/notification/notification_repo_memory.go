@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ NotificationRepository = (*NotificationMemoryRepository)(nil)
+
+// NotificationMemoryRepository 以記憶體實作 NotificationRepository 介面
+// 供通知 service 與 sender 測試使用 , 不需要啟動資料庫 , 不支援鎖定
+//
+// Example:
+//
+//	repo := notification.NewNotificationMemoryRepository()
+//	count, err := repo.Enqueue(ctx, &entity.Notification{Key: "event-1", Channel: entity.ChannelEmail})
+type NotificationMemoryRepository struct {
+	notifications *memory.Table[entity.Notification]
+	preferences   *memory.Table[entity.NotificationPreference]
+}
+
+// NewNotificationMemoryRepository 建立記憶體通知資料操作實例
+// 回傳: 通知資料操作實例
+func NewNotificationMemoryRepository() NotificationRepository {
+	return &NotificationMemoryRepository{
+		notifications: memory.NewTable[entity.Notification](),
+		preferences:   memory.NewTable[entity.NotificationPreference](),
+	}
+}
+
+// Enqueue 新增待發送的通知 , 違反 unique 限制的通知略過
+func (r *NotificationMemoryRepository) Enqueue(ctx context.Context, notifications ...*entity.Notification) (int64, error) {
+	var count int64
+	for _, notification := range notifications {
+		err := r.notifications.Create(notification)
+		switch {
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+		case err != nil:
+			return count, err
+		default:
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetByID 依通知ID查詢通知
+func (r *NotificationMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Notification, error) {
+	return r.notifications.Get(id)
+}
+
+// Find 查詢通知
+func (r *NotificationMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Notification, error) {
+	return r.notifications.Find(pageInfo, conditions)
+}
+
+// ListPending 查詢可發送的通知 , 依 id 排序
+func (r *NotificationMemoryRepository) ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.Notification, error) {
+	return r.notifications.Find(
+		&repo.RepoPageInfo{Page: 1, PageSize: limit, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.NotificationPending(now)},
+	)
+}
+
+// RecordAttempt 記錄一次發送的結果
+func (r *NotificationMemoryRepository) RecordAttempt(ctx context.Context, id uint, attempt *entity.NotificationAttempt) error {
+	_, err := r.notifications.Update(id,
+		func(notification *entity.Notification) bool { return true },
+		func(notification *entity.Notification) {
+			notification.Status = attempt.Status
+			notification.Attempts++
+			notification.LastError = attempt.Error
+			switch attempt.Status {
+			case entity.NotificationStatusPending:
+				notification.NextAttemptAt = attempt.NextAttemptAt
+			case entity.NotificationStatusSent:
+				at := attempt.At
+				notification.SentAt = &at
+			}
+		},
+	)
+	return err
+}
+
+// GetPreference 查詢使用者的通知偏好
+func (r *NotificationMemoryRepository) GetPreference(ctx context.Context, userID uint) (*entity.NotificationPreference, error) {
+	preferences, err := r.preferences.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("user_id = ?", userID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(preferences) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return preferences[0], nil
+}
+
+// SavePreference 新增或更新使用者的通知偏好
+func (r *NotificationMemoryRepository) SavePreference(ctx context.Context, preference *entity.NotificationPreference) error {
+	existing, err := r.GetPreference(ctx, preference.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.preferences.Create(preference)
+	}
+	if err != nil {
+		return err
+	}
+
+	preference.ID = existing.ID
+	preference.CreatedAt = existing.CreatedAt
+	_, err = r.preferences.Update(existing.ID,
+		func(p *entity.NotificationPreference) bool { return true },
+		func(p *entity.NotificationPreference) {
+			p.Locale = preference.Locale
+			p.EmailEnabled = preference.EmailEnabled
+			p.SMSEnabled = preference.SMSEnabled
+			p.Email = preference.Email
+			p.Phone = preference.Phone
+		},
+	)
+	return err
+}
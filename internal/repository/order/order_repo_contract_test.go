@@ -0,0 +1,297 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// OrderRepoContractSuite OrderRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type OrderRepoContractSuite struct {
+	suite.Suite
+	newRepo   func(t *testing.T) OrderRepository
+	orderRepo OrderRepository
+	now       time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增報名 1 的兩筆訂單與報名 2 的一筆訂單
+func (s *OrderRepoContractSuite) SetupTest() {
+	s.orderRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, order := range []*entity.Order{
+		{EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, Currency: "TWD", Status: entity.OrderStatusFailed, ExpiresAt: s.now.Add(-time.Hour)}, // id 1
+		{EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, Currency: "TWD", ExpiresAt: s.now.Add(30 * time.Minute)},                             // id 2
+		{EnrollmentID: 2, CourseID: 1, StudentID: 11, Amount: 3000, Currency: "TWD", ExpiresAt: s.now.Add(-time.Minute)},                                 // id 3 , 已逾期
+	} {
+		_, err := s.orderRepo.Create(context.Background(), order)
+		s.Require().NoError(err)
+	}
+}
+
+// TestOrderRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestOrderRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &OrderRepoContractSuite{newRepo: func(t *testing.T) OrderRepository {
+			return NewOrderRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &OrderRepoContractSuite{newRepo: func(t *testing.T) OrderRepository {
+			return NewOrderMemoryRepository()
+		}})
+	})
+}
+
+func (s *OrderRepoContractSuite) TestSetPayment() {
+	ctx := context.Background()
+
+	rows, err := s.orderRepo.SetPayment(ctx, 2, "fake", "fake_2", "http://localhost/checkout/fake_2")
+	s.NoError(err)
+	s.EqualValues(1, rows)
+
+	// 已建立付款
+	rows, err = s.orderRepo.SetPayment(ctx, 2, "fake", "fake_other", "http://localhost/checkout/fake_other")
+	s.NoError(err)
+	s.Zero(rows)
+
+	// 非待付款
+	rows, err = s.orderRepo.SetPayment(ctx, 1, "fake", "fake_1", "http://localhost/checkout/fake_1")
+	s.NoError(err)
+	s.Zero(rows)
+
+	order, err := s.orderRepo.GetByPayment(ctx, "fake", "fake_2")
+	s.Require().NoError(err)
+	s.EqualValues(2, order.ID)
+	s.Equal("http://localhost/checkout/fake_2", order.CheckoutURL)
+
+	_, err = s.orderRepo.GetByPayment(ctx, "other", "fake_2")
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *OrderRepoContractSuite) TestUpdateStatus() {
+	tests := []struct {
+		name       string
+		id         uint
+		from, to   entity.OrderStatus
+		reason     string
+		assertFunc func(t *testing.T, rows int64, err error, order *entity.Order)
+	}{
+		{
+			name: "paid",
+			id:   2,
+			from: entity.OrderStatusPending,
+			to:   entity.OrderStatusPaid,
+			assertFunc: func(t *testing.T, rows int64, err error, order *entity.Order) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rows)
+				assert.Equal(t, entity.OrderStatusPaid, order.Status)
+				if assert.NotNil(t, order.PaidAt) {
+					assert.True(t, order.PaidAt.Equal(time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)))
+				}
+			},
+		},
+		{
+			name:   "failed",
+			id:     3,
+			from:   entity.OrderStatusPending,
+			to:     entity.OrderStatusFailed,
+			reason: "card declined",
+			assertFunc: func(t *testing.T, rows int64, err error, order *entity.Order) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rows)
+				assert.Equal(t, entity.OrderStatusFailed, order.Status)
+				assert.NotNil(t, order.FailedAt)
+				assert.Equal(t, "card declined", order.FailureReason)
+			},
+		},
+		{
+			name: "status changed",
+			id:   1,
+			from: entity.OrderStatusPending,
+			to:   entity.OrderStatusPaid,
+			assertFunc: func(t *testing.T, rows int64, err error, order *entity.Order) {
+				assert.NoError(t, err)
+				assert.Zero(t, rows)
+				assert.Equal(t, entity.OrderStatusFailed, order.Status)
+				assert.Nil(t, order.PaidAt)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rows, err := s.orderRepo.UpdateStatus(context.Background(), test.id, test.from, test.to, s.now, test.reason)
+			order, getErr := s.orderRepo.GetByID(context.Background(), test.id)
+			s.Require().NoError(getErr)
+			test.assertFunc(s.T(), rows, err, order)
+		})
+	}
+}
+
+func (s *OrderRepoContractSuite) TestAddRefundedAmount() {
+	ctx := context.Background()
+	_, err := s.orderRepo.UpdateStatus(ctx, 2, entity.OrderStatusPending, entity.OrderStatusPaid, s.now, "")
+	s.Require().NoError(err)
+
+	// 部分退款維持已付款
+	rows, err := s.orderRepo.AddRefundedAmount(ctx, 2, 1000, s.now)
+	s.NoError(err)
+	s.EqualValues(1, rows)
+	order, err := s.orderRepo.GetByID(ctx, 2)
+	s.Require().NoError(err)
+	s.Equal(entity.OrderStatusPaid, order.Status)
+	s.EqualValues(1000, order.RefundedAmount)
+	if s.NotNil(order.RefundedAt) {
+		s.True(order.RefundedAt.Equal(s.now))
+	}
+
+	// 累計達到訂單金額時變更為已退款
+	rows, err = s.orderRepo.AddRefundedAmount(ctx, 2, 2000, s.now.Add(time.Hour))
+	s.NoError(err)
+	s.EqualValues(1, rows)
+	order, err = s.orderRepo.GetByID(ctx, 2)
+	s.Require().NoError(err)
+	s.Equal(entity.OrderStatusRefunded, order.Status)
+	s.EqualValues(3000, order.RefundedAmount)
+	if s.NotNil(order.RefundedAt) {
+		s.True(order.RefundedAt.Equal(s.now.Add(time.Hour)))
+	}
+
+	// 付款失敗的訂單只累計金額
+	rows, err = s.orderRepo.AddRefundedAmount(ctx, 1, 3000, s.now)
+	s.NoError(err)
+	s.EqualValues(1, rows)
+	order, err = s.orderRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal(entity.OrderStatusFailed, order.Status)
+	s.EqualValues(3000, order.RefundedAmount)
+
+	rows, err = s.orderRepo.AddRefundedAmount(ctx, 100, 3000, s.now)
+	s.NoError(err)
+	s.Zero(rows)
+}
+
+func (s *OrderRepoContractSuite) TestFind() {
+	orders, err := s.orderRepo.Find(context.Background(),
+		&repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.OrderOfEnrollment(1)},
+	)
+	s.NoError(err)
+	s.Equal([]uint{2, 1}, orderIDs(orders))
+
+	orders, err = s.orderRepo.Find(context.Background(),
+		&repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.InOrderStatus([]entity.OrderStatus{entity.OrderStatusPending})},
+	)
+	s.NoError(err)
+	s.Equal([]uint{2, 3}, orderIDs(orders))
+}
+
+func (s *OrderRepoContractSuite) TestListExpired() {
+	orders, err := s.orderRepo.ListExpired(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Equal([]uint{3}, orderIDs(orders))
+
+	orders, err = s.orderRepo.ListExpired(context.Background(), s.now.Add(time.Hour), 1)
+	s.NoError(err)
+	s.Equal([]uint{2}, orderIDs(orders))
+}
+
+func (s *OrderRepoContractSuite) TestCreateCallback() {
+	callback := func() *entity.PaymentCallback {
+		return &entity.PaymentCallback{Provider: "fake", EventID: "evt_1", OrderID: 2, PaymentID: "fake_2", Result: entity.OrderStatusPaid, Applied: true, Payload: "{}"}
+	}
+
+	id, err := s.orderRepo.CreateCallback(context.Background(), callback())
+	s.NoError(err)
+	s.NotZero(id)
+
+	_, err = s.orderRepo.CreateCallback(context.Background(), callback())
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	other := callback()
+	other.Provider = "other"
+	_, err = s.orderRepo.CreateCallback(context.Background(), other)
+	s.NoError(err)
+}
+
+func (s *OrderRepoContractSuite) TestRefunds() {
+	ctx := context.Background()
+	order := &entity.Order{Model: gorm.Model{ID: 2}, EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, Currency: "TWD", Provider: "fake", PaymentID: "fake_2"}
+
+	id, err := s.orderRepo.CreateRefund(ctx, entity.NewRefund(order, 50, entity.RefundReasonWithdrawal, s.now))
+	s.Require().NoError(err)
+	s.NotZero(id)
+
+	// 每筆訂單最多一筆退款紀錄
+	_, err = s.orderRepo.CreateRefund(ctx, entity.NewRefund(order, 100, entity.RefundReasonCourseCancelled, s.now))
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	refunds, err := s.orderRepo.FindRefunds(ctx, []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(2)})
+	s.Require().NoError(err)
+	s.Require().Len(refunds, 1)
+	s.EqualValues(1500, refunds[0].Amount)
+	s.Equal("fake_2", refunds[0].PaymentID)
+	s.Equal(entity.RefundStatusPending, refunds[0].Status)
+
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now.Add(-time.Second), 10)
+	s.NoError(err)
+	s.Empty(refunds)
+
+	// 取得後於 until 之前不會再取得 , 嘗試次數不變
+	s.Require().NoError(s.orderRepo.ClaimRefunds(ctx, []uint{id}, s.now.Add(time.Minute)))
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now, 10)
+	s.NoError(err)
+	s.Empty(refunds)
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now.Add(time.Minute), 10)
+	s.Require().NoError(err)
+	s.Require().Len(refunds, 1)
+	s.Zero(refunds[0].Attempts)
+
+	// 失敗後於 NextAttemptAt 重試
+	s.Require().NoError(s.orderRepo.RecordRefundAttempt(ctx, id, &entity.RefundAttempt{
+		Status: entity.RefundStatusPending, Error: "timeout", At: s.now, NextAttemptAt: s.now.Add(time.Minute),
+	}))
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now, 10)
+	s.NoError(err)
+	s.Empty(refunds)
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now.Add(time.Minute), 10)
+	s.Require().NoError(err)
+	s.Require().Len(refunds, 1)
+	s.EqualValues(1, refunds[0].Attempts)
+	s.Equal("timeout", refunds[0].LastError)
+
+	s.Require().NoError(s.orderRepo.RecordRefundAttempt(ctx, id, &entity.RefundAttempt{
+		Status: entity.RefundStatusSucceeded, ProviderRefundID: "re_1", At: s.now.Add(time.Minute),
+	}))
+	refunds, err = s.orderRepo.FindRefunds(ctx, []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(2)})
+	s.Require().NoError(err)
+	s.Require().Len(refunds, 1)
+	s.Equal(entity.RefundStatusSucceeded, refunds[0].Status)
+	s.EqualValues(2, refunds[0].Attempts)
+	s.Equal("re_1", refunds[0].ProviderRefundID)
+	s.Require().NotNil(refunds[0].RefundedAt)
+	s.True(refunds[0].RefundedAt.Equal(s.now.Add(time.Minute)))
+
+	refunds, err = s.orderRepo.ListPendingRefunds(ctx, s.now.Add(time.Hour), 10)
+	s.NoError(err)
+	s.Empty(refunds)
+}
+
+func orderIDs(orders []*entity.Order) []uint {
+	ids := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	return ids
+}
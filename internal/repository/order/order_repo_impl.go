@@ -0,0 +1,252 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ OrderRepository = (*OrderRepositoryImpl)(nil)
+
+// OrderRepositoryImpl 實作 OrderRepository 介面
+//
+// Example:
+//
+//	repo := order.NewOrderRepository(db)
+//	order, err := repo.GetByID(ctx, 1)
+type OrderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository 建立訂單資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 訂單資料庫操作實例
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &OrderRepositoryImpl{db: db}
+}
+
+// Create 新增訂單
+func (r *OrderRepositoryImpl) Create(ctx context.Context, order *entity.Order) (uint, error) {
+	defer metrics.ObserveRepoQuery("order", "Create", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(order).Error; err != nil {
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+// GetByID 依訂單ID查詢訂單
+func (r *OrderRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
+	defer metrics.ObserveRepoQuery("order", "GetByID", time.Now())
+
+	var order entity.Order
+	if err := repo.Conn(ctx, r.db).First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByPayment 依金流商的付款ID查詢訂單
+func (r *OrderRepositoryImpl) GetByPayment(ctx context.Context, provider, paymentID string) (*entity.Order, error) {
+	defer metrics.ObserveRepoQuery("order", "GetByPayment", time.Now())
+
+	var order entity.Order
+	if err := repo.Conn(ctx, r.db).
+		Where("provider = ? AND payment_id = ?", provider, paymentID).
+		First(&order).
+		Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Find 查詢訂單
+func (r *OrderRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Order, error) {
+	defer metrics.ObserveRepoQuery("order", "Find", time.Now())
+
+	var orders []*entity.Order
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&orders).
+		Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// SetPayment 記錄金流商建立的付款
+// 以狀態與 payment_id 為條件更新 , 避免併發建立付款時覆蓋彼此的付款ID
+func (r *OrderRepositoryImpl) SetPayment(ctx context.Context, id uint, provider, paymentID, checkoutURL string) (int64, error) {
+	defer metrics.ObserveRepoQuery("order", "SetPayment", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Order{}).
+		Where("id = ? AND status = ? AND payment_id = ''", id, entity.OrderStatusPending).
+		Updates(map[string]interface{}{
+			"provider":     provider,
+			"payment_id":   paymentID,
+			"checkout_url": checkoutURL,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateStatus 更新訂單狀態
+// 以 id 與目前狀態 from 為條件更新 , 避免重複的付款通知或併發的逾期處理覆蓋彼此的結果
+func (r *OrderRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to entity.OrderStatus, at time.Time, reason string) (int64, error) {
+	defer metrics.ObserveRepoQuery("order", "UpdateStatus", time.Now())
+
+	values := map[string]interface{}{"status": to}
+	switch to {
+	case entity.OrderStatusPaid:
+		values["paid_at"] = at
+	case entity.OrderStatusFailed:
+		values["failed_at"] = at
+		values["failure_reason"] = reason
+	case entity.OrderStatusRefunded:
+		values["refunded_at"] = at
+	}
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Order{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(values)
+	return result.RowsAffected, result.Error
+}
+
+// AddRefundedAmount 累計訂單的已退款金額
+// 以單一 UPDATE 累計並判斷是否變更為已退款 , SET 中的欄位皆為更新前的值 , 避免併發退款覆蓋
+func (r *OrderRepositoryImpl) AddRefundedAmount(ctx context.Context, id, amount uint, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("order", "AddRefundedAmount", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Order{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"refunded_amount": gorm.Expr("refunded_amount + ?", amount),
+			"refunded_at":     at,
+			"status": gorm.Expr("CASE WHEN status = ? AND refunded_amount + ? >= amount THEN ? ELSE status END",
+				entity.OrderStatusPaid, amount, entity.OrderStatusRefunded),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ListExpired 查詢已超過付款期限的待付款訂單
+// 使用 FOR UPDATE SKIP LOCKED , 其他 transaction 已鎖定的訂單會被略過
+func (r *OrderRepositoryImpl) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Order, error) {
+	defer metrics.ObserveRepoQuery("order", "ListExpired", time.Now())
+
+	var orders []*entity.Order
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.OrderExpired(now)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("id").
+		Limit(limit).
+		Find(&orders).
+		Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CreateCallback 新增付款通知紀錄
+func (r *OrderRepositoryImpl) CreateCallback(ctx context.Context, callback *entity.PaymentCallback) (uint, error) {
+	defer metrics.ObserveRepoQuery("order", "CreateCallback", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(callback).Error; err != nil {
+		return 0, err
+	}
+	return callback.ID, nil
+}
+
+// CreateRefund 新增退款紀錄
+func (r *OrderRepositoryImpl) CreateRefund(ctx context.Context, refund *entity.Refund) (uint, error) {
+	defer metrics.ObserveRepoQuery("order", "CreateRefund", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(refund).Error; err != nil {
+		return 0, err
+	}
+	return refund.ID, nil
+}
+
+// FindRefunds 查詢退款紀錄 , 依 id 排序
+func (r *OrderRepositoryImpl) FindRefunds(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Refund, error) {
+	defer metrics.ObserveRepoQuery("order", "FindRefunds", time.Now())
+
+	var refunds []*entity.Refund
+	if err := repo.Conn(ctx, r.db).
+		Scopes(conditions...).
+		Order("id").
+		Find(&refunds).
+		Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// ListPendingRefunds 查詢可退款的紀錄
+// 使用 FOR UPDATE SKIP LOCKED , 其他 transaction 已鎖定的紀錄會被略過
+func (r *OrderRepositoryImpl) ListPendingRefunds(ctx context.Context, now time.Time, limit int) ([]*entity.Refund, error) {
+	defer metrics.ObserveRepoQuery("order", "ListPendingRefunds", time.Now())
+
+	var refunds []*entity.Refund
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.RefundPending(now)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("id").
+		Limit(limit).
+		Find(&refunds).
+		Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// ClaimRefunds 延後待退款紀錄的下次嘗試時間
+func (r *OrderRepositoryImpl) ClaimRefunds(ctx context.Context, ids []uint, until time.Time) error {
+	defer metrics.ObserveRepoQuery("order", "ClaimRefunds", time.Now())
+
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.Conn(ctx, r.db).
+		Model(&entity.Refund{}).
+		Where("id IN ? AND status = ?", ids, entity.RefundStatusPending).
+		Update("next_attempt_at", until).
+		Error
+}
+
+// RecordRefundAttempt 記錄一次退款的結果
+func (r *OrderRepositoryImpl) RecordRefundAttempt(ctx context.Context, id uint, attempt *entity.RefundAttempt) error {
+	defer metrics.ObserveRepoQuery("order", "RecordRefundAttempt", time.Now())
+
+	values := map[string]interface{}{
+		"status":     attempt.Status,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": attempt.Error,
+	}
+	switch attempt.Status {
+	case entity.RefundStatusPending:
+		values["next_attempt_at"] = attempt.NextAttemptAt
+	case entity.RefundStatusSucceeded:
+		values["provider_refund_id"] = attempt.ProviderRefundID
+		values["refunded_at"] = attempt.At
+	}
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.Refund{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error
+}
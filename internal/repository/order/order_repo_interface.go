@@ -0,0 +1,94 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// OrderRepository 定義訂單、付款通知與退款紀錄的資料存取介面
+//
+// Example:
+//
+//	var repo OrderRepository
+//	order, err := repo.GetByPayment(ctx, "fake", "fake_123")
+type OrderRepository interface {
+	// Create 新增訂單
+	// 參數: ctx - context, order - 訂單
+	// 回傳: 新增後的訂單ID, 錯誤訊息
+	Create(ctx context.Context, order *entity.Order) (uint, error)
+
+	// GetByID 依訂單ID查詢訂單
+	// 參數: ctx - context, id - 訂單ID
+	// 回傳: 訂單, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Order, error)
+
+	// GetByPayment 依金流商的付款ID查詢訂單
+	// 參數: ctx - context, provider - 金流商, paymentID - 金流商的付款ID
+	// 回傳: 訂單, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByPayment(ctx context.Context, provider, paymentID string) (*entity.Order, error)
+
+	// Find 取得訂單清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 訂單切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Order, error)
+
+	// SetPayment 記錄金流商建立的付款 , 只有待付款且尚未建立付款的訂單才會更新
+	// 參數: ctx - context, id - 訂單ID, provider - 金流商, paymentID - 金流商的付款ID, checkoutURL - 付款頁面網址
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示訂單不存在、已建立付款或狀態已被變更
+	SetPayment(ctx context.Context, id uint, provider, paymentID, checkoutURL string) (int64, error)
+
+	// UpdateStatus 更新訂單狀態 , 只有目前狀態為 from 時才會更新
+	// 依新狀態記錄付款、失敗或退款時間 , 變更為付款失敗時記錄原因
+	// 參數: ctx - context, id - 訂單ID, from - 目前狀態, to - 新狀態, at - 變更時間, reason - 失敗原因
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示訂單不存在或狀態已被變更
+	UpdateStatus(ctx context.Context, id uint, from, to entity.OrderStatus, at time.Time, reason string) (int64, error)
+
+	// AddRefundedAmount 累計訂單的已退款金額並記錄退款時間
+	// 已付款的訂單累計後達到訂單金額時變更為已退款 , 其他狀態的訂單 (例如付款失敗後退還的付款) 只累計金額
+	// 參數: ctx - context, id - 訂單ID, amount - 本次退款金額, at - 退款時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示訂單不存在
+	AddRefundedAmount(ctx context.Context, id, amount uint, at time.Time) (int64, error)
+
+	// ListExpired 查詢於 now 已超過付款期限的待付款訂單並鎖定 , 其他 transaction 已鎖定的訂單會被略過
+	// 須於 transaction 中呼叫 , 鎖定至 transaction 結束
+	// 參數: ctx - context, now - 目前時間, limit - 數量上限
+	// 回傳: 訂單 , 依 id 排序, 錯誤訊息
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Order, error)
+
+	// CreateCallback 新增付款通知紀錄
+	// 參數: ctx - context, callback - 付款通知
+	// 回傳: 新增後的紀錄ID, 錯誤訊息 , 相同金流商與通知ID已存在時回傳 gorm.ErrDuplicatedKey
+	CreateCallback(ctx context.Context, callback *entity.PaymentCallback) (uint, error)
+
+	// CreateRefund 新增退款紀錄
+	// 參數: ctx - context, refund - 退款紀錄
+	// 回傳: 新增後的紀錄ID, 錯誤訊息 , 訂單已有退款紀錄時回傳 gorm.ErrDuplicatedKey
+	CreateRefund(ctx context.Context, refund *entity.Refund) (uint, error)
+
+	// FindRefunds 查詢退款紀錄
+	// 參數: ctx - context, conditions - 查詢條件
+	// 回傳: 退款紀錄 , 依 id 排序, 錯誤訊息
+	FindRefunds(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Refund, error)
+
+	// ListPendingRefunds 查詢於 now 可退款的紀錄並鎖定 , 其他 transaction 已鎖定的紀錄會被略過
+	// 須於 transaction 中呼叫 , 鎖定至 transaction 結束
+	// 參數: ctx - context, now - 目前時間, limit - 數量上限
+	// 回傳: 退款紀錄 , 依 id 排序, 錯誤訊息
+	ListPendingRefunds(ctx context.Context, now time.Time, limit int) ([]*entity.Refund, error)
+
+	// ClaimRefunds 將待退款紀錄的下次嘗試時間延後至 until , 不增加嘗試次數
+	// 於 ListPendingRefunds 的 transaction 中呼叫 , commit 後在 transaction 外呼叫金流商 , 期間其他 instance 不會取得相同紀錄
+	// 參數: ctx - context, ids - 退款紀錄ID, until - 下次嘗試時間
+	// 回傳: 錯誤訊息
+	ClaimRefunds(ctx context.Context, ids []uint, until time.Time) error
+
+	// RecordRefundAttempt 記錄一次退款的結果 , 嘗試次數加一
+	// 參數: ctx - context, id - 退款紀錄ID, attempt - 退款結果
+	// 回傳: 錯誤訊息
+	RecordRefundAttempt(ctx context.Context, id uint, attempt *entity.RefundAttempt) error
+}
@@ -0,0 +1,187 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ OrderRepository = (*OrderMemoryRepository)(nil)
+
+// OrderMemoryRepository 以記憶體實作 OrderRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援鎖定
+//
+// Example:
+//
+//	repo := order.NewOrderMemoryRepository()
+//	id, err := repo.Create(ctx, &entity.Order{EnrollmentID: 1, Amount: 3000, Currency: "TWD"})
+type OrderMemoryRepository struct {
+	orders    *memory.Table[entity.Order]
+	callbacks *memory.Table[entity.PaymentCallback]
+	refunds   *memory.Table[entity.Refund]
+}
+
+// NewOrderMemoryRepository 建立記憶體訂單資料操作實例
+// 回傳: 訂單資料操作實例
+func NewOrderMemoryRepository() OrderRepository {
+	return &OrderMemoryRepository{
+		orders:    memory.NewTable[entity.Order](),
+		callbacks: memory.NewTable[entity.PaymentCallback](),
+		refunds:   memory.NewTable[entity.Refund](),
+	}
+}
+
+// Create 新增訂單
+func (r *OrderMemoryRepository) Create(ctx context.Context, order *entity.Order) (uint, error) {
+	if err := r.orders.Create(order); err != nil {
+		return 0, err
+	}
+	return order.ID, nil
+}
+
+// GetByID 依訂單ID查詢訂單
+func (r *OrderMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
+	return r.orders.Get(id)
+}
+
+// GetByPayment 依金流商的付款ID查詢訂單
+func (r *OrderMemoryRepository) GetByPayment(ctx context.Context, provider, paymentID string) (*entity.Order, error) {
+	orders, err := r.orders.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("provider = ? AND payment_id = ?", provider, paymentID) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return orders[0], nil
+}
+
+// Find 查詢訂單
+func (r *OrderMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Order, error) {
+	return r.orders.Find(pageInfo, conditions)
+}
+
+// SetPayment 記錄金流商建立的付款 , 只有待付款且尚未建立付款的訂單才會更新
+func (r *OrderMemoryRepository) SetPayment(ctx context.Context, id uint, provider, paymentID, checkoutURL string) (int64, error) {
+	return r.orders.Update(id,
+		func(order *entity.Order) bool {
+			return order.Status == entity.OrderStatusPending && order.PaymentID == ""
+		},
+		func(order *entity.Order) {
+			order.Provider = provider
+			order.PaymentID = paymentID
+			order.CheckoutURL = checkoutURL
+		},
+	)
+}
+
+// UpdateStatus 更新訂單狀態 , 只有目前狀態為 from 時才會更新
+func (r *OrderMemoryRepository) UpdateStatus(ctx context.Context, id uint, from, to entity.OrderStatus, at time.Time, reason string) (int64, error) {
+	return r.orders.Update(id,
+		func(order *entity.Order) bool { return order.Status == from },
+		func(order *entity.Order) {
+			order.Status = to
+			switch to {
+			case entity.OrderStatusPaid:
+				order.PaidAt = &at
+			case entity.OrderStatusFailed:
+				order.FailedAt = &at
+				order.FailureReason = reason
+			case entity.OrderStatusRefunded:
+				order.RefundedAt = &at
+			}
+		},
+	)
+}
+
+// AddRefundedAmount 累計訂單的已退款金額 , 已付款且累計達到訂單金額時變更為已退款
+func (r *OrderMemoryRepository) AddRefundedAmount(ctx context.Context, id, amount uint, at time.Time) (int64, error) {
+	return r.orders.Update(id,
+		func(order *entity.Order) bool { return true },
+		func(order *entity.Order) {
+			order.RefundedAmount += amount
+			order.RefundedAt = &at
+			if order.Status == entity.OrderStatusPaid && order.RefundedAmount >= order.Amount {
+				order.Status = entity.OrderStatusRefunded
+			}
+		},
+	)
+}
+
+// ListExpired 查詢已超過付款期限的待付款訂單 , 依 id 排序
+func (r *OrderMemoryRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.Order, error) {
+	return r.orders.Find(
+		&repo.RepoPageInfo{Page: 1, PageSize: limit, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.OrderExpired(now)},
+	)
+}
+
+// CreateCallback 新增付款通知紀錄 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *OrderMemoryRepository) CreateCallback(ctx context.Context, callback *entity.PaymentCallback) (uint, error) {
+	if err := r.callbacks.Create(callback); err != nil {
+		return 0, err
+	}
+	return callback.ID, nil
+}
+
+// CreateRefund 新增退款紀錄 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *OrderMemoryRepository) CreateRefund(ctx context.Context, refund *entity.Refund) (uint, error) {
+	if err := r.refunds.Create(refund); err != nil {
+		return 0, err
+	}
+	return refund.ID, nil
+}
+
+// FindRefunds 查詢退款紀錄 , 依 id 排序
+func (r *OrderMemoryRepository) FindRefunds(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Refund, error) {
+	return r.refunds.Where(conditions)
+}
+
+// ListPendingRefunds 查詢可退款的紀錄 , 依 id 排序
+func (r *OrderMemoryRepository) ListPendingRefunds(ctx context.Context, now time.Time, limit int) ([]*entity.Refund, error) {
+	return r.refunds.Find(
+		&repo.RepoPageInfo{Page: 1, PageSize: limit, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.RefundPending(now)},
+	)
+}
+
+// ClaimRefunds 延後待退款紀錄的下次嘗試時間
+func (r *OrderMemoryRepository) ClaimRefunds(ctx context.Context, ids []uint, until time.Time) error {
+	for _, id := range ids {
+		if _, err := r.refunds.Update(id,
+			func(refund *entity.Refund) bool { return refund.Status == entity.RefundStatusPending },
+			func(refund *entity.Refund) { refund.NextAttemptAt = until },
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordRefundAttempt 記錄一次退款的結果
+func (r *OrderMemoryRepository) RecordRefundAttempt(ctx context.Context, id uint, attempt *entity.RefundAttempt) error {
+	_, err := r.refunds.Update(id,
+		func(refund *entity.Refund) bool { return true },
+		func(refund *entity.Refund) {
+			refund.Status = attempt.Status
+			refund.Attempts++
+			refund.LastError = attempt.Error
+			switch attempt.Status {
+			case entity.RefundStatusPending:
+				refund.NextAttemptAt = attempt.NextAttemptAt
+			case entity.RefundStatusSucceeded:
+				at := attempt.At
+				refund.ProviderRefundID = attempt.ProviderRefundID
+				refund.RefundedAt = &at
+			}
+		},
+	)
+	return err
+}
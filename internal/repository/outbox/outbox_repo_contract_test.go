@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// OutboxRepoContractSuite OutboxRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type OutboxRepoContractSuite struct {
+	suite.Suite
+	newRepo    func(t *testing.T) OutboxRepository
+	outboxRepo OutboxRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增測試資料
+func (s *OutboxRepoContractSuite) SetupTest() {
+	s.outboxRepo = s.newRepo(s.T())
+
+	s.Require().NoError(s.outboxRepo.Add(context.Background(),
+		event.TeacherApproved{TeacherID: 1, Name: "John Doe"},                // id 1
+		event.CourseStatusChanged{CourseID: 2, From: "draft", To: "pending"}, // id 2
+		event.StudentEnrolled{EnrollmentID: 3, CourseID: 2, StudentID: 10},   // id 3
+	))
+}
+
+// TestOutboxRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestOutboxRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &OutboxRepoContractSuite{newRepo: func(t *testing.T) OutboxRepository {
+			return NewOutboxRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &OutboxRepoContractSuite{newRepo: func(t *testing.T) OutboxRepository {
+			return NewOutboxMemoryRepository()
+		}})
+	})
+}
+
+func (s *OutboxRepoContractSuite) TestAdd() {
+	events := s.pending(time.Now().Add(time.Second))
+	s.Require().Len(events, 3)
+
+	e := events[0]
+	s.Len(e.EventID, 36)
+	s.NotEqual(e.EventID, events[1].EventID)
+	s.Equal(event.TypeTeacherApproved, e.EventType)
+	s.Equal("teacher", e.AggregateType)
+	s.EqualValues(1, e.AggregateID)
+	s.Zero(e.Attempts)
+
+	var payload event.TeacherApproved
+	s.Require().NoError(json.Unmarshal([]byte(e.Payload), &payload))
+	s.Equal("John Doe", payload.Name)
+
+	// 沒有事件時不寫入
+	s.NoError(s.outboxRepo.Add(context.Background()))
+}
+
+func (s *OutboxRepoContractSuite) TestListPending() {
+	now := time.Now().Add(time.Second)
+
+	s.Equal([]uint{1, 2}, outboxEventIDs(s.pendingLimit(now, 2)))
+
+	// 尚未到達發送時間的事件不會取得
+	s.Empty(s.pending(time.Now().Add(-time.Hour)))
+}
+
+func (s *OutboxRepoContractSuite) TestMark() {
+	now := time.Now()
+	s.Require().NoError(s.outboxRepo.MarkPublished(context.Background(), 1, now))
+	s.Require().NoError(s.outboxRepo.MarkFailed(context.Background(), 2, "connection refused", now.Add(time.Minute)))
+	s.Require().NoError(s.outboxRepo.MarkDead(context.Background(), 3, "bad request", now))
+
+	// 已發送與放棄發送的事件不再取得 , 失敗的事件於下次發送時間後取得
+	s.Empty(s.pending(now.Add(time.Second)))
+
+	events := s.pending(now.Add(2 * time.Minute))
+	s.Require().Len(events, 1)
+	s.EqualValues(2, events[0].ID)
+	s.EqualValues(1, events[0].Attempts)
+	s.Equal("connection refused", events[0].LastError)
+}
+
+func (s *OutboxRepoContractSuite) TestClaim() {
+	now := time.Now().Add(time.Second)
+	s.Require().NoError(s.outboxRepo.Claim(context.Background(), []uint{1, 2}, now.Add(time.Minute)))
+	s.Require().NoError(s.outboxRepo.Claim(context.Background(), nil, now))
+
+	// 取得後的事件在 until 之前不會再取得 , 發送次數不變
+	s.Equal([]uint{3}, outboxEventIDs(s.pending(now)))
+
+	events := s.pending(now.Add(time.Minute))
+	s.Equal([]uint{1, 2, 3}, outboxEventIDs(events))
+	s.Zero(events[0].Attempts)
+}
+
+func (s *OutboxRepoContractSuite) TestPurge() {
+	now := time.Now()
+	s.Require().NoError(s.outboxRepo.MarkPublished(context.Background(), 1, now.Add(-time.Hour)))
+	s.Require().NoError(s.outboxRepo.MarkDead(context.Background(), 2, "bad request", now.Add(-time.Hour)))
+
+	count, err := s.outboxRepo.Purge(context.Background(), now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Zero(count)
+
+	// 只刪除已發送的事件 , 放棄發送與未發送的事件保留
+	count, err = s.outboxRepo.Purge(context.Background(), now)
+	s.NoError(err)
+	s.EqualValues(1, count)
+	s.Equal([]uint{3}, outboxEventIDs(s.pending(now.Add(time.Second))))
+}
+
+func (s *OutboxRepoContractSuite) pending(now time.Time) []*entity.OutboxEvent {
+	return s.pendingLimit(now, 100)
+}
+
+func (s *OutboxRepoContractSuite) pendingLimit(now time.Time, limit int) []*entity.OutboxEvent {
+	events, err := s.outboxRepo.ListPending(context.Background(), now, limit)
+	s.Require().NoError(err)
+	return events
+}
+
+func outboxEventIDs(events []*entity.OutboxEvent) []uint {
+	ids := make([]uint, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
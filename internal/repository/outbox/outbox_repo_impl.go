@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ OutboxRepository = (*OutboxRepositoryImpl)(nil)
+
+// OutboxRepositoryImpl 實作 OutboxRepository 介面
+//
+// Example:
+//
+//	repo := outbox.NewOutboxRepository(db)
+//	events, err := repo.ListPending(ctx, time.Now(), 100)
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository 建立 outbox 資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: outbox 資料庫操作實例
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &OutboxRepositoryImpl{db: db}
+}
+
+// Add 新增事件
+// 使用 repository.Conn , 於 Transactor 的 transaction 中呼叫時與 entity 變更一起 commit
+func (r *OutboxRepositoryImpl) Add(ctx context.Context, events ...event.Event) error {
+	defer metrics.ObserveRepoQuery("outbox", "Add", time.Now())
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]*entity.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		row, err := entity.NewOutboxEvent(e, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	return repo.Conn(ctx, r.db).Create(&rows).Error
+}
+
+// ListPending 查詢可發送的事件
+// 使用 FOR UPDATE SKIP LOCKED , 其他 transaction 已鎖定的事件會被略過
+func (r *OutboxRepositoryImpl) ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	defer metrics.ObserveRepoQuery("outbox", "ListPending", time.Now())
+
+	var events []*entity.OutboxEvent
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.OutboxEventPending(now)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("id").
+		Limit(limit).
+		Find(&events).
+		Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Claim 延後事件的下次發送時間
+func (r *OutboxRepositoryImpl) Claim(ctx context.Context, ids []uint, until time.Time) error {
+	defer metrics.ObserveRepoQuery("outbox", "Claim", time.Now())
+
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.Conn(ctx, r.db).
+		Model(&entity.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).
+		Error
+}
+
+// MarkPublished 標記事件已發送
+func (r *OutboxRepositoryImpl) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	defer metrics.ObserveRepoQuery("outbox", "MarkPublished", time.Now())
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"published_at": at,
+			"last_error":   "",
+		}).Error
+}
+
+// MarkFailed 記錄發送失敗
+func (r *OutboxRepositoryImpl) MarkFailed(ctx context.Context, id uint, reason string, nextAttemptAt time.Time) error {
+	defer metrics.ObserveRepoQuery("outbox", "MarkFailed", time.Now())
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// MarkDead 記錄發送失敗並放棄發送
+func (r *OutboxRepositoryImpl) MarkDead(ctx context.Context, id uint, reason string, at time.Time) error {
+	defer metrics.ObserveRepoQuery("outbox", "MarkDead", time.Now())
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+			"dead_at":    at,
+		}).Error
+}
+
+// Purge 永久刪除已發送的事件
+func (r *OutboxRepositoryImpl) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("outbox", "Purge", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Where("published_at < ?", olderThan).
+		Delete(&entity.OutboxEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge outbox events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
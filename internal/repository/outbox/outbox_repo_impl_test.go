@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// 事件與 entity 變更在同一個 transaction , rollback 時事件也不會寫入
+func TestAddRollback(t *testing.T) {
+	db := testutil.NewDB(t)
+	outboxRepo := NewOutboxRepository(db)
+
+	err := repo.NewTransactor(db).Transaction(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, outboxRepo.Add(ctx, event.TeacherApproved{TeacherID: 1}))
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	events, err := outboxRepo.ListPending(context.Background(), time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+// 已被其他 transaction 鎖定的事件會被略過
+func TestListPendingSkipLocked(t *testing.T) {
+	db := testutil.NewDB(t)
+	outboxRepo := NewOutboxRepository(db)
+	transactor := repo.NewTransactor(db)
+	now := time.Now().Add(time.Second)
+
+	require.NoError(t, outboxRepo.Add(context.Background(),
+		event.TeacherApproved{TeacherID: 1},
+		event.TeacherApproved{TeacherID: 2},
+	))
+
+	err := transactor.Transaction(context.Background(), func(ctx context.Context) error {
+		locked, err := outboxRepo.ListPending(ctx, now, 1)
+		require.NoError(t, err)
+		require.Len(t, locked, 1)
+
+		// 另一個 transaction 只會取得未鎖定的事件
+		return transactor.Transaction(context.Background(), func(ctx context.Context) error {
+			events, err := outboxRepo.ListPending(ctx, now, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []uint{2}, outboxEventIDs(events))
+			return nil
+		})
+	})
+	require.NoError(t, err)
+}
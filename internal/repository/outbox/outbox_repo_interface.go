@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+)
+
+// OutboxRepository 定義 outbox 事件存取的介面
+// 事件須與 entity 變更在同一個 transaction 寫入 (repository.Transactor) , 才能確保變更與事件同時成功或失敗
+//
+// Example:
+//
+//	err := transactor.Transaction(ctx, func(ctx context.Context) error {
+//		// ... entity 變更
+//		return outboxRepo.Add(ctx, event.TeacherApproved{TeacherID: 1})
+//	})
+type OutboxRepository interface {
+	// Add 新增事件 , 立即可發送
+	// 參數: ctx - context, events - 領域事件
+	// 回傳: 錯誤訊息
+	Add(ctx context.Context, events ...event.Event) error
+
+	// ListPending 取得於 now 可發送的事件 , 依 id 排序
+	// 於 transaction 中呼叫時鎖定取得的事件 (FOR UPDATE SKIP LOCKED) , 多個 relay 同時執行也不會取得相同事件
+	// 參數: ctx - context, now - 目前時間, limit - 數量上限
+	// 回傳: 事件切片, 錯誤訊息
+	ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error)
+
+	// Claim 將事件的下次發送時間延後至 until , 不增加發送次數
+	// 於 ListPending 的 transaction 中呼叫 , commit 後在 transaction 外發送 , 發送期間其他 relay 不會取得相同事件
+	// relay 中斷而未記錄結果時 , 事件於 until 後重新發送
+	// 參數: ctx - context, ids - 事件ID, until - 下次發送時間
+	// 回傳: 錯誤訊息
+	Claim(ctx context.Context, ids []uint, until time.Time) error
+
+	// MarkPublished 標記事件已發送
+	// 參數: ctx - context, id - 事件ID, at - 發送時間
+	// 回傳: 錯誤訊息
+	MarkPublished(ctx context.Context, id uint, at time.Time) error
+
+	// MarkFailed 記錄發送失敗 , 並設定下次發送時間
+	// 參數: ctx - context, id - 事件ID, reason - 失敗原因, nextAttemptAt - 下次發送時間
+	// 回傳: 錯誤訊息
+	MarkFailed(ctx context.Context, id uint, reason string, nextAttemptAt time.Time) error
+
+	// MarkDead 記錄發送失敗並放棄發送
+	// 參數: ctx - context, id - 事件ID, reason - 失敗原因, at - 放棄時間
+	// 回傳: 錯誤訊息
+	MarkDead(ctx context.Context, id uint, reason string, at time.Time) error
+
+	// Purge 永久刪除發送時間早於 olderThan 的事件 , 未發送與放棄發送的事件保留
+	// 參數: ctx - context, olderThan - 發送時間上限
+	// 回傳: 刪除數量, 錯誤訊息
+	Purge(ctx context.Context, olderThan time.Time) (int64, error)
+}
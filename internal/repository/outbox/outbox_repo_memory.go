@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+)
+
+var _ OutboxRepository = (*OutboxMemoryRepository)(nil)
+
+// OutboxMemoryRepository 以記憶體實作 OutboxRepository 介面
+// 供 service 與 relay 測試使用 , 不需要啟動資料庫 , 不支援 transaction 與鎖定
+//
+// Example:
+//
+//	repo := outbox.NewOutboxMemoryRepository()
+//	err := repo.Add(ctx, event.TeacherApproved{TeacherID: 1})
+type OutboxMemoryRepository struct {
+	mu     sync.Mutex
+	events map[uint]*entity.OutboxEvent
+	nextID uint
+}
+
+// NewOutboxMemoryRepository 建立記憶體 outbox 操作實例
+// 回傳: outbox 操作實例
+func NewOutboxMemoryRepository() OutboxRepository {
+	return &OutboxMemoryRepository{events: map[uint]*entity.OutboxEvent{}, nextID: 1}
+}
+
+// Add 新增事件
+func (r *OutboxMemoryRepository) Add(ctx context.Context, events ...event.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	rows := make([]*entity.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		row, err := entity.NewOutboxEvent(e, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range rows {
+		row.ID = r.nextID
+		r.nextID++
+		r.events[row.ID] = row
+	}
+	return nil
+}
+
+// ListPending 查詢可發送的事件 , 依 id 排序
+func (r *OutboxMemoryRepository) ListPending(ctx context.Context, now time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*entity.OutboxEvent
+	for _, e := range r.events {
+		if e.PublishedAt == nil && e.DeadAt == nil && !e.NextAttemptAt.After(now) {
+			row := *e
+			result = append(result, &row)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	if limit >= 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// Claim 延後事件的下次發送時間
+func (r *OutboxMemoryRepository) Claim(ctx context.Context, ids []uint, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		if e, ok := r.events[id]; ok {
+			e.NextAttemptAt = until
+		}
+	}
+	return nil
+}
+
+// MarkPublished 標記事件已發送
+func (r *OutboxMemoryRepository) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	r.update(id, func(e *entity.OutboxEvent) {
+		e.PublishedAt = &at
+		e.LastError = ""
+	})
+	return nil
+}
+
+// MarkFailed 記錄發送失敗
+func (r *OutboxMemoryRepository) MarkFailed(ctx context.Context, id uint, reason string, nextAttemptAt time.Time) error {
+	r.update(id, func(e *entity.OutboxEvent) {
+		e.LastError = reason
+		e.NextAttemptAt = nextAttemptAt
+	})
+	return nil
+}
+
+// MarkDead 記錄發送失敗並放棄發送
+func (r *OutboxMemoryRepository) MarkDead(ctx context.Context, id uint, reason string, at time.Time) error {
+	r.update(id, func(e *entity.OutboxEvent) {
+		e.LastError = reason
+		e.DeadAt = &at
+	})
+	return nil
+}
+
+// Purge 永久刪除發送時間早於 olderThan 的事件
+func (r *OutboxMemoryRepository) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for id, e := range r.events {
+		if e.PublishedAt != nil && e.PublishedAt.Before(olderThan) {
+			delete(r.events, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// update 更新事件並增加發送次數 , 不存在時忽略 , 與 gorm 實作的 UPDATE 行為一致
+func (r *OutboxMemoryRepository) update(id uint, apply func(e *entity.OutboxEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.events[id]; ok {
+		e.Attempts++
+		apply(e)
+	}
+}
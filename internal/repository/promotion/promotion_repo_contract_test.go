@@ -0,0 +1,154 @@
+package promotion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// PromotionRepoContractSuite PromotionRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type PromotionRepoContractSuite struct {
+	suite.Suite
+	newRepo       func(t *testing.T) PromotionRepository
+	promotionRepo PromotionRepository
+	now           time.Time
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增促銷活動
+//   - 1: 優惠券 SUMMER10 , 總使用次數上限 2
+//   - 2: 自動套用的團報優惠
+//   - 3: 自動套用 , 尚未開始
+//   - 4: 自動套用 , 已停用
+func (s *PromotionRepoContractSuite) SetupTest() {
+	s.promotionRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, promotion := range []*entity.Promotion{
+		{Name: "暑期九折", Code: ptr("SUMMER10"), DiscountValue: 10, UsageLimit: 2},
+		{Name: "三人團報", DiscountValue: 15, MinSeats: 3},
+		{Name: "秋季優惠", DiscountValue: 5, StartsAt: ptr(s.now.Add(24 * time.Hour))},
+		{Name: "已停用", DiscountValue: 5, DeactivatedAt: ptr(s.now.Add(-time.Hour))},
+	} {
+		_, err := s.promotionRepo.Create(context.Background(), promotion)
+		s.Require().NoError(err)
+	}
+}
+
+// TestPromotionRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestPromotionRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &PromotionRepoContractSuite{newRepo: func(t *testing.T) PromotionRepository {
+			return NewPromotionRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &PromotionRepoContractSuite{newRepo: func(t *testing.T) PromotionRepository {
+			return NewPromotionMemoryRepository()
+		}})
+	})
+}
+
+func (s *PromotionRepoContractSuite) TestGetByCode() {
+	ctx := context.Background()
+
+	promotion, err := s.promotionRepo.GetByCode(ctx, "SUMMER10")
+	s.Require().NoError(err)
+	s.EqualValues(1, promotion.ID)
+
+	_, err = s.promotionRepo.GetByCode(ctx, "UNKNOWN")
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	// 優惠碼重複
+	_, err = s.promotionRepo.Create(ctx, &entity.Promotion{Name: "重複", Code: ptr("SUMMER10"), DiscountValue: 10})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+}
+
+func (s *PromotionRepoContractSuite) TestListAutomatic() {
+	promotions, err := s.promotionRepo.ListAutomatic(context.Background(), s.now)
+	s.Require().NoError(err)
+	s.Require().Len(promotions, 1)
+	s.EqualValues(2, promotions[0].ID)
+
+	promotions, err = s.promotionRepo.ListAutomatic(context.Background(), s.now.Add(48*time.Hour))
+	s.Require().NoError(err)
+	s.Len(promotions, 2)
+}
+
+func (s *PromotionRepoContractSuite) TestDeactivate() {
+	ctx := context.Background()
+
+	rows, err := s.promotionRepo.Deactivate(ctx, 2, s.now)
+	s.NoError(err)
+	s.EqualValues(1, rows)
+
+	// 已停用
+	rows, err = s.promotionRepo.Deactivate(ctx, 2, s.now)
+	s.NoError(err)
+	s.Zero(rows)
+
+	promotions, err := s.promotionRepo.ListAutomatic(ctx, s.now)
+	s.Require().NoError(err)
+	s.Empty(promotions)
+}
+
+// 使用次數達上限後不再增加 , 釋出訂單的使用紀錄後扣回
+func (s *PromotionRepoContractSuite) TestUsage() {
+	ctx := context.Background()
+
+	for i, orderID := range []uint{100, 101} {
+		rows, err := s.promotionRepo.IncrementUsage(ctx, 1)
+		s.Require().NoError(err)
+		s.EqualValues(1, rows, "redemption %d", i)
+		_, err = s.promotionRepo.CreateRedemption(ctx, &entity.PromotionRedemption{PromotionID: 1, StudentID: 10, OrderID: orderID, Discount: 300, Currency: "TWD"})
+		s.Require().NoError(err)
+	}
+
+	rows, err := s.promotionRepo.IncrementUsage(ctx, 1)
+	s.NoError(err)
+	s.Zero(rows)
+
+	count, err := s.promotionRepo.CountRedemptions(ctx, 1, 10)
+	s.NoError(err)
+	s.EqualValues(2, count)
+
+	redemptions, err := s.promotionRepo.FindRedemptions(ctx, []func(db *gorm.DB) *gorm.DB{entity.RedemptionOfOrder(101)})
+	s.Require().NoError(err)
+	s.Require().Len(redemptions, 1)
+	s.EqualValues(300, redemptions[0].Discount)
+
+	released, err := s.promotionRepo.ReleaseRedemptions(ctx, 100, s.now)
+	s.Require().NoError(err)
+	s.Require().Len(released, 1)
+	s.Equal(entity.RedemptionStatusReleased, released[0].Status)
+
+	// 已釋出的紀錄不是訂單的有效使用紀錄
+	redemptions, err = s.promotionRepo.FindRedemptions(ctx, []func(db *gorm.DB) *gorm.DB{entity.RedemptionOfOrder(100)})
+	s.NoError(err)
+	s.Empty(redemptions)
+
+	// 已釋出
+	released, err = s.promotionRepo.ReleaseRedemptions(ctx, 100, s.now)
+	s.NoError(err)
+	s.Empty(released)
+
+	promotion, err := s.promotionRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.EqualValues(1, promotion.UsedCount)
+
+	count, err = s.promotionRepo.CountRedemptions(ctx, 1, 10)
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	rows, err = s.promotionRepo.IncrementUsage(ctx, 1)
+	s.NoError(err)
+	s.EqualValues(1, rows)
+}
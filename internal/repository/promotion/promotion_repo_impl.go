@@ -0,0 +1,197 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ PromotionRepository = (*PromotionRepositoryImpl)(nil)
+
+// PromotionRepositoryImpl 實作 PromotionRepository 介面
+//
+// Example:
+//
+//	repo := promotion.NewPromotionRepository(db)
+//	promotion, err := repo.GetByID(ctx, 1)
+type PromotionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository 建立促銷活動資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 促銷活動資料庫操作實例
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+// Create 新增促銷活動
+func (r *PromotionRepositoryImpl) Create(ctx context.Context, promotion *entity.Promotion) (uint, error) {
+	defer metrics.ObserveRepoQuery("promotion", "Create", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(promotion).Error; err != nil {
+		return 0, err
+	}
+	return promotion.ID, nil
+}
+
+// GetByID 依促銷活動ID查詢促銷活動
+func (r *PromotionRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	defer metrics.ObserveRepoQuery("promotion", "GetByID", time.Now())
+
+	var promotion entity.Promotion
+	if err := repo.Conn(ctx, r.db).First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetByCode 依優惠碼查詢優惠券
+func (r *PromotionRepositoryImpl) GetByCode(ctx context.Context, code string) (*entity.Promotion, error) {
+	defer metrics.ObserveRepoQuery("promotion", "GetByCode", time.Now())
+
+	var promotion entity.Promotion
+	if err := repo.Conn(ctx, r.db).Where("code = ?", code).First(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// Find 查詢促銷活動
+func (r *PromotionRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Promotion, error) {
+	defer metrics.ObserveRepoQuery("promotion", "Find", time.Now())
+
+	var promotions []*entity.Promotion
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&promotions).
+		Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// ListAutomatic 查詢有效且自動套用的促銷活動
+func (r *PromotionRepositoryImpl) ListAutomatic(ctx context.Context, at time.Time) ([]*entity.Promotion, error) {
+	defer metrics.ObserveRepoQuery("promotion", "ListAutomatic", time.Now())
+
+	var promotions []*entity.Promotion
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.PromotionAutomatic(), entity.PromotionActive(at)).
+		Order("id").
+		Find(&promotions).
+		Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// Deactivate 停用促銷活動
+func (r *PromotionRepositoryImpl) Deactivate(ctx context.Context, id uint, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("promotion", "Deactivate", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Promotion{}).
+		Where("id = ? AND deactivated_at IS NULL", id).
+		Update("deactivated_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// IncrementUsage 使用次數加一
+// 以單一條件 UPDATE 檢查上限 , 併發的 UPDATE 會等待前一個 transaction 結束後重新檢查條件 , 不會超過上限
+func (r *PromotionRepositoryImpl) IncrementUsage(ctx context.Context, id uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("promotion", "IncrementUsage", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Promotion{}).
+		Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", id).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	return result.RowsAffected, result.Error
+}
+
+// CountRedemptions 計算學生對促銷活動的有效使用次數
+func (r *PromotionRepositoryImpl) CountRedemptions(ctx context.Context, promotionID, studentID uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("promotion", "CountRedemptions", time.Now())
+
+	var count int64
+	if err := repo.Conn(ctx, r.db).
+		Model(&entity.PromotionRedemption{}).
+		Scopes(entity.RedemptionOfStudent(promotionID, studentID)).
+		Count(&count).
+		Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateRedemption 新增使用紀錄
+func (r *PromotionRepositoryImpl) CreateRedemption(ctx context.Context, redemption *entity.PromotionRedemption) (uint, error) {
+	defer metrics.ObserveRepoQuery("promotion", "CreateRedemption", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(redemption).Error; err != nil {
+		return 0, err
+	}
+	return redemption.ID, nil
+}
+
+// FindRedemptions 查詢使用紀錄 , 依 id 排序
+func (r *PromotionRepositoryImpl) FindRedemptions(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.PromotionRedemption, error) {
+	defer metrics.ObserveRepoQuery("promotion", "FindRedemptions", time.Now())
+
+	var redemptions []*entity.PromotionRedemption
+	if err := repo.Conn(ctx, r.db).
+		Scopes(conditions...).
+		Order("id").
+		Find(&redemptions).
+		Error; err != nil {
+		return nil, err
+	}
+	return redemptions, nil
+}
+
+// ReleaseRedemptions 釋出訂單的有效使用紀錄
+// 使用紀錄以目前狀態為條件更新 , 已被其他 transaction 釋出的紀錄不會重複扣回使用次數
+func (r *PromotionRepositoryImpl) ReleaseRedemptions(ctx context.Context, orderID uint, at time.Time) ([]*entity.PromotionRedemption, error) {
+	defer metrics.ObserveRepoQuery("promotion", "ReleaseRedemptions", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+
+	var redemptions []*entity.PromotionRedemption
+	if err := db.Scopes(entity.RedemptionOfOrder(orderID)).Order("id").Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+
+	released := make([]*entity.PromotionRedemption, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		result := db.Model(&entity.PromotionRedemption{}).
+			Where("id = ? AND status = ?", redemption.ID, entity.RedemptionStatusActive).
+			Updates(map[string]interface{}{"status": entity.RedemptionStatusReleased, "released_at": at})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := db.Model(&entity.Promotion{}).
+			Where("id = ? AND used_count > 0", redemption.PromotionID).
+			Update("used_count", gorm.Expr("used_count - 1")).
+			Error; err != nil {
+			return nil, err
+		}
+		redemption.Status = entity.RedemptionStatusReleased
+		redemption.ReleasedAt = &at
+		released = append(released, redemption)
+	}
+	return released, nil
+}
@@ -0,0 +1,75 @@
+package promotion
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// PromotionRepository 定義促銷活動與使用紀錄的資料存取介面
+//
+// Example:
+//
+//	var repo PromotionRepository
+//	promotion, err := repo.GetByCode(ctx, "SUMMER10")
+type PromotionRepository interface {
+	// Create 新增促銷活動
+	// 參數: ctx - context, promotion - 促銷活動
+	// 回傳: 新增後的促銷活動ID, 錯誤訊息 , 優惠碼已存在時回傳 gorm.ErrDuplicatedKey
+	Create(ctx context.Context, promotion *entity.Promotion) (uint, error)
+
+	// GetByID 依促銷活動ID查詢促銷活動
+	// 參數: ctx - context, id - 促銷活動ID
+	// 回傳: 促銷活動, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Promotion, error)
+
+	// GetByCode 依優惠碼查詢優惠券
+	// 參數: ctx - context, code - 優惠碼 , 須為大寫
+	// 回傳: 優惠券, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByCode(ctx context.Context, code string) (*entity.Promotion, error)
+
+	// Find 取得促銷活動清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 促銷活動切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Promotion, error)
+
+	// ListAutomatic 查詢於 at 有效且自動套用的促銷活動
+	// 參數: ctx - context, at - 查詢時間
+	// 回傳: 促銷活動 , 依 id 排序, 錯誤訊息
+	ListAutomatic(ctx context.Context, at time.Time) ([]*entity.Promotion, error)
+
+	// Deactivate 停用促銷活動 , 只有尚未停用的促銷活動才會更新
+	// 參數: ctx - context, id - 促銷活動ID, at - 停用時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示促銷活動不存在或已停用
+	Deactivate(ctx context.Context, id uint, at time.Time) (int64, error)
+
+	// IncrementUsage 使用次數加一 , 已達總使用次數上限時不更新
+	// 於 transaction 中呼叫時會鎖定促銷活動至 transaction 結束 , 併發使用同一個促銷活動時依序執行
+	// 參數: ctx - context, id - 促銷活動ID
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示促銷活動不存在或已達上限
+	IncrementUsage(ctx context.Context, id uint) (int64, error)
+
+	// CountRedemptions 計算學生對促銷活動的有效使用次數
+	// 參數: ctx - context, promotionID - 促銷活動ID, studentID - 學生 user ID
+	// 回傳: 使用次數, 錯誤訊息
+	CountRedemptions(ctx context.Context, promotionID, studentID uint) (int64, error)
+
+	// CreateRedemption 新增使用紀錄
+	// 參數: ctx - context, redemption - 使用紀錄
+	// 回傳: 新增後的紀錄ID, 錯誤訊息
+	CreateRedemption(ctx context.Context, redemption *entity.PromotionRedemption) (uint, error)
+
+	// FindRedemptions 查詢使用紀錄
+	// 參數: ctx - context, conditions - 查詢條件
+	// 回傳: 使用紀錄 , 依 id 排序, 錯誤訊息
+	FindRedemptions(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.PromotionRedemption, error)
+
+	// ReleaseRedemptions 釋出訂單的有效使用紀錄 , 並扣回促銷活動的使用次數 , 須於 transaction 中呼叫
+	// 參數: ctx - context, orderID - 訂單ID, at - 釋出時間
+	// 回傳: 釋出的使用紀錄, 錯誤訊息
+	ReleaseRedemptions(ctx context.Context, orderID uint, at time.Time) ([]*entity.PromotionRedemption, error)
+}
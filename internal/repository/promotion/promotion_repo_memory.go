@@ -0,0 +1,145 @@
+package promotion
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ PromotionRepository = (*PromotionMemoryRepository)(nil)
+
+// PromotionMemoryRepository 以記憶體實作 PromotionRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援鎖定
+//
+// Example:
+//
+//	repo := promotion.NewPromotionMemoryRepository()
+//	id, err := repo.Create(ctx, &entity.Promotion{Name: "早鳥九折", DiscountValue: 10})
+type PromotionMemoryRepository struct {
+	promotions  *memory.Table[entity.Promotion]
+	redemptions *memory.Table[entity.PromotionRedemption]
+}
+
+// NewPromotionMemoryRepository 建立記憶體促銷活動資料操作實例
+// 回傳: 促銷活動資料操作實例
+func NewPromotionMemoryRepository() PromotionRepository {
+	return &PromotionMemoryRepository{
+		promotions:  memory.NewTable[entity.Promotion](),
+		redemptions: memory.NewTable[entity.PromotionRedemption](),
+	}
+}
+
+// Create 新增促銷活動 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *PromotionMemoryRepository) Create(ctx context.Context, promotion *entity.Promotion) (uint, error) {
+	if err := r.promotions.Create(promotion); err != nil {
+		return 0, err
+	}
+	return promotion.ID, nil
+}
+
+// GetByID 依促銷活動ID查詢促銷活動
+func (r *PromotionMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	return r.promotions.Get(id)
+}
+
+// GetByCode 依優惠碼查詢優惠券
+func (r *PromotionMemoryRepository) GetByCode(ctx context.Context, code string) (*entity.Promotion, error) {
+	promotions, err := r.promotions.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("code = ?", code) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(promotions) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return promotions[0], nil
+}
+
+// Find 查詢促銷活動
+func (r *PromotionMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Promotion, error) {
+	return r.promotions.Find(pageInfo, conditions)
+}
+
+// ListAutomatic 查詢有效且自動套用的促銷活動 , 依 id 排序
+func (r *PromotionMemoryRepository) ListAutomatic(ctx context.Context, at time.Time) ([]*entity.Promotion, error) {
+	return r.promotions.Where([]func(db *gorm.DB) *gorm.DB{entity.PromotionAutomatic(), entity.PromotionActive(at)})
+}
+
+// Deactivate 停用促銷活動 , 只有尚未停用的促銷活動才會更新
+func (r *PromotionMemoryRepository) Deactivate(ctx context.Context, id uint, at time.Time) (int64, error) {
+	return r.promotions.Update(id,
+		func(promotion *entity.Promotion) bool { return promotion.DeactivatedAt == nil },
+		func(promotion *entity.Promotion) { promotion.DeactivatedAt = &at },
+	)
+}
+
+// IncrementUsage 使用次數加一 , 已達總使用次數上限時不更新
+func (r *PromotionMemoryRepository) IncrementUsage(ctx context.Context, id uint) (int64, error) {
+	return r.promotions.Update(id,
+		func(promotion *entity.Promotion) bool { return !promotion.Exhausted() },
+		func(promotion *entity.Promotion) { promotion.UsedCount++ },
+	)
+}
+
+// CountRedemptions 計算學生對促銷活動的有效使用次數
+func (r *PromotionMemoryRepository) CountRedemptions(ctx context.Context, promotionID, studentID uint) (int64, error) {
+	redemptions, err := r.redemptions.Where([]func(db *gorm.DB) *gorm.DB{entity.RedemptionOfStudent(promotionID, studentID)})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(redemptions)), nil
+}
+
+// CreateRedemption 新增使用紀錄
+func (r *PromotionMemoryRepository) CreateRedemption(ctx context.Context, redemption *entity.PromotionRedemption) (uint, error) {
+	if err := r.redemptions.Create(redemption); err != nil {
+		return 0, err
+	}
+	return redemption.ID, nil
+}
+
+// FindRedemptions 查詢使用紀錄 , 依 id 排序
+func (r *PromotionMemoryRepository) FindRedemptions(ctx context.Context, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.PromotionRedemption, error) {
+	return r.redemptions.Where(conditions)
+}
+
+// ReleaseRedemptions 釋出訂單的有效使用紀錄 , 並扣回促銷活動的使用次數
+func (r *PromotionMemoryRepository) ReleaseRedemptions(ctx context.Context, orderID uint, at time.Time) ([]*entity.PromotionRedemption, error) {
+	redemptions, err := r.redemptions.Where([]func(db *gorm.DB) *gorm.DB{entity.RedemptionOfOrder(orderID)})
+	if err != nil {
+		return nil, err
+	}
+
+	released := make([]*entity.PromotionRedemption, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		rows, err := r.redemptions.Update(redemption.ID,
+			func(row *entity.PromotionRedemption) bool { return row.Status == entity.RedemptionStatusActive },
+			func(row *entity.PromotionRedemption) {
+				row.Status = entity.RedemptionStatusReleased
+				row.ReleasedAt = &at
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if rows == 0 {
+			continue
+		}
+		if _, err := r.promotions.Update(redemption.PromotionID,
+			func(promotion *entity.Promotion) bool { return promotion.UsedCount > 0 },
+			func(promotion *entity.Promotion) { promotion.UsedCount-- },
+		); err != nil {
+			return nil, err
+		}
+		redemption.Status = entity.RedemptionStatusReleased
+		redemption.ReleasedAt = &at
+		released = append(released, redemption)
+	}
+	return released, nil
+}
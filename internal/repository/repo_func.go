@@ -1,13 +1,12 @@
 package repository
 
 import (
-	"os"
-	"os/signal"
-	"syscall"
-	"testing"
+	"context"
+	"errors"
+	"fmt"
+	"time"
 
-	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
@@ -19,39 +18,6 @@ type RepoPageInfo struct {
 	Order    string `json:"order" default:"desc"`
 }
 
-// RepoTestInit 初始化 repository 測試環境
-// 使用 embedded-postgres 啟動 postgres 測試環境
-// 測試完畢後停止 postgres
-func RepoTestInit(m *testing.M) int {
-	postgres := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().Port(5433))
-
-	err := postgres.Start()
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to start postgres")
-	}
-
-	// 註冊 signal handler
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		log.Info().Msg("Received interrupt, stopping postgres...")
-		if err := postgres.Stop(); err != nil {
-			log.Error().Err(err).Msg("failed to stop postgres on interrupt")
-		}
-		os.Exit(1)
-	}()
-
-	code := m.Run()
-
-	err = postgres.Stop()
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to stop postgres")
-	}
-
-	return code
-}
-
 func Paginate(pageInfo *RepoPageInfo) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if pageInfo.Page <= 0 {
@@ -62,3 +28,45 @@ func Paginate(pageInfo *RepoPageInfo) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(offset).Limit(pageInfo.PageSize)
 	}
 }
+
+// OnlyDeleted 只查詢已軟刪除的資料
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL")
+}
+
+// Purger 可清除已軟刪除資料的 repository
+type Purger interface {
+	// Purge 永久刪除刪除時間早於 olderThan 的資料
+	// 參數: ctx - context, olderThan - 刪除時間上限
+	// 回傳: 刪除數量, 錯誤訊息
+	Purge(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// PurgeJob 建立清除已軟刪除資料的背景工作 , 刪除超過 retention 的資料
+// 參數: retention - 軟刪除資料保留時間, purgers - 名稱與 repository , 名稱用於 log
+//
+// Example:
+//
+//	runner.Every("purge_deleted", 24*time.Hour, repository.PurgeJob(90*24*time.Hour, map[string]repository.Purger{
+//		"teacher": teacherRepo,
+//	}))
+func PurgeJob(retention time.Duration, purgers map[string]Purger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		olderThan := time.Now().Add(-retention)
+
+		var errs []error
+		for name, purger := range purgers {
+			count, err := purger.Purge(ctx, olderThan)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to purge %s: %w", name, err))
+				continue
+			}
+			zerolog.Ctx(ctx).Info().
+				Str("repository", name).
+				Int64("count", count).
+				Time("older_than", olderThan).
+				Msg("purged deleted records")
+		}
+		return errors.Join(errs...)
+	}
+}
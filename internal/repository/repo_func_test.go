@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPurger 記錄 Purge 的參數 , 回傳固定結果
+type stubPurger struct {
+	olderThan time.Time
+	count     int64
+	err       error
+}
+
+func (p *stubPurger) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	p.olderThan = olderThan
+	return p.count, p.err
+}
+
+func TestPurgeJob(t *testing.T) {
+	errPurge := errors.New("purge failed")
+
+	tests := []struct {
+		name       string
+		purgers    map[string]*stubPurger
+		assertFunc func(t *testing.T, purgers map[string]*stubPurger, err error)
+	}{
+		{
+			name: "success",
+			purgers: map[string]*stubPurger{
+				"teacher": {count: 2},
+				"course":  {count: 1},
+			},
+			assertFunc: func(t *testing.T, purgers map[string]*stubPurger, err error) {
+				assert.NoError(t, err)
+				for _, p := range purgers {
+					assert.WithinDuration(t, time.Now().Add(-time.Hour), p.olderThan, time.Minute)
+				}
+			},
+		},
+		{
+			name: "continue after error",
+			purgers: map[string]*stubPurger{
+				"teacher": {err: errPurge},
+				"course":  {count: 1},
+			},
+			assertFunc: func(t *testing.T, purgers map[string]*stubPurger, err error) {
+				assert.ErrorIs(t, err, errPurge)
+				assert.ErrorContains(t, err, "teacher")
+				assert.False(t, purgers["course"].olderThan.IsZero())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			purgers := make(map[string]Purger, len(test.purgers))
+			for name, p := range test.purgers {
+				purgers[name] = p
+			}
+
+			err := PurgeJob(time.Hour, purgers)(context.Background())
+			test.assertFunc(t, test.purgers, err)
+		})
+	}
+}
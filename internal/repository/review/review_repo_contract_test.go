@@ -0,0 +1,160 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// ReviewRepoContractSuite ReviewRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type ReviewRepoContractSuite struct {
+	suite.Suite
+	newRepo    func(t *testing.T) ReviewRepository
+	reviewRepo ReviewRepository
+	now        time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增報名 1 (課程 1 學生 10) 的評價
+func (s *ReviewRepoContractSuite) SetupTest() {
+	s.reviewRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.reviewRepo.Create(context.Background(), &entity.Review{EnrollmentID: 1, CourseID: 1, StudentID: 10, Rating: 5, Comment: "內容紮實"})
+	s.Require().NoError(err)
+}
+
+// TestReviewRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestReviewRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &ReviewRepoContractSuite{newRepo: func(t *testing.T) ReviewRepository {
+			return NewReviewRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &ReviewRepoContractSuite{newRepo: func(t *testing.T) ReviewRepository {
+			return NewReviewMemoryRepository()
+		}})
+	})
+}
+
+func (s *ReviewRepoContractSuite) TestCreate() {
+	ctx := context.Background()
+
+	review, err := s.reviewRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.EqualValues(5, review.Rating)
+	s.Equal("內容紮實", review.Comment)
+	s.Equal(entity.ReviewStatusPending, review.Status)
+	s.Nil(review.ModeratedAt)
+
+	// 報名已有評價
+	_, err = s.reviewRepo.Create(ctx, &entity.Review{EnrollmentID: 1, CourseID: 1, StudentID: 10, Rating: 3})
+	s.ErrorIs(err, gorm.ErrDuplicatedKey)
+
+	_, err = s.reviewRepo.GetByID(ctx, 100)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *ReviewRepoContractSuite) TestFind() {
+	ctx := context.Background()
+	for _, review := range []*entity.Review{
+		{EnrollmentID: 2, CourseID: 1, StudentID: 11, Rating: 3, Status: entity.ReviewStatusApproved}, // id 2
+		{EnrollmentID: 3, CourseID: 2, StudentID: 10, Rating: 4, Status: entity.ReviewStatusApproved}, // id 3
+	} {
+		_, err := s.reviewRepo.Create(ctx, review)
+		s.Require().NoError(err)
+	}
+
+	pageInfo := &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}
+	reviews, err := s.reviewRepo.Find(ctx, pageInfo, []func(db *gorm.DB) *gorm.DB{
+		entity.ReviewOfCourse(1),
+		entity.InReviewStatus([]entity.ReviewStatus{entity.ReviewStatusApproved}),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(reviews, 1)
+	s.EqualValues(2, reviews[0].ID)
+
+	reviews, err = s.reviewRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "rating", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.ReviewOfStudent(10)})
+	s.Require().NoError(err)
+	s.Require().Len(reviews, 2)
+	s.EqualValues(1, reviews[0].ID)
+	s.EqualValues(3, reviews[1].ID)
+}
+
+func (s *ReviewRepoContractSuite) TestUpdateStatus() {
+	ctx := context.Background()
+
+	// 目前狀態不符
+	affected, err := s.reviewRepo.UpdateStatus(ctx, 1, entity.ReviewStatusApproved, entity.ReviewStatusRejected, 1, "", s.now)
+	s.Require().NoError(err)
+	s.Zero(affected)
+
+	affected, err = s.reviewRepo.UpdateStatus(ctx, 1, entity.ReviewStatusPending, entity.ReviewStatusRejected, 1, "含有廣告內容", s.now)
+	s.Require().NoError(err)
+	s.EqualValues(1, affected)
+
+	review, err := s.reviewRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal(entity.ReviewStatusRejected, review.Status)
+	s.EqualValues(1, review.ModeratorID)
+	s.Equal("含有廣告內容", review.ModerationNote)
+	s.Require().NotNil(review.ModeratedAt)
+	s.True(review.ModeratedAt.Equal(s.now))
+
+	// 修改內容後改為待審核
+	affected, err = s.reviewRepo.UpdateContent(ctx, 1, entity.ReviewStatusPending, 4, "修改後的評論")
+	s.Require().NoError(err)
+	s.Zero(affected)
+
+	affected, err = s.reviewRepo.UpdateContent(ctx, 1, entity.ReviewStatusRejected, 4, "修改後的評論")
+	s.Require().NoError(err)
+	s.EqualValues(1, affected)
+
+	review, err = s.reviewRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal(entity.ReviewStatusPending, review.Status)
+	s.EqualValues(4, review.Rating)
+	s.Equal("修改後的評論", review.Comment)
+}
+
+func (s *ReviewRepoContractSuite) TestReplaceTeacherRatings() {
+	ctx := context.Background()
+	_, err := s.reviewRepo.Create(ctx, &entity.Review{EnrollmentID: 2, CourseID: 1, StudentID: 11, Rating: 4})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.reviewRepo.ReplaceTeacherRatings(ctx, 1, []*entity.ReviewTeacherRating{
+		{TeacherID: 1, Rating: 5},
+		{TeacherID: 2, Rating: 4},
+	}))
+	s.Require().NoError(s.reviewRepo.ReplaceTeacherRatings(ctx, 2, []*entity.ReviewTeacherRating{{TeacherID: 1, Rating: 3}}))
+
+	// 取代後原本的評分不再出現 , 可再次評分同一位教師
+	s.Require().NoError(s.reviewRepo.ReplaceTeacherRatings(ctx, 1, []*entity.ReviewTeacherRating{{TeacherID: 1, Rating: 2}}))
+
+	ratings, err := s.reviewRepo.ListTeacherRatings(ctx, []uint{1, 2})
+	s.Require().NoError(err)
+	s.Require().Len(ratings, 2)
+	s.EqualValues(2, ratings[0].ReviewID)
+	s.EqualValues(3, ratings[0].Rating)
+	s.EqualValues(1, ratings[1].ReviewID)
+	s.EqualValues(1, ratings[1].TeacherID)
+	s.EqualValues(2, ratings[1].Rating)
+
+	s.Require().NoError(s.reviewRepo.ReplaceTeacherRatings(ctx, 1, nil))
+	ratings, err = s.reviewRepo.ListTeacherRatings(ctx, []uint{1})
+	s.Require().NoError(err)
+	s.Empty(ratings)
+
+	ratings, err = s.reviewRepo.ListTeacherRatings(ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(ratings)
+}
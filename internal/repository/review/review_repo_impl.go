@@ -0,0 +1,145 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ ReviewRepository = (*ReviewRepositoryImpl)(nil)
+
+// ReviewRepositoryImpl 實作 ReviewRepository 介面
+//
+// Example:
+//
+//	repo := review.NewReviewRepository(db)
+//	review, err := repo.GetByID(ctx, 1)
+type ReviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository 建立課程評價資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 課程評價資料庫操作實例
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &ReviewRepositoryImpl{db: db}
+}
+
+// Create 新增評價
+func (r *ReviewRepositoryImpl) Create(ctx context.Context, review *entity.Review) (uint, error) {
+	defer metrics.ObserveRepoQuery("review", "Create", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(review).Error; err != nil {
+		return 0, err
+	}
+	return review.ID, nil
+}
+
+// GetByID 依評價ID查詢評價
+func (r *ReviewRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Review, error) {
+	defer metrics.ObserveRepoQuery("review", "GetByID", time.Now())
+
+	var review entity.Review
+	if err := repo.Conn(ctx, r.db).First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Find 查詢評價
+func (r *ReviewRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Review, error) {
+	defer metrics.ObserveRepoQuery("review", "Find", time.Now())
+
+	var reviews []*entity.Review
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&reviews).
+		Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// UpdateContent 修改評分與評論並改為待審核
+// 以 id 與目前狀態 from 為條件更新 , 避免與審核同時進行時覆蓋審核結果
+func (r *ReviewRepositoryImpl) UpdateContent(ctx context.Context, id uint, from entity.ReviewStatus, rating uint, comment string) (int64, error) {
+	defer metrics.ObserveRepoQuery("review", "UpdateContent", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Review{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"rating":  rating,
+			"comment": comment,
+			"status":  entity.ReviewStatusPending,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateStatus 更新審核狀態
+// 以 id 與目前狀態 from 為條件更新 , 避免併發審核重複計入評分統計
+func (r *ReviewRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to entity.ReviewStatus, moderatorID uint, note string, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("review", "UpdateStatus", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.Review{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":          to,
+			"moderator_id":    moderatorID,
+			"moderated_at":    at.UTC(),
+			"moderation_note": note,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ListTeacherRatings 查詢評價中對授課教師的評分 , 依 id 排序
+func (r *ReviewRepositoryImpl) ListTeacherRatings(ctx context.Context, reviewIDs []uint) ([]*entity.ReviewTeacherRating, error) {
+	defer metrics.ObserveRepoQuery("review", "ListTeacherRatings", time.Now())
+
+	var ratings []*entity.ReviewTeacherRating
+	if len(reviewIDs) == 0 {
+		return ratings, nil
+	}
+	if err := repo.Conn(ctx, r.db).
+		Where("review_id IN (?)", reviewIDs).
+		Order("id").
+		Find(&ratings).
+		Error; err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// ReplaceTeacherRatings 以 ratings 取代評價中對授課教師的評分
+// 先永久刪除原本的評分再新增 , 須於 transaction 中呼叫避免中途失敗時遺失評分
+func (r *ReviewRepositoryImpl) ReplaceTeacherRatings(ctx context.Context, reviewID uint, ratings []*entity.ReviewTeacherRating) error {
+	defer metrics.ObserveRepoQuery("review", "ReplaceTeacherRatings", time.Now())
+
+	db := repo.Conn(ctx, r.db)
+	if err := db.Unscoped().Where("review_id = ?", reviewID).Delete(&entity.ReviewTeacherRating{}).Error; err != nil {
+		return fmt.Errorf("failed to delete teacher ratings of review %d: %w", reviewID, err)
+	}
+	if len(ratings) == 0 {
+		return nil
+	}
+
+	for _, rating := range ratings {
+		rating.ReviewID = reviewID
+	}
+	if err := db.Create(&ratings).Error; err != nil {
+		return fmt.Errorf("failed to create teacher ratings of review %d: %w", reviewID, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// ReviewRepository 定義課程評價的資料存取介面
+//
+// Example:
+//
+//	var repo ReviewRepository
+//	id, err := repo.Create(ctx, review)
+//	ratings, err := repo.ListTeacherRatings(ctx, []uint{id})
+type ReviewRepository interface {
+	// Create 新增評價
+	// 參數: ctx - context, review - 評價
+	// 回傳: 新增後的評價ID, 錯誤訊息 , 報名已有評價時回傳 gorm.ErrDuplicatedKey
+	Create(ctx context.Context, review *entity.Review) (uint, error)
+
+	// GetByID 依評價ID查詢評價
+	// 參數: ctx - context, id - 評價ID
+	// 回傳: 評價, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*entity.Review, error)
+
+	// Find 取得評價清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 評價切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Review, error)
+
+	// UpdateContent 修改評分與評論並改為待審核 , 只有目前狀態為 from 時才會更新
+	// 參數: ctx - context, id - 評價ID, from - 目前狀態, rating - 課程評分, comment - 評論
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示評價不存在或狀態已被變更
+	UpdateContent(ctx context.Context, id uint, from entity.ReviewStatus, rating uint, comment string) (int64, error)
+
+	// UpdateStatus 更新審核狀態 , 只有目前狀態為 from 時才會更新
+	// 參數: ctx - context, id - 評價ID, from - 目前狀態, to - 新狀態, moderatorID - 審核的管理者 user ID, note - 審核備註, at - 審核時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示評價不存在或狀態已被變更
+	UpdateStatus(ctx context.Context, id uint, from, to entity.ReviewStatus, moderatorID uint, note string, at time.Time) (int64, error)
+
+	// ListTeacherRatings 查詢評價中對授課教師的評分
+	// 參數: ctx - context, reviewIDs - 評價ID
+	// 回傳: 教師評分 , 依 id 排序, 錯誤訊息
+	ListTeacherRatings(ctx context.Context, reviewIDs []uint) ([]*entity.ReviewTeacherRating, error)
+
+	// ReplaceTeacherRatings 以 ratings 取代評價中對授課教師的評分 , 須於 transaction 中呼叫
+	// 參數: ctx - context, reviewID - 評價ID, ratings - 新的教師評分 , 空切片表示不評分教師
+	// 回傳: 錯誤訊息
+	ReplaceTeacherRatings(ctx context.Context, reviewID uint, ratings []*entity.ReviewTeacherRating) error
+}
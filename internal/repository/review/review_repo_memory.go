@@ -0,0 +1,110 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ ReviewRepository = (*ReviewMemoryRepository)(nil)
+
+// ReviewMemoryRepository 以記憶體實作 ReviewRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援 transaction
+//
+// Example:
+//
+//	repo := review.NewReviewMemoryRepository()
+//	id, err := repo.Create(ctx, review)
+type ReviewMemoryRepository struct {
+	table   *memory.Table[entity.Review]
+	ratings *memory.Table[entity.ReviewTeacherRating]
+}
+
+// NewReviewMemoryRepository 建立記憶體課程評價資料操作實例
+// 回傳: 課程評價資料操作實例
+func NewReviewMemoryRepository() ReviewRepository {
+	return &ReviewMemoryRepository{
+		table:   memory.NewTable[entity.Review](),
+		ratings: memory.NewTable[entity.ReviewTeacherRating](),
+	}
+}
+
+// Create 新增評價 , 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (r *ReviewMemoryRepository) Create(ctx context.Context, review *entity.Review) (uint, error) {
+	if err := r.table.Create(review); err != nil {
+		return 0, err
+	}
+	return review.ID, nil
+}
+
+// GetByID 依評價ID查詢評價
+func (r *ReviewMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Review, error) {
+	return r.table.Get(id)
+}
+
+// Find 查詢評價
+func (r *ReviewMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Review, error) {
+	return r.table.Find(pageInfo, conditions)
+}
+
+// UpdateContent 修改評分與評論並改為待審核 , 只有目前狀態為 from 時才會更新
+func (r *ReviewMemoryRepository) UpdateContent(ctx context.Context, id uint, from entity.ReviewStatus, rating uint, comment string) (int64, error) {
+	return r.table.Update(id,
+		func(review *entity.Review) bool { return review.Status == from },
+		func(review *entity.Review) {
+			review.Rating = rating
+			review.Comment = comment
+			review.Status = entity.ReviewStatusPending
+		},
+	)
+}
+
+// UpdateStatus 更新審核狀態 , 只有目前狀態為 from 時才會更新
+func (r *ReviewMemoryRepository) UpdateStatus(ctx context.Context, id uint, from, to entity.ReviewStatus, moderatorID uint, note string, at time.Time) (int64, error) {
+	return r.table.Update(id,
+		func(review *entity.Review) bool { return review.Status == from },
+		func(review *entity.Review) {
+			at := at.UTC()
+			review.Status = to
+			review.ModeratorID = moderatorID
+			review.ModeratedAt = &at
+			review.ModerationNote = note
+		},
+	)
+}
+
+// ListTeacherRatings 查詢評價中對授課教師的評分 , 依 id 排序
+func (r *ReviewMemoryRepository) ListTeacherRatings(ctx context.Context, reviewIDs []uint) ([]*entity.ReviewTeacherRating, error) {
+	if len(reviewIDs) == 0 {
+		return []*entity.ReviewTeacherRating{}, nil
+	}
+	return r.ratings.Where([]func(db *gorm.DB) *gorm.DB{
+		func(db *gorm.DB) *gorm.DB { return db.Where("review_id IN (?)", reviewIDs) },
+	})
+}
+
+// ReplaceTeacherRatings 以 ratings 取代評價中對授課教師的評分
+func (r *ReviewMemoryRepository) ReplaceTeacherRatings(ctx context.Context, reviewID uint, ratings []*entity.ReviewTeacherRating) error {
+	existing, err := r.ListTeacherRatings(ctx, []uint{reviewID})
+	if err != nil {
+		return err
+	}
+	for _, rating := range existing {
+		if _, err := r.ratings.Delete(rating.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, rating := range ratings {
+		rating.ReviewID = reviewID
+		if err := r.ratings.Create(rating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// SessionRepoContractSuite SessionRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type SessionRepoContractSuite struct {
+	suite.Suite
+	newRepo     func(t *testing.T) SessionRepository
+	sessionRepo SessionRepository
+	now         time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增課程 1 的三次上課與課程 2 的一次上課
+func (s *SessionRepoContractSuite) SetupTest() {
+	s.sessionRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC)
+
+	count, err := s.sessionRepo.Ensure(context.Background(),
+		newContractSession(1, 1, s.now.Add(11*time.Hour)),             // id 1
+		newContractSession(1, 2, s.now.Add(2*24*time.Hour)),           // id 2
+		newContractSession(1, 1, s.now.Add(7*24*time.Hour)),           // id 3
+		newContractSession(2, 3, s.now.Add(11*time.Hour+time.Minute)), // id 4
+	)
+	s.Require().NoError(err)
+	s.Require().EqualValues(4, count)
+}
+
+// TestSessionRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestSessionRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &SessionRepoContractSuite{newRepo: func(t *testing.T) SessionRepository {
+			return NewSessionRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &SessionRepoContractSuite{newRepo: func(t *testing.T) SessionRepository {
+			return NewSessionMemoryRepository()
+		}})
+	})
+}
+
+func (s *SessionRepoContractSuite) TestEnsureIgnoresExisting() {
+	existing := newContractSession(1, 1, s.now.Add(11*time.Hour))
+	existing.EndAt = existing.StartAt.Add(time.Hour)
+
+	count, err := s.sessionRepo.Ensure(context.Background(), existing, newContractSession(1, 1, s.now.Add(14*24*time.Hour)))
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	session, err := s.sessionRepo.GetByID(context.Background(), 1)
+	s.Require().NoError(err)
+	s.True(session.EndAt.Equal(session.StartAt.Add(2 * time.Hour)))
+}
+
+func (s *SessionRepoContractSuite) TestFind() {
+	tests := []struct {
+		name       string
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, sessions []*courseEntity.CourseSession, err error)
+	}{
+		{
+			name:       "of course",
+			conditions: []func(db *gorm.DB) *gorm.DB{courseEntity.SessionOfCourse(1)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2, 3}, sessionIDs(sessions))
+			},
+		},
+		{
+			name: "start between",
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				courseEntity.SessionOfCourse(1),
+				courseEntity.SessionStartBetween(s.now, s.now.Add(3*24*time.Hour)),
+			},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2}, sessionIDs(sessions))
+			},
+		},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			sessions, err := s.sessionRepo.Find(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "start_at", Order: "asc"}, tt.conditions)
+			tt.assertFunc(s.T(), sessions, err)
+		})
+	}
+}
+
+func (s *SessionRepoContractSuite) TestListDueForReminder() {
+	ctx := context.Background()
+
+	sessions, err := s.sessionRepo.ListDueForReminder(ctx, 1, s.now, s.now.Add(3*24*time.Hour))
+	s.Require().NoError(err)
+	s.Equal([]uint{1, 2}, sessionIDs(sessions))
+
+	// 已提醒與已取消的上課不包含在內
+	rowsAffected, err := s.sessionRepo.MarkReminded(ctx, 1, s.now)
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+	rowsAffected, err = s.sessionRepo.Cancel(ctx, 2, "颱風停課", s.now)
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	sessions, err = s.sessionRepo.ListDueForReminder(ctx, 1, s.now, s.now.Add(3*24*time.Hour))
+	s.Require().NoError(err)
+	s.Empty(sessions)
+
+	// 已開始的上課不包含在內
+	sessions, err = s.sessionRepo.ListDueForReminder(ctx, 2, s.now.Add(11*time.Hour+time.Minute), s.now.Add(24*time.Hour))
+	s.Require().NoError(err)
+	s.Empty(sessions)
+}
+
+func (s *SessionRepoContractSuite) TestMarkReminded() {
+	ctx := context.Background()
+
+	rowsAffected, err := s.sessionRepo.MarkReminded(ctx, 1, s.now)
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	rowsAffected, err = s.sessionRepo.MarkReminded(ctx, 1, s.now.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+
+	session, err := s.sessionRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().NotNil(session.RemindedAt)
+	s.True(session.RemindedAt.Equal(s.now))
+}
+
+func (s *SessionRepoContractSuite) TestCancel() {
+	ctx := context.Background()
+
+	rowsAffected, err := s.sessionRepo.Cancel(ctx, 3, "講師請假", s.now)
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	session, err := s.sessionRepo.GetByID(ctx, 3)
+	s.Require().NoError(err)
+	s.Equal(courseEntity.SessionStatusCancelled, session.Status)
+	s.Equal("講師請假", session.CancelReason)
+	s.Require().NotNil(session.CancelledAt)
+	s.True(session.CancelledAt.Equal(s.now))
+
+	rowsAffected, err = s.sessionRepo.Cancel(ctx, 3, "講師請假", s.now)
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.sessionRepo.Cancel(ctx, 100, "", s.now)
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+}
+
+func (s *SessionRepoContractSuite) TestSetMeeting() {
+	ctx := context.Background()
+	meeting := func(id uint, meetingID string) *courseEntity.CourseSession {
+		session := &courseEntity.CourseSession{MeetingProvider: "fake", MeetingID: meetingID, JoinURL: "https://example.com/" + meetingID, HostKey: "123456"}
+		session.ID = id
+		return session
+	}
+
+	rowsAffected, err := s.sessionRepo.SetMeeting(ctx, meeting(1, "m1"))
+	s.Require().NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	session, err := s.sessionRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal("fake", session.MeetingProvider)
+	s.Equal("m1", session.MeetingID)
+	s.Equal("https://example.com/m1", session.JoinURL)
+	s.Equal("123456", session.HostKey)
+
+	// 已有會議時不覆蓋
+	rowsAffected, err = s.sessionRepo.SetMeeting(ctx, meeting(1, "m2"))
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+
+	found, err := s.sessionRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{courseEntity.SessionOfCourse(1), courseEntity.SessionWithoutMeeting()})
+	s.Require().NoError(err)
+	s.Len(found, 2)
+
+	// 已取消的上課不建立會議
+	_, err = s.sessionRepo.Cancel(ctx, 2, "講師請假", s.now)
+	s.Require().NoError(err)
+	rowsAffected, err = s.sessionRepo.SetMeeting(ctx, meeting(2, "m3"))
+	s.Require().NoError(err)
+	s.Zero(rowsAffected)
+}
+
+// newContractSession 建立測試用上課 , 上課兩小時
+func newContractSession(courseID, patternID uint, startAt time.Time) *courseEntity.CourseSession {
+	return &courseEntity.CourseSession{
+		CourseID:  courseID,
+		PatternID: patternID,
+		StartAt:   startAt,
+		EndAt:     startAt.Add(2 * time.Hour),
+	}
+}
+
+func sessionIDs(sessions []*courseEntity.CourseSession) []uint {
+	ids := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	return ids
+}
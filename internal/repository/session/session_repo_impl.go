@@ -0,0 +1,135 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ SessionRepository = (*SessionRepositoryImpl)(nil)
+
+// SessionRepositoryImpl 實作 SessionRepository 介面
+//
+// Example:
+//
+//	repo := session.NewSessionRepository(db)
+//	session, err := repo.GetByID(ctx, 1)
+type SessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 建立上課資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: 上課資料庫操作實例
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &SessionRepositoryImpl{db: db}
+}
+
+// Ensure 寫入尚未存在的上課 , 以 ON CONFLICT DO NOTHING 略過已存在的上課
+func (r *SessionRepositoryImpl) Ensure(ctx context.Context, sessions ...*courseEntity.CourseSession) (int64, error) {
+	defer metrics.ObserveRepoQuery("session", "Ensure", time.Now())
+
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	result := repo.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&sessions)
+	return result.RowsAffected, result.Error
+}
+
+// GetByID 依上課ID查詢上課
+func (r *SessionRepositoryImpl) GetByID(ctx context.Context, id uint) (*courseEntity.CourseSession, error) {
+	defer metrics.ObserveRepoQuery("session", "GetByID", time.Now())
+
+	var session courseEntity.CourseSession
+	if err := repo.Conn(ctx, r.db).First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Find 查詢上課
+func (r *SessionRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.CourseSession, error) {
+	defer metrics.ObserveRepoQuery("session", "Find", time.Now())
+
+	var sessions []*courseEntity.CourseSession
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&sessions).
+		Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ListDueForReminder 查詢待提醒的上課 , 使用 FOR UPDATE SKIP LOCKED
+func (r *SessionRepositoryImpl) ListDueForReminder(ctx context.Context, courseID uint, from, to time.Time) ([]*courseEntity.CourseSession, error) {
+	defer metrics.ObserveRepoQuery("session", "ListDueForReminder", time.Now())
+
+	var sessions []*courseEntity.CourseSession
+	if err := repo.Conn(ctx, r.db).
+		Scopes(courseEntity.SessionDueForReminder(courseID, from, to)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("start_at, id").
+		Find(&sessions).
+		Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// MarkReminded 記錄上課已發送提醒
+func (r *SessionRepositoryImpl) MarkReminded(ctx context.Context, id uint, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("session", "MarkReminded", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.CourseSession{}).
+		Where("id = ? AND reminded_at IS NULL", id).
+		Update("reminded_at", at.UTC())
+	return result.RowsAffected, result.Error
+}
+
+// Cancel 取消上課
+func (r *SessionRepositoryImpl) Cancel(ctx context.Context, id uint, reason string, at time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("session", "Cancel", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.CourseSession{}).
+		Where("id = ? AND status = ?", id, courseEntity.SessionStatusScheduled).
+		Updates(map[string]interface{}{
+			"status":        courseEntity.SessionStatusCancelled,
+			"cancelled_at":  at.UTC(),
+			"cancel_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// SetMeeting 記錄上課的線上會議
+func (r *SessionRepositoryImpl) SetMeeting(ctx context.Context, session *courseEntity.CourseSession) (int64, error) {
+	defer metrics.ObserveRepoQuery("session", "SetMeeting", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&courseEntity.CourseSession{}).
+		Where("id = ? AND status = ? AND meeting_id = ''", session.ID, courseEntity.SessionStatusScheduled).
+		Updates(map[string]interface{}{
+			"meeting_provider": session.MeetingProvider,
+			"meeting_id":       session.MeetingID,
+			"join_url":         session.JoinURL,
+			"host_key":         session.HostKey,
+		})
+	return result.RowsAffected, result.Error
+}
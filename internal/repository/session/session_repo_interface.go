@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// SessionRepository 定義課程上課的資料存取介面
+// 上課由 courseEntity.DeriveSessions 展開後以 Ensure 寫入 , 寫入後才有ID可供取消與提醒
+//
+// Example:
+//
+//	var repo SessionRepository
+//	count, err := repo.Ensure(ctx, courseEntity.DeriveSessions(course, patterns, from, to)...)
+type SessionRepository interface {
+	// Ensure 寫入尚未存在的上課 , 已有相同上課時段與開始時間的上課時略過 , 不會更新既有的上課
+	// 參數: ctx - context, sessions - 上課
+	// 回傳: 實際新增的數量, 錯誤訊息
+	Ensure(ctx context.Context, sessions ...*courseEntity.CourseSession) (int64, error)
+
+	// GetByID 依上課ID查詢上課
+	// 參數: ctx - context, id - 上課ID
+	// 回傳: 上課, 錯誤訊息
+	GetByID(ctx context.Context, id uint) (*courseEntity.CourseSession, error)
+
+	// Find 取得上課清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 上課切片, 錯誤訊息
+	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.CourseSession, error)
+
+	// ListDueForReminder 查詢課程於 from 之後 , to 之前 (包含) 開始且尚未提醒的預定上課並鎖定
+	// 其他 transaction 已鎖定的上課會被略過 , 須於 transaction 中呼叫 , 鎖定至 transaction 結束
+	// 參數: ctx - context, courseID - 課程ID, from / to - 開始時間範圍
+	// 回傳: 上課 , 依開始時間排序, 錯誤訊息
+	ListDueForReminder(ctx context.Context, courseID uint, from, to time.Time) ([]*courseEntity.CourseSession, error)
+
+	// MarkReminded 記錄上課已發送提醒 , 只有尚未提醒時才會更新
+	// 參數: ctx - context, id - 上課ID, at - 發送時間
+	// 回傳: 影響數量, 錯誤訊息
+	MarkReminded(ctx context.Context, id uint, at time.Time) (int64, error)
+
+	// Cancel 取消上課 , 只有狀態為預定時才會更新
+	// 參數: ctx - context, id - 上課ID, reason - 取消原因, at - 取消時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示上課不存在或已取消
+	Cancel(ctx context.Context, id uint, reason string, at time.Time) (int64, error)
+
+	// SetMeeting 記錄上課的線上會議 , 只有尚未建立會議的預定上課才會更新
+	// 參數: ctx - context, session - 上課 , 以 ID 更新 MeetingProvider 、 MeetingID 、 JoinURL 與 HostKey
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示上課不存在、已取消或已有會議
+	SetMeeting(ctx context.Context, session *courseEntity.CourseSession) (int64, error)
+}
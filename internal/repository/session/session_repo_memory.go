@@ -0,0 +1,107 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ SessionRepository = (*SessionMemoryRepository)(nil)
+
+// SessionMemoryRepository 以記憶體實作 SessionRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫 , 不支援鎖定
+//
+// Example:
+//
+//	repo := session.NewSessionMemoryRepository()
+//	count, err := repo.Ensure(ctx, sessions...)
+type SessionMemoryRepository struct {
+	table *memory.Table[courseEntity.CourseSession]
+}
+
+// NewSessionMemoryRepository 建立記憶體上課資料操作實例
+// 回傳: 上課資料操作實例
+func NewSessionMemoryRepository() SessionRepository {
+	return &SessionMemoryRepository{table: memory.NewTable[courseEntity.CourseSession]()}
+}
+
+// Ensure 寫入尚未存在的上課 , 違反 unique 限制的上課略過
+func (r *SessionMemoryRepository) Ensure(ctx context.Context, sessions ...*courseEntity.CourseSession) (int64, error) {
+	var count int64
+	for _, session := range sessions {
+		err := r.table.Create(session)
+		switch {
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+		case err != nil:
+			return count, err
+		default:
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetByID 依上課ID查詢上課
+func (r *SessionMemoryRepository) GetByID(ctx context.Context, id uint) (*courseEntity.CourseSession, error) {
+	return r.table.Get(id)
+}
+
+// Find 查詢上課
+func (r *SessionMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*courseEntity.CourseSession, error) {
+	return r.table.Find(pageInfo, conditions)
+}
+
+// ListDueForReminder 查詢待提醒的上課 , 依開始時間排序
+func (r *SessionMemoryRepository) ListDueForReminder(ctx context.Context, courseID uint, from, to time.Time) ([]*courseEntity.CourseSession, error) {
+	return r.table.Find(
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{courseEntity.SessionDueForReminder(courseID, from, to)},
+	)
+}
+
+// MarkReminded 記錄上課已發送提醒
+func (r *SessionMemoryRepository) MarkReminded(ctx context.Context, id uint, at time.Time) (int64, error) {
+	return r.table.Update(id,
+		func(session *courseEntity.CourseSession) bool { return session.RemindedAt == nil },
+		func(session *courseEntity.CourseSession) {
+			at := at.UTC()
+			session.RemindedAt = &at
+		},
+	)
+}
+
+// Cancel 取消上課
+func (r *SessionMemoryRepository) Cancel(ctx context.Context, id uint, reason string, at time.Time) (int64, error) {
+	return r.table.Update(id,
+		func(session *courseEntity.CourseSession) bool {
+			return session.Status == courseEntity.SessionStatusScheduled
+		},
+		func(session *courseEntity.CourseSession) {
+			at := at.UTC()
+			session.Status = courseEntity.SessionStatusCancelled
+			session.CancelledAt = &at
+			session.CancelReason = reason
+		},
+	)
+}
+
+// SetMeeting 記錄上課的線上會議
+func (r *SessionMemoryRepository) SetMeeting(ctx context.Context, session *courseEntity.CourseSession) (int64, error) {
+	return r.table.Update(session.ID,
+		func(s *courseEntity.CourseSession) bool {
+			return s.Status == courseEntity.SessionStatusScheduled && !s.HasMeeting()
+		},
+		func(s *courseEntity.CourseSession) {
+			s.MeetingProvider = session.MeetingProvider
+			s.MeetingID = session.MeetingID
+			s.JoinURL = session.JoinURL
+			s.HostKey = session.HostKey
+		},
+	)
+}
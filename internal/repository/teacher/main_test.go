@@ -4,13 +4,13 @@ import (
 	"os"
 	"testing"
 
-	"github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
 )
 
 // TestMain 初始化 repository 測試環境
-// repo 測試呼叫RepoTestInit 初始化測試環境
+// repo 測試呼叫 testutil.Main , 測試結束後停止 embedded postgres
 func TestMain(m *testing.M) {
-	code := repository.RepoTestInit(m)
+	code := testutil.Main(m)
 
 	os.Exit(code)
 }
@@ -0,0 +1,637 @@
+package teacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// TeacherRepoContractSuite TeacherRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type TeacherRepoContractSuite struct {
+	suite.Suite
+	newRepo     func(t *testing.T) TeacherRepository
+	teacherRepo TeacherRepository
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增測試資料
+func (s *TeacherRepoContractSuite) SetupTest() {
+	s.teacherRepo = s.newRepo(s.T())
+
+	for _, teacher := range []*entity.Teacher{
+		{UserID: 1, Name: "John Doe", Phone: "0911000001", Email: "john@example.com", Status: entity.TeacherStatusPending},       // id 1
+		{UserID: 2, Name: "Jane Doe", Phone: "0911000002", Email: "jane@example.com", Status: entity.TeacherStatusApproved},      // id 2
+		{UserID: 3, Name: "Alice Chen", Phone: "0911000003", Email: "alice@example.com", Status: entity.TeacherStatusApproved},   // id 3
+		{UserID: 4, Name: "Bob Lin", Phone: "0911000004", Email: "bob@example.com", Status: entity.TeacherStatusRejected},        // id 4
+		{UserID: 5, Name: "Deleted Doe", Phone: "0911000005", Email: "deleted@example.com", Status: entity.TeacherStatusPending}, // id 5 , 已刪除
+	} {
+		_, err := s.teacherRepo.Create(context.Background(), teacher)
+		s.Require().NoError(err)
+	}
+
+	rowsAffected, err := s.teacherRepo.Delete(context.Background(), 5)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+}
+
+// TestTeacherRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestTeacherRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &TeacherRepoContractSuite{newRepo: func(t *testing.T) TeacherRepository {
+			return NewTeacherRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &TeacherRepoContractSuite{newRepo: func(t *testing.T) TeacherRepository {
+			return NewTeacherMemoryRepository()
+		}})
+	})
+}
+
+func (s *TeacherRepoContractSuite) TestCreate() {
+	tests := []struct {
+		name       string
+		teacher    *entity.Teacher
+		assertFunc func(t *testing.T, teacherID uint, err error)
+	}{
+		{
+			name:    "exists user_id",
+			teacher: &entity.Teacher{UserID: 1, Name: "Test", Phone: "0922000001", Email: "test1@example.com"},
+			assertFunc: func(t *testing.T, teacherID uint, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, teacherID)
+			},
+		},
+		{
+			name:    "exists phone",
+			teacher: &entity.Teacher{UserID: 100, Name: "Test", Phone: "0911000001", Email: "test2@example.com"},
+			assertFunc: func(t *testing.T, teacherID uint, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, teacherID)
+			},
+		},
+		{
+			name:    "exists email",
+			teacher: &entity.Teacher{UserID: 101, Name: "Test", Phone: "0922000003", Email: "john@example.com"},
+			assertFunc: func(t *testing.T, teacherID uint, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, teacherID)
+			},
+		},
+		{
+			name:    "reuse user_id, phone and email of deleted teacher",
+			teacher: &entity.Teacher{UserID: 5, Name: "Deleted Doe", Phone: "0911000005", Email: "deleted@example.com"},
+			assertFunc: func(t *testing.T, teacherID uint, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, teacherID)
+			},
+		},
+		{
+			name:    "success",
+			teacher: &entity.Teacher{UserID: 102, Name: "Test", Phone: "0922000004", Email: "test4@example.com"},
+			assertFunc: func(t *testing.T, teacherID uint, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, teacherID)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			teacherID, err := s.teacherRepo.Create(context.Background(), test.teacher)
+			test.assertFunc(s.T(), teacherID, err)
+		})
+	}
+}
+
+func (s *TeacherRepoContractSuite) TestGetByID() {
+	tests := []struct {
+		name       string
+		id         uint
+		assertFunc func(t *testing.T, teacher *entity.Teacher, err error)
+	}{
+		{
+			name: "found",
+			id:   2,
+			assertFunc: func(t *testing.T, teacher *entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Jane Doe", teacher.Name)
+				assert.EqualValues(t, 2, teacher.UserID)
+				assert.Equal(t, entity.TeacherStatusApproved, teacher.Status)
+			},
+		},
+		{
+			name: "not found",
+			id:   100,
+			assertFunc: func(t *testing.T, teacher *entity.Teacher, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, teacher)
+			},
+		},
+		{
+			name: "deleted",
+			id:   5,
+			assertFunc: func(t *testing.T, teacher *entity.Teacher, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Nil(t, teacher)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			teacher, err := s.teacherRepo.GetByID(context.Background(), test.id)
+			test.assertFunc(s.T(), teacher, err)
+		})
+	}
+}
+
+func (s *TeacherRepoContractSuite) TestUpdate() {
+	tests := []struct {
+		name       string
+		teacher    *entity.Teacher
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name:    "not found",
+			teacher: &entity.Teacher{Model: gorm.Model{ID: 100}, Name: "Not Found"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name:    "deleted",
+			teacher: &entity.Teacher{Model: gorm.Model{ID: 5}, Name: "Deleted"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name:    "duplicate phone",
+			teacher: &entity.Teacher{Model: gorm.Model{ID: 1}, Phone: "0911000002"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name:    "success",
+			teacher: &entity.Teacher{Model: gorm.Model{ID: 1}, Name: "John Smith", Bio: "updated bio"},
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.teacherRepo.Update(context.Background(), test.teacher)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+
+	// 零值欄位不會被更新
+	teacher, err := s.teacherRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal("John Smith", teacher.Name)
+	s.Equal("updated bio", teacher.Bio)
+	s.Equal("0911000001", teacher.Phone)
+	s.Equal("john@example.com", teacher.Email)
+}
+
+func (s *TeacherRepoContractSuite) TestUpdateStatus() {
+	tests := []struct {
+		name       string
+		id         uint
+		from, to   entity.TeacherStatus
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "status changed",
+			id:   2,
+			from: entity.TeacherStatusPending,
+			to:   entity.TeacherStatusApproved,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "deleted",
+			id:   5,
+			from: entity.TeacherStatusPending,
+			to:   entity.TeacherStatusApproved,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "success",
+			id:   1,
+			from: entity.TeacherStatusPending,
+			to:   entity.TeacherStatusApproved,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			rowsAffected, err := s.teacherRepo.UpdateStatus(context.Background(), test.id, test.from, test.to)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+
+	teacher, err := s.teacherRepo.GetByID(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.TeacherStatusApproved, teacher.Status)
+}
+
+func (s *TeacherRepoContractSuite) TestAddRating() {
+	ctx := context.Background()
+
+	// 教師 2 兩則評分 5 與 4 , 教師 3 一則評分 3 , 教師 1 的評分加入後又移除
+	for _, rating := range []struct {
+		id         uint
+		sum, count int
+	}{
+		{2, 5, 1}, {2, 4, 1}, {3, 3, 1}, {1, 2, 1}, {1, -2, -1},
+	} {
+		rowsAffected, err := s.teacherRepo.AddRating(ctx, rating.id, rating.sum, rating.count)
+		s.Require().NoError(err)
+		s.Require().EqualValues(1, rowsAffected)
+	}
+
+	teacher, err := s.teacherRepo.GetByID(ctx, 2)
+	s.Require().NoError(err)
+	s.EqualValues(9, teacher.RatingSum)
+	s.EqualValues(2, teacher.RatingCount)
+	s.InDelta(4.5, teacher.RatingAverage, 0.0001)
+
+	teacher, err = s.teacherRepo.GetByID(ctx, 1)
+	s.Require().NoError(err)
+	s.Zero(teacher.RatingCount)
+	s.Zero(teacher.RatingAverage)
+
+	// 依平均評分排序
+	teachers, err := s.teacherRepo.Find(ctx, &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "rating_average", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.MinTeacherRating(3)})
+	s.Require().NoError(err)
+	s.Equal([]uint{2, 3}, teacherIDs(teachers))
+
+	// 已刪除與不存在的教師
+	rowsAffected, err := s.teacherRepo.AddRating(ctx, 5, 5, 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+	rowsAffected, err = s.teacherRepo.AddRating(ctx, 100, 5, 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+}
+
+func (s *TeacherRepoContractSuite) TestDelete() {
+	rowsAffected, err := s.teacherRepo.Delete(context.Background(), 1)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	// 重複刪除與不存在的資料不影響任何資料
+	rowsAffected, err = s.teacherRepo.Delete(context.Background(), 1)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.teacherRepo.Delete(context.Background(), 100)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	_, err = s.teacherRepo.GetByID(context.Background(), 1)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *TeacherRepoContractSuite) TestRestore() {
+	tests := []struct {
+		name       string
+		setup      func()
+		id         uint
+		assertFunc func(t *testing.T, rowsAffected int64, err error)
+	}{
+		{
+			name: "not deleted",
+			id:   1,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "not found",
+			id:   100,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "phone taken by another teacher",
+			setup: func() {
+				_, err := s.teacherRepo.Create(context.Background(), &entity.Teacher{
+					UserID: 100, Name: "New Doe", Phone: "0911000005", Email: "new@example.com",
+				})
+				s.Require().NoError(err)
+			},
+			id: 5,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+				assert.Zero(t, rowsAffected)
+			},
+		},
+		{
+			name: "success",
+			setup: func() {
+				rowsAffected, err := s.teacherRepo.Delete(context.Background(), 1)
+				s.Require().NoError(err)
+				s.Require().EqualValues(1, rowsAffected)
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, rowsAffected int64, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, rowsAffected)
+
+				teacher, getErr := s.teacherRepo.GetByID(context.Background(), 1)
+				assert.NoError(t, getErr)
+				assert.Equal(t, "John Doe", teacher.Name)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			if test.setup != nil {
+				test.setup()
+			}
+			rowsAffected, err := s.teacherRepo.Restore(context.Background(), test.id)
+			test.assertFunc(s.T(), rowsAffected, err)
+		})
+	}
+}
+
+func (s *TeacherRepoContractSuite) TestListDeleted() {
+	_, err := s.teacherRepo.Delete(context.Background(), 2)
+	s.Require().NoError(err)
+
+	teachers, err := s.teacherRepo.ListDeleted(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"})
+	s.NoError(err)
+	s.Equal([]uint{5, 2}, teacherIDs(teachers))
+	for _, teacher := range teachers {
+		s.True(teacher.DeletedAt.Valid)
+	}
+
+	teachers, err = s.teacherRepo.ListDeleted(context.Background(), &repo.RepoPageInfo{Page: 2, PageSize: 1, Sort: "id", Order: "desc"})
+	s.NoError(err)
+	s.Equal([]uint{2}, teacherIDs(teachers))
+}
+
+func (s *TeacherRepoContractSuite) TestPurge() {
+	// 刪除時間晚於 olderThan 的資料不會被刪除
+	count, err := s.teacherRepo.Purge(context.Background(), time.Now().Add(-time.Hour))
+	s.NoError(err)
+	s.Zero(count)
+
+	count, err = s.teacherRepo.Purge(context.Background(), time.Now().Add(time.Second))
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	// 永久刪除後無法還原 , 未刪除的資料不受影響
+	rowsAffected, err := s.teacherRepo.Restore(context.Background(), 5)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	teachers, err := s.teacherRepo.ListDeleted(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"})
+	s.NoError(err)
+	s.Empty(teachers)
+
+	teachers, err = s.teacherRepo.Find(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, nil)
+	s.NoError(err)
+	s.Equal([]uint{1, 2, 3, 4}, teacherIDs(teachers))
+}
+
+func (s *TeacherRepoContractSuite) TestFind() {
+	tests := []struct {
+		name       string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, teachers []*entity.Teacher, err error)
+	}{
+		{
+			name:     "excludes deleted",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "desc"},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{4, 3, 2, 1}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "sort by name",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "name", Order: "asc"},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 4, 2, 1}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "pagination",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 3, Sort: "id", Order: "asc"},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{4}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "like name",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				entity.LikeTeacherName("Doe"),
+			},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "in status",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				entity.InTeacherStatus([]entity.TeacherStatus{entity.TeacherStatusApproved, entity.TeacherStatusRejected}),
+			},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 3, 4}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "combined conditions",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				entity.LikeTeacherName("Doe"),
+				entity.InTeacherStatus([]entity.TeacherStatus{entity.TeacherStatusApproved}),
+			},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2}, teacherIDs(teachers))
+			},
+		},
+		{
+			name:     "unscoped includes deleted",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				func(db *gorm.DB) *gorm.DB { return db.Unscoped() },
+				entity.LikeTeacherName("Doe"),
+			},
+			assertFunc: func(t *testing.T, teachers []*entity.Teacher, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1, 2, 5}, teacherIDs(teachers))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			teachers, err := s.teacherRepo.Find(context.Background(), test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), teachers, err)
+		})
+	}
+}
+
+func (s *TeacherRepoContractSuite) TestSearch() {
+	for id, bio := range map[uint]string{
+		2: "Golang backend engineer, teaches with Alice",
+		3: "資料庫設計與 PostgreSQL 效能調校",
+	} {
+		_, err := s.teacherRepo.Update(context.Background(), &entity.Teacher{Model: gorm.Model{ID: id}, Bio: bio})
+		s.Require().NoError(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		pageInfo   *repo.RepoPageInfo
+		conditions []func(db *gorm.DB) *gorm.DB
+		assertFunc func(t *testing.T, hits []*search.Hit[entity.Teacher], err error)
+	}{
+		{
+			name:     "name , excludes deleted",
+			query:    "doe",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2, 1}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "name ranks above bio",
+			query:    "ALICE",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3, 2}, teacherHitIDs(hits))
+				assert.Greater(t, hits[0].Rank, hits[1].Rank)
+			},
+		},
+		{
+			name:     "prefix",
+			query:    "postgres",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "chinese substring",
+			query:    "設計",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{3}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "typo",
+			query:    "Jhon Doe",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, []uint{1, 2}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "pagination",
+			query:    "doe",
+			pageInfo: &repo.RepoPageInfo{Page: 2, PageSize: 1},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{1}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "conditions",
+			query:    "doe",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			conditions: []func(db *gorm.DB) *gorm.DB{
+				entity.InTeacherStatus([]entity.TeacherStatus{entity.TeacherStatusApproved}),
+			},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []uint{2}, teacherHitIDs(hits))
+			},
+		},
+		{
+			name:     "no match",
+			query:    "kubernetes",
+			pageInfo: &repo.RepoPageInfo{Page: 1, PageSize: 10},
+			assertFunc: func(t *testing.T, hits []*search.Hit[entity.Teacher], err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, hits)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			query, err := search.Parse(test.query)
+			s.Require().NoError(err)
+
+			hits, err := s.teacherRepo.Search(context.Background(), query, test.pageInfo, test.conditions)
+			test.assertFunc(s.T(), hits, err)
+		})
+	}
+}
+
+func teacherHitIDs(hits []*search.Hit[entity.Teacher]) []uint {
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Item.ID)
+	}
+	return ids
+}
+
+func teacherIDs(teachers []*entity.Teacher) []uint {
+	ids := make([]uint, 0, len(teachers))
+	for _, teacher := range teachers {
+		ids = append(ids, teacher.ID)
+	}
+	return ids
+}
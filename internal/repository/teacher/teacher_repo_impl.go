@@ -3,15 +3,34 @@ package teacher
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
 	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
 	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
 )
 
 var _ TeacherRepository = (*TeacherRepositoryImpl)(nil)
 
+const (
+	// teacherSearchMatch 搜尋條件 , 參數: @tsquery , @text , @pattern
+	teacherSearchMatch = "(" + entity.TeacherSearchVector + " @@ to_tsquery('simple', @tsquery)" +
+		" OR name ILIKE @pattern OR bio ILIKE @pattern OR name % @text)"
+	// teacherSearchRank 相關度 , 全文檢索分數 + 姓名相似度 , 姓名包含完整關鍵字時加 1
+	teacherSearchRank = "ts_rank(" + entity.TeacherSearchVector + ", to_tsquery('simple', @tsquery))" +
+		" + similarity(name, @text) + CASE WHEN name ILIKE @pattern THEN 1 ELSE 0 END"
+)
+
+// teacherHit 搜尋查詢的結果列 , 教師資料加上相關度
+type teacherHit struct {
+	entity.Teacher
+	SearchRank float64
+}
+
 // TeacherRepositoryImpl 實作 TeacherRepository 介面
 // 負責教師資料的存取操作
 //
@@ -39,7 +58,9 @@ func (t *TeacherRepositoryImpl) WithTransaction(tx *gorm.DB) TeacherRepository {
 // 如果建立失敗，返回錯誤
 // 如果建立成功，返回教師ID
 func (t *TeacherRepositoryImpl) Create(ctx context.Context, teacher *entity.Teacher) (uint, error) {
-	result := t.db.WithContext(ctx).Create(teacher)
+	defer metrics.ObserveRepoQuery("teacher", "Create", time.Now())
+
+	result := repo.Conn(ctx, t.db).Create(teacher)
 	if result.Error != nil {
 		return 0, result.Error
 	}
@@ -52,8 +73,10 @@ func (t *TeacherRepositoryImpl) Create(ctx context.Context, teacher *entity.Teac
 // 如果查詢失敗，返回錯誤
 // 如果查詢成功，返回教師資料
 func (t *TeacherRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.Teacher, error) {
+	defer metrics.ObserveRepoQuery("teacher", "GetByID", time.Now())
+
 	var teacher entity.Teacher
-	if err := t.db.WithContext(ctx).First(&teacher, id).Error; err != nil {
+	if err := repo.Conn(ctx, t.db).First(&teacher, id).Error; err != nil {
 		return nil, err
 	}
 	return &teacher, nil
@@ -64,31 +87,137 @@ func (t *TeacherRepositoryImpl) GetByID(ctx context.Context, id uint) (*entity.T
 // 如果更新失敗，返回錯誤
 // 如果更新成功，返回更新後的教師資料數量
 func (t *TeacherRepositoryImpl) Update(ctx context.Context, teacher *entity.Teacher) (int64, error) {
-	result := t.db.WithContext(ctx).Model(teacher).Updates(teacher)
+	defer metrics.ObserveRepoQuery("teacher", "Update", time.Now())
+
+	result := repo.Conn(ctx, t.db).Model(teacher).Updates(teacher)
+	return result.RowsAffected, result.Error
+}
+
+// UpdateStatus 更新教師狀態
+// 以 id 與目前狀態 from 為條件更新 , 避免併發審核覆蓋彼此的結果
+// 如果更新失敗，返回錯誤
+// 如果更新成功，返回更新的教師資料數量 , 0 表示教師不存在或狀態已被變更
+func (t *TeacherRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to entity.TeacherStatus) (int64, error) {
+	defer metrics.ObserveRepoQuery("teacher", "UpdateStatus", time.Now())
+
+	result := repo.Conn(ctx, t.db).
+		Model(&entity.Teacher{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	return result.RowsAffected, result.Error
+}
+
+// AddRating 調整教師的評分統計
+// 以 SET rating_sum = rating_sum + ? 累加 , 平均評分以同一筆 UPDATE 中的舊值計算
+func (t *TeacherRepositoryImpl) AddRating(ctx context.Context, id uint, sum, count int) (int64, error) {
+	defer metrics.ObserveRepoQuery("teacher", "AddRating", time.Now())
+
+	result := repo.Conn(ctx, t.db).
+		Model(&entity.Teacher{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"rating_sum":     gorm.Expr("rating_sum + ?", sum),
+			"rating_count":   gorm.Expr("rating_count + ?", count),
+			"rating_average": gorm.Expr("CASE WHEN rating_count + ? > 0 THEN (rating_sum + ?)::float8 / (rating_count + ?) ELSE 0 END", count, sum, count),
+		})
 	return result.RowsAffected, result.Error
 }
 
 // Delete 刪除教師資料
-// 使用 gorm.Delete 刪除教師資料
+// 使用 gorm.Delete 軟刪除教師資料
 // 如果刪除失敗，返回錯誤
 // 如果刪除成功，返回刪除的教師資料數量
 func (t *TeacherRepositoryImpl) Delete(ctx context.Context, id uint) (int64, error) {
-	result := t.db.WithContext(ctx).Delete(&entity.Teacher{}, id)
+	defer metrics.ObserveRepoQuery("teacher", "Delete", time.Now())
+
+	result := repo.Conn(ctx, t.db).Delete(&entity.Teacher{}, id)
+	return result.RowsAffected, result.Error
+}
+
+// Restore 還原已刪除的教師資料
+// 以 Unscoped 將 deleted_at 設為 NULL
+// 還原後 user_id / phone / email 與其他教師重複時 , 返回 gorm.ErrDuplicatedKey
+// 如果還原成功，返回還原的教師資料數量 , 0 表示教師不存在或未被刪除
+func (t *TeacherRepositoryImpl) Restore(ctx context.Context, id uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("teacher", "Restore", time.Now())
+
+	result := repo.Conn(ctx, t.db).
+		Unscoped().
+		Model(&entity.Teacher{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
 	return result.RowsAffected, result.Error
 }
 
+// ListDeleted 查詢已刪除的教師資料
+// 使用 gorm.Find 查詢已刪除的教師資料
+// 如果查詢失敗，返回錯誤
+// 如果查詢成功，返回教師資料
+func (t *TeacherRepositoryImpl) ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Teacher, error) {
+	defer metrics.ObserveRepoQuery("teacher", "ListDeleted", time.Now())
+
+	var teachers []*entity.Teacher
+	if err := repo.Conn(ctx, t.db).
+		Scopes(repo.Paginate(pageInfo), repo.OnlyDeleted).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Find(&teachers).
+		Error; err != nil {
+		return nil, err
+	}
+	return teachers, nil
+}
+
+// Purge 永久刪除教師資料
+// 只刪除刪除時間早於 olderThan 的教師資料 , 並於同一個 transaction 刪除教師的授課紀錄
+// 如果刪除失敗，返回錯誤
+// 如果刪除成功，返回刪除的教師資料數量
+func (t *TeacherRepositoryImpl) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("teacher", "Purge", time.Now())
+
+	var count int64
+	err := repo.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Unscoped().
+			Model(&entity.Teacher{}).
+			Where("deleted_at < ?", olderThan).
+			Pluck("id", &ids).
+			Error; err != nil {
+			return fmt.Errorf("failed to list purged teachers: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Where("teacher_id IN ?", ids).Delete(&courseEntity.CourseTeacher{}).Error; err != nil {
+			return fmt.Errorf("failed to purge course teachers: %w", err)
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&entity.Teacher{})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Find 查詢教師資料
 // 使用 gorm.Find 查詢教師資料
 // 如果查詢失敗，返回錯誤
 // 如果查詢成功，返回教師資料
 func (t *TeacherRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Teacher, error) {
+	defer metrics.ObserveRepoQuery("teacher", "Find", time.Now())
+
 	var teachers []*entity.Teacher
 
 	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
 	dbFuncs = append(dbFuncs, conditions...)
 
-	if err := t.db.
-		WithContext(ctx).
+	if err := repo.Conn(ctx, t.db).
 		Scopes(dbFuncs...).
 		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
 		Find(&teachers).
@@ -97,3 +226,30 @@ func (t *TeacherRepositoryImpl) Find(ctx context.Context, pageInfo *repo.RepoPag
 	}
 	return teachers, nil
 }
+
+// Search 依關鍵字搜尋教師
+// 以 pg_trgm 與 tsvector 的 GIN index 比對 , 依相關度排序後分頁
+func (t *TeacherRepositoryImpl) Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[entity.Teacher], error) {
+	defer metrics.ObserveRepoQuery("teacher", "Search", time.Now())
+
+	args := map[string]interface{}{"tsquery": query.TSQuery(), "text": query.Text(), "pattern": query.Pattern()}
+
+	var rows []*teacherHit
+	if err := repo.Conn(ctx, t.db).
+		Model(&entity.Teacher{}).
+		Select("*, ("+teacherSearchRank+") AS search_rank", args).
+		Where(teacherSearchMatch, args).
+		Scopes(conditions...).
+		Scopes(repo.Paginate(pageInfo)).
+		Order("search_rank DESC, id DESC").
+		Find(&rows).
+		Error; err != nil {
+		return nil, fmt.Errorf("failed to search teachers: %w", err)
+	}
+
+	hits := make([]*search.Hit[entity.Teacher], 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, &search.Hit[entity.Teacher]{Item: &row.Teacher, Rank: row.SearchRank})
+	}
+	return hits, nil
+}
@@ -2,19 +2,17 @@ package teacher
 
 import (
 	"context"
-	"os"
-	"path/filepath"
 	"testing"
+	"time"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
 
-	"github.com/itmrchow/course-management-system/internal/config"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
 	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
 	"github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
 )
 
 // TeacherRepoTestSuite 用於 TeacherRepositoryImpl 的測試
@@ -26,31 +24,17 @@ type TeacherRepoTestSuite struct {
 }
 
 // SetupTest 於每個測試案例前執行，做初始化
+// 每個測試案例使用獨立的資料庫 , 並載入 fixture
 func (s *TeacherRepoTestSuite) SetupTest() {
-	// logger
-	logger := zerolog.New(os.Stdout).Level(zerolog.DebugLevel)
-
 	// db
-	db := config.NewMemoryDB(context.Background(), &logger)
-
-	sqlDB, err := db.DB()
-	s.Require().NoError(err)
+	db := testutil.NewDB(s.T())
 
 	// fixture
 	// 載入 fixture
-	dir, _ := os.Getwd()
-	fixtures, err := testfixtures.New(
-		testfixtures.Database(sqlDB), // 傳入 *sql.DB
-		testfixtures.Dialect("postgres"),
-		testfixtures.Directory(filepath.Join(dir, "../../repository/teacher/testdata")),
-		testfixtures.DangerousSkipTestDatabaseCheck(),
-	)
-	s.Require().NoError(err)
-	s.Require().NoError(fixtures.Load())
+	testutil.LoadFixtures(s.T(), db, "testdata")
 
 	s.db = db
 	s.teacherRepo = NewTeacherRepository(db)
-
 }
 
 // TestTeacherRepoSuite 執行測試套件
@@ -469,3 +453,27 @@ func (s *TeacherRepoTestSuite) TestList() {
 	}
 
 }
+
+// 永久刪除教師測試 , 教師的授課紀錄一併刪除
+// Test for TeacherRepositoryImpl.Purge
+func (s *TeacherRepoTestSuite) TestPurge() {
+	ctx := context.Background()
+	s.Require().NoError(s.db.Create([]*courseEntity.CourseTeacher{
+		{CourseID: 1, TeacherID: 1, IsMain: true},
+		{CourseID: 1, TeacherID: 2},
+	}).Error)
+
+	rowsAffected, err := s.teacherRepo.Delete(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+
+	count, err := s.teacherRepo.Purge(ctx, time.Now().Add(time.Second))
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	var remaining int64
+	s.NoError(s.db.Unscoped().Model(&courseEntity.CourseTeacher{}).Where("teacher_id = ?", 1).Count(&remaining).Error)
+	s.Zero(remaining)
+	s.NoError(s.db.Unscoped().Model(&courseEntity.CourseTeacher{}).Where("teacher_id = ?", 2).Count(&remaining).Error)
+	s.EqualValues(1, remaining)
+}
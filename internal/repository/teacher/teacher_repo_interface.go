@@ -2,9 +2,11 @@ package teacher
 
 import (
 	"context"
+	"time"
 
 	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
 	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/search"
 	"gorm.io/gorm"
 )
 
@@ -31,13 +33,44 @@ type TeacherRepository interface {
 	// 回傳: 影響數量, 錯誤訊息
 	Update(ctx context.Context, teacher *entity.Teacher) (int64, error)
 
-	// Delete 刪除教師資料
+	// UpdateStatus 更新教師狀態 , 只有目前狀態為 from 時才會更新
+	// 參數: ctx - context, id - 教師ID, from - 目前狀態, to - 新狀態
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示教師不存在或狀態已被變更
+	UpdateStatus(ctx context.Context, id uint, from, to entity.TeacherStatus) (int64, error)
+
+	// AddRating 調整教師的評分統計並重新計算平均評分 , 以資料庫現有值累加 , 併發呼叫不會覆蓋彼此的結果
+	// 參數: ctx - context, id - 教師ID, sum - 評分總和的增減, count - 評分數量的增減
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示教師不存在
+	AddRating(ctx context.Context, id uint, sum, count int) (int64, error)
+
+	// Delete 軟刪除教師資料 , 可透過 Restore 還原
 	// 參數: ctx - context, id - 教師ID
 	// 回傳: 影響數量, 錯誤訊息
 	Delete(ctx context.Context, id uint) (int64, error)
 
+	// Restore 還原已刪除的教師資料
+	// 參數: ctx - context, id - 教師ID
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示教師不存在或未被刪除
+	Restore(ctx context.Context, id uint) (int64, error)
+
+	// ListDeleted 取得已刪除的教師清單（可加分頁）
+	// 參數: ctx - context, pageInfo - 分頁資訊
+	// 回傳: 教師實體切片, 錯誤訊息
+	ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Teacher, error)
+
+	// Purge 永久刪除刪除時間早於 olderThan 的教師資料 , 並刪除教師的授課紀錄
+	// 參數: ctx - context, olderThan - 刪除時間上限
+	// 回傳: 刪除數量, 錯誤訊息
+	Purge(ctx context.Context, olderThan time.Time) (int64, error)
+
 	// Find 取得教師清單（可加分頁、條件查詢）
 	// 參數: ctx - context
 	// 回傳: 教師實體切片, 錯誤訊息
 	Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Teacher, error)
+
+	// Search 依關鍵字搜尋教師姓名與簡介 , 依相關度由高到低排序 , 相關度相同時依 ID 由新到舊
+	// 符合任一條件即列入結果: 全文檢索前綴比對 , 姓名或簡介包含完整關鍵字 (不分大小寫) , 姓名 trigram 相似 (容許錯字)
+	// 參數: ctx - context, query - 查詢, pageInfo - 分頁資訊 (不使用排序欄位), conditions - 額外查詢條件
+	// 回傳: 搜尋結果, 錯誤訊息
+	Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[entity.Teacher], error)
 }
@@ -0,0 +1,131 @@
+package teacher
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+	"github.com/itmrchow/course-management-system/internal/search"
+)
+
+var _ TeacherRepository = (*TeacherMemoryRepository)(nil)
+
+// TeacherMemoryRepository 以記憶體實作 TeacherRepository 介面
+// 供 service 層測試使用 , 不需要啟動資料庫
+// 與 TeacherRepositoryImpl 相同 , 遵守 user_id / phone / email 的 unique 限制與軟刪除
+//
+// Example:
+//
+//	repo := teacher.NewTeacherMemoryRepository()
+//	id, err := repo.Create(ctx, &entity.Teacher{Name: "John"})
+type TeacherMemoryRepository struct {
+	table *memory.Table[entity.Teacher]
+}
+
+// NewTeacherMemoryRepository 建立記憶體教師資料操作實例
+// 回傳: 教師資料操作實例
+func NewTeacherMemoryRepository() TeacherRepository {
+	return &TeacherMemoryRepository{table: memory.NewTable[entity.Teacher]()}
+}
+
+// Create 建立教師資料
+// 違反 unique 限制時回傳 gorm.ErrDuplicatedKey
+func (t *TeacherMemoryRepository) Create(ctx context.Context, teacher *entity.Teacher) (uint, error) {
+	if err := t.table.Create(teacher); err != nil {
+		return 0, err
+	}
+	return teacher.ID, nil
+}
+
+// GetByID 依教師ID查詢教師資料
+// 不存在或已刪除時回傳 gorm.ErrRecordNotFound
+func (t *TeacherMemoryRepository) GetByID(ctx context.Context, id uint) (*entity.Teacher, error) {
+	return t.table.Get(id)
+}
+
+// Update 更新教師資料 , 零值欄位不會更新
+func (t *TeacherMemoryRepository) Update(ctx context.Context, teacher *entity.Teacher) (int64, error) {
+	return t.table.Updates(teacher)
+}
+
+// UpdateStatus 更新教師狀態 , 只有目前狀態為 from 時才會更新
+func (t *TeacherMemoryRepository) UpdateStatus(ctx context.Context, id uint, from, to entity.TeacherStatus) (int64, error) {
+	return t.table.Update(id,
+		func(teacher *entity.Teacher) bool { return teacher.Status == from },
+		func(teacher *entity.Teacher) { teacher.Status = to },
+	)
+}
+
+// AddRating 調整教師的評分統計
+func (t *TeacherMemoryRepository) AddRating(ctx context.Context, id uint, sum, count int) (int64, error) {
+	return t.table.Update(id, nil, func(teacher *entity.Teacher) {
+		teacher.RatingSum = uint(int(teacher.RatingSum) + sum)
+		teacher.RatingCount = uint(int(teacher.RatingCount) + count)
+		teacher.RatingAverage = 0
+		if teacher.RatingCount > 0 {
+			teacher.RatingAverage = float64(teacher.RatingSum) / float64(teacher.RatingCount)
+		}
+	})
+}
+
+// Delete 軟刪除教師資料
+func (t *TeacherMemoryRepository) Delete(ctx context.Context, id uint) (int64, error) {
+	return t.table.Delete(id)
+}
+
+// Restore 還原已刪除的教師資料
+func (t *TeacherMemoryRepository) Restore(ctx context.Context, id uint) (int64, error) {
+	return t.table.Restore(id)
+}
+
+// ListDeleted 查詢已刪除的教師資料
+func (t *TeacherMemoryRepository) ListDeleted(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Teacher, error) {
+	return t.table.Find(pageInfo, []func(db *gorm.DB) *gorm.DB{repo.OnlyDeleted})
+}
+
+// Purge 永久刪除刪除時間早於 olderThan 的教師資料
+func (t *TeacherMemoryRepository) Purge(ctx context.Context, olderThan time.Time) (int64, error) {
+	return t.table.Purge(olderThan), nil
+}
+
+// Find 查詢教師資料 , 條件與 TeacherRepositoryImpl 相同以 gorm scope 表示
+func (t *TeacherMemoryRepository) Find(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.Teacher, error) {
+	return t.table.Find(pageInfo, conditions)
+}
+
+// Search 依關鍵字搜尋教師
+// 比對條件同 TeacherRepositoryImpl , 相關度以 search.Query.TermRank 近似 ts_rank
+func (t *TeacherMemoryRepository) Search(ctx context.Context, query search.Query, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*search.Hit[entity.Teacher], error) {
+	teachers, err := t.table.Where(conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []*search.Hit[entity.Teacher]
+	for _, teacher := range teachers {
+		similarity := search.Similarity(teacher.Name, query.Text())
+		if !query.MatchTerms(teacher.Name, teacher.Bio) && !query.In(teacher.Name) && !query.In(teacher.Bio) &&
+			similarity < search.SimilarityThreshold {
+			continue
+		}
+
+		rank := query.TermRank(search.Field{Text: teacher.Name, Weight: 1}, search.Field{Text: teacher.Bio, Weight: 0.4}) + similarity
+		if query.In(teacher.Name) {
+			rank++
+		}
+		hits = append(hits, &search.Hit[entity.Teacher]{Item: teacher, Rank: rank})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Item.ID > hits[j].Item.ID
+	})
+	return memory.Paginate(hits, pageInfo), nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor 於同一個 transaction 中執行多個 repository 操作
+// transaction 放在 ctx 中 , repository 透過 Conn 取得 , 因此 fn 內須使用傳入的 ctx
+//
+// Example:
+//
+//	err := transactor.Transaction(ctx, func(ctx context.Context) error {
+//		if _, err := teacherRepo.UpdateStatus(ctx, id, from, to); err != nil {
+//			return err
+//		}
+//		return outboxRepo.Add(ctx, event.TeacherApproved{TeacherID: id})
+//	})
+type Transactor interface {
+	// Transaction 以 transaction 執行 fn , fn 回傳錯誤或 panic 時 rollback
+	// ctx 中已有 transaction 時使用 savepoint
+	// 參數: ctx - context, fn - 於 transaction 中執行的操作
+	// 回傳: fn 的錯誤或 commit 錯誤
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// GormTransactor 以 gorm 實作 Transactor
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+var _ Transactor = (*GormTransactor)(nil)
+
+// NewTransactor 建立 gorm Transactor
+// 參數: db - 資料庫連線
+func NewTransactor(db *gorm.DB) Transactor {
+	return &GormTransactor{db: db}
+}
+
+// Transaction 實作 Transactor
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn 取得 ctx 中的 transaction , 不存在時使用 db , 並套用 ctx
+// gorm repository 以 Conn(ctx, r.db) 取代 r.db.WithContext(ctx) , 即可參與 Transactor 的 transaction
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// NoTransaction 直接執行 fn 的 Transactor , 供記憶體 repository 與測試使用 , 不會 rollback
+var NoTransaction Transactor = noTransactor{}
+
+type noTransactor struct{}
+
+func (noTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
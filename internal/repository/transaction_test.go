@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestConn(t *testing.T) {
+	open := func() *gorm.DB {
+		db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+		require.NoError(t, err)
+		return db
+	}
+	db, tx := open(), open()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	// 沒有 transaction 時使用 db
+	conn := Conn(ctx, db)
+	assert.Same(t, db.Statement.ConnPool, conn.Statement.ConnPool)
+	assert.Equal(t, "value", conn.Statement.Context.Value(ctxKey{}))
+
+	// 有 transaction 時使用 transaction , 並套用呼叫端的 ctx
+	txCtx := context.WithValue(ctx, txKey{}, tx)
+	conn = Conn(txCtx, db)
+	assert.Same(t, tx.Statement.ConnPool, conn.Statement.ConnPool)
+	assert.Equal(t, txCtx, conn.Statement.Context)
+}
+
+func TestNoTransaction(t *testing.T) {
+	errFn := errors.New("fn failed")
+	ctx := context.Background()
+
+	called := false
+	err := NoTransaction.Transaction(ctx, func(got context.Context) error {
+		called = true
+		assert.Equal(t, ctx, got)
+		return errFn
+	})
+	assert.True(t, called)
+	assert.ErrorIs(t, err, errFn)
+}
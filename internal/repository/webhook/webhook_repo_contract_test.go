@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/testutil"
+)
+
+// WebhookRepoContractSuite WebhookRepository 的共用契約測試
+// 同時對 gorm 實作與記憶體實作執行 , 確保兩者行為一致
+type WebhookRepoContractSuite struct {
+	suite.Suite
+	newRepo     func(t *testing.T) WebhookRepository
+	webhookRepo WebhookRepository
+	now         time.Time
+}
+
+// SetupTest 於每個測試案例前執行 , 建立 repository 並新增訂閱與發送紀錄
+func (s *WebhookRepoContractSuite) SetupTest() {
+	s.webhookRepo = s.newRepo(s.T())
+	s.now = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, subscription := range []*entity.WebhookSubscription{
+		{URL: "https://a.example.com/hook", EventTypes: entity.EventTypes{"course.published", "course.full"}, Secret: "secret-a"}, // id 1
+		{URL: "https://b.example.com/hook", EventTypes: entity.EventTypes{entity.AllEvents}, Secret: "secret-b"},                  // id 2
+		{URL: "https://c.example.com/hook", EventTypes: entity.EventTypes{"teacher.approved"}, Secret: "secret-c"},                // id 3
+		{URL: "https://d.example.com/hook", EventTypes: entity.EventTypes{"course.published"}, Secret: "secret-d"},                // id 4 , 已刪除
+	} {
+		_, err := s.webhookRepo.CreateSubscription(context.Background(), subscription)
+		s.Require().NoError(err)
+	}
+	rowsAffected, err := s.webhookRepo.DeleteSubscription(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, rowsAffected)
+
+	count, err := s.webhookRepo.AddDeliveries(context.Background(),
+		newContractDelivery(1, "event-1", s.now),                  // id 1
+		newContractDelivery(2, "event-1", s.now),                  // id 2
+		newContractDelivery(2, "event-2", s.now.Add(time.Minute)), // id 3 , 尚未到發送時間
+	)
+	s.Require().NoError(err)
+	s.Require().EqualValues(3, count)
+}
+
+// TestWebhookRepoContract 對 gorm 與記憶體實作執行契約測試
+func TestWebhookRepoContract(t *testing.T) {
+	t.Run("gorm", func(t *testing.T) {
+		suite.Run(t, &WebhookRepoContractSuite{newRepo: func(t *testing.T) WebhookRepository {
+			return NewWebhookRepository(testutil.NewDB(t))
+		}})
+	})
+	t.Run("memory", func(t *testing.T) {
+		suite.Run(t, &WebhookRepoContractSuite{newRepo: func(t *testing.T) WebhookRepository {
+			return NewWebhookMemoryRepository()
+		}})
+	})
+}
+
+func (s *WebhookRepoContractSuite) TestGetSubscription() {
+	subscription, err := s.webhookRepo.GetSubscription(context.Background(), 1)
+	s.NoError(err)
+	s.Equal("https://a.example.com/hook", subscription.URL)
+	s.Equal(entity.EventTypes{"course.published", "course.full"}, subscription.EventTypes)
+	s.Equal("secret-a", subscription.Secret)
+
+	_, err = s.webhookRepo.GetSubscription(context.Background(), 4)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *WebhookRepoContractSuite) TestFindSubscriptions() {
+	subscriptions, err := s.webhookRepo.FindSubscriptions(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 2, Sort: "id", Order: "desc"}, nil)
+	s.NoError(err)
+	s.Require().Len(subscriptions, 2)
+	s.EqualValues(3, subscriptions[0].ID)
+	s.EqualValues(2, subscriptions[1].ID)
+}
+
+func (s *WebhookRepoContractSuite) TestListSubscriptionsForEvent() {
+	tests := []struct {
+		eventType string
+		want      []uint
+	}{
+		{eventType: "course.published", want: []uint{1, 2}},
+		{eventType: "course.full", want: []uint{1, 2}},
+		{eventType: "teacher.approved", want: []uint{2, 3}},
+		{eventType: "enrollment.enrolled", want: []uint{2}},
+	}
+
+	for _, test := range tests {
+		s.Run(test.eventType, func() {
+			subscriptions, err := s.webhookRepo.ListSubscriptionsForEvent(context.Background(), test.eventType)
+			s.NoError(err)
+
+			ids := make([]uint, 0, len(subscriptions))
+			for _, subscription := range subscriptions {
+				ids = append(ids, subscription.ID)
+			}
+			s.Equal(test.want, ids)
+		})
+	}
+}
+
+func (s *WebhookRepoContractSuite) TestAddDeliveriesIgnoresDuplicates() {
+	count, err := s.webhookRepo.AddDeliveries(context.Background(),
+		newContractDelivery(1, "event-1", s.now),
+		newContractDelivery(1, "event-3", s.now),
+	)
+	s.NoError(err)
+	s.EqualValues(1, count)
+
+	deliveries, err := s.webhookRepo.FindDeliveries(context.Background(),
+		&repo.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.WebhookDeliveryOfSubscription(1)},
+	)
+	s.NoError(err)
+	s.Require().Len(deliveries, 2)
+	s.Equal("event-1", deliveries[0].EventID)
+	s.Equal("event-3", deliveries[1].EventID)
+}
+
+func (s *WebhookRepoContractSuite) TestListPendingDeliveries() {
+	deliveries, err := s.webhookRepo.ListPendingDeliveries(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Require().Len(deliveries, 2)
+	s.EqualValues(1, deliveries[0].ID)
+	s.EqualValues(2, deliveries[1].ID)
+
+	deliveries, err = s.webhookRepo.ListPendingDeliveries(context.Background(), s.now.Add(time.Hour), 1)
+	s.NoError(err)
+	s.Len(deliveries, 1)
+}
+
+func (s *WebhookRepoContractSuite) TestClaimDeliveries() {
+	s.Require().NoError(s.webhookRepo.ClaimDeliveries(context.Background(), []uint{1}, s.now.Add(time.Minute)))
+
+	// 取得後的紀錄在 until 之前不會再取得 , 發送次數不變
+	deliveries, err := s.webhookRepo.ListPendingDeliveries(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.EqualValues(2, deliveries[0].ID)
+
+	deliveries, err = s.webhookRepo.ListPendingDeliveries(context.Background(), s.now.Add(time.Minute), 10)
+	s.NoError(err)
+	s.Require().Len(deliveries, 3)
+	for _, delivery := range deliveries {
+		s.Zero(delivery.Attempts)
+	}
+}
+
+func (s *WebhookRepoContractSuite) TestRecordAttempt() {
+	// 失敗 , 稍後重試
+	s.NoError(s.webhookRepo.RecordAttempt(context.Background(), 1, &entity.WebhookAttempt{
+		Status:        entity.WebhookDeliveryStatusPending,
+		ResponseCode:  503,
+		ResponseBody:  "unavailable",
+		Error:         "webhook responded with status 503",
+		At:            s.now,
+		NextAttemptAt: s.now.Add(time.Minute),
+	}))
+	// 成功
+	s.NoError(s.webhookRepo.RecordAttempt(context.Background(), 2, &entity.WebhookAttempt{
+		Status:       entity.WebhookDeliveryStatusSucceeded,
+		ResponseCode: 204,
+		At:           s.now,
+	}))
+
+	delivery, err := s.webhookRepo.GetDelivery(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.WebhookDeliveryStatusPending, delivery.Status)
+	s.EqualValues(1, delivery.Attempts)
+	s.Equal(503, delivery.ResponseCode)
+	s.Equal("unavailable", delivery.ResponseBody)
+	s.True(delivery.NextAttemptAt.Equal(s.now.Add(time.Minute)))
+
+	delivery, err = s.webhookRepo.GetDelivery(context.Background(), 2)
+	s.NoError(err)
+	s.Equal(entity.WebhookDeliveryStatusSucceeded, delivery.Status)
+	s.Equal(204, delivery.ResponseCode)
+	s.Empty(delivery.LastError)
+	s.Require().NotNil(delivery.DeliveredAt)
+	s.True(delivery.DeliveredAt.Equal(s.now))
+
+	deliveries, err := s.webhookRepo.ListPendingDeliveries(context.Background(), s.now, 10)
+	s.NoError(err)
+	s.Empty(deliveries)
+}
+
+func (s *WebhookRepoContractSuite) TestRedeliver() {
+	// 待發送的紀錄不受影響
+	rowsAffected, err := s.webhookRepo.Redeliver(context.Background(), 1, s.now)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	rowsAffected, err = s.webhookRepo.Redeliver(context.Background(), 100, s.now)
+	s.NoError(err)
+	s.Zero(rowsAffected)
+
+	s.Require().NoError(s.webhookRepo.RecordAttempt(context.Background(), 1, &entity.WebhookAttempt{
+		Status: entity.WebhookDeliveryStatusFailed,
+		Error:  "connection refused",
+		At:     s.now,
+	}))
+
+	rowsAffected, err = s.webhookRepo.Redeliver(context.Background(), 1, s.now.Add(time.Hour))
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	delivery, err := s.webhookRepo.GetDelivery(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(entity.WebhookDeliveryStatusPending, delivery.Status)
+	s.Zero(delivery.Attempts)
+	s.True(delivery.NextAttemptAt.Equal(s.now.Add(time.Hour)))
+}
+
+func newContractDelivery(subscriptionID uint, eventID string, nextAttemptAt time.Time) *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      "course.published",
+		Payload:        `{"course_id":1}`,
+		NextAttemptAt:  nextAttemptAt,
+	}
+}
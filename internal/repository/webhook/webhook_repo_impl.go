@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+var _ WebhookRepository = (*WebhookRepositoryImpl)(nil)
+
+// WebhookRepositoryImpl 實作 WebhookRepository 介面
+//
+// Example:
+//
+//	repo := webhook.NewWebhookRepository(db)
+//	subscription, err := repo.GetSubscription(ctx, 1)
+type WebhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository 建立 webhook 資料庫操作實例
+// 參數: db - 資料庫連線
+// 回傳: webhook 資料庫操作實例
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+// CreateSubscription 新增訂閱
+func (r *WebhookRepositoryImpl) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (uint, error) {
+	defer metrics.ObserveRepoQuery("webhook", "CreateSubscription", time.Now())
+
+	if err := repo.Conn(ctx, r.db).Create(subscription).Error; err != nil {
+		return 0, err
+	}
+	return subscription.ID, nil
+}
+
+// GetSubscription 依訂閱ID查詢訂閱
+func (r *WebhookRepositoryImpl) GetSubscription(ctx context.Context, id uint) (*entity.WebhookSubscription, error) {
+	defer metrics.ObserveRepoQuery("webhook", "GetSubscription", time.Now())
+
+	var subscription entity.WebhookSubscription
+	if err := repo.Conn(ctx, r.db).First(&subscription, id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// DeleteSubscription 軟刪除訂閱
+func (r *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, id uint) (int64, error) {
+	defer metrics.ObserveRepoQuery("webhook", "DeleteSubscription", time.Now())
+
+	result := repo.Conn(ctx, r.db).Delete(&entity.WebhookSubscription{}, id)
+	return result.RowsAffected, result.Error
+}
+
+// FindSubscriptions 查詢訂閱
+func (r *WebhookRepositoryImpl) FindSubscriptions(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookSubscription, error) {
+	defer metrics.ObserveRepoQuery("webhook", "FindSubscriptions", time.Now())
+
+	var subscriptions []*entity.WebhookSubscription
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Order("id").
+		Find(&subscriptions).
+		Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ListSubscriptionsForEvent 查詢訂閱事件類型的所有訂閱
+// 以 jsonb 包含運算子 (@>) 比對 event_types
+func (r *WebhookRepositoryImpl) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*entity.WebhookSubscription, error) {
+	defer metrics.ObserveRepoQuery("webhook", "ListSubscriptionsForEvent", time.Now())
+
+	var subscriptions []*entity.WebhookSubscription
+	if err := repo.Conn(ctx, r.db).
+		Where("event_types @> ?::jsonb OR event_types @> ?::jsonb", entity.EventTypes{eventType}, entity.EventTypes{entity.AllEvents}).
+		Order("id").
+		Find(&subscriptions).
+		Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// AddDeliveries 新增發送紀錄 , 以 ON CONFLICT DO NOTHING 略過重複的事件
+// relay 重新發送同一個事件時不會重複建立紀錄
+func (r *WebhookRepositoryImpl) AddDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) (int64, error) {
+	defer metrics.ObserveRepoQuery("webhook", "AddDeliveries", time.Now())
+
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	result := repo.Conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries)
+	return result.RowsAffected, result.Error
+}
+
+// GetDelivery 依紀錄ID查詢發送紀錄
+func (r *WebhookRepositoryImpl) GetDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	defer metrics.ObserveRepoQuery("webhook", "GetDelivery", time.Now())
+
+	var delivery entity.WebhookDelivery
+	if err := repo.Conn(ctx, r.db).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDeliveries 查詢發送紀錄
+func (r *WebhookRepositoryImpl) FindDeliveries(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookDelivery, error) {
+	defer metrics.ObserveRepoQuery("webhook", "FindDeliveries", time.Now())
+
+	var deliveries []*entity.WebhookDelivery
+
+	dbFuncs := []func(db *gorm.DB) *gorm.DB{repo.Paginate(pageInfo)}
+	dbFuncs = append(dbFuncs, conditions...)
+
+	if err := repo.Conn(ctx, r.db).
+		Scopes(dbFuncs...).
+		Order(fmt.Sprintf("%s %s", pageInfo.Sort, pageInfo.Order)).
+		Order("id").
+		Find(&deliveries).
+		Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ListPendingDeliveries 查詢可發送的紀錄
+// 使用 FOR UPDATE SKIP LOCKED , 其他 transaction 已鎖定的紀錄會被略過
+func (r *WebhookRepositoryImpl) ListPendingDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	defer metrics.ObserveRepoQuery("webhook", "ListPendingDeliveries", time.Now())
+
+	var deliveries []*entity.WebhookDelivery
+	if err := repo.Conn(ctx, r.db).
+		Scopes(entity.WebhookDeliveryPending(now)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Order("id").
+		Limit(limit).
+		Find(&deliveries).
+		Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDeliveries 延後待發送紀錄的下次發送時間
+func (r *WebhookRepositoryImpl) ClaimDeliveries(ctx context.Context, ids []uint, until time.Time) error {
+	defer metrics.ObserveRepoQuery("webhook", "ClaimDeliveries", time.Now())
+
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.Conn(ctx, r.db).
+		Model(&entity.WebhookDelivery{}).
+		Where("id IN ? AND status = ?", ids, entity.WebhookDeliveryStatusPending).
+		Update("next_attempt_at", until).
+		Error
+}
+
+// RecordAttempt 記錄一次發送的結果
+func (r *WebhookRepositoryImpl) RecordAttempt(ctx context.Context, id uint, attempt *entity.WebhookAttempt) error {
+	defer metrics.ObserveRepoQuery("webhook", "RecordAttempt", time.Now())
+
+	values := map[string]interface{}{
+		"status":        attempt.Status,
+		"attempts":      gorm.Expr("attempts + 1"),
+		"response_code": attempt.ResponseCode,
+		"response_body": attempt.ResponseBody,
+		"last_error":    attempt.Error,
+	}
+	switch attempt.Status {
+	case entity.WebhookDeliveryStatusPending:
+		values["next_attempt_at"] = attempt.NextAttemptAt
+	case entity.WebhookDeliveryStatusSucceeded:
+		values["delivered_at"] = attempt.At
+	}
+
+	return repo.Conn(ctx, r.db).
+		Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(values).
+		Error
+}
+
+// Redeliver 將已完成的紀錄重設為待發送
+func (r *WebhookRepositoryImpl) Redeliver(ctx context.Context, id uint, now time.Time) (int64, error) {
+	defer metrics.ObserveRepoQuery("webhook", "Redeliver", time.Now())
+
+	result := repo.Conn(ctx, r.db).
+		Model(&entity.WebhookDelivery{}).
+		Where("id = ? AND status <> ?", id, entity.WebhookDeliveryStatusPending).
+		Updates(map[string]interface{}{
+			"status":          entity.WebhookDeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// WebhookRepository 定義 webhook 訂閱與發送紀錄的資料存取介面
+//
+// Example:
+//
+//	var repo WebhookRepository
+//	subscriptions, err := repo.ListSubscriptionsForEvent(ctx, event.TypeCoursePublished)
+type WebhookRepository interface {
+	// CreateSubscription 新增訂閱
+	// 參數: ctx - context, subscription - 訂閱實體
+	// 回傳: 新增後的訂閱ID, 錯誤訊息
+	CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (uint, error)
+
+	// GetSubscription 依訂閱ID查詢訂閱
+	// 參數: ctx - context, id - 訂閱ID
+	// 回傳: 訂閱實體, 錯誤訊息
+	GetSubscription(ctx context.Context, id uint) (*entity.WebhookSubscription, error)
+
+	// DeleteSubscription 軟刪除訂閱 , 刪除後不再建立發送紀錄 , 待發送的紀錄不會再發送
+	// 參數: ctx - context, id - 訂閱ID
+	// 回傳: 影響數量, 錯誤訊息
+	DeleteSubscription(ctx context.Context, id uint) (int64, error)
+
+	// FindSubscriptions 取得訂閱清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 訂閱實體切片, 錯誤訊息
+	FindSubscriptions(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookSubscription, error)
+
+	// ListSubscriptionsForEvent 查詢訂閱事件類型的所有訂閱 , 包含訂閱所有事件的訂閱
+	// 參數: ctx - context, eventType - 事件類型
+	// 回傳: 訂閱實體切片 , 依 id 排序, 錯誤訊息
+	ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*entity.WebhookSubscription, error)
+
+	// AddDeliveries 新增發送紀錄 , 同一個訂閱已有相同事件的紀錄時略過
+	// 參數: ctx - context, deliveries - 發送紀錄
+	// 回傳: 實際新增的數量, 錯誤訊息
+	AddDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) (int64, error)
+
+	// GetDelivery 依紀錄ID查詢發送紀錄
+	// 參數: ctx - context, id - 紀錄ID
+	// 回傳: 發送紀錄, 錯誤訊息
+	GetDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error)
+
+	// FindDeliveries 取得發送紀錄清單（可加分頁、條件查詢）
+	// 參數: ctx - context, pageInfo - 分頁與排序, conditions - 查詢條件
+	// 回傳: 發送紀錄切片, 錯誤訊息
+	FindDeliveries(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookDelivery, error)
+
+	// ListPendingDeliveries 查詢於 now 可發送的紀錄並鎖定 , 其他 transaction 已鎖定的紀錄會被略過
+	// 須於 transaction 中呼叫 , 鎖定至 transaction 結束
+	// 參數: ctx - context, now - 目前時間, limit - 數量上限
+	// 回傳: 發送紀錄 , 依 id 排序, 錯誤訊息
+	ListPendingDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error)
+
+	// ClaimDeliveries 將待發送紀錄的下次發送時間延後至 until , 不增加發送次數
+	// 於 ListPendingDeliveries 的 transaction 中呼叫 , commit 後在 transaction 外發送 , 發送期間其他 deliverer 不會取得相同紀錄
+	// deliverer 中斷而未記錄結果時 , 紀錄於 until 後重新發送
+	// 參數: ctx - context, ids - 紀錄ID, until - 下次發送時間
+	// 回傳: 錯誤訊息
+	ClaimDeliveries(ctx context.Context, ids []uint, until time.Time) error
+
+	// RecordAttempt 記錄一次發送的結果並增加發送次數
+	// 參數: ctx - context, id - 紀錄ID, attempt - 發送結果
+	// 回傳: 錯誤訊息
+	RecordAttempt(ctx context.Context, id uint, attempt *entity.WebhookAttempt) error
+
+	// Redeliver 將已完成 (成功或失敗) 的紀錄重設為待發送 , 發送次數歸零
+	// 參數: ctx - context, id - 紀錄ID, now - 發送時間
+	// 回傳: 影響數量, 錯誤訊息 , 影響數量為 0 表示紀錄不存在或仍在待發送
+	Redeliver(ctx context.Context, id uint, now time.Time) (int64, error)
+}
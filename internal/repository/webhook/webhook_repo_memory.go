@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	"github.com/itmrchow/course-management-system/internal/repository/memory"
+)
+
+var _ WebhookRepository = (*WebhookMemoryRepository)(nil)
+
+// WebhookMemoryRepository 以記憶體實作 WebhookRepository 介面
+// 供 webhook 發送與 handler 測試使用 , 不需要啟動資料庫 , 不支援鎖定
+//
+// Example:
+//
+//	repo := webhook.NewWebhookMemoryRepository()
+//	id, err := repo.CreateSubscription(ctx, &entity.WebhookSubscription{URL: "https://example.com/hook"})
+type WebhookMemoryRepository struct {
+	subscriptions *memory.Table[entity.WebhookSubscription]
+	deliveries    *memory.Table[entity.WebhookDelivery]
+}
+
+// NewWebhookMemoryRepository 建立記憶體 webhook 資料操作實例
+// 回傳: webhook 資料操作實例
+func NewWebhookMemoryRepository() WebhookRepository {
+	return &WebhookMemoryRepository{
+		subscriptions: memory.NewTable[entity.WebhookSubscription](),
+		deliveries:    memory.NewTable[entity.WebhookDelivery](),
+	}
+}
+
+// CreateSubscription 新增訂閱
+func (r *WebhookMemoryRepository) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) (uint, error) {
+	if err := r.subscriptions.Create(subscription); err != nil {
+		return 0, err
+	}
+	return subscription.ID, nil
+}
+
+// GetSubscription 依訂閱ID查詢訂閱
+func (r *WebhookMemoryRepository) GetSubscription(ctx context.Context, id uint) (*entity.WebhookSubscription, error) {
+	return r.subscriptions.Get(id)
+}
+
+// DeleteSubscription 軟刪除訂閱
+func (r *WebhookMemoryRepository) DeleteSubscription(ctx context.Context, id uint) (int64, error) {
+	return r.subscriptions.Delete(id)
+}
+
+// FindSubscriptions 查詢訂閱
+func (r *WebhookMemoryRepository) FindSubscriptions(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookSubscription, error) {
+	return r.subscriptions.Find(pageInfo, conditions)
+}
+
+// ListSubscriptionsForEvent 查詢訂閱事件類型的所有訂閱 , 以 WebhookSubscription.Subscribes 比對
+func (r *WebhookMemoryRepository) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*entity.WebhookSubscription, error) {
+	subscriptions, err := r.subscriptions.Where(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entity.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(eventType) {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+// AddDeliveries 新增發送紀錄 , 違反 unique 限制的紀錄略過
+func (r *WebhookMemoryRepository) AddDeliveries(ctx context.Context, deliveries ...*entity.WebhookDelivery) (int64, error) {
+	var count int64
+	for _, delivery := range deliveries {
+		err := r.deliveries.Create(delivery)
+		switch {
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+		case err != nil:
+			return count, err
+		default:
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetDelivery 依紀錄ID查詢發送紀錄
+func (r *WebhookMemoryRepository) GetDelivery(ctx context.Context, id uint) (*entity.WebhookDelivery, error) {
+	return r.deliveries.Get(id)
+}
+
+// FindDeliveries 查詢發送紀錄
+func (r *WebhookMemoryRepository) FindDeliveries(ctx context.Context, pageInfo *repo.RepoPageInfo, conditions []func(db *gorm.DB) *gorm.DB) ([]*entity.WebhookDelivery, error) {
+	return r.deliveries.Find(pageInfo, conditions)
+}
+
+// ListPendingDeliveries 查詢可發送的紀錄 , 依 id 排序
+func (r *WebhookMemoryRepository) ListPendingDeliveries(ctx context.Context, now time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	return r.deliveries.Find(
+		&repo.RepoPageInfo{Page: 1, PageSize: limit, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.WebhookDeliveryPending(now)},
+	)
+}
+
+// ClaimDeliveries 延後待發送紀錄的下次發送時間
+func (r *WebhookMemoryRepository) ClaimDeliveries(ctx context.Context, ids []uint, until time.Time) error {
+	for _, id := range ids {
+		if _, err := r.deliveries.Update(id,
+			func(delivery *entity.WebhookDelivery) bool {
+				return delivery.Status == entity.WebhookDeliveryStatusPending
+			},
+			func(delivery *entity.WebhookDelivery) { delivery.NextAttemptAt = until },
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordAttempt 記錄一次發送的結果
+func (r *WebhookMemoryRepository) RecordAttempt(ctx context.Context, id uint, attempt *entity.WebhookAttempt) error {
+	_, err := r.deliveries.Update(id,
+		func(delivery *entity.WebhookDelivery) bool { return true },
+		func(delivery *entity.WebhookDelivery) {
+			delivery.Status = attempt.Status
+			delivery.Attempts++
+			delivery.ResponseCode = attempt.ResponseCode
+			delivery.ResponseBody = attempt.ResponseBody
+			delivery.LastError = attempt.Error
+			switch attempt.Status {
+			case entity.WebhookDeliveryStatusPending:
+				delivery.NextAttemptAt = attempt.NextAttemptAt
+			case entity.WebhookDeliveryStatusSucceeded:
+				at := attempt.At
+				delivery.DeliveredAt = &at
+			}
+		},
+	)
+	return err
+}
+
+// Redeliver 將已完成的紀錄重設為待發送
+func (r *WebhookMemoryRepository) Redeliver(ctx context.Context, id uint, now time.Time) (int64, error) {
+	return r.deliveries.Update(id,
+		func(delivery *entity.WebhookDelivery) bool {
+			return delivery.Status != entity.WebhookDeliveryStatusPending
+		},
+		func(delivery *entity.WebhookDelivery) {
+			delivery.Status = entity.WebhookDeliveryStatusPending
+			delivery.Attempts = 0
+			delivery.NextAttemptAt = now
+		},
+	)
+}
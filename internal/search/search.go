@@ -0,0 +1,246 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxQueryLength 查詢字串長度上限 (字元數)
+	MaxQueryLength = 100
+	// MaxTerms 查詢字串拆出的詞數上限 , 超過的部分忽略
+	MaxTerms = 8
+	// SimilarityThreshold 模糊比對的相似度門檻 , 同 pg_trgm.similarity_threshold 預設值
+	SimilarityThreshold = 0.3
+	// SnippetLength 摘要長度 (字元數)
+	SnippetLength = 80
+)
+
+var (
+	// ErrEmptyQuery 查詢字串沒有可搜尋的文字
+	ErrEmptyQuery = errors.New("empty search query")
+	// ErrQueryTooLong 查詢字串超過 MaxQueryLength
+	ErrQueryTooLong = fmt.Errorf("search query exceeds %d characters", MaxQueryLength)
+)
+
+// Hit 搜尋結果 , Rank 越高越相關
+type Hit[T any] struct {
+	Item *T
+	Rank float64
+}
+
+// Field 搜尋欄位與權重 , 權重對應 tsvector 的 setweight (A: 1.0 , B: 0.4 , C: 0.2 , D: 0.1)
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Query 解析後的查詢字串
+// 以 tsquery 前綴比對拆出的詞 , 並以 ILIKE 比對完整字串
+// 中文沒有空白分詞 , 'simple' parser 會將整段中文視為一個詞 , 因此中文主要依靠 ILIKE 比對
+//
+// Example:
+//
+//	q, err := search.Parse("Go 入門")
+//	q.TSQuery() // 'go':* & '入門':*
+//	q.Pattern() // %go 入門%
+type Query struct {
+	text  string
+	terms []string
+}
+
+// Parse 解析查詢字串
+// 參數: raw - 使用者輸入的查詢字串
+// 回傳: 查詢, 錯誤訊息 , 沒有可搜尋的文字時回傳 ErrEmptyQuery
+func Parse(raw string) (Query, error) {
+	if utf8.RuneCountInString(raw) > MaxQueryLength {
+		return Query{}, ErrQueryTooLong
+	}
+
+	text := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	seen := make(map[string]bool)
+	var terms []string
+	for _, term := range words(text) {
+		if seen[term] || len(terms) == MaxTerms {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return Query{}, ErrEmptyQuery
+	}
+
+	return Query{text: text, terms: terms}, nil
+}
+
+// Text 正規化後的查詢字串 , 小寫且連續空白合併為一個
+func (q Query) Text() string {
+	return q.text
+}
+
+// Terms 查詢字串拆出的詞 , 只包含文字與數字
+func (q Query) Terms() []string {
+	return q.terms
+}
+
+// TSQuery 以 AND 連接各詞的前綴比對 , 供 to_tsquery('simple', ?) 使用
+// 詞只包含文字與數字 , 不會產生 tsquery 語法錯誤
+func (q Query) TSQuery() string {
+	parts := make([]string, 0, len(q.terms))
+	for _, term := range q.terms {
+		parts = append(parts, "'"+term+"':*")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Pattern 包含完整查詢字串的 LIKE pattern , 已跳脫 % _ \
+func (q Query) Pattern() string {
+	return "%" + likeEscaper.Replace(q.text) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// MatchTerms 所有詞是否都是 texts 中某個詞的前綴 , 對應 tsvector @@ tsquery
+func (q Query) MatchTerms(texts ...string) bool {
+	var tokens []string
+	for _, text := range texts {
+		tokens = append(tokens, words(strings.ToLower(text))...)
+	}
+	for _, term := range q.terms {
+		if !hasPrefix(tokens, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// In text 是否包含完整查詢字串 (不分大小寫) , 對應 ILIKE Pattern()
+func (q Query) In(text string) bool {
+	return strings.Contains(strings.ToLower(text), q.text)
+}
+
+// TermRank 依欄位權重計算前綴比對的分數 , 近似 ts_rank 的排序
+// 每個欄位的分數為 權重 * 比對到的詞數比例
+func (q Query) TermRank(fields ...Field) float64 {
+	var rank float64
+	for _, field := range fields {
+		tokens := words(strings.ToLower(field.Text))
+		var matched int
+		for _, term := range q.terms {
+			if hasPrefix(tokens, term) {
+				matched++
+			}
+		}
+		rank += field.Weight * float64(matched) / float64(len(q.terms))
+	}
+	return rank
+}
+
+// Snippet 擷取 text 中第一個比對到的位置附近的文字 , 並以 <mark> 標示比對到的部分
+// 回傳的文字已做 HTML 跳脫 , 沒有比對到時回傳空字串
+// 參數: text - 欄位內容, length - 摘要長度 (字元數)
+func (q Query) Snippet(text string, length int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 找出所有比對到的區間 , 完整字串優先 , 其次為各詞
+	needles := append([]string{q.text}, q.terms...)
+	var spans [][2]int
+	for _, needle := range needles {
+		n := []rune(needle)
+		for i := 0; i+len(n) <= len(lower); i++ {
+			if string(lower[i:i+len(n)]) == needle {
+				spans = append(spans, [2]int{i, i + len(n)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i][0] != spans[j][0] {
+			return spans[i][0] < spans[j][0]
+		}
+		return spans[i][1] > spans[j][1]
+	})
+
+	// 比對位置前保留約四分之一的長度作為前文
+	start := max(0, spans[0][0]-length/4)
+	end := min(len(runes), start+length)
+	start = max(0, end-length)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, span := range spans {
+		if span[0] < pos || span[0] >= end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:span[0]])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[span[0]:min(span[1], end)])))
+		b.WriteString("</mark>")
+		pos = min(span[1], end)
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// Similarity 計算兩個字串的 trigram 相似度 , 演算法同 pg_trgm 的 similarity
+// 回傳: 0 ~ 1 , 1 表示 trigram 完全相同
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	var common int
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+// trigrams 取得字串的 trigram 集合 , 每個詞前面補兩個空白 , 後面補一個空白
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range words(strings.ToLower(s)) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// words 以非文字 , 數字的字元切割字串
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func hasPrefix(tokens []string, prefix string) bool {
+	for _, token := range tokens {
+		if strings.HasPrefix(token, prefix) {
+			return true
+		}
+	}
+	return false
+}
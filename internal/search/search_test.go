@@ -0,0 +1,153 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		assertFunc func(t *testing.T, q Query, err error)
+	}{
+		{
+			name: "normalize",
+			raw:  "  Go   入門  ",
+			assertFunc: func(t *testing.T, q Query, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "go 入門", q.Text())
+				assert.Equal(t, []string{"go", "入門"}, q.Terms())
+				assert.Equal(t, "'go':* & '入門':*", q.TSQuery())
+				assert.Equal(t, "%go 入門%", q.Pattern())
+			},
+		},
+		{
+			name: "strips tsquery operators and dedups terms",
+			raw:  "go & !go | (sql):*",
+			assertFunc: func(t *testing.T, q Query, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"go", "sql"}, q.Terms())
+				assert.Equal(t, "'go':* & 'sql':*", q.TSQuery())
+			},
+		},
+		{
+			name: "escapes like wildcards",
+			raw:  `100% c_d\e`,
+			assertFunc: func(t *testing.T, q Query, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, `%100\% c\_d\\e%`, q.Pattern())
+			},
+		},
+		{
+			name: "limits terms",
+			raw:  "a b c d e f g h i j",
+			assertFunc: func(t *testing.T, q Query, err error) {
+				require.NoError(t, err)
+				assert.Len(t, q.Terms(), MaxTerms)
+			},
+		},
+		{
+			name: "empty",
+			raw:  " %*& ",
+			assertFunc: func(t *testing.T, q Query, err error) {
+				assert.ErrorIs(t, err, ErrEmptyQuery)
+			},
+		},
+		{
+			name: "too long",
+			raw:  strings.Repeat("字", MaxQueryLength+1),
+			assertFunc: func(t *testing.T, q Query, err error) {
+				assert.ErrorIs(t, err, ErrQueryTooLong)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.raw)
+			tt.assertFunc(t, q, err)
+		})
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	q, err := Parse("go data")
+	require.NoError(t, err)
+
+	assert.True(t, q.MatchTerms("Golang", "Database design"))
+	assert.False(t, q.MatchTerms("Golang"))
+	assert.False(t, q.MatchTerms("Django database"), "terms match word prefixes only")
+	assert.True(t, q.In("Learn GO DATA pipelines"))
+	assert.False(t, q.In("go, data"))
+
+	assert.Greater(t,
+		q.TermRank(Field{Text: "Go data", Weight: 1}, Field{Text: "", Weight: 0.4}),
+		q.TermRank(Field{Text: "", Weight: 1}, Field{Text: "Go data", Weight: 0.4}),
+	)
+	assert.InDelta(t, 0.5, q.TermRank(Field{Text: "Golang", Weight: 1}), 1e-9)
+}
+
+func TestQuery_Snippet(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		text   string
+		length int
+		want   string
+	}{
+		{
+			name:   "highlights terms",
+			query:  "go sql",
+			text:   "Learn Go and PostgreSQL",
+			length: SnippetLength,
+			want:   "Learn <mark>Go</mark> and Postgre<mark>SQL</mark>",
+		},
+		{
+			name:   "prefers full text",
+			query:  "go 入門",
+			text:   "Go 入門課程",
+			length: SnippetLength,
+			want:   "<mark>Go 入門</mark>課程",
+		},
+		{
+			name:   "truncates around match",
+			query:  "資料庫",
+			text:   "本課程從零開始介紹關聯式資料庫的設計與查詢最佳化",
+			length: 8,
+			want:   "…聯式<mark>資料庫</mark>的設計…",
+		},
+		{
+			name:   "escapes html",
+			query:  "tag",
+			text:   "<b>tag</b>",
+			length: SnippetLength,
+			want:   "&lt;b&gt;<mark>tag</mark>&lt;/b&gt;",
+		},
+		{
+			name:   "no match",
+			query:  "rust",
+			text:   "Learn Go",
+			length: SnippetLength,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q.Snippet(tt.text, tt.length))
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, Similarity("John Doe", "john doe"), 1e-9)
+	assert.InDelta(t, 5.0/13, Similarity("John Doe", "Jhon Doe"), 1e-9)
+	assert.Less(t, Similarity("John Doe", "Jhon"), SimilarityThreshold)
+	assert.Zero(t, Similarity("John", ""))
+}
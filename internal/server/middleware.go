@@ -0,0 +1,202 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+// HeaderRequestID request ID 使用的 header
+const HeaderRequestID = "X-Request-ID"
+
+// HeaderUserID 操作者 user ID 使用的 header , 由前端的 API gateway 驗證身分後設定
+const HeaderUserID = "X-User-ID"
+
+// Middleware http middleware
+type Middleware func(next http.Handler) http.Handler
+
+// chain 依序套用 middleware , 第一個 middleware 在最外層
+func chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// RequestIDMiddleware 取得或產生 request ID
+// 將 logger 與 request ID 放入 request ctx , 並回寫至 response header
+func RequestIDMiddleware(logger *zerolog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(HeaderRequestID)
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			w.Header().Set(HeaderRequestID, requestID)
+
+			ctx := logctx.WithLogger(r.Context(), logger)
+			ctx = logctx.WithRequestID(ctx, requestID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// UserIDMiddleware 從 header 取得操作者 user ID 放入 request ctx , 供稽核紀錄與 log 使用
+// 未帶 header 時視為匿名請求 , 格式錯誤時回傳 400 , 須放在 RequestIDMiddleware 之後
+func UserIDMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := r.Header.Get(HeaderUserID)
+			if value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := strconv.ParseUint(value, 10, 0)
+			if err != nil || userID == 0 {
+				http.Error(w, "invalid "+HeaderUserID+" header", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(logctx.WithUserID(r.Context(), uint(userID))))
+		})
+	}
+}
+
+// TracingMiddleware 為每個請求建立 server span
+// 從 header 還原上游的 trace context , span 名稱使用 ServeMux 比對到的 pattern
+func TracingMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method+" "+r.URL.Path,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			// ServeMux 會將比對到的 pattern 設定在傳入的 request 上
+			req := r.WithContext(ctx)
+			rw := newResponseWriter(w)
+			next.ServeHTTP(rw, req)
+
+			if req.Pattern != "" {
+				span.SetName(req.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(req.Pattern))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(rw.status))
+			if rw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rw.status))
+			}
+		})
+	}
+}
+
+// MetricsMiddleware 記錄 HTTP 請求耗時與狀態碼
+// route label 使用 ServeMux 比對到的 pattern , 避免 path 參數造成 label 過多
+func MetricsMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			begin := time.Now()
+			rw := newResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.HTTPRequestDuration.
+				WithLabelValues(r.Method, route, strconv.Itoa(rw.status)).
+				Observe(time.Since(begin).Seconds())
+		})
+	}
+}
+
+// AccessLogMiddleware 記錄每一筆請求
+func AccessLogMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			begin := time.Now()
+			rw := newResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			zerolog.Ctx(r.Context()).Info().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Int("status", rw.status).
+				Dur("elapsed", time.Since(begin)).
+				Msg("http request")
+		})
+	}
+}
+
+// RecoverMiddleware 攔截 panic , 記錄 log 並回傳 500
+func RecoverMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					if rec == http.ErrAbortHandler {
+						panic(rec)
+					}
+					zerolog.Ctx(r.Context()).Error().
+						Interface("panic", rec).
+						Str("path", r.URL.Path).
+						Msg("http handler panic")
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseWriter 記錄 response status code
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap 讓 http.ResponseController 可以取得原本的 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// newRequestID 產生 16 bytes 隨機 hex 字串作為 request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
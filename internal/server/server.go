@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/itmrchow/course-management-system/internal/metrics"
+)
+
+// Server HTTP server
+// 所有 route 都會套用 request ID / logger / user ID / tracing / metrics / recover middleware
+//
+// Example:
+//
+//	srv := server.NewServer(":8080", logger)
+//	if err := srv.Start(ctx); err != nil {
+//		return err
+//	}
+//	defer srv.Shutdown(ctx)
+type Server struct {
+	httpServer *http.Server
+	mux        *http.ServeMux
+	logger     *zerolog.Logger
+	listener   net.Listener
+}
+
+// NewServer 建立 HTTP server , 預設註冊 /metrics
+// 參數: addr - 監聽位址 , 例如 ":8080" , logger - 預設 logger
+func NewServer(addr string, logger *zerolog.Logger) *Server {
+	s := &Server{
+		mux:    http.NewServeMux(),
+		logger: logger,
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.Handle("GET /metrics", metrics.Handler())
+
+	return s
+}
+
+// Handle 註冊 route , pattern 格式同 http.ServeMux , 例如 "GET /metrics"
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Handler 回傳套用 middleware 後的 http.Handler
+func (s *Server) Handler() http.Handler {
+	return chain(s.mux,
+		RequestIDMiddleware(s.logger),
+		UserIDMiddleware(),
+		TracingMiddleware(),
+		MetricsMiddleware(),
+		AccessLogMiddleware(),
+		RecoverMiddleware(),
+	)
+}
+
+// Start 開始監聽並於背景處理請求
+// 監聽失敗時直接回傳錯誤 , 處理請求過程的錯誤記錄於 log
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Err(err).Ctx(ctx).Msg("http server stopped unexpectedly")
+		}
+	}()
+
+	s.logger.Info().Ctx(ctx).Str("addr", listener.Addr().String()).Msg("http server started")
+	return nil
+}
+
+// Addr 回傳實際監聽位址 , 於 Start 之後呼叫
+// 監聽 port 0 時可用於取得系統分配的 port
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return s.httpServer.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown 停止接受新請求 , 等待處理中的請求完成或 ctx 逾時
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown http server: %w", err)
+	}
+
+	s.logger.Info().Ctx(ctx).Msg("http server stopped")
+	return nil
+}
@@ -0,0 +1,245 @@
+package attendance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	attendanceRepo "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var _ AttendanceService = (*AttendanceServiceImpl)(nil)
+
+var (
+	// ErrNotCourseTeacher 操作者不是課程的授課教師
+	ErrNotCourseTeacher = errors.New("not a teacher of the course")
+	// ErrNotEnrolled 學生未報名課程
+	ErrNotEnrolled = errors.New("student not enrolled")
+	// ErrSessionCancelled 上課已取消
+	ErrSessionCancelled = errors.New("session cancelled")
+	// ErrSessionNotStarted 上課尚未開始
+	ErrSessionNotStarted = errors.New("session not started")
+	// ErrAttendanceLocked 上課的出席紀錄已鎖定
+	ErrAttendanceLocked = errors.New("attendance locked")
+)
+
+// Config 出席紀錄設定
+type Config struct {
+	LockAfter time.Duration // 上課結束後可修改點名的期間 , 之後鎖定
+}
+
+// ConfigFromViper 從 viper 讀取出席紀錄設定
+func ConfigFromViper() Config {
+	viper.SetDefault("ATTENDANCE_LOCK_AFTER", 72*time.Hour)
+
+	return Config{
+		LockAfter: viper.GetDuration("ATTENDANCE_LOCK_AFTER"),
+	}
+}
+
+// AttendanceServiceImpl 實作 AttendanceService 介面
+//
+// Example:
+//
+//	svc := attendance.NewAttendanceService(attendanceRepo.NewAttendanceRepository(db), sessionRepo.NewSessionRepository(db), courseRepo.NewCourseRepository(db), enrollmentRepo.NewEnrollmentRepository(db), teacherRepo.NewTeacherRepository(db), attendance.ConfigFromViper())
+//	rate, err := svc.StudentRate(ctx, 1, 10)
+type AttendanceServiceImpl struct {
+	attendanceRepo attendanceRepo.AttendanceRepository
+	sessionRepo    sessionRepo.SessionRepository
+	courseRepo     courseRepo.CourseRepository
+	enrollmentRepo enrollmentRepo.EnrollmentRepository
+	teacherRepo    teacherRepo.TeacherRepository
+	cfg            Config
+	now            func() time.Time
+}
+
+// NewAttendanceService 建立出席紀錄業務邏輯實例
+// 參數: attendanceRepo - 出席紀錄資料庫操作實例, sessionRepo - 上課資料庫操作實例, courseRepo - 課程資料庫操作實例, enrollmentRepo - 報名資料庫操作實例, teacherRepo - 教師資料庫操作實例 , 以操作者的 user ID 查詢教師, cfg - 設定
+// 回傳: 出席紀錄業務邏輯實例
+func NewAttendanceService(attendanceRepo attendanceRepo.AttendanceRepository, sessionRepo sessionRepo.SessionRepository, courseRepo courseRepo.CourseRepository, enrollmentRepo enrollmentRepo.EnrollmentRepository, teacherRepo teacherRepo.TeacherRepository, cfg Config) AttendanceService {
+	return &AttendanceServiceImpl{
+		attendanceRepo: attendanceRepo,
+		sessionRepo:    sessionRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		teacherRepo:    teacherRepo,
+		cfg:            cfg,
+		now:            time.Now,
+	}
+}
+
+// ListBySession 查詢上課的點名結果
+func (s *AttendanceServiceImpl) ListBySession(ctx context.Context, sessionID uint) (*SessionAttendance, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %d: %w", sessionID, err)
+	}
+	return s.result(ctx, session)
+}
+
+// Mark 批次點名
+// 鎖定以上課結束時間計算 , 不需要鎖定資料列 , 同一位學生併發點名時以後寫入的為準
+func (s *AttendanceServiceImpl) Mark(ctx context.Context, sessionID, userID uint, marks []Mark) (*SessionAttendance, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %d: %w", sessionID, err)
+	}
+	now := s.now()
+	switch {
+	case session.Status == courseEntity.SessionStatusCancelled:
+		return nil, fmt.Errorf("session %d: %w", sessionID, ErrSessionCancelled)
+	case now.Before(session.StartAt):
+		return nil, fmt.Errorf("session %d starts at %s: %w", sessionID, session.StartAt.Format(time.RFC3339), ErrSessionNotStarted)
+	case !now.Before(s.locksAt(session)):
+		return nil, fmt.Errorf("session %d: %w", sessionID, ErrAttendanceLocked)
+	}
+
+	teacherID, err := s.courseTeacherOfUser(ctx, session.CourseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			enrollmentEntity.EnrollmentOfCourse(session.CourseID),
+			enrollmentEntity.InEnrollmentStatus([]enrollmentEntity.EnrollmentStatus{enrollmentEntity.EnrollmentStatusEnrolled}),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find enrollments of course %d: %w", session.CourseID, err)
+	}
+	enrollmentIDs := make(map[uint]uint, len(enrollments))
+	for _, enrollment := range enrollments {
+		enrollmentIDs[enrollment.StudentID] = enrollment.ID
+	}
+
+	attendances := make([]*entity.Attendance, 0, len(marks))
+	for _, mark := range marks {
+		enrollmentID, ok := enrollmentIDs[mark.StudentID]
+		if !ok {
+			return nil, fmt.Errorf("student %d of course %d: %w", mark.StudentID, session.CourseID, ErrNotEnrolled)
+		}
+		attendances = append(attendances, &entity.Attendance{
+			SessionID:    session.ID,
+			StudentID:    mark.StudentID,
+			CourseID:     session.CourseID,
+			EnrollmentID: enrollmentID,
+			Status:       mark.Status,
+			Note:         mark.Note,
+			MarkedBy:     teacherID,
+			MarkedAt:     now,
+		})
+	}
+	if err := s.attendanceRepo.Upsert(ctx, attendances); err != nil {
+		return nil, fmt.Errorf("failed to save attendance of session %d: %w", sessionID, err)
+	}
+	return s.result(ctx, session)
+}
+
+// StudentRate 查詢學生於課程的出席率
+func (s *AttendanceServiceImpl) StudentRate(ctx context.Context, courseID, studentID uint) (*entity.AttendanceRate, error) {
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	counts, err := s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{
+		entity.AttendanceOfCourse(courseID),
+		entity.AttendanceOfStudent(studentID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance of student %d in course %d: %w", studentID, courseID, err)
+	}
+
+	rate := &entity.AttendanceRate{StudentID: studentID}
+	for _, c := range counts {
+		rate.Add(c.Status, c.Count)
+	}
+	return rate, nil
+}
+
+// CourseRate 查詢課程整體與各學生的出席率
+func (s *AttendanceServiceImpl) CourseRate(ctx context.Context, courseID uint) (*CourseAttendanceRate, error) {
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	counts, err := s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{entity.AttendanceOfCourse(courseID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance of course %d: %w", courseID, err)
+	}
+
+	// counts 依學生ID排序 , 同一位學生的統計相鄰
+	result := &CourseAttendanceRate{CourseID: courseID}
+	for _, c := range counts {
+		result.Overall.Add(c.Status, c.Count)
+		if n := len(result.Students); n == 0 || result.Students[n-1].StudentID != c.StudentID {
+			result.Students = append(result.Students, entity.AttendanceRate{StudentID: c.StudentID})
+		}
+		result.Students[len(result.Students)-1].Add(c.Status, c.Count)
+	}
+	return result, nil
+}
+
+// result 查詢上課的出席紀錄與鎖定狀態
+func (s *AttendanceServiceImpl) result(ctx context.Context, session *courseEntity.CourseSession) (*SessionAttendance, error) {
+	attendances, err := s.attendanceRepo.ListBySession(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attendance of session %d: %w", session.ID, err)
+	}
+	locksAt := s.locksAt(session)
+	return &SessionAttendance{
+		Session:     session,
+		Attendances: attendances,
+		LocksAt:     locksAt,
+		Locked:      !s.now().Before(locksAt),
+	}, nil
+}
+
+// locksAt 上課出席紀錄的鎖定時間
+func (s *AttendanceServiceImpl) locksAt(session *courseEntity.CourseSession) time.Time {
+	return session.EndAt.Add(s.cfg.LockAfter)
+}
+
+// courseTeacherOfUser 查詢 user 對應的教師並確認為課程的授課教師
+// 回傳: 教師ID, 錯誤訊息 , user 不是教師或不是課程的授課教師時回傳 ErrNotCourseTeacher
+func (s *AttendanceServiceImpl) courseTeacherOfUser(ctx context.Context, courseID, userID uint) (uint, error) {
+	found, err := s.teacherRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: 1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{teacherEntity.TeacherOfUser(userID)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find teacher of user %d: %w", userID, err)
+	}
+	if len(found) == 0 {
+		return 0, fmt.Errorf("user %d of course %d: %w", userID, courseID, ErrNotCourseTeacher)
+	}
+
+	teachers, err := s.courseRepo.ListTeachers(ctx, courseID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list teachers of course %d: %w", courseID, err)
+	}
+	if !isTeacher(teachers, found[0].ID) {
+		return 0, fmt.Errorf("teacher %d of course %d: %w", found[0].ID, courseID, ErrNotCourseTeacher)
+	}
+	return found[0].ID, nil
+}
+
+// isTeacher 教師是否為課程的授課教師
+func isTeacher(teachers []*courseEntity.CourseTeacher, teacherID uint) bool {
+	for _, teacher := range teachers {
+		if teacher.TeacherID == teacherID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package attendance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	attendanceRepo "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+// testStart 上課 1 的開始時間
+var testStart = time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)
+
+// newTestService 建立使用記憶體 repository 的出席紀錄業務邏輯 , 現在時間為上課 1 開始後 30 分鐘
+// 課程 1 由教師 1 (主教師 , user 100) 與教師 2 (user 101) 授課 , 教師 3 (user 102) 不是授課教師 , 學生 10、11 已報名 , 學生 12 已退出
+// 上課 1 為 testStart 起 2 小時 , 上課 2 為一週後 , 上課 3 已取消 , 上課結束 72 小時後鎖定
+func newTestService(t *testing.T) *AttendanceServiceImpl {
+	t.Helper()
+	ctx := context.Background()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusOnline})
+	require.NoError(t, err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	require.NoError(t, err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 2})
+	require.NoError(t, err)
+
+	teachers := teacherRepo.NewTeacherMemoryRepository()
+	for _, userID := range []uint{100, 101, 102} {
+		_, err := teachers.Create(ctx, &teacherEntity.Teacher{UserID: userID, Name: fmt.Sprintf("teacher %d", userID),
+			Phone: fmt.Sprintf("09%08d", userID), Email: fmt.Sprintf("teacher%d@example.com", userID), Status: teacherEntity.TeacherStatusApproved})
+		require.NoError(t, err)
+	}
+
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	for _, e := range []*enrollmentEntity.Enrollment{
+		{CourseID: 1, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 11, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 12, Status: enrollmentEntity.EnrollmentStatusWithdrawn},
+	} {
+		_, err := enrollments.Create(ctx, e)
+		require.NoError(t, err)
+	}
+
+	sessions := sessionRepo.NewSessionMemoryRepository()
+	_, err = sessions.Ensure(ctx,
+		&courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: testStart, EndAt: testStart.Add(2 * time.Hour)},
+		&courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: testStart.AddDate(0, 0, 7), EndAt: testStart.AddDate(0, 0, 7).Add(2 * time.Hour)},
+		&courseEntity.CourseSession{CourseID: 1, PatternID: 2, StartAt: testStart, EndAt: testStart.Add(2 * time.Hour)},
+	)
+	require.NoError(t, err)
+	_, err = sessions.Cancel(ctx, 3, "颱風停課", testStart)
+	require.NoError(t, err)
+
+	svc := NewAttendanceService(attendanceRepo.NewAttendanceMemoryRepository(), sessions, courses, enrollments, teachers, Config{LockAfter: 72 * time.Hour}).(*AttendanceServiceImpl)
+	svc.now = func() time.Time { return testStart.Add(30 * time.Minute) }
+	return svc
+}
+
+// 批次點名測試
+// Test for AttendanceServiceImpl.Mark
+func TestMark(t *testing.T) {
+	type args struct {
+		sessionID uint
+		userID    uint
+		marks     []Mark
+		now       time.Time
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, result *SessionAttendance, err error)
+	}{
+		{
+			name: "主教師點名",
+			args: args{sessionID: 1, userID: 100, marks: []Mark{
+				{StudentID: 11, Status: entity.AttendanceStatusLate, Note: "塞車"},
+				{StudentID: 10, Status: entity.AttendanceStatusPresent},
+			}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				require.NoError(t, err)
+				require.Len(t, result.Attendances, 2)
+				assert.EqualValues(t, 10, result.Attendances[0].StudentID)
+				assert.EqualValues(t, 1, result.Attendances[0].EnrollmentID)
+				assert.Equal(t, entity.AttendanceStatusLate, result.Attendances[1].Status)
+				assert.Equal(t, "塞車", result.Attendances[1].Note)
+				assert.EqualValues(t, 1, result.Attendances[1].MarkedBy)
+				assert.False(t, result.Locked)
+				assert.True(t, result.LocksAt.Equal(testStart.Add(74*time.Hour)))
+			},
+		},
+		{
+			name: "其他授課教師於鎖定前修改",
+			args: args{sessionID: 1, userID: 101, marks: []Mark{{StudentID: 10, Status: entity.AttendanceStatusExcused}}, now: testStart.Add(73 * time.Hour)},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				require.NoError(t, err)
+				require.Len(t, result.Attendances, 1)
+				assert.EqualValues(t, 2, result.Attendances[0].MarkedBy)
+			},
+		},
+		{
+			name: "非授課教師",
+			args: args{sessionID: 1, userID: 102, marks: []Mark{{StudentID: 10}}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrNotCourseTeacher)
+			},
+		},
+		{
+			name: "不是教師",
+			args: args{sessionID: 1, userID: 10, marks: []Mark{{StudentID: 10}}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrNotCourseTeacher)
+			},
+		},
+		{
+			name: "已退出的學生",
+			args: args{sessionID: 1, userID: 100, marks: []Mark{{StudentID: 10}, {StudentID: 12}}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrNotEnrolled)
+			},
+		},
+		{
+			name: "上課尚未開始",
+			args: args{sessionID: 2, userID: 100, marks: []Mark{{StudentID: 10}}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrSessionNotStarted)
+			},
+		},
+		{
+			name: "上課已取消",
+			args: args{sessionID: 3, userID: 100, marks: []Mark{{StudentID: 10}}},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrSessionCancelled)
+			},
+		},
+		{
+			name: "已鎖定",
+			args: args{sessionID: 1, userID: 100, marks: []Mark{{StudentID: 10}}, now: testStart.Add(74 * time.Hour)},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, ErrAttendanceLocked)
+			},
+		},
+		{
+			name: "上課不存在",
+			args: args{sessionID: 100, userID: 100},
+			assertFunc: func(t *testing.T, result *SessionAttendance, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			if !tt.args.now.IsZero() {
+				svc.now = func() time.Time { return tt.args.now }
+			}
+
+			result, err := svc.Mark(context.Background(), tt.args.sessionID, tt.args.userID, tt.args.marks)
+			tt.assertFunc(t, result, err)
+		})
+	}
+}
+
+func TestListBySession(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	_, err := svc.Mark(ctx, 1, 100, []Mark{{StudentID: 10, Status: entity.AttendanceStatusPresent}})
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return testStart.AddDate(0, 1, 0) }
+	result, err := svc.ListBySession(ctx, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, result.Session.ID)
+	assert.Len(t, result.Attendances, 1)
+	assert.True(t, result.Locked)
+
+	_, err = svc.ListBySession(ctx, 100)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRate(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	// 上課 1 : 學生 10 出席 , 學生 11 缺席 ; 上課 2 : 學生 10 遲到 , 學生 11 請假
+	_, err := svc.Mark(ctx, 1, 100, []Mark{{StudentID: 10, Status: entity.AttendanceStatusPresent}, {StudentID: 11, Status: entity.AttendanceStatusAbsent}})
+	require.NoError(t, err)
+	svc.now = func() time.Time { return testStart.AddDate(0, 0, 7).Add(time.Hour) }
+	_, err = svc.Mark(ctx, 2, 100, []Mark{{StudentID: 10, Status: entity.AttendanceStatusLate}, {StudentID: 11, Status: entity.AttendanceStatusExcused}})
+	require.NoError(t, err)
+
+	rate, err := svc.StudentRate(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, entity.AttendanceRate{StudentID: 10, Present: 1, Late: 1}, *rate)
+	assert.InDelta(t, 1.0, rate.Rate(), 0.0001)
+
+	rate, err = svc.StudentRate(ctx, 1, 11)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, rate.Total())
+	assert.InDelta(t, 0.0, rate.Rate(), 0.0001)
+
+	// 沒有出席紀錄的學生
+	rate, err = svc.StudentRate(ctx, 1, 99)
+	require.NoError(t, err)
+	assert.Zero(t, rate.Total())
+
+	course, err := svc.CourseRate(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.AttendanceRate{Present: 1, Late: 1, Absent: 1, Excused: 1}, course.Overall)
+	assert.InDelta(t, 2.0/3.0, course.Overall.Rate(), 0.0001)
+	require.Len(t, course.Students, 2)
+	assert.EqualValues(t, 10, course.Students[0].StudentID)
+	assert.EqualValues(t, 11, course.Students[1].StudentID)
+	assert.EqualValues(t, 1, course.Students[1].Excused)
+
+	_, err = svc.CourseRate(ctx, 100)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
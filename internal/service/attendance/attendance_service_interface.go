@@ -0,0 +1,61 @@
+package attendance
+
+import (
+	"context"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+)
+
+// Mark 單一學生的點名結果
+type Mark struct {
+	StudentID uint                    // 學生 user ID
+	Status    entity.AttendanceStatus // 出席狀態
+	Note      string                  // 備註
+}
+
+// SessionAttendance 單次上課的點名結果
+type SessionAttendance struct {
+	Session     *courseEntity.CourseSession
+	Attendances []*entity.Attendance // 出席紀錄 , 依學生ID排序 , 尚未點名的學生沒有紀錄
+	LocksAt     time.Time            // 鎖定時間 , 之後不可再點名
+	Locked      bool                 // 是否已鎖定
+}
+
+// CourseAttendanceRate 課程的出席率
+type CourseAttendanceRate struct {
+	CourseID uint
+	Overall  entity.AttendanceRate   // 課程整體 , StudentID 為 0
+	Students []entity.AttendanceRate // 各學生 , 依學生ID排序 , 只包含有出席紀錄的學生
+}
+
+// AttendanceService 定義出席紀錄相關的業務邏輯
+// 課程的授課教師於上課開始後點名 , 上課結束超過 Config.LockAfter 後鎖定
+//
+// Example:
+//
+//	var svc AttendanceService
+//	result, err := svc.Mark(ctx, sessionID, userID, []Mark{{StudentID: 10, Status: entity.AttendanceStatusPresent}})
+type AttendanceService interface {
+	// ListBySession 查詢上課的點名結果
+	// 參數: ctx - context, sessionID - 上課ID
+	// 回傳: 點名結果, 錯誤訊息 , 上課不存在時回傳 gorm.ErrRecordNotFound
+	ListBySession(ctx context.Context, sessionID uint) (*SessionAttendance, error)
+
+	// Mark 批次點名 , 學生已有紀錄時更新 , 未列出的學生不變
+	// 參數: ctx - context, sessionID - 上課ID, userID - 操作者的 user ID , 須為課程授課教師的帳號, marks - 點名結果 , 學生須已報名課程
+	// 回傳: 點名後的結果 , 出席紀錄的 MarkedBy 為操作者對應的教師ID, 錯誤訊息 , 非授課教師回傳 ErrNotCourseTeacher , 學生未報名回傳 ErrNotEnrolled ,
+	// 上課已取消回傳 ErrSessionCancelled , 尚未開始回傳 ErrSessionNotStarted , 已鎖定回傳 ErrAttendanceLocked
+	Mark(ctx context.Context, sessionID, userID uint, marks []Mark) (*SessionAttendance, error)
+
+	// StudentRate 查詢學生於課程的出席率
+	// 參數: ctx - context, courseID - 課程ID, studentID - 學生 user ID
+	// 回傳: 出席率, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	StudentRate(ctx context.Context, courseID, studentID uint) (*entity.AttendanceRate, error)
+
+	// CourseRate 查詢課程整體與各學生的出席率
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 出席率, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	CourseRate(ctx context.Context, courseID uint) (*CourseAttendanceRate, error)
+}
@@ -0,0 +1,382 @@
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/certificate"
+	attendanceEntity "github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	attendanceRepo "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	certificateRepo "github.com/itmrchow/course-management-system/internal/repository/certificate"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ CertificateService = (*CertificateServiceImpl)(nil)
+
+var (
+	// ErrCourseNotEnded 課程尚未結束 , 無法發放證書
+	ErrCourseNotEnded = errors.New("course not ended")
+	// ErrCertificateRevoked 證書已撤銷
+	ErrCertificateRevoked = errors.New("certificate revoked")
+	// ErrInvalidCode 驗證碼格式不符
+	ErrInvalidCode = errors.New("invalid certificate code")
+)
+
+// Config 結業證書設定
+type Config struct {
+	MinAttendanceRate float64        // 發放證書的最低出席率 , 0 至 1
+	Issuer            string         // 發證單位名稱
+	VerifyBaseURL     string         // 本服務的網址 , 用於產生證書上的驗證網址 , 空字串表示不顯示
+	Location          *time.Location // 顯示發證日期的時區
+}
+
+// ConfigFromViper 從 viper 讀取結業證書設定
+// 回傳: 設定, 錯誤訊息 , 最低出席率超出範圍或時區不存在時回傳錯誤
+func ConfigFromViper() (Config, error) {
+	viper.SetDefault("CERTIFICATE_MIN_ATTENDANCE_RATE", 0.8)
+	viper.SetDefault("CERTIFICATE_TIMEZONE", "Asia/Taipei")
+
+	rate := viper.GetFloat64("CERTIFICATE_MIN_ATTENDANCE_RATE")
+	if rate < 0 || rate > 1 {
+		return Config{}, fmt.Errorf("CERTIFICATE_MIN_ATTENDANCE_RATE must be between 0 and 1, got %v", rate)
+	}
+	loc, err := time.LoadLocation(viper.GetString("CERTIFICATE_TIMEZONE"))
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load certificate timezone: %w", err)
+	}
+	return Config{
+		MinAttendanceRate: rate,
+		Issuer:            viper.GetString("CERTIFICATE_ISSUER_NAME"),
+		VerifyBaseURL:     viper.GetString("CERTIFICATE_VERIFY_BASE_URL"),
+		Location:          loc,
+	}, nil
+}
+
+// CertificateServiceImpl 實作 CertificateService 介面
+//
+// Example:
+//
+//	cfg, err := certificateService.ConfigFromViper()
+//	svc := certificateService.NewCertificateService(certificateRepo.NewCertificateRepository(db), courseRepo.NewCourseRepository(db), teacherRepo.NewTeacherRepository(db), enrollmentRepo.NewEnrollmentRepository(db), attendanceRepo.NewAttendanceRepository(db), sessionRepo.NewSessionRepository(db), outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db), certificate.NewRenderer(cfg.Location), cfg)
+//	issued, err := svc.IssueForCourse(ctx, courseID)
+type CertificateServiceImpl struct {
+	certificateRepo certificateRepo.CertificateRepository
+	courseRepo      courseRepo.CourseRepository
+	teacherRepo     teacherRepo.TeacherRepository
+	enrollmentRepo  enrollmentRepo.EnrollmentRepository
+	attendanceRepo  attendanceRepo.AttendanceRepository
+	sessionRepo     sessionRepo.SessionRepository
+	outboxRepo      outboxRepo.OutboxRepository
+	transactor      repo.Transactor
+	renderer        *certificate.Renderer
+	cfg             Config
+	now             func() time.Time
+}
+
+// NewCertificateService 建立結業證書業務邏輯實例
+// 參數: certificateRepo - 結業證書資料庫操作實例, courseRepo - 課程資料庫操作實例, teacherRepo - 教師資料庫操作實例, enrollmentRepo - 報名資料庫操作實例, attendanceRepo - 出席紀錄資料庫操作實例, sessionRepo - 上課資料庫操作實例 , 計算出席率的分母, outboxRepo - 領域事件 outbox, transactor - transaction, renderer - 證書輸出, cfg - 設定
+// 回傳: 結業證書業務邏輯實例
+func NewCertificateService(certificateRepo certificateRepo.CertificateRepository, courseRepo courseRepo.CourseRepository, teacherRepo teacherRepo.TeacherRepository, enrollmentRepo enrollmentRepo.EnrollmentRepository, attendanceRepo attendanceRepo.AttendanceRepository, sessionRepo sessionRepo.SessionRepository, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor, renderer *certificate.Renderer, cfg Config) CertificateService {
+	return &CertificateServiceImpl{
+		certificateRepo: certificateRepo,
+		courseRepo:      courseRepo,
+		teacherRepo:     teacherRepo,
+		enrollmentRepo:  enrollmentRepo,
+		attendanceRepo:  attendanceRepo,
+		sessionRepo:     sessionRepo,
+		outboxRepo:      outboxRepo,
+		transactor:      transactor,
+		renderer:        renderer,
+		cfg:             cfg,
+		now:             time.Now,
+	}
+}
+
+// IssueForCourse 為已結束課程的學生發放證書
+// 每張證書與事件在各自的 transaction 中新增 , 中途失敗時已發放的證書保留 , 重新呼叫時略過
+// 併發發放同一位學生時 , 後寫入的因課程與學生的唯一索引失敗 , 視為已發放
+// 出席率以課程已結束且未取消的上課為分母 , 沒有出席紀錄的上課計為缺席
+func (s *CertificateServiceImpl) IssueForCourse(ctx context.Context, courseID uint) (issued []*entity.Certificate, err error) {
+	ctx, span := tracing.Start(ctx, "CertificateService.IssueForCourse",
+		trace.WithAttributes(attribute.Int("course.id", int(courseID))))
+	defer tracing.End(span, &err)
+
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	if course.Status != courseEntity.CourseStatusEnd {
+		return nil, fmt.Errorf("course %d is %s: %w", courseID, course.Status, ErrCourseNotEnded)
+	}
+	teacherName, err := s.mainTeacherName(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.enrollmentRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			enrollmentEntity.EnrollmentOfCourse(courseID),
+			enrollmentEntity.InEnrollmentStatus([]enrollmentEntity.EnrollmentStatus{enrollmentEntity.EnrollmentStatusEnrolled}),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find enrollments of course %d: %w", courseID, err)
+	}
+	rates, err := s.rates(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.endedSessions(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.certificateRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.CertificateOfCourse(courseID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificates of course %d: %w", courseID, err)
+	}
+	issuedStudents := make(map[uint]bool, len(existing))
+	for _, c := range existing {
+		issuedStudents[c.StudentID] = true
+	}
+
+	for _, enrollment := range enrollments {
+		r, ok := rates[enrollment.StudentID]
+		if !ok {
+			r = &attendanceEntity.AttendanceRate{StudentID: enrollment.StudentID}
+		}
+		r.AddMissing(sessions)
+		rate := r.Rate()
+		if issuedStudents[enrollment.StudentID] || rate < s.cfg.MinAttendanceRate {
+			continue
+		}
+
+		code, err := entity.NewCode()
+		if err != nil {
+			return issued, err
+		}
+		c := &entity.Certificate{
+			Code:           code,
+			CourseID:       courseID,
+			StudentID:      enrollment.StudentID,
+			EnrollmentID:   enrollment.ID,
+			CourseName:     course.Name,
+			TeacherName:    teacherName,
+			AttendanceRate: rate,
+			Status:         entity.CertificateStatusValid,
+			IssuedAt:       s.now(),
+		}
+		err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+			if _, err := s.certificateRepo.Create(ctx, c); err != nil {
+				return fmt.Errorf("failed to create certificate of student %d in course %d: %w", c.StudentID, courseID, err)
+			}
+			return s.outboxRepo.Add(ctx, event.CertificateIssued{
+				CertificateID: c.ID,
+				Code:          c.Code,
+				CourseID:      c.CourseID,
+				StudentID:     c.StudentID,
+				IssuedAt:      c.IssuedAt,
+			})
+		})
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		}
+		if err != nil {
+			return issued, err
+		}
+		issued = append(issued, c)
+	}
+	return issued, nil
+}
+
+// mainTeacherName 查詢課程主教師的姓名 , 沒有主教師或教師已刪除時回傳空字串
+func (s *CertificateServiceImpl) mainTeacherName(ctx context.Context, courseID uint) (string, error) {
+	teachers, err := s.courseRepo.ListTeachers(ctx, courseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list teachers of course %d: %w", courseID, err)
+	}
+	for _, courseTeacher := range teachers {
+		if !courseTeacher.IsMain {
+			continue
+		}
+		teacher, err := s.teacherRepo.GetByID(ctx, courseTeacher.TeacherID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get teacher %d: %w", courseTeacher.TeacherID, err)
+		}
+		return teacher.Name, nil
+	}
+	return "", nil
+}
+
+// rates 查詢課程各學生的出席率
+func (s *CertificateServiceImpl) rates(ctx context.Context, courseID uint) (map[uint]*attendanceEntity.AttendanceRate, error) {
+	counts, err := s.attendanceRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{attendanceEntity.AttendanceOfCourse(courseID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance of course %d: %w", courseID, err)
+	}
+	rates := make(map[uint]*attendanceEntity.AttendanceRate)
+	for _, c := range counts {
+		rate, ok := rates[c.StudentID]
+		if !ok {
+			rate = &attendanceEntity.AttendanceRate{StudentID: c.StudentID}
+			rates[c.StudentID] = rate
+		}
+		rate.Add(c.Status, c.Count)
+	}
+	return rates, nil
+}
+
+// endedSessions 查詢課程已結束且未取消的上課數量
+func (s *CertificateServiceImpl) endedSessions(ctx context.Context, courseID uint) (int64, error) {
+	sessions, err := s.sessionRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			courseEntity.SessionOfCourse(courseID),
+			courseEntity.InSessionStatus([]courseEntity.SessionStatus{courseEntity.SessionStatusScheduled}),
+			courseEntity.SessionEndedBy(s.now()),
+		})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find sessions of course %d: %w", courseID, err)
+	}
+	return int64(len(sessions)), nil
+}
+
+// Get 依證書ID查詢證書
+func (s *CertificateServiceImpl) Get(ctx context.Context, id uint) (*entity.Certificate, error) {
+	c, err := s.certificateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate %d: %w", id, err)
+	}
+	return c, nil
+}
+
+// List 查詢證書清單
+func (s *CertificateServiceImpl) List(ctx context.Context, pageInfo *repo.RepoPageInfo, courseID, studentID uint) ([]*entity.Certificate, error) {
+	var conditions []func(db *gorm.DB) *gorm.DB
+	if courseID != 0 {
+		conditions = append(conditions, entity.CertificateOfCourse(courseID))
+	}
+	if studentID != 0 {
+		conditions = append(conditions, entity.CertificateOfStudent(studentID))
+	}
+	certificates, err := s.certificateRepo.Find(ctx, pageInfo, conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificates: %w", err)
+	}
+	return certificates, nil
+}
+
+// Verify 依驗證碼查詢證書
+func (s *CertificateServiceImpl) Verify(ctx context.Context, code string) (*entity.Certificate, error) {
+	normalized, ok := entity.NormalizeCode(code)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+	c, err := s.certificateRepo.GetByCode(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certificate %s: %w", normalized, err)
+	}
+	return c, nil
+}
+
+// Revoke 撤銷證書 , 撤銷與事件在同一個 transaction 中
+func (s *CertificateServiceImpl) Revoke(ctx context.Context, id uint, reason string) (c *entity.Certificate, err error) {
+	ctx, span := tracing.Start(ctx, "CertificateService.Revoke",
+		trace.WithAttributes(attribute.Int("certificate.id", int(id))))
+	defer tracing.End(span, &err)
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		now := s.now()
+		affected, err := s.certificateRepo.Revoke(ctx, id, reason, now)
+		if err != nil {
+			return fmt.Errorf("failed to revoke certificate %d: %w", id, err)
+		}
+		if affected == 0 {
+			// 區分不存在與已撤銷
+			if _, err := s.certificateRepo.GetByID(ctx, id); err != nil {
+				return fmt.Errorf("failed to get certificate %d: %w", id, err)
+			}
+			return fmt.Errorf("certificate %d: %w", id, ErrCertificateRevoked)
+		}
+
+		c, err = s.certificateRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get certificate %d: %w", id, err)
+		}
+		return s.outboxRepo.Add(ctx, event.CertificateRevoked{
+			CertificateID: c.ID,
+			Code:          c.Code,
+			CourseID:      c.CourseID,
+			StudentID:     c.StudentID,
+			Reason:        reason,
+			RevokedAt:     now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Render 依語系版面輸出證書 PDF , 已撤銷的證書不再提供下載
+func (s *CertificateServiceImpl) Render(ctx context.Context, id uint, locale string) ([]byte, error) {
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status == entity.CertificateStatusRevoked {
+		return nil, fmt.Errorf("certificate %d: %w", id, ErrCertificateRevoked)
+	}
+	return s.renderer.Render(certificate.NewDocument(s.cfg.Issuer, c, s.verifyURL(c.Code)), locale)
+}
+
+// verifyURL 證書的驗證網址 , 未設定 VerifyBaseURL 時回傳空字串
+func (s *CertificateServiceImpl) verifyURL(code string) string {
+	if s.cfg.VerifyBaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(s.cfg.VerifyBaseURL, "/") + "/certificates/verify?code=" + code
+}
+
+// HandleEvent 處理課程狀態變更事件 , 課程結束時發放證書
+func (s *CertificateServiceImpl) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	switch msg.Type {
+	case event.TypeCourseStatusChanged:
+		var e event.CourseStatusChanged
+		if err := json.Unmarshal(msg.Payload, &e); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", msg.Type, err)
+		}
+		if e.To != courseEntity.CourseStatusEnd.String() {
+			return nil
+		}
+		_, err := s.IssueForCourse(ctx, e.CourseID)
+		return err
+
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,322 @@
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/certificate"
+	attendanceEntity "github.com/itmrchow/course-management-system/internal/domain/attendance/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	attendanceRepo "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	certificateRepo "github.com/itmrchow/course-management-system/internal/repository/certificate"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var testNow = time.Date(2025, 9, 1, 4, 0, 0, 0, time.UTC)
+
+type testEnv struct {
+	svc         *CertificateServiceImpl
+	teachers    teacherRepo.TeacherRepository
+	enrollments enrollmentRepo.EnrollmentRepository
+	sessions    sessionRepo.SessionRepository
+	outbox      outboxRepo.OutboxRepository
+}
+
+// newTestEnv 建立使用記憶體 repository 的結業證書業務邏輯 , 最低出席率 0.8
+// 課程 1 已結束 , 主教師為王小明 (教師 1) ; 課程 2 開放報名中
+// 課程 1 已結束 4 次上課 (上課 1 ~ 4) , 另有 1 次已取消與 1 次尚未結束的上課不計入出席率
+// 學生 10 全勤 , 學生 11 缺席 1 次 (0.75) , 學生 12 遲到 3 次並請假 1 次 (1.0) , 學生 13 全勤但已退出
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	teachers := teacherRepo.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 1, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 2, Name: "陳助教", Phone: "0912345679", Email: "chen@example.com", Status: teacherEntity.TeacherStatusApproved},
+	} {
+		_, err := teachers.Create(ctx, teacher)
+		require.NoError(t, err)
+	}
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusEnd})
+	require.NoError(t, err)
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Status: courseEntity.CourseStatusOnline})
+	require.NoError(t, err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 2})
+	require.NoError(t, err)
+	_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: 1, IsMain: true})
+	require.NoError(t, err)
+
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	for _, e := range []*enrollmentEntity.Enrollment{
+		{CourseID: 1, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 11, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 12, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 13, Status: enrollmentEntity.EnrollmentStatusWithdrawn},
+	} {
+		_, err := enrollments.Create(ctx, e)
+		require.NoError(t, err)
+	}
+
+	sessions := sessionRepo.NewSessionMemoryRepository()
+	start := testNow.AddDate(0, 0, -28)
+	for i := range 6 {
+		_, err := sessions.Ensure(ctx, &courseEntity.CourseSession{CourseID: 1, PatternID: 1, StartAt: start.AddDate(0, 0, 7*i), EndAt: start.AddDate(0, 0, 7*i).Add(2 * time.Hour)})
+		require.NoError(t, err)
+	}
+	_, err = sessions.Cancel(ctx, 5, "颱風停課", testNow)
+	require.NoError(t, err)
+
+	attendances := attendanceRepo.NewAttendanceMemoryRepository()
+	statuses := map[uint][]attendanceEntity.AttendanceStatus{
+		10: {attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent},
+		11: {attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusAbsent},
+		12: {attendanceEntity.AttendanceStatusLate, attendanceEntity.AttendanceStatusLate, attendanceEntity.AttendanceStatusExcused, attendanceEntity.AttendanceStatusLate},
+		13: {attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent, attendanceEntity.AttendanceStatusPresent},
+	}
+	for studentID, list := range statuses {
+		for i, status := range list {
+			require.NoError(t, attendances.Upsert(ctx, []*attendanceEntity.Attendance{
+				{SessionID: uint(i + 1), StudentID: studentID, CourseID: 1, EnrollmentID: studentID - 9, Status: status, MarkedBy: 1, MarkedAt: testNow},
+			}))
+		}
+	}
+
+	loc, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	outbox := outboxRepo.NewOutboxMemoryRepository()
+	svc := NewCertificateService(certificateRepo.NewCertificateMemoryRepository(), courses, teachers, enrollments, attendances, sessions, outbox, repository.NoTransaction,
+		certificate.NewRenderer(loc), Config{MinAttendanceRate: 0.8, Issuer: "課程管理系統", VerifyBaseURL: "https://example.com/", Location: loc}).(*CertificateServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return &testEnv{svc: svc, teachers: teachers, enrollments: enrollments, sessions: sessions, outbox: outbox}
+}
+
+// 發放證書測試
+// Test for CertificateServiceImpl.IssueForCourse
+func TestIssueForCourse(t *testing.T) {
+	tests := []struct {
+		name       string
+		courseID   uint
+		setup      func(t *testing.T, e *testEnv)
+		assertFunc func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error)
+	}{
+		{
+			name:     "出席率達門檻的學生",
+			courseID: 1,
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				require.Len(t, issued, 2)
+				assert.EqualValues(t, 10, issued[0].StudentID)
+				assert.EqualValues(t, 12, issued[1].StudentID)
+				assert.InDelta(t, 1.0, issued[1].AttendanceRate, 0.0001)
+				assert.Equal(t, "Go 入門", issued[0].CourseName)
+				assert.Equal(t, "王小明", issued[0].TeacherName)
+				assert.Regexp(t, `^[0-9A-Z]{4}-[0-9A-Z]{4}-[0-9A-Z]{4}$`, issued[0].Code)
+				assert.NotEqual(t, issued[0].Code, issued[1].Code)
+
+				events, err := e.outbox.ListPending(context.Background(), time.Now().Add(time.Second), 100)
+				require.NoError(t, err)
+				require.Len(t, events, 2)
+				assert.Equal(t, event.TypeCertificateIssued, events[0].EventType)
+			},
+		},
+		{
+			name:     "已發放的學生略過",
+			courseID: 1,
+			setup: func(t *testing.T, e *testEnv) {
+				_, err := e.svc.IssueForCourse(context.Background(), 1)
+				require.NoError(t, err)
+			},
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, issued)
+			},
+		},
+		{
+			name:     "門檻為 0 時所有已報名的學生",
+			courseID: 1,
+			setup: func(t *testing.T, e *testEnv) {
+				e.svc.cfg.MinAttendanceRate = 0
+			},
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				assert.Len(t, issued, 3)
+			},
+		},
+		{
+			name:     "沒有出席紀錄的學生出席率為 0",
+			courseID: 1,
+			setup: func(t *testing.T, e *testEnv) {
+				_, err := e.enrollments.Create(context.Background(), &enrollmentEntity.Enrollment{CourseID: 1, StudentID: 14, Status: enrollmentEntity.EnrollmentStatusEnrolled})
+				require.NoError(t, err)
+				e.svc.cfg.MinAttendanceRate = 0
+			},
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				require.Len(t, issued, 4)
+				assert.EqualValues(t, 14, issued[3].StudentID)
+				assert.Zero(t, issued[3].AttendanceRate)
+			},
+		},
+		{
+			name:     "未點名的上課計為缺席",
+			courseID: 1,
+			setup: func(t *testing.T, e *testEnv) {
+				e.svc.now = func() time.Time { return testNow.AddDate(0, 0, 8) }
+			},
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				require.Len(t, issued, 1)
+				assert.EqualValues(t, 10, issued[0].StudentID)
+				assert.InDelta(t, 0.8, issued[0].AttendanceRate, 0.0001)
+			},
+		},
+		{
+			name:     "主教師已刪除",
+			courseID: 1,
+			setup: func(t *testing.T, e *testEnv) {
+				_, err := e.teachers.Delete(context.Background(), 1)
+				require.NoError(t, err)
+			},
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, issued)
+				assert.Empty(t, issued[0].TeacherName)
+			},
+		},
+		{
+			name:     "課程尚未結束",
+			courseID: 2,
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				assert.ErrorIs(t, err, ErrCourseNotEnded)
+			},
+		},
+		{
+			name:     "課程不存在",
+			courseID: 100,
+			assertFunc: func(t *testing.T, e *testEnv, issued []*entity.Certificate, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+			issued, err := e.svc.IssueForCourse(context.Background(), tt.courseID)
+			tt.assertFunc(t, e, issued, err)
+		})
+	}
+}
+
+func TestVerifyAndRevoke(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+	issued, err := e.svc.IssueForCourse(ctx, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, issued)
+	code := issued[0].Code
+
+	// 不分大小寫 , 可省略連字號
+	c, err := e.svc.Verify(ctx, strings.ToLower(strings.ReplaceAll(code, "-", "")))
+	require.NoError(t, err)
+	assert.Equal(t, issued[0].ID, c.ID)
+	assert.Equal(t, entity.CertificateStatusValid, c.Status)
+
+	_, err = e.svc.Verify(ctx, "not-a-code")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	_, err = e.svc.Verify(ctx, "0000-0000-0000")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	c, err = e.svc.Revoke(ctx, issued[0].ID, "出席紀錄有誤")
+	require.NoError(t, err)
+	assert.Equal(t, entity.CertificateStatusRevoked, c.Status)
+	assert.Equal(t, "出席紀錄有誤", c.RevokeReason)
+
+	// 撤銷後仍可驗證 , 狀態為已撤銷
+	c, err = e.svc.Verify(ctx, code)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CertificateStatusRevoked, c.Status)
+
+	_, err = e.svc.Revoke(ctx, issued[0].ID, "重複撤銷")
+	assert.ErrorIs(t, err, ErrCertificateRevoked)
+	_, err = e.svc.Revoke(ctx, 100, "不存在")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// 撤銷的證書不會重新發放
+	reissued, err := e.svc.IssueForCourse(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, reissued)
+}
+
+func TestHandleEvent(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+
+	message := func(to string) outbox.Message {
+		payload, err := json.Marshal(event.CourseStatusChanged{CourseID: 1, From: "online", To: to, ChangedAt: testNow})
+		require.NoError(t, err)
+		return outbox.Message{Type: event.TypeCourseStatusChanged, Payload: payload}
+	}
+
+	// 其他狀態變更不發放
+	require.NoError(t, e.svc.HandleEvent(ctx, message("pause")))
+	certificates, err := e.svc.List(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 1, 0)
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	// 事件重送時不會重複發放
+	require.NoError(t, e.svc.HandleEvent(ctx, message("end")))
+	require.NoError(t, e.svc.HandleEvent(ctx, message("end")))
+	certificates, err = e.svc.List(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 1, 0)
+	require.NoError(t, err)
+	assert.Len(t, certificates, 2)
+
+	certificates, err = e.svc.List(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 0, 12)
+	require.NoError(t, err)
+	assert.Len(t, certificates, 1)
+}
+
+func TestRender(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+	issued, err := e.svc.IssueForCourse(ctx, 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, issued)
+
+	b, err := e.svc.Render(ctx, issued[0].ID, "en")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "%PDF-"))
+	assert.Contains(t, string(b), "(Verify at: https://example.com/certificates/verify?code="+issued[0].Code+") Tj")
+	assert.Contains(t, string(b), "(Date of issue: Sep 1, 2025) Tj")
+
+	_, err = e.svc.Render(ctx, issued[0].ID, "fr")
+	assert.ErrorIs(t, err, certificate.ErrUnsupportedLocale)
+
+	_, err = e.svc.Revoke(ctx, issued[0].ID, "出席紀錄有誤")
+	require.NoError(t, err)
+	_, err = e.svc.Render(ctx, issued[0].ID, "en")
+	assert.ErrorIs(t, err, ErrCertificateRevoked)
+}
@@ -0,0 +1,54 @@
+package certificate
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/certificate/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// CertificateService 定義結業證書相關的業務邏輯
+// 課程結束時 , 出席率達 Config.MinAttendanceRate 的已報名學生各發放一張證書 , 證書可公開驗證與撤銷
+//
+// Example:
+//
+//	var svc CertificateService
+//	issued, err := svc.IssueForCourse(ctx, courseID)
+//	certificate, err := svc.Verify(ctx, "7K3M-Q9TX-2HNB")
+type CertificateService interface {
+	// IssueForCourse 為已結束課程的學生發放證書 , 已有證書的學生略過 , 可重複呼叫
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 本次發放的證書, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound , 課程尚未結束回傳 ErrCourseNotEnded
+	IssueForCourse(ctx context.Context, courseID uint) ([]*entity.Certificate, error)
+
+	// Get 依證書ID查詢證書
+	// 參數: ctx - context, id - 證書ID
+	// 回傳: 證書, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	Get(ctx context.Context, id uint) (*entity.Certificate, error)
+
+	// List 查詢證書清單
+	// 參數: ctx - context, pageInfo - 分頁與排序, courseID - 課程ID , 0 表示不限, studentID - 學生 user ID , 0 表示不限
+	// 回傳: 證書, 錯誤訊息
+	List(ctx context.Context, pageInfo *repo.RepoPageInfo, courseID, studentID uint) ([]*entity.Certificate, error)
+
+	// Verify 依驗證碼查詢證書 , 已撤銷的證書同樣回傳 , 由呼叫端依狀態顯示
+	// 參數: ctx - context, code - 驗證碼 , 不分大小寫 , 可省略連字號
+	// 回傳: 證書, 錯誤訊息 , 格式不符回傳 ErrInvalidCode , 不存在時回傳 gorm.ErrRecordNotFound
+	Verify(ctx context.Context, code string) (*entity.Certificate, error)
+
+	// Revoke 撤銷證書
+	// 參數: ctx - context, id - 證書ID, reason - 撤銷原因
+	// 回傳: 撤銷後的證書, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound , 已撤銷回傳 ErrCertificateRevoked
+	Revoke(ctx context.Context, id uint, reason string) (*entity.Certificate, error)
+
+	// Render 依語系版面輸出證書 PDF
+	// 參數: ctx - context, id - 證書ID, locale - 語系
+	// 回傳: PDF 檔案內容, 錯誤訊息 , 已撤銷回傳 ErrCertificateRevoked , 不支援的語系回傳 certificate.ErrUnsupportedLocale
+	Render(ctx context.Context, id uint, locale string) ([]byte, error)
+
+	// HandleEvent 處理課程狀態變更事件 , 課程結束時發放證書 , 可作為 outbox.Handler
+	// 參數: ctx - context, msg - 事件
+	// 回傳: 錯誤訊息
+	HandleEvent(ctx context.Context, msg outbox.Message) error
+}
@@ -0,0 +1,271 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/money"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ CourseService = (*CourseServiceImpl)(nil)
+
+var (
+	// ErrInvalidStatusTransition 課程狀態不允許轉換至指定狀態
+	ErrInvalidStatusTransition = errors.New("invalid course status transition")
+	// ErrInvalidPrice 價格紀錄的級距、生效時間或截止時間無效
+	ErrInvalidPrice = errors.New("invalid course price")
+	// ErrInvalidRefundRule 退款規則的基準時間或比例無效
+	ErrInvalidRefundRule = errors.New("invalid course refund rule")
+)
+
+// maxRefundRules 每個課程的退款規則數量上限
+const maxRefundRules = 10
+
+// CourseServiceImpl 實作 CourseService 介面
+//
+// Example:
+//
+//	svc := course.NewCourseService(courseRepo.NewCourseRepository(db), outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db))
+//	err := svc.ChangeStatus(ctx, 1, courseEntity.CourseStatusOnline)
+type CourseServiceImpl struct {
+	courseRepo courseRepo.CourseRepository
+	outboxRepo outboxRepo.OutboxRepository
+	transactor repo.Transactor
+	now        func() time.Time
+}
+
+// NewCourseService 建立課程業務邏輯實例
+// 參數: courseRepo - 課程資料庫操作實例, outboxRepo - 領域事件 outbox, transactor - transaction
+// 回傳: 課程業務邏輯實例
+func NewCourseService(courseRepo courseRepo.CourseRepository, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor) CourseService {
+	return &CourseServiceImpl{courseRepo: courseRepo, outboxRepo: outboxRepo, transactor: transactor, now: time.Now}
+}
+
+// ChangeStatus 變更課程狀態
+// 依 CourseStatus.CanTransitionTo 檢查轉換規則 , 並以目前狀態為條件更新避免併發覆蓋
+// 狀態變更與 CourseStatusChanged 事件在同一個 transaction 寫入 , 變更為開放報名時另外寫入 CoursePublished , 取消時另外寫入 CourseCancelled
+func (s *CourseServiceImpl) ChangeStatus(ctx context.Context, id uint, status courseEntity.CourseStatus) (err error) {
+	ctx, span := tracing.Start(ctx, "CourseService.ChangeStatus",
+		trace.WithAttributes(
+			attribute.Int("course.id", int(id)),
+			attribute.String("course.status", status.String()),
+		))
+	defer tracing.End(span, &err)
+
+	var from courseEntity.CourseStatus
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		course, err := s.courseRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", id, err)
+		}
+
+		from = course.Status
+		if !from.CanTransitionTo(status) {
+			return fmt.Errorf("course %d from %s to %s: %w", id, from, status, ErrInvalidStatusTransition)
+		}
+
+		rows, err := s.courseRepo.UpdateStatus(ctx, id, from, status)
+		if err != nil {
+			return fmt.Errorf("failed to update course %d status: %w", id, err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("course %d status changed concurrently: %w", id, ErrInvalidStatusTransition)
+		}
+
+		if err := s.outboxRepo.Add(ctx, statusChangedEvents(course, status, s.now())...); err != nil {
+			return fmt.Errorf("failed to add course %d status events: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	metrics.CourseStatusTransitionsTotal.WithLabelValues(from.String(), status.String()).Inc()
+	zerolog.Ctx(ctx).Info().
+		Uint("course_id", id).
+		Stringer("from", from).
+		Stringer("to", status).
+		Msg("course status changed")
+
+	return nil
+}
+
+// AddPrice 新增課程的價格紀錄
+// 價格紀錄只新增不修改 , 調價時新增生效時間較晚的紀錄 , 取消早鳥價時新增截止時間等於生效時間的早鳥價
+func (s *CourseServiceImpl) AddPrice(ctx context.Context, price *courseEntity.CoursePrice) (*courseEntity.CoursePrice, error) {
+	course, err := s.courseRepo.GetByID(ctx, price.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", price.CourseID, err)
+	}
+
+	if _, err := money.New(int64(price.Amount), price.Currency); err != nil {
+		return nil, err
+	}
+	if price.Currency != course.Currency {
+		return nil, fmt.Errorf("course %d is priced in %s, got %s: %w", course.ID, course.Currency, price.Currency, money.ErrCurrencyMismatch)
+	}
+
+	now := s.now()
+	if price.EffectiveFrom.IsZero() {
+		price.EffectiveFrom = now
+	}
+	if price.EffectiveFrom.Before(now) {
+		return nil, fmt.Errorf("%w: effective_from must not be in the past", ErrInvalidPrice)
+	}
+	switch price.Tier {
+	case courseEntity.PriceTierRegular:
+		if price.ValidUntil != nil {
+			return nil, fmt.Errorf("%w: regular price must not have valid_until", ErrInvalidPrice)
+		}
+	case courseEntity.PriceTierEarlyBird:
+		if price.ValidUntil == nil || price.ValidUntil.Before(price.EffectiveFrom) {
+			return nil, fmt.Errorf("%w: early bird price requires valid_until not before effective_from", ErrInvalidPrice)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown tier %d", ErrInvalidPrice, price.Tier)
+	}
+
+	if _, err := s.courseRepo.CreatePrice(ctx, price); err != nil {
+		return nil, fmt.Errorf("failed to create price of course %d: %w", course.ID, err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Uint("course_id", course.ID).
+		Uint("price_id", price.ID).
+		Stringer("tier", price.Tier).
+		Stringer("price", price.Money()).
+		Time("effective_from", price.EffectiveFrom).
+		Msg("course price added")
+
+	return price, nil
+}
+
+// ListPrices 查詢課程的價格歷史
+func (s *CourseServiceImpl) ListPrices(ctx context.Context, courseID uint) ([]*courseEntity.CoursePrice, error) {
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+
+	prices, err := s.courseRepo.ListPrices(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prices of course %d: %w", courseID, err)
+	}
+	return prices, nil
+}
+
+// Quote 計算課程於 at 的售價
+func (s *CourseServiceImpl) Quote(ctx context.Context, courseID uint, at time.Time) (*courseEntity.PriceQuote, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+
+	prices, err := s.courseRepo.ListPrices(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prices of course %d: %w", courseID, err)
+	}
+	quote := courseEntity.QuotePrice(course, prices, at)
+	return &quote, nil
+}
+
+// ListRefundRules 查詢課程設定的退款規則
+func (s *CourseServiceImpl) ListRefundRules(ctx context.Context, courseID uint) ([]*courseEntity.CourseRefundRule, error) {
+	if _, err := s.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+
+	rules, err := s.courseRepo.ListRefundRules(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refund rules of course %d: %w", courseID, err)
+	}
+	return rules, nil
+}
+
+// SetRefundRules 以 rules 取代課程的退款規則
+// 規則變更只影響之後的退款 , 已建立的退款紀錄保存計算時的比例
+func (s *CourseServiceImpl) SetRefundRules(ctx context.Context, courseID uint, rules []*courseEntity.CourseRefundRule) ([]*courseEntity.CourseRefundRule, error) {
+	if len(rules) > maxRefundRules {
+		return nil, fmt.Errorf("%w: at most %d rules", ErrInvalidRefundRule, maxRefundRules)
+	}
+	seen := make(map[courseEntity.RefundAnchor]map[int]bool)
+	for _, rule := range rules {
+		if rule.Anchor != courseEntity.RefundAnchorRegistrationEnd && rule.Anchor != courseEntity.RefundAnchorStart {
+			return nil, fmt.Errorf("%w: unknown anchor %d", ErrInvalidRefundRule, rule.Anchor)
+		}
+		if rule.Percent > 100 {
+			return nil, fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalidRefundRule)
+		}
+		if seen[rule.Anchor][rule.OffsetHours] {
+			return nil, fmt.Errorf("%w: duplicate deadline %s%+dh", ErrInvalidRefundRule, rule.Anchor, rule.OffsetHours)
+		}
+		if seen[rule.Anchor] == nil {
+			seen[rule.Anchor] = make(map[int]bool)
+		}
+		seen[rule.Anchor][rule.OffsetHours] = true
+	}
+
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.courseRepo.LockByID(ctx, courseID); err != nil {
+			return fmt.Errorf("failed to lock course %d: %w", courseID, err)
+		}
+		if err := s.courseRepo.ReplaceRefundRules(ctx, courseID, rules); err != nil {
+			return fmt.Errorf("failed to replace refund rules of course %d: %w", courseID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Uint("course_id", courseID).
+		Int("rules", len(rules)).
+		Msg("course refund rules updated")
+
+	return rules, nil
+}
+
+// statusChangedEvents 建立課程狀態變更的事件 , course 為變更前的課程
+func statusChangedEvents(course *courseEntity.Course, to courseEntity.CourseStatus, now time.Time) []event.Event {
+	events := []event.Event{event.CourseStatusChanged{
+		CourseID:  course.ID,
+		From:      course.Status.String(),
+		To:        to.String(),
+		ChangedAt: now,
+	}}
+
+	if to == courseEntity.CourseStatusOnline {
+		events = append(events, event.CoursePublished{
+			CourseID:              course.ID,
+			Name:                  course.Name,
+			Price:                 course.Price,
+			Currency:              course.Currency,
+			MaxStudents:           course.MaxStudents,
+			RegistrationStartDate: course.RegistrationStartDate,
+			RegistrationEndDate:   course.RegistrationEndDate,
+			PublishedAt:           now,
+		})
+	}
+	if to == courseEntity.CourseStatusCancelled {
+		events = append(events, event.CourseCancelled{
+			CourseID:    course.ID,
+			Name:        course.Name,
+			CancelledAt: now,
+		})
+	}
+	return events
+}
@@ -0,0 +1,378 @@
+package course
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	outboxEntity "github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/money"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+)
+
+// stubCourseRepo 測試用的課程 repository , 只實作 ChangeStatus 使用到的方法
+type stubCourseRepo struct {
+	courseRepo.CourseRepository
+	courses map[uint]*courseEntity.Course
+}
+
+func (r *stubCourseRepo) GetByID(ctx context.Context, id uint) (*courseEntity.Course, error) {
+	course, ok := r.courses[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	c := *course
+	return &c, nil
+}
+
+func (r *stubCourseRepo) UpdateStatus(ctx context.Context, id uint, from, to courseEntity.CourseStatus) (int64, error) {
+	course, ok := r.courses[id]
+	if !ok || course.Status != from {
+		return 0, nil
+	}
+	course.Status = to
+	return 1, nil
+}
+
+// 變更課程狀態測試
+// Test for CourseServiceImpl.ChangeStatus
+func TestChangeStatus(t *testing.T) {
+	type args struct {
+		id     uint
+		status courseEntity.CourseStatus
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, repo *stubCourseRepo, events []*outboxEntity.OutboxEvent, err error)
+	}{
+		{
+			name: "not found",
+			args: args{id: 100, status: courseEntity.CourseStatusOnline},
+			assertFunc: func(t *testing.T, repo *stubCourseRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "invalid transition",
+			args: args{id: 1, status: courseEntity.CourseStatusEnd},
+			assertFunc: func(t *testing.T, repo *stubCourseRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+				assert.Equal(t, courseEntity.CourseStatusPending, repo.courses[1].Status)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "success",
+			args: args{id: 1, status: courseEntity.CourseStatusOnline},
+			assertFunc: func(t *testing.T, repo *stubCourseRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, courseEntity.CourseStatusOnline, repo.courses[1].Status)
+
+				// 變更為開放報名時另外寫入 CoursePublished
+				if assert.Len(t, events, 2) {
+					assert.Equal(t, event.TypeCourseStatusChanged, events[0].EventType)
+					var changed event.CourseStatusChanged
+					assert.NoError(t, json.Unmarshal([]byte(events[0].Payload), &changed))
+					assert.Equal(t, event.CourseStatusChanged{CourseID: 1, From: "pending", To: "online", ChangedAt: changed.ChangedAt}, changed)
+					assert.Equal(t, event.TypeCoursePublished, events[1].EventType)
+					assert.EqualValues(t, 1, events[1].AggregateID)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &stubCourseRepo{courses: map[uint]*courseEntity.Course{
+				1: {Model: gorm.Model{ID: 1}, Status: courseEntity.CourseStatusPending},
+			}}
+			counter := metrics.CourseStatusTransitionsTotal.WithLabelValues("pending", "online")
+			before := testutil.ToFloat64(counter)
+
+			outbox := outboxRepo.NewOutboxMemoryRepository()
+
+			err := NewCourseService(repo, outbox, repository.NoTransaction).ChangeStatus(context.Background(), test.args.id, test.args.status)
+			events, listErr := outbox.ListPending(context.Background(), time.Now().Add(time.Second), 10)
+			assert.NoError(t, listErr)
+			test.assertFunc(t, repo, events, err)
+
+			if err == nil {
+				assert.Equal(t, before+1, testutil.ToFloat64(counter))
+			} else {
+				assert.Equal(t, before, testutil.ToFloat64(counter))
+			}
+		})
+	}
+}
+
+var testNow = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+// newPriceTestService 建立使用記憶體 repository 的課程業務邏輯 , 課程 1 以 TWD 計價 , 基本價格 3,000
+func newPriceTestService(t *testing.T) *CourseServiceImpl {
+	t.Helper()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(context.Background(), &courseEntity.Course{Name: "Go 入門", Price: 300000, Currency: "TWD"})
+	require.NoError(t, err)
+
+	svc := NewCourseService(courses, outboxRepo.NewOutboxMemoryRepository(), repository.NoTransaction).(*CourseServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return svc
+}
+
+// 新增價格紀錄測試
+// Test for CourseServiceImpl.AddPrice
+func TestAddPrice(t *testing.T) {
+	until := testNow.AddDate(0, 0, 14)
+	past := testNow.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		price      *courseEntity.CoursePrice
+		assertFunc func(t *testing.T, price *courseEntity.CoursePrice, err error)
+	}{
+		{
+			name:  "regular defaults to now",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 350000, Currency: "TWD"},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				require.NoError(t, err)
+				assert.NotZero(t, price.ID)
+				assert.True(t, price.EffectiveFrom.Equal(testNow))
+			},
+		},
+		{
+			name:  "early bird",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierEarlyBird, Amount: 250000, Currency: "TWD", ValidUntil: &until},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:  "early bird without valid until",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierEarlyBird, Amount: 250000, Currency: "TWD"},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPrice)
+			},
+		},
+		{
+			name:  "regular with valid until",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 250000, Currency: "TWD", ValidUntil: &until},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPrice)
+			},
+		},
+		{
+			name:  "backdated",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 250000, Currency: "TWD", EffectiveFrom: past},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPrice)
+			},
+		},
+		{
+			name:  "currency mismatch",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 9900, Currency: "USD"},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+			},
+		},
+		{
+			name:  "unsupported currency",
+			price: &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 9900, Currency: "XXX"},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, money.ErrUnsupportedCurrency)
+			},
+		},
+		{
+			name:  "course not found",
+			price: &courseEntity.CoursePrice{CourseID: 100, Tier: courseEntity.PriceTierRegular, Amount: 9900, Currency: "TWD"},
+			assertFunc: func(t *testing.T, price *courseEntity.CoursePrice, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := newPriceTestService(t).AddPrice(context.Background(), tt.price)
+			tt.assertFunc(t, price, err)
+		})
+	}
+}
+
+// 計算售價測試 , 早鳥價於截止前優先 , 調價後依生效時間使用新價格
+// Test for CourseServiceImpl.Quote
+func TestQuote(t *testing.T) {
+	svc := newPriceTestService(t)
+	ctx := context.Background()
+
+	quote, err := svc.Quote(ctx, 1, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, money.Money{Amount: 300000, Currency: "TWD"}, quote.Price)
+	assert.Zero(t, quote.PriceID, "no price history falls back to base price")
+
+	until := testNow.AddDate(0, 0, 14)
+	for _, price := range []*courseEntity.CoursePrice{
+		{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 320000, Currency: "TWD"},
+		{CourseID: 1, Tier: courseEntity.PriceTierEarlyBird, Amount: 250000, Currency: "TWD", ValidUntil: &until},
+		{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 350000, Currency: "TWD", EffectiveFrom: testNow.AddDate(0, 1, 0)},
+	} {
+		_, err := svc.AddPrice(ctx, price)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want money.Money
+		tier courseEntity.PriceTier
+	}{
+		{name: "early bird", at: testNow.AddDate(0, 0, 7), want: money.Money{Amount: 250000, Currency: "TWD"}, tier: courseEntity.PriceTierEarlyBird},
+		{name: "after early bird", at: until, want: money.Money{Amount: 320000, Currency: "TWD"}, tier: courseEntity.PriceTierRegular},
+		{name: "after price change", at: testNow.AddDate(0, 2, 0), want: money.Money{Amount: 350000, Currency: "TWD"}, tier: courseEntity.PriceTierRegular},
+		{name: "before history", at: testNow.Add(-time.Hour), want: money.Money{Amount: 300000, Currency: "TWD"}, tier: courseEntity.PriceTierRegular},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := svc.Quote(ctx, 1, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, quote.Price)
+			assert.Equal(t, tt.tier, quote.Tier)
+		})
+	}
+
+	prices, err := svc.ListPrices(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, prices, 3)
+}
+
+// 設定退款規則測試
+// Test for CourseServiceImpl.SetRefundRules
+func TestSetRefundRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		courseID   uint
+		rules      []*courseEntity.CourseRefundRule
+		assertFunc func(t *testing.T, svc *CourseServiceImpl, err error)
+	}{
+		{
+			name:     "success",
+			courseID: 1,
+			rules: []*courseEntity.CourseRefundRule{
+				{Anchor: courseEntity.RefundAnchorRegistrationEnd, Percent: 100},
+				{Anchor: courseEntity.RefundAnchorStart, OffsetHours: -48, Percent: 30},
+			},
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				require.NoError(t, err)
+				rules, err := svc.ListRefundRules(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Len(t, rules, 2)
+			},
+		},
+		{
+			name:     "empty resets to default",
+			courseID: 1,
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				require.NoError(t, err)
+				rules, err := svc.ListRefundRules(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Empty(t, rules)
+			},
+		},
+		{
+			name:     "percent over 100",
+			courseID: 1,
+			rules:    []*courseEntity.CourseRefundRule{{Anchor: courseEntity.RefundAnchorStart, Percent: 120}},
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRefundRule)
+			},
+		},
+		{
+			name:     "unknown anchor",
+			courseID: 1,
+			rules:    []*courseEntity.CourseRefundRule{{Anchor: 9, Percent: 50}},
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRefundRule)
+			},
+		},
+		{
+			name:     "duplicate deadline",
+			courseID: 1,
+			rules: []*courseEntity.CourseRefundRule{
+				{Anchor: courseEntity.RefundAnchorStart, Percent: 50},
+				{Anchor: courseEntity.RefundAnchorStart, Percent: 30},
+			},
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRefundRule)
+			},
+		},
+		{
+			name:     "course not found",
+			courseID: 100,
+			rules:    []*courseEntity.CourseRefundRule{{Anchor: courseEntity.RefundAnchorStart, Percent: 50}},
+			assertFunc: func(t *testing.T, svc *CourseServiceImpl, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newPriceTestService(t)
+			_, err := svc.SetRefundRules(context.Background(), tt.courseID, tt.rules)
+			tt.assertFunc(t, svc, err)
+		})
+	}
+}
+
+// 退款比例測試 , 依截止時間排序取第一個未截止的規則
+// Test for courseEntity.QuoteRefund
+func TestQuoteRefund(t *testing.T) {
+	course := &courseEntity.Course{
+		Model:               gorm.Model{ID: 1},
+		RegistrationEndDate: testNow.AddDate(0, 0, 14),
+		StartDate:           testNow.AddDate(0, 0, 21),
+	}
+	custom := []*courseEntity.CourseRefundRule{
+		{Anchor: courseEntity.RefundAnchorStart, OffsetHours: -24, Percent: 30},
+		{Anchor: courseEntity.RefundAnchorRegistrationEnd, OffsetHours: 72, Percent: 80},
+	}
+
+	tests := []struct {
+		name  string
+		rules []*courseEntity.CourseRefundRule
+		at    time.Time
+		want  uint
+	}{
+		{name: "default before registration end", at: testNow, want: 100},
+		{name: "default before start", at: course.RegistrationEndDate, want: 50},
+		{name: "default after start", at: course.StartDate, want: 0},
+		{name: "custom first deadline", rules: custom, at: course.RegistrationEndDate, want: 80},
+		{name: "custom second deadline", rules: custom, at: course.RegistrationEndDate.Add(72 * time.Hour), want: 30},
+		{name: "custom after all deadlines", rules: custom, at: course.StartDate.Add(-time.Hour), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := courseEntity.QuoteRefund(course, tt.rules, tt.at)
+			assert.Equal(t, tt.want, quote.Percent)
+			if tt.want == 0 {
+				assert.Nil(t, quote.Deadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package course
+
+import (
+	"context"
+	"time"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+)
+
+// CourseService 定義課程相關的業務邏輯
+// 負責課程狀態變更、價格與退款規則設定等操作
+//
+// Example:
+//
+//	var svc CourseService
+//	err := svc.ChangeStatus(ctx, 1, courseEntity.CourseStatusOnline)
+type CourseService interface {
+	// ChangeStatus 變更課程狀態 , 須符合課程狀態轉換規則
+	// 參數: ctx - context, id - 課程ID, status - 新狀態
+	// 回傳: 錯誤訊息
+	ChangeStatus(ctx context.Context, id uint, status courseEntity.CourseStatus) error
+
+	// AddPrice 新增課程的價格紀錄 , 幣別須與課程相同 , 早鳥價須設定截止時間
+	// 生效時間未設定時為現在 , 不可早於現在 , 已生效的價格不會被變更
+	// 參數: ctx - context, price - 價格紀錄
+	// 回傳: 新增後的價格紀錄, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	AddPrice(ctx context.Context, price *courseEntity.CoursePrice) (*courseEntity.CoursePrice, error)
+
+	// ListPrices 查詢課程的價格歷史
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 價格紀錄 , 依生效時間排序, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	ListPrices(ctx context.Context, courseID uint) ([]*courseEntity.CoursePrice, error)
+
+	// Quote 計算課程於 at 的售價 , 規則同 courseEntity.QuotePrice
+	// 參數: ctx - context, courseID - 課程ID, at - 查詢時間
+	// 回傳: 售價, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	Quote(ctx context.Context, courseID uint, at time.Time) (*courseEntity.PriceQuote, error)
+
+	// ListRefundRules 查詢課程設定的退款規則 , 沒有設定時回傳空切片 , 退款時使用 courseEntity.DefaultRefundRules
+	// 參數: ctx - context, courseID - 課程ID
+	// 回傳: 退款規則, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	ListRefundRules(ctx context.Context, courseID uint) ([]*courseEntity.CourseRefundRule, error)
+
+	// SetRefundRules 以 rules 取代課程的退款規則 , 空切片表示使用預設規則
+	// 參數: ctx - context, courseID - 課程ID, rules - 退款規則
+	// 回傳: 新的退款規則, 錯誤訊息 , 規則無效時回傳 ErrInvalidRefundRule , 課程不存在時回傳 gorm.ErrRecordNotFound
+	SetRefundRules(ctx context.Context, courseID uint, rules []*courseEntity.CourseRefundRule) ([]*courseEntity.CourseRefundRule, error)
+}
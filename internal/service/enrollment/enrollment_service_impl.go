@@ -0,0 +1,320 @@
+package enrollment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ EnrollmentService = (*EnrollmentServiceImpl)(nil)
+
+var (
+	// ErrRegistrationClosed 課程未開放報名或不在報名期間內
+	ErrRegistrationClosed = errors.New("course registration closed")
+	// ErrCourseFull 課程報名人數已達上限
+	ErrCourseFull = errors.New("course is full")
+	// ErrAlreadyEnrolled 學生已報名該課程
+	ErrAlreadyEnrolled = errors.New("student already enrolled")
+	// ErrPaymentPending 學生已報名該課程 , 訂單尚未付款
+	ErrPaymentPending = errors.New("enrollment payment pending")
+	// ErrInvalidStatus 報名狀態不允許此操作
+	ErrInvalidStatus = errors.New("invalid enrollment status")
+	// ErrNotEnrollee 報名不屬於操作的學生
+	ErrNotEnrollee = errors.New("enrollment does not belong to student")
+)
+
+// Config 報名設定
+type Config struct {
+	OrderTTL time.Duration // 付費課程的付款期限 , 逾期未付款時釋出名額
+}
+
+// ConfigFromViper 從 viper 讀取報名設定
+func ConfigFromViper() Config {
+	viper.SetDefault("PAYMENT_ORDER_TTL", 30*time.Minute)
+
+	return Config{
+		OrderTTL: viper.GetDuration("PAYMENT_ORDER_TTL"),
+	}
+}
+
+// EnrollmentServiceImpl 實作 EnrollmentService 介面
+//
+// Example:
+//
+//	svc := enrollment.NewEnrollmentService(enrollmentRepo.NewEnrollmentRepository(db), courseRepo.NewCourseRepository(db), orderRepo.NewOrderRepository(db), promotionSvc, orderSvc, outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db), enrollment.ConfigFromViper())
+//	enrollment, err := svc.Enroll(ctx, 1, 10, "")
+type EnrollmentServiceImpl struct {
+	enrollmentRepo enrollmentRepo.EnrollmentRepository
+	courseRepo     courseRepo.CourseRepository
+	orderRepo      orderRepo.OrderRepository
+	promotionSvc   promotionService.PromotionService
+	orderSvc       orderService.OrderService
+	outboxRepo     outboxRepo.OutboxRepository
+	transactor     repo.Transactor
+	cfg            Config
+	now            func() time.Time
+}
+
+// NewEnrollmentService 建立報名業務邏輯實例
+// 參數: enrollmentRepo - 報名資料庫操作實例, courseRepo - 課程資料庫操作實例, orderRepo - 訂單資料庫操作實例, promotionSvc - 促銷活動業務邏輯, orderSvc - 訂單業務邏輯 , 退出課程時建立退款, outboxRepo - 領域事件 outbox, transactor - transaction, cfg - 設定
+// 回傳: 報名業務邏輯實例
+func NewEnrollmentService(enrollmentRepo enrollmentRepo.EnrollmentRepository, courseRepo courseRepo.CourseRepository, orderRepo orderRepo.OrderRepository, promotionSvc promotionService.PromotionService, orderSvc orderService.OrderService, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor, cfg Config) EnrollmentService {
+	return &EnrollmentServiceImpl{
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		orderRepo:      orderRepo,
+		promotionSvc:   promotionSvc,
+		orderSvc:       orderSvc,
+		outboxRepo:     outboxRepo,
+		transactor:     transactor,
+		cfg:            cfg,
+		now:            time.Now,
+	}
+}
+
+// Enroll 學生報名課程
+// 以 LockByID 鎖定課程後計算報名人數 , 避免併發報名超過 MaxStudents , MaxStudents 為 0 表示不限人數
+// 曾退出的學生再次報名時沿用原本的報名資料 , 報名後達到 MaxStudents 時另外寫入 CourseFull
+// 以報名時的課程售價 (courseEntity.QuotePrice) 判斷是否為付費課程 , 並以報名人數 (含本次) 計算適用的促銷
+// 付費課程報名後為待付款並以折扣後的金額建立訂單 , 付款成功後才確認報名並寫入 StudentEnrolled
+// 折扣後應付金額為 0 時訂單直接建立為已付款並確認報名 , 促銷的使用紀錄與訂單在同一個 transaction 寫入
+func (s *EnrollmentServiceImpl) Enroll(ctx context.Context, courseID, studentID uint, couponCode string) (enrollment *entity.Enrollment, err error) {
+	ctx, span := tracing.Start(ctx, "EnrollmentService.Enroll",
+		trace.WithAttributes(
+			attribute.Int("course.id", int(courseID)),
+			attribute.Int("student.id", int(studentID)),
+		))
+	defer tracing.End(span, &err)
+
+	now := s.now()
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		course, err := s.courseRepo.LockByID(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock course %d: %w", courseID, err)
+		}
+		if course.Status != courseEntity.CourseStatusOnline ||
+			now.Before(course.RegistrationStartDate) || now.After(course.RegistrationEndDate) {
+			return fmt.Errorf("course %d: %w", courseID, ErrRegistrationClosed)
+		}
+
+		existing, err := s.enrollmentRepo.GetByCourseAndStudent(ctx, courseID, studentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get enrollment of course %d student %d: %w", courseID, studentID, err)
+		}
+		if existing != nil {
+			switch existing.Status {
+			case entity.EnrollmentStatusEnrolled:
+				return fmt.Errorf("course %d student %d: %w", courseID, studentID, ErrAlreadyEnrolled)
+			case entity.EnrollmentStatusPendingPayment:
+				return fmt.Errorf("course %d student %d: %w", courseID, studentID, ErrPaymentPending)
+			}
+		}
+
+		prices, err := s.courseRepo.ListPrices(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to list prices of course %d: %w", courseID, err)
+		}
+		quote := courseEntity.QuotePrice(course, prices, now)
+
+		count, err := s.enrollmentRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{
+			entity.EnrollmentOfCourse(courseID),
+			entity.InEnrollmentStatus([]entity.EnrollmentStatus{entity.EnrollmentStatusEnrolled, entity.EnrollmentStatusPendingPayment}),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to count enrollments of course %d: %w", courseID, err)
+		}
+		if course.MaxStudents > 0 && count >= int64(course.MaxStudents) {
+			return fmt.Errorf("course %d: %w", courseID, ErrCourseFull)
+		}
+
+		pricing, err := s.promotionSvc.Price(ctx, promotionEntity.Checkout{
+			CourseID:  courseID,
+			Category:  course.Category,
+			StudentID: studentID,
+			Price:     quote.Price,
+			Seats:     uint(count) + 1,
+			At:        now,
+		}, couponCode)
+		if err != nil {
+			return err
+		}
+
+		status := entity.EnrollmentStatusEnrolled
+		if pricing.Total.Amount > 0 {
+			status = entity.EnrollmentStatusPendingPayment
+		}
+
+		if existing != nil {
+			rows, err := s.enrollmentRepo.UpdateStatus(ctx, existing.ID, entity.EnrollmentStatusWithdrawn, status)
+			if err != nil {
+				return fmt.Errorf("failed to re-enroll enrollment %d: %w", existing.ID, err)
+			}
+			if rows == 0 {
+				return fmt.Errorf("enrollment %d status changed concurrently: %w", existing.ID, ErrInvalidStatus)
+			}
+			enrollment = existing
+			enrollment.Status = status
+			enrollment.EnrolledAt = now
+			enrollment.WithdrawnAt = nil
+		} else {
+			enrollment = &entity.Enrollment{CourseID: courseID, StudentID: studentID, Status: status, EnrolledAt: now}
+			if _, err := s.enrollmentRepo.Create(ctx, enrollment); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return fmt.Errorf("course %d student %d: %w", courseID, studentID, ErrAlreadyEnrolled)
+				}
+				return fmt.Errorf("failed to create enrollment: %w", err)
+			}
+		}
+
+		var events []event.Event
+		if quote.Price.Amount > 0 {
+			order := &orderEntity.Order{
+				EnrollmentID:   enrollment.ID,
+				CourseID:       courseID,
+				StudentID:      studentID,
+				Amount:         uint(pricing.Total.Amount),
+				ListAmount:     uint(pricing.ListPrice.Amount),
+				DiscountAmount: uint(pricing.Discount().Amount),
+				Currency:       quote.Price.Currency,
+				PriceID:        quote.PriceID,
+				ExpiresAt:      now.Add(s.cfg.OrderTTL),
+			}
+			if status == entity.EnrollmentStatusEnrolled {
+				// 折扣後不需付款
+				order.Status = orderEntity.OrderStatusPaid
+				order.PaidAt = &now
+			}
+			if _, err := s.orderRepo.Create(ctx, order); err != nil {
+				return fmt.Errorf("failed to create order of enrollment %d: %w", enrollment.ID, err)
+			}
+			if err := s.promotionSvc.Redeem(ctx, pricing, order.ID, studentID); err != nil {
+				return err
+			}
+			if order.Status == orderEntity.OrderStatusPaid {
+				events = append(events, event.OrderPaid{
+					OrderID:      order.ID,
+					EnrollmentID: enrollment.ID,
+					CourseID:     courseID,
+					StudentID:    studentID,
+					Amount:       order.Amount,
+					Currency:     order.Currency,
+					PaidAt:       now,
+				})
+			}
+		}
+		if status == entity.EnrollmentStatusEnrolled {
+			events = append(events, event.StudentEnrolled{
+				EnrollmentID: enrollment.ID,
+				CourseID:     courseID,
+				StudentID:    studentID,
+				EnrolledAt:   now,
+			})
+		}
+		if course.MaxStudents > 0 && count+1 == int64(course.MaxStudents) {
+			events = append(events, event.CourseFull{CourseID: courseID, MaxStudents: course.MaxStudents, FullAt: now})
+		}
+		if err := s.outboxRepo.Add(ctx, events...); err != nil {
+			return fmt.Errorf("failed to add enrollment %d event: %w", enrollment.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment.Status == entity.EnrollmentStatusEnrolled {
+		metrics.EnrollmentsTotal.Inc()
+	}
+	zerolog.Ctx(ctx).Info().
+		Uint("enrollment_id", enrollment.ID).
+		Uint("course_id", courseID).
+		Uint("student_id", studentID).
+		Stringer("status", enrollment.Status).
+		Msg("student enrolled")
+
+	return enrollment, nil
+}
+
+// Withdraw 學生退出課程
+// 以目前狀態為條件更新避免併發覆蓋 , 狀態變更、退款紀錄與 StudentWithdrawn 事件在同一個 transaction 寫入
+// 已付款的報名依課程的退款規則建立退款紀錄 , 由 OrderService.ProcessRefunds 向金流商退款
+func (s *EnrollmentServiceImpl) Withdraw(ctx context.Context, id, studentID uint) (err error) {
+	ctx, span := tracing.Start(ctx, "EnrollmentService.Withdraw",
+		trace.WithAttributes(
+			attribute.Int("enrollment.id", int(id)),
+			attribute.Int("student.id", int(studentID)),
+		))
+	defer tracing.End(span, &err)
+
+	var enrollment *entity.Enrollment
+	var refund *orderEntity.Refund
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		enrollment, err = s.enrollmentRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get enrollment %d: %w", id, err)
+		}
+		if enrollment.StudentID != studentID {
+			return fmt.Errorf("enrollment %d student %d: %w", id, studentID, ErrNotEnrollee)
+		}
+
+		rows, err := s.enrollmentRepo.UpdateStatus(ctx, id, entity.EnrollmentStatusEnrolled, entity.EnrollmentStatusWithdrawn)
+		if err != nil {
+			return fmt.Errorf("failed to withdraw enrollment %d: %w", id, err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("enrollment %d is %s: %w", id, enrollment.Status, ErrInvalidStatus)
+		}
+
+		now := s.now()
+		refund, err = s.orderSvc.RefundWithdrawal(ctx, id, now)
+		if err != nil {
+			return err
+		}
+
+		if err := s.outboxRepo.Add(ctx, event.StudentWithdrawn{
+			EnrollmentID: id,
+			CourseID:     enrollment.CourseID,
+			StudentID:    enrollment.StudentID,
+			WithdrawnAt:  now,
+		}); err != nil {
+			return fmt.Errorf("failed to add enrollment %d event: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := zerolog.Ctx(ctx).Info().
+		Uint("enrollment_id", id).
+		Uint("course_id", enrollment.CourseID).
+		Uint("student_id", enrollment.StudentID)
+	if refund != nil {
+		logger = logger.Uint("refund_id", refund.ID).Uint("refund_amount", refund.Amount)
+	}
+	logger.Msg("student withdrawn")
+
+	return nil
+}
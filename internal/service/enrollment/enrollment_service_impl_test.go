@@ -0,0 +1,431 @@
+package enrollment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	outboxEntity "github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+)
+
+var testNow = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+// newTestService 建立使用記憶體 repository 的報名業務邏輯
+// 課程 1 開放報名且上限 2 人 , 課程 2 為草稿 , 課程 3 報名期間已結束 , 課程 4 為付費課程且上限 1 人
+func newTestService(t *testing.T) (*EnrollmentServiceImpl, outboxRepo.OutboxRepository) {
+	t.Helper()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", MaxStudents: 2, Status: courseEntity.CourseStatusOnline, RegistrationStartDate: testNow.AddDate(0, 0, -7), RegistrationEndDate: testNow.AddDate(0, 0, 7)},
+		{Name: "Go 進階", MaxStudents: 2, Status: courseEntity.CourseStatusDraft, RegistrationStartDate: testNow.AddDate(0, 0, -7), RegistrationEndDate: testNow.AddDate(0, 0, 7)},
+		{Name: "Python 入門", MaxStudents: 2, Status: courseEntity.CourseStatusOnline, RegistrationStartDate: testNow.AddDate(0, 0, -14), RegistrationEndDate: testNow.AddDate(0, 0, -7)},
+		{Name: "資料庫設計", Price: 3000, Currency: "TWD", MaxStudents: 1, Status: courseEntity.CourseStatusOnline, RegistrationStartDate: testNow.AddDate(0, 0, -7), RegistrationEndDate: testNow.AddDate(0, 0, 7)},
+	} {
+		_, err := courses.Create(context.Background(), course)
+		require.NoError(t, err)
+	}
+
+	outbox := outboxRepo.NewOutboxMemoryRepository()
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	orders := orderRepo.NewOrderMemoryRepository()
+	promotions := promotionService.NewPromotionService(promotionRepo.NewPromotionMemoryRepository(), courses, enrollments)
+	orderSvc := orderService.NewOrderService(orders, enrollments, courses, promotions, outbox, repository.NoTransaction, orderService.Config{})
+	svc := NewEnrollmentService(enrollments, courses, orders, promotions, orderSvc, outbox, repository.NoTransaction,
+		Config{OrderTTL: 30 * time.Minute}).(*EnrollmentServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return svc, outbox
+}
+
+func pendingEvents(t *testing.T, outbox outboxRepo.OutboxRepository) []*outboxEntity.OutboxEvent {
+	t.Helper()
+
+	events, err := outbox.ListPending(context.Background(), time.Now().Add(time.Second), 100)
+	require.NoError(t, err)
+	return events
+}
+
+// 學生報名課程測試
+// Test for EnrollmentServiceImpl.Enroll
+func TestEnroll(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, svc *EnrollmentServiceImpl)
+		courseID   uint
+		studentID  uint
+		assertFunc func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error)
+	}{
+		{
+			name:      "course not found",
+			courseID:  100,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name:      "course not online",
+			courseID:  2,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrRegistrationClosed)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name:      "registration ended",
+			courseID:  3,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrRegistrationClosed)
+			},
+		},
+		{
+			name: "already enrolled",
+			setup: func(t *testing.T, svc *EnrollmentServiceImpl) {
+				_, err := svc.Enroll(context.Background(), 1, 10, "")
+				require.NoError(t, err)
+			},
+			courseID:  1,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrAlreadyEnrolled)
+				assert.Len(t, events, 1)
+			},
+		},
+		{
+			name: "course full",
+			setup: func(t *testing.T, svc *EnrollmentServiceImpl) {
+				for _, studentID := range []uint{10, 11} {
+					_, err := svc.Enroll(context.Background(), 1, studentID, "")
+					require.NoError(t, err)
+				}
+			},
+			courseID:  1,
+			studentID: 12,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrCourseFull)
+				if assert.Len(t, events, 3) {
+					assert.Equal(t, event.TypeCourseFull, events[2].EventType)
+					assert.Equal(t, "course", events[2].AggregateType)
+				}
+			},
+		},
+		{
+			name: "re-enroll after withdraw",
+			setup: func(t *testing.T, svc *EnrollmentServiceImpl) {
+				enrollment, err := svc.Enroll(context.Background(), 1, 10, "")
+				require.NoError(t, err)
+				require.NoError(t, svc.Withdraw(context.Background(), enrollment.ID, 10))
+			},
+			courseID:  1,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 1, enrollment.ID)
+				assert.Equal(t, entity.EnrollmentStatusEnrolled, enrollment.Status)
+				assert.Nil(t, enrollment.WithdrawnAt)
+				if assert.Len(t, events, 3) {
+					assert.Equal(t, event.TypeStudentWithdrawn, events[1].EventType)
+					assert.Equal(t, event.TypeStudentEnrolled, events[2].EventType)
+				}
+			},
+		},
+		{
+			name:      "paid course pending payment",
+			courseID:  4,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.EnrollmentStatusPendingPayment, enrollment.Status)
+				// 名額已滿 , 付款前不寫入 StudentEnrolled
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, event.TypeCourseFull, events[0].EventType)
+				}
+			},
+		},
+		{
+			name: "paid course payment pending",
+			setup: func(t *testing.T, svc *EnrollmentServiceImpl) {
+				_, err := svc.Enroll(context.Background(), 4, 10, "")
+				require.NoError(t, err)
+			},
+			courseID:  4,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrPaymentPending)
+			},
+		},
+		{
+			name: "pending payment holds seat",
+			setup: func(t *testing.T, svc *EnrollmentServiceImpl) {
+				_, err := svc.Enroll(context.Background(), 4, 10, "")
+				require.NoError(t, err)
+			},
+			courseID:  4,
+			studentID: 11,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrCourseFull)
+			},
+		},
+		{
+			name:      "success",
+			courseID:  1,
+			studentID: 10,
+			assertFunc: func(t *testing.T, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.NotZero(t, enrollment.ID)
+				assert.True(t, enrollment.EnrolledAt.Equal(testNow))
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, event.TypeStudentEnrolled, events[0].EventType)
+					assert.Equal(t, "enrollment", events[0].AggregateType)
+					assert.Equal(t, enrollment.ID, events[0].AggregateID)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, outbox := newTestService(t)
+			if test.setup != nil {
+				test.setup(t, svc)
+			}
+
+			enrollment, err := svc.Enroll(context.Background(), test.courseID, test.studentID, "")
+			test.assertFunc(t, enrollment, pendingEvents(t, outbox), err)
+		})
+	}
+}
+
+// 學生退出課程測試
+// Test for EnrollmentServiceImpl.Withdraw
+func TestWithdraw(t *testing.T) {
+	svc, outbox := newTestService(t)
+
+	enrollment, err := svc.Enroll(context.Background(), 1, 10, "")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.Withdraw(context.Background(), 100, 10), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, svc.Withdraw(context.Background(), enrollment.ID, 11), ErrNotEnrollee)
+	assert.NoError(t, svc.Withdraw(context.Background(), enrollment.ID, 10))
+	assert.ErrorIs(t, svc.Withdraw(context.Background(), enrollment.ID, 10), ErrInvalidStatus)
+
+	events := pendingEvents(t, outbox)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, event.TypeStudentWithdrawn, events[1].EventType)
+		assert.Contains(t, events[1].Payload, `"student_id":10`)
+	}
+}
+
+// 付費課程報名時建立訂單
+func TestEnrollCreatesOrder(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	enrollment, err := svc.Enroll(context.Background(), 4, 10, "")
+	require.NoError(t, err)
+
+	orders, err := svc.orderRepo.Find(context.Background(),
+		&repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{orderEntity.OrderOfEnrollment(enrollment.ID)},
+	)
+	require.NoError(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, orderEntity.OrderStatusPending, orders[0].Status)
+		assert.EqualValues(t, 4, orders[0].CourseID)
+		assert.EqualValues(t, 10, orders[0].StudentID)
+		assert.EqualValues(t, 3000, orders[0].Amount)
+		assert.Equal(t, "TWD", orders[0].Currency)
+		assert.True(t, orders[0].ExpiresAt.Equal(testNow.Add(30*time.Minute)))
+	}
+
+	// 待付款不可退出 , 由付款失敗或逾期釋出名額
+	assert.ErrorIs(t, svc.Withdraw(context.Background(), enrollment.ID, 10), ErrInvalidStatus)
+}
+
+// 已付款的報名退出課程時依退款規則建立退款紀錄
+func TestWithdrawCreatesRefund(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	enrollment, err := svc.Enroll(ctx, 4, 10, "")
+	require.NoError(t, err)
+	_, err = svc.orderRepo.UpdateStatus(ctx, 1, orderEntity.OrderStatusPending, orderEntity.OrderStatusPaid, testNow, "")
+	require.NoError(t, err)
+	_, err = svc.enrollmentRepo.UpdateStatus(ctx, enrollment.ID, entity.EnrollmentStatusPendingPayment, entity.EnrollmentStatusEnrolled)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Withdraw(ctx, enrollment.ID, 10))
+
+	refunds, err := svc.orderRepo.FindRefunds(ctx, []func(db *gorm.DB) *gorm.DB{orderEntity.RefundOfOrder(1)})
+	require.NoError(t, err)
+	if assert.Len(t, refunds, 1) {
+		assert.EqualValues(t, 3000, refunds[0].Amount)
+		assert.EqualValues(t, 100, refunds[0].Percent)
+		assert.Equal(t, orderEntity.RefundReasonWithdrawal, refunds[0].Reason)
+		assert.Equal(t, orderEntity.RefundStatusPending, refunds[0].Status)
+	}
+
+	// 免費課程沒有退款紀錄
+	enrollment, err = svc.Enroll(ctx, 1, 10, "")
+	require.NoError(t, err)
+	require.NoError(t, svc.Withdraw(ctx, enrollment.ID, 10))
+	refunds, err = svc.orderRepo.FindRefunds(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, refunds, 1)
+}
+
+// 付費課程以報名時的售價建立訂單 , 早鳥價於截止前優先
+func TestEnrollUsesQuotedPrice(t *testing.T) {
+	svc, _ := newTestService(t)
+	until := testNow.AddDate(0, 0, 1)
+	for _, price := range []*courseEntity.CoursePrice{
+		{CourseID: 4, Tier: courseEntity.PriceTierRegular, Amount: 3500, Currency: "TWD", EffectiveFrom: testNow.AddDate(0, 0, -30)},
+		{CourseID: 4, Tier: courseEntity.PriceTierEarlyBird, Amount: 2500, Currency: "TWD", EffectiveFrom: testNow.AddDate(0, 0, -30), ValidUntil: &until},
+		{CourseID: 1, Tier: courseEntity.PriceTierRegular, Amount: 1000, Currency: "TWD", EffectiveFrom: testNow.AddDate(0, 0, 1)},
+	} {
+		_, err := svc.courseRepo.CreatePrice(context.Background(), price)
+		require.NoError(t, err)
+	}
+
+	enrollment, err := svc.Enroll(context.Background(), 4, 10, "")
+	require.NoError(t, err)
+	orders, err := svc.orderRepo.Find(context.Background(),
+		&repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{orderEntity.OrderOfEnrollment(enrollment.ID)},
+	)
+	require.NoError(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.EqualValues(t, 2500, orders[0].Amount)
+		assert.EqualValues(t, 2, orders[0].PriceID)
+	}
+
+	// 課程 1 的調價尚未生效 , 仍為免費課程
+	enrollment, err = svc.Enroll(context.Background(), 1, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, entity.EnrollmentStatusEnrolled, enrollment.Status)
+}
+
+// 付費課程報名時套用優惠券 , 訂單記錄折扣並累計使用次數 , 折扣後為 0 時直接確認報名
+func TestEnrollWithCoupon(t *testing.T) {
+	tests := []struct {
+		name       string
+		promotion  *promotionEntity.Promotion
+		code       string
+		assertFunc func(t *testing.T, svc *EnrollmentServiceImpl, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error)
+	}{
+		{
+			name:      "percent",
+			promotion: &promotionEntity.Promotion{Name: "九折", Code: ptr("SAVE10"), DiscountValue: 10, UsageLimit: 5},
+			code:      "save10",
+			assertFunc: func(t *testing.T, svc *EnrollmentServiceImpl, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, entity.EnrollmentStatusPendingPayment, enrollment.Status)
+				order := enrollmentOrder(t, svc, enrollment.ID)
+				assert.Equal(t, orderEntity.OrderStatusPending, order.Status)
+				assert.EqualValues(t, 2700, order.Amount)
+				assert.EqualValues(t, 3000, order.ListAmount)
+				assert.EqualValues(t, 300, order.DiscountAmount)
+
+				promotion, err := svc.promotionSvc.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, promotion.UsedCount)
+			},
+		},
+		{
+			name:      "free after discount",
+			promotion: &promotionEntity.Promotion{Name: "全額折抵", Code: ptr("FREE"), DiscountType: promotionEntity.DiscountTypeFixed, DiscountValue: 5000, Currency: "TWD"},
+			code:      "FREE",
+			assertFunc: func(t *testing.T, svc *EnrollmentServiceImpl, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, entity.EnrollmentStatusEnrolled, enrollment.Status)
+				order := enrollmentOrder(t, svc, enrollment.ID)
+				assert.Equal(t, orderEntity.OrderStatusPaid, order.Status)
+				assert.NotNil(t, order.PaidAt)
+				assert.Zero(t, order.Amount)
+				assert.EqualValues(t, 3000, order.DiscountAmount)
+				if assert.Len(t, events, 3) {
+					assert.Equal(t, event.TypeOrderPaid, events[0].EventType)
+					assert.Equal(t, event.TypeStudentEnrolled, events[1].EventType)
+					assert.Equal(t, event.TypeCourseFull, events[2].EventType)
+				}
+			},
+		},
+		{
+			name: "coupon not found",
+			code: "UNKNOWN",
+			assertFunc: func(t *testing.T, svc *EnrollmentServiceImpl, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, promotionService.ErrCouponNotFound)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name:      "coupon not applicable",
+			promotion: &promotionEntity.Promotion{Name: "設計類九折", Code: ptr("DESIGN"), DiscountValue: 10, Category: "design"},
+			code:      "DESIGN",
+			assertFunc: func(t *testing.T, svc *EnrollmentServiceImpl, enrollment *entity.Enrollment, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, promotionService.ErrCouponNotApplicable)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, outbox := newTestService(t)
+			if test.promotion != nil {
+				_, err := svc.promotionSvc.Create(context.Background(), test.promotion)
+				require.NoError(t, err)
+			}
+
+			enrollment, err := svc.Enroll(context.Background(), 4, 10, test.code)
+			test.assertFunc(t, svc, enrollment, pendingEvents(t, outbox), err)
+		})
+	}
+}
+
+func enrollmentOrder(t *testing.T, svc *EnrollmentServiceImpl, enrollmentID uint) *orderEntity.Order {
+	t.Helper()
+
+	orders, err := svc.orderRepo.Find(context.Background(),
+		&repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{orderEntity.OrderOfEnrollment(enrollmentID)},
+	)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	return orders[0]
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// 報名成功時累計報名數量
+func TestEnrollMetrics(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	before := testutil.ToFloat64(metrics.EnrollmentsTotal)
+	_, err := svc.Enroll(context.Background(), 1, 10, "")
+	require.NoError(t, err)
+	_, err = svc.Enroll(context.Background(), 1, 10, "")
+	require.Error(t, err)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.EnrollmentsTotal))
+}
@@ -0,0 +1,26 @@
+package enrollment
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+)
+
+// EnrollmentService 定義報名相關的業務邏輯
+// 負責學生報名與退出課程
+//
+// Example:
+//
+//	var svc EnrollmentService
+//	enrollment, err := svc.Enroll(ctx, 1, 10, "SUMMER10")
+type EnrollmentService interface {
+	// Enroll 學生報名課程 , 課程須為開放報名且在報名期間內 , 且未額滿
+	// 參數: ctx - context, courseID - 課程ID, studentID - 學生 user ID, couponCode - 優惠碼 , 空字串表示不使用優惠券
+	// 回傳: 報名實體, 錯誤訊息 , 優惠券無法使用時回傳 promotion service 的錯誤 , 例如 promotion.ErrCouponNotApplicable
+	Enroll(ctx context.Context, courseID, studentID uint, couponCode string) (*entity.Enrollment, error)
+
+	// Withdraw 學生退出課程 , 報名狀態須為已報名 , 已付款時依課程的退款規則建立退款紀錄
+	// 參數: ctx - context, id - 報名ID, studentID - 操作的學生 user ID
+	// 回傳: 錯誤訊息 , 報名不屬於該學生時回傳 ErrNotEnrollee
+	Withdraw(ctx context.Context, id, studentID uint) error
+}
@@ -0,0 +1,317 @@
+package invoice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	invoiceRepo "github.com/itmrchow/course-management-system/internal/repository/invoice"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ InvoiceService = (*InvoiceServiceImpl)(nil)
+
+// ErrOrderNotPaid 訂單未付款 , 無法開立發票
+var ErrOrderNotPaid = errors.New("order not paid")
+
+// Config 發票設定
+type Config struct {
+	Prefix   string         // 發票號碼前綴
+	Issuer   invoice.Issuer // 開立單位
+	Location *time.Location // 決定開立年度與顯示日期的時區
+}
+
+// ConfigFromViper 從 viper 讀取發票設定
+// 回傳: 設定, 錯誤訊息 , 時區不存在時回傳錯誤
+func ConfigFromViper() (Config, error) {
+	viper.SetDefault("INVOICE_NUMBER_PREFIX", "INV")
+	viper.SetDefault("INVOICE_TIMEZONE", "Asia/Taipei")
+
+	loc, err := time.LoadLocation(viper.GetString("INVOICE_TIMEZONE"))
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load invoice timezone: %w", err)
+	}
+	return Config{
+		Prefix: viper.GetString("INVOICE_NUMBER_PREFIX"),
+		Issuer: invoice.Issuer{
+			Name:    viper.GetString("INVOICE_ISSUER_NAME"),
+			TaxID:   viper.GetString("INVOICE_ISSUER_TAX_ID"),
+			Address: viper.GetString("INVOICE_ISSUER_ADDRESS"),
+			Email:   viper.GetString("INVOICE_ISSUER_EMAIL"),
+		},
+		Location: loc,
+	}, nil
+}
+
+// InvoiceServiceImpl 實作 InvoiceService 介面
+//
+// Example:
+//
+//	cfg, err := invoiceService.ConfigFromViper()
+//	renderer, err := invoice.NewRenderer(cfg.Location)
+//	svc := invoiceService.NewInvoiceService(invoiceRepo.NewInvoiceRepository(db), orderRepo.NewOrderRepository(db), courseRepo.NewCourseRepository(db), promotionRepo.NewPromotionRepository(db), outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db), renderer, cfg)
+//	inv, err := svc.Issue(ctx, orderID)
+type InvoiceServiceImpl struct {
+	invoiceRepo   invoiceRepo.InvoiceRepository
+	orderRepo     orderRepo.OrderRepository
+	courseRepo    courseRepo.CourseRepository
+	promotionRepo promotionRepo.PromotionRepository
+	outboxRepo    outboxRepo.OutboxRepository
+	transactor    repo.Transactor
+	renderer      *invoice.Renderer
+	cfg           Config
+	now           func() time.Time
+}
+
+// NewInvoiceService 建立發票業務邏輯實例
+// 參數: invoiceRepo - 發票資料庫操作實例, orderRepo - 訂單資料庫操作實例, courseRepo - 課程資料庫操作實例, promotionRepo - 促銷活動資料庫操作實例, outboxRepo - 領域事件 outbox, transactor - transaction, renderer - 發票輸出, cfg - 設定
+// 回傳: 發票業務邏輯實例
+func NewInvoiceService(invoiceRepo invoiceRepo.InvoiceRepository, orderRepo orderRepo.OrderRepository, courseRepo courseRepo.CourseRepository, promotionRepo promotionRepo.PromotionRepository, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor, renderer *invoice.Renderer, cfg Config) InvoiceService {
+	return &InvoiceServiceImpl{
+		invoiceRepo:   invoiceRepo,
+		orderRepo:     orderRepo,
+		courseRepo:    courseRepo,
+		promotionRepo: promotionRepo,
+		outboxRepo:    outboxRepo,
+		transactor:    transactor,
+		renderer:      renderer,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// Issue 為已付款的訂單開立發票
+// 明細於 transaction 外準備 , 取號、新增發票與事件在同一個 transaction 中 , 失敗時流水號一併退回 , 不會跳號
+// 併發開立同一筆訂單時 , 後寫入的因訂單唯一索引失敗 , 回傳先開立的發票
+func (s *InvoiceServiceImpl) Issue(ctx context.Context, orderID uint) (inv *entity.Invoice, err error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.Issue",
+		trace.WithAttributes(attribute.Int("order.id", int(orderID))))
+	defer tracing.End(span, &err)
+
+	if existing, err := s.invoiceRepo.GetByOrder(ctx, orderID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get invoice of order %d: %w", orderID, err)
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+	// 已付款後退款的訂單仍須開立付款時的發票
+	if order.Status != orderEntity.OrderStatusPaid && order.Status != orderEntity.OrderStatusRefunded {
+		return nil, fmt.Errorf("order %d is %s: %w", orderID, order.Status, ErrOrderNotPaid)
+	}
+
+	lines, err := s.lines(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	paidAt := now
+	if order.PaidAt != nil {
+		paidAt = *order.PaidAt
+	}
+	inv = &entity.Invoice{
+		Year:         now.In(s.cfg.Location).Year(),
+		OrderID:      order.ID,
+		EnrollmentID: order.EnrollmentID,
+		CourseID:     order.CourseID,
+		StudentID:    order.StudentID,
+		Currency:     order.Currency,
+		Discount:     order.DiscountAmount,
+		Total:        order.Amount,
+		PaymentID:    order.PaymentID,
+		PaidAt:       paidAt,
+		IssuedAt:     now,
+	}
+	inv.Subtotal = inv.Total + inv.Discount
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		seq, err := s.invoiceRepo.NextNumber(ctx, inv.Year)
+		if err != nil {
+			return fmt.Errorf("failed to get next invoice number of %d: %w", inv.Year, err)
+		}
+		inv.Seq = seq
+		inv.Number = entity.FormatNumber(s.cfg.Prefix, inv.Year, seq)
+
+		if _, err := s.invoiceRepo.Create(ctx, inv, lines); err != nil {
+			return fmt.Errorf("failed to create invoice of order %d: %w", orderID, err)
+		}
+		return s.outboxRepo.Add(ctx, event.InvoiceIssued{
+			InvoiceID: inv.ID,
+			Number:    inv.Number,
+			OrderID:   inv.OrderID,
+			CourseID:  inv.CourseID,
+			StudentID: inv.StudentID,
+			Total:     inv.Total,
+			Currency:  inv.Currency,
+			IssuedAt:  inv.IssuedAt,
+		})
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		existing, getErr := s.invoiceRepo.GetByOrder(ctx, orderID)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get invoice of order %d: %w", orderID, getErr)
+		}
+		return existing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// lines 依訂單建立發票明細 : 課程一筆 , 每個套用的促銷活動各一筆折扣
+// 訂單的促銷使用紀錄與折扣金額不符時 (例如已釋出) , 以一筆不具名的折扣明細表示訂單的折扣金額
+func (s *InvoiceServiceImpl) lines(ctx context.Context, order *orderEntity.Order) ([]*entity.InvoiceLine, error) {
+	course, err := s.courseRepo.GetByID(ctx, order.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", order.CourseID, err)
+	}
+	tier := courseEntity.PriceTierRegular
+	if order.PriceID != 0 {
+		prices, err := s.courseRepo.ListPrices(ctx, order.CourseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prices of course %d: %w", order.CourseID, err)
+		}
+		for _, price := range prices {
+			if price.ID == order.PriceID {
+				tier = price.Tier
+				break
+			}
+		}
+	}
+
+	subtotal := order.Amount + order.DiscountAmount
+	lines := []*entity.InvoiceLine{{
+		Position:    1,
+		Kind:        entity.InvoiceLineKindCourse,
+		Description: course.Name,
+		Detail:      tier.String(),
+		Quantity:    1,
+		UnitAmount:  subtotal,
+		Amount:      subtotal,
+		ReferenceID: order.PriceID,
+	}}
+	if order.DiscountAmount == 0 {
+		return lines, nil
+	}
+
+	redemptions, err := s.promotionRepo.FindRedemptions(ctx, []func(db *gorm.DB) *gorm.DB{promotionEntity.RedemptionOfOrder(order.ID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemptions of order %d: %w", order.ID, err)
+	}
+	var discounts []*entity.InvoiceLine
+	var total uint
+	for _, redemption := range redemptions {
+		promotion, err := s.promotionRepo.GetByID(ctx, redemption.PromotionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get promotion %d: %w", redemption.PromotionID, err)
+		}
+		discounts = append(discounts, &entity.InvoiceLine{
+			Position:    uint(len(lines) + len(discounts) + 1),
+			Kind:        entity.InvoiceLineKindDiscount,
+			Description: promotion.Name,
+			Detail:      promotion.CodeString(),
+			Quantity:    1,
+			UnitAmount:  redemption.Discount,
+			Amount:      redemption.Discount,
+			ReferenceID: promotion.ID,
+		})
+		total += redemption.Discount
+	}
+	if total != order.DiscountAmount {
+		discounts = []*entity.InvoiceLine{{
+			Position:   2,
+			Kind:       entity.InvoiceLineKindDiscount,
+			Quantity:   1,
+			UnitAmount: order.DiscountAmount,
+			Amount:     order.DiscountAmount,
+		}}
+	}
+	return append(lines, discounts...), nil
+}
+
+// Get 依發票ID查詢發票與明細
+func (s *InvoiceServiceImpl) Get(ctx context.Context, id uint) (*entity.Invoice, []*entity.InvoiceLine, error) {
+	inv, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get invoice %d: %w", id, err)
+	}
+	lines, err := s.invoiceRepo.ListLines(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list lines of invoice %d: %w", id, err)
+	}
+	return inv, lines, nil
+}
+
+// GetByOrder 查詢訂單的發票
+func (s *InvoiceServiceImpl) GetByOrder(ctx context.Context, orderID uint) (*entity.Invoice, error) {
+	inv, err := s.invoiceRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice of order %d: %w", orderID, err)
+	}
+	return inv, nil
+}
+
+// List 查詢發票清單
+func (s *InvoiceServiceImpl) List(ctx context.Context, pageInfo *repo.RepoPageInfo, year int, studentID uint) ([]*entity.Invoice, error) {
+	var conditions []func(db *gorm.DB) *gorm.DB
+	if year != 0 {
+		conditions = append(conditions, entity.InvoiceOfYear(year))
+	}
+	if studentID != 0 {
+		conditions = append(conditions, entity.InvoiceOfStudent(studentID))
+	}
+	invoices, err := s.invoiceRepo.Find(ctx, pageInfo, conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// Render 依語系版面輸出發票
+func (s *InvoiceServiceImpl) Render(ctx context.Context, id uint, format invoice.Format, locale string) ([]byte, error) {
+	inv, lines, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderer.Render(invoice.NewDocument(s.cfg.Issuer, inv, lines), format, locale)
+}
+
+// HandleEvent 處理訂單付款成功事件 , 開立發票
+func (s *InvoiceServiceImpl) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	switch msg.Type {
+	case event.TypeOrderPaid:
+		var e event.OrderPaid
+		if err := json.Unmarshal(msg.Payload, &e); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", msg.Type, err)
+		}
+		_, err := s.Issue(ctx, e.OrderID)
+		return err
+
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,276 @@
+package invoice
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	orderEntity "github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	invoiceRepo "github.com/itmrchow/course-management-system/internal/repository/invoice"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+)
+
+// testNow 台北時間 2026/01/01 01:00 , UTC 仍為 2025 年
+var testNow = time.Date(2025, 12, 31, 17, 0, 0, 0, time.UTC)
+
+type testEnv struct {
+	svc        *InvoiceServiceImpl
+	orders     orderRepo.OrderRepository
+	promotions promotionRepo.PromotionRepository
+	outbox     outboxRepo.OutboxRepository
+}
+
+// newTestEnv 建立使用記憶體 repository 的發票業務邏輯
+// 課程 1 售價 3000 , 有一筆早鳥價 (價格紀錄 1)
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Price: 3000, Currency: "TWD"})
+	require.NoError(t, err)
+	_, err = courses.CreatePrice(ctx, &courseEntity.CoursePrice{CourseID: 1, Tier: courseEntity.PriceTierEarlyBird, Amount: 2500, Currency: "TWD", EffectiveFrom: testNow.AddDate(0, -1, 0)})
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	renderer, err := invoice.NewRenderer(loc)
+	require.NoError(t, err)
+
+	orders := orderRepo.NewOrderMemoryRepository()
+	promotions := promotionRepo.NewPromotionMemoryRepository()
+	outbox := outboxRepo.NewOutboxMemoryRepository()
+	svc := NewInvoiceService(invoiceRepo.NewInvoiceMemoryRepository(), orders, courses, promotions, outbox, repository.NoTransaction, renderer, Config{
+		Prefix:   "INV",
+		Issuer:   invoice.Issuer{Name: "課程管理股份有限公司", TaxID: "12345678"},
+		Location: loc,
+	}).(*InvoiceServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return &testEnv{svc: svc, orders: orders, promotions: promotions, outbox: outbox}
+}
+
+// order 建立學生 10 報名課程 1 的訂單 , status 不是待付款時於 testNow 前一小時付款
+func (e *testEnv) order(t *testing.T, o orderEntity.Order) *orderEntity.Order {
+	t.Helper()
+
+	o.EnrollmentID, o.CourseID, o.StudentID, o.Currency = 1, 1, 10, "TWD"
+	if o.Status != orderEntity.OrderStatusPending {
+		paidAt := testNow.Add(-time.Hour)
+		o.PaidAt = &paidAt
+		o.PaymentID = "pay_1"
+	}
+	_, err := e.orders.Create(context.Background(), &o)
+	require.NoError(t, err)
+	return &o
+}
+
+// redeem 建立促銷活動與訂單的使用紀錄
+func (e *testEnv) redeem(t *testing.T, orderID uint, name, code string, discount uint) {
+	t.Helper()
+	ctx := context.Background()
+
+	p := &promotionEntity.Promotion{Name: name, DiscountType: promotionEntity.DiscountTypeFixed, DiscountValue: discount, Currency: "TWD"}
+	if code != "" {
+		p.Code = &code
+	}
+	promotionID, err := e.promotions.Create(ctx, p)
+	require.NoError(t, err)
+	_, err = e.promotions.CreateRedemption(ctx, &promotionEntity.PromotionRedemption{PromotionID: promotionID, StudentID: 10, OrderID: orderID, Discount: discount, Currency: "TWD"})
+	require.NoError(t, err)
+}
+
+func TestIssue(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, e *testEnv) uint
+		assertFunc func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error)
+	}{
+		{
+			name: "一般價格 , 沒有折扣",
+			setup: func(t *testing.T, e *testEnv) uint {
+				return e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusPaid}).ID
+			},
+			assertFunc: func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "INV-2026-000001", inv.Number)
+				assert.Equal(t, 2026, inv.Year)
+				assert.EqualValues(t, 3000, inv.Subtotal)
+				assert.EqualValues(t, 0, inv.Discount)
+				assert.EqualValues(t, 3000, inv.Total)
+				assert.Equal(t, "pay_1", inv.PaymentID)
+				assert.True(t, inv.PaidAt.Equal(testNow.Add(-time.Hour)))
+				require.Len(t, lines, 1)
+				assert.Equal(t, "資料庫設計", lines[0].Description)
+				assert.Equal(t, "regular", lines[0].Detail)
+				assert.EqualValues(t, 3000, lines[0].Amount)
+			},
+		},
+		{
+			name: "早鳥價與優惠券 , 每個促銷活動各一筆折扣",
+			setup: func(t *testing.T, e *testEnv) uint {
+				o := e.order(t, orderEntity.Order{Amount: 2000, ListAmount: 2500, DiscountAmount: 500, PriceID: 1, Status: orderEntity.OrderStatusPaid})
+				e.redeem(t, o.ID, "團報優惠", "", 200)
+				e.redeem(t, o.ID, "暑期折扣", "SUMMER", 300)
+				return o.ID
+			},
+			assertFunc: func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 2500, inv.Subtotal)
+				assert.EqualValues(t, 500, inv.Discount)
+				assert.EqualValues(t, 2000, inv.Total)
+				require.Len(t, lines, 3)
+				assert.Equal(t, "early_bird", lines[0].Detail)
+				assert.EqualValues(t, 1, lines[0].ReferenceID)
+				assert.Equal(t, entity.InvoiceLineKindDiscount, lines[1].Kind)
+				assert.Equal(t, "團報優惠", lines[1].Description)
+				assert.Equal(t, "", lines[1].Detail)
+				assert.EqualValues(t, 200, lines[1].Amount)
+				assert.Equal(t, "暑期折扣", lines[2].Description)
+				assert.Equal(t, "SUMMER", lines[2].Detail)
+				assert.EqualValues(t, 3, lines[2].Position)
+			},
+		},
+		{
+			name: "使用紀錄與折扣金額不符 , 以一筆折扣合計表示",
+			setup: func(t *testing.T, e *testEnv) uint {
+				o := e.order(t, orderEntity.Order{Amount: 2700, ListAmount: 3000, DiscountAmount: 300, Status: orderEntity.OrderStatusPaid})
+				e.redeem(t, o.ID, "暑期折扣", "SUMMER", 100)
+				return o.ID
+			},
+			assertFunc: func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error) {
+				require.NoError(t, err)
+				require.Len(t, lines, 2)
+				assert.Equal(t, "", lines[1].Description)
+				assert.EqualValues(t, 300, lines[1].Amount)
+			},
+		},
+		{
+			name: "已退款的訂單仍可開立",
+			setup: func(t *testing.T, e *testEnv) uint {
+				return e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusRefunded}).ID
+			},
+			assertFunc: func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "INV-2026-000001", inv.Number)
+			},
+		},
+		{
+			name: "未付款的訂單",
+			setup: func(t *testing.T, e *testEnv) uint {
+				return e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000}).ID
+			},
+			assertFunc: func(t *testing.T, inv *entity.Invoice, lines []*entity.InvoiceLine, err error) {
+				assert.ErrorIs(t, err, ErrOrderNotPaid)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			ctx := context.Background()
+
+			inv, err := e.svc.Issue(ctx, tt.setup(t, e))
+			var lines []*entity.InvoiceLine
+			if err == nil {
+				_, lines, err = e.svc.Get(ctx, inv.ID)
+				require.NoError(t, err)
+			}
+			tt.assertFunc(t, inv, lines, err)
+		})
+	}
+}
+
+func TestIssue_Numbering(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+	first := e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusPaid})
+	second := e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusPaid})
+
+	inv, err := e.svc.Issue(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2026-000001", inv.Number)
+
+	// 重複開立回傳原本的發票 , 不會取號
+	again, err := e.svc.Issue(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inv.ID, again.ID)
+
+	inv, err = e.svc.Issue(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2026-000002", inv.Number)
+
+	// 新年度重新編號
+	e.svc.now = func() time.Time { return testNow.AddDate(1, 0, 0) }
+	third := e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusPaid})
+	inv, err = e.svc.Issue(ctx, third.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2027-000001", inv.Number)
+
+	invoices, err := e.svc.List(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 2026, 10)
+	require.NoError(t, err)
+	assert.Len(t, invoices, 2)
+
+	events, err := e.outbox.ListPending(ctx, time.Now().Add(time.Second), 100)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, event.TypeInvoiceIssued, events[0].EventType)
+}
+
+func TestHandleEvent(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+	o := e.order(t, orderEntity.Order{Amount: 3000, ListAmount: 3000, Status: orderEntity.OrderStatusPaid})
+
+	payload, err := json.Marshal(event.OrderPaid{OrderID: o.ID, EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, Currency: "TWD", PaidAt: testNow})
+	require.NoError(t, err)
+	msg := outbox.Message{Type: event.TypeOrderPaid, Payload: payload}
+
+	// 事件重送時不會重複開立
+	require.NoError(t, e.svc.HandleEvent(ctx, msg))
+	require.NoError(t, e.svc.HandleEvent(ctx, msg))
+
+	inv, err := e.svc.GetByOrder(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-2026-000001", inv.Number)
+	invoices, err := e.svc.List(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, invoices, 1)
+}
+
+func TestRender(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+	o := e.order(t, orderEntity.Order{Amount: 2700, ListAmount: 3000, DiscountAmount: 300, Status: orderEntity.OrderStatusPaid})
+	e.redeem(t, o.ID, "暑期折扣", "SUMMER", 300)
+	inv, err := e.svc.Issue(ctx, o.ID)
+	require.NoError(t, err)
+
+	html, err := e.svc.Render(ctx, inv.ID, invoice.FormatHTML, "zh-TW")
+	require.NoError(t, err)
+	for _, s := range []string{"INV-2026-000001", "課程管理股份有限公司", "2026/01/01", "暑期折扣", "TWD -3.00", "TWD 27.00"} {
+		assert.Contains(t, string(html), s)
+	}
+
+	pdf, err := e.svc.Render(ctx, inv.ID, invoice.FormatPDF, "en")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF-"))
+
+	_, err = e.svc.Render(ctx, inv.ID, invoice.FormatPDF, "fr")
+	assert.ErrorIs(t, err, invoice.ErrUnsupportedLocale)
+}
@@ -0,0 +1,50 @@
+package invoice
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/invoice/entity"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// InvoiceService 定義發票相關的業務邏輯
+// 訂單付款後開立發票 , 發票號碼依年度連續編號 , 並可依語系輸出 HTML 或 PDF
+//
+// Example:
+//
+//	var svc InvoiceService
+//	inv, err := svc.Issue(ctx, orderID)
+//	b, err := svc.Render(ctx, inv.ID, invoice.FormatPDF, "zh-TW")
+type InvoiceService interface {
+	// Issue 為已付款的訂單開立發票 , 訂單已開立發票時回傳原本的發票
+	// 參數: ctx - context, orderID - 訂單ID
+	// 回傳: 發票, 錯誤訊息 , 訂單未付款時回傳 ErrOrderNotPaid
+	Issue(ctx context.Context, orderID uint) (*entity.Invoice, error)
+
+	// Get 依發票ID查詢發票與明細
+	// 參數: ctx - context, id - 發票ID
+	// 回傳: 發票, 發票明細, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	Get(ctx context.Context, id uint) (*entity.Invoice, []*entity.InvoiceLine, error)
+
+	// GetByOrder 查詢訂單的發票
+	// 參數: ctx - context, orderID - 訂單ID
+	// 回傳: 發票, 錯誤訊息 , 尚未開立時回傳 gorm.ErrRecordNotFound
+	GetByOrder(ctx context.Context, orderID uint) (*entity.Invoice, error)
+
+	// List 查詢發票清單
+	// 參數: ctx - context, pageInfo - 分頁與排序, year - 開立年度 , 0 表示不限, studentID - 學生 user ID , 0 表示不限
+	// 回傳: 發票, 錯誤訊息
+	List(ctx context.Context, pageInfo *repo.RepoPageInfo, year int, studentID uint) ([]*entity.Invoice, error)
+
+	// Render 依語系版面輸出發票
+	// 參數: ctx - context, id - 發票ID, format - 輸出格式, locale - 語系
+	// 回傳: 檔案內容, 錯誤訊息 , 不支援的格式或語系回傳 invoice.ErrUnsupportedFormat / invoice.ErrUnsupportedLocale
+	Render(ctx context.Context, id uint, format invoice.Format, locale string) ([]byte, error)
+
+	// HandleEvent 處理訂單付款成功事件 , 開立發票 , 可作為 outbox.Handler
+	// 參數: ctx - context, msg - 事件
+	// 回傳: 錯誤訊息
+	HandleEvent(ctx context.Context, msg outbox.Message) error
+}
@@ -0,0 +1,417 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	locationRepo "github.com/itmrchow/course-management-system/internal/repository/location"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ LocationService = (*LocationServiceImpl)(nil)
+
+// MaxConflictRange Conflicts 查詢範圍的上限
+const MaxConflictRange = 366 * 24 * time.Hour
+
+// maxSessionDuration 單次上課時間的上限 , 上課時段的結束時間早於開始時間表示跨日 , 單次上課不超過一天
+const maxSessionDuration = 24 * time.Hour
+
+var (
+	// ErrDuplicateName 地點名稱重複 , 或同一地點中教室名稱重複
+	ErrDuplicateName = errors.New("duplicate name")
+	// ErrOnlineCourse 線上課程不指定教室
+	ErrOnlineCourse = errors.New("online course has no room")
+	// ErrRoomTooSmall 教室容納人數小於課程的最大學生人數
+	ErrRoomTooSmall = errors.New("room capacity is less than max students of course")
+	// ErrRoomConflict 教室於上課時間已有其他上課
+	ErrRoomConflict = errors.New("room is already booked")
+	// ErrInvalidRange 查詢範圍無效或超過 MaxConflictRange
+	ErrInvalidRange = errors.New("invalid conflict range")
+)
+
+// LocationServiceImpl 實作 LocationService 介面
+//
+// Example:
+//
+//	svc := location.NewLocationService(locationRepo.NewLocationRepository(db), courseRepo.NewCourseRepository(db), sessionRepo.NewSessionRepository(db), repository.NewTransactor(db))
+//	pattern, err := svc.AssignRoom(ctx, courseID, patternID, roomID)
+type LocationServiceImpl struct {
+	locationRepo locationRepo.LocationRepository
+	courseRepo   courseRepo.CourseRepository
+	sessionRepo  sessionRepo.SessionRepository
+	transactor   repo.Transactor
+}
+
+// NewLocationService 建立上課地點業務邏輯實例
+// 參數: locationRepo - 上課地點資料庫操作實例, courseRepo - 課程資料庫操作實例, sessionRepo - 上課資料庫操作實例, transactor - transaction
+// 回傳: 上課地點業務邏輯實例
+func NewLocationService(locationRepo locationRepo.LocationRepository, courseRepo courseRepo.CourseRepository, sessionRepo sessionRepo.SessionRepository, transactor repo.Transactor) LocationService {
+	return &LocationServiceImpl{
+		locationRepo: locationRepo,
+		courseRepo:   courseRepo,
+		sessionRepo:  sessionRepo,
+		transactor:   transactor,
+	}
+}
+
+// CreateLocation 新增地點
+func (s *LocationServiceImpl) CreateLocation(ctx context.Context, location *entity.Location) (*entity.Location, error) {
+	if _, err := s.locationRepo.CreateLocation(ctx, location); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("location %q: %w", location.Name, ErrDuplicateName)
+		}
+		return nil, fmt.Errorf("failed to create location: %w", err)
+	}
+	return s.GetLocation(ctx, location.ID)
+}
+
+// UpdateLocation 更新地點
+func (s *LocationServiceImpl) UpdateLocation(ctx context.Context, location *entity.Location) (*entity.Location, error) {
+	affected, err := s.locationRepo.UpdateLocation(ctx, location)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, fmt.Errorf("location %q: %w", location.Name, ErrDuplicateName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update location %d: %w", location.ID, err)
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("location %d: %w", location.ID, gorm.ErrRecordNotFound)
+	}
+	return s.GetLocation(ctx, location.ID)
+}
+
+// GetLocation 依地點ID查詢地點
+func (s *LocationServiceImpl) GetLocation(ctx context.Context, id uint) (*entity.Location, error) {
+	location, err := s.locationRepo.GetLocation(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location %d: %w", id, err)
+	}
+	return location, nil
+}
+
+// ListLocations 查詢地點清單
+func (s *LocationServiceImpl) ListLocations(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Location, error) {
+	locations, err := s.locationRepo.FindLocations(ctx, pageInfo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find locations: %w", err)
+	}
+	return locations, nil
+}
+
+// CreateRoom 新增教室 , 地點須存在
+func (s *LocationServiceImpl) CreateRoom(ctx context.Context, room *entity.Room) (*entity.Room, error) {
+	if _, err := s.locationRepo.GetLocation(ctx, room.LocationID); err != nil {
+		return nil, fmt.Errorf("failed to get location %d: %w", room.LocationID, err)
+	}
+	if _, err := s.locationRepo.CreateRoom(ctx, room); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("room %q of location %d: %w", room.Name, room.LocationID, ErrDuplicateName)
+		}
+		return nil, fmt.Errorf("failed to create room: %w", err)
+	}
+	return s.GetRoom(ctx, room.ID)
+}
+
+// UpdateRoom 更新教室 , 鎖定教室後檢查使用教室的課程 , 避免與 AssignRoom 同時進行時繞過容納人數檢查
+func (s *LocationServiceImpl) UpdateRoom(ctx context.Context, room *entity.Room) (updated *entity.Room, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.UpdateRoom",
+		trace.WithAttributes(attribute.Int("room.id", int(room.ID))))
+	defer tracing.End(span, &err)
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		current, err := s.locationRepo.LockRoom(ctx, room.ID)
+		if err != nil {
+			return fmt.Errorf("failed to lock room %d: %w", room.ID, err)
+		}
+		if room.Capacity < current.Capacity {
+			if err := s.checkCapacity(ctx, room); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.locationRepo.UpdateRoom(ctx, room); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("room %q of location %d: %w", room.Name, current.LocationID, ErrDuplicateName)
+			}
+			return fmt.Errorf("failed to update room %d: %w", room.ID, err)
+		}
+		updated, err = s.locationRepo.GetRoom(ctx, room.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get room %d: %w", room.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// checkCapacity 檢查使用教室的課程的最大學生人數皆不超過 room 的容納人數 , 已取消的課程不檢查
+func (s *LocationServiceImpl) checkCapacity(ctx context.Context, room *entity.Room) error {
+	patterns, err := s.courseRepo.ListPatternsByRoom(ctx, room.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list patterns of room %d: %w", room.ID, err)
+	}
+	checked := make(map[uint]bool)
+	for _, pattern := range patterns {
+		if checked[pattern.CourseID] {
+			continue
+		}
+		checked[pattern.CourseID] = true
+
+		course, err := s.courseRepo.GetByID(ctx, pattern.CourseID)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", pattern.CourseID, err)
+		}
+		if course.Status != courseEntity.CourseStatusCancelled && !fits(room, course) {
+			return fmt.Errorf("room %d capacity %d, course %d max students %d: %w", room.ID, room.Capacity, course.ID, course.MaxStudents, ErrRoomTooSmall)
+		}
+	}
+	return nil
+}
+
+// fits 教室是否容納得下課程的最大學生人數 , 不限人數 (MaxStudents 為 0) 的課程無法確保容納得下
+func fits(room *entity.Room, course *courseEntity.Course) bool {
+	return course.MaxStudents > 0 && course.MaxStudents <= room.Capacity
+}
+
+// GetRoom 依教室ID查詢教室
+func (s *LocationServiceImpl) GetRoom(ctx context.Context, id uint) (*entity.Room, error) {
+	room, err := s.locationRepo.GetRoom(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room %d: %w", id, err)
+	}
+	return room, nil
+}
+
+// ListRooms 查詢教室清單
+func (s *LocationServiceImpl) ListRooms(ctx context.Context, pageInfo *repo.RepoPageInfo, locationID, minCapacity uint) ([]*entity.Room, error) {
+	var conditions []func(db *gorm.DB) *gorm.DB
+	if locationID != 0 {
+		conditions = append(conditions, entity.RoomOfLocation(locationID))
+	}
+	if minCapacity != 0 {
+		conditions = append(conditions, entity.MinRoomCapacity(minCapacity))
+	}
+	rooms, err := s.locationRepo.FindRooms(ctx, pageInfo, conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rooms: %w", err)
+	}
+	return rooms, nil
+}
+
+// AssignRoom 指定上課時段的教室
+// 鎖定教室後檢查容納人數與重疊 , 同一間教室的指定依序進行 , 避免併發指定造成重複預約
+func (s *LocationServiceImpl) AssignRoom(ctx context.Context, courseID, patternID, roomID uint) (pattern *courseEntity.CoursePattern, err error) {
+	ctx, span := tracing.Start(ctx, "LocationService.AssignRoom",
+		trace.WithAttributes(attribute.Int("course.id", int(courseID)), attribute.Int("pattern.id", int(patternID)), attribute.Int("room.id", int(roomID))))
+	defer tracing.End(span, &err)
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		course, err := s.courseRepo.GetByID(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", courseID, err)
+		}
+		pattern, err = s.pattern(ctx, courseID, patternID)
+		if err != nil {
+			return err
+		}
+
+		if roomID != 0 {
+			if err := s.checkAssignment(ctx, course, pattern, roomID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.courseRepo.UpdatePatternRoom(ctx, patternID, roomID); err != nil {
+			return fmt.Errorf("failed to update room of pattern %d: %w", patternID, err)
+		}
+		pattern.RoomID = roomID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// pattern 查詢課程的上課時段 , 上課時段不屬於課程時回傳 gorm.ErrRecordNotFound
+func (s *LocationServiceImpl) pattern(ctx context.Context, courseID, patternID uint) (*courseEntity.CoursePattern, error) {
+	patterns, err := s.courseRepo.ListPatterns(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patterns of course %d: %w", courseID, err)
+	}
+	for _, pattern := range patterns {
+		if pattern.ID == patternID {
+			return pattern, nil
+		}
+	}
+	return nil, fmt.Errorf("pattern %d of course %d: %w", patternID, courseID, gorm.ErrRecordNotFound)
+}
+
+// checkAssignment 鎖定教室並檢查上課時段是否可使用教室 , 須於 transaction 中呼叫
+func (s *LocationServiceImpl) checkAssignment(ctx context.Context, course *courseEntity.Course, pattern *courseEntity.CoursePattern, roomID uint) error {
+	if course.IsOnline {
+		return fmt.Errorf("course %d: %w", course.ID, ErrOnlineCourse)
+	}
+	room, err := s.locationRepo.LockRoom(ctx, roomID)
+	if err != nil {
+		return fmt.Errorf("failed to lock room %d: %w", roomID, err)
+	}
+	if !fits(room, course) {
+		return fmt.Errorf("room %d capacity %d, course %d max students %d: %w", room.ID, room.Capacity, course.ID, course.MaxStudents, ErrRoomTooSmall)
+	}
+
+	from, to := course.StartDate, course.EndDate
+	sessions, err := s.scheduled(ctx, course, []*courseEntity.CoursePattern{pattern}, from, to)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	// 其他上課可能在範圍開始前一天開始並跨日 , 查詢範圍往前延伸
+	booked, err := s.roomSessions(ctx, roomID, pattern.ID, from.Add(-maxSessionDuration), to)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		for _, other := range booked {
+			if session.Overlaps(other) {
+				return fmt.Errorf("room %d at %s is used by course %d at %s ~ %s: %w",
+					roomID, session.StartAt.Format(time.RFC3339), other.CourseID,
+					other.StartAt.Format(time.RFC3339), other.EndAt.Format(time.RFC3339), ErrRoomConflict)
+			}
+		}
+	}
+	return nil
+}
+
+// Conflicts 查詢教室時間重疊的上課
+func (s *LocationServiceImpl) Conflicts(ctx context.Context, roomID uint, from, to time.Time) ([]Conflict, error) {
+	if to.Before(from) || to.Sub(from) > MaxConflictRange {
+		return nil, fmt.Errorf("%w: %s ~ %s", ErrInvalidRange, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	if _, err := s.locationRepo.GetRoom(ctx, roomID); err != nil {
+		return nil, fmt.Errorf("failed to get room %d: %w", roomID, err)
+	}
+
+	sessions, err := s.roomSessions(ctx, roomID, 0, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartAt.Before(sessions[j].StartAt) })
+
+	conflicts := make([]Conflict, 0)
+	for i, first := range sessions {
+		for _, second := range sessions[i+1:] {
+			if !second.StartAt.Before(first.EndAt) {
+				break
+			}
+			conflicts = append(conflicts, Conflict{RoomID: roomID, First: first, Second: second})
+		}
+	}
+	return conflicts, nil
+}
+
+// roomSessions 展開使用教室的上課時段於 from ~ to 的上課 , 不包含 excludePatternID 與已取消課程的上課時段
+func (s *LocationServiceImpl) roomSessions(ctx context.Context, roomID, excludePatternID uint, from, to time.Time) ([]*courseEntity.CourseSession, error) {
+	patterns, err := s.courseRepo.ListPatternsByRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patterns of room %d: %w", roomID, err)
+	}
+
+	var courseIDs []uint
+	byCourse := make(map[uint][]*courseEntity.CoursePattern)
+	for _, pattern := range patterns {
+		if pattern.ID == excludePatternID {
+			continue
+		}
+		if _, ok := byCourse[pattern.CourseID]; !ok {
+			courseIDs = append(courseIDs, pattern.CourseID)
+		}
+		byCourse[pattern.CourseID] = append(byCourse[pattern.CourseID], pattern)
+	}
+
+	var sessions []*courseEntity.CourseSession
+	for _, courseID := range courseIDs {
+		course, err := s.courseRepo.GetByID(ctx, courseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+		}
+		if course.Status == courseEntity.CourseStatusCancelled {
+			continue
+		}
+		scheduled, err := s.scheduled(ctx, course, byCourse[courseID], from, to)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, scheduled...)
+	}
+	return sessions, nil
+}
+
+// scheduled 展開課程的上課時段於 from ~ to 的上課 , 排除已寫入且已取消的上課 , 加入改期至範圍內的上課
+// 回傳: 上課 , 依開始時間排序, 錯誤訊息
+func (s *LocationServiceImpl) scheduled(ctx context.Context, course *courseEntity.Course, patterns []*courseEntity.CoursePattern, from, to time.Time) ([]*courseEntity.CourseSession, error) {
+	sessions := courseEntity.DeriveSessions(course, patterns, from, to)
+
+	persisted, err := s.sessionRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			courseEntity.SessionOfCourse(course.ID),
+			courseEntity.SessionStartBetween(from, to),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions of course %d: %w", course.ID, err)
+	}
+	if len(persisted) == 0 {
+		return sessions, nil
+	}
+
+	type sessionKey struct {
+		patternID uint
+		startAt   int64
+	}
+	ofPatterns := make(map[uint]bool, len(patterns))
+	for _, pattern := range patterns {
+		ofPatterns[pattern.ID] = true
+	}
+	skip := make(map[sessionKey]bool, len(persisted))
+	var rescheduled []*courseEntity.CourseSession
+	for _, session := range persisted {
+		switch {
+		case session.Status == courseEntity.SessionStatusCancelled:
+			skip[sessionKey{session.PatternID, session.StartAt.Unix()}] = true
+		case session.RescheduledFromID != 0 && ofPatterns[session.PatternID]:
+			// 改期產生的上課不會由上課時段展開
+			rescheduled = append(rescheduled, session)
+		}
+	}
+
+	result := make([]*courseEntity.CourseSession, 0, len(sessions)+len(rescheduled))
+	for _, session := range sessions {
+		if !skip[sessionKey{session.PatternID, session.StartAt.Unix()}] {
+			result = append(result, session)
+		}
+	}
+	if len(rescheduled) > 0 {
+		result = append(result, rescheduled...)
+		sort.SliceStable(result, func(i, j int) bool { return result[i].StartAt.Before(result[j].StartAt) })
+	}
+	return result, nil
+}
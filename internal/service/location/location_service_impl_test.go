@@ -0,0 +1,377 @@
+package location
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	locationRepo "github.com/itmrchow/course-management-system/internal/repository/location"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+)
+
+type testEnv struct {
+	svc       *LocationServiceImpl
+	courses   courseRepo.CourseRepository
+	sessions  sessionRepo.SessionRepository
+	locations locationRepo.LocationRepository
+}
+
+// clock 建立 2025-01-01 的時間 , 上課時段只使用時間部分
+func clock(hour, min int) time.Time {
+	return time.Date(2025, 1, 1, hour, min, 0, 0, time.UTC)
+}
+
+// newTestEnv 建立使用記憶體 repository 的上課地點業務邏輯
+// 地點 1 有教室 1 (30 人) 與教室 2 (10 人)
+// 課程 1 於 2025-07-21 ~ 08-03 上課 , 上課時段 1 每週一 11:00 ~ 13:00 , 上課時段 2 每週三 23:00 ~ 隔天 01:00
+// 課程 2 於 2025-07-28 ~ 08-10 上課 , 上課時段 3 每週一 12:00 ~ 14:00 , 上課時段 4 每週四 00:30 ~ 02:00 , 上課時段 5 每週二 11:00 ~ 13:00
+// 課程 3 為線上課程 , 上課時段 6 ; 課程 4 不限人數 , 上課時段 7 ; 課程 5 於 2025-09-01 ~ 09-14 上課 , 上課時段 8 每週一 11:00 ~ 13:00
+// 課程 1 、 2 、 5 的最大學生人數為 20
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	locations := locationRepo.NewLocationMemoryRepository()
+	_, err := locations.CreateLocation(ctx, &entity.Location{Name: "台北分校", Address: "台北市中正區"})
+	require.NoError(t, err)
+	for _, room := range []*entity.Room{
+		{LocationID: 1, Name: "301", Capacity: 30, Facilities: entity.Facilities{"projector"}},
+		{LocationID: 1, Name: "302", Capacity: 10},
+	} {
+		_, err := locations.CreateRoom(ctx, room)
+		require.NoError(t, err)
+	}
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門", MaxStudents: 20, StartDate: time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 8, 3, 23, 59, 59, 0, time.UTC)},
+		{Name: "Go 進階", MaxStudents: 20, StartDate: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 8, 10, 23, 59, 59, 0, time.UTC)},
+		{Name: "線上課程", MaxStudents: 20, IsOnline: true, StartDate: time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 8, 3, 23, 59, 59, 0, time.UTC)},
+		{Name: "不限人數", StartDate: time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 8, 3, 23, 59, 59, 0, time.UTC)},
+		{Name: "資料庫設計", MaxStudents: 20, StartDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 9, 14, 23, 59, 59, 0, time.UTC)},
+	} {
+		course.Status = courseEntity.CourseStatusOnline
+		_, err := courses.Create(ctx, course)
+		require.NoError(t, err)
+	}
+	for _, pattern := range []*courseEntity.CoursePattern{
+		{CourseID: 1, DayOfWeek: uint(time.Monday), StartTime: clock(11, 0), EndTime: clock(13, 0)},
+		{CourseID: 1, DayOfWeek: uint(time.Wednesday), StartTime: clock(23, 0), EndTime: clock(1, 0)},
+		{CourseID: 2, DayOfWeek: uint(time.Monday), StartTime: clock(12, 0), EndTime: clock(14, 0)},
+		{CourseID: 2, DayOfWeek: uint(time.Thursday), StartTime: clock(0, 30), EndTime: clock(2, 0)},
+		{CourseID: 2, DayOfWeek: uint(time.Tuesday), StartTime: clock(11, 0), EndTime: clock(13, 0)},
+		{CourseID: 3, DayOfWeek: uint(time.Monday), StartTime: clock(11, 0), EndTime: clock(13, 0)},
+		{CourseID: 4, DayOfWeek: uint(time.Friday), StartTime: clock(11, 0), EndTime: clock(13, 0)},
+		{CourseID: 5, DayOfWeek: uint(time.Monday), StartTime: clock(11, 0), EndTime: clock(13, 0)},
+	} {
+		_, err := courses.CreatePattern(ctx, pattern)
+		require.NoError(t, err)
+	}
+
+	sessions := sessionRepo.NewSessionMemoryRepository()
+	svc := NewLocationService(locations, courses, sessions, repository.NoTransaction).(*LocationServiceImpl)
+	return &testEnv{svc: svc, courses: courses, sessions: sessions, locations: locations}
+}
+
+// assign 以 repository 直接指定上課時段的教室 , 不檢查重疊
+func (e *testEnv) assign(t *testing.T, roomID uint, patternIDs ...uint) {
+	t.Helper()
+	for _, patternID := range patternIDs {
+		_, err := e.courses.UpdatePatternRoom(context.Background(), patternID, roomID)
+		require.NoError(t, err)
+	}
+}
+
+// cancelSession 寫入並取消課程於 startAt 開始的上課
+func (e *testEnv) cancelSession(t *testing.T, courseID uint, startAt time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	course, err := e.courses.GetByID(ctx, courseID)
+	require.NoError(t, err)
+	patterns, err := e.courses.ListPatterns(ctx, courseID)
+	require.NoError(t, err)
+	_, err = e.sessions.Ensure(ctx, courseEntity.DeriveSessions(course, patterns, startAt, startAt)...)
+	require.NoError(t, err)
+
+	sessions, err := e.sessions.Find(ctx, &repository.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{courseEntity.SessionOfCourse(courseID), courseEntity.SessionStartBetween(startAt, startAt)})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	_, err = e.sessions.Cancel(ctx, sessions[0].ID, "停課", startAt)
+	require.NoError(t, err)
+}
+
+// 指定上課時段的教室測試
+// Test for LocationServiceImpl.AssignRoom
+func TestAssignRoom(t *testing.T) {
+	type args struct {
+		courseID, patternID, roomID uint
+	}
+	tests := []struct {
+		name       string
+		args       args
+		setup      func(t *testing.T, e *testEnv)
+		assertFunc func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error)
+	}{
+		{
+			name: "指定教室",
+			args: args{courseID: 1, patternID: 1, roomID: 1},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, pattern.RoomID)
+
+				patterns, err := e.courses.ListPatternsByRoom(context.Background(), 1)
+				require.NoError(t, err)
+				require.Len(t, patterns, 1)
+				assert.EqualValues(t, 1, patterns[0].ID)
+			},
+		},
+		{
+			name:  "重新指定相同教室",
+			args:  args{courseID: 1, patternID: 1, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 1) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, pattern.RoomID)
+			},
+		},
+		{
+			name:  "取消指定",
+			args:  args{courseID: 1, patternID: 1, roomID: 0},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 1) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				require.NoError(t, err)
+				assert.Zero(t, pattern.RoomID)
+
+				patterns, err := e.courses.ListPatternsByRoom(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Empty(t, patterns)
+			},
+		},
+		{
+			name:  "上課時間重疊",
+			args:  args{courseID: 2, patternID: 3, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 1) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				require.ErrorIs(t, err, ErrRoomConflict)
+				assert.Contains(t, err.Error(), "course 1 at 2025-07-28T11:00:00Z")
+
+				patterns, err := e.courses.ListPatternsByRoom(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Len(t, patterns, 1)
+			},
+		},
+		{
+			name:  "與跨日的上課重疊",
+			args:  args{courseID: 2, patternID: 4, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 2) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, ErrRoomConflict)
+			},
+		},
+		{
+			name:  "不同時間",
+			args:  args{courseID: 2, patternID: 5, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 1, 2) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, pattern.RoomID)
+			},
+		},
+		{
+			name:  "上課期間不重疊",
+			args:  args{courseID: 5, patternID: 8, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) { e.assign(t, 1, 1) },
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "重疊的上課已取消",
+			args: args{courseID: 2, patternID: 3, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) {
+				e.assign(t, 1, 1)
+				e.cancelSession(t, 1, time.Date(2025, 7, 28, 11, 0, 0, 0, time.UTC))
+			},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "重疊的課程已取消",
+			args: args{courseID: 2, patternID: 3, roomID: 1},
+			setup: func(t *testing.T, e *testEnv) {
+				e.assign(t, 1, 1)
+				_, err := e.courses.UpdateStatus(context.Background(), 1, courseEntity.CourseStatusOnline, courseEntity.CourseStatusCancelled)
+				require.NoError(t, err)
+			},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "容納人數不足",
+			args: args{courseID: 1, patternID: 1, roomID: 2},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, ErrRoomTooSmall)
+			},
+		},
+		{
+			name: "不限人數的課程",
+			args: args{courseID: 4, patternID: 7, roomID: 1},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, ErrRoomTooSmall)
+			},
+		},
+		{
+			name: "線上課程",
+			args: args{courseID: 3, patternID: 6, roomID: 1},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, ErrOnlineCourse)
+			},
+		},
+		{
+			name: "上課時段不屬於課程",
+			args: args{courseID: 1, patternID: 3, roomID: 1},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "教室不存在",
+			args: args{courseID: 1, patternID: 1, roomID: 100},
+			assertFunc: func(t *testing.T, e *testEnv, pattern *courseEntity.CoursePattern, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+			pattern, err := e.svc.AssignRoom(context.Background(), tt.args.courseID, tt.args.patternID, tt.args.roomID)
+			tt.assertFunc(t, e, pattern, err)
+		})
+	}
+}
+
+// 更新教室測試
+// Test for LocationServiceImpl.UpdateRoom
+func TestUpdateRoom(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	e.assign(t, 1, 1)
+
+	_, err := e.svc.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "301", Capacity: 15})
+	assert.ErrorIs(t, err, ErrRoomTooSmall)
+
+	_, err = e.svc.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "302", Capacity: 30})
+	assert.ErrorIs(t, err, ErrDuplicateName)
+
+	_, err = e.svc.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 100}, Name: "303", Capacity: 30})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	room, err := e.svc.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "301A", Capacity: 20, Facilities: entity.Facilities{"projector", "whiteboard"}})
+	require.NoError(t, err)
+	assert.Equal(t, "301A", room.Name)
+	assert.EqualValues(t, 20, room.Capacity)
+	assert.Equal(t, entity.Facilities{"projector", "whiteboard"}, room.Facilities)
+
+	// 課程取消後不再檢查
+	_, err = e.courses.UpdateStatus(ctx, 1, courseEntity.CourseStatusOnline, courseEntity.CourseStatusCancelled)
+	require.NoError(t, err)
+	room, err = e.svc.UpdateRoom(ctx, &entity.Room{Model: gorm.Model{ID: 1}, Name: "301A", Capacity: 5})
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, room.Capacity)
+}
+
+// 新增地點與教室測試
+// Test for LocationServiceImpl.CreateLocation / CreateRoom
+func TestCreateRoom(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+
+	_, err := e.svc.CreateLocation(ctx, &entity.Location{Name: "台北分校", Address: "台北市大安區"})
+	assert.ErrorIs(t, err, ErrDuplicateName)
+
+	location, err := e.svc.CreateLocation(ctx, &entity.Location{Name: "台中分校", Address: "台中市西區"})
+	require.NoError(t, err)
+	assert.Equal(t, "台中分校", location.Name)
+
+	_, err = e.svc.CreateRoom(ctx, &entity.Room{LocationID: 100, Name: "101", Capacity: 20})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = e.svc.CreateRoom(ctx, &entity.Room{LocationID: 1, Name: "301", Capacity: 20})
+	assert.ErrorIs(t, err, ErrDuplicateName)
+
+	room, err := e.svc.CreateRoom(ctx, &entity.Room{LocationID: location.ID, Name: "301", Capacity: 20})
+	require.NoError(t, err)
+	assert.EqualValues(t, location.ID, room.LocationID)
+
+	rooms, err := e.svc.ListRooms(ctx, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, 0, 20)
+	require.NoError(t, err)
+	require.Len(t, rooms, 2)
+	assert.EqualValues(t, 1, rooms[0].ID)
+	assert.Equal(t, room.ID, rooms[1].ID)
+}
+
+// 查詢教室重疊的上課測試
+// Test for LocationServiceImpl.Conflicts
+func TestConflicts(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnv(t)
+	// 以 repository 直接指定 , 上課時段 1 與 3 於 2025-07-28 重疊 , 上課時段 2 與 4 於 2025-07-30 跨日重疊
+	e.assign(t, 1, 1, 2, 3, 4, 5)
+
+	from, to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)
+	conflicts, err := e.svc.Conflicts(ctx, 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	assert.EqualValues(t, 1, conflicts[0].First.PatternID)
+	assert.EqualValues(t, 3, conflicts[0].Second.PatternID)
+	assert.True(t, conflicts[0].First.StartAt.Equal(time.Date(2025, 7, 28, 11, 0, 0, 0, time.UTC)))
+	assert.EqualValues(t, 2, conflicts[1].First.PatternID)
+	assert.EqualValues(t, 4, conflicts[1].Second.PatternID)
+
+	// 取消其中一次上課後不再重疊
+	e.cancelSession(t, 2, time.Date(2025, 7, 28, 12, 0, 0, 0, time.UTC))
+	conflicts, err = e.svc.Conflicts(ctx, 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.EqualValues(t, 2, conflicts[0].First.PatternID)
+
+	// 改期至 2025-07-21 12:00 的上課與上課時段 1 重疊
+	rescheduledAt := time.Date(2025, 7, 21, 12, 0, 0, 0, time.UTC)
+	_, err = e.sessions.Ensure(ctx, &courseEntity.CourseSession{
+		CourseID: 2, PatternID: 3, StartAt: rescheduledAt, EndAt: rescheduledAt.Add(2 * time.Hour), RescheduledFromID: 1,
+	})
+	require.NoError(t, err)
+	conflicts, err = e.svc.Conflicts(ctx, 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	assert.True(t, conflicts[0].Second.StartAt.Equal(rescheduledAt))
+
+	conflicts, err = e.svc.Conflicts(ctx, 2, from, to)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	_, err = e.svc.Conflicts(ctx, 100, from, to)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = e.svc.Conflicts(ctx, 1, to, from)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = e.svc.Conflicts(ctx, 1, from, from.Add(MaxConflictRange+time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,83 @@
+package location
+
+import (
+	"context"
+	"time"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/location/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// Conflict 同一間教室上課時間重疊的兩次上課 , First 的開始時間不晚於 Second
+// 上課由上課時段展開 , 尚未寫入資料庫時 ID 為 0
+type Conflict struct {
+	RoomID uint
+	First  *courseEntity.CourseSession
+	Second *courseEntity.CourseSession
+}
+
+// LocationService 定義上課地點與教室相關的業務邏輯
+// 實體課程的上課時段 (CoursePattern) 可指定教室 , 教室的容納人數須不小於課程的最大學生人數 , 不限人數的課程無法指定教室 ,
+// 同一間教室展開後的上課時間不可重疊 , 已取消的上課與已取消課程的上課不列入
+//
+// Example:
+//
+//	var svc LocationService
+//	room, err := svc.CreateRoom(ctx, &entity.Room{LocationID: 1, Name: "301", Capacity: 30})
+//	pattern, err := svc.AssignRoom(ctx, courseID, patternID, room.ID)
+type LocationService interface {
+	// CreateLocation 新增地點
+	// 參數: ctx - context, location - 地點
+	// 回傳: 新增後的地點, 錯誤訊息 , 名稱重複時回傳 ErrDuplicateName
+	CreateLocation(ctx context.Context, location *entity.Location) (*entity.Location, error)
+
+	// UpdateLocation 更新地點的名稱、地址與備註
+	// 參數: ctx - context, location - 地點 , 以 ID 指定更新的地點
+	// 回傳: 更新後的地點, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound , 名稱重複時回傳 ErrDuplicateName
+	UpdateLocation(ctx context.Context, location *entity.Location) (*entity.Location, error)
+
+	// GetLocation 依地點ID查詢地點
+	// 參數: ctx - context, id - 地點ID
+	// 回傳: 地點, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetLocation(ctx context.Context, id uint) (*entity.Location, error)
+
+	// ListLocations 查詢地點清單
+	// 參數: ctx - context, pageInfo - 分頁與排序
+	// 回傳: 地點, 錯誤訊息
+	ListLocations(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Location, error)
+
+	// CreateRoom 新增教室
+	// 參數: ctx - context, room - 教室
+	// 回傳: 新增後的教室, 錯誤訊息 , 地點不存在時回傳 gorm.ErrRecordNotFound , 同一地點中名稱重複時回傳 ErrDuplicateName
+	CreateRoom(ctx context.Context, room *entity.Room) (*entity.Room, error)
+
+	// UpdateRoom 更新教室的名稱、容納人數與設備
+	// 參數: ctx - context, room - 教室 , 以 ID 指定更新的教室
+	// 回傳: 更新後的教室, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound , 同一地點中名稱重複時回傳 ErrDuplicateName ,
+	// 容納人數小於使用教室的課程的最大學生人數時回傳 ErrRoomTooSmall
+	UpdateRoom(ctx context.Context, room *entity.Room) (*entity.Room, error)
+
+	// GetRoom 依教室ID查詢教室
+	// 參數: ctx - context, id - 教室ID
+	// 回傳: 教室, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetRoom(ctx context.Context, id uint) (*entity.Room, error)
+
+	// ListRooms 查詢教室清單
+	// 參數: ctx - context, pageInfo - 分頁與排序, locationID - 地點ID , 0 表示不限, minCapacity - 最小容納人數 , 0 表示不限
+	// 回傳: 教室, 錯誤訊息
+	ListRooms(ctx context.Context, pageInfo *repo.RepoPageInfo, locationID, minCapacity uint) ([]*entity.Room, error)
+
+	// AssignRoom 指定課程上課時段的教室 , 檢查課程整個上課期間展開後的上課
+	// 參數: ctx - context, courseID - 課程ID, patternID - 上課時段ID, roomID - 教室ID , 0 表示取消指定
+	// 回傳: 更新後的上課時段, 錯誤訊息 , 課程、上課時段或教室不存在時回傳 gorm.ErrRecordNotFound ,
+	// 線上課程回傳 ErrOnlineCourse , 容納人數小於課程的最大學生人數時回傳 ErrRoomTooSmall ,
+	// 與教室其他上課時間重疊時回傳 ErrRoomConflict
+	AssignRoom(ctx context.Context, courseID, patternID, roomID uint) (*courseEntity.CoursePattern, error)
+
+	// Conflicts 查詢教室於 from ~ to 開始的上課中時間重疊的上課 , 範圍不可超過 MaxConflictRange
+	// 指定教室時已檢查重疊 , 用於找出課程期間變更後產生的重疊
+	// 參數: ctx - context, roomID - 教室ID, from / to - 上課開始時間範圍 , 皆包含在內
+	// 回傳: 重疊的上課 , 依開始時間排序, 錯誤訊息 , 教室不存在時回傳 gorm.ErrRecordNotFound
+	Conflicts(ctx context.Context, roomID uint, from, to time.Time) ([]Conflict, error)
+}
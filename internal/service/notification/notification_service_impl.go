@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	notificationRepo "github.com/itmrchow/course-management-system/internal/repository/notification"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var _ NotificationService = (*NotificationServiceImpl)(nil)
+
+// ErrUnsupportedLocale 不支援的語系
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+// NotificationServiceImpl 實作 NotificationService 介面
+//
+// Example:
+//
+//	renderer, err := notification.NewRenderer(time.Local)
+//	svc := notificationService.NewNotificationService(notificationRepo.NewNotificationRepository(db), teacherRepo.NewTeacherRepository(db), renderer)
+//	dispatcher.Subscribe(event.TypeTeacherApproved, svc.HandleEvent)
+type NotificationServiceImpl struct {
+	notificationRepo notificationRepo.NotificationRepository
+	teacherRepo      teacherRepo.TeacherRepository
+	renderer         *notification.Renderer
+	now              func() time.Time
+}
+
+// NewNotificationService 建立通知業務邏輯實例
+// 參數: notificationRepo - 通知資料庫操作實例, teacherRepo - 教師資料庫操作實例 , 查詢教師的聯絡方式, renderer - 通知樣板
+// 回傳: 通知業務邏輯實例
+func NewNotificationService(notificationRepo notificationRepo.NotificationRepository, teacherRepo teacherRepo.TeacherRepository, renderer *notification.Renderer) NotificationService {
+	return &NotificationServiceImpl{
+		notificationRepo: notificationRepo,
+		teacherRepo:      teacherRepo,
+		renderer:         renderer,
+		now:              time.Now,
+	}
+}
+
+// Notify 依收件人的通知偏好建立通知
+// 每個管道各建立一則通知 , 建立時即套用收件人語系的樣板 , 之後變更偏好不影響已建立的通知
+func (s *NotificationServiceImpl) Notify(ctx context.Context, req *NotifyRequest) (int64, error) {
+	preference, err := s.GetPreference(ctx, req.Recipient.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	key := req.Key
+	if key == "" {
+		key = uuid.NewString()
+	}
+	data := maps.Clone(req.Data)
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["Name"] = req.Recipient.Name
+
+	now := s.now()
+	var notifications []*entity.Notification
+	for _, channel := range entity.Channels() {
+		address := req.Recipient.address(channel, preference)
+		if address == "" || !preference.Enabled(channel) || !s.renderer.Has(req.Template, channel) {
+			continue
+		}
+
+		rendered, locale, err := s.renderer.Render(req.Template, preference.Locale, channel, data)
+		if err != nil {
+			return 0, fmt.Errorf("failed to render notification: %w", err)
+		}
+		notifications = append(notifications, &entity.Notification{
+			Key:           key,
+			Channel:       channel,
+			UserID:        req.Recipient.UserID,
+			Address:       address,
+			Template:      req.Template,
+			Locale:        locale,
+			Subject:       rendered.Subject,
+			Body:          rendered.Body,
+			NextAttemptAt: now,
+		})
+	}
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	count, err := s.notificationRepo.Enqueue(ctx, notifications...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue notifications: %w", err)
+	}
+	return count, nil
+}
+
+// address 收件人於管道的地址 , 收件人沒有提供時使用通知偏好的地址
+func (r Recipient) address(channel entity.Channel, preference *entity.NotificationPreference) string {
+	switch channel {
+	case entity.ChannelEmail:
+		return cmp.Or(r.Email, preference.Email)
+	case entity.ChannelSMS:
+		return cmp.Or(r.Phone, preference.Phone)
+	default:
+		return ""
+	}
+}
+
+// GetPreference 查詢使用者的通知偏好 , 沒有設定時回傳 DefaultNotificationPreference
+func (s *NotificationServiceImpl) GetPreference(ctx context.Context, userID uint) (*entity.NotificationPreference, error) {
+	preference, err := s.notificationRepo.GetPreference(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.DefaultNotificationPreference(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preference of user %d: %w", userID, err)
+	}
+	return preference, nil
+}
+
+// SavePreference 儲存使用者的通知偏好
+func (s *NotificationServiceImpl) SavePreference(ctx context.Context, preference *entity.NotificationPreference) error {
+	if !entity.IsSupportedLocale(preference.Locale) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLocale, preference.Locale)
+	}
+	if err := s.notificationRepo.SavePreference(ctx, preference); err != nil {
+		return fmt.Errorf("failed to save notification preference of user %d: %w", preference.UserID, err)
+	}
+	return nil
+}
+
+// HandleEvent 處理教師審核結果事件 , 通知教師
+// 其他事件類型略過
+func (s *NotificationServiceImpl) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	switch msg.Type {
+	case event.TypeTeacherApproved:
+		var e event.TeacherApproved
+		if err := json.Unmarshal(msg.Payload, &e); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", msg.Type, err)
+		}
+		return s.notifyTeacher(ctx, msg.ID, e.TeacherID, Recipient{UserID: e.UserID, Name: e.Name, Email: e.Email},
+			notification.TemplateTeacherApproved, map[string]any{"ApprovedAt": e.ApprovedAt})
+
+	case event.TypeTeacherRejected:
+		var e event.TeacherRejected
+		if err := json.Unmarshal(msg.Payload, &e); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", msg.Type, err)
+		}
+		return s.notifyTeacher(ctx, msg.ID, e.TeacherID, Recipient{UserID: e.UserID, Name: e.Name, Email: e.Email},
+			notification.TemplateTeacherRejected, map[string]any{"RejectedAt": e.RejectedAt})
+
+	default:
+		return nil
+	}
+}
+
+// notifyTeacher 通知教師 , 事件沒有電話 , 由教師資料補上
+// 教師已刪除時只以事件中的 email 通知
+func (s *NotificationServiceImpl) notifyTeacher(ctx context.Context, eventID string, teacherID uint, recipient Recipient, template string, data map[string]any) error {
+	teacher, err := s.teacherRepo.GetByID(ctx, teacherID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		zerolog.Ctx(ctx).Warn().Uint("teacher_id", teacherID).Msg("teacher not found, notify by email only")
+	case err != nil:
+		return fmt.Errorf("failed to get teacher %d: %w", teacherID, err)
+	default:
+		recipient.Phone = teacher.Phone
+	}
+
+	if _, err := s.Notify(ctx, &NotifyRequest{
+		Key:       eventID + ":" + template,
+		Recipient: recipient,
+		Template:  template,
+		Data:      data,
+	}); err != nil {
+		return fmt.Errorf("failed to notify teacher %d: %w", teacherID, err)
+	}
+	return nil
+}
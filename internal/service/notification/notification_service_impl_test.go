@@ -0,0 +1,242 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	notificationRepo "github.com/itmrchow/course-management-system/internal/repository/notification"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var testNow = time.Date(2025, 7, 10, 1, 0, 0, 0, time.UTC)
+
+// newTestService 建立使用記憶體 repository 的通知業務邏輯
+// 教師 1 (user 10) 有 email 與電話
+func newTestService(t *testing.T) (*NotificationServiceImpl, notificationRepo.NotificationRepository) {
+	t.Helper()
+
+	teachers := teacherRepo.NewTeacherMemoryRepository()
+	_, err := teachers.Create(context.Background(), &teacherEntity.Teacher{
+		UserID: 10, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved,
+	})
+	require.NoError(t, err)
+
+	renderer, err := notification.NewRenderer(time.UTC)
+	require.NoError(t, err)
+
+	notifications := notificationRepo.NewNotificationMemoryRepository()
+	svc := NewNotificationService(notifications, teachers, renderer).(*NotificationServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return svc, notifications
+}
+
+func listNotifications(t *testing.T, r notificationRepo.NotificationRepository) []*entity.Notification {
+	t.Helper()
+
+	notifications, err := r.Find(context.Background(), &repo.RepoPageInfo{Page: 1, PageSize: 100}, nil)
+	require.NoError(t, err)
+	return notifications
+}
+
+// 建立通知測試
+// Test for NotificationServiceImpl.Notify
+func TestNotify(t *testing.T) {
+	recipient := Recipient{UserID: 20, Name: "Amy", Email: "amy@example.com", Phone: "0987654321"}
+	data := map[string]any{"CourseName": "Go 入門", "StartAt": testNow, "EndAt": testNow.Add(time.Hour), "JoinURL": ""}
+
+	tests := []struct {
+		name       string
+		preference *entity.NotificationPreference
+		req        *NotifyRequest
+		assertFunc func(t *testing.T, count int64, err error, notifications []*entity.Notification)
+	}{
+		{
+			name: "default preference sends all channels in default locale",
+			req:  &NotifyRequest{Key: "k1", Recipient: recipient, Template: notification.TemplateCourseReminder, Data: data},
+			assertFunc: func(t *testing.T, count int64, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(2), count)
+				require.Len(t, notifications, 2)
+				assert.Equal(t, entity.ChannelEmail, notifications[0].Channel)
+				assert.Equal(t, "amy@example.com", notifications[0].Address)
+				assert.Equal(t, entity.DefaultLocale, notifications[0].Locale)
+				assert.Contains(t, notifications[0].Subject, "課程提醒")
+				assert.Equal(t, entity.ChannelSMS, notifications[1].Channel)
+				assert.Equal(t, "0987654321", notifications[1].Address)
+				assert.Equal(t, testNow, notifications[1].NextAttemptAt)
+			},
+		},
+		{
+			name:       "preference disables sms and uses en",
+			preference: &entity.NotificationPreference{UserID: 20, Locale: entity.LocaleEn, EmailEnabled: true},
+			req:        &NotifyRequest{Key: "k1", Recipient: recipient, Template: notification.TemplateCourseReminder, Data: data},
+			assertFunc: func(t *testing.T, count int64, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+				require.Len(t, notifications, 1)
+				assert.Equal(t, entity.LocaleEn, notifications[0].Locale)
+				assert.Contains(t, notifications[0].Body, "Hi Amy")
+			},
+		},
+		{
+			name: "missing address skips channel",
+			req:  &NotifyRequest{Key: "k1", Recipient: Recipient{UserID: 20, Name: "Amy", Email: "amy@example.com"}, Template: notification.TemplateCourseReminder, Data: data},
+			assertFunc: func(t *testing.T, count int64, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				require.Len(t, notifications, 1)
+				assert.Equal(t, entity.ChannelEmail, notifications[0].Channel)
+			},
+		},
+		{
+			name:       "address from preference",
+			preference: &entity.NotificationPreference{UserID: 30, Locale: entity.LocaleEn, EmailEnabled: true, SMSEnabled: true, Phone: "0911000111"},
+			req:        &NotifyRequest{Key: "k1", Recipient: Recipient{UserID: 30}, Template: notification.TemplateCourseReminder, Data: data},
+			assertFunc: func(t *testing.T, count int64, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				require.Len(t, notifications, 1)
+				assert.Equal(t, entity.ChannelSMS, notifications[0].Channel)
+				assert.Equal(t, "0911000111", notifications[0].Address)
+			},
+		},
+		{
+			name: "missing template data",
+			req:  &NotifyRequest{Key: "k1", Recipient: recipient, Template: notification.TemplateCourseReminder},
+			assertFunc: func(t *testing.T, count int64, err error, notifications []*entity.Notification) {
+				assert.Error(t, err)
+				assert.Empty(t, notifications)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, notifications := newTestService(t)
+			if tt.preference != nil {
+				require.NoError(t, svc.SavePreference(context.Background(), tt.preference))
+			}
+
+			count, err := svc.Notify(context.Background(), tt.req)
+			tt.assertFunc(t, count, err, listNotifications(t, notifications))
+		})
+	}
+}
+
+// 相同 Key 只建立一次
+// Test for NotificationServiceImpl.Notify
+func TestNotify_Dedupe(t *testing.T) {
+	svc, notifications := newTestService(t)
+	req := &NotifyRequest{
+		Key:       "cancel:1:20",
+		Recipient: Recipient{UserID: 20, Name: "Amy", Email: "amy@example.com"},
+		Template:  notification.TemplateCourseCancelled,
+		Data:      map[string]any{"CourseName": "Go 入門", "Reason": ""},
+	}
+
+	count, err := svc.Notify(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = svc.Notify(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Len(t, listNotifications(t, notifications), 1)
+}
+
+// 通知偏好測試
+// Test for NotificationServiceImpl.GetPreference / SavePreference
+func TestPreference(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	preference, err := svc.GetPreference(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DefaultNotificationPreference(20), preference)
+
+	err = svc.SavePreference(context.Background(), &entity.NotificationPreference{UserID: 20, Locale: "ja"})
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+
+	require.NoError(t, svc.SavePreference(context.Background(), &entity.NotificationPreference{UserID: 20, Locale: entity.LocaleEn, SMSEnabled: true}))
+	preference, err = svc.GetPreference(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, entity.LocaleEn, preference.Locale)
+	assert.False(t, preference.EmailEnabled)
+	assert.True(t, preference.SMSEnabled)
+}
+
+// 處理教師審核事件測試
+// Test for NotificationServiceImpl.HandleEvent
+func TestHandleEvent(t *testing.T) {
+	message := func(id string, e event.Event) outbox.Message {
+		payload, err := json.Marshal(e)
+		require.NoError(t, err)
+		return outbox.Message{ID: id, Type: e.EventType(), AggregateType: e.AggregateType(), AggregateID: e.AggregateID(), Payload: payload}
+	}
+
+	tests := []struct {
+		name       string
+		msg        outbox.Message
+		assertFunc func(t *testing.T, err error, notifications []*entity.Notification)
+	}{
+		{
+			name: "teacher approved notifies by email and sms",
+			msg:  message("evt-1", event.TeacherApproved{TeacherID: 1, UserID: 10, Name: "王小明", Email: "ming@example.com", ApprovedAt: testNow}),
+			assertFunc: func(t *testing.T, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				require.Len(t, notifications, 2)
+				assert.Equal(t, "evt-1:"+notification.TemplateTeacherApproved, notifications[0].Key)
+				assert.Equal(t, uint(10), notifications[0].UserID)
+				assert.Equal(t, "0912345678", notifications[1].Address)
+				assert.Contains(t, notifications[0].Body, "王小明")
+			},
+		},
+		{
+			name: "teacher rejected for deleted teacher notifies by email only",
+			msg:  message("evt-2", event.TeacherRejected{TeacherID: 99, UserID: 11, Name: "李大華", Email: "hua@example.com", RejectedAt: testNow}),
+			assertFunc: func(t *testing.T, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				require.Len(t, notifications, 1)
+				assert.Equal(t, notification.TemplateTeacherRejected, notifications[0].Template)
+				assert.Equal(t, "hua@example.com", notifications[0].Address)
+			},
+		},
+		{
+			name: "other event ignored",
+			msg:  message("evt-3", event.CourseFull{CourseID: 1, MaxStudents: 2, FullAt: testNow}),
+			assertFunc: func(t *testing.T, err error, notifications []*entity.Notification) {
+				require.NoError(t, err)
+				assert.Empty(t, notifications)
+			},
+		},
+		{
+			name: "invalid payload",
+			msg:  outbox.Message{ID: "evt-4", Type: event.TypeTeacherApproved, Payload: json.RawMessage(`"x"`)},
+			assertFunc: func(t *testing.T, err error, notifications []*entity.Notification) {
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, notifications := newTestService(t)
+
+			err := svc.HandleEvent(context.Background(), tt.msg)
+			created := listNotifications(t, notifications)
+			tt.assertFunc(t, err, created)
+
+			// 重新發送的事件不會重複通知
+			if err == nil {
+				require.NoError(t, svc.HandleEvent(context.Background(), tt.msg))
+				assert.Len(t, listNotifications(t, notifications), len(created))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/notification/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+)
+
+// Recipient 通知的收件人
+type Recipient struct {
+	UserID uint   // user ID , 用於查詢通知偏好
+	Name   string // 姓名 , 樣板中的 Name , 可為空白
+	Email  string // email , 空白時使用通知偏好的 email
+	Phone  string // 電話 , 空白時使用通知偏好的電話
+}
+
+// NotifyRequest 建立通知的請求
+type NotifyRequest struct {
+	Key       string         // 去除重複用的識別 , 相同 Key 的通知只會建立一次
+	Recipient Recipient      // 收件人
+	Template  string         // 樣板名稱 , 見 notification.Template*
+	Data      map[string]any // 樣板資料 , Name 由 Recipient.Name 帶入
+}
+
+// NotificationService 定義通知相關的業務邏輯
+// 依收件人的通知偏好套用樣板並加入發送佇列 , 實際發送由 notification.Sender 負責
+//
+// Example:
+//
+//	var svc NotificationService
+//	count, err := svc.Notify(ctx, &NotifyRequest{Key: "reminder:1:2", Recipient: recipient, Template: notification.TemplateCourseReminder, Data: data})
+type NotificationService interface {
+	// Notify 依收件人的通知偏好建立通知 , 收件人關閉或沒有地址 (收件人與通知偏好皆沒有) 的管道不會建立
+	// 參數: ctx - context, req - 通知請求
+	// 回傳: 新增的通知數量 , 已建立過相同 Key 的通知不計入, 錯誤訊息
+	Notify(ctx context.Context, req *NotifyRequest) (int64, error)
+
+	// GetPreference 查詢使用者的通知偏好 , 沒有設定時回傳預設值
+	// 參數: ctx - context, userID - user ID
+	// 回傳: 通知偏好, 錯誤訊息
+	GetPreference(ctx context.Context, userID uint) (*entity.NotificationPreference, error)
+
+	// SavePreference 儲存使用者的通知偏好
+	// 參數: ctx - context, preference - 通知偏好
+	// 回傳: 錯誤訊息 , 語系不支援時回傳 ErrUnsupportedLocale
+	SavePreference(ctx context.Context, preference *entity.NotificationPreference) error
+
+	// HandleEvent 處理領域事件並通知相關人員 , 可作為 outbox.Handler
+	// 以事件ID作為通知的 Key , 事件重複發送時不會重複通知
+	// 參數: ctx - context, msg - 事件
+	// 回傳: 錯誤訊息
+	HandleEvent(ctx context.Context, msg outbox.Message) error
+}
@@ -0,0 +1,798 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/job"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/payment"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ OrderService = (*OrderServiceImpl)(nil)
+
+// expireBatchSize ExpirePending 每個 transaction 處理的訂單數量
+const expireBatchSize = 100
+
+// ExpiredReason 逾期未付款的失敗原因
+const ExpiredReason = "expired"
+
+// CourseCancelledReason 課程取消的失敗原因
+const CourseCancelledReason = "course_cancelled"
+
+// AmountMismatchReason 付款金額或幣別與訂單不符的失敗原因
+const AmountMismatchReason = "amount_mismatch"
+
+var (
+	// ErrInvalidStatus 訂單狀態不允許此操作
+	ErrInvalidStatus = errors.New("invalid order status")
+	// ErrOrderExpired 訂單已超過付款期限
+	ErrOrderExpired = errors.New("order expired")
+	// ErrUnknownProvider 不支援的金流商
+	ErrUnknownProvider = errors.New("unknown payment provider")
+	// ErrPaymentProvider 金流商建立付款失敗
+	ErrPaymentProvider = errors.New("payment provider error")
+	// ErrNotRefundable 報名不是已報名狀態 , 無法計算退款
+	ErrNotRefundable = errors.New("enrollment not refundable")
+)
+
+// Config 訂單設定
+type Config struct {
+	RefundBatchSize      int           // 每次取得的待退款紀錄數量
+	RefundTimeout        time.Duration // 每筆退款呼叫金流商的時間上限 , 取得的紀錄於整批的時間上限內不會被其他 instance 取得
+	RefundMaxAttempts    uint          // 退款次數上限 , 超過後標記為失敗 , 需人工處理
+	RefundInitialBackoff time.Duration // 第一次重試等待時間 , 之後每次加倍
+	RefundMaxBackoff     time.Duration // 重試等待時間上限
+}
+
+// ConfigFromViper 從 viper 讀取訂單設定
+func ConfigFromViper() Config {
+	viper.SetDefault("PAYMENT_TIMEOUT", 10*time.Second)
+	viper.SetDefault("PAYMENT_REFUND_BATCH_SIZE", 50)
+	viper.SetDefault("PAYMENT_REFUND_MAX_ATTEMPTS", 8)
+	viper.SetDefault("PAYMENT_REFUND_INITIAL_BACKOFF", time.Minute)
+	viper.SetDefault("PAYMENT_REFUND_MAX_BACKOFF", 6*time.Hour)
+
+	return Config{
+		RefundBatchSize:      viper.GetInt("PAYMENT_REFUND_BATCH_SIZE"),
+		RefundTimeout:        viper.GetDuration("PAYMENT_TIMEOUT"),
+		RefundMaxAttempts:    viper.GetUint("PAYMENT_REFUND_MAX_ATTEMPTS"),
+		RefundInitialBackoff: viper.GetDuration("PAYMENT_REFUND_INITIAL_BACKOFF"),
+		RefundMaxBackoff:     viper.GetDuration("PAYMENT_REFUND_MAX_BACKOFF"),
+	}
+}
+
+// errDuplicateCallback 付款通知已處理過 , 用於結束 transaction 並捨棄本次的變更
+var errDuplicateCallback = errors.New("duplicate payment callback")
+
+// OrderServiceImpl 實作 OrderService 介面
+//
+// Example:
+//
+//	gateway, err := payment.GatewayFromViper()
+//	svc := order.NewOrderService(orderRepo.NewOrderRepository(db), enrollmentRepo.NewEnrollmentRepository(db), courseRepo.NewCourseRepository(db), promotionSvc, outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db), order.ConfigFromViper(), gateway)
+//	order, err := svc.Pay(ctx, 1)
+type OrderServiceImpl struct {
+	orderRepo      orderRepo.OrderRepository
+	enrollmentRepo enrollmentRepo.EnrollmentRepository
+	courseRepo     courseRepo.CourseRepository
+	promotionSvc   promotionService.PromotionService
+	outboxRepo     outboxRepo.OutboxRepository
+	transactor     repo.Transactor
+	cfg            Config
+	gateway        payment.Gateway            // 建立付款使用的金流商
+	gateways       map[string]payment.Gateway // 依名稱處理付款通知
+	now            func() time.Time
+}
+
+// NewOrderService 建立訂單業務邏輯實例
+// 參數: orderRepo - 訂單資料庫操作實例, enrollmentRepo - 報名資料庫操作實例, courseRepo - 課程資料庫操作實例, promotionSvc - 促銷活動業務邏輯, outboxRepo - 領域事件 outbox, transactor - transaction, cfg - 設定, gateways - 金流商 , 第一個用於建立付款
+// 回傳: 訂單業務邏輯實例
+func NewOrderService(orderRepo orderRepo.OrderRepository, enrollmentRepo enrollmentRepo.EnrollmentRepository, courseRepo courseRepo.CourseRepository, promotionSvc promotionService.PromotionService, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor, cfg Config, gateways ...payment.Gateway) OrderService {
+	s := &OrderServiceImpl{
+		orderRepo:      orderRepo,
+		enrollmentRepo: enrollmentRepo,
+		courseRepo:     courseRepo,
+		promotionSvc:   promotionSvc,
+		outboxRepo:     outboxRepo,
+		transactor:     transactor,
+		cfg:            cfg,
+		gateways:       make(map[string]payment.Gateway, len(gateways)),
+		now:            time.Now,
+	}
+	for _, gateway := range gateways {
+		if s.gateway == nil {
+			s.gateway = gateway
+		}
+		s.gateways[gateway.Name()] = gateway
+	}
+	return s
+}
+
+// GetByID 依訂單ID查詢訂單
+func (s *OrderServiceImpl) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", id, err)
+	}
+	return order, nil
+}
+
+// ListByEnrollment 查詢報名的所有訂單
+func (s *OrderServiceImpl) ListByEnrollment(ctx context.Context, enrollmentID uint) ([]*entity.Order, error) {
+	orders, err := s.orderRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{entity.OrderOfEnrollment(enrollmentID)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders of enrollment %d: %w", enrollmentID, err)
+	}
+	return orders, nil
+}
+
+// Pay 向金流商建立付款
+// 呼叫金流商不在 transaction 中 , 建立後以 SetPayment 條件更新 , 併發建立付款時以先寫入的付款為準
+func (s *OrderServiceImpl) Pay(ctx context.Context, id uint) (order *entity.Order, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.Pay",
+		trace.WithAttributes(attribute.Int("order.id", int(id))))
+	defer tracing.End(span, &err)
+
+	order, err = s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", id, err)
+	}
+	if order.Status != entity.OrderStatusPending {
+		return nil, fmt.Errorf("order %d is %s: %w", id, order.Status, ErrInvalidStatus)
+	}
+	if order.PaymentID != "" {
+		return order, nil
+	}
+	if !s.now().Before(order.ExpiresAt) {
+		return nil, fmt.Errorf("order %d: %w", id, ErrOrderExpired)
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, order.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", order.CourseID, err)
+	}
+	p, err := s.gateway.CreatePayment(ctx, payment.Request{
+		OrderID:     order.ID,
+		Amount:      order.Amount,
+		Currency:    order.Currency,
+		Description: course.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPaymentProvider, err)
+	}
+
+	rows, err := s.orderRepo.SetPayment(ctx, id, s.gateway.Name(), p.ID, p.CheckoutURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set payment of order %d: %w", id, err)
+	}
+	if rows == 0 {
+		// 併發建立付款或訂單已逾期 , 以目前的訂單為準
+		order, err = s.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order %d: %w", id, err)
+		}
+		if order.Status != entity.OrderStatusPending || order.PaymentID == "" {
+			return nil, fmt.Errorf("order %d is %s: %w", id, order.Status, ErrInvalidStatus)
+		}
+		return order, nil
+	}
+
+	order.Provider = s.gateway.Name()
+	order.PaymentID = p.ID
+	order.CheckoutURL = p.CheckoutURL
+
+	zerolog.Ctx(ctx).Info().
+		Uint("order_id", id).
+		Str("provider", order.Provider).
+		Str("payment_id", order.PaymentID).
+		Msg("payment created")
+
+	return order, nil
+}
+
+// HandleCallback 處理金流商的付款結果通知
+// 訂單與報名狀態、通知紀錄與領域事件在同一個 transaction 寫入
+// 通知紀錄以金流商與通知ID唯一 , 重複的通知於寫入紀錄時失敗並捨棄本次的變更
+// 付款成功但金額或幣別與訂單不符時 , 訂單標記為付款失敗並全額退還實際收取的金額
+// 訂單已付款失敗 (例如已逾期) 後才收到付款成功時 , 不恢復報名 , 全額退還實際收取的金額
+func (s *OrderServiceImpl) HandleCallback(ctx context.Context, provider string, header http.Header, body []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.HandleCallback",
+		trace.WithAttributes(attribute.String("payment.provider", provider)))
+	defer tracing.End(span, &err)
+
+	gateway, ok := s.gateways[provider]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProvider, provider)
+	}
+	cb, err := gateway.ParseCallback(header, body)
+	if err != nil {
+		metrics.PaymentCallbacksTotal.WithLabelValues(provider, "invalid").Inc()
+		return err
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Str("provider", provider).
+		Str("event_id", cb.EventID).
+		Str("payment_id", cb.PaymentID).
+		Str("status", string(cb.Status)).
+		Logger()
+
+	now := s.now()
+	var applied, refunded bool
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetByPayment(ctx, provider, cb.PaymentID)
+		if err != nil {
+			return fmt.Errorf("failed to get order of payment %s: %w", cb.PaymentID, err)
+		}
+
+		result := entity.OrderStatusPaid
+		if cb.Status == payment.StatusFailed {
+			result = entity.OrderStatusFailed
+		}
+
+		if result == entity.OrderStatusPaid && (cb.Amount != order.Amount || cb.Currency != order.Currency) {
+			// 金額不符的付款不確認報名 , 訂單標記為付款失敗並退還實際收取的金額
+			logger.Warn().
+				Uint("order_id", order.ID).
+				Uint("amount", cb.Amount).
+				Str("currency", cb.Currency).
+				Msg("payment amount mismatch")
+			if _, err := s.transition(ctx, order, entity.OrderStatusFailed, now, AmountMismatchReason); err != nil {
+				return err
+			}
+			refunded, err = s.refundRejectedPayment(ctx, order, cb, now)
+			if err != nil {
+				return err
+			}
+		} else {
+			reason := cb.Reason
+			if result == entity.OrderStatusFailed && reason == "" {
+				reason = "payment failed"
+			}
+			applied, err = s.transition(ctx, order, result, now, reason)
+			if err != nil {
+				return err
+			}
+
+			// 訂單已付款失敗 (例如已逾期) 後才完成的付款 , 退還實際收取的金額
+			if !applied && result == entity.OrderStatusPaid {
+				order, err = s.orderRepo.GetByID(ctx, order.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get order %d: %w", order.ID, err)
+				}
+				if order.Status == entity.OrderStatusFailed {
+					logger.Warn().Uint("order_id", order.ID).Msg("payment completed after order failed")
+					refunded, err = s.refundRejectedPayment(ctx, order, cb, now)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if _, err := s.orderRepo.CreateCallback(ctx, &entity.PaymentCallback{
+			Provider:  provider,
+			EventID:   cb.EventID,
+			OrderID:   order.ID,
+			PaymentID: cb.PaymentID,
+			Result:    result,
+			Applied:   applied,
+			Payload:   string(body),
+		}); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return errDuplicateCallback
+			}
+			return fmt.Errorf("failed to create payment callback %s: %w", cb.EventID, err)
+		}
+		return nil
+	})
+	if errors.Is(err, errDuplicateCallback) {
+		metrics.PaymentCallbacksTotal.WithLabelValues(provider, "duplicate").Inc()
+		logger.Info().Msg("duplicate payment callback")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case applied:
+		metrics.PaymentCallbacksTotal.WithLabelValues(provider, "applied").Inc()
+		if cb.Status != payment.StatusFailed {
+			metrics.EnrollmentsTotal.Inc()
+		}
+	case refunded:
+		metrics.PaymentCallbacksTotal.WithLabelValues(provider, "refunded").Inc()
+	default:
+		metrics.PaymentCallbacksTotal.WithLabelValues(provider, "ignored").Inc()
+	}
+	logger.Info().Bool("applied", applied).Bool("refunded", refunded).Msg("payment callback handled")
+	return nil
+}
+
+// refundRejectedPayment 為未被接受的付款建立全額退款 , 金額為金流商實際收取的金額 , 須於 transaction 中呼叫
+// 訂單已有退款紀錄時不重複建立
+// 回傳: 是否已建立退款, 錯誤訊息
+func (s *OrderServiceImpl) refundRejectedPayment(ctx context.Context, order *entity.Order, cb *payment.Callback, at time.Time) (bool, error) {
+	// 先查詢再建立 , 避免 unique 衝突中止 transaction
+	existing, err := s.orderRepo.FindRefunds(ctx, []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(order.ID)})
+	if err != nil {
+		return false, fmt.Errorf("failed to find refunds of order %d: %w", order.ID, err)
+	}
+	if len(existing) > 0 {
+		return false, nil
+	}
+
+	if _, err := s.orderRepo.CreateRefund(ctx, &entity.Refund{
+		OrderID:       order.ID,
+		EnrollmentID:  order.EnrollmentID,
+		CourseID:      order.CourseID,
+		StudentID:     order.StudentID,
+		Amount:        cb.Amount,
+		Currency:      cb.Currency,
+		Percent:       100,
+		Reason:        entity.RefundReasonPaymentRejected,
+		Provider:      order.Provider,
+		PaymentID:     cb.PaymentID,
+		NextAttemptAt: at,
+	}); err != nil {
+		return false, fmt.Errorf("failed to create refund of order %d: %w", order.ID, err)
+	}
+	return true, nil
+}
+
+// ExpirePending 將超過付款期限的待付款訂單標記為付款失敗 , 直到沒有逾期訂單或 ctx 結束
+// 每批訂單以 ListExpired 鎖定 , 多個 instance 同時執行時不會重複處理
+func (s *OrderServiceImpl) ExpirePending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		var count int
+		err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+			now := s.now()
+			orders, err := s.orderRepo.ListExpired(ctx, now, expireBatchSize)
+			if err != nil {
+				return fmt.Errorf("failed to list expired orders: %w", err)
+			}
+			count = len(orders)
+
+			for _, order := range orders {
+				if _, err := s.transition(ctx, order, entity.OrderStatusFailed, now, ExpiredReason); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			zerolog.Ctx(ctx).Info().Int("count", count).Msg("pending orders expired")
+		}
+		if count < expireBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// QuoteRefund 計算報名於現在退出課程可退款的金額
+// 以最新一筆已付款的訂單計算 , 比例依課程的退款規則 (courseEntity.QuoteRefund)
+func (s *OrderServiceImpl) QuoteRefund(ctx context.Context, enrollmentID uint) (*entity.RefundQuote, error) {
+	enrollment, err := s.enrollmentRepo.GetByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enrollment %d: %w", enrollmentID, err)
+	}
+	if enrollment.Status != enrollmentEntity.EnrollmentStatusEnrolled {
+		return nil, fmt.Errorf("enrollment %d is %s: %w", enrollmentID, enrollment.Status, ErrNotRefundable)
+	}
+
+	order, err := s.paidOrder(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	quote := &entity.RefundQuote{EnrollmentID: enrollmentID}
+	if order == nil {
+		return quote, nil
+	}
+
+	percent, deadline, err := s.refundPercent(ctx, order.CourseID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	refund := entity.NewRefund(order, percent, entity.RefundReasonWithdrawal, s.now())
+	quote.OrderID = order.ID
+	quote.Paid = order.Amount
+	quote.Amount = refund.Amount
+	quote.Currency = order.Currency
+	quote.Percent = percent
+	quote.Deadline = deadline
+	return quote, nil
+}
+
+// RefundWithdrawal 建立學生退出課程的退款紀錄 , 須於退出課程的 transaction 中呼叫
+// 比例依退出時間 at 的課程退款規則 , 沒有已付款的訂單或退款金額為 0 時不建立紀錄
+func (s *OrderServiceImpl) RefundWithdrawal(ctx context.Context, enrollmentID uint, at time.Time) (*entity.Refund, error) {
+	return s.refund(ctx, enrollmentID, entity.RefundReasonWithdrawal, at)
+}
+
+// ListRefunds 查詢訂單的退款紀錄
+func (s *OrderServiceImpl) ListRefunds(ctx context.Context, orderID uint) ([]*entity.Refund, error) {
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", orderID, err)
+	}
+
+	refunds, err := s.orderRepo.FindRefunds(ctx, []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(orderID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find refunds of order %d: %w", orderID, err)
+	}
+	return refunds, nil
+}
+
+// ProcessRefunds 向金流商退款 , 直到沒有待退款紀錄或 ctx 結束
+// 每批紀錄於 transaction 中以 ListPendingRefunds 鎖定並以 ClaimRefunds 延後下次嘗試時間 , commit 後在 transaction 外呼叫金流商
+// 每筆結果在各自的 transaction 中記錄 , 記錄失敗時於 claim 到期後以相同的退款ID 重送 , 金流商不會重複退款
+// 個別退款失敗不回傳錯誤 , 只記錄於退款紀錄並依指數退避重試 , 回傳的錯誤為存取資料庫失敗
+func (s *OrderServiceImpl) ProcessRefunds(ctx context.Context) error {
+	for ctx.Err() == nil {
+		refunds, err := s.claimRefunds(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, refund := range refunds {
+			if err := s.processRefund(ctx, refund); err != nil {
+				return err
+			}
+		}
+		if len(refunds) < s.cfg.RefundBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// claimRefunds 於 transaction 中取得一批可退款的紀錄 , 並延後至整批呼叫金流商的時間上限之後才可再取得
+func (s *OrderServiceImpl) claimRefunds(ctx context.Context) ([]*entity.Refund, error) {
+	now := s.now()
+	var refunds []*entity.Refund
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		refunds, err = s.orderRepo.ListPendingRefunds(ctx, now, s.cfg.RefundBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list pending refunds: %w", err)
+		}
+
+		ids := make([]uint, 0, len(refunds))
+		for _, refund := range refunds {
+			ids = append(ids, refund.ID)
+		}
+		until := now.Add(time.Duration(len(refunds)) * s.cfg.RefundTimeout)
+		if err := s.orderRepo.ClaimRefunds(ctx, ids, until); err != nil {
+			return fmt.Errorf("failed to claim refunds: %w", err)
+		}
+		return nil
+	})
+	return refunds, err
+}
+
+// HandleEvent 處理課程取消事件 , 待付款的訂單標記為付款失敗 , 已報名的學生退出課程並全額退款
+// 重送的事件不會重複處理 , 已退出的報名不會再次退款 , 其他事件類型略過
+func (s *OrderServiceImpl) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	switch msg.Type {
+	case event.TypeCourseCancelled:
+		var e event.CourseCancelled
+		if err := json.Unmarshal(msg.Payload, &e); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", msg.Type, err)
+		}
+		return s.cancelCourse(ctx, e.CourseID)
+
+	default:
+		return nil
+	}
+}
+
+// cancelCourse 處理課程取消 , 訂單、報名、退款紀錄與事件在同一個 transaction 寫入
+func (s *OrderServiceImpl) cancelCourse(ctx context.Context, courseID uint) error {
+	now := s.now()
+	var failed, refunded int
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		all := &repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "id", Order: "asc"}
+		orders, err := s.orderRepo.Find(ctx, all, []func(db *gorm.DB) *gorm.DB{
+			entity.OrderOfCourse(courseID),
+			entity.InOrderStatus([]entity.OrderStatus{entity.OrderStatusPending}),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find pending orders of course %d: %w", courseID, err)
+		}
+		for _, order := range orders {
+			applied, err := s.transition(ctx, order, entity.OrderStatusFailed, now, CourseCancelledReason)
+			if err != nil {
+				return err
+			}
+			if applied {
+				failed++
+			}
+		}
+
+		enrollments, err := s.enrollmentRepo.Find(ctx, all, []func(db *gorm.DB) *gorm.DB{
+			enrollmentEntity.EnrollmentOfCourse(courseID),
+			enrollmentEntity.InEnrollmentStatus([]enrollmentEntity.EnrollmentStatus{enrollmentEntity.EnrollmentStatusEnrolled}),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find enrollments of course %d: %w", courseID, err)
+		}
+		var events []event.Event
+		for _, enrollment := range enrollments {
+			rows, err := s.enrollmentRepo.UpdateStatus(ctx, enrollment.ID, enrollmentEntity.EnrollmentStatusEnrolled, enrollmentEntity.EnrollmentStatusWithdrawn)
+			if err != nil {
+				return fmt.Errorf("failed to withdraw enrollment %d: %w", enrollment.ID, err)
+			}
+			if rows == 0 {
+				continue
+			}
+			events = append(events, event.StudentWithdrawn{
+				EnrollmentID: enrollment.ID,
+				CourseID:     courseID,
+				StudentID:    enrollment.StudentID,
+				WithdrawnAt:  now,
+			})
+
+			refund, err := s.refund(ctx, enrollment.ID, entity.RefundReasonCourseCancelled, now)
+			if err != nil {
+				return err
+			}
+			if refund != nil {
+				refunded++
+			}
+		}
+
+		if err := s.outboxRepo.Add(ctx, events...); err != nil {
+			return fmt.Errorf("failed to add course %d cancellation events: %w", courseID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Uint("course_id", courseID).
+		Int("failed_orders", failed).
+		Int("refunds", refunded).
+		Msg("course cancellation handled")
+	return nil
+}
+
+// refund 建立報名最新一筆已付款訂單的退款紀錄 , 須於 transaction 中呼叫
+// 課程取消時全額退款 , 學生退出時依 at 的課程退款規則計算比例
+// 回傳: 退款紀錄 , 沒有已付款的訂單或退款金額為 0 時為 nil, 錯誤訊息
+func (s *OrderServiceImpl) refund(ctx context.Context, enrollmentID uint, reason entity.RefundReason, at time.Time) (*entity.Refund, error) {
+	order, err := s.paidOrder(ctx, enrollmentID)
+	if err != nil || order == nil {
+		return nil, err
+	}
+
+	percent := uint(100)
+	if reason == entity.RefundReasonWithdrawal {
+		percent, _, err = s.refundPercent(ctx, order.CourseID, at)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refund := entity.NewRefund(order, percent, reason, at)
+	if refund.Amount == 0 {
+		return nil, nil
+	}
+	if _, err := s.orderRepo.CreateRefund(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to create refund of order %d: %w", order.ID, err)
+	}
+
+	metrics.RefundsTotal.WithLabelValues(reason.String(), "requested").Inc()
+	zerolog.Ctx(ctx).Info().
+		Uint("refund_id", refund.ID).
+		Uint("order_id", order.ID).
+		Uint("amount", refund.Amount).
+		Uint("percent", percent).
+		Stringer("reason", reason).
+		Msg("refund requested")
+	return refund, nil
+}
+
+// paidOrder 查詢報名最新一筆已付款的訂單 , 沒有時回傳 nil
+func (s *OrderServiceImpl) paidOrder(ctx context.Context, enrollmentID uint) (*entity.Order, error) {
+	orders, err := s.orderRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: 1, Sort: "id", Order: "desc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			entity.OrderOfEnrollment(enrollmentID),
+			entity.InOrderStatus([]entity.OrderStatus{entity.OrderStatusPaid}),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find paid order of enrollment %d: %w", enrollmentID, err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	return orders[0], nil
+}
+
+// refundPercent 依課程的退款規則計算於 at 退出的退款比例
+// 回傳: 退款比例, 目前比例的截止時間, 錯誤訊息
+func (s *OrderServiceImpl) refundPercent(ctx context.Context, courseID uint, at time.Time) (uint, *time.Time, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	rules, err := s.courseRepo.ListRefundRules(ctx, courseID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list refund rules of course %d: %w", courseID, err)
+	}
+	quote := courseEntity.QuoteRefund(course, rules, at)
+	return quote.Percent, quote.Deadline, nil
+}
+
+// processRefund 在 transaction 外向金流商退款 , 以退款ID 作為冪等鍵
+// 結果在 transaction 中記錄 , 成功時累計訂單的已退款金額並寫入 OrderRefunded , 全額退款後訂單才變更為已退款
+func (s *OrderServiceImpl) processRefund(ctx context.Context, refund *entity.Refund) error {
+	logger := zerolog.Ctx(ctx).With().
+		Uint("refund_id", refund.ID).
+		Uint("order_id", refund.OrderID).
+		Str("provider", refund.Provider).
+		Uint("attempts", refund.Attempts+1).
+		Logger()
+
+	attempt := &entity.RefundAttempt{At: s.now()}
+	gateway, ok := s.gateways[refund.Provider]
+	if !ok {
+		attempt.Error = fmt.Sprintf("%s: %q", ErrUnknownProvider, refund.Provider)
+	} else {
+		result, err := gateway.Refund(ctx, payment.RefundRequest{
+			RefundID:  refund.ID,
+			PaymentID: refund.PaymentID,
+			Amount:    refund.Amount,
+			Currency:  refund.Currency,
+		})
+		if err != nil {
+			attempt.Error = err.Error()
+		} else {
+			attempt.ProviderRefundID = result.ID
+		}
+	}
+
+	switch {
+	case attempt.Error == "":
+		attempt.Status = entity.RefundStatusSucceeded
+		metrics.RefundsTotal.WithLabelValues(refund.Reason.String(), "succeeded").Inc()
+		logger.Info().Str("provider_refund_id", attempt.ProviderRefundID).Msg("refund succeeded")
+
+	case !ok || refund.Attempts+1 >= s.cfg.RefundMaxAttempts:
+		attempt.Status = entity.RefundStatusFailed
+		metrics.RefundsTotal.WithLabelValues(refund.Reason.String(), "failed").Inc()
+		logger.Error().Str("error", attempt.Error).Msg("refund failed")
+
+	default:
+		attempt.Status = entity.RefundStatusPending
+		attempt.NextAttemptAt = attempt.At.Add(job.Backoff(s.cfg.RefundInitialBackoff, s.cfg.RefundMaxBackoff, refund.Attempts))
+		metrics.RefundsTotal.WithLabelValues(refund.Reason.String(), "retry").Inc()
+		logger.Warn().Str("error", attempt.Error).Time("next_attempt_at", attempt.NextAttemptAt).Msg("failed to refund")
+	}
+
+	return s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.orderRepo.RecordRefundAttempt(ctx, refund.ID, attempt); err != nil {
+			return fmt.Errorf("failed to record refund %d attempt: %w", refund.ID, err)
+		}
+		if attempt.Status != entity.RefundStatusSucceeded {
+			return nil
+		}
+
+		rows, err := s.orderRepo.AddRefundedAmount(ctx, refund.OrderID, refund.Amount, attempt.At)
+		if err != nil {
+			return fmt.Errorf("failed to add order %d refunded amount: %w", refund.OrderID, err)
+		}
+		if rows == 0 {
+			logger.Warn().Msg("order not found, refunded amount unchanged")
+		}
+		if err := s.outboxRepo.Add(ctx, event.OrderRefunded{
+			OrderID:      refund.OrderID,
+			RefundID:     refund.ID,
+			EnrollmentID: refund.EnrollmentID,
+			CourseID:     refund.CourseID,
+			StudentID:    refund.StudentID,
+			Amount:       refund.Amount,
+			Currency:     refund.Currency,
+			Reason:       refund.Reason.String(),
+			RefundedAt:   attempt.At,
+		}); err != nil {
+			return fmt.Errorf("failed to add order %d event: %w", refund.OrderID, err)
+		}
+		return nil
+	})
+}
+
+// transition 將待付款訂單變更為已付款或付款失敗 , 並確認報名或釋出報名名額與促銷的使用次數 , 須於 transaction 中呼叫
+// 回傳: 是否已變更 , 訂單已不是待付款時回傳 false, 錯誤訊息
+func (s *OrderServiceImpl) transition(ctx context.Context, order *entity.Order, to entity.OrderStatus, at time.Time, reason string) (bool, error) {
+	rows, err := s.orderRepo.UpdateStatus(ctx, order.ID, entity.OrderStatusPending, to, at, reason)
+	if err != nil {
+		return false, fmt.Errorf("failed to update order %d to %s: %w", order.ID, to, err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	var events []event.Event
+	enrollmentStatus := enrollmentEntity.EnrollmentStatusWithdrawn
+	if to == entity.OrderStatusPaid {
+		enrollmentStatus = enrollmentEntity.EnrollmentStatusEnrolled
+		events = append(events,
+			event.OrderPaid{
+				OrderID:      order.ID,
+				EnrollmentID: order.EnrollmentID,
+				CourseID:     order.CourseID,
+				StudentID:    order.StudentID,
+				Amount:       order.Amount,
+				Currency:     order.Currency,
+				Provider:     order.Provider,
+				PaymentID:    order.PaymentID,
+				PaidAt:       at,
+			},
+			event.StudentEnrolled{
+				EnrollmentID: order.EnrollmentID,
+				CourseID:     order.CourseID,
+				StudentID:    order.StudentID,
+				EnrolledAt:   at,
+			},
+		)
+	} else {
+		if err := s.promotionSvc.Release(ctx, order.ID); err != nil {
+			return false, err
+		}
+		events = append(events, event.OrderFailed{
+			OrderID:      order.ID,
+			EnrollmentID: order.EnrollmentID,
+			CourseID:     order.CourseID,
+			StudentID:    order.StudentID,
+			Reason:       reason,
+			FailedAt:     at,
+		})
+	}
+
+	rows, err = s.enrollmentRepo.UpdateStatus(ctx, order.EnrollmentID, enrollmentEntity.EnrollmentStatusPendingPayment, enrollmentStatus)
+	if err != nil {
+		return false, fmt.Errorf("failed to update enrollment %d to %s: %w", order.EnrollmentID, enrollmentStatus, err)
+	}
+	if rows == 0 {
+		return false, fmt.Errorf("enrollment %d of order %d is not pending payment: %w", order.EnrollmentID, order.ID, ErrInvalidStatus)
+	}
+
+	if err := s.outboxRepo.Add(ctx, events...); err != nil {
+		return false, fmt.Errorf("failed to add order %d event: %w", order.ID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,734 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	promotionEntity "github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/payment"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	orderRepo "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+	"github.com/itmrchow/course-management-system/internal/webhook"
+)
+
+var testNow = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+const testSecret = "whsec_test"
+
+// failingGateway 建立付款一律失敗的金流商
+type failingGateway struct{}
+
+func (failingGateway) Name() string { return "failing" }
+
+func (failingGateway) CreatePayment(ctx context.Context, req payment.Request) (*payment.Payment, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingGateway) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingGateway) ParseCallback(header http.Header, body []byte) (*payment.Callback, error) {
+	return nil, payment.ErrInvalidCallback
+}
+
+// refundFuncGateway 退款時呼叫 refund 的金流商
+type refundFuncGateway struct {
+	failingGateway
+	refund func(req payment.RefundRequest) (*payment.RefundResult, error)
+}
+
+func (g refundFuncGateway) Name() string { return "refund_func" }
+
+func (g refundFuncGateway) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	return g.refund(req)
+}
+
+type testEnv struct {
+	svc         *OrderServiceImpl
+	orders      orderRepo.OrderRepository
+	enrollments enrollmentRepo.EnrollmentRepository
+	promotions  promotionRepo.PromotionRepository
+	outbox      outboxRepo.OutboxRepository
+}
+
+// newTestEnv 建立使用記憶體 repository 與模擬金流商的訂單業務邏輯
+// 訂單 1 為待付款 , 對應報名 1 (學生 10 報名付費課程 1) , 付款期限為 testNow 後 30 分鐘
+// 課程報名於 testNow 後 7 天結束 , 14 天後開始上課 , 使用預設退款規則
+func newTestEnv(t *testing.T, gateways ...payment.Gateway) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Price: 3000, Status: courseEntity.CourseStatusOnline,
+		RegistrationEndDate: testNow.AddDate(0, 0, 7), StartDate: testNow.AddDate(0, 0, 14)})
+	require.NoError(t, err)
+
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	_, err = enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusPendingPayment, EnrolledAt: testNow})
+	require.NoError(t, err)
+
+	orders := orderRepo.NewOrderMemoryRepository()
+	_, err = orders.Create(ctx, &entity.Order{EnrollmentID: 1, CourseID: 1, StudentID: 10, Amount: 3000, Currency: "TWD", ExpiresAt: testNow.Add(30 * time.Minute)})
+	require.NoError(t, err)
+
+	if len(gateways) == 0 {
+		gateways = []payment.Gateway{payment.NewFakeGateway(payment.FakeConfig{BaseURL: "http://localhost:8080", Secret: testSecret}, http.DefaultClient)}
+	}
+
+	outbox := outboxRepo.NewOutboxMemoryRepository()
+	promotions := promotionRepo.NewPromotionMemoryRepository()
+	promotionSvc := promotionService.NewPromotionService(promotions, courses, enrollments)
+	svc := NewOrderService(orders, enrollments, courses, promotionSvc, outbox, repository.NoTransaction, Config{
+		RefundBatchSize:      10,
+		RefundTimeout:        time.Second,
+		RefundMaxAttempts:    2,
+		RefundInitialBackoff: time.Minute,
+		RefundMaxBackoff:     time.Hour,
+	}, gateways...).(*OrderServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return &testEnv{svc: svc, orders: orders, enrollments: enrollments, promotions: promotions, outbox: outbox}
+}
+
+// eventTypes 取得 outbox 中所有事件的類型
+func (e *testEnv) eventTypes(t *testing.T) []string {
+	t.Helper()
+
+	events, err := e.outbox.ListPending(context.Background(), time.Now().Add(time.Second), 100)
+	require.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	return types
+}
+
+// paid 將訂單 1 標記為以 provider 付款完成 , 報名 1 變更為已報名
+func (e *testEnv) paid(t *testing.T, provider string) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := e.orders.SetPayment(ctx, 1, provider, "pay_1", "")
+	require.NoError(t, err)
+	_, err = e.orders.UpdateStatus(ctx, 1, entity.OrderStatusPending, entity.OrderStatusPaid, testNow, "")
+	require.NoError(t, err)
+	_, err = e.enrollments.UpdateStatus(ctx, 1, enrollmentEntity.EnrollmentStatusPendingPayment, enrollmentEntity.EnrollmentStatusEnrolled)
+	require.NoError(t, err)
+}
+
+// signedCallback 已簽章的付款通知
+type signedCallback struct {
+	header http.Header
+	body   []byte
+}
+
+// callback 產生模擬金流商簽章的付款通知
+func callback(t *testing.T, eventID, paymentID string, status payment.Status, amount uint) signedCallback {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event_id":   eventID,
+		"payment_id": paymentID,
+		"status":     status,
+		"amount":     amount,
+		"currency":   "TWD",
+	})
+	require.NoError(t, err)
+
+	ts := time.Now().Unix()
+	header := http.Header{}
+	header.Set(payment.HeaderFakeTimestamp, strconv.FormatInt(ts, 10))
+	header.Set(payment.HeaderFakeSignature, webhook.Sign(testSecret, ts, body))
+	return signedCallback{header: header, body: body}
+}
+
+// 建立付款測試
+// Test for OrderServiceImpl.Pay
+func TestPay(t *testing.T) {
+	tests := []struct {
+		name       string
+		gateways   []payment.Gateway
+		setup      func(t *testing.T, env *testEnv)
+		id         uint
+		assertFunc func(t *testing.T, env *testEnv, order *entity.Order, err error)
+	}{
+		{
+			name: "success",
+			id:   1,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, payment.FakeProvider, order.Provider)
+				assert.Contains(t, order.CheckoutURL, "/fake-payments/"+order.PaymentID)
+
+				stored, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, order.PaymentID, stored.PaymentID)
+			},
+		},
+		{
+			name: "idempotent",
+			setup: func(t *testing.T, env *testEnv) {
+				_, err := env.orders.SetPayment(context.Background(), 1, payment.FakeProvider, "fake_1", "http://localhost:8080/fake-payments/fake_1")
+				require.NoError(t, err)
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "fake_1", order.PaymentID)
+			},
+		},
+		{
+			name: "expired",
+			setup: func(t *testing.T, env *testEnv) {
+				env.svc.now = func() time.Time { return testNow.Add(time.Hour) }
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				assert.ErrorIs(t, err, ErrOrderExpired)
+			},
+		},
+		{
+			name: "not pending",
+			setup: func(t *testing.T, env *testEnv) {
+				_, err := env.orders.UpdateStatus(context.Background(), 1, entity.OrderStatusPending, entity.OrderStatusFailed, testNow, "declined")
+				require.NoError(t, err)
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				assert.ErrorIs(t, err, ErrInvalidStatus)
+			},
+		},
+		{
+			name: "not found",
+			id:   100,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name:     "provider error",
+			gateways: []payment.Gateway{failingGateway{}},
+			id:       1,
+			assertFunc: func(t *testing.T, env *testEnv, order *entity.Order, err error) {
+				assert.ErrorIs(t, err, ErrPaymentProvider)
+
+				stored, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Empty(t, stored.PaymentID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, tt.gateways...)
+			if tt.setup != nil {
+				tt.setup(t, env)
+			}
+			order, err := env.svc.Pay(context.Background(), tt.id)
+			tt.assertFunc(t, env, order, err)
+		})
+	}
+}
+
+// 處理付款通知測試
+// Test for OrderServiceImpl.HandleCallback
+func TestHandleCallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, env *testEnv)
+		callbacks  func(t *testing.T, paymentID string) []signedCallback
+		assertFunc func(t *testing.T, env *testEnv, err error)
+	}{
+		{
+			name: "paid confirms enrollment",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				return []signedCallback{callback(t, "evt_1", paymentID, payment.StatusPaid, 3000)}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+
+				order, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusPaid, order.Status)
+				assert.NotNil(t, order.PaidAt)
+
+				enrollment, err := env.enrollments.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, enrollmentEntity.EnrollmentStatusEnrolled, enrollment.Status)
+
+				assert.Equal(t, []string{event.TypeOrderPaid, event.TypeStudentEnrolled}, env.eventTypes(t))
+			},
+		},
+		{
+			name: "failed releases seat",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				return []signedCallback{callback(t, "evt_1", paymentID, payment.StatusFailed, 0)}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+
+				order, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusFailed, order.Status)
+				assert.Equal(t, "payment failed", order.FailureReason)
+
+				enrollment, err := env.enrollments.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, enrollmentEntity.EnrollmentStatusWithdrawn, enrollment.Status)
+
+				assert.Equal(t, []string{event.TypeOrderFailed}, env.eventTypes(t))
+			},
+		},
+		{
+			name: "duplicate callback",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				cb := callback(t, "evt_1", paymentID, payment.StatusPaid, 3000)
+				return []signedCallback{cb, cb}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []string{event.TypeOrderPaid, event.TypeStudentEnrolled}, env.eventTypes(t))
+			},
+		},
+		{
+			name: "failed after paid is ignored",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				return []signedCallback{
+					callback(t, "evt_1", paymentID, payment.StatusPaid, 3000),
+					callback(t, "evt_2", paymentID, payment.StatusFailed, 0),
+				}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+
+				order, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusPaid, order.Status)
+			},
+		},
+		{
+			name: "amount mismatch",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				return []signedCallback{callback(t, "evt_1", paymentID, payment.StatusPaid, 1)}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+
+				order, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusFailed, order.Status)
+				assert.Equal(t, AmountMismatchReason, order.FailureReason)
+				assert.Equal(t, []string{event.TypeOrderFailed}, env.eventTypes(t))
+
+				// 退還實際收取的金額
+				refunds, err := env.orders.FindRefunds(context.Background(), []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(1)})
+				require.NoError(t, err)
+				require.Len(t, refunds, 1)
+				assert.EqualValues(t, 1, refunds[0].Amount)
+				assert.Equal(t, entity.RefundReasonPaymentRejected, refunds[0].Reason)
+			},
+		},
+		{
+			name: "paid after expired",
+			setup: func(t *testing.T, env *testEnv) {
+				env.svc.now = func() time.Time { return testNow.Add(time.Hour) }
+				require.NoError(t, env.svc.ExpirePending(context.Background()))
+			},
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				// 重送的通知不重複退款
+				cb := callback(t, "evt_1", paymentID, payment.StatusPaid, 3000)
+				return []signedCallback{cb, cb, callback(t, "evt_2", paymentID, payment.StatusPaid, 3000)}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				require.NoError(t, err)
+
+				order, err := env.orders.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Equal(t, entity.OrderStatusFailed, order.Status)
+				enrollment, err := env.enrollments.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.NotEqual(t, enrollmentEntity.EnrollmentStatusEnrolled, enrollment.Status)
+
+				refunds, err := env.orders.FindRefunds(context.Background(), []func(db *gorm.DB) *gorm.DB{entity.RefundOfOrder(1)})
+				require.NoError(t, err)
+				require.Len(t, refunds, 1)
+				assert.EqualValues(t, 3000, refunds[0].Amount)
+				assert.Equal(t, "TWD", refunds[0].Currency)
+				assert.Equal(t, order.PaymentID, refunds[0].PaymentID)
+				assert.Equal(t, entity.RefundReasonPaymentRejected, refunds[0].Reason)
+				assert.Equal(t, entity.RefundStatusPending, refunds[0].Status)
+			},
+		},
+		{
+			name: "unknown payment",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				return []signedCallback{callback(t, "evt_1", "fake_unknown", payment.StatusPaid, 3000)}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "invalid signature",
+			callbacks: func(t *testing.T, paymentID string) []signedCallback {
+				cb := callback(t, "evt_1", paymentID, payment.StatusPaid, 3000)
+				cb.header.Set(payment.HeaderFakeSignature, "v1=invalid")
+				return []signedCallback{cb}
+			},
+			assertFunc: func(t *testing.T, env *testEnv, err error) {
+				assert.ErrorIs(t, err, payment.ErrInvalidCallback)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			order, err := env.svc.Pay(context.Background(), 1)
+			require.NoError(t, err)
+			if tt.setup != nil {
+				tt.setup(t, env)
+			}
+
+			for _, cb := range tt.callbacks(t, order.PaymentID) {
+				err = env.svc.HandleCallback(context.Background(), payment.FakeProvider, cb.header, cb.body)
+				if err != nil {
+					break
+				}
+			}
+			tt.assertFunc(t, env, err)
+		})
+	}
+}
+
+// 付款完成確認報名時累計報名數量 , 重複的通知不重複累計
+func TestHandleCallback_EnrollMetrics(t *testing.T) {
+	env := newTestEnv(t)
+	order, err := env.svc.Pay(context.Background(), 1)
+	require.NoError(t, err)
+
+	before := testutil.ToFloat64(metrics.EnrollmentsTotal)
+	cb := callback(t, "evt_1", order.PaymentID, payment.StatusPaid, 3000)
+	require.NoError(t, env.svc.HandleCallback(context.Background(), payment.FakeProvider, cb.header, cb.body))
+	require.NoError(t, env.svc.HandleCallback(context.Background(), payment.FakeProvider, cb.header, cb.body))
+
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.EnrollmentsTotal))
+}
+
+// 不支援的金流商
+func TestHandleCallback_UnknownProvider(t *testing.T) {
+	env := newTestEnv(t)
+
+	err := env.svc.HandleCallback(context.Background(), "unknown", http.Header{}, []byte("{}"))
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
+
+// 逾期未付款測試
+// Test for OrderServiceImpl.ExpirePending
+func TestExpirePending(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	// 訂單 1 使用優惠券
+	code := "SAVE10"
+	_, err := env.promotions.Create(ctx, &promotionEntity.Promotion{Name: "九折", Code: &code, DiscountValue: 10, UsageLimit: 1})
+	require.NoError(t, err)
+	_, err = env.promotions.IncrementUsage(ctx, 1)
+	require.NoError(t, err)
+	_, err = env.promotions.CreateRedemption(ctx, &promotionEntity.PromotionRedemption{PromotionID: 1, StudentID: 10, OrderID: 1, Discount: 300, Currency: "TWD"})
+	require.NoError(t, err)
+
+	// 尚未逾期
+	require.NoError(t, env.svc.ExpirePending(ctx))
+	order, err := env.orders.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusPending, order.Status)
+
+	env.svc.now = func() time.Time { return testNow.Add(time.Hour) }
+	require.NoError(t, env.svc.ExpirePending(ctx))
+
+	order, err = env.orders.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusFailed, order.Status)
+	assert.Equal(t, ExpiredReason, order.FailureReason)
+
+	enrollment, err := env.enrollments.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, enrollmentEntity.EnrollmentStatusWithdrawn, enrollment.Status)
+	assert.Equal(t, []string{event.TypeOrderFailed}, env.eventTypes(t))
+
+	// 釋出優惠券的使用次數
+	promotion, err := env.promotions.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, promotion.UsedCount)
+}
+
+// 退款金額試算測試
+// Test for OrderServiceImpl.QuoteRefund
+func TestQuoteRefund(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, env *testEnv)
+		id         uint
+		assertFunc func(t *testing.T, quote *entity.RefundQuote, err error)
+	}{
+		{
+			name: "before registration ends",
+			setup: func(t *testing.T, env *testEnv) {
+				env.paid(t, payment.FakeProvider)
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, quote *entity.RefundQuote, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, quote.OrderID)
+				assert.EqualValues(t, 3000, quote.Amount)
+				assert.EqualValues(t, 100, quote.Percent)
+				require.NotNil(t, quote.Deadline)
+				assert.Equal(t, testNow.AddDate(0, 0, 7), *quote.Deadline)
+			},
+		},
+		{
+			name: "before start",
+			setup: func(t *testing.T, env *testEnv) {
+				env.paid(t, payment.FakeProvider)
+				env.svc.now = func() time.Time { return testNow.AddDate(0, 0, 10) }
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, quote *entity.RefundQuote, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 3000, quote.Paid)
+				assert.EqualValues(t, 1500, quote.Amount)
+				assert.EqualValues(t, 50, quote.Percent)
+			},
+		},
+		{
+			name: "after start",
+			setup: func(t *testing.T, env *testEnv) {
+				env.paid(t, payment.FakeProvider)
+				env.svc.now = func() time.Time { return testNow.AddDate(0, 0, 15) }
+			},
+			id: 1,
+			assertFunc: func(t *testing.T, quote *entity.RefundQuote, err error) {
+				require.NoError(t, err)
+				assert.Zero(t, quote.Amount)
+				assert.Nil(t, quote.Deadline)
+			},
+		},
+		{
+			name: "pending payment",
+			id:   1,
+			assertFunc: func(t *testing.T, quote *entity.RefundQuote, err error) {
+				assert.ErrorIs(t, err, ErrNotRefundable)
+			},
+		},
+		{
+			name: "not found",
+			id:   100,
+			assertFunc: func(t *testing.T, quote *entity.RefundQuote, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, env)
+			}
+			quote, err := env.svc.QuoteRefund(context.Background(), tt.id)
+			tt.assertFunc(t, quote, err)
+		})
+	}
+}
+
+// 退款測試
+// Test for OrderServiceImpl.ProcessRefunds
+func TestProcessRefunds(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		env := newTestEnv(t)
+		ctx := context.Background()
+		env.paid(t, payment.FakeProvider)
+
+		refund, err := env.svc.RefundWithdrawal(ctx, 1, testNow)
+		require.NoError(t, err)
+		require.NotNil(t, refund)
+		assert.EqualValues(t, 3000, refund.Amount)
+
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+
+		refunds, err := env.svc.ListRefunds(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, refunds, 1)
+		assert.Equal(t, entity.RefundStatusSucceeded, refunds[0].Status)
+		assert.Contains(t, refunds[0].ProviderRefundID, "fake_refund_")
+		assert.EqualValues(t, 1, refunds[0].Attempts)
+
+		order, err := env.orders.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderStatusRefunded, order.Status)
+		assert.EqualValues(t, 3000, order.RefundedAmount)
+		assert.Equal(t, []string{event.TypeOrderRefunded}, env.eventTypes(t))
+	})
+
+	t.Run("partial refund", func(t *testing.T) {
+		env := newTestEnv(t)
+		ctx := context.Background()
+		env.paid(t, payment.FakeProvider)
+		env.svc.now = func() time.Time { return testNow.AddDate(0, 0, 10) }
+
+		refund, err := env.svc.RefundWithdrawal(ctx, 1, testNow.AddDate(0, 0, 10))
+		require.NoError(t, err)
+		require.NotNil(t, refund)
+		assert.EqualValues(t, 1500, refund.Amount)
+
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+
+		// 部分退款的訂單維持已付款
+		order, err := env.orders.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderStatusPaid, order.Status)
+		assert.EqualValues(t, 1500, order.RefundedAmount)
+		assert.NotNil(t, order.RefundedAt)
+		assert.Equal(t, []string{event.TypeOrderRefunded}, env.eventTypes(t))
+	})
+
+	t.Run("claimed while refunding", func(t *testing.T) {
+		var env *testEnv
+		var requests []payment.RefundRequest
+		env = newTestEnv(t, refundFuncGateway{refund: func(req payment.RefundRequest) (*payment.RefundResult, error) {
+			requests = append(requests, req)
+
+			// 呼叫金流商期間紀錄已 claim , 不會被其他 instance 取得
+			pending, err := env.orders.ListPendingRefunds(context.Background(), testNow, 10)
+			require.NoError(t, err)
+			assert.Empty(t, pending)
+			return &payment.RefundResult{ID: "re_1"}, nil
+		}})
+		ctx := context.Background()
+		env.paid(t, "refund_func")
+
+		refund, err := env.svc.RefundWithdrawal(ctx, 1, testNow)
+		require.NoError(t, err)
+		require.NotNil(t, refund)
+
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+		require.Len(t, requests, 1)
+		assert.Equal(t, refund.ID, requests[0].RefundID)
+
+		refunds, err := env.svc.ListRefunds(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, refunds, 1)
+		assert.Equal(t, entity.RefundStatusSucceeded, refunds[0].Status)
+		assert.Equal(t, "re_1", refunds[0].ProviderRefundID)
+	})
+
+	t.Run("retry then fail", func(t *testing.T) {
+		env := newTestEnv(t, failingGateway{})
+		ctx := context.Background()
+		env.paid(t, "failing")
+
+		_, err := env.svc.RefundWithdrawal(ctx, 1, testNow)
+		require.NoError(t, err)
+
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+		refunds, err := env.svc.ListRefunds(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, refunds, 1)
+		assert.Equal(t, entity.RefundStatusPending, refunds[0].Status)
+		assert.Equal(t, "connection refused", refunds[0].LastError)
+		assert.Equal(t, testNow.Add(time.Minute), refunds[0].NextAttemptAt)
+
+		// 尚未到下次嘗試時間
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+		refunds, err = env.svc.ListRefunds(ctx, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, refunds[0].Attempts)
+
+		env.svc.now = func() time.Time { return testNow.Add(time.Minute) }
+		require.NoError(t, env.svc.ProcessRefunds(ctx))
+		refunds, err = env.svc.ListRefunds(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RefundStatusFailed, refunds[0].Status)
+		assert.EqualValues(t, 2, refunds[0].Attempts)
+
+		order, err := env.orders.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, entity.OrderStatusPaid, order.Status)
+		assert.Empty(t, env.eventTypes(t))
+	})
+
+	t.Run("no refund after start", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.paid(t, payment.FakeProvider)
+
+		refund, err := env.svc.RefundWithdrawal(context.Background(), 1, testNow.AddDate(0, 0, 15))
+		require.NoError(t, err)
+		assert.Nil(t, refund)
+	})
+}
+
+// 課程取消測試
+// Test for OrderServiceImpl.HandleEvent
+func TestHandleEvent_CourseCancelled(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.paid(t, payment.FakeProvider)
+
+	// 報名 2 (學生 11) 待付款
+	_, err := env.enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: 11, Status: enrollmentEntity.EnrollmentStatusPendingPayment, EnrolledAt: testNow})
+	require.NoError(t, err)
+	_, err = env.orders.Create(ctx, &entity.Order{EnrollmentID: 2, CourseID: 1, StudentID: 11, Amount: 3000, Currency: "TWD", ExpiresAt: testNow.Add(30 * time.Minute)})
+	require.NoError(t, err)
+
+	// 課程開始後取消仍全額退款
+	env.svc.now = func() time.Time { return testNow.AddDate(0, 0, 15) }
+	payload, err := json.Marshal(event.CourseCancelled{CourseID: 1, Name: "資料庫設計", CancelledAt: testNow})
+	require.NoError(t, err)
+	msg := outbox.Message{Type: event.TypeCourseCancelled, Payload: payload}
+	require.NoError(t, env.svc.HandleEvent(ctx, msg))
+
+	order, err := env.orders.GetByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusFailed, order.Status)
+	assert.Equal(t, CourseCancelledReason, order.FailureReason)
+
+	enrollment, err := env.enrollments.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, enrollmentEntity.EnrollmentStatusWithdrawn, enrollment.Status)
+
+	refunds, err := env.svc.ListRefunds(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+	assert.EqualValues(t, 3000, refunds[0].Amount)
+	assert.Equal(t, entity.RefundReasonCourseCancelled, refunds[0].Reason)
+
+	// 重送的事件不會重複退款
+	require.NoError(t, env.svc.HandleEvent(ctx, msg))
+	refunds, err = env.svc.ListRefunds(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, refunds, 1)
+	assert.ElementsMatch(t, []string{event.TypeOrderFailed, event.TypeStudentWithdrawn}, env.eventTypes(t))
+}
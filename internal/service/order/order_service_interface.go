@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/itmrchow/course-management-system/internal/domain/order/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+)
+
+// OrderService 定義訂單付款相關的業務邏輯
+// 負責建立付款、處理金流商的付款結果通知、逾期未付款的訂單 , 以及退款
+//
+// Example:
+//
+//	var svc OrderService
+//	order, err := svc.Pay(ctx, 1)
+//	// 導向 order.CheckoutURL
+type OrderService interface {
+	// GetByID 依訂單ID查詢訂單
+	// 參數: ctx - context, id - 訂單ID
+	// 回傳: 訂單, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByID(ctx context.Context, id uint) (*entity.Order, error)
+
+	// ListByEnrollment 查詢報名的所有訂單
+	// 參數: ctx - context, enrollmentID - 報名ID
+	// 回傳: 訂單 , 新的在前, 錯誤訊息
+	ListByEnrollment(ctx context.Context, enrollmentID uint) ([]*entity.Order, error)
+
+	// Pay 向金流商建立付款 , 訂單須為待付款且未逾期 , 已建立付款時回傳原本的付款頁面
+	// 參數: ctx - context, id - 訂單ID
+	// 回傳: 含付款頁面網址的訂單, 錯誤訊息 , 建立付款失敗時回傳 ErrPaymentProvider
+	Pay(ctx context.Context, id uint) (*entity.Order, error)
+
+	// HandleCallback 處理金流商的付款結果通知 , 相同通知重送時不會重複處理
+	// 付款成功時確認報名 , 付款失敗時釋出報名名額 , 訂單已逾期或金額不符的付款建立全額退款
+	// 參數: ctx - context, provider - 金流商名稱, header - 通知的 header, body - 通知內容
+	// 回傳: 錯誤訊息 , 不支援的金流商回傳 ErrUnknownProvider , 簽章不符回傳 payment.ErrInvalidCallback
+	HandleCallback(ctx context.Context, provider string, header http.Header, body []byte) error
+
+	// ExpirePending 將超過付款期限的待付款訂單標記為付款失敗並釋出報名名額 , 可作為 job.Func
+	// 參數: ctx - context
+	// 回傳: 錯誤訊息
+	ExpirePending(ctx context.Context) error
+
+	// QuoteRefund 計算報名於現在退出課程可退款的金額 , 比例依課程的退款規則
+	// 參數: ctx - context, enrollmentID - 報名ID
+	// 回傳: 可退款金額 , 沒有已付款的訂單時 OrderID 為 0, 錯誤訊息 , 報名不是已報名時回傳 ErrNotRefundable
+	QuoteRefund(ctx context.Context, enrollmentID uint) (*entity.RefundQuote, error)
+
+	// RefundWithdrawal 建立學生退出課程的退款紀錄 , 須於退出課程的 transaction 中呼叫
+	// 參數: ctx - context, enrollmentID - 報名ID, at - 退出時間 , 依此時間的退款規則計算比例
+	// 回傳: 退款紀錄 , 沒有需要退款的金額時為 nil, 錯誤訊息
+	RefundWithdrawal(ctx context.Context, enrollmentID uint, at time.Time) (*entity.Refund, error)
+
+	// ListRefunds 查詢訂單的退款紀錄
+	// 參數: ctx - context, orderID - 訂單ID
+	// 回傳: 退款紀錄, 錯誤訊息 , 訂單不存在時回傳 gorm.ErrRecordNotFound
+	ListRefunds(ctx context.Context, orderID uint) ([]*entity.Refund, error)
+
+	// ProcessRefunds 向金流商退款 , 失敗時依指數退避重試 , 成功時累計訂單的已退款金額 , 全額退款後訂單變更為已退款 , 可作為 job.Func
+	// 參數: ctx - context
+	// 回傳: 錯誤訊息
+	ProcessRefunds(ctx context.Context) error
+
+	// HandleEvent 處理課程取消事件 , 待付款訂單標記為付款失敗 , 已報名的學生退出課程並全額退款 , 可作為 outbox.Handler
+	// 參數: ctx - context, msg - 事件
+	// 回傳: 錯誤訊息
+	HandleEvent(ctx context.Context, msg outbox.Message) error
+}
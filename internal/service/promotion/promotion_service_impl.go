@@ -0,0 +1,334 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/money"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ PromotionService = (*PromotionServiceImpl)(nil)
+
+// maxCodeLength 優惠碼長度上限 , 與 Promotion.Code 欄位長度相同
+const maxCodeLength = 50
+
+var (
+	// ErrInvalidPromotion 促銷活動的內容無效
+	ErrInvalidPromotion = errors.New("invalid promotion")
+	// ErrDuplicateCode 優惠碼已存在
+	ErrDuplicateCode = errors.New("coupon code already exists")
+	// ErrCouponNotFound 優惠碼不存在
+	ErrCouponNotFound = errors.New("coupon not found")
+	// ErrCouponNotApplicable 優惠券已停用、不在活動期間內、不適用於課程或沒有折扣
+	ErrCouponNotApplicable = errors.New("coupon not applicable")
+	// ErrCouponExhausted 促銷活動已達總使用次數上限
+	ErrCouponExhausted = errors.New("coupon usage limit reached")
+	// ErrPerUserLimit 學生已達促銷活動的使用次數上限
+	ErrPerUserLimit = errors.New("coupon per-user limit reached")
+)
+
+// PromotionServiceImpl 實作 PromotionService 介面
+//
+// Example:
+//
+//	svc := promotion.NewPromotionService(promotionRepo.NewPromotionRepository(db), courseRepo.NewCourseRepository(db), enrollmentRepo.NewEnrollmentRepository(db))
+//	pricing, err := svc.Preview(ctx, 1, 10, "SUMMER10")
+type PromotionServiceImpl struct {
+	promotionRepo  promotionRepo.PromotionRepository
+	courseRepo     courseRepo.CourseRepository
+	enrollmentRepo enrollmentRepo.EnrollmentRepository
+	now            func() time.Time
+}
+
+// NewPromotionService 建立促銷活動業務邏輯實例
+// 參數: promotionRepo - 促銷活動資料庫操作實例, courseRepo - 課程資料庫操作實例, enrollmentRepo - 報名資料庫操作實例
+// 回傳: 促銷活動業務邏輯實例
+func NewPromotionService(promotionRepo promotionRepo.PromotionRepository, courseRepo courseRepo.CourseRepository, enrollmentRepo enrollmentRepo.EnrollmentRepository) PromotionService {
+	return &PromotionServiceImpl{
+		promotionRepo:  promotionRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		now:            time.Now,
+	}
+}
+
+// Create 新增促銷活動
+// 百分比折扣須為 1-100 , 固定金額折抵須大於 0 且指定支援的幣別 , 結束時間須晚於開始時間
+func (s *PromotionServiceImpl) Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+	if promotion.Code != nil {
+		code := entity.NormalizeCode(*promotion.Code)
+		if code == "" || len(code) > maxCodeLength {
+			return nil, fmt.Errorf("%w: code must be 1-%d characters", ErrInvalidPromotion, maxCodeLength)
+		}
+		promotion.Code = &code
+	}
+
+	switch promotion.DiscountType {
+	case entity.DiscountTypePercent:
+		if promotion.DiscountValue < 1 || promotion.DiscountValue > 100 {
+			return nil, fmt.Errorf("%w: percent discount must be between 1 and 100", ErrInvalidPromotion)
+		}
+		promotion.Currency = ""
+	case entity.DiscountTypeFixed:
+		if promotion.DiscountValue == 0 {
+			return nil, fmt.Errorf("%w: fixed discount must be greater than 0", ErrInvalidPromotion)
+		}
+		if !money.IsSupported(promotion.Currency) {
+			return nil, fmt.Errorf("%w: %q", money.ErrUnsupportedCurrency, promotion.Currency)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown discount type %d", ErrInvalidPromotion, promotion.DiscountType)
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	promotion.UsedCount = 0
+	promotion.DeactivatedAt = nil
+
+	if _, err := s.promotionRepo.Create(ctx, promotion); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("code %s: %w", promotion.CodeString(), ErrDuplicateCode)
+		}
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Uint("promotion_id", promotion.ID).
+		Str("code", promotion.CodeString()).
+		Stringer("discount_type", promotion.DiscountType).
+		Uint("discount_value", promotion.DiscountValue).
+		Msg("promotion created")
+
+	return promotion, nil
+}
+
+// GetByID 依促銷活動ID查詢促銷活動
+func (s *PromotionServiceImpl) GetByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	promotion, err := s.promotionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion %d: %w", id, err)
+	}
+	return promotion, nil
+}
+
+// List 查詢促銷活動
+func (s *PromotionServiceImpl) List(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Promotion, error) {
+	promotions, err := s.promotionRepo.Find(ctx, pageInfo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+// Deactivate 停用促銷活動
+func (s *PromotionServiceImpl) Deactivate(ctx context.Context, id uint) error {
+	rows, err := s.promotionRepo.Deactivate(ctx, id, s.now())
+	if err != nil {
+		return fmt.Errorf("failed to deactivate promotion %d: %w", id, err)
+	}
+	if rows == 0 {
+		// 不存在時回傳 gorm.ErrRecordNotFound , 已停用時不做任何事
+		if _, err := s.promotionRepo.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to get promotion %d: %w", id, err)
+		}
+		return nil
+	}
+
+	zerolog.Ctx(ctx).Info().Uint("promotion_id", id).Msg("promotion deactivated")
+	return nil
+}
+
+// Price 計算結帳內容套用促銷後的價格
+// 已達使用次數上限的自動促銷直接略過 , 優惠券無法使用時回傳原因
+func (s *PromotionServiceImpl) Price(ctx context.Context, checkout entity.Checkout, code string) (entity.Pricing, error) {
+	automatic, err := s.promotionRepo.ListAutomatic(ctx, checkout.At)
+	if err != nil {
+		return entity.Pricing{}, fmt.Errorf("failed to list automatic promotions: %w", err)
+	}
+	available := make([]*entity.Promotion, 0, len(automatic))
+	for _, promotion := range automatic {
+		err := s.checkUsage(ctx, promotion, checkout.StudentID)
+		switch {
+		case errors.Is(err, ErrCouponExhausted), errors.Is(err, ErrPerUserLimit):
+		case err != nil:
+			return entity.Pricing{}, err
+		default:
+			available = append(available, promotion)
+		}
+	}
+
+	var coupon *entity.Promotion
+	if code = entity.NormalizeCode(code); code != "" {
+		coupon, err = s.promotionRepo.GetByCode(ctx, code)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Pricing{}, fmt.Errorf("code %s: %w", code, ErrCouponNotFound)
+		}
+		if err != nil {
+			return entity.Pricing{}, fmt.Errorf("failed to get coupon %s: %w", code, err)
+		}
+		if err := s.checkUsage(ctx, coupon, checkout.StudentID); err != nil {
+			return entity.Pricing{}, err
+		}
+	}
+
+	pricing := entity.Evaluate(checkout, available, coupon)
+	if coupon != nil && !applied(pricing, coupon.ID) {
+		return entity.Pricing{}, fmt.Errorf("code %s course %d: %w", code, checkout.CourseID, ErrCouponNotApplicable)
+	}
+	return pricing, nil
+}
+
+// Redeem 記錄訂單套用的促銷
+// IncrementUsage 以條件更新檢查總使用次數並鎖定促銷活動 , 鎖定後才計算學生的使用次數 , 併發使用時不會超過上限
+func (s *PromotionServiceImpl) Redeem(ctx context.Context, pricing entity.Pricing, orderID, studentID uint) (err error) {
+	if len(pricing.Discounts) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "PromotionService.Redeem",
+		trace.WithAttributes(
+			attribute.Int("order.id", int(orderID)),
+			attribute.Int("student.id", int(studentID)),
+		))
+	defer tracing.End(span, &err)
+
+	for _, discount := range pricing.Discounts {
+		rows, err := s.promotionRepo.IncrementUsage(ctx, discount.PromotionID)
+		if err != nil {
+			return fmt.Errorf("failed to increment usage of promotion %d: %w", discount.PromotionID, err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("promotion %d: %w", discount.PromotionID, ErrCouponExhausted)
+		}
+
+		promotion, err := s.promotionRepo.GetByID(ctx, discount.PromotionID)
+		if err != nil {
+			return fmt.Errorf("failed to get promotion %d: %w", discount.PromotionID, err)
+		}
+		if promotion.PerUserLimit > 0 {
+			count, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, studentID)
+			if err != nil {
+				return fmt.Errorf("failed to count redemptions of promotion %d: %w", promotion.ID, err)
+			}
+			if count >= int64(promotion.PerUserLimit) {
+				return fmt.Errorf("promotion %d student %d: %w", promotion.ID, studentID, ErrPerUserLimit)
+			}
+		}
+
+		if _, err := s.promotionRepo.CreateRedemption(ctx, &entity.PromotionRedemption{
+			PromotionID: discount.PromotionID,
+			StudentID:   studentID,
+			OrderID:     orderID,
+			Discount:    uint(discount.Amount),
+			Currency:    pricing.ListPrice.Currency,
+		}); err != nil {
+			return fmt.Errorf("failed to create redemption of promotion %d: %w", discount.PromotionID, err)
+		}
+		metrics.PromotionRedemptionsTotal.WithLabelValues(kind(discount.Code), "redeemed").Inc()
+	}
+	return nil
+}
+
+// Release 釋出訂單套用的促銷
+func (s *PromotionServiceImpl) Release(ctx context.Context, orderID uint) error {
+	released, err := s.promotionRepo.ReleaseRedemptions(ctx, orderID, s.now())
+	if err != nil {
+		return fmt.Errorf("failed to release redemptions of order %d: %w", orderID, err)
+	}
+	for _, redemption := range released {
+		promotion, err := s.promotionRepo.GetByID(ctx, redemption.PromotionID)
+		if err != nil {
+			return fmt.Errorf("failed to get promotion %d: %w", redemption.PromotionID, err)
+		}
+		metrics.PromotionRedemptionsTotal.WithLabelValues(kind(promotion.CodeString()), "released").Inc()
+	}
+	return nil
+}
+
+// Preview 預覽學生報名課程時的應付金額
+// 報名人數為目前已報名與待付款的人數加上本次報名
+func (s *PromotionServiceImpl) Preview(ctx context.Context, courseID, studentID uint, code string) (entity.Pricing, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return entity.Pricing{}, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	prices, err := s.courseRepo.ListPrices(ctx, courseID)
+	if err != nil {
+		return entity.Pricing{}, fmt.Errorf("failed to list prices of course %d: %w", courseID, err)
+	}
+	count, err := s.enrollmentRepo.Count(ctx, []func(db *gorm.DB) *gorm.DB{
+		enrollmentEntity.EnrollmentOfCourse(courseID),
+		enrollmentEntity.InEnrollmentStatus([]enrollmentEntity.EnrollmentStatus{enrollmentEntity.EnrollmentStatusEnrolled, enrollmentEntity.EnrollmentStatusPendingPayment}),
+	})
+	if err != nil {
+		return entity.Pricing{}, fmt.Errorf("failed to count enrollments of course %d: %w", courseID, err)
+	}
+
+	now := s.now()
+	return s.Price(ctx, entity.Checkout{
+		CourseID:  courseID,
+		Category:  course.Category,
+		StudentID: studentID,
+		Price:     courseEntity.QuotePrice(course, prices, now).Price,
+		Seats:     uint(count) + 1,
+		At:        now,
+	}, code)
+}
+
+// checkUsage 檢查促銷活動的總使用次數與學生的使用次數是否已達上限
+func (s *PromotionServiceImpl) checkUsage(ctx context.Context, promotion *entity.Promotion, studentID uint) error {
+	if promotion.Exhausted() {
+		return fmt.Errorf("promotion %d: %w", promotion.ID, ErrCouponExhausted)
+	}
+	if promotion.PerUserLimit == 0 {
+		return nil
+	}
+	count, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, studentID)
+	if err != nil {
+		return fmt.Errorf("failed to count redemptions of promotion %d: %w", promotion.ID, err)
+	}
+	if count >= int64(promotion.PerUserLimit) {
+		return fmt.Errorf("promotion %d student %d: %w", promotion.ID, studentID, ErrPerUserLimit)
+	}
+	return nil
+}
+
+// applied 價格是否已套用促銷活動
+func applied(pricing entity.Pricing, promotionID uint) bool {
+	for _, discount := range pricing.Discounts {
+		if discount.PromotionID == promotionID {
+			return true
+		}
+	}
+	return false
+}
+
+// kind 促銷活動類型的 metrics label
+func kind(code string) string {
+	if code == "" {
+		return "automatic"
+	}
+	return "coupon"
+}
@@ -0,0 +1,324 @@
+package promotion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	"github.com/itmrchow/course-management-system/internal/money"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	promotionRepo "github.com/itmrchow/course-management-system/internal/repository/promotion"
+)
+
+var testNow = time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+
+func ptr[T any](v T) *T { return &v }
+
+// newTestService 建立使用記憶體 repository 的促銷活動業務邏輯
+// 課程 1 售價 TWD 3000 , 分類 programming , 已有 2 位學生報名
+func newTestService(t *testing.T) *PromotionServiceImpl {
+	t.Helper()
+	ctx := context.Background()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Category: "programming", Price: 3000, Currency: "TWD", Status: courseEntity.CourseStatusOnline})
+	require.NoError(t, err)
+
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	for _, studentID := range []uint{1, 2} {
+		_, err := enrollments.Create(ctx, &enrollmentEntity.Enrollment{CourseID: 1, StudentID: studentID, Status: enrollmentEntity.EnrollmentStatusEnrolled, EnrolledAt: testNow})
+		require.NoError(t, err)
+	}
+
+	svc := NewPromotionService(promotionRepo.NewPromotionMemoryRepository(), courses, enrollments).(*PromotionServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return svc
+}
+
+func (s *PromotionServiceImpl) mustCreate(t *testing.T, promotions ...*entity.Promotion) {
+	t.Helper()
+
+	for _, promotion := range promotions {
+		_, err := s.Create(context.Background(), promotion)
+		require.NoError(t, err)
+	}
+}
+
+// 新增促銷活動測試
+// Test for PromotionServiceImpl.Create
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		promotion  *entity.Promotion
+		assertFunc func(t *testing.T, promotion *entity.Promotion, err error)
+	}{
+		{
+			name:      "coupon code normalized",
+			promotion: &entity.Promotion{Name: "暑期九折", Code: ptr(" summer10 "), DiscountValue: 10},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "SUMMER10", promotion.CodeString())
+			},
+		},
+		{
+			name:      "duplicate code",
+			promotion: &entity.Promotion{Name: "重複", Code: ptr("Existing"), DiscountValue: 10},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				assert.ErrorIs(t, err, ErrDuplicateCode)
+			},
+		},
+		{
+			name:      "percent out of range",
+			promotion: &entity.Promotion{Name: "超額", DiscountValue: 101},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPromotion)
+			},
+		},
+		{
+			name:      "fixed without currency",
+			promotion: &entity.Promotion{Name: "折 100", DiscountType: entity.DiscountTypeFixed, DiscountValue: 10000},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				assert.ErrorIs(t, err, money.ErrUnsupportedCurrency)
+			},
+		},
+		{
+			name:      "ends before starts",
+			promotion: &entity.Promotion{Name: "期間錯誤", DiscountValue: 10, StartsAt: ptr(testNow), EndsAt: ptr(testNow.Add(-time.Hour))},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPromotion)
+			},
+		},
+		{
+			name:      "empty name",
+			promotion: &entity.Promotion{Name: " ", DiscountValue: 10},
+			assertFunc: func(t *testing.T, promotion *entity.Promotion, err error) {
+				assert.ErrorIs(t, err, ErrInvalidPromotion)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := newTestService(t)
+			svc.mustCreate(t, &entity.Promotion{Name: "既有", Code: ptr("EXISTING"), DiscountValue: 5})
+
+			promotion, err := svc.Create(context.Background(), test.promotion)
+			test.assertFunc(t, promotion, err)
+		})
+	}
+}
+
+// 計算促銷後價格測試
+// Test for PromotionServiceImpl.Price
+func TestPrice(t *testing.T) {
+	checkout := entity.Checkout{CourseID: 1, Category: "programming", StudentID: 10, Price: money.Money{Amount: 3000, Currency: "TWD"}, Seats: 1, At: testNow}
+
+	tests := []struct {
+		name       string
+		promotions []*entity.Promotion
+		checkout   entity.Checkout
+		code       string
+		assertFunc func(t *testing.T, pricing entity.Pricing, err error)
+	}{
+		{
+			name:     "no promotion",
+			checkout: checkout,
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, pricing.Discounts)
+				assert.EqualValues(t, 3000, pricing.Total.Amount)
+			},
+		},
+		{
+			name: "best automatic promotion",
+			promotions: []*entity.Promotion{
+				{Name: "九五折", DiscountValue: 5},
+				{Name: "折 500", DiscountType: entity.DiscountTypeFixed, DiscountValue: 500, Currency: "TWD"},
+				{Name: "美金折抵", DiscountType: entity.DiscountTypeFixed, DiscountValue: 1000, Currency: "USD"},
+			},
+			checkout: checkout,
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				if assert.Len(t, pricing.Discounts, 1) {
+					assert.Equal(t, "折 500", pricing.Discounts[0].Name)
+				}
+				assert.EqualValues(t, 2500, pricing.Total.Amount)
+			},
+		},
+		{
+			name:       "group discount below min seats",
+			promotions: []*entity.Promotion{{Name: "三人團報", DiscountValue: 20, MinSeats: 3}},
+			checkout:   checkout,
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, pricing.Discounts)
+			},
+		},
+		{
+			name:       "group discount",
+			promotions: []*entity.Promotion{{Name: "三人團報", DiscountValue: 20, MinSeats: 3}},
+			checkout:   entity.Checkout{CourseID: 1, StudentID: 10, Price: checkout.Price, Seats: 3, At: testNow},
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 2400, pricing.Total.Amount)
+			},
+		},
+		{
+			name: "coupon applied after automatic promotion",
+			promotions: []*entity.Promotion{
+				{Name: "九折", DiscountValue: 10},
+				{Name: "再九五折", Code: ptr("EXTRA5"), DiscountValue: 5},
+			},
+			checkout: checkout,
+			code:     "extra5",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				require.Len(t, pricing.Discounts, 2)
+				assert.EqualValues(t, 300, pricing.Discounts[0].Amount)
+				assert.Equal(t, "EXTRA5", pricing.Discounts[1].Code)
+				assert.EqualValues(t, 135, pricing.Discounts[1].Amount)
+				assert.EqualValues(t, 2565, pricing.Total.Amount)
+				assert.EqualValues(t, 435, pricing.Discount().Amount)
+			},
+		},
+		{
+			name:       "total not below zero",
+			promotions: []*entity.Promotion{{Name: "折 5000", Code: ptr("BIG"), DiscountType: entity.DiscountTypeFixed, DiscountValue: 5000, Currency: "TWD"}},
+			checkout:   checkout,
+			code:       "BIG",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				require.NoError(t, err)
+				assert.Zero(t, pricing.Total.Amount)
+			},
+		},
+		{
+			name:     "coupon not found",
+			checkout: checkout,
+			code:     "UNKNOWN",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				assert.ErrorIs(t, err, ErrCouponNotFound)
+			},
+		},
+		{
+			name:       "coupon for other course",
+			promotions: []*entity.Promotion{{Name: "課程 2 限定", Code: ptr("C2"), DiscountValue: 10, CourseID: 2}},
+			checkout:   checkout,
+			code:       "C2",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				assert.ErrorIs(t, err, ErrCouponNotApplicable)
+			},
+		},
+		{
+			name:       "coupon expired",
+			promotions: []*entity.Promotion{{Name: "已結束", Code: ptr("OLD"), DiscountValue: 10, EndsAt: ptr(testNow)}},
+			checkout:   checkout,
+			code:       "OLD",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				assert.ErrorIs(t, err, ErrCouponNotApplicable)
+			},
+		},
+		{
+			name:       "coupon on free course",
+			promotions: []*entity.Promotion{{Name: "九折", Code: ptr("SAVE10"), DiscountValue: 10}},
+			checkout:   entity.Checkout{CourseID: 1, StudentID: 10, Price: money.Money{Currency: "TWD"}, Seats: 1, At: testNow},
+			code:       "SAVE10",
+			assertFunc: func(t *testing.T, pricing entity.Pricing, err error) {
+				assert.ErrorIs(t, err, ErrCouponNotApplicable)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := newTestService(t)
+			svc.mustCreate(t, test.promotions...)
+
+			pricing, err := svc.Price(context.Background(), test.checkout, test.code)
+			test.assertFunc(t, pricing, err)
+		})
+	}
+}
+
+// 記錄使用次數測試 , 達總使用次數或每位學生上限後不可再使用 , 釋出後可再使用
+// Test for PromotionServiceImpl.Redeem / PromotionServiceImpl.Release
+func TestRedeem(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	svc.mustCreate(t, &entity.Promotion{Name: "九折", Code: ptr("SAVE10"), DiscountValue: 10, UsageLimit: 2, PerUserLimit: 1})
+
+	checkout := func(studentID uint) entity.Checkout {
+		return entity.Checkout{CourseID: 1, StudentID: studentID, Price: money.Money{Amount: 3000, Currency: "TWD"}, Seats: 1, At: testNow}
+	}
+
+	pricing, err := svc.Price(ctx, checkout(10), "SAVE10")
+	require.NoError(t, err)
+	require.NoError(t, svc.Redeem(ctx, pricing, 100, 10))
+
+	// 每位學生上限
+	_, err = svc.Price(ctx, checkout(10), "SAVE10")
+	assert.ErrorIs(t, err, ErrPerUserLimit)
+
+	pricing, err = svc.Price(ctx, checkout(11), "SAVE10")
+	require.NoError(t, err)
+	require.NoError(t, svc.Redeem(ctx, pricing, 102, 11))
+
+	// 總使用次數上限
+	_, err = svc.Price(ctx, checkout(12), "SAVE10")
+	assert.ErrorIs(t, err, ErrCouponExhausted)
+
+	// 訂單 100 付款失敗後釋出 , 學生 10 可再使用
+	require.NoError(t, svc.Release(ctx, 100))
+	pricing, err = svc.Price(ctx, checkout(10), "SAVE10")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2700, pricing.Total.Amount)
+
+	// 以 Price 檢查後併發使用 , Redeem 於鎖定後再次檢查
+	// 測試未使用 transaction , 失敗時已增加的使用次數不會 rollback , 放在最後
+	require.NoError(t, svc.Redeem(ctx, pricing, 103, 10))
+	assert.ErrorIs(t, svc.Redeem(ctx, pricing, 104, 11), ErrCouponExhausted)
+	require.NoError(t, svc.Release(ctx, 102))
+	assert.ErrorIs(t, svc.Redeem(ctx, pricing, 105, 10), ErrPerUserLimit)
+}
+
+// 結帳預覽測試 , 以課程售價與報名人數 (含本次) 計算
+// Test for PromotionServiceImpl.Preview
+func TestPreview(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	svc.mustCreate(t,
+		&entity.Promotion{Name: "三人團報", DiscountValue: 20, MinSeats: 3},
+		&entity.Promotion{Name: "程式類折 100", Code: ptr("CODE100"), DiscountType: entity.DiscountTypeFixed, DiscountValue: 100, Currency: "TWD", Category: "programming"},
+	)
+
+	pricing, err := svc.Preview(ctx, 1, 10, "code100")
+	require.NoError(t, err)
+	assert.Len(t, pricing.Discounts, 2)
+	assert.EqualValues(t, 3000, pricing.ListPrice.Amount)
+	assert.EqualValues(t, 2300, pricing.Total.Amount)
+
+	_, err = svc.Preview(ctx, 100, 10, "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// 停用促銷活動測試
+// Test for PromotionServiceImpl.Deactivate
+func TestDeactivate(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	svc.mustCreate(t, &entity.Promotion{Name: "九折", Code: ptr("SAVE10"), DiscountValue: 10})
+
+	require.NoError(t, svc.Deactivate(ctx, 1))
+	require.NoError(t, svc.Deactivate(ctx, 1))
+	assert.ErrorIs(t, svc.Deactivate(ctx, 100), gorm.ErrRecordNotFound)
+
+	_, err := svc.Price(ctx, entity.Checkout{CourseID: 1, Price: money.Money{Amount: 3000, Currency: "TWD"}, At: testNow}, "SAVE10")
+	assert.ErrorIs(t, err, ErrCouponNotApplicable)
+}
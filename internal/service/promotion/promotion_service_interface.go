@@ -0,0 +1,58 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/itmrchow/course-management-system/internal/domain/promotion/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// PromotionService 定義促銷活動相關的業務邏輯
+// 負責管理優惠券與自動套用的促銷 , 並於結帳時計算折扣與記錄使用次數
+//
+// Example:
+//
+//	var svc PromotionService
+//	pricing, err := svc.Preview(ctx, 1, 10, "SUMMER10")
+//	fmt.Println(pricing.Total)
+type PromotionService interface {
+	// Create 新增促銷活動 , 優惠碼不分大小寫並以大寫儲存
+	// 參數: ctx - context, promotion - 促銷活動
+	// 回傳: 新增後的促銷活動, 錯誤訊息 , 內容無效時回傳 ErrInvalidPromotion , 優惠碼已存在時回傳 ErrDuplicateCode
+	Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+
+	// GetByID 依促銷活動ID查詢促銷活動
+	// 參數: ctx - context, id - 促銷活動ID
+	// 回傳: 促銷活動, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	GetByID(ctx context.Context, id uint) (*entity.Promotion, error)
+
+	// List 查詢促銷活動
+	// 參數: ctx - context, pageInfo - 分頁與排序
+	// 回傳: 促銷活動, 錯誤訊息
+	List(ctx context.Context, pageInfo *repo.RepoPageInfo) ([]*entity.Promotion, error)
+
+	// Deactivate 停用促銷活動 , 已停用時不做任何事
+	// 參數: ctx - context, id - 促銷活動ID
+	// 回傳: 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	Deactivate(ctx context.Context, id uint) error
+
+	// Price 計算結帳內容套用促銷後的價格 , 不記錄使用次數
+	// 參數: ctx - context, checkout - 結帳內容, code - 優惠碼 , 空字串表示不使用優惠券
+	// 回傳: 套用促銷後的價格, 錯誤訊息 , 優惠券無法使用時回傳 ErrCouponNotFound / ErrCouponNotApplicable / ErrCouponExhausted / ErrPerUserLimit
+	Price(ctx context.Context, checkout entity.Checkout, code string) (entity.Pricing, error)
+
+	// Redeem 記錄訂單套用的促銷 , 使用次數加一並新增使用紀錄 , 須與訂單在同一個 transaction 中呼叫
+	// 參數: ctx - context, pricing - Price 計算的價格, orderID - 訂單ID, studentID - 學生 user ID
+	// 回傳: 錯誤訊息 , 已達使用次數上限時回傳 ErrCouponExhausted 或 ErrPerUserLimit
+	Redeem(ctx context.Context, pricing entity.Pricing, orderID, studentID uint) error
+
+	// Release 釋出訂單套用的促銷 , 扣回使用次數 , 訂單付款失敗或逾期時於同一個 transaction 中呼叫
+	// 參數: ctx - context, orderID - 訂單ID
+	// 回傳: 錯誤訊息
+	Release(ctx context.Context, orderID uint) error
+
+	// Preview 預覽學生報名課程時的應付金額 , 以目前的課程售價與報名人數計算
+	// 參數: ctx - context, courseID - 課程ID, studentID - 學生 user ID, code - 優惠碼 , 空字串表示不使用優惠券
+	// 回傳: 套用促銷後的價格, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	Preview(ctx context.Context, courseID, studentID uint, code string) (entity.Pricing, error)
+}
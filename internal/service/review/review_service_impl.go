@@ -0,0 +1,384 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	reviewRepo "github.com/itmrchow/course-management-system/internal/repository/review"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ ReviewService = (*ReviewServiceImpl)(nil)
+
+var (
+	// ErrNotReviewer 學生不是報名或評價的學生
+	ErrNotReviewer = errors.New("not the reviewer")
+	// ErrNotCompleted 學生已退出或課程尚未結束 , 無法評價
+	ErrNotCompleted = errors.New("course not completed")
+	// ErrAlreadyReviewed 報名已有評價
+	ErrAlreadyReviewed = errors.New("already reviewed")
+	// ErrInvalidRating 評分超出範圍
+	ErrInvalidRating = errors.New("invalid rating")
+	// ErrNotCourseTeacher 評分的教師不是課程的授課教師
+	ErrNotCourseTeacher = errors.New("not a teacher of the course")
+	// ErrInvalidTransition 評價無法轉換至指定的審核狀態
+	ErrInvalidTransition = errors.New("invalid review status transition")
+)
+
+// ReviewServiceImpl 實作 ReviewService 介面
+//
+// Example:
+//
+//	svc := review.NewReviewService(reviewRepo.NewReviewRepository(db), courseRepo.NewCourseRepository(db), teacherRepo.NewTeacherRepository(db), enrollmentRepo.NewEnrollmentRepository(db), outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db))
+//	result, err := svc.ListByCourse(ctx, courseID, pageInfo)
+type ReviewServiceImpl struct {
+	reviewRepo     reviewRepo.ReviewRepository
+	courseRepo     courseRepo.CourseRepository
+	teacherRepo    teacherRepo.TeacherRepository
+	enrollmentRepo enrollmentRepo.EnrollmentRepository
+	outboxRepo     outboxRepo.OutboxRepository
+	transactor     repo.Transactor
+	now            func() time.Time
+}
+
+// NewReviewService 建立課程評價業務邏輯實例
+// 參數: reviewRepo - 課程評價資料庫操作實例, courseRepo - 課程資料庫操作實例, teacherRepo - 教師資料庫操作實例, enrollmentRepo - 報名資料庫操作實例, outboxRepo - 領域事件 outbox, transactor - transaction
+// 回傳: 課程評價業務邏輯實例
+func NewReviewService(reviewRepo reviewRepo.ReviewRepository, courseRepo courseRepo.CourseRepository, teacherRepo teacherRepo.TeacherRepository, enrollmentRepo enrollmentRepo.EnrollmentRepository, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor) ReviewService {
+	return &ReviewServiceImpl{
+		reviewRepo:     reviewRepo,
+		courseRepo:     courseRepo,
+		teacherRepo:    teacherRepo,
+		enrollmentRepo: enrollmentRepo,
+		outboxRepo:     outboxRepo,
+		transactor:     transactor,
+		now:            time.Now,
+	}
+}
+
+// Submit 新增評價 , 評價、教師評分與事件在同一個 transaction 中新增
+// 待審核的評價不計入評分統計 , 不需要調整統計
+func (s *ReviewServiceImpl) Submit(ctx context.Context, enrollmentID, studentID uint, submission Submission) (review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.Submit",
+		trace.WithAttributes(attribute.Int("enrollment.id", int(enrollmentID))))
+	defer tracing.End(span, &err)
+
+	enrollment, err := s.enrollmentRepo.GetByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get enrollment %d: %w", enrollmentID, err)
+	}
+	if enrollment.StudentID != studentID {
+		return nil, nil, fmt.Errorf("student %d of enrollment %d: %w", studentID, enrollmentID, ErrNotReviewer)
+	}
+	if enrollment.Status != enrollmentEntity.EnrollmentStatusEnrolled {
+		return nil, nil, fmt.Errorf("enrollment %d is %s: %w", enrollmentID, enrollment.Status, ErrNotCompleted)
+	}
+	course, err := s.courseRepo.GetByID(ctx, enrollment.CourseID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get course %d: %w", enrollment.CourseID, err)
+	}
+	if course.Status != courseEntity.CourseStatusEnd {
+		return nil, nil, fmt.Errorf("course %d is %s: %w", course.ID, course.Status, ErrNotCompleted)
+	}
+	ratings, err = s.teacherRatings(ctx, course.ID, submission)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	review = &entity.Review{
+		EnrollmentID: enrollment.ID,
+		CourseID:     enrollment.CourseID,
+		StudentID:    studentID,
+		Rating:       submission.Rating,
+		Comment:      submission.Comment,
+		Status:       entity.ReviewStatusPending,
+	}
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.reviewRepo.Create(ctx, review); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("enrollment %d: %w", enrollmentID, ErrAlreadyReviewed)
+			}
+			return fmt.Errorf("failed to create review of enrollment %d: %w", enrollmentID, err)
+		}
+		if err := s.reviewRepo.ReplaceTeacherRatings(ctx, review.ID, ratings); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, s.submitted(review))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return review, ratings, nil
+}
+
+// Update 修改評價
+// 鎖定課程後重新讀取評價 , 與同一門課程的審核及修改依序執行 , 避免以過期的狀態調整評分統計
+func (s *ReviewServiceImpl) Update(ctx context.Context, id, studentID uint, submission Submission) (review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.Update",
+		trace.WithAttributes(attribute.Int("review.id", int(id))))
+	defer tracing.End(span, &err)
+
+	current, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get review %d: %w", id, err)
+	}
+	if current.StudentID != studentID {
+		return nil, nil, fmt.Errorf("student %d of review %d: %w", studentID, id, ErrNotReviewer)
+	}
+	ratings, err = s.teacherRatings(ctx, current.CourseID, submission)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		before, beforeRatings, err := s.lock(ctx, current.CourseID, id)
+		if err != nil {
+			return err
+		}
+
+		affected, err := s.reviewRepo.UpdateContent(ctx, id, before.Status, submission.Rating, submission.Comment)
+		if err != nil {
+			return fmt.Errorf("failed to update review %d: %w", id, err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("review %d: %w", id, gorm.ErrRecordNotFound)
+		}
+		if err := s.reviewRepo.ReplaceTeacherRatings(ctx, id, ratings); err != nil {
+			return err
+		}
+
+		review, err = s.reviewRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get review %d: %w", id, err)
+		}
+		if err := s.adjustRatings(ctx, before, beforeRatings, review, ratings); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, s.submitted(review))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return review, ratings, nil
+}
+
+// Moderate 審核評價 , 審核結果、評分統計與事件在同一個 transaction 中更新
+func (s *ReviewServiceImpl) Moderate(ctx context.Context, id, moderatorID uint, to entity.ReviewStatus, note string) (review *entity.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.Moderate",
+		trace.WithAttributes(attribute.Int("review.id", int(id)), attribute.String("review.status", to.String())))
+	defer tracing.End(span, &err)
+
+	current, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review %d: %w", id, err)
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		before, ratings, err := s.lock(ctx, current.CourseID, id)
+		if err != nil {
+			return err
+		}
+		if !before.Status.CanTransitionTo(to) {
+			return fmt.Errorf("review %d from %s to %s: %w", id, before.Status, to, ErrInvalidTransition)
+		}
+
+		now := s.now()
+		affected, err := s.reviewRepo.UpdateStatus(ctx, id, before.Status, to, moderatorID, note, now)
+		if err != nil {
+			return fmt.Errorf("failed to update status of review %d: %w", id, err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("review %d: %w", id, gorm.ErrRecordNotFound)
+		}
+
+		review, err = s.reviewRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get review %d: %w", id, err)
+		}
+		if err := s.adjustRatings(ctx, before, ratings, review, ratings); err != nil {
+			return err
+		}
+		return s.outboxRepo.Add(ctx, event.ReviewModerated{
+			ReviewID:    review.ID,
+			CourseID:    review.CourseID,
+			StudentID:   review.StudentID,
+			From:        before.Status.String(),
+			To:          review.Status.String(),
+			ModeratorID: moderatorID,
+			ModeratedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// lock 鎖定課程並重新讀取評價與教師評分 , 須於 transaction 中呼叫
+func (s *ReviewServiceImpl) lock(ctx context.Context, courseID, id uint) (*entity.Review, []*entity.ReviewTeacherRating, error) {
+	if _, err := s.courseRepo.LockByID(ctx, courseID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock course %d: %w", courseID, err)
+	}
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get review %d: %w", id, err)
+	}
+	ratings, err := s.reviewRepo.ListTeacherRatings(ctx, []uint{id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list teacher ratings of review %d: %w", id, err)
+	}
+	return review, ratings, nil
+}
+
+// teacherRatings 檢查評分並轉換為教師評分 , 依教師ID排序
+func (s *ReviewServiceImpl) teacherRatings(ctx context.Context, courseID uint, submission Submission) ([]*entity.ReviewTeacherRating, error) {
+	if !entity.ValidRating(submission.Rating) {
+		return nil, fmt.Errorf("course rating %d: %w", submission.Rating, ErrInvalidRating)
+	}
+	if len(submission.TeacherRatings) == 0 {
+		return []*entity.ReviewTeacherRating{}, nil
+	}
+
+	teachers, err := s.courseRepo.ListTeachers(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teachers of course %d: %w", courseID, err)
+	}
+	courseTeachers := make(map[uint]bool, len(teachers))
+	for _, teacher := range teachers {
+		courseTeachers[teacher.TeacherID] = true
+	}
+
+	ratings := make([]*entity.ReviewTeacherRating, 0, len(submission.TeacherRatings))
+	for teacherID, rating := range submission.TeacherRatings {
+		if !courseTeachers[teacherID] {
+			return nil, fmt.Errorf("teacher %d of course %d: %w", teacherID, courseID, ErrNotCourseTeacher)
+		}
+		if !entity.ValidRating(rating) {
+			return nil, fmt.Errorf("teacher %d rating %d: %w", teacherID, rating, ErrInvalidRating)
+		}
+		ratings = append(ratings, &entity.ReviewTeacherRating{TeacherID: teacherID, Rating: rating})
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].TeacherID < ratings[j].TeacherID })
+	return ratings, nil
+}
+
+// ratingDelta 評分統計的增減
+type ratingDelta struct {
+	sum   int
+	count int
+}
+
+// adjustRatings 依評價變更前後的內容調整課程與教師的評分統計 , 只有審核通過的評價計入
+// 教師依ID由小到大更新 , 避免併發的 transaction 以不同順序鎖定教師而 deadlock
+func (s *ReviewServiceImpl) adjustRatings(ctx context.Context, before *entity.Review, beforeRatings []*entity.ReviewTeacherRating, after *entity.Review, afterRatings []*entity.ReviewTeacherRating) error {
+	var course ratingDelta
+	teachers := make(map[uint]ratingDelta)
+	collect := func(review *entity.Review, ratings []*entity.ReviewTeacherRating, sign int) {
+		if review.Status != entity.ReviewStatusApproved {
+			return
+		}
+		course.sum += sign * int(review.Rating)
+		course.count += sign
+		for _, rating := range ratings {
+			d := teachers[rating.TeacherID]
+			d.sum += sign * int(rating.Rating)
+			d.count += sign
+			teachers[rating.TeacherID] = d
+		}
+	}
+	collect(before, beforeRatings, -1)
+	collect(after, afterRatings, 1)
+
+	if course != (ratingDelta{}) {
+		if _, err := s.courseRepo.AddRating(ctx, after.CourseID, course.sum, course.count); err != nil {
+			return fmt.Errorf("failed to update rating of course %d: %w", after.CourseID, err)
+		}
+	}
+
+	teacherIDs := make([]uint, 0, len(teachers))
+	for teacherID, d := range teachers {
+		if d != (ratingDelta{}) {
+			teacherIDs = append(teacherIDs, teacherID)
+		}
+	}
+	sort.Slice(teacherIDs, func(i, j int) bool { return teacherIDs[i] < teacherIDs[j] })
+	for _, teacherID := range teacherIDs {
+		// 教師已刪除時影響數量為 0 , 不影響評價的審核
+		d := teachers[teacherID]
+		if _, err := s.teacherRepo.AddRating(ctx, teacherID, d.sum, d.count); err != nil {
+			return fmt.Errorf("failed to update rating of teacher %d: %w", teacherID, err)
+		}
+	}
+	return nil
+}
+
+func (s *ReviewServiceImpl) submitted(review *entity.Review) event.ReviewSubmitted {
+	return event.ReviewSubmitted{
+		ReviewID:    review.ID,
+		CourseID:    review.CourseID,
+		StudentID:   review.StudentID,
+		Rating:      review.Rating,
+		SubmittedAt: s.now(),
+	}
+}
+
+// Get 依評價ID查詢評價與教師評分
+func (s *ReviewServiceImpl) Get(ctx context.Context, id uint) (*entity.Review, []*entity.ReviewTeacherRating, error) {
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get review %d: %w", id, err)
+	}
+	ratings, err := s.reviewRepo.ListTeacherRatings(ctx, []uint{id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list teacher ratings of review %d: %w", id, err)
+	}
+	return review, ratings, nil
+}
+
+// List 查詢評價清單
+func (s *ReviewServiceImpl) List(ctx context.Context, pageInfo *repo.RepoPageInfo, courseID, studentID uint, statuses []entity.ReviewStatus) ([]*entity.Review, error) {
+	var conditions []func(db *gorm.DB) *gorm.DB
+	if courseID != 0 {
+		conditions = append(conditions, entity.ReviewOfCourse(courseID))
+	}
+	if studentID != 0 {
+		conditions = append(conditions, entity.ReviewOfStudent(studentID))
+	}
+	if len(statuses) > 0 {
+		conditions = append(conditions, entity.InReviewStatus(statuses))
+	}
+	reviews, err := s.reviewRepo.Find(ctx, pageInfo, conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// ListByCourse 查詢課程的評分統計與審核通過的評價
+func (s *ReviewServiceImpl) ListByCourse(ctx context.Context, courseID uint, pageInfo *repo.RepoPageInfo) (*CourseReviews, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course %d: %w", courseID, err)
+	}
+	reviews, err := s.List(ctx, pageInfo, courseID, 0, []entity.ReviewStatus{entity.ReviewStatusApproved})
+	if err != nil {
+		return nil, err
+	}
+	return &CourseReviews{Course: course, Reviews: reviews}, nil
+}
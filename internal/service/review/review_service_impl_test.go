@@ -0,0 +1,280 @@
+package review
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	enrollmentEntity "github.com/itmrchow/course-management-system/internal/domain/enrollment/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepo "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	reviewRepo "github.com/itmrchow/course-management-system/internal/repository/review"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var testNow = time.Date(2025, 9, 1, 4, 0, 0, 0, time.UTC)
+
+type testEnv struct {
+	svc      *ReviewServiceImpl
+	courses  courseRepo.CourseRepository
+	teachers teacherRepo.TeacherRepository
+	outbox   outboxRepo.OutboxRepository
+}
+
+// newTestEnv 建立使用記憶體 repository 的課程評價業務邏輯
+// 課程 1 已結束 , 授課教師為教師 1 與教師 2 ; 課程 2 開放報名中
+// 報名 1 至 3 為課程 1 的學生 10 至 12 , 學生 12 已退出 ; 報名 4 為課程 2 的學生 10
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	teachers := teacherRepo.NewTeacherMemoryRepository()
+	for _, teacher := range []*teacherEntity.Teacher{
+		{UserID: 1, Name: "王小明", Phone: "0912345678", Email: "ming@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 2, Name: "陳助教", Phone: "0912345679", Email: "chen@example.com", Status: teacherEntity.TeacherStatusApproved},
+		{UserID: 3, Name: "林老師", Phone: "0912345670", Email: "lin@example.com", Status: teacherEntity.TeacherStatusApproved},
+	} {
+		_, err := teachers.Create(ctx, teacher)
+		require.NoError(t, err)
+	}
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	_, err := courses.Create(ctx, &courseEntity.Course{Name: "Go 入門", Status: courseEntity.CourseStatusEnd})
+	require.NoError(t, err)
+	_, err = courses.Create(ctx, &courseEntity.Course{Name: "資料庫設計", Status: courseEntity.CourseStatusOnline})
+	require.NoError(t, err)
+	for _, teacherID := range []uint{1, 2} {
+		_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: 1, TeacherID: teacherID, IsMain: teacherID == 1})
+		require.NoError(t, err)
+	}
+
+	enrollments := enrollmentRepo.NewEnrollmentMemoryRepository()
+	for _, e := range []*enrollmentEntity.Enrollment{
+		{CourseID: 1, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 11, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+		{CourseID: 1, StudentID: 12, Status: enrollmentEntity.EnrollmentStatusWithdrawn},
+		{CourseID: 2, StudentID: 10, Status: enrollmentEntity.EnrollmentStatusEnrolled},
+	} {
+		_, err := enrollments.Create(ctx, e)
+		require.NoError(t, err)
+	}
+
+	outbox := outboxRepo.NewOutboxMemoryRepository()
+	svc := NewReviewService(reviewRepo.NewReviewMemoryRepository(), courses, teachers, enrollments, outbox, repository.NoTransaction).(*ReviewServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return &testEnv{svc: svc, courses: courses, teachers: teachers, outbox: outbox}
+}
+
+// 新增評價測試
+// Test for ReviewServiceImpl.Submit
+func TestSubmit(t *testing.T) {
+	tests := []struct {
+		name         string
+		enrollmentID uint
+		studentID    uint
+		submission   Submission
+		setup        func(t *testing.T, e *testEnv)
+		assertFunc   func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error)
+	}{
+		{
+			name:         "評價課程與教師",
+			enrollmentID: 1,
+			studentID:    10,
+			submission:   Submission{Rating: 5, Comment: "內容紮實", TeacherRatings: map[uint]uint{2: 4, 1: 5}},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, review.CourseID)
+				assert.Equal(t, entity.ReviewStatusPending, review.Status)
+				require.Len(t, ratings, 2)
+				assert.EqualValues(t, 1, ratings[0].TeacherID)
+				assert.EqualValues(t, review.ID, ratings[0].ReviewID)
+
+				// 待審核的評價不計入評分統計
+				course, err := e.courses.GetByID(context.Background(), 1)
+				require.NoError(t, err)
+				assert.Zero(t, course.RatingCount)
+
+				events, err := e.outbox.ListPending(context.Background(), time.Now().Add(time.Second), 100)
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				assert.Equal(t, event.TypeReviewSubmitted, events[0].EventType)
+			},
+		},
+		{
+			name:         "已評價",
+			enrollmentID: 1,
+			studentID:    10,
+			submission:   Submission{Rating: 4},
+			setup: func(t *testing.T, e *testEnv) {
+				_, _, err := e.svc.Submit(context.Background(), 1, 10, Submission{Rating: 5})
+				require.NoError(t, err)
+			},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrAlreadyReviewed)
+			},
+		},
+		{
+			name:         "非報名的學生",
+			enrollmentID: 1,
+			studentID:    11,
+			submission:   Submission{Rating: 5},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrNotReviewer)
+			},
+		},
+		{
+			name:         "已退出",
+			enrollmentID: 3,
+			studentID:    12,
+			submission:   Submission{Rating: 5},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrNotCompleted)
+			},
+		},
+		{
+			name:         "課程尚未結束",
+			enrollmentID: 4,
+			studentID:    10,
+			submission:   Submission{Rating: 5},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrNotCompleted)
+			},
+		},
+		{
+			name:         "課程評分超出範圍",
+			enrollmentID: 1,
+			studentID:    10,
+			submission:   Submission{Rating: 6},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRating)
+			},
+		},
+		{
+			name:         "教師評分超出範圍",
+			enrollmentID: 1,
+			studentID:    10,
+			submission:   Submission{Rating: 5, TeacherRatings: map[uint]uint{1: 0}},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRating)
+			},
+		},
+		{
+			name:         "非授課教師",
+			enrollmentID: 1,
+			studentID:    10,
+			submission:   Submission{Rating: 5, TeacherRatings: map[uint]uint{3: 5}},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, ErrNotCourseTeacher)
+			},
+		},
+		{
+			name:         "報名不存在",
+			enrollmentID: 100,
+			studentID:    10,
+			submission:   Submission{Rating: 5},
+			assertFunc: func(t *testing.T, e *testEnv, review *entity.Review, ratings []*entity.ReviewTeacherRating, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+			review, ratings, err := e.svc.Submit(context.Background(), tt.enrollmentID, tt.studentID, tt.submission)
+			tt.assertFunc(t, e, review, ratings, err)
+		})
+	}
+}
+
+// 審核與修改評價時的評分統計
+// Test for ReviewServiceImpl.Moderate and ReviewServiceImpl.Update
+func TestRatingAggregation(t *testing.T) {
+	e := newTestEnv(t)
+	ctx := context.Background()
+
+	assertCourse := func(t *testing.T, count uint, average float64) {
+		t.Helper()
+		course, err := e.courses.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, count, course.RatingCount)
+		assert.InDelta(t, average, course.RatingAverage, 0.0001)
+	}
+	assertTeacher := func(t *testing.T, id, count uint, average float64) {
+		t.Helper()
+		teacher, err := e.teachers.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, count, teacher.RatingCount)
+		assert.InDelta(t, average, teacher.RatingAverage, 0.0001)
+	}
+
+	first, _, err := e.svc.Submit(ctx, 1, 10, Submission{Rating: 5, TeacherRatings: map[uint]uint{1: 5, 2: 3}})
+	require.NoError(t, err)
+	second, _, err := e.svc.Submit(ctx, 2, 11, Submission{Rating: 2, TeacherRatings: map[uint]uint{1: 4}})
+	require.NoError(t, err)
+
+	// 審核通過後計入統計
+	review, err := e.svc.Moderate(ctx, first.ID, 99, entity.ReviewStatusApproved, "")
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusApproved, review.Status)
+	assert.EqualValues(t, 99, review.ModeratorID)
+	_, err = e.svc.Moderate(ctx, second.ID, 99, entity.ReviewStatusApproved, "")
+	require.NoError(t, err)
+	assertCourse(t, 2, 3.5)
+	assertTeacher(t, 1, 2, 4.5)
+	assertTeacher(t, 2, 1, 3)
+
+	// 重複審核通過
+	_, err = e.svc.Moderate(ctx, first.ID, 99, entity.ReviewStatusApproved, "")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assertCourse(t, 2, 3.5)
+
+	// 審核未通過後從統計中移除
+	_, err = e.svc.Moderate(ctx, second.ID, 99, entity.ReviewStatusRejected, "含有不當內容")
+	require.NoError(t, err)
+	assertCourse(t, 1, 5)
+	assertTeacher(t, 1, 1, 5)
+
+	// 修改審核通過的評價後改為待審核 , 從統計中移除 , 重新審核通過後以新的評分計入
+	_, _, err = e.svc.Update(ctx, first.ID, 11, Submission{Rating: 1})
+	assert.ErrorIs(t, err, ErrNotReviewer)
+	review, ratings, err := e.svc.Update(ctx, first.ID, 10, Submission{Rating: 4, Comment: "修改後", TeacherRatings: map[uint]uint{2: 5}})
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReviewStatusPending, review.Status)
+	assert.Equal(t, "修改後", review.Comment)
+	require.Len(t, ratings, 1)
+	assertCourse(t, 0, 0)
+	assertTeacher(t, 1, 0, 0)
+	assertTeacher(t, 2, 0, 0)
+
+	_, err = e.svc.Moderate(ctx, first.ID, 99, entity.ReviewStatusApproved, "")
+	require.NoError(t, err)
+	assertCourse(t, 1, 4)
+	assertTeacher(t, 1, 0, 0)
+	assertTeacher(t, 2, 1, 5)
+
+	// 課程的評價只包含審核通過的評價
+	result, err := e.svc.ListByCourse(ctx, 1, &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"})
+	require.NoError(t, err)
+	assert.InDelta(t, 4, result.Course.RatingAverage, 0.0001)
+	require.Len(t, result.Reviews, 1)
+	assert.Equal(t, first.ID, result.Reviews[0].ID)
+
+	_, err = e.svc.Moderate(ctx, 100, 99, entity.ReviewStatusApproved, "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = e.svc.Moderate(ctx, first.ID, 99, entity.ReviewStatusPending, "")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+}
@@ -0,0 +1,66 @@
+package review
+
+import (
+	"context"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/review/entity"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+)
+
+// Submission 學生送出的評價內容
+type Submission struct {
+	Rating         uint          // 課程評分 , 1 至 5
+	Comment        string        // 評論
+	TeacherRatings map[uint]uint // 教師ID 對應的評分 , 1 至 5 , 須為課程的授課教師 , 可只評分部分教師
+}
+
+// CourseReviews 課程的評分統計與審核通過的評價
+type CourseReviews struct {
+	Course  *courseEntity.Course // 課程 , 包含平均評分與評價數量
+	Reviews []*entity.Review
+}
+
+// ReviewService 定義課程評價相關的業務邏輯
+// 報名課程且課程已結束的學生可評價課程與授課教師 , 每筆報名一則評價 , 新增或修改後須經審核
+// 審核通過的評價計入課程與教師的評分統計 , 統計與評價狀態在同一個 transaction 中更新
+//
+// Example:
+//
+//	var svc ReviewService
+//	review, ratings, err := svc.Submit(ctx, enrollmentID, studentID, Submission{Rating: 5, TeacherRatings: map[uint]uint{1: 5}})
+//	review, err = svc.Moderate(ctx, review.ID, moderatorID, entity.ReviewStatusApproved, "")
+type ReviewService interface {
+	// Submit 新增評價 , 評價為待審核
+	// 參數: ctx - context, enrollmentID - 報名ID, studentID - 評價的學生 user ID , 須為報名的學生, submission - 評價內容
+	// 回傳: 評價, 教師評分, 錯誤訊息 , 報名不存在時回傳 gorm.ErrRecordNotFound , 非報名的學生回傳 ErrNotReviewer ,
+	// 已退出或課程尚未結束回傳 ErrNotCompleted , 已評價回傳 ErrAlreadyReviewed , 評分超出範圍回傳 ErrInvalidRating ,
+	// 評分的教師非授課教師回傳 ErrNotCourseTeacher
+	Submit(ctx context.Context, enrollmentID, studentID uint, submission Submission) (*entity.Review, []*entity.ReviewTeacherRating, error)
+
+	// Update 修改評價 , 修改後改為待審核 , 原本審核通過的評價從評分統計中移除
+	// 參數: ctx - context, id - 評價ID, studentID - 評價的學生 user ID, submission - 評價內容 , 取代原本的內容
+	// 回傳: 評價, 教師評分, 錯誤訊息 , 評價不存在時回傳 gorm.ErrRecordNotFound , 非評價的學生回傳 ErrNotReviewer ,
+	// 評分超出範圍回傳 ErrInvalidRating , 評分的教師非授課教師回傳 ErrNotCourseTeacher
+	Update(ctx context.Context, id, studentID uint, submission Submission) (*entity.Review, []*entity.ReviewTeacherRating, error)
+
+	// Moderate 審核評價 , 已審核的評價可重新審核
+	// 參數: ctx - context, id - 評價ID, moderatorID - 審核的管理者 user ID, to - 審核結果 , 審核通過或未通過, note - 審核備註
+	// 回傳: 審核後的評價, 錯誤訊息 , 評價不存在時回傳 gorm.ErrRecordNotFound , 無法轉換至 to 時回傳 ErrInvalidTransition
+	Moderate(ctx context.Context, id, moderatorID uint, to entity.ReviewStatus, note string) (*entity.Review, error)
+
+	// Get 依評價ID查詢評價與教師評分
+	// 參數: ctx - context, id - 評價ID
+	// 回傳: 評價, 教師評分, 錯誤訊息 , 不存在時回傳 gorm.ErrRecordNotFound
+	Get(ctx context.Context, id uint) (*entity.Review, []*entity.ReviewTeacherRating, error)
+
+	// List 查詢評價清單 , 用於審核與查詢學生的評價
+	// 參數: ctx - context, pageInfo - 分頁與排序, courseID - 課程ID , 0 表示不限, studentID - 學生 user ID , 0 表示不限, statuses - 狀態 , 空切片表示不限
+	// 回傳: 評價, 錯誤訊息
+	List(ctx context.Context, pageInfo *repo.RepoPageInfo, courseID, studentID uint, statuses []entity.ReviewStatus) ([]*entity.Review, error)
+
+	// ListByCourse 查詢課程的評分統計與審核通過的評價
+	// 參數: ctx - context, courseID - 課程ID, pageInfo - 分頁與排序
+	// 回傳: 課程評價, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	ListByCourse(ctx context.Context, courseID uint, pageInfo *repo.RepoPageInfo) (*CourseReviews, error)
+}
@@ -0,0 +1,439 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/meeting"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var _ SessionService = (*SessionServiceImpl)(nil)
+
+// MaxListRange ListByCourse 查詢範圍的上限
+const MaxListRange = 366 * 24 * time.Hour
+
+// maxSessionDuration 單次上課時間的上限 , 與 CoursePattern 跨日的上限相同
+const maxSessionDuration = 24 * time.Hour
+
+var (
+	// ErrInvalidRange 查詢範圍無效或超過 MaxListRange
+	ErrInvalidRange = errors.New("invalid session range")
+	// ErrAlreadyCancelled 上課已取消
+	ErrAlreadyCancelled = errors.New("session already cancelled")
+	// ErrSessionStarted 上課已開始 , 不可改期
+	ErrSessionStarted = errors.New("session already started")
+	// ErrInvalidSchedule 改期的時間已過、結束時間不晚於開始時間或上課超過 24 小時
+	ErrInvalidSchedule = errors.New("invalid session schedule")
+	// ErrSlotTaken 同一上課時段於改期的時間已有上課
+	ErrSlotTaken = errors.New("session slot already taken")
+	// ErrNotCourseTeacher 教師不是課程的授課教師
+	ErrNotCourseTeacher = errors.New("not a teacher of the course")
+	// ErrNoMeeting 上課尚未建立線上會議
+	ErrNoMeeting = errors.New("session has no meeting")
+)
+
+// Config 上課設定
+type Config struct {
+	MeetingLead time.Duration // SyncMeetings 為上課前多久內的上課建立會議
+	BatchSize   int           // SyncMeetings 每次取得的課程數量
+}
+
+// ConfigFromViper 從 viper 讀取上課設定
+func ConfigFromViper() Config {
+	viper.SetDefault("MEETING_SYNC_LEAD", 7*24*time.Hour)
+	viper.SetDefault("MEETING_SYNC_BATCH_SIZE", 100)
+
+	return Config{
+		MeetingLead: viper.GetDuration("MEETING_SYNC_LEAD"),
+		BatchSize:   viper.GetInt("MEETING_SYNC_BATCH_SIZE"),
+	}
+}
+
+// SessionServiceImpl 實作 SessionService 介面
+//
+// Example:
+//
+//	svc := session.NewSessionService(courseRepo.NewCourseRepository(db), sessionRepo.NewSessionRepository(db), teacherRepo.NewTeacherRepository(db), repository.NewTransactor(db), session.ConfigFromViper(), meetings)
+//	err := svc.Cancel(ctx, 1, "颱風停課")
+type SessionServiceImpl struct {
+	courseRepo  courseRepo.CourseRepository
+	sessionRepo sessionRepo.SessionRepository
+	teacherRepo teacherRepo.TeacherRepository
+	transactor  repo.Transactor
+	cfg         Config
+	meetings    meeting.Provider
+	now         func() time.Time
+}
+
+// NewSessionService 建立上課業務邏輯實例
+// 參數: courseRepo - 課程資料庫操作實例, sessionRepo - 上課資料庫操作實例, teacherRepo - 教師資料庫操作實例 , 以操作者的 user ID 查詢教師, transactor - transaction, cfg - 設定, meetings - 線上會議服務
+// 回傳: 上課業務邏輯實例
+func NewSessionService(courseRepo courseRepo.CourseRepository, sessionRepo sessionRepo.SessionRepository, teacherRepo teacherRepo.TeacherRepository, transactor repo.Transactor, cfg Config, meetings meeting.Provider) SessionService {
+	return &SessionServiceImpl{
+		courseRepo:  courseRepo,
+		sessionRepo: sessionRepo,
+		teacherRepo: teacherRepo,
+		transactor:  transactor,
+		cfg:         cfg,
+		meetings:    meetings,
+		now:         time.Now,
+	}
+}
+
+// ListByCourse 查詢課程的上課 , 先寫入範圍內尚未寫入的上課 , 讓每次上課都有ID可供取消
+// 線上課程於 transaction 結束後為 Config.MeetingLead 內尚未結束的上課建立會議 , 建立失敗只記錄 log
+// 之後的上課於進入 MeetingLead 後由 SyncMeetings 建立會議 , 查詢較長的範圍時不會同步建立大量會議
+func (s *SessionServiceImpl) ListByCourse(ctx context.Context, courseID uint, from, to time.Time) ([]*courseEntity.CourseSession, error) {
+	if to.Before(from) || to.Sub(from) > MaxListRange {
+		return nil, fmt.Errorf("%w: %s ~ %s", ErrInvalidRange, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	var (
+		course   *courseEntity.Course
+		sessions []*courseEntity.CourseSession
+	)
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		course, err = s.courseRepo.GetByID(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", courseID, err)
+		}
+		patterns, err := s.courseRepo.ListPatterns(ctx, courseID)
+		if err != nil {
+			return fmt.Errorf("failed to list patterns of course %d: %w", courseID, err)
+		}
+		if _, err := s.sessionRepo.Ensure(ctx, courseEntity.DeriveSessions(course, patterns, from, to)...); err != nil {
+			return fmt.Errorf("failed to ensure sessions of course %d: %w", courseID, err)
+		}
+
+		sessions, err = s.sessionRepo.Find(ctx,
+			&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"},
+			[]func(db *gorm.DB) *gorm.DB{
+				courseEntity.SessionOfCourse(courseID),
+				courseEntity.SessionStartBetween(from, to),
+			})
+		if err != nil {
+			return fmt.Errorf("failed to find sessions of course %d: %w", courseID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if course.IsOnline {
+		lead := s.now().Add(s.cfg.MeetingLead)
+		upcoming := make([]*courseEntity.CourseSession, 0, len(sessions))
+		for _, session := range sessions {
+			if !session.StartAt.After(lead) {
+				upcoming = append(upcoming, session)
+			}
+		}
+		if err := s.ensureMeetings(ctx, course, upcoming); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Uint("course_id", courseID).Msg("failed to create meetings")
+		}
+	}
+	return sessions, nil
+}
+
+// Cancel 取消上課 , 只有預定的上課可以取消 , 已建立的會議於取消後一併取消 , 取消會議失敗只記錄 log
+func (s *SessionServiceImpl) Cancel(ctx context.Context, id uint, reason string) error {
+	rowsAffected, err := s.sessionRepo.Cancel(ctx, id, reason, s.now())
+	if err != nil {
+		return fmt.Errorf("failed to cancel session %d: %w", id, err)
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get session %d: %w", id, err)
+	}
+	if rowsAffected == 0 {
+		// 沒有更新時區分上課不存在與已取消
+		return fmt.Errorf("session %d: %w", id, ErrAlreadyCancelled)
+	}
+
+	s.cancelMeeting(ctx, session)
+	return nil
+}
+
+// Reschedule 改期上課 , 於 transaction 中取消原本的上課並新增改期後的上課
+// 改期後的上課沿用原本的會議 , transaction 結束後更新會議時間 ; 原本沒有會議的線上課程則建立會議
+func (s *SessionServiceImpl) Reschedule(ctx context.Context, id uint, startAt, endAt time.Time) (*courseEntity.CourseSession, error) {
+	now := s.now()
+	startAt, endAt = startAt.UTC(), endAt.UTC()
+	if !startAt.After(now) || !endAt.After(startAt) || endAt.Sub(startAt) > maxSessionDuration {
+		return nil, fmt.Errorf("%w: %s ~ %s", ErrInvalidSchedule, startAt.Format(time.RFC3339), endAt.Format(time.RFC3339))
+	}
+
+	var (
+		course      *courseEntity.Course
+		original    *courseEntity.CourseSession
+		rescheduled *courseEntity.CourseSession
+	)
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		original, err = s.sessionRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get session %d: %w", id, err)
+		}
+		if original.Status == courseEntity.SessionStatusCancelled {
+			return fmt.Errorf("session %d: %w", id, ErrAlreadyCancelled)
+		}
+		if !original.StartAt.After(now) {
+			return fmt.Errorf("session %d started at %s: %w", id, original.StartAt.Format(time.RFC3339), ErrSessionStarted)
+		}
+
+		course, err = s.courseRepo.GetByID(ctx, original.CourseID)
+		if err != nil {
+			return fmt.Errorf("failed to get course %d: %w", original.CourseID, err)
+		}
+		if err := s.checkSlot(ctx, course, original.PatternID, startAt); err != nil {
+			return err
+		}
+
+		rowsAffected, err := s.sessionRepo.Cancel(ctx, id, "rescheduled to "+startAt.Format(time.RFC3339), now)
+		if err != nil {
+			return fmt.Errorf("failed to cancel session %d: %w", id, err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("session %d: %w", id, ErrAlreadyCancelled)
+		}
+
+		rescheduled = &courseEntity.CourseSession{
+			CourseID:          original.CourseID,
+			PatternID:         original.PatternID,
+			StartAt:           startAt,
+			EndAt:             endAt,
+			RescheduledFromID: id,
+		}
+		if original.HasMeeting() && original.MeetingProvider == s.meetings.Name() {
+			rescheduled.MeetingProvider = original.MeetingProvider
+			rescheduled.MeetingID = original.MeetingID
+			rescheduled.JoinURL = original.JoinURL
+			rescheduled.HostKey = original.HostKey
+		}
+		count, err := s.sessionRepo.Ensure(ctx, rescheduled)
+		if err != nil {
+			return fmt.Errorf("failed to create rescheduled session of %d: %w", id, err)
+		}
+		if count == 0 {
+			return fmt.Errorf("pattern %d at %s: %w", original.PatternID, startAt.Format(time.RFC3339), ErrSlotTaken)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx).With().Uint("session_id", id).Uint("rescheduled_id", rescheduled.ID).Logger()
+	switch {
+	case rescheduled.HasMeeting():
+		if err := s.meetings.UpdateMeeting(ctx, rescheduled.MeetingID, meetingRequest(course, rescheduled)); err != nil {
+			logger.Warn().Err(err).Str("meeting_id", rescheduled.MeetingID).Msg("failed to update meeting")
+		}
+	case original.HasMeeting():
+		// 原本的會議由其他會議服務建立 , 無法沿用
+		logger.Warn().Str("meeting_provider", original.MeetingProvider).Msg("meeting provider changed, original meeting not cancelled")
+		fallthrough
+	case course.IsOnline:
+		if err := s.ensureMeetings(ctx, course, []*courseEntity.CourseSession{rescheduled}); err != nil {
+			logger.Warn().Err(err).Msg("failed to create meeting")
+		}
+	}
+
+	logger.Info().Time("start_at", startAt).Time("end_at", endAt).Msg("session rescheduled")
+	return rescheduled, nil
+}
+
+// GetMeeting 授課教師查詢上課的線上會議 , 以 user 對應的教師確認為授課教師
+func (s *SessionServiceImpl) GetMeeting(ctx context.Context, id, userID uint) (*courseEntity.CourseSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %d: %w", id, err)
+	}
+
+	found, err := s.teacherRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: 1, Sort: "id", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{teacherEntity.TeacherOfUser(userID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find teacher of user %d: %w", userID, err)
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("user %d of course %d: %w", userID, session.CourseID, ErrNotCourseTeacher)
+	}
+
+	teachers, err := s.courseRepo.ListTeachers(ctx, session.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teachers of course %d: %w", session.CourseID, err)
+	}
+	if !isTeacher(teachers, found[0].ID) {
+		return nil, fmt.Errorf("teacher %d of course %d: %w", found[0].ID, session.CourseID, ErrNotCourseTeacher)
+	}
+	if !session.HasMeeting() {
+		return nil, fmt.Errorf("session %d: %w", id, ErrNoMeeting)
+	}
+	return session, nil
+}
+
+// SyncMeetings 為即將開始的線上課程上課建立會議
+func (s *SessionServiceImpl) SyncMeetings(ctx context.Context) error {
+	now := s.now()
+	to := now.Add(s.cfg.MeetingLead)
+	conditions := []func(db *gorm.DB) *gorm.DB{
+		courseEntity.InCourseStatus([]courseEntity.CourseStatus{courseEntity.CourseStatusOnline, courseEntity.CourseStatusPause}),
+		courseEntity.IsOnlineCourse(true),
+		courseEntity.RunningBetween(now, to),
+	}
+
+	var errs []error
+	for page := 1; ctx.Err() == nil; page++ {
+		courses, err := s.courseRepo.Find(ctx, &repo.RepoPageInfo{Page: page, PageSize: s.cfg.BatchSize, Sort: "id", Order: "asc"}, conditions)
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("failed to find courses: %w", err))...)
+		}
+
+		for _, course := range courses {
+			if err := s.syncCourseMeetings(ctx, course, now, to); err != nil {
+				errs = append(errs, fmt.Errorf("course %d: %w", course.ID, err))
+			}
+		}
+		if len(courses) < s.cfg.BatchSize {
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(append(errs, ctx.Err())...)
+}
+
+// syncCourseMeetings 寫入課程於 from ~ to 開始的上課 , 並為尚未結束且沒有會議的預定上課建立會議
+func (s *SessionServiceImpl) syncCourseMeetings(ctx context.Context, course *courseEntity.Course, from, to time.Time) error {
+	patterns, err := s.courseRepo.ListPatterns(ctx, course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list patterns: %w", err)
+	}
+	if _, err := s.sessionRepo.Ensure(ctx, courseEntity.DeriveSessions(course, patterns, from, to)...); err != nil {
+		return fmt.Errorf("failed to ensure sessions: %w", err)
+	}
+
+	sessions, err := s.sessionRepo.Find(ctx,
+		&repo.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"},
+		[]func(db *gorm.DB) *gorm.DB{
+			courseEntity.SessionOfCourse(course.ID),
+			courseEntity.InSessionStatus([]courseEntity.SessionStatus{courseEntity.SessionStatusScheduled}),
+			courseEntity.SessionWithoutMeeting(),
+			courseEntity.SessionStartBetween(from.Add(-maxSessionDuration), to),
+			courseEntity.SessionEndAfter(from),
+		})
+	if err != nil {
+		return fmt.Errorf("failed to find sessions without meeting: %w", err)
+	}
+	return s.ensureMeetings(ctx, course, sessions)
+}
+
+// ensureMeetings 為尚未結束且沒有會議的預定上課建立會議 , 並寫入 sessions
+// 上課已取消或已由其他請求建立會議時取消本次建立的會議 , 單一上課失敗時仍會處理其他上課
+func (s *SessionServiceImpl) ensureMeetings(ctx context.Context, course *courseEntity.Course, sessions []*courseEntity.CourseSession) error {
+	now := s.now()
+
+	var errs []error
+	for _, session := range sessions {
+		if session.Status != courseEntity.SessionStatusScheduled || session.HasMeeting() || !session.EndAt.After(now) {
+			continue
+		}
+
+		m, err := s.meetings.CreateMeeting(ctx, meetingRequest(course, session))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create meeting of session %d: %w", session.ID, err))
+			continue
+		}
+
+		withMeeting := *session
+		withMeeting.MeetingProvider = s.meetings.Name()
+		withMeeting.MeetingID = m.ID
+		withMeeting.JoinURL = m.JoinURL
+		withMeeting.HostKey = m.HostKey
+		rowsAffected, err := s.sessionRepo.SetMeeting(ctx, &withMeeting)
+		if err == nil && rowsAffected > 0 {
+			*session = withMeeting
+			zerolog.Ctx(ctx).Info().
+				Uint("session_id", session.ID).
+				Str("meeting_provider", session.MeetingProvider).
+				Str("meeting_id", session.MeetingID).
+				Msg("meeting created")
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to set meeting of session %d: %w", session.ID, err))
+		}
+		if cancelErr := s.meetings.CancelMeeting(ctx, m.ID); cancelErr != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel unused meeting %s of session %d: %w", m.ID, session.ID, cancelErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cancelMeeting 取消已取消上課的會議 , 失敗只記錄 log
+func (s *SessionServiceImpl) cancelMeeting(ctx context.Context, session *courseEntity.CourseSession) {
+	if !session.HasMeeting() {
+		return
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Uint("session_id", session.ID).
+		Str("meeting_provider", session.MeetingProvider).
+		Str("meeting_id", session.MeetingID).
+		Logger()
+	if session.MeetingProvider != s.meetings.Name() {
+		logger.Warn().Msg("meeting provider changed, meeting not cancelled")
+		return
+	}
+	if err := s.meetings.CancelMeeting(ctx, session.MeetingID); err != nil {
+		logger.Warn().Err(err).Msg("failed to cancel meeting")
+		return
+	}
+	logger.Info().Msg("meeting cancelled")
+}
+
+// checkSlot 檢查上課時段於 startAt 是否已有上課 , 包含已寫入與尚未寫入的上課
+func (s *SessionServiceImpl) checkSlot(ctx context.Context, course *courseEntity.Course, patternID uint, startAt time.Time) error {
+	patterns, err := s.courseRepo.ListPatterns(ctx, course.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list patterns of course %d: %w", course.ID, err)
+	}
+	for _, pattern := range patterns {
+		if pattern.ID != patternID {
+			continue
+		}
+		if derived := courseEntity.DeriveSessions(course, []*courseEntity.CoursePattern{pattern}, startAt, startAt); len(derived) > 0 {
+			return fmt.Errorf("pattern %d at %s: %w", patternID, startAt.Format(time.RFC3339), ErrSlotTaken)
+		}
+	}
+	return nil
+}
+
+// meetingRequest 上課的會議內容 , 主題為課程名稱
+func meetingRequest(course *courseEntity.Course, session *courseEntity.CourseSession) meeting.Request {
+	return meeting.Request{Topic: course.Name, StartAt: session.StartAt, EndAt: session.EndAt}
+}
+
+// isTeacher 教師是否為課程的授課教師
+func isTeacher(teachers []*courseEntity.CourseTeacher, teacherID uint) bool {
+	for _, teacher := range teachers {
+		if teacher.TeacherID == teacherID {
+			return true
+		}
+	}
+	return false
+}
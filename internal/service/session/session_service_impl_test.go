@@ -0,0 +1,355 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+	teacherEntity "github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/meeting"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	courseRepo "github.com/itmrchow/course-management-system/internal/repository/course"
+	sessionRepo "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+var testNow = time.Date(2025, 7, 21, 0, 0, 0, 0, time.UTC) // 星期一
+
+// newTestService 建立使用記憶體 repository 與本機模擬會議服務的上課業務邏輯
+// 課程 1 於 2025-07-21 ~ 2025-08-03 每週一 11:00 ~ 13:00 及每週三 23:00 ~ 隔天 01:00 上課
+// 課程 2 為線上課程 , 於同一期間每週一 11:00 ~ 13:00 上課 (上課時段 3) ; 教師 1 (user 100) 為兩門課程的授課教師 , 教師 2 (user 101) 不是
+func newTestService(t *testing.T) *SessionServiceImpl {
+	t.Helper()
+	ctx := context.Background()
+
+	courses := courseRepo.NewCourseMemoryRepository()
+	for _, course := range []*courseEntity.Course{
+		{Name: "Go 入門"},
+		{Name: "線上 Go 入門", IsOnline: true},
+	} {
+		course.Status = courseEntity.CourseStatusOnline
+		course.StartDate = testNow
+		course.EndDate = time.Date(2025, 8, 3, 23, 59, 59, 0, time.UTC)
+		_, err := courses.Create(ctx, course)
+		require.NoError(t, err)
+
+		_, err = courses.AddTeacher(ctx, &courseEntity.CourseTeacher{CourseID: course.ID, TeacherID: 1, IsMain: true})
+		require.NoError(t, err)
+	}
+	for _, pattern := range []*courseEntity.CoursePattern{
+		{CourseID: 1, DayOfWeek: uint(time.Monday), StartTime: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{CourseID: 1, DayOfWeek: uint(time.Wednesday), StartTime: time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)},
+		{CourseID: 2, DayOfWeek: uint(time.Monday), StartTime: time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)},
+	} {
+		_, err := courses.CreatePattern(ctx, pattern)
+		require.NoError(t, err)
+	}
+
+	teachers := teacherRepo.NewTeacherMemoryRepository()
+	for _, userID := range []uint{100, 101} {
+		_, err := teachers.Create(ctx, &teacherEntity.Teacher{UserID: userID, Name: fmt.Sprintf("teacher %d", userID),
+			Phone: fmt.Sprintf("09%08d", userID), Email: fmt.Sprintf("teacher%d@example.com", userID), Status: teacherEntity.TeacherStatusApproved})
+		require.NoError(t, err)
+	}
+
+	svc := NewSessionService(courses, sessionRepo.NewSessionMemoryRepository(), teachers, repository.NoTransaction,
+		Config{MeetingLead: 7 * 24 * time.Hour, BatchSize: 1}, meeting.NewFakeMeeting(meeting.FakeConfig{BaseURL: "http://localhost:8080"})).(*SessionServiceImpl)
+	svc.now = func() time.Time { return testNow }
+	return svc
+}
+
+// 查詢課程的上課測試
+// Test for SessionServiceImpl.ListByCourse
+func TestListByCourse(t *testing.T) {
+	type args struct {
+		courseID uint
+		from, to time.Time
+	}
+	tests := []struct {
+		name       string
+		args       args
+		assertFunc func(t *testing.T, sessions []*courseEntity.CourseSession, err error)
+	}{
+		{
+			name: "whole course",
+			args: args{courseID: 1, from: testNow.AddDate(0, -1, 0), to: testNow.AddDate(0, 2, 0)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				require.NoError(t, err)
+				require.Len(t, sessions, 4)
+				assert.True(t, sessions[0].StartAt.Equal(time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC)))
+				// 跨日的上課
+				assert.True(t, sessions[1].StartAt.Equal(time.Date(2025, 7, 23, 23, 0, 0, 0, time.UTC)))
+				assert.True(t, sessions[1].EndAt.Equal(time.Date(2025, 7, 24, 1, 0, 0, 0, time.UTC)))
+				assert.True(t, sessions[2].StartAt.Equal(time.Date(2025, 7, 28, 11, 0, 0, 0, time.UTC)))
+				assert.True(t, sessions[3].StartAt.Equal(time.Date(2025, 7, 30, 23, 0, 0, 0, time.UTC)))
+				for _, session := range sessions {
+					assert.NotZero(t, session.ID)
+					assert.Equal(t, courseEntity.SessionStatusScheduled, session.Status)
+				}
+			},
+		},
+		{
+			name: "range boundaries inclusive",
+			args: args{courseID: 1, from: time.Date(2025, 7, 21, 11, 0, 0, 0, time.UTC), to: time.Date(2025, 7, 28, 11, 0, 0, 0, time.UTC)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				require.NoError(t, err)
+				assert.Len(t, sessions, 3)
+			},
+		},
+		{
+			name: "course not found",
+			args: args{courseID: 100, from: testNow, to: testNow.AddDate(0, 1, 0)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "range too large",
+			args: args{courseID: 1, from: testNow, to: testNow.AddDate(2, 0, 0)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRange)
+			},
+		},
+		{
+			name: "to before from",
+			args: args{courseID: 1, from: testNow, to: testNow.Add(-time.Hour)},
+			assertFunc: func(t *testing.T, sessions []*courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidRange)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			sessions, err := svc.ListByCourse(context.Background(), tt.args.courseID, tt.args.from, tt.args.to)
+			tt.assertFunc(t, sessions, err)
+		})
+	}
+}
+
+// 重複查詢不會重複寫入上課 , 已取消的上課維持取消
+// Test for SessionServiceImpl.ListByCourse / Cancel
+func TestCancel(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	sessions, err := svc.ListByCourse(ctx, 1, testNow, testNow.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	require.NoError(t, svc.Cancel(ctx, sessions[1].ID, "講師請假"))
+	assert.ErrorIs(t, svc.Cancel(ctx, sessions[1].ID, "講師請假"), ErrAlreadyCancelled)
+	assert.ErrorIs(t, svc.Cancel(ctx, 100, ""), gorm.ErrRecordNotFound)
+
+	sessions, err = svc.ListByCourse(ctx, 1, testNow, testNow.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, sessions, 4)
+	assert.Equal(t, courseEntity.SessionStatusCancelled, sessions[1].Status)
+	assert.Equal(t, "講師請假", sessions[1].CancelReason)
+	assert.Equal(t, testNow, *sessions[1].CancelledAt)
+}
+
+// 線上課程的上課寫入後為 MeetingLead 內的上課建立會議 , 重複查詢不重複建立 , 取消上課時取消會議
+// Test for SessionServiceImpl.ListByCourse / Cancel
+func TestOnlineMeetings(t *testing.T) {
+	svc := newTestService(t)
+	fake := svc.meetings.(*meeting.FakeMeeting)
+	ctx := context.Background()
+
+	sessions, err := svc.ListByCourse(ctx, 2, testNow, testNow.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	session := sessions[0]
+	require.True(t, session.HasMeeting())
+	assert.Equal(t, meeting.FakeProvider, session.MeetingProvider)
+	assert.Equal(t, "http://localhost:8080/fake-meetings/"+session.MeetingID, session.JoinURL)
+	assert.Len(t, session.HostKey, 6)
+
+	req, ok := fake.Get(session.MeetingID)
+	require.True(t, ok)
+	assert.Equal(t, "線上 Go 入門", req.Topic)
+	assert.True(t, req.StartAt.Equal(session.StartAt))
+
+	// 07-28 的上課超過 MeetingLead , 由 SyncMeetings 建立會議
+	assert.False(t, sessions[1].HasMeeting())
+
+	again, err := svc.ListByCourse(ctx, 2, testNow, testNow.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	assert.Equal(t, sessions[0].MeetingID, again[0].MeetingID)
+	assert.False(t, again[1].HasMeeting())
+
+	// 進入 MeetingLead 後查詢時建立會議
+	svc.now = func() time.Time { return testNow.AddDate(0, 0, 2) }
+	again, err = svc.ListByCourse(ctx, 2, testNow, testNow.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	assert.True(t, again[1].HasMeeting())
+	svc.now = func() time.Time { return testNow }
+
+	require.NoError(t, svc.Cancel(ctx, sessions[0].ID, "講師請假"))
+	_, ok = fake.Get(sessions[0].MeetingID)
+	assert.False(t, ok)
+
+	// 非線上課程不建立會議
+	sessions, err = svc.ListByCourse(ctx, 1, testNow, testNow.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.NotEmpty(t, sessions)
+	assert.False(t, sessions[0].HasMeeting())
+}
+
+// 改期上課測試 , 課程 2 於 2025-07-21 與 07-28 11:00 上課
+// Test for SessionServiceImpl.Reschedule
+func TestReschedule(t *testing.T) {
+	tuesday := time.Date(2025, 7, 22, 11, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		startAt    time.Time
+		endAt      time.Time
+		cancelled  bool
+		assertFunc func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error)
+	}{
+		{
+			name:    "沿用會議並更新會議時間",
+			startAt: tuesday,
+			endAt:   tuesday.Add(2 * time.Hour),
+			assertFunc: func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, original.ID, rescheduled.RescheduledFromID)
+				assert.Equal(t, original.PatternID, rescheduled.PatternID)
+				assert.True(t, rescheduled.StartAt.Equal(tuesday))
+				assert.Equal(t, original.MeetingID, rescheduled.MeetingID)
+				assert.Equal(t, original.JoinURL, rescheduled.JoinURL)
+
+				req, ok := svc.meetings.(*meeting.FakeMeeting).Get(rescheduled.MeetingID)
+				require.True(t, ok)
+				assert.True(t, req.StartAt.Equal(tuesday))
+
+				// 原本的上課已取消且不會再被展開
+				sessions, err := svc.ListByCourse(context.Background(), 2, testNow, testNow.AddDate(0, 0, 14))
+				require.NoError(t, err)
+				require.Len(t, sessions, 3)
+				assert.Equal(t, courseEntity.SessionStatusCancelled, sessions[0].Status)
+				assert.Equal(t, "rescheduled to 2025-07-22T11:00:00Z", sessions[0].CancelReason)
+				assert.Equal(t, rescheduled.ID, sessions[1].ID)
+			},
+		},
+		{
+			name:    "同一上課時段已有上課",
+			startAt: time.Date(2025, 7, 28, 11, 0, 0, 0, time.UTC),
+			endAt:   time.Date(2025, 7, 28, 13, 0, 0, 0, time.UTC),
+			assertFunc: func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrSlotTaken)
+			},
+		},
+		{
+			name:    "改至過去的時間",
+			startAt: testNow.Add(-time.Hour),
+			endAt:   testNow.Add(time.Hour),
+			assertFunc: func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+			},
+		},
+		{
+			name:    "上課超過 24 小時",
+			startAt: tuesday,
+			endAt:   tuesday.Add(25 * time.Hour),
+			assertFunc: func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+			},
+		},
+		{
+			name:      "已取消的上課",
+			startAt:   tuesday,
+			endAt:     tuesday.Add(2 * time.Hour),
+			cancelled: true,
+			assertFunc: func(t *testing.T, svc *SessionServiceImpl, original, rescheduled *courseEntity.CourseSession, err error) {
+				assert.ErrorIs(t, err, ErrAlreadyCancelled)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(t)
+			ctx := context.Background()
+			sessions, err := svc.ListByCourse(ctx, 2, testNow, testNow.AddDate(0, 0, 14))
+			require.NoError(t, err)
+			original := sessions[0]
+			if tt.cancelled {
+				require.NoError(t, svc.Cancel(ctx, original.ID, ""))
+			}
+
+			rescheduled, err := svc.Reschedule(ctx, original.ID, tt.startAt, tt.endAt)
+			tt.assertFunc(t, svc, original, rescheduled, err)
+		})
+	}
+}
+
+// 已開始的上課不可改期
+// Test for SessionServiceImpl.Reschedule
+func TestRescheduleStarted(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	sessions, err := svc.ListByCourse(ctx, 1, testNow, testNow.AddDate(0, 0, 7))
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return sessions[0].StartAt }
+	_, err = svc.Reschedule(ctx, sessions[0].ID, testNow.AddDate(0, 0, 1), testNow.AddDate(0, 0, 1).Add(time.Hour))
+	assert.ErrorIs(t, err, ErrSessionStarted)
+
+	_, err = svc.Reschedule(ctx, 100, testNow.AddDate(0, 0, 1), testNow.AddDate(0, 0, 1).Add(time.Hour))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// 授課教師查詢上課的會議
+// Test for SessionServiceImpl.GetMeeting
+func TestGetMeeting(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+	online, err := svc.ListByCourse(ctx, 2, testNow, testNow.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	offline, err := svc.ListByCourse(ctx, 1, testNow, testNow.AddDate(0, 0, 7))
+	require.NoError(t, err)
+
+	session, err := svc.GetMeeting(ctx, online[0].ID, 100)
+	require.NoError(t, err)
+	assert.Equal(t, online[0].HostKey, session.HostKey)
+
+	// 教師 2 不是授課教師 , user 200 不是教師
+	_, err = svc.GetMeeting(ctx, online[0].ID, 101)
+	assert.ErrorIs(t, err, ErrNotCourseTeacher)
+	_, err = svc.GetMeeting(ctx, online[0].ID, 200)
+	assert.ErrorIs(t, err, ErrNotCourseTeacher)
+	_, err = svc.GetMeeting(ctx, offline[0].ID, 100)
+	assert.ErrorIs(t, err, ErrNoMeeting)
+	_, err = svc.GetMeeting(ctx, 100, 100)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+// 為線上課程於 MeetingLead 內的上課建立會議
+// Test for SessionServiceImpl.SyncMeetings
+func TestSyncMeetings(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.SyncMeetings(ctx))
+
+	sessions, err := svc.sessionRepo.Find(ctx, &repository.RepoPageInfo{Page: 1, PageSize: -1, Sort: "start_at", Order: "asc"}, nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "只寫入線上課程 7 天內的上課")
+	assert.EqualValues(t, 2, sessions[0].CourseID)
+	assert.True(t, sessions[0].HasMeeting())
+
+	// 重複執行不重複建立
+	meetingID := sessions[0].MeetingID
+	require.NoError(t, svc.SyncMeetings(ctx))
+	session, err := svc.sessionRepo.GetByID(ctx, sessions[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, meetingID, session.MeetingID)
+}
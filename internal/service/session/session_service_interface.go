@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	courseEntity "github.com/itmrchow/course-management-system/internal/domain/course/entity"
+)
+
+// SessionService 定義課程上課相關的業務邏輯
+// 上課由課程的 CoursePattern 展開 , 查詢時寫入查詢範圍內尚未寫入的上課
+// 線上課程的上課於寫入後建立線上會議 , 改期時更新會議時間 , 取消時取消會議
+// 會議服務失敗時不影響上課的查詢、改期與取消 , 尚未建立的會議由 SyncMeetings 重試
+//
+// Example:
+//
+//	var svc SessionService
+//	sessions, err := svc.ListByCourse(ctx, 1, from, to)
+type SessionService interface {
+	// ListByCourse 查詢課程於 from ~ to 開始的上課 , 範圍不可超過 MaxListRange
+	// 線上課程只為 Config.MeetingLead 內的上課建立會議 , 之後的上課由 SyncMeetings 建立
+	// 參數: ctx - context, courseID - 課程ID, from / to - 開始時間範圍 , 皆包含在內
+	// 回傳: 上課 , 依開始時間排序, 錯誤訊息 , 課程不存在時回傳 gorm.ErrRecordNotFound
+	ListByCourse(ctx context.Context, courseID uint, from, to time.Time) ([]*courseEntity.CourseSession, error)
+
+	// Cancel 取消上課 , 已取消的上課不會收到上課提醒
+	// 參數: ctx - context, id - 上課ID, reason - 取消原因
+	// 回傳: 錯誤訊息 , 上課不存在時回傳 gorm.ErrRecordNotFound , 已取消時回傳 ErrAlreadyCancelled
+	Cancel(ctx context.Context, id uint, reason string) error
+
+	// Reschedule 將尚未開始的上課改至 startAt ~ endAt , 取消原本的上課並新增改期後的上課 , 線上會議沿用原本的會議
+	// 參數: ctx - context, id - 上課ID, startAt / endAt - 改期後的上課時間
+	// 回傳: 改期後的上課, 錯誤訊息 , 上課不存在時回傳 gorm.ErrRecordNotFound , 已取消時回傳 ErrAlreadyCancelled ,
+	// 已開始時回傳 ErrSessionStarted , 時間無效時回傳 ErrInvalidSchedule , 同一上課時段於 startAt 已有上課時回傳 ErrSlotTaken
+	Reschedule(ctx context.Context, id uint, startAt, endAt time.Time) (*courseEntity.CourseSession, error)
+
+	// GetMeeting 授課教師查詢上課的線上會議 , 包含主持人金鑰
+	// 參數: ctx - context, id - 上課ID, userID - 操作者的 user ID
+	// 回傳: 上課, 錯誤訊息 , 上課不存在時回傳 gorm.ErrRecordNotFound , user 不是教師或不是授課教師時回傳 ErrNotCourseTeacher ,
+	// 尚未建立會議時回傳 ErrNoMeeting
+	GetMeeting(ctx context.Context, id, userID uint) (*courseEntity.CourseSession, error)
+
+	// SyncMeetings 寫入線上課程於 Config.MeetingLead 內的上課並建立尚未建立的會議 , 可作為 job.Func
+	// 只處理開放報名或暫停報名的課程 , 單一課程失敗時仍會處理其他課程 , 回傳所有失敗的錯誤
+	// 參數: ctx - context
+	// 回傳: 錯誤訊息
+	SyncMeetings(ctx context.Context) error
+}
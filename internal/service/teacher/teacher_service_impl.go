@@ -0,0 +1,121 @@
+package teacher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+)
+
+var _ TeacherService = (*TeacherServiceImpl)(nil)
+
+// ErrInvalidStatus 教師目前狀態不允許此操作
+var ErrInvalidStatus = errors.New("invalid teacher status")
+
+// TeacherServiceImpl 實作 TeacherService 介面
+//
+// Example:
+//
+//	svc := teacher.NewTeacherService(teacherRepo.NewTeacherRepository(db), outboxRepo.NewOutboxRepository(db), repository.NewTransactor(db))
+//	err := svc.Approve(ctx, 1)
+type TeacherServiceImpl struct {
+	teacherRepo teacherRepo.TeacherRepository
+	outboxRepo  outboxRepo.OutboxRepository
+	transactor  repo.Transactor
+}
+
+// NewTeacherService 建立教師業務邏輯實例
+// 參數: teacherRepo - 教師資料庫操作實例, outboxRepo - 領域事件 outbox, transactor - transaction
+// 回傳: 教師業務邏輯實例
+func NewTeacherService(teacherRepo teacherRepo.TeacherRepository, outboxRepo outboxRepo.OutboxRepository, transactor repo.Transactor) TeacherService {
+	return &TeacherServiceImpl{teacherRepo: teacherRepo, outboxRepo: outboxRepo, transactor: transactor}
+}
+
+// Approve 審核通過教師
+func (s *TeacherServiceImpl) Approve(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Start(ctx, "TeacherService.Approve",
+		trace.WithAttributes(attribute.Int("teacher.id", int(id))))
+	defer tracing.End(span, &err)
+
+	return s.review(ctx, id, entity.TeacherStatusApproved)
+}
+
+// Reject 審核不通過教師
+func (s *TeacherServiceImpl) Reject(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Start(ctx, "TeacherService.Reject",
+		trace.WithAttributes(attribute.Int("teacher.id", int(id))))
+	defer tracing.End(span, &err)
+
+	return s.review(ctx, id, entity.TeacherStatusRejected)
+}
+
+// review 將審核中的教師更新為審核結果 , 並記錄審核數量
+// 狀態變更與審核事件在同一個 transaction 寫入
+func (s *TeacherServiceImpl) review(ctx context.Context, id uint, result entity.TeacherStatus) error {
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		teacher, err := s.teacherRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get teacher %d: %w", id, err)
+		}
+
+		if teacher.Status != entity.TeacherStatusPending {
+			return fmt.Errorf("teacher %d is %s: %w", id, teacher.Status, ErrInvalidStatus)
+		}
+
+		rows, err := s.teacherRepo.UpdateStatus(ctx, id, entity.TeacherStatusPending, result)
+		if err != nil {
+			return fmt.Errorf("failed to update teacher %d status: %w", id, err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("teacher %d status changed concurrently: %w", id, ErrInvalidStatus)
+		}
+
+		if err := s.outboxRepo.Add(ctx, reviewedEvent(teacher, result, time.Now())); err != nil {
+			return fmt.Errorf("failed to add teacher %d reviewed event: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	metrics.TeacherApprovalsTotal.WithLabelValues(result.String()).Inc()
+	zerolog.Ctx(ctx).Info().
+		Uint("teacher_id", id).
+		Stringer("status", result).
+		Msg("teacher reviewed")
+
+	return nil
+}
+
+// reviewedEvent 依審核結果建立事件
+func reviewedEvent(teacher *entity.Teacher, result entity.TeacherStatus, now time.Time) event.Event {
+	if result == entity.TeacherStatusApproved {
+		return event.TeacherApproved{
+			TeacherID:  teacher.ID,
+			UserID:     teacher.UserID,
+			Name:       teacher.Name,
+			Email:      teacher.Email,
+			ApprovedAt: now,
+		}
+	}
+	return event.TeacherRejected{
+		TeacherID:  teacher.ID,
+		UserID:     teacher.UserID,
+		Name:       teacher.Name,
+		Email:      teacher.Email,
+		RejectedAt: now,
+	}
+}
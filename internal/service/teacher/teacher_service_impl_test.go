@@ -0,0 +1,127 @@
+package teacher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	outboxEntity "github.com/itmrchow/course-management-system/internal/domain/outbox/entity"
+	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	outboxRepo "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	teacherRepo "github.com/itmrchow/course-management-system/internal/repository/teacher"
+)
+
+// stubTeacherRepo 測試用的教師 repository , 只實作審核使用到的方法
+type stubTeacherRepo struct {
+	teacherRepo.TeacherRepository
+	teachers map[uint]*entity.Teacher
+}
+
+func (r *stubTeacherRepo) GetByID(ctx context.Context, id uint) (*entity.Teacher, error) {
+	teacher, ok := r.teachers[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	t := *teacher
+	return &t, nil
+}
+
+func (r *stubTeacherRepo) UpdateStatus(ctx context.Context, id uint, from, to entity.TeacherStatus) (int64, error) {
+	teacher, ok := r.teachers[id]
+	if !ok || teacher.Status != from {
+		return 0, nil
+	}
+	teacher.Status = to
+	return 1, nil
+}
+
+// 教師審核測試
+// Test for TeacherServiceImpl.Approve / TeacherServiceImpl.Reject
+func TestReview(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         uint
+		review     func(svc TeacherService, id uint) error
+		assertFunc func(t *testing.T, repo *stubTeacherRepo, events []*outboxEntity.OutboxEvent, err error)
+	}{
+		{
+			name:   "not found",
+			id:     100,
+			review: func(svc TeacherService, id uint) error { return svc.Approve(context.Background(), id) },
+			assertFunc: func(t *testing.T, repo *stubTeacherRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name:   "already approved",
+			id:     2,
+			review: func(svc TeacherService, id uint) error { return svc.Reject(context.Background(), id) },
+			assertFunc: func(t *testing.T, repo *stubTeacherRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.ErrorIs(t, err, ErrInvalidStatus)
+				assert.Equal(t, entity.TeacherStatusApproved, repo.teachers[2].Status)
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name:   "approve",
+			id:     1,
+			review: func(svc TeacherService, id uint) error { return svc.Approve(context.Background(), id) },
+			assertFunc: func(t *testing.T, repo *stubTeacherRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.TeacherStatusApproved, repo.teachers[1].Status)
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, event.TypeTeacherApproved, events[0].EventType)
+					assert.EqualValues(t, 1, events[0].AggregateID)
+					assert.Contains(t, events[0].Payload, `"email":"john@example.com"`)
+				}
+			},
+		},
+		{
+			name:   "reject",
+			id:     1,
+			review: func(svc TeacherService, id uint) error { return svc.Reject(context.Background(), id) },
+			assertFunc: func(t *testing.T, repo *stubTeacherRepo, events []*outboxEntity.OutboxEvent, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.TeacherStatusRejected, repo.teachers[1].Status)
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, event.TypeTeacherRejected, events[0].EventType)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &stubTeacherRepo{teachers: map[uint]*entity.Teacher{
+				1: {Model: gorm.Model{ID: 1}, Email: "john@example.com", Status: entity.TeacherStatusPending},
+				2: {Model: gorm.Model{ID: 2}, Status: entity.TeacherStatusApproved},
+			}}
+			outbox := outboxRepo.NewOutboxMemoryRepository()
+
+			err := test.review(NewTeacherService(repo, outbox, repository.NoTransaction), test.id)
+			events, listErr := outbox.ListPending(context.Background(), time.Now().Add(time.Second), 10)
+			assert.NoError(t, listErr)
+			test.assertFunc(t, repo, events, err)
+		})
+	}
+}
+
+// 審核結果計入 metrics 測試
+func TestReviewMetrics(t *testing.T) {
+	repo := &stubTeacherRepo{teachers: map[uint]*entity.Teacher{
+		1: {Model: gorm.Model{ID: 1}, Status: entity.TeacherStatusPending},
+	}}
+	counter := metrics.TeacherApprovalsTotal.WithLabelValues("approved")
+	before := testutil.ToFloat64(counter)
+
+	assert.NoError(t, NewTeacherService(repo, outboxRepo.NewOutboxMemoryRepository(), repository.NoTransaction).Approve(context.Background(), 1))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
@@ -0,0 +1,24 @@
+package teacher
+
+import (
+	"context"
+)
+
+// TeacherService 定義教師相關的業務邏輯
+// 負責教師審核等狀態變更
+//
+// Example:
+//
+//	var svc TeacherService
+//	err := svc.Approve(ctx, 1)
+type TeacherService interface {
+	// Approve 審核通過教師 , 教師狀態須為審核中
+	// 參數: ctx - context, id - 教師ID
+	// 回傳: 錯誤訊息
+	Approve(ctx context.Context, id uint) error
+
+	// Reject 審核不通過教師 , 教師狀態須為審核中
+	// 參數: ctx - context, id - 教師ID
+	// 回傳: 錯誤訊息
+	Reject(ctx context.Context, id uint) error
+}
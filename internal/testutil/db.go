@@ -0,0 +1,231 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/go-testfixtures/testfixtures/v3"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/config"
+)
+
+const (
+	pgUser       = "postgres"
+	pgPassword   = "postgres"
+	templateName = "cms_template"
+)
+
+// pgServer 整個測試程序共用的 embedded postgres
+// 於第一次呼叫 NewDB 時啟動 , 由 Main 於測試結束後停止
+var pgServer struct {
+	once     sync.Once
+	err      error
+	postgres *embeddedpostgres.EmbeddedPostgres
+	port     uint32
+	runtime  string
+	admin    *sql.DB
+
+	// createMu CREATE DATABASE ... TEMPLATE 需要 template 沒有其他連線 , 建立資料庫時依序執行
+	createMu sync.Mutex
+	seq      atomic.Int64
+}
+
+// Main 供 TestMain 使用 , 執行測試並於結束後停止 embedded postgres
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testutil.Main(m))
+//	}
+func Main(m *testing.M) int {
+	// 註冊 signal handler , 中斷測試時仍會停止 postgres
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		log.Info().Msg("Received interrupt, stopping postgres...")
+		stopPostgres()
+		os.Exit(1)
+	}()
+
+	code := m.Run()
+
+	stopPostgres()
+	return code
+}
+
+// NewDB 建立獨立的測試資料庫 , 測試結束時自動刪除
+// 第一次呼叫時以可用的 port 啟動 embedded postgres , 並建立已 migrate 的 template 資料庫
+// 之後每次呼叫從 template 複製新的資料庫 , 測試之間的資料互不影響 , 可搭配 t.Parallel 使用
+//
+// Example:
+//
+//	db := testutil.NewDB(t)
+//	testutil.LoadFixtures(t, db, "testdata")
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	pgServer.once.Do(func() {
+		pgServer.err = startPostgres()
+	})
+	if pgServer.err != nil {
+		t.Fatalf("failed to start embedded postgres: %v", pgServer.err)
+	}
+
+	name := fmt.Sprintf("test_%d_%d", os.Getpid(), pgServer.seq.Add(1))
+
+	pgServer.createMu.Lock()
+	_, err := pgServer.admin.Exec(fmt.Sprintf(`CREATE DATABASE %q TEMPLATE %q`, name, templateName))
+	pgServer.createMu.Unlock()
+	if err != nil {
+		t.Fatalf("failed to create database %s: %v", name, err)
+	}
+
+	logger := zerolog.New(zerolog.NewTestWriter(t)).Level(zerolog.WarnLevel)
+	db, err := config.NewDB(context.Background(), postgres.Open(dsn(name)), &gorm.Config{
+		Logger: config.NewGormLogger(&logger, 0),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database %s: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		if err := config.CloseDB(db); err != nil {
+			t.Errorf("failed to close database %s: %v", name, err)
+		}
+		if _, err := pgServer.admin.Exec(fmt.Sprintf(`DROP DATABASE IF EXISTS %q WITH (FORCE)`, name)); err != nil {
+			t.Errorf("failed to drop database %s: %v", name, err)
+		}
+	})
+
+	return db
+}
+
+// LoadFixtures 載入 testfixtures 格式的 fixture
+// 參數: paths - fixture 檔案或目錄 , 檔名需與 table 名稱相同 , 例如 testdata/teacher.yml
+func LoadFixtures(t testing.TB, db *gorm.DB, paths ...string) {
+	t.Helper()
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql db: %v", err)
+	}
+
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(sqlDB),
+		testfixtures.Dialect("postgres"),
+		testfixtures.Paths(paths...),
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create fixtures: %v", err)
+	}
+	if err := fixtures.Load(); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+}
+
+// startPostgres 以可用的 port 啟動 embedded postgres , 並建立 template 資料庫
+func startPostgres() error {
+	port, err := freePort()
+	if err != nil {
+		return err
+	}
+
+	runtime, err := os.MkdirTemp("", "cms-postgres-")
+	if err != nil {
+		return fmt.Errorf("failed to create runtime dir: %w", err)
+	}
+
+	pg := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Port(port).
+		Username(pgUser).
+		Password(pgPassword).
+		RuntimePath(runtime).
+		DataPath(filepath.Join(runtime, "data")).
+		Logger(nil))
+	if err := pg.Start(); err != nil {
+		_ = os.RemoveAll(runtime)
+		return err
+	}
+
+	pgServer.postgres = pg
+	pgServer.port = port
+	pgServer.runtime = runtime
+
+	admin, err := sql.Open("pgx", dsn("postgres"))
+	if err != nil {
+		return fmt.Errorf("failed to open admin connection: %w", err)
+	}
+	pgServer.admin = admin
+
+	return createTemplate()
+}
+
+// createTemplate 建立 template 資料庫並執行 migrate , 完成後關閉連線讓 template 可被複製
+func createTemplate() error {
+	if _, err := pgServer.admin.Exec(fmt.Sprintf(`CREATE DATABASE %q`, templateName)); err != nil {
+		return fmt.Errorf("failed to create template database: %w", err)
+	}
+
+	logger := zerolog.Nop()
+	db, err := config.NewDB(context.Background(), postgres.Open(dsn(templateName)), &gorm.Config{
+		Logger: config.NewGormLogger(&logger, 0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open template database: %w", err)
+	}
+	if err := config.Migrate(db); err != nil {
+		_ = config.CloseDB(db)
+		return fmt.Errorf("failed to migrate template database: %w", err)
+	}
+
+	return config.CloseDB(db)
+}
+
+// stopPostgres 停止 embedded postgres 並刪除暫存目錄 , 未啟動時不做任何事
+func stopPostgres() {
+	if pgServer.postgres == nil {
+		return
+	}
+
+	if pgServer.admin != nil {
+		_ = pgServer.admin.Close()
+	}
+	if err := pgServer.postgres.Stop(); err != nil {
+		log.Error().Err(err).Msg("failed to stop postgres")
+	}
+	_ = os.RemoveAll(pgServer.runtime)
+	pgServer.postgres = nil
+}
+
+// freePort 取得目前可用的 port
+func freePort() (uint32, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find free port: %w", err)
+	}
+	defer l.Close()
+
+	return uint32(l.Addr().(*net.TCPAddr).Port), nil
+}
+
+func dsn(database string) string {
+	return fmt.Sprintf("host=localhost user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC client_encoding=UTF8",
+		pgUser, pgPassword, database, pgServer.port)
+}
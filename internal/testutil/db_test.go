@@ -0,0 +1,44 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itmrchow/course-management-system/internal/domain/teacher/entity"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(Main(m))
+}
+
+// 每個測試使用獨立資料庫測試
+// 兩個平行測試各自寫入相同 unique 欄位的資料 , 互不影響
+func TestNewDBIsolation(t *testing.T) {
+	for _, name := range []string{"a", "b"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := NewDB(t)
+
+			teacher := &entity.Teacher{UserID: 1, Name: name, Phone: "0900000000", Email: "same@example.com"}
+			require.NoError(t, db.Create(teacher).Error)
+
+			var count int64
+			require.NoError(t, db.Model(&entity.Teacher{}).Count(&count).Error)
+			assert.EqualValues(t, 1, count)
+		})
+	}
+}
+
+// fixture 載入測試
+func TestLoadFixtures(t *testing.T) {
+	db := NewDB(t)
+	LoadFixtures(t, db, "../repository/teacher/testdata/teacher.yml")
+
+	var count int64
+	require.NoError(t, db.Model(&entity.Teacher{}).Count(&count).Error)
+	assert.EqualValues(t, 10, count)
+}
@@ -0,0 +1,181 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+)
+
+// instrumentationName 系統 tracer 名稱
+const instrumentationName = "github.com/itmrchow/course-management-system"
+
+// 支援的 exporter
+const (
+	ExporterNone   = "none"   // 不輸出 , 仍會產生 trace ID 供 log 使用
+	ExporterStdout = "stdout" // 輸出至 stdout , 供本機開發與離線測試使用
+	ExporterOTLP   = "otlp"   // 以 OTLP/HTTP 輸出至 collector
+)
+
+// Config tracing 設定
+type Config struct {
+	ServiceName  string    // 服務名稱
+	Exporter     string    // none, stdout, otlp
+	OTLPEndpoint string    // OTLP collector 位址 , 例如 localhost:4318
+	OTLPInsecure bool      // OTLP 是否不使用 TLS
+	SampleRatio  float64   // 取樣比例 , 0 ~ 1
+	Writer       io.Writer // stdout exporter 輸出目標 , 預設 os.Stdout
+}
+
+// ConfigFromViper 從 config 讀取 tracing 設定
+func ConfigFromViper() Config {
+	viper.SetDefault("TRACING_SERVICE_NAME", "course-management-system")
+	viper.SetDefault("TRACING_EXPORTER", ExporterNone)
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1)
+
+	return Config{
+		ServiceName:  viper.GetString("TRACING_SERVICE_NAME"),
+		Exporter:     viper.GetString("TRACING_EXPORTER"),
+		OTLPEndpoint: viper.GetString("TRACING_OTLP_ENDPOINT"),
+		OTLPInsecure: viper.GetBool("TRACING_OTLP_INSECURE"),
+		SampleRatio:  viper.GetFloat64("TRACING_SAMPLE_RATIO"),
+	}
+}
+
+// Init 初始化全域 tracer provider 與 propagator
+// 回傳: shutdown function , 於程式結束時呼叫以送出剩餘的 span
+//
+// Example:
+//
+//	shutdown, err := tracing.Init(ctx, tracing.ConfigFromViper())
+//	if err != nil {
+//		return err
+//	}
+//	defer shutdown(ctx)
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// newExporter 依設定建立 exporter , none 時回傳 nil
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(cfg.Exporter) {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		w := cfg.Writer
+		if w == nil {
+			w = os.Stdout
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", cfg.Exporter)
+	}
+}
+
+// Tracer 回傳系統使用的 tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 開始一個 span , 並將 trace ID 放入 ctx logger
+// ctx 中已有 span 時為其子 span
+//
+// Example:
+//
+//	func (s *TeacherServiceImpl) Approve(ctx context.Context, id uint) (err error) {
+//		ctx, span := tracing.Start(ctx, "TeacherService.Approve",
+//			trace.WithAttributes(attribute.Int("teacher.id", int(id))))
+//		defer tracing.End(span, &err)
+//		...
+//	}
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, name, opts...)
+
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		if traceID := sc.TraceID().String(); logctx.TraceID(ctx) != traceID {
+			ctx = logctx.WithTraceID(ctx, traceID)
+		}
+	}
+
+	return ctx, span
+}
+
+// End 結束 span , err 不為 nil 時記錄錯誤並將 span 狀態設為 error
+// 參數 err 使用指標 , 搭配 named return 於 defer 中取得最終的錯誤
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// Inject 將 ctx 的 trace context 轉為 map , 供跨程序或寫入資料庫後再還原
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// Extract 從 Inject 產生的 map 還原 trace context 至 ctx
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
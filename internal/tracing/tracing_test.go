@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/itmrchow/course-management-system/internal/logctx"
+)
+
+// stdout exporter 測試
+// 以 stdout exporter 輸出至 buffer , 驗證 span 與錯誤狀態
+func TestInitStdout(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := Init(context.Background(), Config{
+		ServiceName: "test-service",
+		Exporter:    ExporterStdout,
+		SampleRatio: 1,
+		Writer:      &buf,
+	})
+	require.NoError(t, err)
+
+	func() (err error) {
+		ctx, span := Start(context.Background(), "TestService.Do")
+		defer End(span, &err)
+
+		assert.Equal(t, span.SpanContext().TraceID().String(), logctx.TraceID(ctx))
+		return errors.New("boom")
+	}()
+
+	require.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"TestService.Do"`)
+	assert.Contains(t, buf.String(), `"Description":"boom"`)
+	assert.Contains(t, buf.String(), "test-service")
+}
+
+// 不支援的 exporter 測試
+func TestInitUnknownExporter(t *testing.T) {
+	_, err := Init(context.Background(), Config{Exporter: "unknown"})
+	assert.Error(t, err)
+}
+
+// trace context 轉為 map 後還原測試
+func TestInjectExtract(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{Exporter: ExporterNone, SampleRatio: 1})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	ctx, span := Start(context.Background(), "parent")
+	defer span.End()
+
+	carrier := Inject(ctx)
+	assert.NotEmpty(t, carrier["traceparent"])
+
+	restored := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	assert.Equal(t, span.SpanContext().TraceID(), restored.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), restored.SpanID())
+}
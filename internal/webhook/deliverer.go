@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	"github.com/itmrchow/course-management-system/internal/job"
+	"github.com/itmrchow/course-management-system/internal/metrics"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	repo "github.com/itmrchow/course-management-system/internal/repository"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+)
+
+// maxResponseBody 發送紀錄保留的回應內容長度上限
+const maxResponseBody = 1024
+
+// Config deliverer 設定
+type Config struct {
+	BatchSize      int           // 每次取得的發送紀錄數量
+	MaxAttempts    uint          // 發送次數上限 , 超過後標記為失敗 , 只能手動重新發送
+	InitialBackoff time.Duration // 第一次重試等待時間 , 之後每次加倍
+	MaxBackoff     time.Duration // 重試等待時間上限
+	Timeout        time.Duration // 單次 HTTP 請求的時間上限
+	// AllowPrivateNetworks 是否允許發送至內部位址 , 例如 loopback、私有網路與 link-local , 只用於本機開發與測試
+	AllowPrivateNetworks bool
+}
+
+// ConfigFromViper 從 viper 讀取 deliverer 設定
+func ConfigFromViper() Config {
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_INITIAL_BACKOFF", 30*time.Second)
+	viper.SetDefault("WEBHOOK_MAX_BACKOFF", 6*time.Hour)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
+
+	return Config{
+		BatchSize:      viper.GetInt("WEBHOOK_BATCH_SIZE"),
+		MaxAttempts:    viper.GetUint("WEBHOOK_MAX_ATTEMPTS"),
+		InitialBackoff: viper.GetDuration("WEBHOOK_INITIAL_BACKOFF"),
+		MaxBackoff:     viper.GetDuration("WEBHOOK_MAX_BACKOFF"),
+		Timeout:        viper.GetDuration("WEBHOOK_TIMEOUT"),
+
+		AllowPrivateNetworks: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS"),
+	}
+}
+
+// Deliverer 發送待發送的 webhook , 請求以訂閱的 Secret 簽章
+// 每一批紀錄在 transaction 中取得並以 ClaimDeliveries 延後下次發送時間 , commit 後在 transaction 外發送 , 再個別記錄結果
+// 發送期間不持有鎖定 , 多個 deliverer 同時執行不會重複發送同一筆紀錄 , deliverer 中斷時紀錄於 claim 到期後重新發送
+// 回應 2xx 視為成功 , 其他狀態碼或連線失敗時依指數退避重試 , 超過 MaxAttempts 標記為失敗
+// 以 Guard 的 client 發送 , 連線前拒絕內部位址且不跟隨 redirect
+//
+// Example:
+//
+//	deliverer := webhook.NewDeliverer(transactor, webhookRepo, webhook.ConfigFromViper())
+//	runner.Every("webhook_delivery", 5*time.Second, deliverer.RunOnce)
+type Deliverer struct {
+	transactor repo.Transactor
+	repo       webhookRepository.WebhookRepository
+	client     *http.Client
+	cfg        Config
+	now        func() time.Time
+}
+
+// NewDeliverer 建立 deliverer
+// 參數: transactor - transaction, repo - webhook repository, cfg - 設定
+func NewDeliverer(transactor repo.Transactor, repo webhookRepository.WebhookRepository, cfg Config) *Deliverer {
+	return &Deliverer{
+		transactor: transactor,
+		repo:       repo,
+		client:     NewGuard(cfg.AllowPrivateNetworks, net.DefaultResolver).Client(cfg.Timeout),
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+// RunOnce 發送所有可發送的紀錄 , 直到沒有可發送的紀錄或 ctx 結束 , 可作為 job.Func
+// 個別紀錄發送失敗不回傳錯誤 , 只記錄於發送紀錄 , 回傳的錯誤為存取資料庫失敗
+func (d *Deliverer) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		count, err := d.runBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if count < d.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// runBatch 取得並發送一批紀錄 , 回傳: 取得的紀錄數量
+func (d *Deliverer) runBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.deliver(ctx, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// claim 於 transaction 中取得一批可發送的紀錄 , 並延後至整批發送時間上限之後才可再取得
+func (d *Deliverer) claim(ctx context.Context) ([]*entity.WebhookDelivery, error) {
+	now := d.now()
+	var deliveries []*entity.WebhookDelivery
+	err := d.transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		deliveries, err = d.repo.ListPendingDeliveries(ctx, now, d.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list pending webhook deliveries: %w", err)
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		until := now.Add(time.Duration(len(deliveries)) * d.cfg.Timeout)
+		if err := d.repo.ClaimDeliveries(ctx, ids, until); err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+// deliver 發送單一紀錄並記錄結果
+func (d *Deliverer) deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	logger := zerolog.Ctx(ctx).With().
+		Uint("delivery_id", delivery.ID).
+		Uint("subscription_id", delivery.SubscriptionID).
+		Str("event_id", delivery.EventID).
+		Uint("attempts", delivery.Attempts+1).
+		Logger()
+
+	subscription, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get webhook subscription %d: %w", delivery.SubscriptionID, err)
+	}
+
+	attempt := &entity.WebhookAttempt{At: d.now()}
+	if subscription == nil {
+		// 訂閱已刪除 , 不再發送
+		attempt.Error = "subscription deleted"
+	} else {
+		attempt.ResponseCode, attempt.ResponseBody, err = d.send(ctx, subscription, delivery, attempt.At)
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	}
+
+	switch {
+	case attempt.Error == "":
+		attempt.Status = entity.WebhookDeliveryStatusSucceeded
+		metrics.WebhookDeliveriesTotal.WithLabelValues("succeeded").Inc()
+		logger.Debug().Int("response_code", attempt.ResponseCode).Msg("webhook delivered")
+
+	case subscription == nil || delivery.Attempts+1 >= d.cfg.MaxAttempts:
+		attempt.Status = entity.WebhookDeliveryStatusFailed
+		metrics.WebhookDeliveriesTotal.WithLabelValues("failed").Inc()
+		logger.Error().Str("error", attempt.Error).Int("response_code", attempt.ResponseCode).Msg("webhook delivery failed")
+
+	default:
+		attempt.Status = entity.WebhookDeliveryStatusPending
+		attempt.NextAttemptAt = attempt.At.Add(job.Backoff(d.cfg.InitialBackoff, d.cfg.MaxBackoff, delivery.Attempts))
+		metrics.WebhookDeliveriesTotal.WithLabelValues("retry").Inc()
+		logger.Warn().Str("error", attempt.Error).Int("response_code", attempt.ResponseCode).
+			Time("next_attempt_at", attempt.NextAttemptAt).Msg("failed to deliver webhook")
+	}
+
+	if err := d.repo.RecordAttempt(ctx, delivery.ID, attempt); err != nil {
+		return fmt.Errorf("failed to record webhook delivery %d attempt: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// send 以 HTTP POST 發送並簽章
+// 回傳: HTTP 狀態碼 (沒有回應時為 0), 回應內容, 錯誤訊息
+func (d *Deliverer) send(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery, now time.Time) (int, string, error) {
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(outbox.HeaderEventID, delivery.EventID)
+	req.Header.Set(outbox.HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	// text 欄位不接受無效的 UTF-8 與 NUL
+	respBody := strings.ReplaceAll(strings.ToValidUTF8(string(b), ""), "\x00", "")
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, respBody, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, respBody, nil
+}
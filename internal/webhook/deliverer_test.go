@@ -0,0 +1,278 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+)
+
+// receivedRequest 測試 server 收到的請求
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type DelivererTestSuite struct {
+	suite.Suite
+	repo      webhookRepository.WebhookRepository
+	fanout    *Fanout
+	deliverer *Deliverer
+	server    *httptest.Server
+	now       time.Time
+
+	mu       sync.Mutex
+	status   int
+	received []receivedRequest
+	// onRequest 於 server 收到請求時呼叫
+	onRequest func()
+}
+
+// SetupTest 於每個測試案例前執行 , 建立接收 webhook 的 server 與兩個訂閱
+func (s *DelivererTestSuite) SetupTest() {
+	s.repo = webhookRepository.NewWebhookMemoryRepository()
+	s.now = time.Now().Truncate(time.Second)
+	s.status = http.StatusNoContent
+	s.received = nil
+	s.onRequest = nil
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if s.onRequest != nil {
+			s.onRequest()
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.received = append(s.received, receivedRequest{header: r.Header.Clone(), body: body})
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	s.T().Cleanup(s.server.Close)
+
+	for _, subscription := range []*entity.WebhookSubscription{
+		{URL: s.server.URL + "/course", EventTypes: entity.EventTypes{"course.published", "course.full"}, Secret: "secret-1"}, // id 1
+		{URL: s.server.URL + "/teacher", EventTypes: entity.EventTypes{"teacher.approved"}, Secret: "secret-2"},               // id 2
+	} {
+		_, err := s.repo.CreateSubscription(context.Background(), subscription)
+		s.Require().NoError(err)
+	}
+
+	s.fanout = NewFanout(s.repo)
+	s.fanout.now = func() time.Time { return s.now }
+	s.deliverer = NewDeliverer(repository.NoTransaction, s.repo, Config{
+		BatchSize:      10,
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		Timeout:        time.Second,
+
+		AllowPrivateNetworks: true,
+	})
+	s.deliverer.now = func() time.Time { return s.now }
+}
+
+// TestDelivererSuite 執行測試套件
+func TestDelivererSuite(t *testing.T) {
+	suite.Run(t, new(DelivererTestSuite))
+}
+
+func (s *DelivererTestSuite) publish(id, eventType string) {
+	s.Require().NoError(s.fanout.Handle(context.Background(), outbox.Message{
+		ID:            id,
+		Type:          eventType,
+		AggregateType: "course",
+		AggregateID:   1,
+		OccurredAt:    s.now,
+		Payload:       json.RawMessage(`{"course_id":1}`),
+	}))
+}
+
+func (s *DelivererTestSuite) delivery(id uint) *entity.WebhookDelivery {
+	delivery, err := s.repo.GetDelivery(context.Background(), id)
+	s.Require().NoError(err)
+	return delivery
+}
+
+func (s *DelivererTestSuite) TestFanoutOnlySubscribedAndIdempotent() {
+	s.publish("event-1", "course.published")
+	s.publish("event-1", "course.published") // relay 重新發送
+	s.publish("event-2", "enrollment.enrolled")
+
+	deliveries, err := s.repo.FindDeliveries(context.Background(), &repository.RepoPageInfo{Page: 1, PageSize: 10, Sort: "id", Order: "asc"}, nil)
+	s.NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.EqualValues(1, deliveries[0].SubscriptionID)
+	s.Equal("event-1", deliveries[0].EventID)
+
+	var msg outbox.Message
+	s.NoError(json.Unmarshal([]byte(deliveries[0].Payload), &msg))
+	s.Equal("course.published", msg.Type)
+}
+
+func (s *DelivererTestSuite) TestDeliverSigned() {
+	s.publish("event-1", "course.full")
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+
+	s.Require().Len(s.received, 1)
+	req := s.received[0]
+	s.Equal("application/json", req.header.Get("Content-Type"))
+	s.Equal("1", req.header.Get(HeaderDeliveryID))
+	s.Equal("event-1", req.header.Get(outbox.HeaderEventID))
+	s.Equal("course.full", req.header.Get(outbox.HeaderEventType))
+	s.NoError(Verify("secret-1", req.header.Get(HeaderSignature), req.header.Get(HeaderTimestamp), req.body, s.now, time.Minute))
+	s.ErrorIs(Verify("secret-2", req.header.Get(HeaderSignature), req.header.Get(HeaderTimestamp), req.body, s.now, time.Minute), ErrInvalidSignature)
+
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusSucceeded, delivery.Status)
+	s.Equal(http.StatusNoContent, delivery.ResponseCode)
+	s.EqualValues(1, delivery.Attempts)
+
+	// 已成功的紀錄不會再發送
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Len(s.received, 1)
+}
+
+func (s *DelivererTestSuite) TestClaimedWhileSending() {
+	s.publish("event-1", "course.full")
+
+	// 發送期間紀錄已 claim , 不會被其他 deliverer 取得
+	var pending []*entity.WebhookDelivery
+	s.onRequest = func() {
+		var err error
+		pending, err = s.repo.ListPendingDeliveries(context.Background(), s.now, 10)
+		s.NoError(err)
+	}
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Require().Len(s.received, 1)
+	s.Empty(pending)
+	s.Equal(entity.WebhookDeliveryStatusSucceeded, s.delivery(1).Status)
+}
+
+func (s *DelivererTestSuite) TestRetryWithBackoffThenFail() {
+	s.status = http.StatusServiceUnavailable
+	s.publish("event-1", "course.published")
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusPending, delivery.Status)
+	s.Equal(http.StatusServiceUnavailable, delivery.ResponseCode)
+	s.Equal("ok", delivery.ResponseBody)
+	s.Contains(delivery.LastError, "status 503")
+	s.True(delivery.NextAttemptAt.Equal(s.now.Add(time.Minute)))
+
+	// 尚未到重試時間
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Len(s.received, 1)
+
+	// 第二次失敗等待時間加倍
+	s.now = s.now.Add(time.Minute)
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.True(s.delivery(1).NextAttemptAt.Equal(s.now.Add(2 * time.Minute)))
+
+	// 超過發送次數上限
+	s.now = s.now.Add(2 * time.Minute)
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	delivery = s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusFailed, delivery.Status)
+	s.EqualValues(3, delivery.Attempts)
+
+	s.now = s.now.Add(time.Hour)
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Len(s.received, 3)
+}
+
+func (s *DelivererTestSuite) TestRedeliver() {
+	s.status = http.StatusInternalServerError
+	s.deliverer.cfg.MaxAttempts = 1
+	s.publish("event-1", "course.published")
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Equal(entity.WebhookDeliveryStatusFailed, s.delivery(1).Status)
+
+	s.status = http.StatusOK
+	rowsAffected, err := s.repo.Redeliver(context.Background(), 1, s.now)
+	s.NoError(err)
+	s.EqualValues(1, rowsAffected)
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+	s.Require().Len(s.received, 2)
+	s.Equal(s.received[0].header.Get(HeaderDeliveryID), s.received[1].header.Get(HeaderDeliveryID))
+
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusSucceeded, delivery.Status)
+	s.Equal(http.StatusOK, delivery.ResponseCode)
+	s.Empty(delivery.LastError)
+}
+
+func (s *DelivererTestSuite) TestSubscriptionDeleted() {
+	s.publish("event-1", "course.published")
+	_, err := s.repo.DeleteSubscription(context.Background(), 1)
+	s.Require().NoError(err)
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+
+	s.Empty(s.received)
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusFailed, delivery.Status)
+	s.Equal("subscription deleted", delivery.LastError)
+}
+
+func (s *DelivererTestSuite) TestConnectionRefused() {
+	s.server.Close()
+	s.publish("event-1", "course.published")
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusPending, delivery.Status)
+	s.Zero(delivery.ResponseCode)
+	s.True(strings.HasPrefix(delivery.LastError, "failed to post webhook"))
+}
+
+// 不允許內部位址時 , 連線前拒絕發送至 loopback 的訂閱
+func (s *DelivererTestSuite) TestForbiddenDestination() {
+	s.deliverer = NewDeliverer(repository.NoTransaction, s.repo, Config{BatchSize: 10, MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour, Timeout: time.Second})
+	s.deliverer.now = func() time.Time { return s.now }
+	s.publish("event-1", "course.published")
+
+	s.NoError(s.deliverer.RunOnce(context.Background()))
+
+	s.Empty(s.received)
+	delivery := s.delivery(1)
+	s.Equal(entity.WebhookDeliveryStatusPending, delivery.Status)
+	s.Contains(delivery.LastError, ErrForbiddenDestination.Error())
+}
+
+// 回應內容只保留開頭 , 並移除無效的 UTF-8
+func TestSendResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("\xff\x00" + strings.Repeat("a", 2*maxResponseBody)))
+	}))
+	defer server.Close()
+
+	d := NewDeliverer(repository.NoTransaction, nil, Config{Timeout: time.Second, AllowPrivateNetworks: true})
+	code, body, err := d.send(context.Background(),
+		&entity.WebhookSubscription{URL: server.URL, Secret: "secret"},
+		&entity.WebhookDelivery{Model: gorm.Model{ID: 1}, Payload: `{}`},
+		time.Now(),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, strings.Repeat("a", maxResponseBody-2), body)
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/itmrchow/course-management-system/internal/domain/webhook/entity"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+)
+
+// Fanout 將 outbox 事件轉為各訂閱的發送紀錄 , 實際發送由 Deliverer 執行
+// 以 Dispatcher 訂閱時於 relay 的 transaction 中執行 , 發送紀錄與事件標記為已發送一起 commit
+// relay 重新發送同一個事件時 , 已建立的發送紀錄會被略過
+//
+// Example:
+//
+//	dispatcher.Subscribe(outbox.AllEvents, webhook.NewFanout(webhookRepo).Handle)
+type Fanout struct {
+	repo webhookRepository.WebhookRepository
+	now  func() time.Time
+}
+
+// NewFanout 建立 webhook fanout
+// 參數: repo - webhook repository
+func NewFanout(repo webhookRepository.WebhookRepository) *Fanout {
+	return &Fanout{repo: repo, now: time.Now}
+}
+
+// Handle 實作 outbox.Handler , 為訂閱事件類型的每個訂閱建立發送紀錄
+func (f *Fanout) Handle(ctx context.Context, msg outbox.Message) error {
+	subscriptions, err := f.repo.ListSubscriptionsForEvent(ctx, msg.Type)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions for %s: %w", msg.Type, err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	now := f.now()
+	deliveries := make([]*entity.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        msg.ID,
+			EventType:      msg.Type,
+			Payload:        string(payload),
+			NextAttemptAt:  now,
+		})
+	}
+
+	count, err := f.repo.AddDeliveries(ctx, deliveries...)
+	if err != nil {
+		return fmt.Errorf("failed to add webhook deliveries for event %s: %w", msg.ID, err)
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("event_id", msg.ID).
+		Str("event_type", msg.Type).
+		Int64("deliveries", count).
+		Msg("webhook deliveries scheduled")
+	return nil
+}
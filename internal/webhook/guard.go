@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenDestination webhook 的目的地為內部位址 , 例如 loopback、私有網路或 link-local
+var ErrForbiddenDestination = errors.New("forbidden webhook destination")
+
+// blockedPrefixes netip 未涵蓋的非公開網段
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // this network
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 , 可對應至 IPv4 內部位址
+}
+
+// Resolver 解析主機名稱 , *net.Resolver 實作此介面
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Guard 限制 webhook 的目的地 , 避免以 webhook 存取內部服務 (SSRF)
+// 建立訂閱時以 CheckURL 檢查解析後的位址 , 發送時由 Client 於連線前再次檢查實際連線的位址 , 避免 DNS 解析結果改變
+//
+// Example:
+//
+//	guard := webhook.NewGuard(false, net.DefaultResolver)
+//	if err := guard.CheckURL(ctx, req.URL); err != nil { ... }
+type Guard struct {
+	allowPrivate bool
+	resolver     Resolver
+}
+
+// NewGuard 建立 Guard
+// 參數: allowPrivate - 是否允許內部位址 , 只用於本機開發與測試, resolver - 主機名稱解析
+func NewGuard(allowPrivate bool, resolver Resolver) *Guard {
+	return &Guard{allowPrivate: allowPrivate, resolver: resolver}
+}
+
+// CheckURL 檢查 URL 的主機名稱解析後的所有位址皆為公開位址
+// 回傳: 錯誤訊息 , 內部位址回傳 ErrForbiddenDestination
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	if g.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	host := u.Hostname()
+
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		addrs, err = g.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve host %q: %w", host, err)
+		}
+	}
+
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenDestination, host, addr)
+		}
+	}
+	return nil
+}
+
+// Client 建立發送 webhook 的 HTTP client
+// 連線前檢查實際連線的位址 , 不使用環境變數的 proxy , 不跟隨 redirect , 3xx 回應視為發送失敗
+// 參數: timeout - 單次 HTTP 請求的時間上限
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: g.control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// control 於建立連線前檢查解析後的位址
+func (g *Guard) control(network, address string, _ syscall.RawConn) error {
+	if g.allowPrivate {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, address)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, address)
+	}
+	return nil
+}
+
+// IsPublicAddr 是否為可發送 webhook 的公開位址
+// loopback、私有網路、link-local (包含雲端 metadata 169.254.169.254)、multicast 與保留網段皆不是公開位址
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
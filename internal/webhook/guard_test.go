@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticResolver 以固定的對應解析主機名稱
+type staticResolver map[string][]netip.Addr
+
+func (r staticResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+// 公開位址判斷測試
+// Test for IsPublicAddr
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.0.0.1", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "224.0.0.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+// 建立訂閱時的目的地檢查測試
+// Test for Guard.CheckURL
+func TestCheckURL(t *testing.T) {
+	resolver := staticResolver{
+		"partner.example.com":  {netip.MustParseAddr("93.184.216.34")},
+		"internal.example.com": {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.5")},
+	}
+	guard := NewGuard(false, resolver)
+
+	tests := []struct {
+		name       string
+		url        string
+		assertFunc func(t *testing.T, err error)
+	}{
+		{
+			name: "public host",
+			url:  "https://partner.example.com/hook",
+			assertFunc: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "host resolves to private address",
+			url:  "https://internal.example.com/hook",
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrForbiddenDestination)
+			},
+		},
+		{
+			name: "cloud metadata",
+			url:  "http://169.254.169.254/latest/meta-data/",
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrForbiddenDestination)
+			},
+		},
+		{
+			name: "ipv6 loopback",
+			url:  "http://[::1]:8080/hook",
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrForbiddenDestination)
+			},
+		},
+		{
+			name: "unresolvable host",
+			url:  "https://unknown.example.com/hook",
+			assertFunc: func(t *testing.T, err error) {
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, ErrForbiddenDestination)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertFunc(t, guard.CheckURL(context.Background(), tt.url))
+		})
+	}
+
+	// 允許內部位址時不檢查
+	assert.NoError(t, NewGuard(true, resolver).CheckURL(context.Background(), "http://127.0.0.1:8080/hook"))
+}
+
+// 發送時的目的地檢查測試 , 連線前拒絕內部位址 , 不跟隨 redirect
+// Test for Guard.Client
+func TestGuardClient(t *testing.T) {
+	var redirected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+		}
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewGuard(false, staticResolver{}).Client(time.Second).Get(server.URL)
+	assert.ErrorIs(t, err, ErrForbiddenDestination)
+
+	resp, err := NewGuard(true, staticResolver{}).Client(time.Second).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.False(t, redirected)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// 發送 webhook 時的 header , 事件ID與類型沿用 outbox.HeaderEventID / outbox.HeaderEventType
+const (
+	HeaderDeliveryID = "X-Webhook-Delivery"  // 發送紀錄ID , 重新發送時不變
+	HeaderTimestamp  = "X-Webhook-Timestamp" // 簽章時間 (unix 秒)
+	HeaderSignature  = "X-Webhook-Signature" // 簽章 , 格式為 sha256=<hex>
+)
+
+const signaturePrefix = "sha256="
+
+var (
+	// ErrInvalidSignature 簽章不符
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrTimestampExpired 簽章時間超過容許範圍 , 可能為重送攻擊
+	ErrTimestampExpired = errors.New("webhook timestamp expired")
+)
+
+// NewSecret 產生簽章金鑰
+// 回傳: 32 bytes 隨機值的 hex 字串 , 以 whsec_ 為前綴
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign 計算簽章 , 內容為 HMAC-SHA256(secret, "<timestamp>.<body>")
+// 將時間納入簽章 , 接收端可拒絕過舊的請求
+// 參數: secret - 簽章金鑰, timestamp - unix 秒, body - 請求內容
+// 回傳: sha256=<hex>
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 驗證簽章 , 供接收端與測試使用
+// 參數: secret - 簽章金鑰, signature - HeaderSignature, timestamp - HeaderTimestamp, body - 請求內容, now - 目前時間, tolerance - 容許的時間差
+// 回傳: 錯誤訊息 , ErrInvalidSignature 或 ErrTimestampExpired
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", timestamp, ErrInvalidSignature)
+	}
+	if diff := now.Sub(time.Unix(ts, 0)); diff > tolerance || diff < -tolerance {
+		return ErrTimestampExpired
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
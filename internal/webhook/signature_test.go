@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 簽章與驗證測試
+// Test for Sign, Verify
+func TestVerify(t *testing.T) {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"event-1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign("secret", now.Unix(), body)
+
+	tests := []struct {
+		name       string
+		secret     string
+		signature  string
+		timestamp  string
+		body       []byte
+		now        time.Time
+		assertFunc func(t *testing.T, err error)
+	}{
+		{
+			name: "valid", secret: "secret", signature: signature, timestamp: timestamp, body: body, now: now.Add(time.Minute),
+			assertFunc: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "wrong secret", secret: "other", signature: signature, timestamp: timestamp, body: body, now: now,
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "tampered body", secret: "secret", signature: signature, timestamp: timestamp, body: []byte(`{"id":"event-2"}`), now: now,
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "tampered timestamp", secret: "secret", signature: signature, timestamp: strconv.FormatInt(now.Unix()+1, 10), body: body, now: now,
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "invalid timestamp", secret: "secret", signature: signature, timestamp: "yesterday", body: body, now: now,
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "expired", secret: "secret", signature: signature, timestamp: timestamp, body: body, now: now.Add(10 * time.Minute),
+			assertFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrTimestampExpired)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Verify(test.secret, test.signature, test.timestamp, test.body, test.now, 5*time.Minute)
+			test.assertFunc(t, err)
+		})
+	}
+}
+
+// Test for NewSecret
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(a, "whsec_"))
+	assert.Len(t, a, len("whsec_")+64)
+	assert.NotEqual(t, a, b)
+}
@@ -2,47 +2,401 @@ package main
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+
+	"github.com/itmrchow/course-management-system/internal/certificate"
 	"github.com/itmrchow/course-management-system/internal/config"
+	"github.com/itmrchow/course-management-system/internal/domain/event"
+	"github.com/itmrchow/course-management-system/internal/handler"
+	"github.com/itmrchow/course-management-system/internal/health"
+	"github.com/itmrchow/course-management-system/internal/invoice"
+	"github.com/itmrchow/course-management-system/internal/job"
+	"github.com/itmrchow/course-management-system/internal/lifecycle"
+	"github.com/itmrchow/course-management-system/internal/meeting"
+	"github.com/itmrchow/course-management-system/internal/notification"
+	"github.com/itmrchow/course-management-system/internal/outbox"
+	"github.com/itmrchow/course-management-system/internal/payment"
+	"github.com/itmrchow/course-management-system/internal/reminder"
+	"github.com/itmrchow/course-management-system/internal/repository"
+	attendanceRepository "github.com/itmrchow/course-management-system/internal/repository/attendance"
+	auditRepository "github.com/itmrchow/course-management-system/internal/repository/audit"
+	certificateRepository "github.com/itmrchow/course-management-system/internal/repository/certificate"
+	courseRepository "github.com/itmrchow/course-management-system/internal/repository/course"
+	enrollmentRepository "github.com/itmrchow/course-management-system/internal/repository/enrollment"
+	invoiceRepository "github.com/itmrchow/course-management-system/internal/repository/invoice"
+	locationRepository "github.com/itmrchow/course-management-system/internal/repository/location"
+	notificationRepository "github.com/itmrchow/course-management-system/internal/repository/notification"
+	orderRepository "github.com/itmrchow/course-management-system/internal/repository/order"
+	outboxRepository "github.com/itmrchow/course-management-system/internal/repository/outbox"
+	promotionRepository "github.com/itmrchow/course-management-system/internal/repository/promotion"
+	reviewRepository "github.com/itmrchow/course-management-system/internal/repository/review"
+	sessionRepository "github.com/itmrchow/course-management-system/internal/repository/session"
+	teacherRepository "github.com/itmrchow/course-management-system/internal/repository/teacher"
+	webhookRepository "github.com/itmrchow/course-management-system/internal/repository/webhook"
+	"github.com/itmrchow/course-management-system/internal/server"
+	attendanceService "github.com/itmrchow/course-management-system/internal/service/attendance"
+	certificateService "github.com/itmrchow/course-management-system/internal/service/certificate"
+	courseService "github.com/itmrchow/course-management-system/internal/service/course"
+	enrollmentService "github.com/itmrchow/course-management-system/internal/service/enrollment"
+	invoiceService "github.com/itmrchow/course-management-system/internal/service/invoice"
+	locationService "github.com/itmrchow/course-management-system/internal/service/location"
+	notificationService "github.com/itmrchow/course-management-system/internal/service/notification"
+	orderService "github.com/itmrchow/course-management-system/internal/service/order"
+	promotionService "github.com/itmrchow/course-management-system/internal/service/promotion"
+	reviewService "github.com/itmrchow/course-management-system/internal/service/review"
+	sessionService "github.com/itmrchow/course-management-system/internal/service/session"
+	"github.com/itmrchow/course-management-system/internal/tracing"
+	"github.com/itmrchow/course-management-system/internal/webhook"
 )
 
 func main() {
 
-	// 系統信號處理
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	// ctx
-	ctx, cancel := context.WithCancel(context.Background())
-
 	// config
 	config.InitConfig()
 
 	// logger
 	logger := config.InitLogger()
 
-	// db
-	db := config.NewPostgresDB(context.Background(), logger)
-	if err := config.PingDB(ctx, logger, db); err != nil {
-		logger.Err(err).Msg("failed to ping db")
-	}
-
-	select {
-	case sig := <-sigChan:
-		logger.Info().Msgf("收到系統信號: %v, 開始關閉服務", sig)
-		cancel()
-	case <-ctx.Done():
-		logger.Info().Msg("服務開始關閉")
-		// close db
-		sqlDB, err := db.DB()
-		if err != nil {
-			logger.Err(err).Msg("failed to get sql db")
+	// 系統信號處理 , 收到 SIGINT/SIGTERM 時取消 ctx
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// migrate 指令只執行 migrate 後結束 , 供 DB_AUTO_MIGRATE 關閉時於部署流程執行
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = migrate(ctx, logger)
+	} else {
+		err = run(ctx, logger)
+	}
+	if err != nil {
+		logger.Err(err).Msg("service stopped with errors")
+		stop()
+		os.Exit(1)
+	}
+
+	logger.Info().Msg("service stopped")
+}
+
+// migrate 連線 db 並執行 migrate 後結束
+func migrate(ctx context.Context, logger *zerolog.Logger) error {
+	db, err := config.WaitForPostgresDB(ctx, logger)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = config.CloseDB(db) }()
+
+	if err := config.Migrate(db); err != nil {
+		return err
+	}
+	logger.Info().Msg("migration completed")
+	return nil
+}
+
+// run 建立並啟動所有元件 , 直到 ctx 結束後依相反順序停止
+// 建立元件的過程中發生錯誤時 , 關閉已開啟的 db 與 tracing 後回傳
+func run(ctx context.Context, logger *zerolog.Logger) (err error) {
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("DB_AUTO_MIGRATE", true)
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+	viper.SetDefault("PURGE_INTERVAL", 24*time.Hour)
+	viper.SetDefault("SOFT_DELETE_RETENTION", 90*24*time.Hour)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", time.Second)
+	viper.SetDefault("OUTBOX_RETENTION", 7*24*time.Hour)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+	viper.SetDefault("NOTIFICATION_POLL_INTERVAL", 10*time.Second)
+	viper.SetDefault("NOTIFICATION_TIMEZONE", "Asia/Taipei")
+	viper.SetDefault("REMINDER_POLL_INTERVAL", time.Minute)
+	viper.SetDefault("PAYMENT_EXPIRY_INTERVAL", time.Minute)
+	viper.SetDefault("PAYMENT_REFUND_INTERVAL", time.Minute)
+	viper.SetDefault("MEETING_SYNC_INTERVAL", 10*time.Minute)
+
+	m := lifecycle.NewManager(logger, viper.GetDuration("SHUTDOWN_TIMEOUT"))
+	running := false
+	defer func() {
+		if !running {
+			err = errors.Join(err, m.Close(context.WithoutCancel(ctx)))
 		}
-		if err := sqlDB.Close(); err != nil {
-			logger.Err(err).Msg("failed to close sql db")
+	}()
+
+	// tracing
+	shutdownTracing, err := tracing.Init(ctx, tracing.ConfigFromViper())
+	if err != nil {
+		return err
+	}
+	m.Add(lifecycle.Component{Name: "tracing", Stop: shutdownTracing})
+
+	// db
+	// 等待 db 可連線 , 逾時則結束程式
+	db, err := config.WaitForPostgresDB(ctx, logger)
+	if err != nil {
+		return err
+	}
+	m.Add(lifecycle.Component{
+		Name: "db",
+		Stop: func(ctx context.Context) error { return config.CloseDB(db) },
+	})
+	// 關閉自動 migrate 時由 migrate 指令執行 , schema 不是最新時 readiness 檢查失敗
+	if viper.GetBool("DB_AUTO_MIGRATE") {
+		if err := config.Migrate(db); err != nil {
+			return err
 		}
 	}
+
+	// repository
+	teacherRepo := teacherRepository.NewTeacherRepository(db)
+	courseRepo := courseRepository.NewCourseRepository(db)
+	auditLogRepo := auditRepository.NewAuditLogRepository(db)
+	outboxRepo := outboxRepository.NewOutboxRepository(db)
+	webhookRepo := webhookRepository.NewWebhookRepository(db)
+	notificationRepo := notificationRepository.NewNotificationRepository(db)
+	sessionRepo := sessionRepository.NewSessionRepository(db)
+	enrollmentRepo := enrollmentRepository.NewEnrollmentRepository(db)
+	orderRepo := orderRepository.NewOrderRepository(db)
+	promotionRepo := promotionRepository.NewPromotionRepository(db)
+	invoiceRepo := invoiceRepository.NewInvoiceRepository(db)
+	attendanceRepo := attendanceRepository.NewAttendanceRepository(db)
+	certificateRepo := certificateRepository.NewCertificateRepository(db)
+	reviewRepo := reviewRepository.NewReviewRepository(db)
+	locationRepo := locationRepository.NewLocationRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	// notification
+	loc, err := time.LoadLocation(viper.GetString("NOTIFICATION_TIMEZONE"))
+	if err != nil {
+		return err
+	}
+	renderer, err := notification.NewRenderer(loc)
+	if err != nil {
+		return err
+	}
+	notificationSvc := notificationService.NewNotificationService(notificationRepo, teacherRepo, renderer)
+	notifiers, closeNotifiers, err := notification.NotifiersFromViper()
+	if err != nil {
+		return err
+	}
+	m.Add(lifecycle.Component{
+		Name: "notifiers",
+		Stop: func(ctx context.Context) error { return closeNotifiers() },
+	})
+
+	// promotion
+	promotionSvc := promotionService.NewPromotionService(promotionRepo, courseRepo, enrollmentRepo)
+
+	// payment
+	gateway, err := payment.GatewayFromViper()
+	if err != nil {
+		return err
+	}
+	orderSvc := orderService.NewOrderService(orderRepo, enrollmentRepo, courseRepo, promotionSvc, outboxRepo, transactor, orderService.ConfigFromViper(), gateway)
+
+	// enrollment
+	enrollmentSvc := enrollmentService.NewEnrollmentService(enrollmentRepo, courseRepo, orderRepo, promotionSvc, orderSvc, outboxRepo, transactor, enrollmentService.ConfigFromViper())
+
+	// meeting
+	meetings, err := meeting.ProviderFromViper()
+	if err != nil {
+		return err
+	}
+	sessionSvc := sessionService.NewSessionService(courseRepo, sessionRepo, teacherRepo, transactor, sessionService.ConfigFromViper(), meetings)
+
+	// invoice
+	invoiceCfg, err := invoiceService.ConfigFromViper()
+	if err != nil {
+		return err
+	}
+	invoiceRenderer, err := invoice.NewRenderer(invoiceCfg.Location)
+	if err != nil {
+		return err
+	}
+	invoiceSvc := invoiceService.NewInvoiceService(invoiceRepo, orderRepo, courseRepo, promotionRepo, outboxRepo, transactor, invoiceRenderer, invoiceCfg)
+
+	// certificate
+	certificateCfg, err := certificateService.ConfigFromViper()
+	if err != nil {
+		return err
+	}
+	certificateSvc := certificateService.NewCertificateService(certificateRepo, courseRepo, teacherRepo, enrollmentRepo, attendanceRepo, sessionRepo, outboxRepo, transactor, certificate.NewRenderer(certificateCfg.Location), certificateCfg)
+
+	// outbox
+	// in-process handler 以 dispatcher.Subscribe 訂閱 , 設定 OUTBOX_WEBHOOK_URL 時另外以 webhook 發送
+	dispatcher := outbox.NewDispatcher()
+	dispatcher.Subscribe(outbox.AllEvents, webhook.NewFanout(webhookRepo).Handle)
+	dispatcher.Subscribe(event.TypeTeacherApproved, notificationSvc.HandleEvent)
+	dispatcher.Subscribe(event.TypeTeacherRejected, notificationSvc.HandleEvent)
+	dispatcher.Subscribe(event.TypeCourseCancelled, orderSvc.HandleEvent)
+	dispatcher.Subscribe(event.TypeOrderPaid, invoiceSvc.HandleEvent)
+	dispatcher.Subscribe(event.TypeCourseStatusChanged, certificateSvc.HandleEvent)
+	sinks := []outbox.Sink{dispatcher}
+	if url := viper.GetString("OUTBOX_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, outbox.NewWebhookSink(url, &http.Client{Timeout: 10 * time.Second}))
+	}
+	relay := outbox.NewRelay(transactor, outboxRepo, outbox.ConfigFromViper(), sinks...)
+
+	// job runner
+	runner := job.NewRunner(logger)
+	runner.Every("purge_deleted", viper.GetDuration("PURGE_INTERVAL"), repository.PurgeJob(
+		viper.GetDuration("SOFT_DELETE_RETENTION"),
+		map[string]repository.Purger{
+			"teacher": teacherRepo,
+			"course":  courseRepo,
+		},
+	))
+	runner.Every("outbox_relay", viper.GetDuration("OUTBOX_POLL_INTERVAL"), relay.RunOnce)
+	runner.Every("purge_outbox", viper.GetDuration("PURGE_INTERVAL"), repository.PurgeJob(
+		viper.GetDuration("OUTBOX_RETENTION"),
+		map[string]repository.Purger{"outbox": outboxRepo},
+	))
+	webhookCfg := webhook.ConfigFromViper()
+	deliverer := webhook.NewDeliverer(transactor, webhookRepo, webhookCfg)
+	runner.Every("webhook_delivery", viper.GetDuration("WEBHOOK_POLL_INTERVAL"), deliverer.RunOnce)
+	sender := notification.NewSender(transactor, notificationRepo, notification.ConfigFromViper(), notifiers...)
+	runner.Every("notification_sender", viper.GetDuration("NOTIFICATION_POLL_INTERVAL"), sender.RunOnce)
+	sessionReminder := reminder.NewReminder(transactor, courseRepo, sessionRepo, enrollmentRepo, teacherRepo, notificationSvc, reminder.ConfigFromViper())
+	runner.Every("session_reminder", viper.GetDuration("REMINDER_POLL_INTERVAL"), sessionReminder.RunOnce)
+	runner.Every("order_expiry", viper.GetDuration("PAYMENT_EXPIRY_INTERVAL"), orderSvc.ExpirePending)
+	runner.Every("refund_processor", viper.GetDuration("PAYMENT_REFUND_INTERVAL"), orderSvc.ProcessRefunds)
+	runner.Every("meeting_sync", viper.GetDuration("MEETING_SYNC_INTERVAL"), sessionSvc.SyncMeetings)
+	m.Add(lifecycle.Component{Name: "job_runner", Start: runner.Start, Stop: runner.Stop})
+
+	// http server
+	srv := server.NewServer(viper.GetString("HTTP_ADDR"), logger)
+
+	// health
+	h := health.New(viper.GetDuration("HEALTH_CHECK_TIMEOUT"))
+	h.Register("db", health.DBCheck(db))
+	h.Register("migration", health.MigrationCheck(db))
+	h.Register("job_runner", runner.Check)
+	srv.Handle("GET /healthz", h.LivenessHandler())
+	srv.Handle("GET /readyz", h.ReadinessHandler())
+
+	// audit
+	srv.Handle("GET /audit-logs", handler.NewAuditHandler(auditLogRepo).List())
+
+	// webhook
+	webhookHandler := handler.NewWebhookHandler(webhookRepo, webhook.NewGuard(webhookCfg.AllowPrivateNetworks, net.DefaultResolver))
+	srv.Handle("POST /webhooks", webhookHandler.Create())
+	srv.Handle("GET /webhooks", webhookHandler.List())
+	srv.Handle("GET /webhooks/{id}", webhookHandler.Get())
+	srv.Handle("DELETE /webhooks/{id}", webhookHandler.Delete())
+	srv.Handle("GET /webhooks/{id}/deliveries", webhookHandler.ListDeliveries())
+	srv.Handle("POST /webhook-deliveries/{id}/redeliver", webhookHandler.Redeliver())
+
+	// notification
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+	srv.Handle("GET /users/{id}/notification-preferences", notificationHandler.GetPreference())
+	srv.Handle("PUT /users/{id}/notification-preferences", notificationHandler.PutPreference())
+
+	// search
+	searchHandler := handler.NewSearchHandler(teacherRepo, courseRepo)
+	srv.Handle("GET /search/teachers", searchHandler.Teachers())
+	srv.Handle("GET /search/courses", searchHandler.Courses())
+
+	// course price
+	courseSvc := courseService.NewCourseService(courseRepo, outboxRepo, transactor)
+	coursePriceHandler := handler.NewCoursePriceHandler(courseSvc)
+	srv.Handle("GET /courses/{id}/prices", coursePriceHandler.List())
+	srv.Handle("POST /courses/{id}/prices", coursePriceHandler.Create())
+	srv.Handle("GET /courses/{id}/price", coursePriceHandler.Quote())
+
+	// course refund policy
+	refundPolicyHandler := handler.NewRefundPolicyHandler(courseSvc)
+	srv.Handle("GET /courses/{id}/refund-policy", refundPolicyHandler.Get())
+	srv.Handle("PUT /courses/{id}/refund-policy", refundPolicyHandler.Put())
+
+	// session
+	sessionHandler := handler.NewSessionHandler(sessionSvc)
+	srv.Handle("GET /courses/{id}/sessions", sessionHandler.ListByCourse())
+	srv.Handle("POST /course-sessions/{id}/cancel", sessionHandler.Cancel())
+	srv.Handle("POST /course-sessions/{id}/reschedule", sessionHandler.Reschedule())
+	srv.Handle("GET /course-sessions/{id}/meeting", sessionHandler.Meeting())
+	if fake, ok := meetings.(*meeting.FakeMeeting); ok {
+		// 本機模擬會議服務的加入頁面
+		srv.Handle("GET /fake-meetings/{id}", fake.Join())
+	}
+
+	// attendance
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService.NewAttendanceService(attendanceRepo, sessionRepo, courseRepo, enrollmentRepo, teacherRepo, attendanceService.ConfigFromViper()))
+	srv.Handle("GET /course-sessions/{id}/attendance", attendanceHandler.ListBySession())
+	srv.Handle("PUT /course-sessions/{id}/attendance", attendanceHandler.Mark())
+	srv.Handle("GET /courses/{id}/attendance-rate", attendanceHandler.CourseRate())
+	srv.Handle("GET /courses/{id}/students/{student_id}/attendance-rate", attendanceHandler.StudentRate())
+
+	// certificate
+	certificateHandler := handler.NewCertificateHandler(certificateSvc)
+	srv.Handle("GET /certificates", certificateHandler.List())
+	srv.Handle("GET /certificates/{id}", certificateHandler.Get())
+	srv.Handle("GET /certificates/{id}/pdf", certificateHandler.PDF())
+	srv.Handle("POST /certificates/{id}/revoke", certificateHandler.Revoke())
+	srv.Handle("GET /certificates/verify", certificateHandler.Verify())
+	srv.Handle("POST /courses/{id}/certificates", certificateHandler.Issue())
+
+	// review
+	reviewHandler := handler.NewReviewHandler(reviewService.NewReviewService(reviewRepo, courseRepo, teacherRepo, enrollmentRepo, outboxRepo, transactor))
+	srv.Handle("POST /enrollments/{id}/review", reviewHandler.Submit())
+	srv.Handle("GET /reviews", reviewHandler.List())
+	srv.Handle("GET /reviews/{id}", reviewHandler.Get())
+	srv.Handle("PUT /reviews/{id}", reviewHandler.Update())
+	srv.Handle("POST /reviews/{id}/moderate", reviewHandler.Moderate())
+	srv.Handle("GET /courses/{id}/reviews", reviewHandler.ListByCourse())
+
+	// location
+	locationHandler := handler.NewLocationHandler(locationService.NewLocationService(locationRepo, courseRepo, sessionRepo, transactor))
+	srv.Handle("POST /locations", locationHandler.CreateLocation())
+	srv.Handle("GET /locations", locationHandler.ListLocations())
+	srv.Handle("GET /locations/{id}", locationHandler.GetLocation())
+	srv.Handle("PUT /locations/{id}", locationHandler.UpdateLocation())
+	srv.Handle("POST /locations/{id}/rooms", locationHandler.CreateRoom())
+	srv.Handle("GET /rooms", locationHandler.ListRooms())
+	srv.Handle("GET /rooms/{id}", locationHandler.GetRoom())
+	srv.Handle("PUT /rooms/{id}", locationHandler.UpdateRoom())
+	srv.Handle("GET /rooms/{id}/conflicts", locationHandler.Conflicts())
+	srv.Handle("PUT /courses/{id}/patterns/{pattern_id}/room", locationHandler.AssignRoom())
+
+	// promotion
+	promotionHandler := handler.NewPromotionHandler(promotionSvc)
+	srv.Handle("POST /promotions", promotionHandler.Create())
+	srv.Handle("GET /promotions", promotionHandler.List())
+	srv.Handle("GET /promotions/{id}", promotionHandler.Get())
+	srv.Handle("POST /promotions/{id}/deactivate", promotionHandler.Deactivate())
+	srv.Handle("GET /courses/{id}/checkout-preview", promotionHandler.Preview())
+
+	// enrollment
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentSvc)
+	srv.Handle("POST /courses/{id}/enrollments", enrollmentHandler.Enroll())
+	srv.Handle("POST /enrollments/{id}/withdraw", enrollmentHandler.Withdraw())
+
+	// order
+	orderHandler := handler.NewOrderHandler(orderSvc)
+	srv.Handle("GET /orders/{id}", orderHandler.Get())
+	srv.Handle("GET /enrollments/{id}/orders", orderHandler.ListByEnrollment())
+	srv.Handle("POST /orders/{id}/pay", orderHandler.Pay())
+	srv.Handle("GET /orders/{id}/refunds", orderHandler.ListRefunds())
+	srv.Handle("GET /enrollments/{id}/refund-quote", orderHandler.QuoteRefund())
+	srv.Handle("POST /payments/{provider}/callback", orderHandler.Callback())
+	if fake, ok := gateway.(*payment.FakeGateway); ok {
+		// 本機模擬金流商的付款頁面
+		srv.Handle("POST /fake-payments/{id}", fake.Checkout())
+	}
+
+	// invoice
+	invoiceHandler := handler.NewInvoiceHandler(invoiceSvc)
+	srv.Handle("GET /invoices", invoiceHandler.List())
+	srv.Handle("GET /invoices/{id}", invoiceHandler.Get())
+	srv.Handle("GET /invoices/{id}/html", invoiceHandler.Document(invoice.FormatHTML))
+	srv.Handle("GET /invoices/{id}/pdf", invoiceHandler.Document(invoice.FormatPDF))
+	srv.Handle("GET /orders/{id}/invoice", invoiceHandler.GetByOrder())
+
+	m.Add(lifecycle.Component{Name: "http_server", Start: srv.Start, Stop: srv.Shutdown})
+
+	running = true
+	return m.Run(ctx)
 }